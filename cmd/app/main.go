@@ -1,12 +1,23 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/routes"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/workers"
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -15,6 +26,13 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Configure password hashing
+	hasher, err := models.NewPasswordHasher(&cfg.Security)
+	if err != nil {
+		log.Fatalf("❌ Invalid password hashing configuration: %v", err)
+	}
+	models.SetPasswordHasher(hasher)
+
 	// Set Gin mode
 	gin.SetMode(cfg.Server.GinMode)
 
@@ -24,6 +42,7 @@ func main() {
 		log.Fatalf("❌ Failed to connect to database: %v", err)
 	}
 	defer db.Close()
+	cfg.Database.ConfigurePool(db)
 
 	// Test database connection
 	if err := db.Ping(); err != nil {
@@ -31,21 +50,106 @@ func main() {
 	}
 	log.Printf("✅ Database connection established")
 
-	// Initialize Gin router
+	// Initialize Gin router. Only the configured proxies may set the client IP through
+	// X-Forwarded-For; rate limits and login audits key on it.
 	router := gin.New()
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// Setup routes
-	routes.SetupRoutes(router, cfg, db)
+	shutdownRoutes, notificationService := routes.SetupRoutes(router, cfg, db)
+
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	startWorkers(workerCtx, cfg, db, notificationService)
 
 	// Server information
 	log.Printf("🚀 Starting %s v%s", cfg.App.Name, cfg.App.Version)
 	log.Printf("📡 Server running on port %s", cfg.Server.Port)
-	log.Printf("🔧 Environment: %s", cfg.Server.GinMode)
+	log.Printf("🔧 Environment: %s (gin mode: %s)", cfg.Server.Environment, cfg.Server.GinMode)
+	cfg.LogEffective(log.Default())
 	log.Printf("🏥 Health check available at: http://localhost:%s/health", cfg.Server.Port)
 
 	// Start server
-	address := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
-	if err := router.Run(address); err != nil {
-		log.Fatalf("❌ Failed to start server: %v", err)
+	server := &http.Server{
+		Addr:    fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port),
+		Handler: router,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("❌ Failed to start server: %v", err)
+		}
+	}()
+
+	// Wait for an interrupt, then finish in-flight requests and queued notifications
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+	<-signals.Done()
+	log.Printf("🛑 Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️ Server shutdown: %v", err)
+	}
+	stopWorkers()
+	if err := shutdownRoutes(shutdownCtx); err != nil {
+		log.Printf("⚠️ %v", err)
 	}
 }
+
+// shutdownTimeout bounds how long shutdown waits for requests and queued notifications
+const shutdownTimeout = 30 * time.Second
+
+// startWorkers launches the background workers
+func startWorkers(ctx context.Context, cfg *config.Config, db *sql.DB, notificationService *services.NotificationService) {
+	maxBackoff := time.Duration(cfg.Workers.MaxBackoff) * time.Minute
+
+	if cfg.Workers.SessionCleanupInterval > 0 {
+		sessionService := services.NewSessionService(repositories.NewSessionRepository(db), cfg)
+		interval := time.Duration(cfg.Workers.SessionCleanupInterval) * time.Minute
+		cleaner := workers.NewPeriodicWorker("session-cleanup", interval, maxBackoff, func(ctx context.Context) error {
+			return sessionService.CleanupExpiredSessions()
+		})
+		go cleaner.Run(ctx)
+		log.Printf("🧹 Session cleanup worker running every %s", interval)
+
+		revokedTokenRepo := repositories.NewRevokedTokenRepository(db)
+		tokenCleaner := workers.NewPeriodicWorker("revoked-token-cleanup", interval, maxBackoff, func(ctx context.Context) error {
+			return revokedTokenRepo.CleanupExpiredTokens()
+		})
+		go tokenCleaner.Run(ctx)
+		log.Printf("🧹 Revoked token cleanup worker running every %s", interval)
+
+		loginLockoutRepo := repositories.NewLoginLockoutRepository(db)
+		lockoutCleaner := workers.NewPeriodicWorker("login-lockout-cleanup", interval, maxBackoff, func(ctx context.Context) error {
+			return loginLockoutRepo.CleanupExpiredLockouts()
+		})
+		go lockoutCleaner.Run(ctx)
+		log.Printf("🧹 Login lockout cleanup worker running every %s", interval)
+	}
+
+	if cfg.Workers.NotificationRetryInterval > 0 {
+		interval := time.Duration(cfg.Workers.NotificationRetryInterval) * time.Minute
+		policy := services.NotificationRetryPolicy{
+			MaxRetries: cfg.Workers.NotificationMaxRetries,
+			Backoff:    interval,
+			MaxBackoff: maxBackoff,
+			BatchSize:  notificationRetryBatchSize,
+		}
+		sweeper := workers.NewPeriodicWorker("notification-retry", interval, maxBackoff, func(ctx context.Context) error {
+			summary, err := notificationService.RetryFailedNotifications(ctx, policy)
+			if summary != nil && summary.Retried+summary.GaveUp > 0 {
+				log.Printf("📨 Notification retry sweep: retried %d, sent %d, skipped %d, gave up %d", summary.Retried, summary.Sent, summary.Skipped, summary.GaveUp)
+			}
+			return err
+		})
+		go sweeper.Run(ctx)
+		log.Printf("📨 Notification retry worker running every %s", interval)
+	}
+}
+
+// notificationRetryBatchSize is the most failed notifications one retry sweep looks at
+const notificationRetryBatchSize = 100
@@ -20,49 +20,74 @@ const (
 
 func main() {
 	var (
-		command = flag.String("command", "status", "Migration command: status, up, down, create")
-		name    = flag.String("name", "", "Migration name (for create command)")
-		steps   = flag.Int("steps", 1, "Number of migrations to run (for up/down commands)")
-		seed    = flag.String("seed", "", "Run seeders after migration: 'all' or specific seeder name")
+		command     = flag.String("command", "status", "Migration command: status, up, down, redo, create")
+		name        = flag.String("name", "", "Migration name (for create command)")
+		steps       = flag.Int("steps", 1, "Number of migrations to run (for up/down/redo commands)")
+		target      = flag.String("target", "", "Migrate up to and including this version, or roll back everything newer than it (overrides -steps)")
+		seed        = flag.String("seed", "", "Run seeders after migration: 'all' or specific seeder name")
+		force       = flag.Bool("force", false, "Run seeders again even if they have already run")
+		dryRun      = flag.Bool("dry-run", false, "Print the SQL that up/down would execute without running it")
+		verify      = flag.Bool("verify", false, "Exit non-zero if an applied migration file has changed (for status command)")
+		confirm     = flag.Bool(config.DestructiveConfirmation, false, "Allow destructive commands (down, redo, -seed all or gamenet, -force) in production")
+		lockTimeout = flag.Duration("lock-timeout", migrations.DefaultLockTimeout, "How long up/down wait for another migration run to finish")
 	)
 	flag.Parse()
 
 	// Load configuration
 	cfg := config.Load()
 
+	// Refuse destructive commands in production unless explicitly confirmed;
+	// a dry run executes nothing and needs no confirmation
+	if !*dryRun {
+		if err := guardCommand(cfg, *command, *seed, *force, *confirm); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
 	// Get migrations directory path
 	migrationsPath, err := getMigrationsPath()
 	if err != nil {
 		log.Fatalf("Failed to get migrations path: %v", err)
 	}
 
-	// Create migration runner
-	runner, err := migrations.NewMySQLRunner(cfg)
+	// Create the migration runner for the configured driver
+	runner, err := migrations.NewRunner(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create migration runner: %v", err)
 	}
 	defer runner.Close()
 
+	runOptions := migrations.RunOptions{
+		Steps:  *steps,
+		Target: *target,
+		DryRun: *dryRun,
+		Out:    os.Stdout,
+	}
+
 	// Execute command
 	switch *command {
 	case "status":
-		if err := runStatus(runner, migrationsPath); err != nil {
+		if err := runStatus(runner, migrationsPath, *verify); err != nil {
 			log.Fatalf("Status command failed: %v", err)
 		}
 	case "up":
-		if err := runUp(runner, migrationsPath, *steps); err != nil {
+		if err := runUp(runner, migrationsPath, runOptions, *lockTimeout); err != nil {
 			log.Fatalf("Up command failed: %v", err)
 		}
 		// Run seeders after successful migration if requested
-		if *seed != "" {
-			if err := runSeeders(cfg, *seed); err != nil {
+		if *seed != "" && !*dryRun {
+			if err := runSeeders(cfg, *seed, *force); err != nil {
 				log.Fatalf("Seeding failed: %v", err)
 			}
 		}
 	case "down":
-		if err := runDown(runner, migrationsPath, *steps); err != nil {
+		if err := runDown(runner, migrationsPath, runOptions, *lockTimeout); err != nil {
 			log.Fatalf("Down command failed: %v", err)
 		}
+	case "redo":
+		if err := runRedo(runner, migrationsPath, runOptions, *lockTimeout); err != nil {
+			log.Fatalf("Redo command failed: %v", err)
+		}
 	case "create":
 		if *name == "" {
 			log.Fatal("Migration name is required for create command")
@@ -75,53 +100,34 @@ func main() {
 	}
 }
 
-func runStatus(runner migrations.MigrationRunner, migrationsPath string) error {
-	// Create migration table if it doesn't exist
-	if err := runner.CreateMigrationTable(); err != nil {
-		return fmt.Errorf("failed to create migration table: %w", err)
-	}
-
-	// Get applied migrations
-	applied, err := runner.GetAppliedMigrations()
-	if err != nil {
-		return fmt.Errorf("failed to get applied migrations: %w", err)
-	}
-
-	// Get available migration files
-	available, err := migrations.LoadMigrationFiles(migrationsPath)
-	if err != nil {
-		return fmt.Errorf("failed to load migration files: %w", err)
-	}
-
-	// Create a map of applied migrations for quick lookup
-	appliedMap := make(map[string]bool)
-	for _, m := range applied {
-		appliedMap[m.Version] = true
-	}
-
-	fmt.Println("Migration Status:")
-	fmt.Println("================")
-
-	if len(available) == 0 {
-		fmt.Println("No migration files found.")
+// guardCommand applies the production guard to destructive commands
+func guardCommand(cfg *config.Config, command, seed string, force, confirmed bool) error {
+	var operation string
+	switch {
+	case command == "down":
+		operation = "migrate down"
+	case command == "redo":
+		operation = "migrate redo"
+	case command == "up" && seed == "all":
+		operation = "seeding all seeders (includes test data)"
+	case command == "up" && seed == "gamenet":
+		operation = "seeding gamenets (inserts test data)"
+	case command == "up" && seed != "" && force:
+		operation = fmt.Sprintf("seeding %s with -force (re-runs seeders that already ran)", seed)
+	default:
 		return nil
 	}
 
-	fmt.Printf("%-20s %-30s %-15s\n", "Version", "Description", "Status")
-	fmt.Println(strings.Repeat("-", 65))
-
-	for _, migration := range available {
-		status := "PENDING"
-		if appliedMap[migration.Version] {
-			status = "APPLIED"
-		}
-		fmt.Printf("%-20s %-30s %-15s\n", migration.Version, migration.Description, status)
+	if err := cfg.GuardDestructive(operation, confirmed); err != nil {
+		return fmt.Errorf("%w; re-run with -%s to override", err, config.DestructiveConfirmation)
+	}
+	if confirmed && cfg.IsProduction() {
+		log.Printf("⚠️  Running %s in production (confirmed with -%s)", operation, config.DestructiveConfirmation)
 	}
-
 	return nil
 }
 
-func runUp(runner migrations.MigrationRunner, migrationsPath string, steps int) error {
+func runStatus(runner migrations.MigrationRunner, migrationsPath string, verify bool) error {
 	// Create migration table if it doesn't exist
 	if err := runner.CreateMigrationTable(); err != nil {
 		return fmt.Errorf("failed to create migration table: %w", err)
@@ -133,109 +139,82 @@ func runUp(runner migrations.MigrationRunner, migrationsPath string, steps int)
 		return fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
-	// Create a map of applied migrations for quick lookup
-	appliedMap := make(map[string]bool)
-	for _, m := range applied {
-		appliedMap[m.Version] = true
-	}
-
 	// Get available migration files
 	available, err := migrations.LoadMigrationFiles(migrationsPath)
 	if err != nil {
 		return fmt.Errorf("failed to load migration files: %w", err)
 	}
 
-	// Find pending migrations
-	var pending []migrations.MigrationFile
-	for _, migration := range available {
-		if !appliedMap[migration.Version] {
-			pending = append(pending, migration)
-		}
-	}
+	statuses := migrations.BuildStatus(available, applied)
+	migrations.WriteStatus(os.Stdout, statuses)
 
-	if len(pending) == 0 {
-		fmt.Println("No pending migrations.")
-		return nil
-	}
-
-	// Limit by steps
-	if steps > len(pending) {
-		steps = len(pending)
-	}
-
-	fmt.Printf("Applying %d migration(s)...\n", steps)
-
-	// Apply migrations
-	for i := 0; i < steps; i++ {
-		migration := pending[i]
-		fmt.Printf("Applying migration %s: %s\n", migration.Version, migration.Description)
-
-		if err := runner.ApplyMigration(migration.Version, migration.Description, migration.UpSQL); err != nil {
-			return fmt.Errorf("failed to apply migration %s: %w", migration.Version, err)
+	modified := migrations.ModifiedVersions(statuses)
+	if len(modified) > 0 {
+		fmt.Printf("\n⚠️  %d applied migration(s) changed on disk since they were applied: %s\n",
+			len(modified), strings.Join(modified, ", "))
+		if verify {
+			return fmt.Errorf("migration drift detected in %s", strings.Join(modified, ", "))
 		}
-
-		fmt.Printf("✅ Migration %s applied successfully\n", migration.Version)
 	}
 
 	return nil
 }
 
-func runDown(runner migrations.MigrationRunner, migrationsPath string, steps int) error {
-	// Get applied migrations
-	applied, err := runner.GetAppliedMigrations()
+func runUp(runner migrations.LockingRunner, migrationsPath string, opts migrations.RunOptions, lockTimeout time.Duration) error {
+	// Get available migration files
+	available, err := migrations.LoadMigrationFiles(migrationsPath)
 	if err != nil {
-		// If migrations table doesn't exist, there are no migrations to rollback
-		if strings.Contains(err.Error(), "doesn't exist") || strings.Contains(err.Error(), "Table") {
-			fmt.Println("No applied migrations to rollback.")
-			return nil
-		}
-		return fmt.Errorf("failed to get applied migrations: %w", err)
-	}
-
-	if len(applied) == 0 {
-		fmt.Println("No applied migrations to rollback.")
-		return nil
+		return fmt.Errorf("failed to load migration files: %w", err)
 	}
 
-	// Limit by steps
-	if steps > len(applied) {
-		steps = len(applied)
-	}
+	return withMigrationLock(runner, opts.DryRun, lockTimeout, func() error {
+		return migrations.Up(runner, available, opts)
+	})
+}
 
+func runDown(runner migrations.LockingRunner, migrationsPath string, opts migrations.RunOptions, lockTimeout time.Duration) error {
 	// Get available migration files
 	available, err := migrations.LoadMigrationFiles(migrationsPath)
 	if err != nil {
 		return fmt.Errorf("failed to load migration files: %w", err)
 	}
 
-	// Create a map of available migrations for quick lookup
-	availableMap := make(map[string]migrations.MigrationFile)
-	for _, migration := range available {
-		availableMap[migration.Version] = migration
-	}
-
-	fmt.Printf("Rolling back %d migration(s)...\n", steps)
-
-	// Rollback migrations (from latest to oldest)
-	for i := len(applied) - 1; i >= len(applied)-steps; i-- {
-		migration := applied[i]
+	return withMigrationLock(runner, opts.DryRun, lockTimeout, func() error {
+		return migrations.Down(runner, available, opts)
+	})
+}
 
-		// Get migration file for rollback SQL
-		migrationFile, exists := availableMap[migration.Version]
-		if !exists {
-			return fmt.Errorf("migration file not found for version %s", migration.Version)
-		}
+func runRedo(runner migrations.LockingRunner, migrationsPath string, opts migrations.RunOptions, lockTimeout time.Duration) error {
+	// Get available migration files
+	available, err := migrations.LoadMigrationFiles(migrationsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load migration files: %w", err)
+	}
 
-		fmt.Printf("Rolling back migration %s: %s\n", migration.Version, migration.Description)
+	return withMigrationLock(runner, opts.DryRun, lockTimeout, func() error {
+		return migrations.Redo(runner, available, opts)
+	})
+}
 
-		if err := runner.RollbackMigration(migration.Version, migrationFile.DownSQL); err != nil {
-			return fmt.Errorf("failed to rollback migration %s: %w", migration.Version, err)
-		}
+// withMigrationLock runs fn while holding the migration lock so that concurrent
+// deployments cannot apply migrations at the same time. A dry run changes nothing
+// and runs without the lock.
+func withMigrationLock(runner migrations.LockingRunner, dryRun bool, timeout time.Duration, fn func() error) error {
+	if dryRun {
+		return fn()
+	}
 
-		fmt.Printf("✅ Migration %s rolled back successfully\n", migration.Version)
+	unlock, err := runner.Lock(timeout)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if err := unlock(); err != nil {
+			log.Printf("⚠️  %v", err)
+		}
+	}()
 
-	return nil
+	return fn()
 }
 
 func runCreate(name, migrationsPath string) error {
@@ -325,14 +304,26 @@ func sanitizeName(name string) string {
 	return strings.ToLower(result.String())
 }
 
-// runSeeders executes seeders based on the seed parameter
-func runSeeders(cfg *config.Config, seedParam string) error {
+// runSeeders executes seeders based on the seed parameter, skipping seeders that
+// have already run unless force is set
+func runSeeders(cfg *config.Config, seedParam string, force bool) error {
 	log.Println("🌱 Running seeders...")
 
+	runner, err := seeders.NewMySQLSeederRunner(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create seeder runner: %w", err)
+	}
+	defer runner.Close()
+
+	if err := runner.CreateSeederTable(); err != nil {
+		return err
+	}
+
 	switch seedParam {
 	case "all":
-		return seeders.RunAllSeeders(cfg)
+		return seeders.RunAllSeedersOnce(runner, cfg, force)
 	default:
-		return seeders.RunSeeder(seedParam, cfg)
+		_, err := seeders.RunSeederOnce(runner, seedParam, cfg, force)
+		return err
 	}
 }
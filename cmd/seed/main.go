@@ -12,7 +12,12 @@ import (
 )
 
 func main() {
-	var command = flag.String("command", "admin", "Seeder command to run (admin, notification_templates, gamenet, all)")
+	var (
+		command = flag.String("command", "admin", "Seeder command to run (admin, notification_templates, gamenet, fake_users, all)")
+		confirm = flag.Bool(config.DestructiveConfirmation, false, "Allow seeding test data (gamenet, all) and -force in production")
+		force   = flag.Bool("force", false, "Run seeders again even if they have already run")
+		count   = flag.Int("count", 1000, "Number of users to insert (for fake_users command)")
+	)
 	flag.Parse()
 
 	// Load environment variables
@@ -23,21 +28,35 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Test data must not end up in production by accident, and seeders that already
+	// ran must not run again there by accident either
+	if *command == "gamenet" || *command == "fake_users" || *command == "all" {
+		guardSeed(cfg, fmt.Sprintf("seed %s (inserts test data)", *command), *confirm)
+	}
+	if *force {
+		guardSeed(cfg, fmt.Sprintf("seed %s -force (re-runs seeders that already ran)", *command), *confirm)
+	}
+
 	switch *command {
-	case "admin":
-		if err := seedAdmin(cfg); err != nil {
-			log.Fatalf("Failed to seed admin: %v", err)
-		}
-	case "notification_templates":
-		if err := seedNotificationTemplates(cfg); err != nil {
-			log.Fatalf("Failed to seed notification templates: %v", err)
+	case "admin", "notification_templates":
+		if err := runTracked(cfg, *force, func(runner seeders.SeederRunner) error {
+			_, err := seeders.RunSeederOnce(runner, *command, cfg, *force)
+			return err
+		}); err != nil {
+			log.Fatalf("Failed to seed %s: %v", *command, err)
 		}
 	case "gamenet":
 		if err := seedGamenets(cfg); err != nil {
 			log.Fatalf("Failed to seed gamenets: %v", err)
 		}
+	case "fake_users":
+		if err := seeders.SeedFakeUsers(cfg, *count); err != nil {
+			log.Fatalf("Failed to seed fake users: %v", err)
+		}
 	case "all":
-		if err := seeders.RunAllSeeders(cfg); err != nil {
+		if err := runTracked(cfg, *force, func(runner seeders.SeederRunner) error {
+			return seeders.RunAllSeedersOnce(runner, cfg, *force)
+		}); err != nil {
 			log.Fatalf("Failed to run all seeders: %v", err)
 		}
 	default:
@@ -46,19 +65,40 @@ func main() {
 		fmt.Println("  admin - Seed admin user")
 		fmt.Println("  notification_templates - Seed notification templates")
 		fmt.Println("  gamenet - Seed 25 gamenets for testing")
+		fmt.Println("  fake_users - Seed -count fake users for load testing")
 		fmt.Println("  all - Run all seeders")
+		fmt.Println("Registered seeders run once; pass -force to run them again.")
 		os.Exit(1)
 	}
 }
 
-// seedAdmin seeds the default admin user
-func seedAdmin(cfg *config.Config) error {
-	return seeders.SeedAdmin(cfg)
+// guardSeed exits unless operation may run, i.e. outside production or when confirmed
+func guardSeed(cfg *config.Config, operation string, confirmed bool) {
+	if err := cfg.GuardDestructive(operation, confirmed); err != nil {
+		log.Fatalf("%v; re-run with -%s to override", err, config.DestructiveConfirmation)
+	}
+	if confirmed && cfg.IsProduction() {
+		log.Printf("⚠️  Running %s in production (confirmed with -%s)", operation, config.DestructiveConfirmation)
+	}
 }
 
-// seedNotificationTemplates seeds the notification templates
-func seedNotificationTemplates(cfg *config.Config) error {
-	return seeders.SeedNotificationTemplates(cfg)
+// runTracked runs fn with a seeder runner that records which seeders have run,
+// so registered seeders are skipped on later runs unless force is set
+func runTracked(cfg *config.Config, force bool, fn func(runner seeders.SeederRunner) error) error {
+	runner, err := seeders.NewMySQLSeederRunner(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create seeder runner: %w", err)
+	}
+	defer runner.Close()
+
+	if err := runner.CreateSeederTable(); err != nil {
+		return err
+	}
+	if force {
+		log.Println("⚠️  -force set: seeders that have already run will run again")
+	}
+
+	return fn(runner)
 }
 
 // seedGamenets seeds 25 gamenets for testing
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,26 +21,86 @@ type Config struct {
 	Database     DatabaseConfig
 	Notification NotificationConfig
 	FileStorage  FileStorageConfig
+	EmailPolicy  EmailPolicyConfig
+	Workers      WorkersConfig
+	Subscription SubscriptionConfig
 }
 
 // ServerConfig holds server-related configuration
 type ServerConfig struct {
-	Host    string
-	Port    string
-	GinMode string
+	Host        string
+	Port        string
+	GinMode     string
+	Environment string // deployment environment, e.g. "development", "staging" or "production"
+	// ExposeVerificationCodes returns email verification codes in API responses so
+	// tests can read them; it only takes effect in gin test mode
+	ExposeVerificationCodes bool
+	// TrustedProxies lists the proxy addresses or CIDRs whose X-Forwarded-For header is
+	// used for the client IP. When empty the header is ignored and the client IP is the
+	// connection's remote address, so clients can't choose the IP rate limits key on.
+	TrustedProxies []string
 }
 
 // AppConfig holds application metadata
 type AppConfig struct {
 	Name    string
 	Version string
+	// Base URL of the frontend app, used for the links sent in emails
+	FrontendBaseURL string
 }
 
 // SecurityConfig holds security-related configuration
 type SecurityConfig struct {
-	APISecret     string
-	JWTSecret     string
-	JWTExpiration int // in hours
+	APISecret          string
+	JWTSecret          string
+	JWTExpirationHours int
+	// Per user type overrides (in hours); 0 falls back to JWTExpirationHours
+	AdminJWTExpiration   int
+	UserJWTExpiration    int
+	GamenetJWTExpiration int
+	// Token lifetime (in hours) for user and gamenet logins that asked to be
+	// remembered; 0 gives them the normal lifetime. Admins always get their own lifetime.
+	RememberMeExpirationHours int
+	// How long after expiring a token may still be refreshed (in minutes)
+	RefreshGracePeriodMinutes int
+	// Step-up re-authentication
+	ReauthTokenMinutes int // lifetime of the token issued by verify-password
+	ReauthMaxAttempts  int // failed password checks allowed per account per window; 0 disables the limit
+	// Login methods that can be switched off per deployment
+	EnablePasswordLogin bool
+	// Login lockout: after MaxLoginAttempts failures for an email within the window,
+	// logins for it are refused for the lockout duration. 0 attempts disables it.
+	MaxLoginAttempts          int
+	LoginAttemptWindowMinutes int
+	LoginLockoutMinutes       int
+	// MaxConcurrentSessions caps the active sessions per account; logging in beyond it
+	// ends the oldest session. 0 means unlimited.
+	MaxConcurrentSessions int
+	// Requests allowed per client IP per minute on the login and forgot password
+	// endpoints; 0 disables the limit
+	LoginRateLimitPerMinute          int
+	ForgotPasswordRateLimitPerMinute int
+	// Minimum seconds between email verification codes for one account; 0 disables it
+	EmailVerificationCooldownSeconds int
+	// How long permission checks are cached in memory, in seconds; 0 disables the cache
+	PermissionCacheTTLSeconds int
+	// Password hashing ("bcrypt" or "argon2id")
+	HashAlgorithm     string
+	BcryptCost        int
+	Argon2Memory      uint32 // in KiB
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	// Rules new passwords must meet when they are reset or changed
+	PasswordPolicy PasswordPolicyConfig
+}
+
+// PasswordPolicyConfig holds the password strength rules
+type PasswordPolicyConfig struct {
+	MinLength     int // in characters
+	RequireDigit  bool
+	RequireUpper  bool
+	RequireLower  bool
+	RequireSymbol bool
 }
 
 // DatabaseConfig holds database-related configuration
@@ -48,12 +112,36 @@ type DatabaseConfig struct {
 	DBName   string
 	SSLMode  string
 	Driver   string
+	// Connection pool limits applied by ConfigurePool
+	MaxOpenConns           int // 0 means unlimited
+	MaxIdleConns           int
+	ConnMaxLifetimeSeconds int // 0 means connections are reused forever
 }
 
 // NotificationConfig holds notification-related configuration
 type NotificationConfig struct {
 	Email EmailConfig
 	SMS   SMSConfig
+	// AlertsRecipient, when set, receives internal alerts instead of every admin
+	AlertsRecipient string
+	// Sandbox reroutes every email and SMS away from real recipients outside production
+	Sandbox SandboxConfig
+	// Queue sends non-critical notifications in the background
+	Queue NotificationQueueConfig
+}
+
+// NotificationQueueConfig holds the background notification queue settings
+type NotificationQueueConfig struct {
+	Workers int // 0 disables the queue, so every notification is sent synchronously
+	Size    int // notifications that may wait at once; beyond it they are sent synchronously
+}
+
+// SandboxConfig holds the notification sandbox used by staging. Each rerouted
+// message notes its original recipient; an empty recipient drops the channel's messages.
+type SandboxConfig struct {
+	Enabled        bool
+	EmailRecipient string
+	SMSRecipient   string
 }
 
 // EmailConfig holds email SMTP configuration
@@ -67,6 +155,7 @@ type EmailConfig struct {
 	FromName  string
 	UseTLS    bool
 	UseSSL    bool
+	RateLimit RateLimitConfig
 }
 
 // SMSConfig holds SMS configuration for Kavenegar
@@ -76,6 +165,34 @@ type SMSConfig struct {
 	Sender     string
 	TestMode   bool
 	MaxRetries int
+	// RetryBaseDelayMS is the delay before the first retry; it doubles for each further
+	// retry up to RetryMaxDelayMS, with random jitter
+	RetryBaseDelayMS int
+	RetryMaxDelayMS  int
+	// Strategy is one of verify_only, sms_only or verify_then_sms
+	Strategy string
+	// Templates maps message types (e.g. user_credentials) to Verify Lookup template names
+	Templates map[string]string
+	RateLimit RateLimitConfig
+	// CircuitBreaker stops calling Kavenegar while it is failing
+	CircuitBreaker CircuitBreakerConfig
+	// MaxBulkSize is the most messages one bulk SMS request may carry
+	MaxBulkSize int
+	// SendTimeoutSeconds bounds sending one message, including its retries; a bulk
+	// send applies it to each message rather than to the whole batch
+	SendTimeoutSeconds int
+}
+
+// CircuitBreakerConfig holds the circuit breaker settings for an external provider
+type CircuitBreakerConfig struct {
+	FailureThreshold int // consecutive failures that open the breaker; 0 disables it
+	OpenSeconds      int // how long the breaker stays open before probing the provider again
+}
+
+// RateLimitConfig holds the send rate for a notification channel
+type RateLimitConfig struct {
+	PerMinute int // 0 disables limiting
+	Burst     int
 }
 
 // FileStorageConfig holds file storage configuration
@@ -84,6 +201,41 @@ type FileStorageConfig struct {
 	MaxFileSize  int64 // in bytes
 	AllowedTypes []string
 	PublicURL    string
+	Images       ImageConfig
+}
+
+// ImageConfig holds image upload processing configuration; all sizes are in pixels
+type ImageConfig struct {
+	MaxWidth      int // larger images are scaled down to fit; 0 disables resizing
+	MaxHeight     int // larger images are scaled down to fit; 0 disables resizing
+	MinDimension  int // images narrower or shorter than this are rejected; 0 disables the check
+	ThumbnailSize int // bounding box for the thumbnail variant; 0 disables thumbnails
+	// MaxPixels is the largest width x height accepted before decoding; images declaring
+	// more are rejected so a small file can't expand into a huge bitmap. 0 disables the check
+	MaxPixels int
+}
+
+// EmailPolicyConfig holds email address validation configuration
+type EmailPolicyConfig struct {
+	BlockDisposable       bool
+	DisposableDomainsFile string // one domain per line, reloaded when the file changes
+}
+
+// WorkersConfig holds background worker configuration
+type WorkersConfig struct {
+	SessionCleanupInterval int // in minutes; 0 disables the session, revoked token and login lockout cleanup workers
+	MaxBackoff             int // in minutes; upper bound for the retry interval after failures
+	// NotificationRetryInterval is how often failed notifications are retried, in
+	// minutes; 0 disables the retry sweep
+	NotificationRetryInterval int
+	// NotificationMaxRetries is the retry count at which a failed notification is given up on
+	NotificationMaxRetries int
+}
+
+// SubscriptionConfig holds subscription plan business rules
+type SubscriptionConfig struct {
+	MaxTrialDurationDays       int  // upper bound for a plan's trial duration; defaults to 365
+	RepriceExistingSubscribers bool // apply plan price changes to existing subscribers instead of keeping their subscribed price
 }
 
 // Load reads configuration from environment variables
@@ -95,27 +247,63 @@ func Load() *Config {
 
 	return &Config{
 		Server: ServerConfig{
-			Host:    getEnv("HOST", "0.0.0.0"),
-			Port:    getEnv("PORT", "8080"),
-			GinMode: getEnv("GIN_MODE", "debug"),
+			Host:        getEnv("HOST", "0.0.0.0"),
+			Port:        getEnv("PORT", "8080"),
+			GinMode:     getEnv("GIN_MODE", "debug"),
+			Environment: getEnv("APP_ENV", "development"),
+			// Only honoured in test mode, see ExposeVerificationCodesActive
+			ExposeVerificationCodes: getEnvBool("EXPOSE_VERIFICATION_CODES", false),
+			TrustedProxies:          getEnvList("TRUSTED_PROXIES"),
 		},
 		App: AppConfig{
-			Name:    getEnv("APP_NAME", "GateHide API"),
-			Version: getEnv("APP_VERSION", "1.0.0"),
+			Name:            getEnv("APP_NAME", "GateHide API"),
+			Version:         getEnv("APP_VERSION", "1.0.0"),
+			FrontendBaseURL: getEnv("FRONTEND_BASE_URL", "http://localhost:3000"),
 		},
 		Security: SecurityConfig{
-			APISecret:     getEnv("API_SECRET", "default-secret-key"),
-			JWTSecret:     getEnv("JWT_SECRET", "jwt-secret-key-change-in-production"),
-			JWTExpiration: getEnvInt("JWT_EXPIRATION_HOURS", 24),
+			APISecret:                        getEnv("API_SECRET", "default-secret-key"),
+			JWTSecret:                        getEnv("JWT_SECRET", "jwt-secret-key-change-in-production"),
+			JWTExpirationHours:               getEnvInt("JWT_EXPIRATION_HOURS", 24),
+			AdminJWTExpiration:               getEnvInt("ADMIN_JWT_EXPIRATION_HOURS", 0),
+			UserJWTExpiration:                getEnvInt("USER_JWT_EXPIRATION_HOURS", 0),
+			GamenetJWTExpiration:             getEnvInt("GAMENET_JWT_EXPIRATION_HOURS", 0),
+			RememberMeExpirationHours:        getEnvInt("REMEMBER_ME_EXPIRATION_HOURS", 24*7),
+			RefreshGracePeriodMinutes:        getEnvInt("REFRESH_GRACE_PERIOD_MINUTES", 30),
+			ReauthTokenMinutes:               getEnvInt("REAUTH_TOKEN_MINUTES", 5),
+			ReauthMaxAttempts:                getEnvInt("REAUTH_MAX_ATTEMPTS", 5),
+			EnablePasswordLogin:              getEnvBool("AUTH_ENABLE_PASSWORD_LOGIN", true),
+			MaxLoginAttempts:                 getEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+			LoginAttemptWindowMinutes:        getEnvInt("LOGIN_ATTEMPT_WINDOW_MINUTES", 15),
+			LoginLockoutMinutes:              getEnvInt("LOGIN_LOCKOUT_MINUTES", 15),
+			MaxConcurrentSessions:            getEnvInt("MAX_CONCURRENT_SESSIONS", 0),
+			LoginRateLimitPerMinute:          getEnvInt("LOGIN_RATE_LIMIT_PER_MINUTE", 10),
+			ForgotPasswordRateLimitPerMinute: getEnvInt("FORGOT_PASSWORD_RATE_LIMIT_PER_MINUTE", 3),
+			EmailVerificationCooldownSeconds: getEnvInt("EMAIL_VERIFICATION_COOLDOWN_SECONDS", 60),
+			PermissionCacheTTLSeconds:        getEnvInt("PERMISSION_CACHE_TTL_SECONDS", 60),
+			HashAlgorithm:                    getEnv("PASSWORD_HASH_ALGORITHM", "bcrypt"),
+			BcryptCost:                       getEnvInt("BCRYPT_COST", 10),
+			Argon2Memory:                     uint32(getEnvInt("ARGON2_MEMORY_KB", 64*1024)),
+			Argon2Iterations:                 uint32(getEnvInt("ARGON2_ITERATIONS", 3)),
+			Argon2Parallelism:                uint8(getEnvInt("ARGON2_PARALLELISM", 2)),
+			PasswordPolicy: PasswordPolicyConfig{
+				MinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 6),
+				RequireDigit:  getEnvBool("PASSWORD_REQUIRE_DIGIT", false),
+				RequireUpper:  getEnvBool("PASSWORD_REQUIRE_UPPER", false),
+				RequireLower:  getEnvBool("PASSWORD_REQUIRE_LOWER", false),
+				RequireSymbol: getEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+			},
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "3306"),
-			User:     getEnv("DB_USER", "root"),
-			Password: getEnv("DB_PASSWORD", ""),
-			DBName:   getEnv("DB_NAME", "gatehide"),
-			SSLMode:  getEnv("DB_SSLMODE", "false"),
-			Driver:   getEnv("DB_DRIVER", "mysql"),
+			Host:                   getEnv("DB_HOST", "localhost"),
+			Port:                   getEnv("DB_PORT", "3306"),
+			User:                   getEnv("DB_USER", "root"),
+			Password:               getEnv("DB_PASSWORD", ""),
+			DBName:                 getEnv("DB_NAME", "gatehide"),
+			SSLMode:                getEnv("DB_SSLMODE", "false"),
+			Driver:                 getEnv("DB_DRIVER", "mysql"),
+			MaxOpenConns:           getEnvInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:           getEnvInt("DB_MAX_IDLE_CONNS", 10),
+			ConnMaxLifetimeSeconds: getEnvInt("DB_CONN_MAX_LIFETIME_SECONDS", 300),
 		},
 		Notification: NotificationConfig{
 			Email: EmailConfig{
@@ -128,20 +316,72 @@ func Load() *Config {
 				FromName:  getEnv("FROM_NAME", "GateHide"),
 				UseTLS:    getEnvBool("SMTP_USE_TLS", true),
 				UseSSL:    getEnvBool("SMTP_USE_SSL", false),
+				RateLimit: RateLimitConfig{
+					PerMinute: getEnvInt("EMAIL_RATE_PER_MINUTE", 120),
+					Burst:     getEnvInt("EMAIL_RATE_BURST", 10),
+				},
 			},
 			SMS: SMSConfig{
-				Enabled:    getEnvBool("SMS_ENABLED", false),
-				APIKey:     getEnv("KAVENEGAR_API_KEY", ""),
-				Sender:     getEnv("SMS_SENDER", "10008663"),
-				TestMode:   getEnvBool("SMS_TEST_MODE", true),
-				MaxRetries: getEnvInt("SMS_MAX_RETRIES", 3),
+				Enabled:          getEnvBool("SMS_ENABLED", false),
+				APIKey:           getEnv("KAVENEGAR_API_KEY", ""),
+				Sender:           getEnv("SMS_SENDER", "10008663"),
+				TestMode:         getEnvBool("SMS_TEST_MODE", true),
+				MaxRetries:       getEnvInt("SMS_MAX_RETRIES", 3),
+				Strategy:         getEnv("SMS_STRATEGY", "verify_then_sms"),
+				RetryBaseDelayMS: getEnvInt("SMS_RETRY_BASE_DELAY_MS", 1000),
+				RetryMaxDelayMS:  getEnvInt("SMS_RETRY_MAX_DELAY_MS", 10000),
+				Templates: map[string]string{
+					"user_credentials":    getEnv("SMS_TEMPLATE_USER_CREDENTIALS", "user-credentials"),
+					"gamenet_credentials": getEnv("SMS_TEMPLATE_GAMENET_CREDENTIALS", "gamenet-credentials"),
+				},
+				RateLimit: RateLimitConfig{
+					PerMinute: getEnvInt("SMS_RATE_PER_MINUTE", 60),
+					Burst:     getEnvInt("SMS_RATE_BURST", 5),
+				},
+				CircuitBreaker: CircuitBreakerConfig{
+					FailureThreshold: getEnvInt("SMS_CIRCUIT_FAILURE_THRESHOLD", 5),
+					OpenSeconds:      getEnvInt("SMS_CIRCUIT_OPEN_SECONDS", 30),
+				},
+				MaxBulkSize:        getEnvInt("SMS_MAX_BULK_SIZE", 100),
+				SendTimeoutSeconds: getEnvInt("SMS_SEND_TIMEOUT_SECONDS", 30),
+			},
+			AlertsRecipient: getEnv("ALERTS_EMAIL", ""),
+			Sandbox: SandboxConfig{
+				Enabled:        getEnvBool("NOTIFICATION_SANDBOX", false),
+				EmailRecipient: getEnv("SANDBOX_EMAIL_RECIPIENT", ""),
+				SMSRecipient:   getEnv("SANDBOX_SMS_RECIPIENT", ""),
+			},
+			Queue: NotificationQueueConfig{
+				Workers: getEnvInt("NOTIFICATION_QUEUE_WORKERS", 4),
+				Size:    getEnvInt("NOTIFICATION_QUEUE_SIZE", 100),
 			},
 		},
 		FileStorage: FileStorageConfig{
 			UploadPath:   getEnv("UPLOAD_PATH", "./uploads"),
 			MaxFileSize:  getEnvInt64("MAX_FILE_SIZE", 10*1024*1024), // 10MB default
-			AllowedTypes: []string{".pdf", ".jpg", ".jpeg", ".png", ".doc", ".docx"},
+			AllowedTypes: []string{".pdf", ".jpg", ".jpeg", ".png", ".gif", ".doc", ".docx"},
 			PublicURL:    getEnv("PUBLIC_URL", "http://localhost:8080"),
+			Images: ImageConfig{
+				MaxWidth:      getEnvInt("IMAGE_MAX_WIDTH", 1024),
+				MaxHeight:     getEnvInt("IMAGE_MAX_HEIGHT", 1024),
+				MinDimension:  getEnvInt("IMAGE_MIN_DIMENSION", 64),
+				ThumbnailSize: getEnvInt("IMAGE_THUMBNAIL_SIZE", 128),
+				MaxPixels:     getEnvInt("IMAGE_MAX_PIXELS", 40_000_000),
+			},
+		},
+		EmailPolicy: EmailPolicyConfig{
+			BlockDisposable:       getEnvBool("BLOCK_DISPOSABLE_EMAILS", false),
+			DisposableDomainsFile: getEnv("DISPOSABLE_DOMAINS_FILE", "./config/disposable_domains.txt"),
+		},
+		Workers: WorkersConfig{
+			SessionCleanupInterval:    getEnvInt("SESSION_CLEANUP_INTERVAL_MINUTES", 60),
+			MaxBackoff:                getEnvInt("WORKER_MAX_BACKOFF_MINUTES", 30),
+			NotificationRetryInterval: getEnvInt("NOTIFICATION_RETRY_INTERVAL_MINUTES", 5),
+			NotificationMaxRetries:    getEnvInt("NOTIFICATION_MAX_RETRIES", 3),
+		},
+		Subscription: SubscriptionConfig{
+			MaxTrialDurationDays:       getEnvInt("MAX_TRIAL_DURATION_DAYS", 365),
+			RepriceExistingSubscribers: getEnvBool("SUBSCRIPTION_REPRICE_EXISTING", false),
 		},
 	}
 }
@@ -174,6 +414,17 @@ func getEnvBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvList retrieves a comma-separated environment variable as a list, or nil when unset
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 // getEnvInt64 retrieves an environment variable as int64 or returns a default value
 func getEnvInt64(key string, defaultValue int64) int64 {
 	if value := os.Getenv(key); value != "" {
@@ -184,6 +435,94 @@ func getEnvInt64(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+// GetJWTExpiration returns the token expiration in hours for the given user type,
+// falling back to the global JWTExpirationHours when no override is set
+func (c *Config) GetJWTExpiration(userType string) int {
+	var hours int
+	switch userType {
+	case "admin":
+		hours = c.Security.AdminJWTExpiration
+	case "user":
+		hours = c.Security.UserJWTExpiration
+	case "gamenet":
+		hours = c.Security.GamenetJWTExpiration
+	}
+	if hours <= 0 {
+		return c.Security.JWTExpirationHours
+	}
+	return hours
+}
+
+// GetTokenExpiration returns the token expiration in hours for the given user type,
+// using RememberMeExpirationHours for logins that asked to be remembered. Admin tokens
+// always keep the admin lifetime, so remember me cannot outlast a shorter admin limit.
+func (c *Config) GetTokenExpiration(userType string, rememberMe bool) int {
+	if rememberMe && userType != "admin" && c.Security.RememberMeExpirationHours > 0 {
+		return c.Security.RememberMeExpirationHours
+	}
+	return c.GetJWTExpiration(userType)
+}
+
+// Login methods that can be enabled or disabled per deployment
+const (
+	LoginMethodPassword = "password"
+)
+
+// LoginMethodEnabled reports whether the given login method is enabled
+func (c *Config) LoginMethodEnabled(method string) bool {
+	switch method {
+	case LoginMethodPassword:
+		return c.Security.EnablePasswordLogin
+	}
+	return false
+}
+
+// DestructiveConfirmation is the explicit override that lets a destructive operation run in production
+const DestructiveConfirmation = "yes-i-am-sure"
+
+// ErrDestructiveInProduction is returned when a destructive operation is refused in production
+var ErrDestructiveInProduction = errors.New("destructive operation refused in production")
+
+// IsProduction reports whether the application runs in production, either because
+// APP_ENV says so or because Gin runs in release mode
+func (c *Config) IsProduction() bool {
+	switch strings.ToLower(c.Server.Environment) {
+	case "production", "prod":
+		return true
+	}
+	return c.Server.GinMode == "release"
+}
+
+// NotificationSandboxActive reports whether notifications are rerouted to the sandbox
+// recipients. The sandbox is never active in production, so a copied staging
+// configuration cannot silence real notifications.
+func (c *Config) NotificationSandboxActive() bool {
+	return c.Notification.Sandbox.Enabled && !c.IsProduction()
+}
+
+// ExposeVerificationCodesActive reports whether email verification codes are returned
+// in API responses. Codes are only exposed in gin test mode, so the flag cannot leak
+// them from a real deployment.
+func (c *Config) ExposeVerificationCodesActive() bool {
+	return c.Server.ExposeVerificationCodes && c.Server.GinMode == "test"
+}
+
+// GuardDestructive refuses a destructive operation in production unless it was
+// explicitly confirmed with DestructiveConfirmation
+func (c *Config) GuardDestructive(operation string, confirmed bool) error {
+	if !c.IsProduction() || confirmed {
+		return nil
+	}
+	return fmt.Errorf("%w: %s requires explicit confirmation (%s)", ErrDestructiveInProduction, operation, DestructiveConfirmation)
+}
+
+// ConfigurePool applies the configured connection pool limits to db
+func (d DatabaseConfig) ConfigurePool(db *sql.DB) {
+	db.SetMaxOpenConns(d.MaxOpenConns)
+	db.SetMaxIdleConns(d.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(d.ConnMaxLifetimeSeconds) * time.Second)
+}
+
 // GetDSN returns the database connection string
 func (c *Config) GetDSN() string {
 	switch c.Database.Driver {
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// redactedValue replaces secrets in logged configuration
+const redactedValue = "***"
+
+// LogEffective logs the effective configuration, one line per section, so it is easy
+// to see how an environment is configured. Secrets (API/JWT secrets, passwords and API
+// keys) are never logged; they show as *** when set and as an empty value otherwise.
+func (c *Config) LogEffective(logger *log.Logger) {
+	for _, line := range c.effectiveSettings() {
+		logger.Printf("⚙️  %s", line)
+	}
+}
+
+// effectiveSettings returns the configuration as "section: key=value ..." lines
+func (c *Config) effectiveSettings() []string {
+	email := c.Notification.Email
+	sms := c.Notification.SMS
+
+	return []string{
+		section("server",
+			"host", c.Server.Host,
+			"port", c.Server.Port,
+			"environment", c.Server.Environment,
+			"gin_mode", c.Server.GinMode,
+			"expose_verification_codes", c.ExposeVerificationCodesActive(),
+			"trusted_proxies", strings.Join(c.Server.TrustedProxies, ","),
+			"production", c.IsProduction()),
+		section("app",
+			"name", c.App.Name,
+			"version", c.App.Version,
+			"frontend_base_url", c.App.FrontendBaseURL),
+		section("security",
+			"api_secret", redact(c.Security.APISecret),
+			"jwt_secret", redact(c.Security.JWTSecret),
+			"jwt_expiration_hours", c.Security.JWTExpirationHours,
+			"admin_jwt_expiration_hours", c.Security.AdminJWTExpiration,
+			"user_jwt_expiration_hours", c.Security.UserJWTExpiration,
+			"gamenet_jwt_expiration_hours", c.Security.GamenetJWTExpiration,
+			"remember_me_expiration_hours", c.Security.RememberMeExpirationHours,
+			"refresh_grace_period_minutes", c.Security.RefreshGracePeriodMinutes,
+			"reauth_token_minutes", c.Security.ReauthTokenMinutes,
+			"reauth_max_attempts", c.Security.ReauthMaxAttempts,
+			"enable_password_login", c.Security.EnablePasswordLogin,
+			"max_login_attempts", c.Security.MaxLoginAttempts,
+			"login_attempt_window_minutes", c.Security.LoginAttemptWindowMinutes,
+			"login_lockout_minutes", c.Security.LoginLockoutMinutes,
+			"max_concurrent_sessions", c.Security.MaxConcurrentSessions,
+			"login_rate_limit_per_minute", c.Security.LoginRateLimitPerMinute,
+			"forgot_password_rate_limit_per_minute", c.Security.ForgotPasswordRateLimitPerMinute,
+			"email_verification_cooldown_seconds", c.Security.EmailVerificationCooldownSeconds,
+			"permission_cache_ttl_seconds", c.Security.PermissionCacheTTLSeconds,
+			"password_min_length", c.Security.PasswordPolicy.MinLength,
+			"password_require_digit", c.Security.PasswordPolicy.RequireDigit,
+			"password_require_upper", c.Security.PasswordPolicy.RequireUpper,
+			"password_require_lower", c.Security.PasswordPolicy.RequireLower,
+			"password_require_symbol", c.Security.PasswordPolicy.RequireSymbol,
+			"hash_algorithm", c.Security.HashAlgorithm),
+		section("database",
+			"driver", c.Database.Driver,
+			"host", c.Database.Host,
+			"port", c.Database.Port,
+			"user", c.Database.User,
+			"password", redact(c.Database.Password),
+			"name", c.Database.DBName,
+			"ssl_mode", c.Database.SSLMode,
+			"max_open_conns", c.Database.MaxOpenConns,
+			"max_idle_conns", c.Database.MaxIdleConns,
+			"conn_max_lifetime_seconds", c.Database.ConnMaxLifetimeSeconds),
+		section("email",
+			"enabled", email.Enabled,
+			"smtp_host", email.SMTPHost,
+			"smtp_port", email.SMTPPort,
+			"smtp_user", email.SMTPUser,
+			"smtp_pass", redact(email.SMTPPass),
+			"from", email.FromEmail,
+			"tls", email.UseTLS,
+			"ssl", email.UseSSL,
+			"rate_per_minute", email.RateLimit.PerMinute),
+		section("sms",
+			"enabled", sms.Enabled,
+			"api_key", redact(sms.APIKey),
+			"sender", sms.Sender,
+			"test_mode", sms.TestMode,
+			"strategy", sms.Strategy,
+			"max_retries", sms.MaxRetries,
+			"retry_base_delay_ms", sms.RetryBaseDelayMS,
+			"retry_max_delay_ms", sms.RetryMaxDelayMS,
+			"rate_per_minute", sms.RateLimit.PerMinute,
+			"circuit_failure_threshold", sms.CircuitBreaker.FailureThreshold,
+			"circuit_open_seconds", sms.CircuitBreaker.OpenSeconds,
+			"max_bulk_size", sms.MaxBulkSize,
+			"send_timeout_seconds", sms.SendTimeoutSeconds),
+		section("sandbox",
+			"active", c.NotificationSandboxActive(),
+			"email_recipient", c.Notification.Sandbox.EmailRecipient,
+			"sms_recipient", c.Notification.Sandbox.SMSRecipient),
+		section("notification_queue",
+			"workers", c.Notification.Queue.Workers,
+			"size", c.Notification.Queue.Size),
+		section("features",
+			"block_disposable_emails", c.EmailPolicy.BlockDisposable,
+			"alerts_recipient", c.Notification.AlertsRecipient,
+			"session_cleanup_interval_minutes", c.Workers.SessionCleanupInterval,
+			"notification_retry_interval_minutes", c.Workers.NotificationRetryInterval,
+			"notification_max_retries", c.Workers.NotificationMaxRetries,
+			"max_trial_duration_days", c.Subscription.MaxTrialDurationDays,
+			"reprice_existing_subscribers", c.Subscription.RepriceExistingSubscribers,
+			"upload_path", c.FileStorage.UploadPath,
+			"max_file_size", c.FileStorage.MaxFileSize),
+	}
+}
+
+// section formats a configuration section from alternating keys and values
+func section(name string, keyValues ...interface{}) string {
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteString(":")
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", keyValues[i], keyValues[i+1])
+	}
+	return b.String()
+}
+
+// redact hides a secret value, keeping only whether it is set
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
@@ -0,0 +1,22 @@
+-- version: 022_create_plan_history_table
+-- description: Create plan_history table recording changes to subscription plans
+
+-- UP
+CREATE TABLE IF NOT EXISTS plan_history (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    plan_id INT NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    changed_by_id INT NULL,
+    changed_by_type ENUM('user', 'admin', 'gamenet') NULL,
+    before_data JSON NULL,
+    after_data JSON NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    
+    INDEX idx_plan_id (plan_id),
+    INDEX idx_created_at (created_at),
+    
+    FOREIGN KEY (plan_id) REFERENCES subscription_plans(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- DOWN
+DROP TABLE IF EXISTS plan_history;
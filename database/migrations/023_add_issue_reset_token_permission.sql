@@ -0,0 +1,16 @@
+-- version: 023_add_issue_reset_token_permission
+-- description: Add the users:issue_reset_token permission for admin-issued password reset links
+
+-- UP
+INSERT IGNORE INTO permissions (name, description, resource, action) VALUES
+('users:issue_reset_token', 'Issue password reset links for users', 'users', 'issue_reset_token');
+
+-- Assign permission to administrator role
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'administrator'
+AND p.name = 'users:issue_reset_token';
+
+-- DOWN
+DELETE FROM permissions WHERE name = 'users:issue_reset_token';
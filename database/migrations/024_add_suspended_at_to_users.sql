@@ -0,0 +1,8 @@
+-- version: 024_add_suspended_at_to_users
+-- description: Add suspended_at column to users table for account suspension
+
+-- UP
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMP NULL AFTER last_login_at;
+
+-- DOWN
+ALTER TABLE users DROP COLUMN suspended_at;
@@ -0,0 +1,8 @@
+-- version: 025_add_notifications_keyset_index
+-- description: Add (created_at, id) index to notifications for keyset pagination
+
+-- UP
+CREATE INDEX idx_notifications_created_at_id ON notifications (created_at, id);
+
+-- DOWN
+DROP INDEX idx_notifications_created_at_id ON notifications;
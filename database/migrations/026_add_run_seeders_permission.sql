@@ -0,0 +1,16 @@
+-- version: 026_add_run_seeders_permission
+-- description: Add the seeders:run permission for re-running safe seeders from the admin API
+
+-- UP
+INSERT IGNORE INTO permissions (name, description, resource, action) VALUES
+('seeders:run', 'Re-run idempotent data seeders', 'seeders', 'run');
+
+-- Assign permission to administrator role
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'administrator'
+AND p.name = 'seeders:run';
+
+-- DOWN
+DELETE FROM permissions WHERE name = 'seeders:run';
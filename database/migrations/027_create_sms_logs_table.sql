@@ -0,0 +1,20 @@
+-- version: 027_create_sms_logs_table
+-- description: Create sms_logs table recording every SMS handed to the provider, for usage statistics
+
+-- UP
+CREATE TABLE IF NOT EXISTS sms_logs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    recipient VARCHAR(20) NOT NULL,
+    message_type VARCHAR(50) NOT NULL,
+    status ENUM('sent', 'failed') NOT NULL,
+    provider_message_id BIGINT NULL,
+    error_msg TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    
+    INDEX idx_sms_logs_created_at (created_at),
+    INDEX idx_sms_logs_type_created_at (message_type, created_at),
+    INDEX idx_sms_logs_provider_message_id (provider_message_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- DOWN
+DROP TABLE IF EXISTS sms_logs;
@@ -0,0 +1,14 @@
+-- version: 028_add_related_user_to_notifications
+-- description: Link notifications to the user or entity they concern
+
+-- UP
+ALTER TABLE notifications
+    ADD COLUMN related_user_id INT NULL AFTER recipient,
+    ADD COLUMN related_user_type VARCHAR(20) NULL AFTER related_user_id;
+CREATE INDEX idx_notifications_related_user ON notifications (related_user_type, related_user_id, created_at);
+
+-- DOWN
+DROP INDEX idx_notifications_related_user ON notifications;
+ALTER TABLE notifications
+    DROP COLUMN related_user_type,
+    DROP COLUMN related_user_id;
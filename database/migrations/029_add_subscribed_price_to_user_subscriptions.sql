@@ -0,0 +1,14 @@
+-- version: 029_add_subscribed_price_to_user_subscriptions
+-- description: Keep the price a gamenet subscribed at on its subscription
+
+-- UP
+ALTER TABLE user_subscriptions
+    ADD COLUMN subscribed_price DECIMAL(10,2) NULL AFTER plan_id;
+UPDATE user_subscriptions us
+    JOIN subscription_plans sp ON sp.id = us.plan_id
+    SET us.subscribed_price = sp.price
+    WHERE us.status IN ('active', 'trial');
+
+-- DOWN
+ALTER TABLE user_subscriptions
+    DROP COLUMN subscribed_price;
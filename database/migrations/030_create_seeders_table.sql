@@ -0,0 +1,12 @@
+-- version: 030_create_seeders_table
+-- description: Record which named seeders have run so they are not repeated
+
+-- UP
+CREATE TABLE IF NOT EXISTS seeders (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    ran_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- DOWN
+DROP TABLE IF EXISTS seeders;
@@ -0,0 +1,21 @@
+-- version: 031_rename_failed_logins_to_login_attempts
+-- description: Rename failed_logins to login_attempts and store a reason category per attempt
+
+-- UP
+RENAME TABLE failed_logins TO login_attempts;
+
+-- Earlier rows stored the error message; it does not tell an unknown email from a wrong password
+UPDATE login_attempts SET reason = 'suspended' WHERE reason = 'account suspended';
+UPDATE login_attempts SET reason = 'other'
+WHERE reason IS NULL OR reason NOT IN ('unknown_email', 'wrong_password', 'locked', 'suspended', 'method_disabled');
+
+ALTER TABLE login_attempts
+    MODIFY COLUMN reason VARCHAR(32) NOT NULL DEFAULT 'other',
+    ADD INDEX idx_reason (reason);
+
+-- DOWN
+ALTER TABLE login_attempts
+    DROP INDEX idx_reason,
+    MODIFY COLUMN reason VARCHAR(255) NULL;
+
+RENAME TABLE login_attempts TO failed_logins;
@@ -0,0 +1,10 @@
+-- version: 032_add_totp_to_admins
+-- description: Add TOTP secret and enablement columns to admins table for two-factor authentication
+
+-- UP
+ALTER TABLE admins ADD COLUMN totp_secret VARCHAR(64) NULL AFTER password;
+ALTER TABLE admins ADD COLUMN totp_enabled_at TIMESTAMP NULL AFTER totp_secret;
+
+-- DOWN
+ALTER TABLE admins DROP COLUMN totp_enabled_at;
+ALTER TABLE admins DROP COLUMN totp_secret;
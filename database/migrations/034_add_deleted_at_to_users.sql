@@ -0,0 +1,10 @@
+-- version: 034_add_deleted_at_to_users
+-- description: Add deleted_at column to users table so deleting a user keeps its row and history
+
+-- UP
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL AFTER suspended_at;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+-- DOWN
+DROP INDEX idx_users_deleted_at ON users;
+ALTER TABLE users DROP COLUMN deleted_at;
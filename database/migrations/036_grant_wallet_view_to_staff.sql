@@ -0,0 +1,16 @@
+-- version: 036_grant_wallet_view_to_staff
+-- description: Let administrators and gamenets view user wallets and their transaction history
+
+-- UP
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name IN ('administrator', 'gamenet')
+AND p.name = 'wallet:view';
+
+-- DOWN
+DELETE rp FROM role_permissions rp
+INNER JOIN roles r ON rp.role_id = r.id
+INNER JOIN permissions p ON rp.permission_id = p.id
+WHERE r.name IN ('administrator', 'gamenet')
+AND p.name = 'wallet:view';
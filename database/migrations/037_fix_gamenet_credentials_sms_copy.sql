@@ -0,0 +1,12 @@
+-- version: 037_fix_gamenet_credentials_sms_copy
+-- description: Correct "گیت نت" to "گیم نت" in the seeded gamenet credentials SMS template
+
+-- UP
+UPDATE notification_templates
+SET content = REPLACE(content, 'سیستم گیت نت', 'سیستم گیم نت')
+WHERE name = 'gamenet_credentials_sms' AND type = 'sms';
+
+-- DOWN
+UPDATE notification_templates
+SET content = REPLACE(content, 'سیستم گیم نت', 'سیستم گیت نت')
+WHERE name = 'gamenet_credentials_sms' AND type = 'sms';
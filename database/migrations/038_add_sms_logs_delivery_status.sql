@@ -0,0 +1,12 @@
+-- version: 038_add_sms_logs_delivery_status
+-- description: Track the provider-reported delivery status of each recorded SMS
+
+-- UP
+ALTER TABLE sms_logs
+    ADD COLUMN delivery_status ENUM('pending', 'delivered', 'failed') NULL AFTER error_msg,
+    ADD COLUMN delivery_checked_at TIMESTAMP NULL AFTER delivery_status;
+
+-- DOWN
+ALTER TABLE sms_logs
+    DROP COLUMN delivery_checked_at,
+    DROP COLUMN delivery_status;
@@ -0,0 +1,11 @@
+-- version: 039_add_notification_permanently_failed_status
+-- description: Add the permanently_failed status for notifications that ran out of automatic retries
+
+-- UP
+ALTER TABLE notifications
+    MODIFY COLUMN status ENUM('pending', 'sent', 'failed', 'cancelled', 'permanently_failed') NOT NULL DEFAULT 'pending';
+
+-- DOWN
+UPDATE notifications SET status = 'failed' WHERE status = 'permanently_failed';
+ALTER TABLE notifications
+    MODIFY COLUMN status ENUM('pending', 'sent', 'failed', 'cancelled') NOT NULL DEFAULT 'pending';
@@ -0,0 +1,16 @@
+-- version: 040_add_manage_roles_permission
+-- description: Add the roles:manage permission for assigning and removing user roles from the admin API
+
+-- UP
+INSERT IGNORE INTO permissions (name, description, resource, action) VALUES
+('roles:manage', 'Assign and remove user roles', 'roles', 'manage');
+
+-- Assign permission to administrator role
+INSERT IGNORE INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE r.name = 'administrator'
+AND p.name = 'roles:manage';
+
+-- DOWN
+DELETE FROM permissions WHERE name = 'roles:manage';
@@ -0,0 +1,12 @@
+-- version: 041_allow_multiple_subscriptions_per_status
+-- description: Drop the unique (gamenet_id, status) key so a gamenet can keep several cancelled or expired subscriptions
+
+-- UP
+ALTER TABLE user_subscriptions
+    DROP INDEX unique_active_subscription,
+    ADD INDEX idx_gamenet_status (gamenet_id, status);
+
+-- DOWN
+ALTER TABLE user_subscriptions
+    DROP INDEX idx_gamenet_status,
+    ADD UNIQUE KEY unique_active_subscription (gamenet_id, status);
@@ -0,0 +1,20 @@
+-- version: 042_create_successful_logins_table
+-- description: Record successful logins for the login activity feed instead of deriving them from sessions
+
+-- UP
+CREATE TABLE IF NOT EXISTS successful_logins (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    user_type ENUM('user', 'admin', 'gamenet') NOT NULL,
+    ip_address VARCHAR(45) NULL,
+    user_agent TEXT NULL,
+    device_info TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_user (user_type, user_id),
+    INDEX idx_ip_address (ip_address),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- DOWN
+DROP TABLE IF EXISTS successful_logins;
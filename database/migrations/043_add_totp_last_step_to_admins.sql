@@ -0,0 +1,8 @@
+-- version: 043_add_totp_last_step_to_admins
+-- description: Remember the last accepted TOTP time step per admin so a code cannot be used twice
+
+-- UP
+ALTER TABLE admins ADD COLUMN totp_last_step BIGINT NULL AFTER totp_enabled_at;
+
+-- DOWN
+ALTER TABLE admins DROP COLUMN totp_last_step;
@@ -0,0 +1,8 @@
+-- version: 044_add_repriced_subscriptions_to_plan_history
+-- description: Record how many existing subscriptions a plan price change moved to the new price
+
+-- UP
+ALTER TABLE plan_history ADD COLUMN repriced_subscriptions INT NULL AFTER after_data;
+
+-- DOWN
+ALTER TABLE plan_history DROP COLUMN repriced_subscriptions;
@@ -0,0 +1,17 @@
+-- version: 045_create_login_lockouts_table
+-- description: Count failed logins per identifier in the database so lockouts survive restarts and are shared between instances
+
+-- UP
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    identifier VARCHAR(255) NOT NULL PRIMARY KEY,
+    failed_count INT NOT NULL DEFAULT 0,
+    window_ends_at DATETIME NOT NULL,
+    locked_until DATETIME NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_window_ends_at (window_ends_at),
+    INDEX idx_locked_until (locked_until)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- DOWN
+DROP TABLE IF EXISTS login_lockouts;
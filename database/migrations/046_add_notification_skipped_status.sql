@@ -0,0 +1,11 @@
+-- version: 046_add_notification_skipped_status
+-- description: Add the skipped status for notifications whose channel is disabled
+
+-- UP
+ALTER TABLE notifications
+    MODIFY COLUMN status ENUM('pending', 'sent', 'failed', 'cancelled', 'permanently_failed', 'skipped') NOT NULL DEFAULT 'pending';
+
+-- DOWN
+UPDATE notifications SET status = 'cancelled' WHERE status = 'skipped';
+ALTER TABLE notifications
+    MODIFY COLUMN status ENUM('pending', 'sent', 'failed', 'cancelled', 'permanently_failed') NOT NULL DEFAULT 'pending';
@@ -0,0 +1,8 @@
+-- version: 047_add_html_content_to_notifications
+-- description: Keep the HTML body of email notifications alongside the plain-text content
+
+-- UP
+ALTER TABLE notifications ADD COLUMN html_content MEDIUMTEXT NULL AFTER content;
+
+-- DOWN
+ALTER TABLE notifications DROP COLUMN html_content;
@@ -0,0 +1,10 @@
+-- version: 048_keep_plan_history_of_deleted_plans
+-- description: Keep a subscription plan's change history when the plan is deleted
+
+-- UP
+-- The cascading foreign key erased a plan's history together with the plan
+ALTER TABLE plan_history DROP FOREIGN KEY plan_history_ibfk_1;
+
+-- DOWN
+DELETE FROM plan_history WHERE plan_id NOT IN (SELECT id FROM subscription_plans);
+ALTER TABLE plan_history ADD CONSTRAINT plan_history_ibfk_1 FOREIGN KEY (plan_id) REFERENCES subscription_plans(id) ON DELETE CASCADE;
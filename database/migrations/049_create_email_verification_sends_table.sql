@@ -0,0 +1,14 @@
+-- version: 049_create_email_verification_sends_table
+-- description: Track when each account was last sent an email verification code, so the resend cooldown can be checked under a row lock
+
+-- UP
+CREATE TABLE IF NOT EXISTS email_verification_sends (
+    user_id INT NOT NULL,
+    user_type ENUM('user', 'admin', 'gamenet') NOT NULL,
+    last_sent_at DATETIME NULL,
+
+    PRIMARY KEY (user_id, user_type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- DOWN
+DROP TABLE IF EXISTS email_verification_sends;
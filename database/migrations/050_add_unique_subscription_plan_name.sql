@@ -0,0 +1,14 @@
+-- version: 050_add_unique_subscription_plan_name
+-- description: Make subscription plan names unique
+
+-- UP
+-- Existing duplicates keep their oldest plan's name; the others get their id appended
+UPDATE subscription_plans p
+JOIN (
+    SELECT name, MIN(id) AS keep_id FROM subscription_plans GROUP BY name HAVING COUNT(*) > 1
+) d ON p.name = d.name AND p.id <> d.keep_id
+SET p.name = CONCAT(p.name, ' #', p.id);
+ALTER TABLE subscription_plans ADD UNIQUE INDEX idx_name (name);
+
+-- DOWN
+ALTER TABLE subscription_plans DROP INDEX idx_name;
@@ -0,0 +1,160 @@
+package seeders
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+	_ "github.com/go-sql-driver/mysql"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// FakeUserPassword is the password every fake user can log in with
+const FakeUserPassword = "password123"
+
+// fakeUserBatchSize is how many users are inserted per transaction
+const fakeUserBatchSize = 500
+
+// FakeUserSeeder inserts bulk fake users for load testing
+type FakeUserSeeder struct {
+	db *sql.DB
+}
+
+// NewFakeUserSeeder creates a new fake user seeder instance
+func NewFakeUserSeeder(cfg *config.Config) (*FakeUserSeeder, error) {
+	db, err := sql.Open("mysql", cfg.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &FakeUserSeeder{db: db}, nil
+}
+
+// NewFakeUserSeederWithDB creates a fake user seeder on an existing connection
+func NewFakeUserSeederWithDB(db *sql.DB) *FakeUserSeeder {
+	return &FakeUserSeeder{db: db}
+}
+
+// FakeUserData represents a generated fake user
+type FakeUserData struct {
+	Name   string
+	Mobile string
+	Email  string
+}
+
+// SeedFakeUsers inserts count fake users, all with FakeUserPassword as password
+func SeedFakeUsers(cfg *config.Config, count int) error {
+	seeder, err := NewFakeUserSeeder(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create fake user seeder: %w", err)
+	}
+	defer seeder.Close()
+
+	_, err = seeder.Seed(count)
+	return err
+}
+
+// GenerateFakeUsers creates count users with random Persian names and mobiles and
+// emails that are unique within the set. The mobiles are consecutive numbers from a
+// random start, and the emails carry a per-run tag, so separate runs rarely collide.
+func GenerateFakeUsers(count int) []FakeUserData {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	firstNames := []string{
+		"علی", "محمد", "حسین", "رضا", "مهدی", "امیر", "سعید", "حسن", "احمد", "جواد",
+		"زهرا", "فاطمه", "مریم", "سارا", "نرگس", "لیلا", "مینا", "الهام", "نازنین", "پریسا",
+	}
+
+	lastNames := []string{
+		"احمدی", "رضایی", "کریمی", "محمدی", "حسینی", "نوری", "صادقی", "علیزاده", "رحمانی", "اکبری",
+		"جوادی", "مرادی", "کرمانی", "تهرانی", "اصفهانی", "شیرازی", "تبریزی", "یزدی", "قاسمی", "موسوی",
+	}
+
+	// Nine digits follow the 09 prefix; leave room so the range doesn't wrap
+	start := rng.Intn(1_000_000_000 - count)
+	runTag := fmt.Sprintf("%x", time.Now().UnixNano())
+
+	users := make([]FakeUserData, 0, count)
+	for i := 0; i < count; i++ {
+		users = append(users, FakeUserData{
+			Name:   fmt.Sprintf("%s %s", firstNames[rng.Intn(len(firstNames))], lastNames[rng.Intn(len(lastNames))]),
+			Mobile: fmt.Sprintf("09%09d", start+i),
+			Email:  fmt.Sprintf("loadtest.%s.%d@example.com", runTag, i+1),
+		})
+	}
+
+	return users
+}
+
+// Seed inserts count fake users in transactions of fakeUserBatchSize rows, logging
+// progress after each batch, and returns how many users were inserted
+func (s *FakeUserSeeder) Seed(count int) (int, error) {
+	if count <= 0 {
+		return 0, fmt.Errorf("count must be positive, got %d", count)
+	}
+
+	// Every fake user shares one hash, so it is computed once instead of per row
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(FakeUserPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return 0, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	users := GenerateFakeUsers(count)
+	inserted := 0
+
+	for start := 0; start < len(users); start += fakeUserBatchSize {
+		end := start + fakeUserBatchSize
+		if end > len(users) {
+			end = len(users)
+		}
+
+		if err := s.insertBatch(users[start:end], string(hashedPassword)); err != nil {
+			return inserted, fmt.Errorf("failed to insert users %d-%d: %w", start+1, end, err)
+		}
+
+		inserted = end
+		log.Printf("Seeded %d/%d fake users", inserted, count)
+	}
+
+	log.Printf("✅ Successfully seeded %d fake users (password: %s)", inserted, FakeUserPassword)
+	return inserted, nil
+}
+
+// insertBatch inserts users in a single transaction
+func (s *FakeUserSeeder) insertBatch(users []FakeUserData, hashedPassword string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT INTO users (name, mobile, email, password, created_at, updated_at) VALUES (?, ?, ?, ?, NOW(), NOW())")
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, user := range users {
+		if _, err := stmt.Exec(user.Name, user.Mobile, user.Email, hashedPassword); err != nil {
+			return fmt.Errorf("failed to insert user %s: %w", user.Mobile, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Close closes the database connection
+func (s *FakeUserSeeder) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
@@ -36,11 +36,23 @@ func NewNotificationTemplateSeeder(cfg *config.Config) (*NotificationTemplateSee
 	return &NotificationTemplateSeeder{db: db}, nil
 }
 
+// NewNotificationTemplateSeederWithDB creates a notification template seeder on an existing connection
+func NewNotificationTemplateSeederWithDB(db *sql.DB) *NotificationTemplateSeeder {
+	return &NotificationTemplateSeeder{db: db}
+}
+
 // SeedNotificationTemplates is the public seeder function that can be called by the registry
 func SeedNotificationTemplates(cfg *config.Config) error {
+	_, err := SeedNotificationTemplatesWithResult(cfg)
+	return err
+}
+
+// SeedNotificationTemplatesWithResult seeds the notification templates and reports
+// which templates were inserted and which already existed
+func SeedNotificationTemplatesWithResult(cfg *config.Config) (*models.SeedResult, error) {
 	seeder, err := NewNotificationTemplateSeeder(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to create notification template seeder: %w", err)
+		return nil, fmt.Errorf("failed to create notification template seeder: %w", err)
 	}
 	defer seeder.Close()
 
@@ -48,8 +60,9 @@ func SeedNotificationTemplates(cfg *config.Config) error {
 }
 
 // seedTemplates seeds the default notification templates
-func (s *NotificationTemplateSeeder) seedTemplates() error {
+func (s *NotificationTemplateSeeder) seedTemplates() (*models.SeedResult, error) {
 	log.Println("Seeding notification templates...")
+	result := models.NewSeedResult("notification_templates")
 
 	templates := []*models.NotificationTemplate{
 		{
@@ -66,8 +79,8 @@ func (s *NotificationTemplateSeeder) seedTemplates() error {
 		{
 			Name:    "password_reset_email",
 			Type:    models.NotificationTypeEmail,
-			Subject: "بازنشانی رمز عبور - {{app_name}}",
-			Content: "کاربر گرامی {{user_name}}،\n\nدرخواست بازنشانی رمز عبور برای حساب کاربری شما در {{app_name}} دریافت شده است.\n\nبرای تنظیم رمز عبور جدید، لطفاً روی لینک زیر کلیک کنید:\n{{reset_link}}\n\nاین لینک تا {{expiry_hours}} ساعت معتبر است.\n\nاگر شما این درخواست را انجام نداده‌اید، لطفاً این ایمیل را نادیده بگیرید.\n\nبا احترام،\nتیم {{app_name}}",
+			Subject: models.PasswordResetEmailSubject,
+			Content: models.PasswordResetEmailContent,
 			HTMLContent: `<!DOCTYPE html>
 <html lang="fa" dir="rtl">
 <head>
@@ -189,6 +202,16 @@ func (s *NotificationTemplateSeeder) seedTemplates() error {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
+		{
+			Name:      "email_verification_email",
+			Type:      models.NotificationTypeEmail,
+			Subject:   models.EmailVerificationEmailSubject,
+			Content:   models.EmailVerificationEmailContent,
+			Variables: []string{"app_name", "user_name", "current_email", "new_email", "verification_code"},
+			IsActive:  true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
 		{
 			Name:        "login_notification_email",
 			Type:        models.NotificationTypeEmail,
@@ -220,6 +243,26 @@ func (s *NotificationTemplateSeeder) seedTemplates() error {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
+		{
+			Name:      "user_credentials_sms",
+			Type:      models.NotificationTypeSMS,
+			Subject:   models.UserCredentialsSMSSubject,
+			Content:   models.UserCredentialsSMSContent,
+			Variables: []string{"email", "password"},
+			IsActive:  true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		{
+			Name:      "gamenet_credentials_sms",
+			Type:      models.NotificationTypeSMS,
+			Subject:   models.GamenetCredentialsSMSSubject,
+			Content:   models.GamenetCredentialsSMSContent,
+			Variables: []string{"email", "password"},
+			IsActive:  true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
 		{
 			Name:      "system_notification",
 			Type:      models.NotificationTypeDatabase,
@@ -233,17 +276,21 @@ func (s *NotificationTemplateSeeder) seedTemplates() error {
 	}
 
 	for _, template := range templates {
+		key := fmt.Sprintf("%s (%s)", template.Name, template.Type)
+
 		// Check if template already exists
 		var count int
 		err := s.db.QueryRow("SELECT COUNT(*) FROM notification_templates WHERE name = ? AND type = ?",
 			template.Name, template.Type).Scan(&count)
 		if err != nil {
 			log.Printf("Error checking template existence: %v", err)
+			result.Failed = append(result.Failed, key)
 			continue
 		}
 
 		if count > 0 {
-			log.Printf("Template %s (%s) already exists, skipping...", template.Name, template.Type)
+			log.Printf("Template %s already exists, skipping...", key)
+			result.Skipped = append(result.Skipped, key)
 			continue
 		}
 
@@ -259,14 +306,16 @@ func (s *NotificationTemplateSeeder) seedTemplates() error {
 
 		if err != nil {
 			log.Printf("Error seeding template %s: %v", template.Name, err)
+			result.Failed = append(result.Failed, key)
 			continue
 		}
 
-		log.Printf("✅ Seeded template: %s (%s)", template.Name, template.Type)
+		log.Printf("✅ Seeded template: %s", key)
+		result.Inserted = append(result.Inserted, key)
 	}
 
 	log.Println("Notification template seeding completed!")
-	return nil
+	return result, nil
 }
 
 // Close closes the database connection
@@ -56,7 +56,7 @@ func (r *Registry) Run(name string, cfg *config.Config) error {
 // RunAll executes only specified default seeders
 func (r *Registry) RunAll(cfg *config.Config) error {
 	// Default seeders to run
-	defaultSeeders := []string{"admin", "notification_template"}
+	defaultSeeders := []string{"admin", "notification_templates"}
 
 	log.Printf("Running default seeders: %v", defaultSeeders)
 
@@ -0,0 +1,142 @@
+package seeders
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/gatehide/gatehide-api/config"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// SeederRunner records which named seeders have run so they are not repeated
+type SeederRunner interface {
+	CreateSeederTable() error
+	HasRun(name string) (bool, error)
+	MarkRun(name string) error
+	Close() error
+}
+
+// MySQLSeederRunner implements SeederRunner on the seeders table
+type MySQLSeederRunner struct {
+	db *sql.DB
+}
+
+// Ensure MySQLSeederRunner satisfies SeederRunner
+var _ SeederRunner = (*MySQLSeederRunner)(nil)
+
+// NewMySQLSeederRunner creates a new seeder runner connected to the configured database
+func NewMySQLSeederRunner(cfg *config.Config) (*MySQLSeederRunner, error) {
+	db, err := sql.Open("mysql", cfg.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &MySQLSeederRunner{db: db}, nil
+}
+
+// NewMySQLSeederRunnerWithDB creates a seeder runner on an existing connection
+func NewMySQLSeederRunnerWithDB(db *sql.DB) *MySQLSeederRunner {
+	return &MySQLSeederRunner{db: db}
+}
+
+// CreateSeederTable creates the seeders tracking table
+func (r *MySQLSeederRunner) CreateSeederTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS seeders (
+		id INT AUTO_INCREMENT PRIMARY KEY,
+		name VARCHAR(100) NOT NULL UNIQUE,
+		ran_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create seeders table: %w", err)
+	}
+	return nil
+}
+
+// HasRun reports whether the named seeder has been recorded as run
+func (r *MySQLSeederRunner) HasRun(name string) (bool, error) {
+	var count int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM seeders WHERE name = ?", name).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check seeder %s: %w", name, err)
+	}
+	return count > 0, nil
+}
+
+// MarkRun records that the named seeder has run, refreshing the time of a forced re-run
+func (r *MySQLSeederRunner) MarkRun(name string) error {
+	query := "INSERT INTO seeders (name) VALUES (?) ON DUPLICATE KEY UPDATE ran_at = CURRENT_TIMESTAMP"
+	if _, err := r.db.Exec(query, name); err != nil {
+		return fmt.Errorf("failed to record seeder %s: %w", name, err)
+	}
+	return nil
+}
+
+// Close closes the database connection
+func (r *MySQLSeederRunner) Close() error {
+	if r.db != nil {
+		return r.db.Close()
+	}
+	return nil
+}
+
+// RunOnce executes a seeder unless the runner has already recorded it, then records it.
+// With force the seeder runs regardless. It reports whether the seeder ran.
+func (r *Registry) RunOnce(runner SeederRunner, name string, cfg *config.Config, force bool) (bool, error) {
+	if _, exists := r.Get(name); !exists {
+		return false, fmt.Errorf("seeder '%s' not found. Available seeders: %v", name, r.List())
+	}
+
+	if !force {
+		ran, err := runner.HasRun(name)
+		if err != nil {
+			return false, err
+		}
+		if ran {
+			log.Printf("Skipping seeder %s: already run (use -force to run it again)", name)
+			return false, nil
+		}
+	}
+
+	if err := r.Run(name, cfg); err != nil {
+		return false, err
+	}
+
+	if err := runner.MarkRun(name); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// RunAllOnce executes every registered seeder that the runner has not recorded yet,
+// or all of them with force. Seeders run in name order.
+func (r *Registry) RunAllOnce(runner SeederRunner, cfg *config.Config, force bool) error {
+	names := r.List()
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := r.RunOnce(runner, name, cfg, force); err != nil {
+			return fmt.Errorf("failed to run seeder '%s': %w", name, err)
+		}
+	}
+
+	log.Println("All seeders completed successfully")
+	return nil
+}
+
+// RunSeederOnce executes a seeder from the global registry unless it has already run
+func RunSeederOnce(runner SeederRunner, name string, cfg *config.Config, force bool) (bool, error) {
+	return globalRegistry.RunOnce(runner, name, cfg, force)
+}
+
+// RunAllSeedersOnce executes the seeders from the global registry that have not run yet
+func RunAllSeedersOnce(runner SeederRunner, cfg *config.Config, force bool) error {
+	return globalRegistry.RunAllOnce(runner, cfg, force)
+}
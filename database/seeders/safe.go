@@ -0,0 +1,67 @@
+package seeders
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// ErrSeederNotAllowed is returned when a seeder is not in the safe set
+var ErrSeederNotAllowed = errors.New("seeder not allowed")
+
+// ReportingSeederFunc runs a seeder on an existing connection and reports what it
+// inserted and skipped
+type ReportingSeederFunc func(db *sql.DB) (*models.SeedResult, error)
+
+// safeSeeders lists the seeders that only insert missing rows and never modify
+// or delete existing data, so they can be re-run against a live database
+var safeSeeders = map[string]ReportingSeederFunc{
+	"notification_templates": func(db *sql.DB) (*models.SeedResult, error) {
+		return NewNotificationTemplateSeederWithDB(db).seedTemplates()
+	},
+}
+
+// SafeSeeders runs the seeders that may be re-run at runtime
+type SafeSeeders interface {
+	List() []string
+	Run(name string) (*models.SeedResult, error)
+}
+
+// safeSeederSet runs the safe seeders on the application's database connection
+type safeSeederSet struct {
+	db *sql.DB
+}
+
+// NewSafeSeeders returns the safe seeders, run on an existing connection
+func NewSafeSeeders(db *sql.DB) SafeSeeders {
+	return &safeSeederSet{db: db}
+}
+
+// List returns the names of the seeders that may be re-run at runtime
+func (s *safeSeederSet) List() []string {
+	return ListSafeSeeders()
+}
+
+// Run runs a seeder from the safe set and returns its report.
+// Returns an error wrapping ErrSeederNotAllowed for names outside the safe set.
+func (s *safeSeederSet) Run(name string) (*models.SeedResult, error) {
+	seeder, ok := safeSeeders[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (allowed: %v)", ErrSeederNotAllowed, name, ListSafeSeeders())
+	}
+
+	return seeder(s.db)
+}
+
+// ListSafeSeeders returns the names of the seeders that may be re-run at runtime
+func ListSafeSeeders() []string {
+	names := make([]string, 0, len(safeSeeders))
+	for name := range safeSeeders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
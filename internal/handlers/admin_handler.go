@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles admin account HTTP requests
+type AdminHandler struct {
+	adminService services.AdminServiceInterface
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminService services.AdminServiceInterface) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// GetAllAdmins handles GET /admins. It returns a page of admins, optionally filtered
+// by a query matched against name, mobile and email.
+func (h *AdminHandler) GetAllAdmins(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	result, err := h.adminService.Search(c.Request.Context(), &models.UserSearchRequest{
+		Query:    c.Query("query"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to search admins",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Admins retrieved successfully",
+		"data":       result.Data,
+		"pagination": result.Pagination,
+	})
+}
@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gatehide/gatehide-api/internal/middlewares"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gatehide/gatehide-api/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -14,8 +20,9 @@ import (
 
 // AuthHandler handles authentication HTTP requests
 type AuthHandler struct {
-	authService  services.AuthServiceInterface
-	fileUploader *utils.FileUploader
+	authService             services.AuthServiceInterface
+	fileUploader            *utils.FileUploader
+	exposeVerificationCodes bool
 }
 
 // NewAuthHandler creates a new authentication handler
@@ -26,6 +33,28 @@ func NewAuthHandler(authService services.AuthServiceInterface, fileUploader *uti
 	}
 }
 
+// SetExposeVerificationCodes makes SendEmailVerification return the code it sent, so
+// tests can complete the email change without reading the mailbox
+func (h *AuthHandler) SetExposeVerificationCodes(expose bool) {
+	h.exposeVerificationCodes = expose
+}
+
+// bearerToken returns the token of the request's Authorization header, with or without
+// the "Bearer " prefix, or an empty string when there is none
+func bearerToken(c *gin.Context) string {
+	authHeader := c.GetHeader("Authorization")
+	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+		return authHeader[7:]
+	}
+	return authHeader
+}
+
+// setRetryAfter sets the Retry-After header to the wait in whole seconds, rounded up
+// the same way as the rate limit middleware
+func setRetryAfter(c *gin.Context, wait time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
+
 // RefreshToken handles token refresh requests
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	authHeader := c.GetHeader("Authorization")
@@ -37,10 +66,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	}
 
 	// Extract token from "Bearer <token>" format
-	tokenString := authHeader
-	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-		tokenString = authHeader[7:]
-	}
+	tokenString := bearerToken(c)
 
 	// Get remember me preference from request body (optional)
 	var req struct {
@@ -50,6 +76,13 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 
 	newToken, err := h.authService.RefreshToken(tokenString, req.RememberMe)
 	if err != nil {
+		if errors.Is(err, utils.ErrRefreshWindowExpired) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Token expired too long ago to refresh, please log in again",
+				"code":  middlewares.TokenErrorRefreshExpired,
+			})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": "Invalid or expired token",
 		})
@@ -96,8 +129,12 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	fmt.Printf("User logout: ID=%d, Email=%s, Type=%s, Time=%s\n",
 		claims.UserID, claims.Email, claims.UserType, time.Now().Format(time.RFC3339))
 
-	// Since we're using stateless JWT tokens, logout is handled client-side
-	// by removing the token from storage. This endpoint confirms the logout.
+	// Deactivate the login session; the client still discards the token itself, so a
+	// failure here doesn't fail the logout
+	if err := h.authService.LogoutSession(tokenString); err != nil {
+		fmt.Printf("Warning: failed to deactivate session on logout for %s %d: %v\n", claims.UserType, claims.UserID, err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Logout successful",
 		"data": gin.H{
@@ -109,8 +146,15 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 // Login handles unified login requests (automatically determines user type)
 func (h *AuthHandler) Login(c *gin.Context) {
-	var req models.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	// v2 clients may describe their device in the body; v1 uses the X-Device-Info header
+	var req models.LoginRequestV2
+	var err error
+	if middlewares.GetAPIVersion(c) >= middlewares.APIVersion2 {
+		err = c.ShouldBindJSON(&req)
+	} else {
+		err = c.ShouldBindJSON(&req.LoginRequest)
+	}
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request data",
 			"details": err.Error(),
@@ -118,20 +162,85 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Extract device information from request headers
-	deviceInfo := c.GetHeader("X-Device-Info")
+	// Extract device information from the request body or headers
+	deviceInfo := req.Device.String()
+	if deviceInfo == "" {
+		deviceInfo = c.GetHeader("X-Device-Info")
+	}
 	ipAddress := c.ClientIP()
 	userAgent := c.GetHeader("User-Agent")
 
-	// Use LoginWithSession to create a session during login
-	response, err := h.authService.LoginWithSession(req.Email, req.Password, req.RememberMe, deviceInfo, ipAddress, userAgent)
+	// Use LoginWithSession to create a session during login; a client that is still
+	// signed in sends its token so its session is renewed rather than duplicated
+	response, err := h.authService.LoginWithSession(req.Email, req.Password, req.RememberMe, deviceInfo, ipAddress, userAgent, bearerToken(c))
 	if err != nil {
+		if errors.Is(err, services.ErrLoginMethodDisabled) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": services.ErrLoginMethodDisabled.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrAccountSuspended) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		var tooMany *services.TooManyAttemptsError
+		if errors.As(err, &tooMany) {
+			setRetryAfter(c, tooMany.RetryAfter)
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": services.ErrTooManyLoginAttempts.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	if response.TwoFactorRequired() {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Two-factor authentication required",
+			"data":    response,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successful",
+		"data":    response,
+	})
+}
+
+// VerifyTOTP handles POST /auth/2fa/verify, the second step of a two-factor login. It
+// exchanges the challenge token returned by Login and a TOTP code for an access token.
+func (h *AuthHandler) VerifyTOTP(c *gin.Context) {
+	var req models.TOTPVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.authService.VerifyTOTP(req.ChallengeToken, req.Code, c.GetHeader("X-Device-Info"), c.ClientIP(), c.GetHeader("User-Agent"), bearerToken(c))
+	if err != nil {
+		var tooMany *services.TooManyAttemptsError
+		switch {
+		case errors.As(err, &tooMany):
+			setRetryAfter(c, tooMany.RetryAfter)
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": services.ErrTooManyTOTPAttempts.Error()})
+		case errors.Is(err, services.ErrInvalidTOTPChallenge), errors.Is(err, services.ErrInvalidTOTPCode):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify two-factor code"})
+		}
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Login successful",
 		"data":    response,
@@ -160,6 +269,7 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 	// Fetch complete user data from database
 	var user interface{}
+	var updatedAt time.Time
 
 	switch claims.UserType {
 	case "admin":
@@ -171,6 +281,7 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 			return
 		}
 		user = admin.ToResponse()
+		updatedAt = admin.UpdatedAt
 	case "gamenet":
 		gamenet, err := h.authService.GetGamenetByID(claims.UserID)
 		if err != nil {
@@ -180,6 +291,7 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 			return
 		}
 		user = gamenet.ToResponse()
+		updatedAt = gamenet.UpdatedAt
 	default: // "user"
 		userModel, err := h.authService.GetUserByID(claims.UserID)
 		if err != nil {
@@ -189,6 +301,7 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 			return
 		}
 		user = userModel.ToResponse()
+		updatedAt = userModel.UpdatedAt
 	}
 
 	// Get user permissions
@@ -200,6 +313,12 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	// Let polling clients skip unchanged profiles. Role and permission changes leave no
+	// timestamp on the account, so the ETag covers the permissions as well.
+	if checkNotModified(c, updatedAt, profileETag(claims.UserType, updatedAt, permissions)) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile retrieved successfully",
 		"data": models.ProfileResponse{
@@ -210,6 +329,14 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 	})
 }
 
+// profileETag identifies a profile version by the account's last update and its
+// permissions, in any order
+func profileETag(userType string, updatedAt time.Time, permissions []string) string {
+	sorted := append([]string(nil), permissions...)
+	sort.Strings(sorted)
+	return computeETag(userType, strconv.FormatInt(updatedAt.UnixNano(), 10), strings.Join(sorted, ","))
+}
+
 // UpdateProfile handles profile update requests
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	// Get user info from context (set by middleware)
@@ -257,6 +384,12 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		user, err = h.authService.UpdateUserProfile(claims.UserID, req.Name, req.Mobile, req.Image)
 	}
 
+	if errors.Is(err, services.ErrMobileInUse) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "This mobile number is already in use",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to update profile",
@@ -300,6 +433,47 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 	})
 }
 
+// IssueUserResetLink handles POST /users/:id/reset-token. It returns a one-time reset
+// link to the requesting admin instead of emailing it to the user.
+func (h *AuthHandler) IssueUserResetLink(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	actor := middlewares.GetCurrentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	link, err := h.authService.IssueUserResetLink(userID, actor)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to issue password reset link",
+		})
+		return
+	}
+
+	// The link grants account access; keep it out of any cache
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password reset link issued successfully",
+		"data":    link,
+	})
+}
+
 // ResetPassword handles password reset requests
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var req models.ResetPasswordRequest
@@ -325,9 +499,16 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 			})
 			return
 		}
-		if err.Error() == "password must be at least 6 characters long" {
+		if errors.Is(err, utils.ErrWeakPassword) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Password does not meet the password policy",
+				"details": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, models.ErrPasswordTooLong) {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Password must be at least 6 characters long",
+				"error": "Password must not be longer than 72 bytes",
 			})
 			return
 		}
@@ -342,6 +523,22 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	})
 }
 
+// weakPasswordMessage describes the password policy rule a new password failed
+func weakPasswordMessage(err *utils.WeakPasswordError) string {
+	switch err.Rule {
+	case utils.ErrPasswordNoDigit:
+		return "رمز عبور باید حداقل یک رقم داشته باشد"
+	case utils.ErrPasswordNoUpper:
+		return "رمز عبور باید حداقل یک حرف بزرگ داشته باشد"
+	case utils.ErrPasswordNoLower:
+		return "رمز عبور باید حداقل یک حرف کوچک داشته باشد"
+	case utils.ErrPasswordNoSymbol:
+		return "رمز عبور باید حداقل یک نماد داشته باشد"
+	default:
+		return fmt.Sprintf("رمز عبور باید حداقل %d کاراکتر باشد", err.MinLength)
+	}
+}
+
 // ValidateResetToken validates a password reset token
 func (h *AuthHandler) ValidateResetToken(c *gin.Context) {
 	token := c.Query("token")
@@ -393,13 +590,23 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	// The session making the change stays logged in
+	currentToken, err := middlewares.ExtractTokenFromHeader(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "توکن یافت نشد",
+		})
+		return
+	}
+
 	// Call the service to change password
-	err := h.authService.ChangePassword(
+	err = h.authService.ChangePassword(
 		userID.(int),
 		userType.(string),
 		req.CurrentPassword,
 		req.NewPassword,
 		req.ConfirmPassword,
+		currentToken,
 	)
 
 	if err != nil {
@@ -415,9 +622,16 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 			})
 			return
 		}
-		if err.Error() == "رمز عبور باید حداقل 6 کاراکتر باشد" {
+		var weak *utils.WeakPasswordError
+		if errors.As(err, &weak) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": weakPasswordMessage(weak),
+			})
+			return
+		}
+		if errors.Is(err, models.ErrPasswordTooLong) {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "رمز عبور باید حداقل 6 کاراکتر باشد",
+				"error": "رمز عبور نباید بیشتر از 72 بایت باشد",
 			})
 			return
 		}
@@ -488,18 +702,30 @@ func (h *AuthHandler) SendEmailVerification(c *gin.Context) {
 	// Send verification email using the auth service
 	verificationCode, err := h.authService.SendEmailVerification(claims.UserID, claims.UserType, req.NewEmail)
 	if err != nil {
+		if errors.Is(err, utils.ErrDisposableEmail) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Disposable email addresses are not allowed"})
+			return
+		}
+		var tooMany *services.TooManyAttemptsError
+		if errors.As(err, &tooMany) {
+			setRetryAfter(c, tooMany.RetryAfter)
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": services.ErrVerificationCodeCooldown.Error(),
+			})
+			return
+		}
 		fmt.Printf("Failed to send email verification: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
 		return
 	}
 
-	// Log verification code for development/testing (remove in production)
-	fmt.Printf("Verification code for user %d (%s) to email %s: %s\n", claims.UserID, claims.Email, req.NewEmail, verificationCode)
-
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message": "Verification code sent to email",
-		"code":    verificationCode, // Remove this in production
-	})
+	}
+	if h.exposeVerificationCodes {
+		response["code"] = verificationCode
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // VerifyEmailCode verifies the email verification code and updates the email
@@ -570,6 +796,236 @@ func (h *AuthHandler) VerifyEmailCode(c *gin.Context) {
 	})
 }
 
+// ChangeEmail changes an admin's email in a single step after confirming the current password
+func (h *AuthHandler) ChangeEmail(c *gin.Context) {
+	userInfo, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	claims := userInfo.(*utils.JWTClaims)
+	if claims.UserType != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var req models.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	admin, err := h.authService.ChangeAdminEmail(claims.UserID, req.CurrentPassword, req.NewEmail)
+	if err != nil {
+		switch err.Error() {
+		case "invalid password":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Current password is incorrect"})
+		case "new email must be different from current email":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "New email must be different from current email"})
+		case "email already in use":
+			c.JSON(http.StatusConflict, gin.H{"error": "This email address is already in use"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update email"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Email updated successfully",
+		"user":    admin,
+	})
+}
+
+// VerifyPassword handles POST /auth/verify-password. It confirms the current password of
+// the authenticated account and returns a short-lived re-authentication token that must be
+// sent in the X-Reauth-Token header to sensitive endpoints.
+func (h *AuthHandler) VerifyPassword(c *gin.Context) {
+	claims, exists := middlewares.GetCurrentUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.VerifyPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	reauth, err := h.authService.VerifyPassword(claims.UserID, claims.UserType, req.Password)
+	if err != nil {
+		var tooMany *services.TooManyAttemptsError
+		switch {
+		case errors.As(err, &tooMany):
+			setRetryAfter(c, tooMany.RetryAfter)
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many attempts, please try again later"})
+		case errors.Is(err, services.ErrInvalidPassword):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Password is incorrect"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify password"})
+		}
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password verified successfully",
+		"data":    reauth,
+	})
+}
+
+// GetSessions handles GET /profile/sessions, listing where the caller is logged in
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	claims, exists := middlewares.GetCurrentUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	sessions, err := h.authService.GetActiveSessions(claims.UserID, claims.UserType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve sessions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Mark the session this request was made with
+	if current, ok := c.Get("session"); ok {
+		if session, ok := current.(*models.UserSession); ok {
+			for i := range sessions {
+				sessions[i].IsCurrent = sessions[i].ID == session.ID
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sessions retrieved successfully",
+		"data":    sessions,
+	})
+}
+
+// RevokeOtherSessions handles POST /profile/sessions/revoke-others, logging out every
+// device except the one making the request
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	claims, exists := middlewares.GetCurrentUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	currentToken, err := middlewares.ExtractTokenFromHeader(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error":   "Failed to extract token",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.RevokeOtherSessions(claims.UserID, claims.UserType, currentToken); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to revoke other sessions",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Other sessions revoked successfully",
+	})
+}
+
+// EnableTOTP handles POST /profile/2fa, starting two-factor setup for the calling admin.
+// The returned secret must be confirmed with a code before logins require it.
+func (h *AuthHandler) EnableTOTP(c *gin.Context) {
+	claims, exists := middlewares.GetCurrentUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	secret, otpauthURL, err := h.authService.EnableTOTP(claims.UserID)
+	if err != nil {
+		if errors.Is(err, services.ErrTOTPAlreadyEnabled) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to set up two-factor authentication",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication secret generated",
+		"data": models.TOTPSetupResponse{
+			Secret:     secret,
+			OTPAuthURL: otpauthURL,
+		},
+	})
+}
+
+// ConfirmTOTP handles POST /profile/2fa/confirm, enabling two-factor authentication for
+// the calling admin once a code from their authenticator app matches
+func (h *AuthHandler) ConfirmTOTP(c *gin.Context) {
+	claims, exists := middlewares.GetCurrentUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if err := h.authService.ConfirmTOTP(claims.UserID, req.Code); err != nil {
+		var tooMany *services.TooManyAttemptsError
+		switch {
+		case errors.As(err, &tooMany):
+			setRetryAfter(c, tooMany.RetryAfter)
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": services.ErrTooManyTOTPAttempts.Error()})
+		case errors.Is(err, services.ErrInvalidTOTPCode):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrTOTPAlreadyEnabled), errors.Is(err, services.ErrTOTPNotSetUp):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to enable two-factor authentication",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication enabled successfully",
+	})
+}
+
+// thumbnailURL returns the public URL of an image's thumbnail, or nil when none was generated
+func thumbnailURL(result *utils.ImageUploadResult) *string {
+	if result.Thumbnail == nil {
+		return nil
+	}
+	return &result.Thumbnail.PublicURL
+}
+
 // UploadProfileImage handles profile image upload
 func (h *AuthHandler) UploadProfileImage(c *gin.Context) {
 	userInfo, exists := c.Get("user")
@@ -586,12 +1042,17 @@ func (h *AuthHandler) UploadProfileImage(c *gin.Context) {
 		return
 	}
 
-	// Upload the file
-	uploadResult, err := h.fileUploader.UploadFile(file, "profiles")
+	// Upload the image, resizing it and generating a thumbnail
+	imageResult, err := h.fileUploader.UploadImage(file, "profiles")
 	if err != nil {
+		if errors.Is(err, utils.ErrImageTooSmall) || errors.Is(err, utils.ErrUnsupportedImage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image: " + err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image: " + err.Error()})
 		return
 	}
+	uploadResult := imageResult.Image
 
 	// Update user profile with new image URL
 	var user interface{}
@@ -611,8 +1072,9 @@ func (h *AuthHandler) UploadProfileImage(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Profile image updated successfully",
 		"data": gin.H{
-			"user":      user,
-			"image_url": uploadResult.PublicURL,
+			"user":          user,
+			"image_url":     uploadResult.PublicURL,
+			"thumbnail_url": thumbnailURL(imageResult),
 		},
 	})
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkNotModified sets the Last-Modified and ETag headers for a single-resource GET
+// and responds with 304 Not Modified when the client's copy is current. When etag is
+// set, If-None-Match decides and If-Modified-Since is ignored, because the etag can
+// cover state that lastModified doesn't. Without an etag, a client copy not older than
+// lastModified is current. It returns true when the response has been written.
+func checkNotModified(c *gin.Context, lastModified time.Time, etag string) bool {
+	if !lastModified.IsZero() {
+		// HTTP dates have second precision
+		lastModified = lastModified.UTC().Truncate(time.Second)
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if etag != "" {
+		c.Header("ETag", etag)
+		ifNoneMatch := c.GetHeader("If-None-Match")
+		if ifNoneMatch == "" || !etagMatches(ifNoneMatch, etag) {
+			return false
+		}
+	} else {
+		if lastModified.IsZero() {
+			return false
+		}
+
+		ifModifiedSince := c.GetHeader("If-Modified-Since")
+		if ifModifiedSince == "" {
+			return false
+		}
+
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+
+		if lastModified.After(since) {
+			return false
+		}
+	}
+
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header lists etag, using the weak
+// comparison that GET requests call for
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// computeETag returns a strong entity tag for the given parts
+func computeETag(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+}
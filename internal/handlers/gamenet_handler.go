@@ -1,20 +1,24 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gatehide/gatehide-api/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // GamenetHandler handles gamenet HTTP requests
 type GamenetHandler struct {
 	gamenetService services.GamenetServiceInterface
+	userService    services.UserServiceInterface
 	fileUploader   *utils.FileUploader
 }
 
@@ -26,6 +30,11 @@ func NewGamenetHandler(gamenetService services.GamenetServiceInterface, fileUplo
 	}
 }
 
+// SetUserService sets the user service used to link users to gamenets
+func (h *GamenetHandler) SetUserService(userService services.UserServiceInterface) {
+	h.userService = userService
+}
+
 // GetAllGamenets handles GET /gamenets
 func (h *GamenetHandler) GetAllGamenets(c *gin.Context) {
 	// Check if search parameters are provided
@@ -102,8 +111,15 @@ func (h *GamenetHandler) GetGamenetByID(c *gin.Context) {
 
 	gamenet, err := h.gamenetService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Gamenet not found",
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Gamenet not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve gamenet",
+			"details": err.Error(),
 		})
 		return
 	}
@@ -132,6 +148,25 @@ func (h *GamenetHandler) CreateGamenet(c *gin.Context) {
 		Address:     c.PostForm("address"),
 		Email:       c.PostForm("email"),
 	}
+	if sendCredentials := c.PostForm("send_credentials"); sendCredentials != "" {
+		value, err := strconv.ParseBool(sendCredentials)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "send_credentials must be true or false",
+			})
+			return
+		}
+		req.SendCredentials = &value
+	}
+
+	// Form values skip binding, so validate them here
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
 
 	// Handle license file upload
 	file, fileHeader, err := c.Request.FormFile("license_attachment")
@@ -152,8 +187,15 @@ func (h *GamenetHandler) CreateGamenet(c *gin.Context) {
 
 	gamenet, err := h.gamenetService.Create(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
+		if errors.Is(err, services.ErrGamenetEmailTaken) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to create gamenet",
+			"details": err.Error(),
 		})
 		return
 	}
@@ -308,6 +350,78 @@ func (h *GamenetHandler) ResendCredentials(c *gin.Context) {
 	})
 }
 
+// LinkUser handles POST /gamenets/:id/users/:userId
+func (h *GamenetHandler) LinkUser(c *gin.Context) {
+	gamenetID, userID, ok := h.parseGamenetUserParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.userService.AttachToGamenet(c.Request.Context(), userID, gamenetID)
+	if err != nil {
+		respondGamenetLinkError(c, err, "Failed to link user to gamenet")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User linked to gamenet successfully",
+	})
+}
+
+// UnlinkUser handles DELETE /gamenets/:id/users/:userId
+func (h *GamenetHandler) UnlinkUser(c *gin.Context) {
+	gamenetID, userID, ok := h.parseGamenetUserParams(c)
+	if !ok {
+		return
+	}
+
+	err := h.userService.DetachFromGamenet(c.Request.Context(), userID, gamenetID)
+	if err != nil {
+		respondGamenetLinkError(c, err, "Failed to unlink user from gamenet")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User unlinked from gamenet successfully",
+	})
+}
+
+// parseGamenetUserParams parses the gamenet and user IDs from the path and checks the
+// gamenet exists, writing the error response when it returns false
+func (h *GamenetHandler) parseGamenetUserParams(c *gin.Context) (int, int, bool) {
+	gamenetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gamenet ID",
+		})
+		return 0, 0, false
+	}
+
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return 0, 0, false
+	}
+
+	if _, err := h.gamenetService.GetByID(c.Request.Context(), gamenetID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Gamenet not found",
+			})
+			return 0, 0, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve gamenet",
+			"details": err.Error(),
+		})
+		return 0, 0, false
+	}
+
+	return gamenetID, userID, true
+}
+
 // extractFilePathFromURL extracts the file path from a public URL or relative path
 func (h *GamenetHandler) extractFilePathFromURL(publicURL string) string {
 	// Handle both formats:
@@ -1,17 +1,47 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gatehide/gatehide-api/config"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
+// readinessTimeout bounds how long the readiness probe waits for each dependency
+const readinessTimeout = 5 * time.Second
+
+// notificationCheckTTL is how long the SMS and SMTP statuses are reused before the
+// probe contacts the providers again, so polling the public probe can't hammer them
+const notificationCheckTTL = 30 * time.Second
+
+// DatabasePinger is the part of *sql.DB the readiness probe uses
+type DatabasePinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// CircuitStateReporter reports the state of a circuit breaker
+type CircuitStateReporter interface {
+	CircuitState() string
+}
+
 // HealthHandler handles health check requests
 type HealthHandler struct {
-	config *config.Config
+	config        *config.Config
+	db            DatabasePinger
+	notifications services.NotificationServiceInterface
+	smsCircuit    CircuitStateReporter
+
+	// Cached SMS and email statuses, guarded by mu
+	mu                   sync.Mutex
+	notificationStatuses map[string]models.DependencyStatus
+	notificationsChecked time.Time
 }
 
 // NewHealthHandler creates a new health handler instance
@@ -21,6 +51,18 @@ func NewHealthHandler(cfg *config.Config) *HealthHandler {
 	}
 }
 
+// SetReadinessChecks sets the dependencies checked by the readiness probe.
+// Dependencies left nil are not reported.
+func (h *HealthHandler) SetReadinessChecks(db DatabasePinger, notifications services.NotificationServiceInterface) {
+	h.db = db
+	h.notifications = notifications
+}
+
+// SetSMSCircuit sets the circuit breaker whose state is reported with the SMS check
+func (h *HealthHandler) SetSMSCircuit(circuit CircuitStateReporter) {
+	h.smsCircuit = circuit
+}
+
 // Check handles the health check endpoint
 // @Summary Health Check
 // @Description Check if the API is running and healthy
@@ -31,10 +73,92 @@ func NewHealthHandler(cfg *config.Config) *HealthHandler {
 func (h *HealthHandler) Check(c *gin.Context) {
 	response := models.HealthResponse{
 		Status:    "healthy",
-		Timestamp: time.Now(),
+		Timestamp: models.NewTimestamp(time.Now()),
 		Service:   h.config.App.Name,
 		Version:   h.config.App.Version,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// Ready handles the readiness probe
+// @Summary Readiness Check
+// @Description Check that the database is reachable; only the database decides readiness. The SMS provider and SMTP relay are reported for information, from a status refreshed at most every 30 seconds, and never fail the probe. Disabled transports are reported as disabled. The SMS check includes the state of the SMS circuit breaker.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.ReadinessResponse
+// @Failure 503 {object} models.ReadinessResponse
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	checks := make(map[string]models.DependencyStatus)
+
+	response := models.ReadinessResponse{
+		Status:    models.ReadinessReady,
+		Timestamp: models.NewTimestamp(time.Now()),
+		Checks:    checks,
+	}
+	status := http.StatusOK
+
+	if h.db != nil {
+		checks["database"] = checkDependency(c.Request.Context(), "database", h.db.PingContext)
+		if checks["database"].Status == models.DependencyDown {
+			response.Status = models.ReadinessNotReady
+			status = http.StatusServiceUnavailable
+		}
+	}
+	if h.notifications != nil {
+		for name, check := range h.notificationChecks(c.Request.Context()) {
+			checks[name] = check
+		}
+		if h.smsCircuit != nil {
+			sms := checks["sms"]
+			sms.CircuitState = h.smsCircuit.CircuitState()
+			checks["sms"] = sms
+		}
+	}
+
+	c.JSON(status, response)
+}
+
+// notificationChecks returns the SMS and email statuses, contacting the providers only
+// when the cached statuses are older than notificationCheckTTL
+func (h *HealthHandler) notificationChecks(ctx context.Context) map[string]models.DependencyStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	if h.notificationStatuses == nil || now.Sub(h.notificationsChecked) >= notificationCheckTTL {
+		checkedAt := models.NewTimestamp(now)
+		sms := checkDependency(ctx, "sms", h.notifications.TestSMSConnection)
+		sms.CheckedAt = &checkedAt
+		email := checkDependency(ctx, "email", h.notifications.TestEmailConnection)
+		email.CheckedAt = &checkedAt
+
+		h.notificationStatuses = map[string]models.DependencyStatus{"sms": sms, "email": email}
+		h.notificationsChecked = now
+	}
+
+	statuses := make(map[string]models.DependencyStatus, len(h.notificationStatuses))
+	for name, check := range h.notificationStatuses {
+		statuses[name] = check
+	}
+	return statuses
+}
+
+// checkDependency runs one readiness check with a timeout. Failures are logged, not
+// returned, so the public probe doesn't leak hosts or credentials from error messages.
+func checkDependency(ctx context.Context, name string, check func(ctx context.Context) error) models.DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	err := check(ctx)
+	switch {
+	case err == nil:
+		return models.DependencyStatus{Status: models.DependencyUp}
+	case errors.Is(err, services.ErrTransportDisabled):
+		return models.DependencyStatus{Status: models.DependencyDisabled}
+	default:
+		fmt.Printf("Readiness check failed: Dependency=%s, Error=%v, Time=%s\n", name, err, time.Now().Format(time.RFC3339))
+		return models.DependencyStatus{Status: models.DependencyDown}
+	}
+}
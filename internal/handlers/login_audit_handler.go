@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// LoginAuditHandler handles login activity HTTP requests
+type LoginAuditHandler struct {
+	loginAuditService services.LoginAuditServiceInterface
+}
+
+// NewLoginAuditHandler creates a new login audit handler
+func NewLoginAuditHandler(loginAuditService services.LoginAuditServiceInterface) *LoginAuditHandler {
+	return &LoginAuditHandler{loginAuditService: loginAuditService}
+}
+
+// ListLogins handles GET /admin/logins
+func (h *LoginAuditHandler) ListLogins(c *gin.Context) {
+	filter, err := parseLoginEventFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.loginAuditService.ListLoginEvents(c.Request.Context(), filter)
+	if err != nil {
+		respondLoginAuditError(c, err, "Failed to retrieve login events")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Login events retrieved successfully",
+		"data":       result.Data,
+		"pagination": result.Pagination,
+	})
+}
+
+// ExportLogins handles GET /admin/logins/export and returns the matching login events as CSV
+func (h *LoginAuditHandler) ExportLogins(c *gin.Context) {
+	filter, err := parseLoginEventFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	events, err := h.loginAuditService.ExportLoginEvents(c.Request.Context(), filter)
+	if err != nil {
+		respondLoginAuditError(c, err, "Failed to export login events")
+		return
+	}
+
+	filename := fmt.Sprintf("logins_%s.csv", time.Now().Format("20060102_150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"id", "source", "success", "user_id", "user_type", "email", "ip_address", "user_agent", "device_info", "reason", "created_at"})
+	for _, event := range events {
+		userID := ""
+		if event.UserID != nil {
+			userID = strconv.Itoa(*event.UserID)
+		}
+		writer.Write([]string{
+			strconv.Itoa(event.ID),
+			event.Source,
+			strconv.FormatBool(event.Success),
+			userID,
+			stringValue(event.UserType),
+			stringValue(event.Email),
+			stringValue(event.IPAddress),
+			stringValue(event.UserAgent),
+			stringValue(event.DeviceInfo),
+			stringValue(event.Reason),
+			event.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+}
+
+// ListLoginAttempts handles GET /admin/login-attempts. Failed attempts are filtered by
+// reason, email, ip, from and to; with group_by (email, ip_address or reason) the
+// top limit values and their counts are returned instead of the attempts.
+func (h *LoginAuditHandler) ListLoginAttempts(c *gin.Context) {
+	filter, err := parseLoginAttemptFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if groupBy := c.Query("group_by"); groupBy != "" {
+		limit, _ := strconv.Atoi(c.Query("limit"))
+		counts, err := h.loginAuditService.AggregateLoginAttempts(c.Request.Context(), filter, groupBy, limit)
+		if err != nil {
+			respondLoginAuditError(c, err, "Failed to aggregate login attempts")
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Login attempts aggregated successfully",
+			"data":    counts,
+		})
+		return
+	}
+
+	result, err := h.loginAuditService.ListLoginAttempts(c.Request.Context(), filter)
+	if err != nil {
+		respondLoginAuditError(c, err, "Failed to retrieve login attempts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Login attempts retrieved successfully",
+		"data":       result.Data,
+		"pagination": result.Pagination,
+	})
+}
+
+// respondLoginAuditError responds 400 to invalid filters and 500 to other failures
+func respondLoginAuditError(c *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrInvalidLoginFilter) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error":   message,
+		"details": err.Error(),
+	})
+}
+
+// parseLoginAttemptFilter reads the login attempt filters from the query string
+func parseLoginAttemptFilter(c *gin.Context) (*models.LoginAttemptFilter, error) {
+	filter := &models.LoginAttemptFilter{
+		Reason:    c.Query("reason"),
+		Email:     strings.ToLower(strings.TrimSpace(c.Query("email"))),
+		IPAddress: c.Query("ip"),
+	}
+
+	if from := c.Query("from"); from != "" {
+		value, err := parseFilterTime(from, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date: %w", err)
+		}
+		filter.From = &value
+	}
+
+	if to := c.Query("to"); to != "" {
+		value, err := parseFilterTime(to, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date: %w", err)
+		}
+		filter.To = &value
+	}
+
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	return filter, nil
+}
+
+// parseLoginEventFilter reads the login event filters from the query string
+func parseLoginEventFilter(c *gin.Context) (*models.LoginEventFilter, error) {
+	filter := &models.LoginEventFilter{
+		UserType:  c.Query("user_type"),
+		IPAddress: c.Query("ip"),
+	}
+
+	if success := c.Query("success"); success != "" {
+		value, err := strconv.ParseBool(success)
+		if err != nil {
+			return nil, fmt.Errorf("success must be true or false")
+		}
+		filter.Success = &value
+	}
+
+	if from := c.Query("from"); from != "" {
+		value, err := parseFilterTime(from, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date: %w", err)
+		}
+		filter.From = &value
+	}
+
+	if to := c.Query("to"); to != "" {
+		value, err := parseFilterTime(to, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date: %w", err)
+		}
+		filter.To = &value
+	}
+
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	return filter, nil
+}
+
+// parseFilterTime accepts RFC3339 timestamps or plain dates (YYYY-MM-DD); a plain
+// date used as an upper bound covers the whole day
+func parseFilterTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// stringValue dereferences an optional string for CSV output
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gatehide/gatehide-api/internal/models"
@@ -12,11 +14,19 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Page size limits for the notification history
+const (
+	defaultNotificationPageSize = 50
+	maxNotificationPageSize     = 100
+)
+
 // NotificationHandler handles notification-related HTTP requests
 type NotificationHandler struct {
 	notificationService services.NotificationServiceInterface
 	templateService     services.TemplateServiceInterface
 	emailService        services.EmailServiceInterface
+	smsService          services.SMSServiceInterface
+	maxBulkSMS          int
 	jwtManager          *utils.JWTManager
 }
 
@@ -35,6 +45,13 @@ func NewNotificationHandler(
 	}
 }
 
+// SetSMSService sets the SMS service used for bulk SMS and the most messages one
+// bulk request may carry
+func (h *NotificationHandler) SetSMSService(smsService services.SMSServiceInterface, maxBulkSize int) {
+	h.smsService = smsService
+	h.maxBulkSMS = maxBulkSize
+}
+
 // GetNotification handles GET /api/notifications/:id
 func (h *NotificationHandler) GetNotification(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -109,10 +126,9 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		filters["priority"] = priority
 	}
 
-	if limit := c.Query("limit"); limit != "" {
-		if limitInt, err := strconv.Atoi(limit); err == nil {
-			filters["limit"] = limitInt
-		}
+	limit, ok := parseNotificationPage(c, filters)
+	if !ok {
+		return
 	}
 
 	// Add user-specific filter for non-admin users
@@ -120,6 +136,77 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		filters["recipient"] = user.Email
 	}
 
+	h.respondWithNotificationPage(c, filters, limit)
+}
+
+// GetUserNotifications handles GET /users/:id/notifications, listing the notifications
+// sent about an account. The account type defaults to user and can be set with user_type.
+func (h *NotificationHandler) GetUserNotifications(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userType := c.DefaultQuery("user_type", "user")
+	if userType != "user" && userType != "admin" && userType != "gamenet" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid user type",
+			"details": "user_type must be one of user, admin or gamenet",
+		})
+		return
+	}
+
+	filters := map[string]interface{}{
+		"related_user_id":   id,
+		"related_user_type": userType,
+	}
+
+	if notificationType := c.Query("type"); notificationType != "" {
+		filters["type"] = notificationType
+	}
+
+	if status := c.Query("status"); status != "" {
+		filters["status"] = status
+	}
+
+	limit, ok := parseNotificationPage(c, filters)
+	if !ok {
+		return
+	}
+
+	h.respondWithNotificationPage(c, filters, limit)
+}
+
+// parseNotificationPage adds the limit and cursor query parameters to the filters and
+// returns the page size. It writes a 400 response and returns false on a bad cursor.
+func parseNotificationPage(c *gin.Context, filters map[string]interface{}) (int, bool) {
+	limit := defaultNotificationPageSize
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limitInt, err := strconv.Atoi(limitStr); err == nil && limitInt > 0 {
+			limit = limitInt
+		}
+	}
+	if limit > maxNotificationPageSize {
+		limit = maxNotificationPageSize
+	}
+	filters["limit"] = limit
+
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cursor, err := models.DecodeNotificationCursor(cursorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return 0, false
+		}
+		filters["cursor"] = cursor
+	}
+
+	return limit, true
+}
+
+// respondWithNotificationPage queries one page of notifications and writes it together
+// with the cursor for the next page
+func (h *NotificationHandler) respondWithNotificationPage(c *gin.Context, filters map[string]interface{}, limit int) {
 	notifications, err := h.notificationService.GetNotifications(c.Request.Context(), filters)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -132,7 +219,199 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 		responses = append(responses, notification.ToResponse())
 	}
 
-	c.JSON(http.StatusOK, gin.H{"notifications": responses})
+	// A full page means there may be more; point the client past the last row
+	var nextCursor *string
+	if len(notifications) == limit {
+		encoded := models.NewNotificationCursor(notifications[len(notifications)-1]).Encode()
+		nextCursor = &encoded
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": responses, "next_cursor": nextCursor})
+}
+
+// GetRateLimitStats handles GET /admin/notifications/rate-limits
+func (h *NotificationHandler) GetRateLimitStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Notification rate limits retrieved successfully",
+		"data":    h.notificationService.RateLimitStats(),
+	})
+}
+
+// PreviewTemplate handles POST /admin/notifications/templates/preview
+func (h *NotificationHandler) PreviewTemplate(c *gin.Context) {
+	var req models.TemplatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if req.Type == "" {
+		req.Type = models.NotificationTypeSMS
+	}
+
+	template, err := h.templateService.GetTemplateByName(c.Request.Context(), req.Name, req.Type)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Template not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	subject, content, err := h.templateService.RenderTemplate(c.Request.Context(), template, req.Data)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Failed to render template",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	missing := []string{}
+	for _, variable := range template.Variables {
+		if _, ok := req.Data[variable]; !ok {
+			missing = append(missing, variable)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Template rendered successfully",
+		"data": models.TemplatePreviewResponse{
+			Name:             template.Name,
+			Type:             template.Type,
+			Subject:          subject,
+			Content:          content,
+			MissingVariables: missing,
+		},
+	})
+}
+
+// SendBulkSMS handles POST /notifications/sms/bulk. Messages to invalid numbers are
+// reported as failed without being sent; the rest are sent in one batch.
+func (h *NotificationHandler) SendBulkSMS(c *gin.Context) {
+	var req models.BulkSMSRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if len(req.Messages) > 0 && (req.Message != "" || len(req.Recipients) > 0) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Send either messages or a message with recipients, not both",
+		})
+		return
+	}
+	if len(req.Messages) == 0 && (strings.TrimSpace(req.Message) == "" || len(req.Recipients) == 0) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Messages, or a message with recipients, are required",
+		})
+		return
+	}
+
+	messages := req.SMSNotifications()
+	if len(messages) > h.maxBulkSMS {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Too many messages",
+			"details": fmt.Sprintf("a bulk SMS request may carry at most %d messages", h.maxBulkSMS),
+		})
+		return
+	}
+
+	if h.smsService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "SMS service not configured",
+		})
+		return
+	}
+
+	// Only valid numbers are sent; positions maps each sent message back to its result
+	results := make([]models.BulkSMSResult, len(messages))
+	var valid []*models.SMSNotification
+	var positions []int
+	for i, message := range messages {
+		results[i].To = message.To
+		if !h.smsService.ValidatePhoneNumber(message.To) {
+			results[i].Error = fmt.Sprintf("invalid phone number: %s", message.To)
+			continue
+		}
+		valid = append(valid, message)
+		positions = append(positions, i)
+	}
+
+	if len(valid) > 0 {
+		sent, err := h.smsService.SendBulkSMSWithResults(c.Request.Context(), valid)
+		if err != nil && sent == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "Failed to send SMS messages",
+				"details": err.Error(),
+			})
+			return
+		}
+		for i, result := range sent {
+			results[positions[i]] = result
+		}
+	}
+
+	response := models.BulkSMSResponse{Results: results}
+	for _, result := range results {
+		if result.Error != "" {
+			response.Failed++
+		} else {
+			response.Sent++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Bulk SMS processed",
+		"data":    response,
+	})
+}
+
+// GetSMSDeliveryStatus handles GET /notifications/sms/:id/status, where id is the
+// provider message ID of a sent SMS
+func (h *NotificationHandler) GetSMSDeliveryStatus(c *gin.Context) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || messageID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	if h.smsService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "SMS service not configured",
+		})
+		return
+	}
+
+	status, err := h.smsService.GetDeliveryStatus(c.Request.Context(), messageID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSMSMessageNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "SMS message not found"})
+		case errors.Is(err, services.ErrSMSDisabled), errors.Is(err, services.ErrSMSNotConfigured), errors.Is(err, services.ErrSMSCircuitOpen):
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error":   "SMS service unavailable",
+				"details": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to get SMS delivery status",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "SMS delivery status retrieved successfully",
+		"data":    status,
+	})
 }
 
 // getUserFromToken extracts user information from JWT token
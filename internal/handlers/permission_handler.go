@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionHandler handles role and permission management HTTP requests
+type PermissionHandler struct {
+	permissionService services.PermissionServiceInterface
+}
+
+// NewPermissionHandler creates a new permission handler
+func NewPermissionHandler(permissionService services.PermissionServiceInterface) *PermissionHandler {
+	return &PermissionHandler{permissionService: permissionService}
+}
+
+// ListPermissions handles GET /permissions
+func (h *PermissionHandler) ListPermissions(c *gin.Context) {
+	permissions, err := h.permissionService.GetAllPermissions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve permissions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Permissions retrieved successfully",
+		"data":    permissions,
+	})
+}
+
+// GetUserRoles handles GET /users/:id/roles
+func (h *PermissionHandler) GetUserRoles(c *gin.Context) {
+	userID, ok := parseRoleUserID(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.permissionService.GetUserRoles(userID, models.RoleUser)
+	if err != nil {
+		respondRoleError(c, err, "Failed to retrieve user roles")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User roles retrieved successfully",
+		"data":    roles,
+	})
+}
+
+// GetUserPermissions handles GET /users/:id/permissions. It lists the user's roles and
+// the union of their permissions.
+func (h *PermissionHandler) GetUserPermissions(c *gin.Context) {
+	userID, ok := parseRoleUserID(c)
+	if !ok {
+		return
+	}
+
+	roles, err := h.permissionService.GetUserRoles(userID, models.RoleUser)
+	if err != nil {
+		respondRoleError(c, err, "Failed to retrieve user permissions")
+		return
+	}
+
+	permissions, err := h.permissionService.GetUserPermissionsByID(userID, models.RoleUser)
+	if err != nil {
+		respondRoleError(c, err, "Failed to retrieve user permissions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User permissions retrieved successfully",
+		"data": models.UserPermissionsResponse{
+			Roles:       roles,
+			Permissions: permissions,
+		},
+	})
+}
+
+// AssignUserRole handles POST /users/:id/roles
+func (h *PermissionHandler) AssignUserRole(c *gin.Context) {
+	userID, ok := parseRoleUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	actor := middlewares.GetCurrentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if err := h.permissionService.AssignRole(userID, models.RoleUser, req.Role, actor); err != nil {
+		respondRoleError(c, err, "Failed to assign role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role assigned successfully",
+	})
+}
+
+// RemoveUserRole handles DELETE /users/:id/roles/:role
+func (h *PermissionHandler) RemoveUserRole(c *gin.Context) {
+	userID, ok := parseRoleUserID(c)
+	if !ok {
+		return
+	}
+
+	actor := middlewares.GetCurrentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if err := h.permissionService.RemoveRole(userID, models.RoleUser, c.Param("role"), actor); err != nil {
+		respondRoleError(c, err, "Failed to remove role")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role removed successfully",
+	})
+}
+
+// parseRoleUserID reads the user ID path parameter, responding with 400 when it is invalid
+func parseRoleUserID(c *gin.Context) (int, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return 0, false
+	}
+	return userID, true
+}
+
+// respondRoleError maps role management errors to HTTP responses
+func respondRoleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not found",
+			"details": err.Error(),
+		})
+	case errors.Is(err, services.ErrRoleNotAssignable):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrRoleAlreadyAssigned):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
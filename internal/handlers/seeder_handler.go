@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gatehide/gatehide-api/database/seeders"
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SeederHandler handles admin requests to re-run seeders
+type SeederHandler struct {
+	seederService services.SeederServiceInterface
+}
+
+// NewSeederHandler creates a new seeder handler
+func NewSeederHandler(seederService services.SeederServiceInterface) *SeederHandler {
+	return &SeederHandler{seederService: seederService}
+}
+
+// ListSeeders handles GET /admin/seeders
+func (h *SeederHandler) ListSeeders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Seeders retrieved successfully",
+		"data":    h.seederService.ListSeeders(c.Request.Context()),
+	})
+}
+
+// RunSeeder handles POST /admin/seeders/:name/run
+func (h *SeederHandler) RunSeeder(c *gin.Context) {
+	actor := middlewares.GetCurrentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	result, err := h.seederService.RunSeeder(c.Request.Context(), c.Param("name"), actor)
+	if err != nil {
+		if errors.Is(err, seeders.ErrSeederNotAllowed) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Seeder not found",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to run seeder",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Seeder completed successfully",
+		"data":    result,
+	})
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -67,6 +69,73 @@ func (h *SessionHandler) GetActiveSessions(c *gin.Context) {
 	})
 }
 
+// GetSession retrieves a single session's details
+// @Summary Get session details
+// @Description Get the details of a session owned by the current user (admins may view any session)
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param session_id path int true "Session ID"
+// @Success 200 {object} map[string]interface{} "Session retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Session not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /sessions/{session_id} [get]
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	// Get current user from context
+	claims, exists := middlewares.GetCurrentUser(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not found in context",
+		})
+		return
+	}
+
+	// Get session ID from URL parameter
+	sessionID, err := parseSessionID(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid session ID",
+		})
+		return
+	}
+
+	// The current token is only used to flag the session as current
+	currentToken, _ := middlewares.ExtractTokenFromHeader(c)
+
+	session, err := h.sessionService.GetSession(sessionID, currentToken)
+	if err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Session not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get session",
+		})
+		return
+	}
+
+	// Owners can always see their sessions and admins can see any. users:read is not
+	// enough: gamenets hold it for their own users, and a session carries the IP and
+	// device of its account. Other callers get the same 404 as a missing session.
+	isOwner := session.UserID == claims.UserID && session.UserType == claims.UserType
+	if !isOwner && claims.UserType != "admin" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Session not found",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session retrieved successfully",
+		"session": session,
+	})
+}
+
 // LogoutSession deactivates a specific session
 // @Summary Logout a session
 // @Description Deactivate a specific session by ID
@@ -199,6 +268,77 @@ func (h *SessionHandler) LogoutAllSessions(c *gin.Context) {
 	})
 }
 
+// TerminateAccountSessions returns a handler that deactivates all sessions of the account
+// identified by the :id parameter, for the given account type ("user", "admin" or "gamenet")
+// @Summary Terminate all sessions of an account
+// @Description Deactivate all sessions of a (possibly compromised) account and audit-log the action
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Account ID"
+// @Success 200 {object} map[string]interface{} "Sessions terminated successfully"
+// @Failure 400 {object} map[string]interface{} "Bad request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Account not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /users/{id}/terminate-sessions [post]
+func (h *SessionHandler) TerminateAccountSessions(accountType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get acting user from context
+		claims, exists := middlewares.GetCurrentUser(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "User not found in context",
+			})
+			return
+		}
+
+		accountID, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid account ID",
+			})
+			return
+		}
+
+		var req struct {
+			Reason string `json:"reason" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request data",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		count, err := h.sessionService.TerminateAccountSessions(accountID, accountType, claims.UserID, claims.UserType, req.Reason)
+		if err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{
+					"error": "Account not found",
+				})
+				return
+			}
+
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to terminate sessions",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Sessions terminated successfully",
+			"data": gin.H{
+				"account_id":          accountID,
+				"account_type":        accountType,
+				"terminated_sessions": count,
+			},
+		})
+	}
+}
+
 // parseSessionID parses session ID from string to int
 func parseSessionID(sessionIDStr string) (int, error) {
 	return strconv.Atoi(sessionIDStr)
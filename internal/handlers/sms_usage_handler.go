@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SMSUsageHandler handles SMS usage statistics HTTP requests
+type SMSUsageHandler struct {
+	smsUsageService services.SMSUsageServiceInterface
+}
+
+// NewSMSUsageHandler creates a new SMS usage handler
+func NewSMSUsageHandler(smsUsageService services.SMSUsageServiceInterface) *SMSUsageHandler {
+	return &SMSUsageHandler{smsUsageService: smsUsageService}
+}
+
+// GetSMSStats handles GET /integrations/sms/stats
+func (h *SMSUsageHandler) GetSMSStats(c *gin.Context) {
+	filter, err := parseSMSUsageFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter parameters",
+			"details": "from date must be before to date",
+		})
+		return
+	}
+
+	stats, err := h.smsUsageService.GetUsageStats(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve SMS statistics",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "SMS statistics retrieved successfully",
+		"data":    stats,
+	})
+}
+
+// parseSMSUsageFilter reads the SMS usage filters from the query string
+func parseSMSUsageFilter(c *gin.Context) (*models.SMSUsageFilter, error) {
+	filter := &models.SMSUsageFilter{
+		MessageType: c.Query("type"),
+	}
+
+	if from := c.Query("from"); from != "" {
+		value, err := parseFilterTime(from, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date: %w", err)
+		}
+		filter.From = &value
+	}
+
+	if to := c.Query("to"); to != "" {
+		value, err := parseFilterTime(to, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date: %w", err)
+		}
+		filter.To = &value
+	}
+
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	return filter, nil
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler handles dashboard statistics HTTP requests
+type StatsHandler struct {
+	statsService services.StatsServiceInterface
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(statsService services.StatsServiceInterface) *StatsHandler {
+	return &StatsHandler{statsService: statsService}
+}
+
+// GetAdminOverview handles GET /admin/stats/overview
+func (h *StatsHandler) GetAdminOverview(c *gin.Context) {
+	overview, err := h.statsService.GetAdminOverview(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve statistics",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Statistics retrieved successfully",
+		"data":    overview,
+	})
+}
+
+// GetGamenetStats handles GET /gamenets/:id/stats. A gamenet caller only ever sees its
+// own statistics: the gamenet comes from the caller's claims, and asking for another
+// gamenet's ID is refused. Admins may read any gamenet's statistics.
+func (h *StatsHandler) GetGamenetStats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gamenet ID",
+		})
+		return
+	}
+
+	userType, _ := c.Get("user_type")
+	userID, _ := c.Get("user_id")
+
+	if userType == "gamenet" {
+		if gamenetID, ok := userID.(int); !ok || gamenetID != id {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "You can only view your own gamenet's statistics",
+			})
+			return
+		}
+	} else if userType != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Access denied",
+		})
+		return
+	}
+
+	stats, err := h.statsService.GetGamenetStats(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Gamenet not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve statistics",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Statistics retrieved successfully",
+		"data":    stats,
+	})
+}
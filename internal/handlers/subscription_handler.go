@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SubscriptionHandler handles gamenet subscription HTTP requests
+type SubscriptionHandler struct {
+	service services.SubscriptionServiceInterface
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(service services.SubscriptionServiceInterface) *SubscriptionHandler {
+	return &SubscriptionHandler{service: service}
+}
+
+// Subscribe handles requests to subscribe a gamenet to a plan
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	gamenetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gamenet ID",
+		})
+		return
+	}
+
+	var req struct {
+		PlanID int `json:"plan_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.service.Subscribe(gamenetID, req.PlanID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Gamenet or plan not found",
+				"details": err.Error(),
+			})
+		case errors.Is(err, services.ErrActiveSubscriptionExists):
+			c.JSON(http.StatusConflict, gin.H{
+				"error": err.Error(),
+			})
+		case errors.Is(err, services.ErrPlanNotAvailable):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to subscribe",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Subscribed successfully",
+		"data":    subscription,
+	})
+}
+
+// GetActiveSubscription handles requests for a gamenet's current subscription
+func (h *SubscriptionHandler) GetActiveSubscription(c *gin.Context) {
+	gamenetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gamenet ID",
+		})
+		return
+	}
+
+	subscription, err := h.service.GetActiveSubscription(gamenetID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Gamenet has no active subscription",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get subscription",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": subscription,
+	})
+}
+
+// CancelSubscription handles requests to cancel a gamenet's current subscription
+func (h *SubscriptionHandler) CancelSubscription(c *gin.Context) {
+	gamenetID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid gamenet ID",
+		})
+		return
+	}
+
+	subscription, err := h.service.GetActiveSubscription(gamenetID)
+	if err == nil {
+		err = h.service.Cancel(subscription.ID)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound), errors.Is(err, services.ErrSubscriptionNotActive):
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Gamenet has no active subscription",
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to cancel subscription",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Subscription cancelled successfully",
+	})
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"strings"
 
+	"github.com/gatehide/gatehide-api/internal/middlewares"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -27,12 +29,21 @@ func (h *SubscriptionPlanHandler) CreatePlan(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request data",
 			"details": err.Error(),
+			"fields":  bindingFieldErrors(err, &req),
 		})
 		return
 	}
 
 	plan, err := h.service.CreatePlan(&req)
 	if err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "A plan with this name already exists",
+				"details": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create plan",
 			"details": err.Error(),
@@ -59,8 +70,15 @@ func (h *SubscriptionPlanHandler) GetPlan(c *gin.Context) {
 
 	plan, err := h.service.GetPlan(id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error":   "Plan not found",
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Plan not found",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get plan",
 			"details": err.Error(),
 		})
 		return
@@ -131,12 +149,21 @@ func (h *SubscriptionPlanHandler) UpdatePlan(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Invalid request data",
 			"details": err.Error(),
+			"fields":  bindingFieldErrors(err, &req),
 		})
 		return
 	}
 
-	plan, err := h.service.UpdatePlan(id, &req)
+	plan, err := h.service.UpdatePlan(id, &req, middlewares.GetCurrentActor(c))
 	if err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "A plan with this name already exists",
+				"details": err.Error(),
+			})
+			return
+		}
+
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to update plan",
 			"details": err.Error(),
@@ -150,6 +177,165 @@ func (h *SubscriptionPlanHandler) UpdatePlan(c *gin.Context) {
 	})
 }
 
+// ActivatePlan handles requests to make a plan available for new subscriptions
+func (h *SubscriptionPlanHandler) ActivatePlan(c *gin.Context) {
+	h.setPlanActive(c, true)
+}
+
+// DeactivatePlan handles requests to withdraw a plan from new subscriptions
+func (h *SubscriptionPlanHandler) DeactivatePlan(c *gin.Context) {
+	h.setPlanActive(c, false)
+}
+
+// setPlanActive flips a plan's is_active flag and responds with the updated plan
+func (h *SubscriptionPlanHandler) setPlanActive(c *gin.Context, active bool) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid plan ID",
+		})
+		return
+	}
+
+	action, message := "activate", "Plan activated successfully"
+	if !active {
+		action, message = "deactivate", "Plan deactivated successfully"
+	}
+
+	plan, err := h.service.SetActive(id, active, middlewares.GetCurrentActor(c))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Plan not found",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to " + action + " plan",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    plan,
+	})
+}
+
+// GetPlanHistory handles requests for a plan's change log
+func (h *SubscriptionPlanHandler) GetPlanHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid plan ID",
+		})
+		return
+	}
+
+	history, err := h.service.GetPlanHistory(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Plan not found",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve plan history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Plan history retrieved successfully",
+		"data":    history,
+	})
+}
+
+// GetDeleteImpact handles requests to preview the effect of deleting a plan
+func (h *SubscriptionPlanHandler) GetDeleteImpact(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid plan ID",
+		})
+		return
+	}
+
+	impact, err := h.service.GetDeleteImpact(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Plan not found",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve plan delete impact",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Plan delete impact retrieved successfully",
+		"data":    impact,
+	})
+}
+
+// ClonePlan handles requests to duplicate a plan
+func (h *SubscriptionPlanHandler) ClonePlan(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid plan ID",
+		})
+		return
+	}
+
+	plan, err := h.service.Clone(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   "A plan with this name already exists",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Plan not found",
+				"details": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to clone plan",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Plan cloned successfully",
+		"data":    plan,
+	})
+}
+
 // DeletePlan handles plan deletion requests
 func (h *SubscriptionPlanHandler) DeletePlan(c *gin.Context) {
 	idStr := c.Param("id")
@@ -173,7 +359,7 @@ func (h *SubscriptionPlanHandler) DeletePlan(c *gin.Context) {
 		}
 
 		// Check if it's a not found error
-		if err.Error() == "subscription plan not found" || strings.Contains(err.Error(), "subscription plan not found") {
+		if errors.Is(err, repositories.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error":   "Plan not found",
 				"details": err.Error(),
@@ -1,18 +1,28 @@
 package handlers
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/middlewares"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 // UserHandler handles user HTTP requests
 type UserHandler struct {
-	userService services.UserServiceInterface
+	userService  services.UserServiceInterface
+	config       *config.Config
+	fileUploader *utils.FileUploader
 }
 
 // NewUserHandler creates a new user handler
@@ -22,6 +32,17 @@ func NewUserHandler(userService services.UserServiceInterface) *UserHandler {
 	}
 }
 
+// SetConfig sets the configuration used to guard destructive operations in production.
+// Without one, destructive operations are never guarded.
+func (h *UserHandler) SetConfig(cfg *config.Config) {
+	h.config = cfg
+}
+
+// SetFileUploader sets the uploader used to read CSV imports. Without one, imports are rejected.
+func (h *UserHandler) SetFileUploader(fileUploader *utils.FileUploader) {
+	h.fileUploader = fileUploader
+}
+
 // GetAllUsers handles GET /users
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	// Check if search parameters are provided
@@ -64,6 +85,13 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 			Page:     page,
 			PageSize: pageSize,
 		}
+		if err := parseUserSearchFilters(c, searchReq); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid filter",
+				"details": err.Error(),
+			})
+			return
+		}
 
 		var result *models.UserSearchResponse
 		if gamenetID != nil {
@@ -114,6 +142,48 @@ func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	})
 }
 
+// parseUserSearchFilters reads the optional balance, debt and last login filters from
+// the query string into req
+func parseUserSearchFilters(c *gin.Context, req *models.UserSearchRequest) error {
+	amounts := []struct {
+		param  string
+		target **float64
+	}{
+		{"min_balance", &req.MinBalance},
+		{"max_balance", &req.MaxBalance},
+		{"min_debt", &req.MinDebt},
+	}
+	for _, amount := range amounts {
+		value := c.Query(amount.param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number", amount.param)
+		}
+		*amount.target = &parsed
+	}
+
+	if hasDebt := c.Query("has_debt"); hasDebt != "" {
+		value, err := strconv.ParseBool(hasDebt)
+		if err != nil {
+			return fmt.Errorf("has_debt must be true or false")
+		}
+		req.HasDebt = &value
+	}
+
+	if before := c.Query("last_login_before"); before != "" {
+		value, err := parseFilterTime(before, false)
+		if err != nil {
+			return fmt.Errorf("invalid last_login_before date: %w", err)
+		}
+		req.LastLoginBefore = &value
+	}
+
+	return nil
+}
+
 // GetUserByID handles GET /users/:id
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -127,8 +197,15 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 	user, err := h.userService.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "User not found",
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "User not found",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to retrieve user",
+			"details": err.Error(),
 		})
 		return
 	}
@@ -162,7 +239,11 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 
 	user, err := h.userService.Create(c.Request.Context(), &req, gamenetID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrIdentifierBelongsToDeletedUser) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -174,6 +255,172 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	})
 }
 
+// CreateUsersBatch handles POST /users/batch. The body is an array of users to
+// create; either all of them are created or, when any row fails, none are and the
+// per-row results explain why.
+func (h *UserHandler) CreateUsersBatch(c *gin.Context) {
+	var rows []models.UserCreateRequest
+	if err := c.ShouldBindJSON(&rows); err != nil && !isSliceValidationError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Gin's slice validation drops the valid rows from its error, so validate
+	// each row on its own to report which rows were invalid
+	response := &models.UserBatchCreateResponse{Results: make([]models.UserBatchCreateResult, len(rows))}
+	invalid := false
+	for i := range rows {
+		response.Results[i].Index = i
+		if err := binding.Validator.ValidateStruct(&rows[i]); err != nil {
+			response.Results[i].Error = err.Error()
+			invalid = true
+		}
+	}
+	if invalid {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": services.ErrBatchCreateFailed.Error(),
+			"data":  response.MarkRolledBack(),
+		})
+		return
+	}
+
+	// Check if user is a gamenet
+	userType, _ := c.Get("user_type")
+	userID, _ := c.Get("user_id")
+
+	var gamenetID *int
+	if userType == "gamenet" {
+		if id, ok := userID.(int); ok {
+			gamenetID = &id
+		}
+	}
+
+	reqs := make([]*models.UserCreateRequest, len(rows))
+	for i := range rows {
+		reqs[i] = &rows[i]
+	}
+
+	result, err := h.userService.CreateBatch(c.Request.Context(), reqs, gamenetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrBatchCreateFailed):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+				"data":  result,
+			})
+		case errors.Is(err, services.ErrInvalidBatchSize):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to create users",
+				"details": err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Users created successfully",
+		"data":    result,
+	})
+}
+
+// isSliceValidationError reports whether err only failed field validation of a slice element
+func isSliceValidationError(err error) bool {
+	var rowErrs binding.SliceValidationError
+	return errors.As(err, &rowErrs)
+}
+
+// ImportUsers handles POST /users/import. The multipart "file" field holds a CSV with
+// name, mobile and email columns; the response summarizes which lines were created,
+// skipped or failed.
+func (h *UserHandler) ImportUsers(c *gin.Context) {
+	if h.fileUploader == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "User import is not available"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No CSV file provided"})
+		return
+	}
+
+	src, err := h.fileUploader.OpenFile(file, ".csv")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid file",
+			"details": err.Error(),
+		})
+		return
+	}
+	defer src.Close()
+
+	// Check if user is a gamenet
+	userType, _ := c.Get("user_type")
+	userID, _ := c.Get("user_id")
+
+	var gamenetID *int
+	if userType == "gamenet" {
+		if id, ok := userID.(int); ok {
+			gamenetID = &id
+		}
+	}
+
+	result, err := h.userService.ImportCSV(c.Request.Context(), src, gamenetID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidImportFile) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid file",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to import users",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Users imported",
+		"data":    result,
+	})
+}
+
+// ExportUsers handles GET /users/export, returning the users as a CSV download.
+// Gamenets only export their own users.
+func (h *UserHandler) ExportUsers(c *gin.Context) {
+	// Check if user is a gamenet
+	userType, _ := c.Get("user_type")
+	userID, _ := c.Get("user_id")
+
+	var gamenetID *int
+	if userType == "gamenet" {
+		if id, ok := userID.(int); ok {
+			gamenetID = &id
+		}
+	}
+
+	// Build the file first so a failure can still be reported as JSON
+	var body bytes.Buffer
+	if err := h.userService.ExportCSV(c.Request.Context(), &body, gamenetID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to export users",
+		})
+		return
+	}
+
+	fileName := fmt.Sprintf("users-%s.csv", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", body.Bytes())
+}
+
 // UpdateUser handles PUT /users/:id
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
@@ -225,7 +472,11 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 
 	user, err := h.userService.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrIdentifierBelongsToDeletedUser) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -270,6 +521,73 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	})
 }
 
+// RestoreUser handles POST /users/:id/restore (Admin only), bringing back a deleted user
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	user, err := h.userService.Restore(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case errors.Is(err, services.ErrUserNotDeleted):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore user"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User restored successfully",
+		"data":    user,
+	})
+}
+
+// BulkAction handles POST /users/bulk-action
+func (h *UserHandler) BulkAction(c *gin.Context) {
+	var req models.UserBulkActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request data",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	actor := middlewares.GetCurrentActor(c)
+	if actor == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "User not authenticated",
+		})
+		return
+	}
+
+	if req.Action == "delete" && !middlewares.ConfirmDestructive(c, h.config, "bulk user delete") {
+		return
+	}
+
+	result, err := h.userService.BulkAction(c.Request.Context(), &req, actor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Bulk action processed",
+		"data":    result,
+	})
+}
+
 // ResendCredentials handles POST /users/:id/resend-credentials
 func (h *UserHandler) ResendCredentials(c *gin.Context) {
 	idStr := c.Param("id")
@@ -333,6 +651,58 @@ func (h *UserHandler) SearchUserByIdentifier(c *gin.Context) {
 	})
 }
 
+// defaultRecentlyActiveWindow is how far back the recently-active listing looks when no since is given
+const defaultRecentlyActiveWindow = 15 * time.Minute
+
+// GetRecentlyActiveUsers handles GET /users/recently-active?since=&limit=
+func (h *UserHandler) GetRecentlyActiveUsers(c *gin.Context) {
+	since := time.Now().Add(-defaultRecentlyActiveWindow)
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid since parameter",
+				"details": "since must be an RFC3339 timestamp",
+			})
+			return
+		}
+		if parsed.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid since parameter",
+				"details": "since cannot be in the future",
+			})
+			return
+		}
+		since = parsed
+	}
+
+	limit := services.DefaultRecentlyActiveLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid limit parameter",
+				"details": "limit must be a positive integer",
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	users, err := h.userService.GetRecentlyActive(c.Request.Context(), since, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve recently active users",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Recently active users retrieved successfully",
+		"data":    users,
+	})
+}
+
 // AttachUserToGamenet handles POST /users/:id/attach
 func (h *UserHandler) AttachUserToGamenet(c *gin.Context) {
 	idStr := c.Param("id")
@@ -365,9 +735,7 @@ func (h *UserHandler) AttachUserToGamenet(c *gin.Context) {
 
 	err = h.userService.AttachToGamenet(c.Request.Context(), id, gamenetID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondGamenetLinkError(c, err, "Failed to attach user to gamenet")
 		return
 	}
 
@@ -408,9 +776,7 @@ func (h *UserHandler) DetachUserFromGamenet(c *gin.Context) {
 
 	err = h.userService.DetachFromGamenet(c.Request.Context(), id, gamenetID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		respondGamenetLinkError(c, err, "Failed to detach user from gamenet")
 		return
 	}
 
@@ -418,3 +784,26 @@ func (h *UserHandler) DetachUserFromGamenet(c *gin.Context) {
 		"message": "User detached from gamenet successfully",
 	})
 }
+
+// respondGamenetLinkError writes the response for a failed attach or detach
+func respondGamenetLinkError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "User not found",
+		})
+	case errors.Is(err, services.ErrUserAlreadyLinked):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrUserNotLinked):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   message,
+			"details": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a single invalid request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// bindingFieldErrors converts a request binding error into per-field errors, using
+// the JSON field names of obj (the struct the request was bound into)
+func bindingFieldErrors(err error, obj interface{}) []FieldError {
+	fields := []FieldError{}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, fe := range validationErrors {
+			name := jsonFieldName(obj, fe.StructField())
+			fields = append(fields, FieldError{
+				Field:   name,
+				Message: validationMessage(name, fe),
+			})
+		}
+		return fields
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		fields = append(fields, FieldError{
+			Field:   typeError.Field,
+			Message: fmt.Sprintf("%s must be a %s", typeError.Field, jsonTypeName(typeError.Type)),
+		})
+	}
+
+	return fields
+}
+
+// validationMessage maps a validator tag to a human readable message
+func validationMessage(field string, fe validator.FieldError) string {
+	isNumber := false
+	switch fe.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		isNumber = true
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", field)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", field, strings.ReplaceAll(fe.Param(), " ", "/"))
+	case "min":
+		if isNumber {
+			return fmt.Sprintf("%s must be at least %s", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s characters", field, fe.Param())
+	case "max":
+		if isNumber {
+			return fmt.Sprintf("%s must be at most %s", field, fe.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s characters", field, fe.Param())
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s characters", field, fe.Param())
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	default:
+		return fmt.Sprintf("%s is invalid", field)
+	}
+}
+
+// jsonFieldName returns the JSON name of a struct field, falling back to the Go name
+func jsonFieldName(obj interface{}, structField string) string {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return structField
+	}
+
+	field, ok := t.FieldByName(structField)
+	if !ok {
+		return structField
+	}
+
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return structField
+	}
+	return name
+}
+
+// jsonTypeName describes a Go type in JSON terms
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	default:
+		return t.String()
+	}
+}
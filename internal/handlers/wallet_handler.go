@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// WalletHandler handles wallet HTTP requests
+type WalletHandler struct {
+	walletService     services.WalletServiceInterface
+	permissionService services.PermissionServiceInterface
+}
+
+// NewWalletHandler creates a new wallet handler
+func NewWalletHandler(walletService services.WalletServiceInterface, permissionService services.PermissionServiceInterface) *WalletHandler {
+	return &WalletHandler{
+		walletService:     walletService,
+		permissionService: permissionService,
+	}
+}
+
+// ListTransactions handles GET /users/:id/wallet/transactions. Users only see their
+// own wallet and gamenets only the wallets of their users.
+func (h *WalletHandler) ListTransactions(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid user ID",
+		})
+		return
+	}
+
+	if !h.canViewWallet(c, userID) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Access denied: insufficient ownership",
+		})
+		return
+	}
+
+	filter, err := parseWalletTransactionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid filter parameters",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.walletService.ListTransactions(c.Request.Context(), userID, filter)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidWalletFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid filter parameters",
+				"details": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to retrieve wallet transactions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Wallet transactions retrieved successfully",
+		"data":       result.Data,
+		"pagination": result.Pagination,
+	})
+}
+
+// canViewWallet reports whether the authenticated account may see the user's wallet
+func (h *WalletHandler) canViewWallet(c *gin.Context, userID int) bool {
+	requesterType, _ := c.Get("user_type")
+	requesterID, _ := c.Get("user_id")
+	requesterTypeStr, _ := requesterType.(string)
+	requesterIDInt, _ := requesterID.(int)
+
+	if requesterTypeStr == models.RoleUser {
+		return requesterIDInt == userID
+	}
+
+	canAccess, err := h.permissionService.CanAccessResource(requesterTypeStr, "users", userID, requesterIDInt)
+	return err == nil && canAccess
+}
+
+// parseWalletTransactionFilter reads the wallet transaction filters from the query string
+func parseWalletTransactionFilter(c *gin.Context) (*models.WalletTransactionFilter, error) {
+	filter := &models.WalletTransactionFilter{
+		Type: models.WalletTransactionType(c.Query("type")),
+	}
+
+	if from := c.Query("from"); from != "" {
+		value, err := parseFilterTime(from, false)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date: %w", err)
+		}
+		filter.From = &value
+	}
+
+	if to := c.Query("to"); to != "" {
+		value, err := parseFilterTime(to, true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date: %w", err)
+		}
+		filter.To = &value
+	}
+
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	return filter, nil
+}
@@ -1,15 +1,48 @@
 package middlewares
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/gatehide/gatehide-api/internal/models"
 	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gatehide/gatehide-api/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
+// Error codes returned with a 401 so clients can tell an expired token, which they
+// should refresh, from one they must discard by logging in again
+const (
+	TokenErrorExpired = "token_expired"
+	TokenErrorInvalid = "token_invalid"
+	TokenErrorRevoked = "token_revoked"
+	// TokenErrorRefreshExpired is returned by the refresh endpoint for a token that
+	// expired too long ago to be refreshed
+	TokenErrorRefreshExpired = "refresh_expired"
+)
+
+// tokenErrorResponse builds the 401 body for a token rejected by ValidateToken
+func tokenErrorResponse(err error) gin.H {
+	if errors.Is(err, utils.ErrTokenExpired) {
+		return gin.H{
+			"error": "Token has expired",
+			"code":  TokenErrorExpired,
+		}
+	}
+	if errors.Is(err, utils.ErrTokenRevoked) {
+		return gin.H{
+			"error": "Token has been revoked",
+			"code":  TokenErrorRevoked,
+		}
+	}
+	return gin.H{
+		"error": "Invalid token",
+		"code":  TokenErrorInvalid,
+	}
+}
+
 // AuthMiddleware validates JWT tokens and sets user information in context
 func AuthMiddleware(authService services.AuthServiceInterface) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -31,9 +64,7 @@ func AuthMiddleware(authService services.AuthServiceInterface) gin.HandlerFunc {
 		// Validate token
 		claims, err := authService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid or expired token",
-			})
+			c.JSON(http.StatusUnauthorized, tokenErrorResponse(err))
 			c.Abort()
 			return
 		}
@@ -67,21 +98,19 @@ func AuthMiddlewareWithSession(authService services.AuthServiceInterface, sessio
 			tokenString = authHeader[7:]
 		}
 
-		// Validate token and session
-		session, err := sessionService.ValidateAndUpdateSession(tokenString)
+		// Validate the token before the session, so an expired, malformed or revoked
+		// token is answered with its error code rather than as a missing session
+		claims, err := authService.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid or expired session",
-			})
+			c.JSON(http.StatusUnauthorized, tokenErrorResponse(err))
 			c.Abort()
 			return
 		}
 
-		// Also validate the JWT token to get claims for compatibility
-		claims, err := authService.ValidateToken(tokenString)
+		session, err := sessionService.ValidateAndUpdateSession(tokenString)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Invalid token",
+				"error": "Invalid or expired session",
 			})
 			c.Abort()
 			return
@@ -225,6 +254,47 @@ func RequireAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
+// ReauthTokenHeader carries the token issued by POST /auth/verify-password
+const ReauthTokenHeader = "X-Reauth-Token"
+
+// RequireReauth ensures the request carries a valid re-authentication token issued to the
+// authenticated account, i.e. the password was confirmed recently. Must run after an
+// authentication middleware.
+func RequireReauth(authService services.AuthServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, exists := GetCurrentUser(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "Authentication required",
+			})
+			c.Abort()
+			return
+		}
+
+		tokenString := c.GetHeader(ReauthTokenHeader)
+		if tokenString == "" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Re-authentication required",
+				"code":  "reauth_required",
+			})
+			c.Abort()
+			return
+		}
+
+		reauth, err := authService.ValidateReauthToken(tokenString)
+		if err != nil || reauth.UserID != claims.UserID || reauth.UserType != claims.UserType {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Invalid or expired re-authentication token",
+				"code":  "reauth_required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
 // ExtractTokenFromHeader extracts JWT token from Authorization header
 func ExtractTokenFromHeader(c *gin.Context) (string, error) {
 	authHeader := c.GetHeader("Authorization")
@@ -250,3 +320,13 @@ func GetCurrentUser(c *gin.Context) (*utils.JWTClaims, bool) {
 	claims, ok := user.(*utils.JWTClaims)
 	return claims, ok
 }
+
+// GetCurrentActor returns the authenticated account performing the request, or nil if unauthenticated
+func GetCurrentActor(c *gin.Context) *models.Actor {
+	claims, exists := GetCurrentUser(c)
+	if !exists || claims == nil {
+		return nil
+	}
+
+	return &models.Actor{ID: claims.UserID, Type: claims.UserType}
+}
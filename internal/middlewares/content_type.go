@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireJSON rejects POST/PUT/PATCH requests whose body isn't application/json
+// with 415 Unsupported Media Type, instead of letting them fail later with a
+// generic bind error. Requests without a body are allowed, as are the routes in
+// exemptPaths (matched against the route pattern, e.g. multipart upload routes).
+func RequireJSON(exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]struct{}, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		if _, ok := exempt[c.FullPath()]; ok || c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"error":   "Unsupported media type",
+				"details": "Content-Type must be application/json",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gin-gonic/gin"
+)
+
+// DestructiveConfirmationHeader carries the explicit override for destructive operations in production
+const DestructiveConfirmationHeader = "X-Confirm-Destructive"
+
+// ConfirmDestructive applies the production guard to a destructive operation. In production
+// the request must carry DestructiveConfirmationHeader set to config.DestructiveConfirmation;
+// confirmed requests are audit-logged. It writes a 403 response and returns false when the
+// operation is refused.
+func ConfirmDestructive(c *gin.Context, cfg *config.Config, operation string) bool {
+	if cfg == nil {
+		return true
+	}
+
+	confirmed := c.GetHeader(DestructiveConfirmationHeader) == config.DestructiveConfirmation
+	if err := cfg.GuardDestructive(operation, confirmed); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Operation refused in production",
+			"details": fmt.Sprintf("Send the %s: %s header to confirm %s", DestructiveConfirmationHeader, config.DestructiveConfirmation, operation),
+		})
+		return false
+	}
+
+	if cfg.IsProduction() {
+		actor := "unknown"
+		if a := GetCurrentActor(c); a != nil {
+			actor = fmt.Sprintf("%s:%d", a.Type, a.ID)
+		}
+		fmt.Printf("Destructive operation confirmed in production: Operation=%s, By=%s, IP=%s, Time=%s\n",
+			operation, actor, c.ClientIP(), time.Now().Format(time.RFC3339))
+	}
+
+	return true
+}
+
+// RequireDestructiveConfirmation guards a route whose every request is a destructive
+// operation, such as deleting a record, with ConfirmDestructive
+func RequireDestructiveConfirmation(cfg *config.Config, operation string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ConfirmDestructive(c, cfg, operation) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
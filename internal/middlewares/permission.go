@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -50,10 +51,7 @@ func RequirePermission(permissionService services.PermissionServiceInterface, re
 		// Check permission using user ID and type
 		err := permissionService.CheckUserPermission(userIDInt, userTypeStr, resource, action)
 		if err != nil {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Permission denied",
-			})
-			c.Abort()
+			abortPermissionCheck(c, err)
 			return
 		}
 
@@ -61,6 +59,21 @@ func RequirePermission(permissionService services.PermissionServiceInterface, re
 	}
 }
 
+// abortPermissionCheck aborts with 403 when the permission is missing, or 500 when it
+// could not be checked
+func abortPermissionCheck(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrPermissionDenied) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Permission denied",
+		})
+	} else {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to check permissions",
+		})
+	}
+	c.Abort()
+}
+
 // RequireResourceOwnership checks if the authenticated user owns the resource they're trying to access
 func RequireResourceOwnership(permissionService services.PermissionServiceInterface, resourceType string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -182,10 +195,7 @@ func RequirePermissionAndOwnership(permissionService services.PermissionServiceI
 		// Check permission first
 		err := permissionService.CheckUserPermission(userIDInt, userTypeStr, resource, action)
 		if err != nil {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Permission denied",
-			})
-			c.Abort()
+			abortPermissionCheck(c, err)
 			return
 		}
 
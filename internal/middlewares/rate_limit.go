@@ -0,0 +1,87 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimiter holds one token bucket per client IP and route
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	buckets   map[string]*utils.TokenBucket
+	lastSweep time.Time
+}
+
+// RateLimit limits each client IP to limit requests per window on the route it is
+// attached to, with bursts of up to limit requests. Requests over the limit get a 429
+// with a Retry-After header. Every call creates its own limiter, so routes attached
+// to separate RateLimit middlewares are limited independently. A non-positive limit
+// disables limiting.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 || window <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	limiter := &rateLimiter{
+		limit:     limit,
+		window:    window,
+		buckets:   make(map[string]*utils.TokenBucket),
+		lastSweep: time.Now(),
+	}
+
+	return func(c *gin.Context) {
+		key := c.ClientIP() + " " + c.Request.Method + " " + c.FullPath()
+		allowed, retryAfter := limiter.bucket(key).Allow()
+		if !allowed {
+			fmt.Printf("Rate limit exceeded: IP=%s, Route=%s %s, Time=%s\n",
+				c.ClientIP(), c.Request.Method, c.FullPath(), time.Now().Format(time.RFC3339))
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": "Too many requests, please try again later",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// bucket returns the token bucket for key, creating it on first use
+func (l *rateLimiter) bucket(key string) *utils.TokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = utils.NewTokenBucketPer(l.limit, l.window)
+		l.buckets[key] = bucket
+	}
+	return bucket
+}
+
+// sweep drops the buckets that have refilled completely, at most once per window, so
+// clients that went quiet don't keep using memory
+func (l *rateLimiter) sweep() {
+	if time.Since(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = time.Now()
+
+	for key, bucket := range l.buckets {
+		if bucket.Full() {
+			delete(l.buckets, key)
+		}
+	}
+}
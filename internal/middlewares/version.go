@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Supported request/response schema versions
+const (
+	APIVersion1      = 1
+	APIVersion2      = 2
+	LatestAPIVersion = APIVersion2
+
+	// apiVersionKey is the context key holding the negotiated API version
+	apiVersionKey = "api_version"
+)
+
+// APIVersion reads the requested schema version from the X-API-Version (or
+// Accept-Version) header and stores it in the context. Requests without a
+// version header default to v1 so existing clients keep working.
+func APIVersion() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("X-API-Version")
+		if header == "" {
+			header = c.GetHeader("Accept-Version")
+		}
+
+		version := APIVersion1
+		if header != "" {
+			parsed, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(header)), "v"))
+			if err != nil || parsed < APIVersion1 || parsed > LatestAPIVersion {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Unsupported API version",
+					"details": "Supported versions: 1-" + strconv.Itoa(LatestAPIVersion),
+				})
+				c.Abort()
+				return
+			}
+			version = parsed
+		}
+
+		c.Set(apiVersionKey, version)
+		c.Writer.Header().Set("X-API-Version", strconv.Itoa(version))
+
+		c.Next()
+	}
+}
+
+// GetAPIVersion returns the API version negotiated for the request (v1 if unset)
+func GetAPIVersion(c *gin.Context) int {
+	if version, exists := c.Get(apiVersionKey); exists {
+		if v, ok := version.(int); ok {
+			return v
+		}
+	}
+	return APIVersion1
+}
@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// LockName is the MySQL named lock held while migrations are applied or rolled back
+const LockName = "gatehide_migrations"
+
+// DefaultLockTimeout is how long to wait for another migration run to finish
+const DefaultLockTimeout = 30 * time.Second
+
+// ErrMigrationInProgress is returned when the migration lock could not be acquired in time
+var ErrMigrationInProgress = errors.New("another migration is in progress")
+
+// LockingRunner is a MigrationRunner that can hold the migration lock
+type LockingRunner interface {
+	MigrationRunner
+	// Lock waits up to timeout for the migration lock and returns a function releasing it
+	Lock(timeout time.Duration) (unlock func() error, err error)
+}
+
+// Lock acquires the named migration lock so that only one process migrates the
+// database at a time, waiting up to timeout for it. MySQL named locks belong to a
+// connection, so a dedicated connection is held until the returned unlock function
+// is called.
+func (r *MySQLRunner) Lock(timeout time.Duration) (unlock func() error, err error) {
+	ctx := context.Background()
+
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for migration lock: %w", err)
+	}
+
+	// GET_LOCK returns 1 when acquired, 0 on timeout and NULL on error
+	var acquired sql.NullInt64
+	seconds := int(math.Ceil(timeout.Seconds()))
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", LockName, seconds).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, fmt.Errorf("%w (lock %q not acquired within %s)", ErrMigrationInProgress, LockName, timeout)
+	}
+
+	return func() error {
+		defer conn.Close()
+		if _, err := conn.ExecContext(ctx, "DO RELEASE_LOCK(?)", LockName); err != nil {
+			return fmt.Errorf("failed to release migration lock: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,257 @@
+package migrations
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RunOptions controls an up or down run
+type RunOptions struct {
+	Steps  int    // number of migrations to apply or roll back
+	Target string // migrate up to and including, or roll back everything newer than, this version; overrides Steps
+	DryRun bool   // print the SQL that would run without executing it
+	Out    io.Writer
+}
+
+// Up applies pending migrations in version order. In a dry run the migrations table
+// is only read: each selected migration's SQL is printed and nothing is executed.
+func Up(runner MigrationRunner, available []MigrationFile, opts RunOptions) error {
+	if !opts.DryRun {
+		// Create migration table if it doesn't exist
+		if err := runner.CreateMigrationTable(); err != nil {
+			return fmt.Errorf("failed to create migration table: %w", err)
+		}
+	}
+
+	// Get applied migrations
+	applied, err := runner.GetAppliedMigrations()
+	if err != nil {
+		if !opts.DryRun || !isMissingTableError(err) {
+			return fmt.Errorf("failed to get applied migrations: %w", err)
+		}
+		// Nothing has been applied yet
+		applied = nil
+	}
+
+	// Find pending migrations
+	pending := PendingMigrations(available, applied)
+
+	// A target selects every pending migration up to and including it
+	steps := opts.Steps
+	if opts.Target != "" {
+		pending, err = PendingUpTo(pending, applied, opts.Target)
+		if err != nil {
+			return err
+		}
+		steps = len(pending)
+	}
+
+	if len(pending) == 0 {
+		fmt.Fprintln(opts.Out, "No pending migrations.")
+		return nil
+	}
+
+	// Limit by steps
+	if steps > len(pending) {
+		steps = len(pending)
+	}
+
+	if opts.DryRun {
+		fmt.Fprintf(opts.Out, "Dry run: %d migration(s) would be applied\n", steps)
+		for _, migration := range pending[:steps] {
+			writeDryRun(opts.Out, migration.Version, migration.Description, migration.UpSQL)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(opts.Out, "Applying %d migration(s)...\n", steps)
+
+	// Apply migrations
+	for _, migration := range pending[:steps] {
+		fmt.Fprintf(opts.Out, "Applying migration %s: %s\n", migration.Version, migration.Description)
+
+		if err := runner.ApplyMigration(migration.Version, migration.Description, migration.UpSQL); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", migration.Version, err)
+		}
+
+		fmt.Fprintf(opts.Out, "✅ Migration %s applied successfully\n", migration.Version)
+	}
+
+	return nil
+}
+
+// Down rolls back applied migrations, newest first. In a dry run each selected
+// migration's rollback SQL is printed and nothing is executed.
+func Down(runner MigrationRunner, available []MigrationFile, opts RunOptions) error {
+	// Get applied migrations
+	applied, err := runner.GetAppliedMigrations()
+	if err != nil {
+		// If migrations table doesn't exist, there are no migrations to rollback
+		if isMissingTableError(err) {
+			fmt.Fprintln(opts.Out, "No applied migrations to rollback.")
+			return nil
+		}
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	if len(applied) == 0 {
+		fmt.Fprintln(opts.Out, "No applied migrations to rollback.")
+		return nil
+	}
+
+	// A target rolls back every migration newer than it
+	steps := opts.Steps
+	if opts.Target != "" {
+		newer, err := AppliedAfter(applied, opts.Target)
+		if err != nil {
+			return err
+		}
+		if len(newer) == 0 {
+			fmt.Fprintf(opts.Out, "Migration %s is already the latest applied migration.\n", opts.Target)
+			return nil
+		}
+		steps = len(newer)
+	}
+
+	// Limit by steps
+	if steps > len(applied) {
+		steps = len(applied)
+	}
+
+	// Create a map of available migrations for quick lookup
+	availableMap := make(map[string]MigrationFile)
+	for _, migration := range available {
+		availableMap[migration.Version] = migration
+	}
+
+	if opts.DryRun {
+		fmt.Fprintf(opts.Out, "Dry run: %d migration(s) would be rolled back\n", steps)
+	} else {
+		fmt.Fprintf(opts.Out, "Rolling back %d migration(s)...\n", steps)
+	}
+
+	// Rollback migrations (from latest to oldest)
+	for i := len(applied) - 1; i >= len(applied)-steps; i-- {
+		migration := applied[i]
+
+		// Get migration file for rollback SQL
+		migrationFile, exists := availableMap[migration.Version]
+		if !exists {
+			return fmt.Errorf("migration file not found for version %s", migration.Version)
+		}
+
+		if opts.DryRun {
+			writeDryRun(opts.Out, migration.Version, migration.Description, migrationFile.DownSQL)
+			continue
+		}
+
+		fmt.Fprintf(opts.Out, "Rolling back migration %s: %s\n", migration.Version, migration.Description)
+
+		if err := runner.RollbackMigration(migration.Version, migrationFile.DownSQL); err != nil {
+			return fmt.Errorf("failed to rollback migration %s: %w", migration.Version, err)
+		}
+
+		fmt.Fprintf(opts.Out, "✅ Migration %s rolled back successfully\n", migration.Version)
+	}
+
+	return nil
+}
+
+// Redo rolls back the most recent applied migrations and immediately re-applies
+// them, oldest first. If re-applying fails, the rollback stays in place and the
+// remaining migrations are left rolled back. In a dry run the rollback and
+// re-apply SQL is printed and nothing is executed.
+func Redo(runner MigrationRunner, available []MigrationFile, opts RunOptions) error {
+	// Get applied migrations
+	applied, err := runner.GetAppliedMigrations()
+	if err != nil {
+		if isMissingTableError(err) {
+			fmt.Fprintln(opts.Out, "No applied migrations to redo.")
+			return nil
+		}
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	if len(applied) == 0 {
+		fmt.Fprintln(opts.Out, "No applied migrations to redo.")
+		return nil
+	}
+
+	// Limit by steps
+	steps := opts.Steps
+	if steps > len(applied) {
+		steps = len(applied)
+	}
+
+	// Create a map of available migrations for quick lookup
+	availableMap := make(map[string]MigrationFile)
+	for _, migration := range available {
+		availableMap[migration.Version] = migration
+	}
+
+	// Resolve every migration file before changing anything
+	redo := make([]MigrationFile, 0, steps)
+	for _, migration := range applied[len(applied)-steps:] {
+		migrationFile, exists := availableMap[migration.Version]
+		if !exists {
+			return fmt.Errorf("migration file not found for version %s", migration.Version)
+		}
+		redo = append(redo, migrationFile)
+	}
+
+	if opts.DryRun {
+		fmt.Fprintf(opts.Out, "Dry run: %d migration(s) would be redone\n", steps)
+		for i := len(redo) - 1; i >= 0; i-- {
+			writeDryRun(opts.Out, redo[i].Version, redo[i].Description+" (rollback)", redo[i].DownSQL)
+		}
+		for _, migration := range redo {
+			writeDryRun(opts.Out, migration.Version, migration.Description, migration.UpSQL)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(opts.Out, "Redoing %d migration(s)...\n", steps)
+
+	// Rollback migrations (from latest to oldest)
+	for i := len(redo) - 1; i >= 0; i-- {
+		migration := redo[i]
+		fmt.Fprintf(opts.Out, "Rolling back migration %s: %s\n", migration.Version, migration.Description)
+
+		if err := runner.RollbackMigration(migration.Version, migration.DownSQL); err != nil {
+			return fmt.Errorf("failed to rollback migration %s: %w", migration.Version, err)
+		}
+
+		fmt.Fprintf(opts.Out, "✅ Migration %s rolled back successfully\n", migration.Version)
+	}
+
+	// Re-apply them (from oldest to latest)
+	for _, migration := range redo {
+		fmt.Fprintf(opts.Out, "Applying migration %s: %s\n", migration.Version, migration.Description)
+
+		if err := runner.ApplyMigration(migration.Version, migration.Description, migration.UpSQL); err != nil {
+			return fmt.Errorf("failed to re-apply migration %s: %w", migration.Version, err)
+		}
+
+		fmt.Fprintf(opts.Out, "✅ Migration %s applied successfully\n", migration.Version)
+	}
+
+	return nil
+}
+
+// writeDryRun prints a migration and the SQL a real run would execute
+func writeDryRun(w io.Writer, version, description, script string) {
+	fmt.Fprintf(w, "\n-- Migration %s: %s\n", version, description)
+	if strings.TrimSpace(script) == "" {
+		fmt.Fprintln(w, "-- (no SQL)")
+		return
+	}
+	fmt.Fprintln(w, script)
+}
+
+// isMissingTableError reports whether err is caused by the migrations table not existing
+// (MySQL: "Table ... doesn't exist", PostgreSQL: "relation ... does not exist")
+func isMissingTableError(err error) bool {
+	return strings.Contains(err.Error(), "doesn't exist") || strings.Contains(err.Error(), "Table") ||
+		strings.Contains(err.Error(), "does not exist")
+}
@@ -1,6 +1,8 @@
 package migrations
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 )
 
@@ -9,6 +11,7 @@ type Migration struct {
 	ID          int       `json:"id" db:"id"`
 	Version     string    `json:"version" db:"version"`
 	Description string    `json:"description" db:"description"`
+	Checksum    string    `json:"checksum" db:"checksum"` // empty for migrations applied before checksums were recorded
 	AppliedAt   time.Time `json:"applied_at" db:"applied_at"`
 }
 
@@ -20,6 +23,17 @@ type MigrationFile struct {
 	DownSQL     string
 }
 
+// Checksum returns the hex SHA-256 of the migration's UP section
+func (f MigrationFile) Checksum() string {
+	return Checksum(f.UpSQL)
+}
+
+// Checksum returns the hex SHA-256 of a migration script
+func Checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
+
 // MigrationRunner interface defines methods for running migrations
 type MigrationRunner interface {
 	CreateMigrationTable() error
@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownTarget is returned when a target version matches no migration file
+var ErrUnknownTarget = errors.New("unknown target migration")
+
+// ErrTargetAlreadyApplied is returned when migrating up to a version that is already applied
+var ErrTargetAlreadyApplied = errors.New("target migration is already applied")
+
+// ErrTargetNotApplied is returned when rolling back to a version that is not applied
+var ErrTargetNotApplied = errors.New("target migration is not applied")
+
+// PendingMigrations returns the available migrations that have not been applied, in order
+func PendingMigrations(available []MigrationFile, applied []Migration) []MigrationFile {
+	appliedMap := make(map[string]bool)
+	for _, m := range applied {
+		appliedMap[m.Version] = true
+	}
+
+	var pending []MigrationFile
+	for _, migration := range available {
+		if !appliedMap[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending
+}
+
+// PendingUpTo returns the pending migrations up to and including the target version
+func PendingUpTo(pending []MigrationFile, applied []Migration, target string) ([]MigrationFile, error) {
+	for i, migration := range pending {
+		if migration.Version == target {
+			return pending[:i+1], nil
+		}
+	}
+
+	for _, m := range applied {
+		if m.Version == target {
+			return nil, fmt.Errorf("%w: %s", ErrTargetAlreadyApplied, target)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownTarget, target)
+}
+
+// AppliedAfter returns the applied migrations newer than the target version, oldest
+// first. Rolling these back leaves the target as the latest applied migration.
+func AppliedAfter(applied []Migration, target string) ([]Migration, error) {
+	for i, m := range applied {
+		if m.Version == target {
+			return applied[i+1:], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrTargetNotApplied, target)
+}
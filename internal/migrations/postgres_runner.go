@@ -0,0 +1,278 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/lib/pq"
+)
+
+// postgresLockPollInterval is how often Lock retries while another run holds the lock
+const postgresLockPollInterval = 250 * time.Millisecond
+
+// PostgresRunner implements MigrationRunner for PostgreSQL. Migration files must use
+// PostgreSQL syntax. Unlike MySQL, PostgreSQL DDL is transactional, so a failing
+// migration is rolled back completely.
+type PostgresRunner struct {
+	db     *sql.DB
+	config *config.Config
+}
+
+// Ensure PostgresRunner satisfies LockingRunner
+var _ LockingRunner = (*PostgresRunner)(nil)
+
+// NewPostgresRunner creates a new PostgreSQL migration runner
+func NewPostgresRunner(cfg *config.Config) (*PostgresRunner, error) {
+	// First check if database exists
+	dbExists, err := checkPostgresDatabaseExists(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check database existence: %w", err)
+	}
+
+	if !dbExists {
+		// Check if we should auto-create database (for non-interactive environments)
+		autoCreate := os.Getenv("DB_AUTO_CREATE") == "true"
+
+		if !autoCreate && !promptCreateDatabase(cfg.Database.DBName) {
+			return nil, fmt.Errorf("database creation cancelled by user")
+		}
+
+		// Create database
+		if err := createPostgresDatabase(cfg); err != nil {
+			return nil, fmt.Errorf("failed to create database: %w", err)
+		}
+	}
+
+	// Connect to the specific database
+	db, err := sql.Open("postgres", cfg.GetDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &PostgresRunner{
+		db:     db,
+		config: cfg,
+	}, nil
+}
+
+// NewPostgresRunnerWithDB creates a PostgreSQL migration runner on an existing connection
+func NewPostgresRunnerWithDB(db *sql.DB, cfg *config.Config) *PostgresRunner {
+	return &PostgresRunner{
+		db:     db,
+		config: cfg,
+	}
+}
+
+// CreateMigrationTable creates the migrations tracking table
+func (r *PostgresRunner) CreateMigrationTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS migrations (
+		id SERIAL PRIMARY KEY,
+		version VARCHAR(255) NOT NULL UNIQUE,
+		description VARCHAR(500) NOT NULL,
+		checksum CHAR(64) NULL,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)
+	`
+	_, err := r.db.ExecContext(context.Background(), query)
+	return err
+}
+
+// GetAppliedMigrations returns all applied migrations
+func (r *PostgresRunner) GetAppliedMigrations() ([]Migration, error) {
+	query := "SELECT id, version, description, checksum, applied_at FROM migrations ORDER BY version"
+	rows, err := r.db.QueryContext(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var migrations []Migration
+	for rows.Next() {
+		var m Migration
+		var checksum sql.NullString
+		if err := rows.Scan(&m.ID, &m.Version, &m.Description, &checksum, &m.AppliedAt); err != nil {
+			return nil, err
+		}
+		m.Checksum = checksum.String
+		migrations = append(migrations, m)
+	}
+
+	return migrations, rows.Err()
+}
+
+// ApplyMigration applies a migration and records it in a single transaction
+func (r *PostgresRunner) ApplyMigration(version, description, upSQL string) error {
+	return r.runInTransaction(version, "migration", upSQL, func(tx *sql.Tx) error {
+		// Record migration
+		insertQuery := "INSERT INTO migrations (version, description, checksum) VALUES ($1, $2, $3)"
+		if _, err := tx.ExecContext(context.Background(), insertQuery, version, description, Checksum(upSQL)); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", version, err)
+		}
+		return nil
+	})
+}
+
+// RollbackMigration rolls back a migration and removes its record in a single transaction
+func (r *PostgresRunner) RollbackMigration(version, downSQL string) error {
+	return r.runInTransaction(version, "rollback", downSQL, func(tx *sql.Tx) error {
+		// Special case: if we're dropping the migrations table itself, skip this step
+		if version != "001_create_migrations_table" {
+			deleteQuery := "DELETE FROM migrations WHERE version = $1"
+			if _, err := tx.ExecContext(context.Background(), deleteQuery, version); err != nil {
+				return fmt.Errorf("failed to remove migration record %s: %w", version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// runInTransaction executes a migration script statement by statement in a transaction,
+// then runs record in the same transaction and commits
+func (r *PostgresRunner) runInTransaction(version, kind, script string, record func(tx *sql.Tx) error) error {
+	statements := SplitStatements(script)
+
+	tx, err := r.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction for %s %s: %w", kind, version, err)
+	}
+
+	for i, statement := range statements {
+		if _, err := tx.ExecContext(context.Background(), statement); err != nil {
+			r.rollback(tx, version)
+			return fmt.Errorf("failed to execute %s %s (statement %d of %d): %w", kind, version, i+1, len(statements), err)
+		}
+	}
+
+	if err := record(tx); err != nil {
+		r.rollback(tx, version)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s %s: %w", kind, version, err)
+	}
+
+	return nil
+}
+
+// rollback rolls back a failed migration transaction; a rollback failure is logged so
+// the original error is the one returned
+func (r *PostgresRunner) rollback(tx *sql.Tx, version string) {
+	if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		log.Printf("Warning: failed to roll back %s: %v", version, err)
+	}
+}
+
+// Lock acquires the migration advisory lock, waiting up to timeout for it. PostgreSQL
+// advisory locks belong to a session, so a dedicated connection is held until the
+// returned unlock function is called.
+func (r *PostgresRunner) Lock(timeout time.Duration) (unlock func() error, err error) {
+	ctx := context.Background()
+
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for migration lock: %w", err)
+	}
+
+	// pg_advisory_lock has no timeout, so poll pg_try_advisory_lock until the deadline
+	deadline := time.Now().Add(timeout)
+	for {
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", LockName).Scan(&acquired); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if acquired {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			conn.Close()
+			return nil, fmt.Errorf("%w (lock %q not acquired within %s)", ErrMigrationInProgress, LockName, timeout)
+		}
+		wait := postgresLockPollInterval
+		if remaining := time.Until(deadline); remaining < wait {
+			wait = remaining
+		}
+		time.Sleep(wait)
+	}
+
+	return func() error {
+		defer conn.Close()
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", LockName); err != nil {
+			return fmt.Errorf("failed to release migration lock: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// CheckDatabaseExists checks if the database exists
+func (r *PostgresRunner) CheckDatabaseExists() (bool, error) {
+	return checkPostgresDatabaseExists(r.config)
+}
+
+// CreateDatabase creates the database
+func (r *PostgresRunner) CreateDatabase() error {
+	return createPostgresDatabase(r.config)
+}
+
+// Close closes the database connection
+func (r *PostgresRunner) Close() error {
+	if r.db != nil {
+		return r.db.Close()
+	}
+	return nil
+}
+
+// postgresServerDSN connects to the server's maintenance database
+func postgresServerDSN(cfg *config.Config) string {
+	return cfg.GetServerDSN() + " dbname=postgres"
+}
+
+// checkPostgresDatabaseExists checks if the database exists
+func checkPostgresDatabaseExists(cfg *config.Config) (bool, error) {
+	db, err := sql.Open("postgres", postgresServerDSN(cfg))
+	if err != nil {
+		return false, err
+	}
+	defer db.Close()
+
+	// Test connection
+	if err := db.Ping(); err != nil {
+		return false, err
+	}
+
+	// Check if database exists
+	var exists bool
+	query := "SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1)"
+	if err := db.QueryRowContext(context.Background(), query, cfg.Database.DBName).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// createPostgresDatabase creates the database
+func createPostgresDatabase(cfg *config.Config) error {
+	db, err := sql.Open("postgres", postgresServerDSN(cfg))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// CREATE DATABASE cannot take parameters, so quote the name as an identifier
+	query := fmt.Sprintf("CREATE DATABASE %s ENCODING 'UTF8'", pq.QuoteIdentifier(cfg.Database.DBName))
+	_, err = db.ExecContext(context.Background(), query)
+	return err
+}
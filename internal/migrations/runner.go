@@ -21,6 +21,9 @@ type MySQLRunner struct {
 	config *config.Config
 }
 
+// Ensure MySQLRunner satisfies LockingRunner
+var _ LockingRunner = (*MySQLRunner)(nil)
+
 // NewMySQLRunner creates a new MySQL migration runner
 func NewMySQLRunner(cfg *config.Config) (*MySQLRunner, error) {
 	// First check if database exists
@@ -60,6 +63,26 @@ func NewMySQLRunner(cfg *config.Config) (*MySQLRunner, error) {
 	}, nil
 }
 
+// NewRunner creates the migration runner for the configured database driver
+func NewRunner(cfg *config.Config) (LockingRunner, error) {
+	switch cfg.Database.Driver {
+	case "mysql":
+		return NewMySQLRunner(cfg)
+	case "postgres":
+		return NewPostgresRunner(cfg)
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Database.Driver)
+	}
+}
+
+// NewMySQLRunnerWithDB creates a MySQL migration runner on an existing connection
+func NewMySQLRunnerWithDB(db *sql.DB, cfg *config.Config) *MySQLRunner {
+	return &MySQLRunner{
+		db:     db,
+		config: cfg,
+	}
+}
+
 // CreateMigrationTable creates the migrations tracking table
 func (r *MySQLRunner) CreateMigrationTable() error {
 	query := `
@@ -67,16 +90,57 @@ func (r *MySQLRunner) CreateMigrationTable() error {
 		id INT AUTO_INCREMENT PRIMARY KEY,
 		version VARCHAR(255) NOT NULL UNIQUE,
 		description VARCHAR(500) NOT NULL,
+		checksum CHAR(64) NULL,
 		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 	`
-	_, err := r.db.ExecContext(context.Background(), query)
-	return err
+	if _, err := r.db.ExecContext(context.Background(), query); err != nil {
+		return err
+	}
+	return r.ensureChecksumColumn()
 }
 
-// GetAppliedMigrations returns all applied migrations
+// ensureChecksumColumn adds the checksum column to a migrations table created before
+// checksums were recorded. It does nothing when the table doesn't exist.
+func (r *MySQLRunner) ensureChecksumColumn() error {
+	exists, hasChecksum, err := r.inspectMigrationTable()
+	if err != nil {
+		return err
+	}
+	if !exists || hasChecksum {
+		return nil
+	}
+
+	alter := "ALTER TABLE migrations ADD COLUMN checksum CHAR(64) NULL AFTER description"
+	if _, err := r.db.ExecContext(context.Background(), alter); err != nil {
+		return fmt.Errorf("failed to add checksum column to migrations table: %w", err)
+	}
+	return nil
+}
+
+// inspectMigrationTable reports whether the migrations table exists and has the checksum column
+func (r *MySQLRunner) inspectMigrationTable() (exists, hasChecksum bool, err error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(COLUMN_NAME = 'checksum'), 0)
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'migrations'
+	`
+	var columns, checksumColumns int
+	if err := r.db.QueryRowContext(context.Background(), query).Scan(&columns, &checksumColumns); err != nil {
+		return false, false, fmt.Errorf("failed to inspect migrations table: %w", err)
+	}
+	return columns > 0, checksumColumns > 0, nil
+}
+
+// GetAppliedMigrations returns all applied migrations. It only reads, so it is safe
+// for dry runs; tables without the checksum column report empty checksums.
 func (r *MySQLRunner) GetAppliedMigrations() ([]Migration, error) {
-	query := "SELECT id, version, description, applied_at FROM migrations ORDER BY version"
+	checksumColumn := "checksum"
+	if exists, hasChecksum, err := r.inspectMigrationTable(); err == nil && exists && !hasChecksum {
+		checksumColumn = "NULL"
+	}
+
+	query := "SELECT id, version, description, " + checksumColumn + ", applied_at FROM migrations ORDER BY version"
 	rows, err := r.db.QueryContext(context.Background(), query)
 	if err != nil {
 		return nil, err
@@ -86,60 +150,95 @@ func (r *MySQLRunner) GetAppliedMigrations() ([]Migration, error) {
 	var migrations []Migration
 	for rows.Next() {
 		var m Migration
-		if err := rows.Scan(&m.ID, &m.Version, &m.Description, &m.AppliedAt); err != nil {
+		var checksum sql.NullString
+		if err := rows.Scan(&m.ID, &m.Version, &m.Description, &checksum, &m.AppliedAt); err != nil {
 			return nil, err
 		}
+		m.Checksum = checksum.String
 		migrations = append(migrations, m)
 	}
 
 	return migrations, nil
 }
 
-// ApplyMigration applies a migration
+// ApplyMigration applies a migration. Its statements run one by one inside a single
+// transaction together with the migrations record, so a failing statement rolls back
+// every row change made by the migration. The checksum of upSQL is stored so later
+// edits to the file can be detected.
 func (r *MySQLRunner) ApplyMigration(version, description, upSQL string) error {
-	tx, err := r.db.BeginTx(context.Background(), nil)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
+	return r.runInTransaction(version, "migration", upSQL, func(tx *sql.Tx) error {
+		// Record migration
+		insertQuery := "INSERT INTO migrations (version, description, checksum) VALUES (?, ?, ?)"
+		if _, err := tx.ExecContext(context.Background(), insertQuery, version, description, Checksum(upSQL)); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", version, err)
+		}
+		return nil
+	})
+}
 
-	// Execute migration SQL
-	if _, err := tx.ExecContext(context.Background(), upSQL); err != nil {
-		return fmt.Errorf("failed to execute migration %s: %w", version, err)
-	}
+// RollbackMigration rolls back a migration, with the same transaction guarantees as ApplyMigration
+func (r *MySQLRunner) RollbackMigration(version, downSQL string) error {
+	return r.runInTransaction(version, "rollback", downSQL, func(tx *sql.Tx) error {
+		// Remove migration record (only if migrations table still exists)
+		// Special case: if we're dropping the migrations table itself, skip this step
+		if version != "001_create_migrations_table" {
+			deleteQuery := "DELETE FROM migrations WHERE version = ?"
+			if _, err := tx.ExecContext(context.Background(), deleteQuery, version); err != nil {
+				return fmt.Errorf("failed to remove migration record %s: %w", version, err)
+			}
+		}
+		return nil
+	})
+}
 
-	// Record migration
-	insertQuery := "INSERT INTO migrations (version, description) VALUES (?, ?)"
-	if _, err := tx.ExecContext(context.Background(), insertQuery, version, description); err != nil {
-		return fmt.Errorf("failed to record migration %s: %w", version, err)
+// runInTransaction executes a migration script statement by statement in a transaction,
+// then runs record in the same transaction and commits. On failure the transaction is
+// rolled back and the original error returned. MySQL commits DDL implicitly, so a
+// warning is logged when the script mixes DDL that a rollback cannot undo.
+func (r *MySQLRunner) runInTransaction(version, kind, script string, record func(tx *sql.Tx) error) error {
+	statements := SplitStatements(script)
+
+	var ddl int
+	for _, statement := range statements {
+		if IsImplicitCommit(statement) {
+			ddl++
+		}
+	}
+	if ddl > 0 && len(statements) > 1 {
+		log.Printf("Warning: %s %s contains %d DDL statement(s) that MySQL commits implicitly; "+
+			"if a later statement fails their effects cannot be rolled back", kind, version, ddl)
 	}
 
-	return tx.Commit()
-}
-
-// RollbackMigration rolls back a migration
-func (r *MySQLRunner) RollbackMigration(version, downSQL string) error {
 	tx, err := r.db.BeginTx(context.Background(), nil)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to begin transaction for %s %s: %w", kind, version, err)
 	}
-	defer tx.Rollback()
 
-	// Execute rollback SQL
-	if _, err := tx.ExecContext(context.Background(), downSQL); err != nil {
-		return fmt.Errorf("failed to execute rollback for %s: %w", version, err)
+	for i, statement := range statements {
+		if _, err := tx.ExecContext(context.Background(), statement); err != nil {
+			r.rollback(tx, version)
+			return fmt.Errorf("failed to execute %s %s (statement %d of %d): %w", kind, version, i+1, len(statements), err)
+		}
 	}
 
-	// Remove migration record (only if migrations table still exists)
-	// Special case: if we're dropping the migrations table itself, skip this step
-	if version != "001_create_migrations_table" {
-		deleteQuery := "DELETE FROM migrations WHERE version = ?"
-		if _, err := tx.ExecContext(context.Background(), deleteQuery, version); err != nil {
-			return fmt.Errorf("failed to remove migration record %s: %w", version, err)
-		}
+	if err := record(tx); err != nil {
+		r.rollback(tx, version)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s %s: %w", kind, version, err)
 	}
 
-	return tx.Commit()
+	return nil
+}
+
+// rollback rolls back a failed migration transaction; a rollback failure is logged so
+// the original error is the one returned
+func (r *MySQLRunner) rollback(tx *sql.Tx, version string) {
+	if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		log.Printf("Warning: failed to roll back %s: %v", version, err)
+	}
 }
 
 // CheckDatabaseExists checks if the database exists
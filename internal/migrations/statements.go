@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"strings"
+)
+
+// implicitCommitPrefixes are the statement prefixes MySQL commits implicitly; their
+// effects survive a transaction rollback
+var implicitCommitPrefixes = []string{
+	"CREATE", "ALTER", "DROP", "RENAME", "TRUNCATE",
+	"LOCK TABLES", "UNLOCK TABLES", "GRANT", "REVOKE",
+}
+
+// SplitStatements splits a migration script into individual statements on semicolons,
+// ignoring semicolons inside quoted strings, identifiers and comments. Comment-only and
+// empty statements are dropped.
+func SplitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote rune // the open quote character, or 0
+	inLineComment, inBlockComment := false, false
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case inLineComment:
+			if c == '\n' {
+				inLineComment = false
+				current.WriteRune(c)
+			}
+			continue
+		case inBlockComment:
+			if c == '*' && next == '/' {
+				inBlockComment = false
+				i++
+			}
+			continue
+		case quote != 0:
+			current.WriteRune(c)
+			if c == '\\' && quote != '`' && next != 0 {
+				current.WriteRune(next)
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '-' && next == '-', c == '#':
+			inLineComment = true
+		case c == '/' && next == '*':
+			inBlockComment = true
+			i++
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			current.WriteRune(c)
+		case c == ';':
+			statements = appendStatement(statements, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(c)
+		}
+	}
+
+	return appendStatement(statements, current.String())
+}
+
+// appendStatement appends a statement unless it is blank
+func appendStatement(statements []string, statement string) []string {
+	statement = strings.TrimSpace(statement)
+	if statement == "" {
+		return statements
+	}
+	return append(statements, statement)
+}
+
+// IsImplicitCommit reports whether MySQL implicitly commits the statement (DDL and
+// similar), meaning a transaction cannot undo it
+func IsImplicitCommit(statement string) bool {
+	upper := strings.ToUpper(strings.Join(strings.Fields(statement), " "))
+	for _, prefix := range implicitCommitPrefixes {
+		if strings.HasPrefix(upper, prefix+" ") || upper == prefix {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Migration statuses reported by the status command
+const (
+	StatusPending  = "PENDING"
+	StatusApplied  = "APPLIED"
+	StatusModified = "MODIFIED"
+)
+
+// MigrationStatus describes the state of one migration file against the database
+type MigrationStatus struct {
+	Version     string
+	Description string
+	Status      string
+}
+
+// BuildStatus compares the migration files on disk with the applied migrations. An
+// applied migration whose file no longer matches the recorded checksum is MODIFIED;
+// migrations applied before checksums were recorded are reported as APPLIED.
+func BuildStatus(available []MigrationFile, applied []Migration) []MigrationStatus {
+	appliedMap := make(map[string]Migration)
+	for _, m := range applied {
+		appliedMap[m.Version] = m
+	}
+
+	statuses := make([]MigrationStatus, 0, len(available))
+	for _, migration := range available {
+		status := StatusPending
+		if m, ok := appliedMap[migration.Version]; ok {
+			status = StatusApplied
+			if m.Checksum != "" && m.Checksum != migration.Checksum() {
+				status = StatusModified
+			}
+		}
+		statuses = append(statuses, MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Status:      status,
+		})
+	}
+
+	return statuses
+}
+
+// ModifiedVersions returns the versions of the MODIFIED migrations
+func ModifiedVersions(statuses []MigrationStatus) []string {
+	var versions []string
+	for _, s := range statuses {
+		if s.Status == StatusModified {
+			versions = append(versions, s.Version)
+		}
+	}
+	return versions
+}
+
+// WriteStatus prints the migration status table
+func WriteStatus(w io.Writer, statuses []MigrationStatus) {
+	fmt.Fprintln(w, "Migration Status:")
+	fmt.Fprintln(w, "================")
+
+	if len(statuses) == 0 {
+		fmt.Fprintln(w, "No migration files found.")
+		return
+	}
+
+	fmt.Fprintf(w, "%-20s %-30s %-15s\n", "Version", "Description", "Status")
+	fmt.Fprintln(w, strings.Repeat("-", 65))
+
+	for _, s := range statuses {
+		fmt.Fprintf(w, "%-20s %-30s %-15s\n", s.Version, s.Description, s.Status)
+	}
+}
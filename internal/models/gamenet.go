@@ -4,16 +4,16 @@ import "time"
 
 // Gamenet represents a gaming center in the system
 type Gamenet struct {
-	ID                int       `json:"id" db:"id"`
-	Name              string    `json:"name" db:"name"`
-	OwnerName         string    `json:"owner_name" db:"owner_name"`
-	OwnerMobile       string    `json:"owner_mobile" db:"owner_mobile"`
-	Address           string    `json:"address" db:"address"`
-	Email             string    `json:"email" db:"email"`
-	Password          string    `json:"-" db:"password"` // Hidden from JSON
-	LicenseAttachment *string   `json:"license_attachment" db:"license_attachment"`
-	CreatedAt         time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+	ID                int          `json:"id" db:"id"`
+	Name              string       `json:"name" db:"name"`
+	OwnerName         string       `json:"owner_name" db:"owner_name"`
+	OwnerMobile       string       `json:"owner_mobile" db:"owner_mobile"`
+	Address           string       `json:"address" db:"address"`
+	Email             string       `json:"email" db:"email"`
+	Password          PasswordHash `json:"-" db:"password"` // Hidden from JSON
+	LicenseAttachment *string      `json:"license_attachment" db:"license_attachment"`
+	CreatedAt         time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at" db:"updated_at"`
 }
 
 // GamenetCreateRequest represents a gamenet creation request
@@ -24,6 +24,13 @@ type GamenetCreateRequest struct {
 	Address           string  `json:"address" binding:"required"`
 	Email             string  `json:"email" binding:"required,email"`
 	LicenseAttachment *string `json:"license_attachment"`
+	// SendCredentials controls whether the generated password is sent to the owner by SMS; defaults to true
+	SendCredentials *bool `json:"send_credentials"`
+}
+
+// ShouldSendCredentials reports whether the new gamenet's credentials should be sent by SMS
+func (r *GamenetCreateRequest) ShouldSendCredentials() bool {
+	return r.SendCredentials == nil || *r.SendCredentials
 }
 
 // GamenetUpdateRequest represents a gamenet update request
@@ -46,8 +53,8 @@ type GamenetResponse struct {
 	Address           string    `json:"address"`
 	Email             string    `json:"email"`
 	LicenseAttachment *string   `json:"license_attachment"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	CreatedAt         Timestamp `json:"created_at"`
+	UpdatedAt         Timestamp `json:"updated_at"`
 }
 
 // GamenetSearchRequest represents a gamenet search request
@@ -83,7 +90,7 @@ func (g *Gamenet) ToResponse() GamenetResponse {
 		Address:           g.Address,
 		Email:             g.Email,
 		LicenseAttachment: g.LicenseAttachment,
-		CreatedAt:         g.CreatedAt,
-		UpdatedAt:         g.UpdatedAt,
+		CreatedAt:         NewTimestamp(g.CreatedAt),
+		UpdatedAt:         NewTimestamp(g.UpdatedAt),
 	}
 }
@@ -1,11 +1,36 @@
 package models
 
-import "time"
-
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
+	Timestamp Timestamp `json:"timestamp"`
 	Service   string    `json:"service"`
 	Version   string    `json:"version"`
 }
+
+// Readiness statuses of the probe and of each dependency
+const (
+	ReadinessReady    = "ready"
+	ReadinessNotReady = "not_ready"
+
+	DependencyUp       = "up"
+	DependencyDown     = "down"
+	DependencyDisabled = "disabled"
+)
+
+// DependencyStatus reports the state of one dependency checked by the readiness probe.
+// Failure details are logged rather than returned, since the probe is public.
+type DependencyStatus struct {
+	Status string `json:"status"`
+	// CheckedAt is when a cached status was last refreshed; unset for live checks
+	CheckedAt *Timestamp `json:"checked_at,omitempty"`
+	// CircuitState is the state of the circuit breaker in front of the dependency, if any
+	CircuitState string `json:"circuit_state,omitempty"`
+}
+
+// ReadinessResponse represents the readiness probe response
+type ReadinessResponse struct {
+	Status    string                      `json:"status"`
+	Timestamp Timestamp                   `json:"timestamp"`
+	Checks    map[string]DependencyStatus `json:"checks"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// Failed login reasons stored with each login attempt
+const (
+	LoginFailureUnknownEmail   = "unknown_email"
+	LoginFailureWrongPassword  = "wrong_password"
+	LoginFailureLocked         = "locked"
+	LoginFailureSuspended      = "suspended"
+	LoginFailureMethodDisabled = "method_disabled"
+	LoginFailureInvalidTOTP    = "invalid_totp"
+	LoginFailureOther          = "other"
+)
+
+// LoginFailureReasons lists the valid failed login reasons
+var LoginFailureReasons = []string{
+	LoginFailureUnknownEmail,
+	LoginFailureWrongPassword,
+	LoginFailureLocked,
+	LoginFailureSuspended,
+	LoginFailureMethodDisabled,
+	LoginFailureInvalidTOTP,
+	LoginFailureOther,
+}
+
+// Fields login attempts can be grouped by
+const (
+	LoginAttemptGroupByEmail  = "email"
+	LoginAttemptGroupByIP     = "ip_address"
+	LoginAttemptGroupByReason = "reason"
+)
+
+// LoginAttempt represents a failed login attempt. The email is the one that was
+// typed in, so it may not belong to any account.
+type LoginAttempt struct {
+	ID         int       `json:"id"`
+	Email      string    `json:"email"`
+	IPAddress  *string   `json:"ip_address,omitempty"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	DeviceInfo *string   `json:"device_info,omitempty"`
+	Reason     string    `json:"reason"`
+	CreatedAt  Timestamp `json:"created_at"`
+}
+
+// LoginAttemptFilter represents the filters for listing and aggregating login attempts
+type LoginAttemptFilter struct {
+	Reason    string     `json:"reason,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	IPAddress string     `json:"ip_address,omitempty"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+	Page      int        `json:"page"`
+	PageSize  int        `json:"page_size"`
+}
+
+// LoginAttemptListResponse represents a paginated list of login attempts
+type LoginAttemptListResponse struct {
+	Data       []LoginAttempt `json:"data"`
+	Pagination PaginationInfo `json:"pagination"`
+}
+
+// LoginAttemptCount is the number of login attempts sharing a value, such as the
+// attempts against one email or from one IP address
+type LoginAttemptCount struct {
+	Value    string    `json:"value"`
+	Count    int64     `json:"count"`
+	LastSeen Timestamp `json:"last_seen"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Sources of login events. An event's ID is only unique within its source.
+const (
+	LoginEventSourceLogin   = "login"
+	LoginEventSourceAttempt = "attempt"
+)
+
+// LoginEvent represents a single login attempt. Successful logins come from the
+// successful_logins table and failed attempts from the login_attempts table.
+type LoginEvent struct {
+	ID         int       `json:"id"`
+	Source     string    `json:"source"`
+	Success    bool      `json:"success"`
+	UserID     *int      `json:"user_id,omitempty"`
+	UserType   *string   `json:"user_type,omitempty"`
+	Email      *string   `json:"email,omitempty"`
+	IPAddress  *string   `json:"ip_address,omitempty"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	DeviceInfo *string   `json:"device_info,omitempty"`
+	Reason     *string   `json:"reason,omitempty"`
+	CreatedAt  Timestamp `json:"created_at"`
+}
+
+// LoginEventFilter represents the filters for listing login events
+type LoginEventFilter struct {
+	Success   *bool      `json:"success,omitempty"`
+	UserType  string     `json:"user_type,omitempty"`
+	IPAddress string     `json:"ip_address,omitempty"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+	Page      int        `json:"page"`
+	PageSize  int        `json:"page_size"`
+}
+
+// LoginEventListResponse represents a paginated list of login events
+type LoginEventListResponse struct {
+	Data       []LoginEvent   `json:"data"`
+	Pagination PaginationInfo `json:"pagination"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/base64"
+	"fmt"
 	"time"
 )
 
@@ -21,6 +23,10 @@ const (
 	NotificationStatusSent      NotificationStatus = "sent"
 	NotificationStatusFailed    NotificationStatus = "failed"
 	NotificationStatusCancelled NotificationStatus = "cancelled"
+	// NotificationStatusPermanentlyFailed marks a failed notification that ran out of automatic retries
+	NotificationStatusPermanentlyFailed NotificationStatus = "permanently_failed"
+	// NotificationStatusSkipped marks a notification that was not sent because its channel is disabled
+	NotificationStatusSkipped NotificationStatus = "skipped"
 )
 
 // NotificationPriority represents the priority of a notification
@@ -35,22 +41,25 @@ const (
 
 // Notification represents a notification in the system
 type Notification struct {
-	ID           int                    `json:"id" db:"id"`
-	Type         NotificationType       `json:"type" db:"type"`
-	Status       NotificationStatus     `json:"status" db:"status"`
-	Priority     NotificationPriority   `json:"priority" db:"priority"`
-	Recipient    string                 `json:"recipient" db:"recipient"`
-	Subject      string                 `json:"subject" db:"subject"`
-	Content      string                 `json:"content" db:"content"`
-	TemplateID   *int                   `json:"template_id" db:"template_id"`
-	TemplateData map[string]interface{} `json:"template_data" db:"template_data"`
-	Metadata     map[string]interface{} `json:"metadata" db:"metadata"`
-	ScheduledAt  *time.Time             `json:"scheduled_at" db:"scheduled_at"`
-	SentAt       *time.Time             `json:"sent_at" db:"sent_at"`
-	ErrorMsg     *string                `json:"error_msg" db:"error_msg"`
-	RetryCount   int                    `json:"retry_count" db:"retry_count"`
-	CreatedAt    time.Time              `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at" db:"updated_at"`
+	ID              int                    `json:"id" db:"id"`
+	Type            NotificationType       `json:"type" db:"type"`
+	Status          NotificationStatus     `json:"status" db:"status"`
+	Priority        NotificationPriority   `json:"priority" db:"priority"`
+	Recipient       string                 `json:"recipient" db:"recipient"`
+	RelatedUserID   *int                   `json:"related_user_id" db:"related_user_id"`
+	RelatedUserType *string                `json:"related_user_type" db:"related_user_type"`
+	Subject         string                 `json:"subject" db:"subject"`
+	Content         string                 `json:"content" db:"content"`
+	HTMLContent     string                 `json:"html_content,omitempty" db:"html_content"` // HTML body of an email
+	TemplateID      *int                   `json:"template_id" db:"template_id"`
+	TemplateData    map[string]interface{} `json:"template_data" db:"template_data"`
+	Metadata        map[string]interface{} `json:"metadata" db:"metadata"`
+	ScheduledAt     *time.Time             `json:"scheduled_at" db:"scheduled_at"`
+	SentAt          *time.Time             `json:"sent_at" db:"sent_at"`
+	ErrorMsg        *string                `json:"error_msg" db:"error_msg"`
+	RetryCount      int                    `json:"retry_count" db:"retry_count"`
+	CreatedAt       time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at" db:"updated_at"`
 }
 
 // EmailNotification represents an email notification
@@ -70,6 +79,53 @@ type SMSNotification struct {
 	To       string               `json:"to"`
 	Message  string               `json:"message"`
 	Priority NotificationPriority `json:"priority,omitempty"`
+	Type     string               `json:"type,omitempty"` // message type for usage statistics; defaults to "notification"
+}
+
+// BulkSMSResult reports the outcome of one message in a bulk SMS batch
+type BulkSMSResult struct {
+	To        string `json:"to"`
+	MessageID int    `json:"message_id,omitempty"`
+	Status    int    `json:"status,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkSMSMessage is one message of a bulk SMS request
+type BulkSMSMessage struct {
+	To      string `json:"to" binding:"required"`
+	Message string `json:"message" binding:"required"`
+}
+
+// BulkSMSRequest represents a bulk SMS request. It carries either one message per
+// recipient in Messages, or a single Message sent to every number in Recipients.
+type BulkSMSRequest struct {
+	Messages   []BulkSMSMessage `json:"messages" binding:"omitempty,dive"`
+	Message    string           `json:"message"`
+	Recipients []string         `json:"recipients"`
+}
+
+// SMSNotifications returns the request's messages in order
+func (r *BulkSMSRequest) SMSNotifications() []*SMSNotification {
+	if len(r.Messages) > 0 {
+		notifications := make([]*SMSNotification, len(r.Messages))
+		for i, message := range r.Messages {
+			notifications[i] = &SMSNotification{To: message.To, Message: message.Message}
+		}
+		return notifications
+	}
+
+	notifications := make([]*SMSNotification, len(r.Recipients))
+	for i, recipient := range r.Recipients {
+		notifications[i] = &SMSNotification{To: recipient, Message: r.Message}
+	}
+	return notifications
+}
+
+// BulkSMSResponse reports the outcome of a bulk SMS request, one result per message
+type BulkSMSResponse struct {
+	Sent    int             `json:"sent"`
+	Failed  int             `json:"failed"`
+	Results []BulkSMSResult `json:"results"`
 }
 
 // DatabaseNotification represents a database notification
@@ -95,17 +151,60 @@ type NotificationTemplate struct {
 	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
 }
 
+// Persian copy of the credential SMS templates, shared by the built-in defaults and the
+// notification template seeder. Both templates take the email and password variables.
+const (
+	UserCredentialsSMSSubject    = "اطلاعات ورود"
+	UserCredentialsSMSContent    = "اطلاعات ورود به سیستم:\nایمیل: {{email}}\nرمز عبور: {{password}}"
+	GamenetCredentialsSMSSubject = "اطلاعات ورود گیم نت"
+	GamenetCredentialsSMSContent = "اطلاعات ورود به سیستم گیم نت:\nایمیل: {{email}}\nرمز عبور: {{password}}"
+)
+
+// Persian copy of the account email templates, shared by the built-in defaults and the
+// notification template seeder
+const (
+	PasswordResetEmailSubject     = "بازنشانی رمز عبور - {{app_name}}"
+	PasswordResetEmailContent     = "کاربر گرامی {{user_name}}،\n\nدرخواست بازنشانی رمز عبور برای حساب کاربری شما در {{app_name}} دریافت شده است.\n\nبرای تنظیم رمز عبور جدید، لطفاً روی لینک زیر کلیک کنید:\n{{reset_link}}\n\nاین لینک تا {{expiry_hours}} ساعت معتبر است.\n\nاگر شما این درخواست را انجام نداده\u200cاید، لطفاً این ایمیل را نادیده بگیرید.\n\nبا احترام،\nتیم {{app_name}}"
+	EmailVerificationEmailSubject = "تأیید تغییر ایمیل - {{app_name}}"
+	EmailVerificationEmailContent = "{{user_name}} عزیز،\n\nدرخواست تغییر ایمیل برای حساب کاربری شما در {{app_name}} دریافت شده است.\n\nایمیل فعلی: {{current_email}}\nایمیل جدید: {{new_email}}\n\nکد تأیید شما: {{verification_code}}\n\nلطفاً این کد را در صفحه تنظیمات وارد کنید تا تغییر ایمیل تکمیل شود.\n\nاگر شما این درخواست را انجام نداده\u200cاید، لطفاً فوراً با تیم پشتیبانی تماس بگیرید.\n\nبا احترام،\nتیم {{app_name}}"
+)
+
+// TemplatePreviewRequest represents a request to render a stored template without sending it
+type TemplatePreviewRequest struct {
+	Name string                 `json:"name" binding:"required"`
+	Type NotificationType       `json:"type,omitempty" binding:"omitempty,oneof=email sms database"`
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// TemplatePreviewResponse represents a rendered template
+type TemplatePreviewResponse struct {
+	Name             string           `json:"name"`
+	Type             NotificationType `json:"type"`
+	Subject          string           `json:"subject"`
+	Content          string           `json:"content"`
+	MissingVariables []string         `json:"missing_variables"`
+}
+
 // CreateNotificationRequest represents a request to create a notification
 type CreateNotificationRequest struct {
-	Type         NotificationType       `json:"type" binding:"required"`
-	Priority     NotificationPriority   `json:"priority,omitempty"`
-	Recipient    string                 `json:"recipient" binding:"required"`
-	Subject      string                 `json:"subject,omitempty"`
-	Content      string                 `json:"content,omitempty"`
-	TemplateID   *int                   `json:"template_id,omitempty"`
-	TemplateData map[string]interface{} `json:"template_data,omitempty"`
-	Metadata     map[string]interface{} `json:"metadata,omitempty"`
-	ScheduledAt  *time.Time             `json:"scheduled_at,omitempty"`
+	Type            NotificationType       `json:"type" binding:"required"`
+	Priority        NotificationPriority   `json:"priority,omitempty"`
+	Recipient       string                 `json:"recipient" binding:"required"`
+	RelatedUserID   *int                   `json:"related_user_id,omitempty"`   // account the notification concerns
+	RelatedUserType *string                `json:"related_user_type,omitempty"` // user, admin or gamenet
+	Subject         string                 `json:"subject,omitempty"`
+	Content         string                 `json:"content,omitempty"`
+	HTMLContent     string                 `json:"html_content,omitempty"` // HTML body of an email, sent alongside Content
+	TemplateID      *int                   `json:"template_id,omitempty"`
+	TemplateData    map[string]interface{} `json:"template_data,omitempty"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	ScheduledAt     *time.Time             `json:"scheduled_at,omitempty"`
+}
+
+// SetRelatedUser links the notification to the account it concerns
+func (r *CreateNotificationRequest) SetRelatedUser(userID int, userType string) {
+	r.RelatedUserID = &userID
+	r.RelatedUserType = &userType
 }
 
 // SendEmailRequest represents a request to send an email
@@ -122,41 +221,81 @@ type SendEmailRequest struct {
 
 // SendSMSRequest represents a request to send an SMS
 type SendSMSRequest struct {
-	To       string               `json:"to" binding:"required"`
-	Message  string               `json:"message" binding:"required"`
-	Priority NotificationPriority `json:"priority,omitempty"`
+	To           string                 `json:"to" binding:"required"`
+	Message      string                 `json:"message" binding:"required_without=Template"`
+	Template     string                 `json:"template,omitempty"`
+	TemplateData map[string]interface{} `json:"template_data,omitempty"`
+	Priority     NotificationPriority   `json:"priority,omitempty"`
 }
 
 // NotificationResponse represents a notification response
 type NotificationResponse struct {
-	ID          int                  `json:"id"`
-	Type        NotificationType     `json:"type"`
-	Status      NotificationStatus   `json:"status"`
-	Priority    NotificationPriority `json:"priority"`
-	Recipient   string               `json:"recipient"`
-	Subject     string               `json:"subject"`
-	Content     string               `json:"content"`
-	ScheduledAt *time.Time           `json:"scheduled_at"`
-	SentAt      *time.Time           `json:"sent_at"`
-	ErrorMsg    *string              `json:"error_msg"`
-	RetryCount  int                  `json:"retry_count"`
-	CreatedAt   time.Time            `json:"created_at"`
+	ID              int                  `json:"id"`
+	Type            NotificationType     `json:"type"`
+	Status          NotificationStatus   `json:"status"`
+	Priority        NotificationPriority `json:"priority"`
+	Recipient       string               `json:"recipient"`
+	RelatedUserID   *int                 `json:"related_user_id,omitempty"`
+	RelatedUserType *string              `json:"related_user_type,omitempty"`
+	Subject         string               `json:"subject"`
+	Content         string               `json:"content"`
+	ScheduledAt     *Timestamp           `json:"scheduled_at"`
+	SentAt          *Timestamp           `json:"sent_at"`
+	ErrorMsg        *string              `json:"error_msg"`
+	RetryCount      int                  `json:"retry_count"`
+	CreatedAt       Timestamp            `json:"created_at"`
 }
 
 // ToResponse converts Notification to NotificationResponse
 func (n *Notification) ToResponse() NotificationResponse {
 	return NotificationResponse{
-		ID:          n.ID,
-		Type:        n.Type,
-		Status:      n.Status,
-		Priority:    n.Priority,
-		Recipient:   n.Recipient,
-		Subject:     n.Subject,
-		Content:     n.Content,
-		ScheduledAt: n.ScheduledAt,
-		SentAt:      n.SentAt,
-		ErrorMsg:    n.ErrorMsg,
-		RetryCount:  n.RetryCount,
-		CreatedAt:   n.CreatedAt,
+		ID:              n.ID,
+		Type:            n.Type,
+		Status:          n.Status,
+		Priority:        n.Priority,
+		Recipient:       n.Recipient,
+		RelatedUserID:   n.RelatedUserID,
+		RelatedUserType: n.RelatedUserType,
+		Subject:         n.Subject,
+		Content:         n.Content,
+		ScheduledAt:     NewTimestampPtr(n.ScheduledAt),
+		SentAt:          NewTimestampPtr(n.SentAt),
+		ErrorMsg:        n.ErrorMsg,
+		RetryCount:      n.RetryCount,
+		CreatedAt:       NewTimestamp(n.CreatedAt),
 	}
 }
+
+// NotificationCursor marks the last notification of a page for keyset pagination.
+// Notifications are ordered by (created_at, id) descending.
+type NotificationCursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+// NewNotificationCursor returns the cursor pointing after the given notification
+func NewNotificationCursor(n *Notification) *NotificationCursor {
+	return &NotificationCursor{CreatedAt: n.CreatedAt, ID: n.ID}
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *NotificationCursor) Encode() string {
+	raw := fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeNotificationCursor parses a cursor produced by Encode
+func DecodeNotificationCursor(value string) (*NotificationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var nanos int64
+	var id int
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil || id <= 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &NotificationCursor{CreatedAt: time.Unix(0, nanos), ID: id}, nil
+}
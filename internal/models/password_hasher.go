@@ -0,0 +1,255 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gatehide/gatehide-api/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported password hashing algorithms
+const (
+	HashAlgorithmBcrypt   = "bcrypt"
+	HashAlgorithmArgon2id = "argon2id"
+)
+
+// MaxPasswordBytes is the longest password that may be set, in bytes. bcrypt only uses
+// the first 72 bytes of its input, so longer passwords would silently match any password
+// sharing that prefix; the limit applies to every algorithm so hashes stay portable. It
+// is enforced when a password is hashed, not when one is checked, so accounts whose
+// password predates the limit can still sign in.
+const MaxPasswordBytes = 72
+
+// ErrPasswordTooLong is returned when a password exceeds MaxPasswordBytes
+var ErrPasswordTooLong = errors.New("password must not be longer than 72 bytes")
+
+// PasswordHasher hashes and verifies passwords. Implementations encode the
+// algorithm and its parameters in the hash string so it can be verified later
+// even if the configured algorithm changes.
+type PasswordHasher interface {
+	// Algorithm returns the algorithm name
+	Algorithm() string
+	// Hash hashes a password
+	Hash(password string) (string, error)
+	// Verify checks if the provided password matches a hash produced by this algorithm
+	Verify(password, hash string) bool
+	// Matches reports whether the hash was produced by this algorithm
+	Matches(hash string) bool
+	// NeedsRehash reports whether the hash was produced with different parameters
+	NeedsRehash(hash string) bool
+}
+
+// BcryptHasher hashes passwords using bcrypt
+type BcryptHasher struct {
+	Cost int
+}
+
+// NewBcryptHasher creates a bcrypt hasher; a non-positive cost uses bcrypt.DefaultCost
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost <= 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{Cost: cost}
+}
+
+// Algorithm returns the algorithm name
+func (h *BcryptHasher) Algorithm() string {
+	return HashAlgorithmBcrypt
+}
+
+// Hash hashes a password using bcrypt
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	if len(password) > MaxPasswordBytes {
+		return "", ErrPasswordTooLong
+	}
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	return string(bytes), err
+}
+
+// Verify checks a password against a bcrypt hash
+func (h *BcryptHasher) Verify(password, hash string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return err == nil
+}
+
+// Matches reports whether the hash is a bcrypt hash
+func (h *BcryptHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// NeedsRehash reports whether the hash was produced with a different cost
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.Cost
+}
+
+// Argon2idHasher hashes passwords using Argon2id. Hashes are stored in the
+// standard PHC format: $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type Argon2idHasher struct {
+	Memory      uint32 // in KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// NewArgon2idHasher creates an Argon2id hasher; zero values fall back to sensible defaults
+func NewArgon2idHasher(memory, iterations uint32, parallelism uint8) *Argon2idHasher {
+	if memory == 0 {
+		memory = 64 * 1024
+	}
+	if iterations == 0 {
+		iterations = 3
+	}
+	if parallelism == 0 {
+		parallelism = 2
+	}
+	return &Argon2idHasher{
+		Memory:      memory,
+		Iterations:  iterations,
+		Parallelism: parallelism,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Algorithm returns the algorithm name
+func (h *Argon2idHasher) Algorithm() string {
+	return HashAlgorithmArgon2id
+}
+
+// Hash hashes a password using Argon2id
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks a password against an Argon2id hash using the parameters stored in the hash
+func (h *Argon2idHasher) Verify(password, hash string) bool {
+	params, salt, key, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return false
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+// Matches reports whether the hash is an Argon2id hash
+func (h *Argon2idHasher) Matches(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+// NeedsRehash reports whether the hash was produced with different parameters
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, _, err := decodeArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.Memory || params.Iterations != h.Iterations || params.Parallelism != h.Parallelism
+}
+
+// decodeArgon2idHash parses a PHC-formatted Argon2id hash
+func decodeArgon2idHash(hash string) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != HashAlgorithmArgon2id {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2id version: %d", version)
+	}
+
+	params := &Argon2idHasher{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// NewPasswordHasher creates the password hasher selected by the security configuration
+func NewPasswordHasher(cfg *config.SecurityConfig) (PasswordHasher, error) {
+	switch strings.ToLower(cfg.HashAlgorithm) {
+	case "", HashAlgorithmBcrypt:
+		return NewBcryptHasher(cfg.BcryptCost), nil
+	case HashAlgorithmArgon2id:
+		return NewArgon2idHasher(cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", cfg.HashAlgorithm)
+	}
+}
+
+var (
+	passwordHasherMu sync.RWMutex
+	passwordHasher   PasswordHasher = NewBcryptHasher(bcrypt.DefaultCost)
+)
+
+// SetPasswordHasher sets the hasher used for new password hashes
+func SetPasswordHasher(hasher PasswordHasher) {
+	passwordHasherMu.Lock()
+	defer passwordHasherMu.Unlock()
+	passwordHasher = hasher
+}
+
+// GetPasswordHasher returns the hasher used for new password hashes
+func GetPasswordHasher() PasswordHasher {
+	passwordHasherMu.RLock()
+	defer passwordHasherMu.RUnlock()
+	return passwordHasher
+}
+
+// knownHashers returns a hasher for every supported algorithm, used to verify
+// hashes produced by an algorithm other than the configured one
+func knownHashers() []PasswordHasher {
+	current := GetPasswordHasher()
+	hashers := []PasswordHasher{current}
+	if current.Algorithm() != HashAlgorithmBcrypt {
+		hashers = append(hashers, NewBcryptHasher(bcrypt.DefaultCost))
+	}
+	if current.Algorithm() != HashAlgorithmArgon2id {
+		hashers = append(hashers, NewArgon2idHasher(0, 0, 0))
+	}
+	return hashers
+}
+
+// PasswordNeedsRehash reports whether a hash should be replaced with one produced
+// by the configured hasher (different algorithm or parameters)
+func PasswordNeedsRehash(hash string) bool {
+	current := GetPasswordHasher()
+	return !current.Matches(hash) || current.NeedsRehash(hash)
+}
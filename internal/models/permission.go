@@ -113,8 +113,23 @@ const (
 
 	// Wallet permissions
 	PermissionWalletView = "wallet:view"
+
+	// Role management permissions
+	PermissionRolesManage = "roles:manage"
 )
 
+// UserPermissionsResponse represents the roles assigned to an account and the union of
+// their permissions
+type UserPermissionsResponse struct {
+	Roles       []Role   `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// AssignRoleRequest represents a request to assign a role to a user by name
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
 // Role constants
 const (
 	RoleAdministrator = "administrator"
@@ -0,0 +1,19 @@
+package models
+
+// SeedResult reports what a seeder run inserted, skipped and failed to insert
+type SeedResult struct {
+	Seeder   string   `json:"seeder"`
+	Inserted []string `json:"inserted"`
+	Skipped  []string `json:"skipped"`
+	Failed   []string `json:"failed"`
+}
+
+// NewSeedResult creates an empty result for the named seeder
+func NewSeedResult(seeder string) *SeedResult {
+	return &SeedResult{
+		Seeder:   seeder,
+		Inserted: []string{},
+		Skipped:  []string{},
+		Failed:   []string{},
+	}
+}
@@ -28,9 +28,9 @@ type SessionResponse struct {
 	IPAddress      *string   `json:"ip_address"`
 	UserAgent      *string   `json:"user_agent"`
 	IsActive       bool      `json:"is_active"`
-	LastActivityAt time.Time `json:"last_activity_at"`
-	CreatedAt      time.Time `json:"created_at"`
-	ExpiresAt      time.Time `json:"expires_at"`
+	LastActivityAt Timestamp `json:"last_activity_at"`
+	CreatedAt      Timestamp `json:"created_at"`
+	ExpiresAt      Timestamp `json:"expires_at"`
 	IsCurrent      bool      `json:"is_current"` // This will be set by the service
 }
 
@@ -44,9 +44,9 @@ func (s *UserSession) ToResponse() SessionResponse {
 		IPAddress:      s.IPAddress,
 		UserAgent:      s.UserAgent,
 		IsActive:       s.IsActive,
-		LastActivityAt: s.LastActivityAt,
-		CreatedAt:      s.CreatedAt,
-		ExpiresAt:      s.ExpiresAt,
+		LastActivityAt: NewTimestamp(s.LastActivityAt),
+		CreatedAt:      NewTimestamp(s.CreatedAt),
+		ExpiresAt:      NewTimestamp(s.ExpiresAt),
 	}
 }
 
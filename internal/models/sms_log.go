@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// SMSLogStatus represents the outcome of an SMS send
+type SMSLogStatus string
+
+const (
+	SMSLogStatusSent   SMSLogStatus = "sent"
+	SMSLogStatusFailed SMSLogStatus = "failed"
+)
+
+// SMSDeliveryState is the delivery outcome of an SMS the provider accepted
+type SMSDeliveryState string
+
+const (
+	SMSDeliveryPending   SMSDeliveryState = "pending"
+	SMSDeliveryDelivered SMSDeliveryState = "delivered"
+	SMSDeliveryFailed    SMSDeliveryState = "failed"
+)
+
+// SMSLog represents a single SMS handed to the provider
+type SMSLog struct {
+	ID                int               `json:"id" db:"id"`
+	Recipient         string            `json:"recipient" db:"recipient"`
+	MessageType       string            `json:"message_type" db:"message_type"`
+	Status            SMSLogStatus      `json:"status" db:"status"`
+	ProviderMessageID *int              `json:"provider_message_id,omitempty" db:"provider_message_id"`
+	ErrorMsg          *string           `json:"error_msg,omitempty" db:"error_msg"`
+	DeliveryStatus    *SMSDeliveryState `json:"delivery_status,omitempty" db:"delivery_status"`
+	DeliveryCheckedAt *time.Time        `json:"delivery_checked_at,omitempty" db:"delivery_checked_at"`
+	CreatedAt         time.Time         `json:"created_at" db:"created_at"`
+}
+
+// SMSDeliveryStatus represents the delivery status of a sent SMS as reported by the
+// provider. Recipient, MessageType and SentAt come from the send record and are empty
+// when the message was not recorded.
+type SMSDeliveryStatus struct {
+	MessageID      int              `json:"message_id"`
+	Delivery       SMSDeliveryState `json:"delivery"`
+	ProviderStatus int              `json:"provider_status"`
+	StatusText     string           `json:"status_text"`
+	Recipient      string           `json:"recipient,omitempty"`
+	MessageType    string           `json:"message_type,omitempty"`
+	SentAt         *Timestamp       `json:"sent_at,omitempty"`
+	CheckedAt      Timestamp        `json:"checked_at"`
+}
+
+// SMSUsageFilter represents the filters for SMS usage statistics
+type SMSUsageFilter struct {
+	MessageType string     `json:"message_type,omitempty"`
+	From        *time.Time `json:"from,omitempty"`
+	To          *time.Time `json:"to,omitempty"`
+	Page        int        `json:"page"`
+	PageSize    int        `json:"page_size"`
+}
+
+// SMSUsageCount represents the sent and failed counts for one message type
+type SMSUsageCount struct {
+	MessageType string `json:"message_type"`
+	Sent        int    `json:"sent"`
+	Failed      int    `json:"failed"`
+}
+
+// SMSUsageWindow represents SMS usage over a time window, in total and per message type
+type SMSUsageWindow struct {
+	Since  time.Time       `json:"since"`
+	Sent   int             `json:"sent"`
+	Failed int             `json:"failed"`
+	ByType []SMSUsageCount `json:"by_type"`
+}
+
+// SMSDailyUsage represents the SMS usage for one message type on one day
+type SMSDailyUsage struct {
+	Date        string `json:"date"` // YYYY-MM-DD
+	MessageType string `json:"message_type"`
+	Sent        int    `json:"sent"`
+	Failed      int    `json:"failed"`
+}
+
+// SMSUsageStatsResponse represents aggregate SMS usage statistics
+type SMSUsageStatsResponse struct {
+	Today       SMSUsageWindow  `json:"today"`
+	Last7Days   SMSUsageWindow  `json:"last_7_days"`
+	Last30Days  SMSUsageWindow  `json:"last_30_days"`
+	Daily       []SMSDailyUsage `json:"daily"`
+	Pagination  PaginationInfo  `json:"pagination"`
+	From        Timestamp       `json:"from"`
+	To          Timestamp       `json:"to"`
+	GeneratedAt Timestamp       `json:"generated_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// AdminOverviewResponse represents the aggregated admin dashboard statistics.
+// A metric is null when its query failed, so one failure doesn't hide the rest.
+type AdminOverviewResponse struct {
+	TotalUsers        *int      `json:"total_users"`
+	TotalGamenets     *int      `json:"total_gamenets"`
+	ActiveSessions    *int      `json:"active_sessions"`
+	SubscriptionPlans *int      `json:"subscription_plans"`
+	SMSSentToday      *int      `json:"sms_sent_today"`
+	RecentSignups     *int      `json:"recent_signups"` // users created in the last RecentSignupDays days
+	RecentSignupDays  int       `json:"recent_signup_days"`
+	GeneratedAt       Timestamp `json:"generated_at"`
+}
+
+// GamenetStatsResponse represents a gamenet operator's dashboard statistics, counted
+// over the users linked to that gamenet only. A metric is null when its query failed.
+type GamenetStatsResponse struct {
+	GamenetID          int       `json:"gamenet_id"`
+	LinkedUsers        *int      `json:"linked_users"`
+	NewUsersLast7Days  *int      `json:"new_users_last_7_days"`  // users linked in the last 7 days
+	NewUsersLast30Days *int      `json:"new_users_last_30_days"` // users linked in the last 30 days
+	ActiveUsers        *int      `json:"active_users"`           // linked users who logged in within ActiveUserDays days
+	ActiveUserDays     int       `json:"active_user_days"`
+	GeneratedAt        Timestamp `json:"generated_at"`
+}
@@ -23,17 +23,33 @@ type SubscriptionPlan struct {
 
 // UserSubscription represents a gamenet's current subscription
 type UserSubscription struct {
-	ID        int        `json:"id" db:"id"`
-	GamenetID int        `json:"gamenet_id" db:"gamenet_id"`
-	PlanID    int        `json:"plan_id" db:"plan_id"`
-	Status    string     `json:"status" db:"status"`
-	StartedAt time.Time  `json:"started_at" db:"started_at"`
-	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
-	AutoRenew bool       `json:"auto_renew" db:"auto_renew"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	ID        int `json:"id" db:"id"`
+	GamenetID int `json:"gamenet_id" db:"gamenet_id"`
+	PlanID    int `json:"plan_id" db:"plan_id"`
+	// SubscribedPrice is the plan price locked in for this subscription; later
+	// plan price changes only apply to new subscriptions
+	SubscribedPrice *float64   `json:"subscribed_price" db:"subscribed_price"`
+	Status          string     `json:"status" db:"status"`
+	StartedAt       time.Time  `json:"started_at" db:"started_at"`
+	ExpiresAt       *time.Time `json:"expires_at" db:"expires_at"`
+	AutoRenew       bool       `json:"auto_renew" db:"auto_renew"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// Subscription statuses
+const (
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusTrial     = "trial"
+	SubscriptionStatusCancelled = "cancelled"
+)
+
+// Subscription history actions
+const (
+	SubscriptionHistoryActionCreated   = "created"
+	SubscriptionHistoryActionCancelled = "cancelled"
+)
+
 // SubscriptionHistory represents subscription changes and payments
 type SubscriptionHistory struct {
 	ID               int       `json:"id" db:"id"`
@@ -48,6 +64,37 @@ type SubscriptionHistory struct {
 	CreatedAt        time.Time `json:"created_at" db:"created_at"`
 }
 
+// PlanHistory represents a recorded change to a subscription plan
+type PlanHistory struct {
+	ID            int           `json:"id" db:"id"`
+	PlanID        int           `json:"plan_id" db:"plan_id"`
+	Action        string        `json:"action" db:"action"`
+	ChangedByID   *int          `json:"changed_by_id" db:"changed_by_id"`
+	ChangedByType *string       `json:"changed_by_type" db:"changed_by_type"`
+	Before        *PlanResponse `json:"before" db:"before_data"`
+	After         *PlanResponse `json:"after" db:"after_data"`
+	// RepriceSubscriptions asks the update to move the plan's active subscriptions to
+	// the new price instead of locking in their current one
+	RepriceSubscriptions bool `json:"-" db:"-"`
+	// RepricedSubscriptions is how many subscriptions the change moved to the new price;
+	// it is only set for changes that repriced existing subscribers
+	RepricedSubscriptions *int64    `json:"repriced_subscriptions,omitempty" db:"repriced_subscriptions"`
+	CreatedAt             time.Time `json:"created_at" db:"created_at"`
+}
+
+// Plan history actions
+const (
+	PlanHistoryActionUpdate     = "update"
+	PlanHistoryActionActivate   = "activate"
+	PlanHistoryActionDeactivate = "deactivate"
+)
+
+// Actor identifies the authenticated account performing a change
+type Actor struct {
+	ID   int
+	Type string
+}
+
 // SubscriptionPayment represents a payment transaction
 type SubscriptionPayment struct {
 	ID               int              `json:"id" db:"id"`
@@ -116,29 +163,45 @@ type UpdatePlanRequest struct {
 
 // PlanResponse represents a plan response
 type PlanResponse struct {
-	ID                       int       `json:"id"`
-	Name                     string    `json:"name"`
-	PlanType                 string    `json:"plan_type"`
-	Price                    float64   `json:"price"`
-	AnnualDiscountPercentage *float64  `json:"annual_discount_percentage"`
-	TrialDurationDays        *int      `json:"trial_duration_days"`
-	IsActive                 bool      `json:"is_active"`
-	CreatedAt                time.Time `json:"created_at"`
-	UpdatedAt                time.Time `json:"updated_at"`
+	ID                       int      `json:"id"`
+	Name                     string   `json:"name"`
+	PlanType                 string   `json:"plan_type"`
+	Price                    float64  `json:"price"`
+	AnnualDiscountPercentage *float64 `json:"annual_discount_percentage"`
+	// EffectiveAnnualPrice is the price after the annual discount; it equals Price
+	// for plans that are not annual
+	EffectiveAnnualPrice float64 `json:"effective_annual_price"`
+	// MonthlyEquivalentPrice spreads the effective price of an annual plan over
+	// twelve months; it equals Price for other plans
+	MonthlyEquivalentPrice float64   `json:"monthly_equivalent_price"`
+	TrialDurationDays      *int      `json:"trial_duration_days"`
+	IsActive               bool      `json:"is_active"`
+	CreatedAt              Timestamp `json:"created_at"`
+	UpdatedAt              Timestamp `json:"updated_at"`
+}
+
+// PlanDeleteImpact describes what deleting a plan would affect
+type PlanDeleteImpact struct {
+	PlanID              int    `json:"plan_id"`
+	PlanName            string `json:"plan_name"`
+	ActiveSubscriptions int    `json:"active_subscriptions"`
+	CanDelete           bool   `json:"can_delete"`
+	Reason              string `json:"reason,omitempty"`
 }
 
 // SubscriptionResponse represents a subscription response
 type SubscriptionResponse struct {
-	ID        int           `json:"id"`
-	GamenetID int           `json:"gamenet_id"`
-	PlanID    int           `json:"plan_id"`
-	Plan      *PlanResponse `json:"plan,omitempty"`
-	Status    string        `json:"status"`
-	StartedAt time.Time     `json:"started_at"`
-	ExpiresAt *time.Time    `json:"expires_at"`
-	AutoRenew bool          `json:"auto_renew"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
+	ID              int           `json:"id"`
+	GamenetID       int           `json:"gamenet_id"`
+	PlanID          int           `json:"plan_id"`
+	Plan            *PlanResponse `json:"plan,omitempty"`
+	SubscribedPrice *float64      `json:"subscribed_price"`
+	Status          string        `json:"status"`
+	StartedAt       Timestamp     `json:"started_at"`
+	ExpiresAt       *Timestamp    `json:"expires_at"`
+	AutoRenew       bool          `json:"auto_renew"`
+	CreatedAt       Timestamp     `json:"created_at"`
+	UpdatedAt       Timestamp     `json:"updated_at"`
 }
 
 // CreateSubscriptionRequest represents a subscription creation request
@@ -182,25 +245,37 @@ func (sp *SubscriptionPlan) ToResponse() PlanResponse {
 		PlanType:                 sp.PlanType,
 		Price:                    sp.Price,
 		AnnualDiscountPercentage: sp.AnnualDiscountPercentage,
+		EffectiveAnnualPrice:     roundMoney(sp.GetEffectivePrice()),
+		MonthlyEquivalentPrice:   sp.monthlyEquivalentPrice(),
 		TrialDurationDays:        sp.TrialDurationDays,
 		IsActive:                 sp.IsActive,
-		CreatedAt:                sp.CreatedAt,
-		UpdatedAt:                sp.UpdatedAt,
+		CreatedAt:                NewTimestamp(sp.CreatedAt),
+		UpdatedAt:                NewTimestamp(sp.UpdatedAt),
 	}
 }
 
+// monthlyEquivalentPrice returns the discounted price of an annual plan per month,
+// or the price of any other plan
+func (sp *SubscriptionPlan) monthlyEquivalentPrice() float64 {
+	if sp.PlanType == "annual" {
+		return roundMoney(sp.GetEffectivePrice() / 12)
+	}
+	return roundMoney(sp.Price)
+}
+
 // ToResponse converts UserSubscription to SubscriptionResponse
 func (us *UserSubscription) ToResponse() SubscriptionResponse {
 	return SubscriptionResponse{
-		ID:        us.ID,
-		GamenetID: us.GamenetID,
-		PlanID:    us.PlanID,
-		Status:    us.Status,
-		StartedAt: us.StartedAt,
-		ExpiresAt: us.ExpiresAt,
-		AutoRenew: us.AutoRenew,
-		CreatedAt: us.CreatedAt,
-		UpdatedAt: us.UpdatedAt,
+		ID:              us.ID,
+		GamenetID:       us.GamenetID,
+		PlanID:          us.PlanID,
+		SubscribedPrice: us.SubscribedPrice,
+		Status:          us.Status,
+		StartedAt:       NewTimestamp(us.StartedAt),
+		ExpiresAt:       NewTimestampPtr(us.ExpiresAt),
+		AutoRenew:       us.AutoRenew,
+		CreatedAt:       NewTimestamp(us.CreatedAt),
+		UpdatedAt:       NewTimestamp(us.UpdatedAt),
 	}
 }
 
@@ -226,6 +301,25 @@ func (sp *SubscriptionPlan) GetEffectivePrice() float64 {
 	return sp.Price
 }
 
+// SubscriptionEndDate returns when a subscription to the plan started at start ends:
+// a month later for monthly plans, a year later for annual plans and after
+// TrialDurationDays for trials
+func (sp *SubscriptionPlan) SubscriptionEndDate(start time.Time) (time.Time, error) {
+	switch sp.PlanType {
+	case "monthly":
+		return start.AddDate(0, 1, 0), nil
+	case "annual":
+		return start.AddDate(1, 0, 0), nil
+	case "trial":
+		if sp.TrialDurationDays == nil || *sp.TrialDurationDays <= 0 {
+			return time.Time{}, errors.New("trial plan has no trial duration")
+		}
+		return start.AddDate(0, 0, *sp.TrialDurationDays), nil
+	default:
+		return time.Time{}, errors.New("unknown plan type: " + sp.PlanType)
+	}
+}
+
 // GetTrialEndDate calculates when the trial period ends
 func (us *UserSubscription) GetTrialEndDate() *time.Time {
 	if us.Status != "trial" {
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Timestamp is a time that always serializes as RFC3339 in UTC, whatever zone the
+// database or the server returned it in, so clients get unambiguous timestamps.
+// It embeds time.Time, so the usual time methods are available on it.
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp wraps t as a Timestamp
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// NewTimestampPtr wraps t as a Timestamp, keeping nil as nil
+func NewTimestampPtr(t *time.Time) *Timestamp {
+	if t == nil {
+		return nil
+	}
+	ts := NewTimestamp(*t)
+	return &ts
+}
+
+// MarshalJSON encodes the time as an RFC3339 string in UTC
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(time.RFC3339))
+}
+
+// UnmarshalJSON decodes an RFC3339 string; null leaves the time unchanged
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
@@ -1,53 +1,115 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
-
-	"golang.org/x/crypto/bcrypt"
 )
 
 // User represents a user in the system
 type User struct {
-	ID          int        `json:"id" db:"id"`
-	Name        string     `json:"name" db:"name"`
-	Mobile      string     `json:"mobile" db:"mobile"`
-	Email       string     `json:"email" db:"email"`
-	Password    string     `json:"-" db:"password"` // Hidden from JSON
-	Image       *string    `json:"image" db:"image"`
-	Balance     float64    `json:"balance" db:"balance"`
-	Debt        float64    `json:"debt" db:"debt"`
-	LastLoginAt *time.Time `json:"last_login_at" db:"last_login_at"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID          int          `json:"id" db:"id"`
+	Name        string       `json:"name" db:"name"`
+	Mobile      string       `json:"mobile" db:"mobile"`
+	Email       string       `json:"email" db:"email"`
+	Password    PasswordHash `json:"-" db:"password"` // Hidden from JSON
+	Image       *string      `json:"image" db:"image"`
+	Balance     float64      `json:"balance" db:"balance"`
+	Debt        float64      `json:"debt" db:"debt"`
+	LastLoginAt *time.Time   `json:"last_login_at" db:"last_login_at"`
+	SuspendedAt *time.Time   `json:"suspended_at" db:"suspended_at"`
+	DeletedAt   *time.Time   `json:"deleted_at,omitempty" db:"deleted_at"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
 }
 
 // Admin represents an admin in the system
 type Admin struct {
-	ID          int        `json:"id" db:"id"`
-	Name        string     `json:"name" db:"name"`
-	Mobile      string     `json:"mobile" db:"mobile"`
-	Email       string     `json:"email" db:"email"`
-	Password    string     `json:"-" db:"password"` // Hidden from JSON
-	Image       *string    `json:"image" db:"image"`
-	LastLoginAt *time.Time `json:"last_login_at" db:"last_login_at"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-}
-
-// LoginRequest represents a login request
+	ID            int          `json:"id" db:"id"`
+	Name          string       `json:"name" db:"name"`
+	Mobile        string       `json:"mobile" db:"mobile"`
+	Email         string       `json:"email" db:"email"`
+	Password      PasswordHash `json:"-" db:"password"` // Hidden from JSON
+	Image         *string      `json:"image" db:"image"`
+	LastLoginAt   *time.Time   `json:"last_login_at" db:"last_login_at"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at" db:"updated_at"`
+	TOTPSecret    *string      `json:"-" db:"totp_secret"`     // Set when two-factor setup starts
+	TOTPEnabledAt *time.Time   `json:"-" db:"totp_enabled_at"` // Set once a code confirms the secret
+}
+
+// LoginRequest represents a login request. Email may also hold a user's mobile
+// number (e.g. 09123456789); admins and gamenets log in by email only.
 type LoginRequest struct {
-	Email      string `json:"email" binding:"required,email"`
+	Email      string `json:"email" binding:"required,max=255"`
 	Password   string `json:"password" binding:"required,min=6"`
 	RememberMe bool   `json:"remember_me"`
 }
 
-// LoginResponse represents a login response
+// LoginRequestV2 represents a v2 login request, which describes the client
+// device in the body instead of the X-Device-Info header
+type LoginRequestV2 struct {
+	LoginRequest
+	Device *DeviceInfo `json:"device,omitempty"`
+}
+
+// DeviceInfo describes the client device a login comes from
+type DeviceInfo struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Platform   string `json:"platform,omitempty"`
+	OSVersion  string `json:"os_version,omitempty"`
+	AppVersion string `json:"app_version,omitempty"`
+}
+
+// String returns the device description stored with the session
+func (d *DeviceInfo) String() string {
+	if d == nil {
+		return ""
+	}
+	data, err := json.Marshal(d)
+	if err != nil || string(data) == "{}" {
+		return ""
+	}
+	return string(data)
+}
+
+// LoginStatusTwoFactorRequired is the login status of an account that must still
+// confirm a TOTP code before it is issued a token
+const LoginStatusTwoFactorRequired = "2fa_required"
+
+// LoginResponse represents a login response. When Status is
+// LoginStatusTwoFactorRequired, only ChallengeToken and ExpiresAt (the challenge's
+// expiry) are set.
 type LoginResponse struct {
-	Token       string      `json:"token"`
-	UserType    string      `json:"user_type"`
-	User        interface{} `json:"user"`
-	Permissions []string    `json:"permissions"`
-	ExpiresAt   time.Time   `json:"expires_at"`
+	Token          string      `json:"token"`
+	UserType       string      `json:"user_type"`
+	User           interface{} `json:"user"`
+	Permissions    []string    `json:"permissions"`
+	ExpiresAt      Timestamp   `json:"expires_at"`
+	Status         string      `json:"status,omitempty"`
+	ChallengeToken string      `json:"challenge_token,omitempty"`
+}
+
+// TwoFactorRequired reports whether the login still needs a TOTP code
+func (r *LoginResponse) TwoFactorRequired() bool {
+	return r.Status == LoginStatusTwoFactorRequired
+}
+
+// TOTPSetupResponse carries a new TOTP secret, to be added to an authenticator app
+type TOTPSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+}
+
+// TOTPCodeRequest represents a request confirming a TOTP code
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// TOTPVerifyRequest represents the second step of a two-factor login
+type TOTPVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required,len=6,numeric"`
 }
 
 // UserResponse represents a user response without sensitive data
@@ -59,9 +121,11 @@ type UserResponse struct {
 	Image       *string    `json:"image"`
 	Balance     float64    `json:"balance"`
 	Debt        float64    `json:"debt"`
-	LastLoginAt *time.Time `json:"last_login_at"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	LastLoginAt *Timestamp `json:"last_login_at"`
+	SuspendedAt *Timestamp `json:"suspended_at"`
+	DeletedAt   *Timestamp `json:"deleted_at,omitempty"`
+	CreatedAt   Timestamp  `json:"created_at"`
+	UpdatedAt   Timestamp  `json:"updated_at"`
 }
 
 // AdminResponse represents an admin response without sensitive data
@@ -71,9 +135,10 @@ type AdminResponse struct {
 	Mobile      string     `json:"mobile"`
 	Email       string     `json:"email"`
 	Image       *string    `json:"image"`
-	LastLoginAt *time.Time `json:"last_login_at"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	TOTPEnabled bool       `json:"totp_enabled"`
+	LastLoginAt *Timestamp `json:"last_login_at"`
+	CreatedAt   Timestamp  `json:"created_at"`
+	UpdatedAt   Timestamp  `json:"updated_at"`
 }
 
 // ProfileResponse represents a profile response with permissions
@@ -93,12 +158,24 @@ func (u *User) ToResponse() UserResponse {
 		Image:       u.Image,
 		Balance:     u.Balance,
 		Debt:        u.Debt,
-		LastLoginAt: u.LastLoginAt,
-		CreatedAt:   u.CreatedAt,
-		UpdatedAt:   u.UpdatedAt,
+		LastLoginAt: NewTimestampPtr(u.LastLoginAt),
+		SuspendedAt: NewTimestampPtr(u.SuspendedAt),
+		DeletedAt:   NewTimestampPtr(u.DeletedAt),
+		CreatedAt:   NewTimestamp(u.CreatedAt),
+		UpdatedAt:   NewTimestamp(u.UpdatedAt),
 	}
 }
 
+// IsDeleted reports whether the user has been soft-deleted
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// IsSuspended reports whether the user's account is suspended
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
 // ToResponse converts Admin to AdminResponse
 func (a *Admin) ToResponse() AdminResponse {
 	return AdminResponse{
@@ -107,22 +184,64 @@ func (a *Admin) ToResponse() AdminResponse {
 		Mobile:      a.Mobile,
 		Email:       a.Email,
 		Image:       a.Image,
-		LastLoginAt: a.LastLoginAt,
-		CreatedAt:   a.CreatedAt,
-		UpdatedAt:   a.UpdatedAt,
+		TOTPEnabled: a.TOTPEnabled(),
+		LastLoginAt: NewTimestampPtr(a.LastLoginAt),
+		CreatedAt:   NewTimestamp(a.CreatedAt),
+		UpdatedAt:   NewTimestamp(a.UpdatedAt),
 	}
 }
 
-// HashPassword hashes a password using bcrypt
+// TOTPEnabled reports whether the admin must confirm a TOTP code to log in
+func (a *Admin) TOTPEnabled() bool {
+	return a.TOTPEnabledAt != nil && a.TOTPSecret != nil
+}
+
+// PasswordHash holds a stored password hash. It never serializes its value, so a
+// model returned directly (instead of via ToResponse) or logged can't leak it.
+type PasswordHash string
+
+// redactedPasswordHash is what a PasswordHash prints as
+const redactedPasswordHash = "[REDACTED]"
+
+// MarshalJSON always encodes the hash as null
+func (PasswordHash) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+// MarshalText always encodes the hash as an empty string
+func (PasswordHash) MarshalText() ([]byte, error) {
+	return []byte{}, nil
+}
+
+// String redacts the hash when formatted with %s/%v
+func (PasswordHash) String() string {
+	return redactedPasswordHash
+}
+
+// GoString redacts the hash when formatted with %#v
+func (PasswordHash) GoString() string {
+	return redactedPasswordHash
+}
+
+// HashPassword hashes a password using the configured password hasher. Passwords
+// longer than MaxPasswordBytes are rejected with ErrPasswordTooLong.
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+	if len(password) > MaxPasswordBytes {
+		return "", ErrPasswordTooLong
+	}
+	return GetPasswordHasher().Hash(password)
 }
 
-// CheckPassword checks if the provided password matches the hash
+// CheckPassword checks if the provided password matches the hash, whichever
+// supported algorithm produced it. The comparison is constant-time. Passwords longer
+// than MaxPasswordBytes are checked too, since they may have been set before the limit.
 func CheckPassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	for _, hasher := range knownHashers() {
+		if hasher.Matches(hash) {
+			return hasher.Verify(password, hash)
+		}
+	}
+	return false
 }
 
 // PasswordResetToken represents a password reset token
@@ -145,15 +264,39 @@ type ForgotPasswordRequest struct {
 type ResetPasswordRequest struct {
 	Token           string `json:"token" binding:"required"`
 	Email           string `json:"email" binding:"required,email"`
-	NewPassword     string `json:"new_password" binding:"required,min=6"`
-	ConfirmPassword string `json:"confirm_password" binding:"required,min=6"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
+// PasswordResetLinkResponse represents a password reset link issued by an admin
+type PasswordResetLinkResponse struct {
+	UserID    int       `json:"user_id"`
+	ResetLink string    `json:"reset_link"`
+	ExpiresAt Timestamp `json:"expires_at"`
 }
 
 // ChangePasswordRequest represents a change password request
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
-	NewPassword     string `json:"new_password" binding:"required,min=6"`
-	ConfirmPassword string `json:"confirm_password" binding:"required,min=6"`
+	NewPassword     string `json:"new_password" binding:"required"`
+	ConfirmPassword string `json:"confirm_password" binding:"required"`
+}
+
+// ChangeEmailRequest represents a single-step email change request confirmed by password
+type ChangeEmailRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewEmail        string `json:"new_email" binding:"required,email"`
+}
+
+// VerifyPasswordRequest represents a password confirmation for step-up re-authentication
+type VerifyPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// ReauthResponse represents the re-authentication token issued after a password confirmation
+type ReauthResponse struct {
+	ReauthToken string    `json:"reauth_token"`
+	ExpiresAt   Timestamp `json:"expires_at"`
 }
 
 // IsExpired checks if the token is expired
@@ -186,11 +329,101 @@ type UserUpdateRequest struct {
 	Image  *string `json:"image,omitempty"`
 }
 
-// UserSearchRequest represents a search request for users
+// RecentlyActiveUser is the minimal view of a user returned by the recently-active listing
+type RecentlyActiveUser struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	LastLoginAt Timestamp `json:"last_login_at"`
+}
+
+// Bulk actions that can be applied to users
+const (
+	UserBulkActionDelete     = "delete"
+	UserBulkActionSuspend    = "suspend"
+	UserBulkActionReactivate = "reactivate"
+)
+
+// UserBulkActionRequest represents a request to apply one action to many users
+type UserBulkActionRequest struct {
+	UserIDs []int  `json:"user_ids" binding:"required,min=1"`
+	Action  string `json:"action" binding:"required,oneof=delete suspend reactivate"`
+}
+
+// UserBulkActionResult is the outcome of a bulk action for a single user
+type UserBulkActionResult struct {
+	UserID  int    `json:"user_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// UserBulkActionResponse reports the per-user results of a bulk action
+type UserBulkActionResponse struct {
+	Action    string                 `json:"action"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []UserBulkActionResult `json:"results"`
+}
+
+// UserBatchCreateResult is the outcome of a batch create for a single row. Rows are
+// only created when every row succeeds.
+type UserBatchCreateResult struct {
+	Index   int           `json:"index"`
+	Success bool          `json:"success"`
+	User    *UserResponse `json:"user,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// UserBatchCreateResponse reports the per-row results of a batch create
+type UserBatchCreateResponse struct {
+	Created int                     `json:"created"`
+	Results []UserBatchCreateResult `json:"results"`
+}
+
+// MarkRolledBack explains to rows without an error of their own that they were not
+// created because another row failed
+func (r *UserBatchCreateResponse) MarkRolledBack() *UserBatchCreateResponse {
+	for i := range r.Results {
+		if r.Results[i].Error == "" {
+			r.Results[i].Error = "not created because another row failed"
+		}
+	}
+	return r
+}
+
+// UserImportLine reports a CSV import line that was skipped or failed. Line numbers
+// count the header as line 1.
+type UserImportLine struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// UserImportResponse summarizes a CSV import. Each line is imported on its own, so
+// failed lines do not affect the others.
+type UserImportResponse struct {
+	Created int              `json:"created"`
+	Skipped []UserImportLine `json:"skipped"`
+	Errored []UserImportLine `json:"errored"`
+}
+
+// UserSearchRequest represents a search request for users. The balance, debt and
+// last login filters are optional and only apply to users, not admins.
 type UserSearchRequest struct {
-	Query    string `json:"query"`
-	Page     int    `json:"page"`
-	PageSize int    `json:"page_size"`
+	Query      string   `json:"query"`
+	Page       int      `json:"page"`
+	PageSize   int      `json:"page_size"`
+	MinBalance *float64 `json:"min_balance,omitempty"`
+	MaxBalance *float64 `json:"max_balance,omitempty"`
+	MinDebt    *float64 `json:"min_debt,omitempty"`
+	// HasDebt keeps users with a debt above zero when true, and without one when false
+	HasDebt *bool `json:"has_debt,omitempty"`
+	// LastLoginBefore keeps users who have not logged in since, including those who never did
+	LastLoginBefore *time.Time `json:"last_login_before,omitempty"`
+}
+
+// AdminSearchResponse represents a search response for admins
+type AdminSearchResponse struct {
+	Data       []AdminResponse `json:"data"`
+	Pagination PaginationInfo  `json:"pagination"`
 }
 
 // UserSearchResponse represents a search response for users
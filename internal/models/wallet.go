@@ -0,0 +1,79 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrInsufficientBalance is returned when a debit would overdraw a wallet that may not go into debt
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// WalletTransactionType is the direction of a wallet transaction
+type WalletTransactionType string
+
+const (
+	WalletTransactionCredit WalletTransactionType = "credit"
+	WalletTransactionDebit  WalletTransactionType = "debit"
+)
+
+// WalletTransaction is one change to a user's wallet, with the balance and debt it left behind
+type WalletTransaction struct {
+	ID           int                   `json:"id" db:"id"`
+	UserID       int                   `json:"user_id" db:"user_id"`
+	Type         WalletTransactionType `json:"type" db:"type"`
+	Amount       float64               `json:"amount" db:"amount"`
+	BalanceAfter float64               `json:"balance_after" db:"balance_after"`
+	DebtAfter    float64               `json:"debt_after" db:"debt_after"`
+	Reason       string                `json:"reason" db:"reason"`
+	CreatedAt    time.Time             `json:"created_at" db:"created_at"`
+}
+
+// WalletBalance is a user's current balance and debt
+type WalletBalance struct {
+	UserID  int     `json:"user_id"`
+	Balance float64 `json:"balance"`
+	Debt    float64 `json:"debt"`
+}
+
+// Credit adds amount to the wallet. Any debt is paid off first and only the rest
+// goes to the balance.
+func (w *WalletBalance) Credit(amount float64) {
+	repaid := math.Min(w.Debt, amount)
+	w.Debt = roundMoney(w.Debt - repaid)
+	w.Balance = roundMoney(w.Balance + amount - repaid)
+}
+
+// Debit takes amount from the balance. When the balance does not cover it, the
+// shortfall becomes debt if allowDebt is set, otherwise ErrInsufficientBalance is
+// returned and the wallet is left unchanged.
+func (w *WalletBalance) Debit(amount float64, allowDebt bool) error {
+	if amount > w.Balance && !allowDebt {
+		return ErrInsufficientBalance
+	}
+
+	shortfall := math.Max(amount-w.Balance, 0)
+	w.Balance = roundMoney(w.Balance - amount + shortfall)
+	w.Debt = roundMoney(w.Debt + shortfall)
+	return nil
+}
+
+// roundMoney rounds an amount to the two decimals the balance columns store
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// WalletTransactionFilter represents the filters for listing a user's wallet transactions
+type WalletTransactionFilter struct {
+	Type     WalletTransactionType `json:"type,omitempty"`
+	From     *time.Time            `json:"from,omitempty"`
+	To       *time.Time            `json:"to,omitempty"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"page_size"`
+}
+
+// WalletTransactionListResponse represents a paginated list of wallet transactions
+type WalletTransactionListResponse struct {
+	Data       []WalletTransaction `json:"data"`
+	Pagination PaginationInfo      `json:"pagination"`
+}
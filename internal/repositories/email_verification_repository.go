@@ -8,6 +8,14 @@ import (
 	"time"
 )
 
+// EmailVerificationRepositoryInterface defines the interface for email verification code operations
+type EmailVerificationRepositoryInterface interface {
+	StoreCode(userID int, userType, email, code string, expiresAt time.Time) error
+	ReserveCode(userID int, userType, email, code string, expiresAt time.Time, cooldown time.Duration) (time.Duration, error)
+	VerifyCode(userID int, userType, email, code string) (bool, error)
+	CleanupExpiredCodes() error
+}
+
 // EmailVerificationRepository handles email verification code operations
 type EmailVerificationRepository struct {
 	db *sql.DB
@@ -87,6 +95,70 @@ func (r *EmailVerificationRepository) VerifyCode(userID int, userType, email, co
 	return true, nil
 }
 
+// ReserveCode stores a verification code like StoreCode, unless the user was sent a code,
+// to any email address, less than cooldown ago. The check and the insert run in one
+// transaction holding a lock on the user's send record, so concurrent requests can't
+// both pass the check. When a code was sent too recently nothing is stored and the
+// remaining wait is returned.
+func (r *EmailVerificationRepository) ReserveCode(userID int, userType, email, code string, expiresAt time.Time, cooldown time.Duration) (time.Duration, error) {
+	now := time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Make sure the row exists so that concurrent first requests lock the same row
+	_, err = tx.Exec(`
+		INSERT INTO email_verification_sends (user_id, user_type)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE user_id = user_id
+	`, userID, userType)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create verification send record: %w", err)
+	}
+
+	var lastSentAt sql.NullTime
+	err = tx.QueryRow(`
+		SELECT last_sent_at FROM email_verification_sends
+		WHERE user_id = ? AND user_type = ? FOR UPDATE
+	`, userID, userType).Scan(&lastSentAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get verification send record: %w", err)
+	}
+
+	if lastSentAt.Valid {
+		if wait := lastSentAt.Time.Add(cooldown).Sub(now); wait > 0 {
+			return wait, nil
+		}
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE email_verification_sends SET last_sent_at = ?
+		WHERE user_id = ? AND user_type = ?
+	`, now, userID, userType); err != nil {
+		return 0, fmt.Errorf("failed to update verification send record: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM email_verification_codes WHERE user_id = ? AND user_type = ? AND email = ?`,
+		userID, userType, email); err != nil {
+		return 0, fmt.Errorf("failed to delete existing codes: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+		INSERT INTO email_verification_codes (user_id, user_type, email, code, expires_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, userID, userType, email, r.hashCode(code), expiresAt); err != nil {
+		return 0, fmt.Errorf("failed to store verification code: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return 0, nil
+}
+
 // DeleteCode deletes a specific verification code by ID
 func (r *EmailVerificationRepository) DeleteCode(id int) error {
 	query := `DELETE FROM email_verification_codes WHERE id = ?`
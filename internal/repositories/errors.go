@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrNotFound is matched (via errors.Is) by every error a repository returns
+// when a lookup, update or delete finds no matching row
+var ErrNotFound = errors.New("not found")
+
+// ErrDuplicate is matched (via errors.Is) by every error a repository returns
+// when a write violates a unique index
+var ErrDuplicate = errors.New("already exists")
+
+// mysqlDuplicateEntry is the MySQL error number for a unique index violation
+const mysqlDuplicateEntry = 1062
+
+// isDuplicateEntry reports whether err is a MySQL unique index violation
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
+
+// notFoundError reads "<entity> not found" and matches ErrNotFound
+type notFoundError struct {
+	entity string
+}
+
+func (e *notFoundError) Error() string {
+	return e.entity + " not found"
+}
+
+func (e *notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// notFound returns the not-found error for an entity, e.g. notFound("user")
+func notFound(entity string) error {
+	return &notFoundError{entity: entity}
+}
+
+// BatchRowError reports the row of a batch insert that failed; the whole batch is
+// rolled back
+type BatchRowError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchRowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchRowError) Unwrap() error {
+	return e.Err
+}
@@ -97,7 +97,7 @@ func (r *gamenetRepository) GetByID(id int) (*models.Gamenet, error) {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("gamenet not found")
+			return nil, notFound("gamenet")
 		}
 		return nil, fmt.Errorf("failed to get gamenet: %w", err)
 	}
@@ -130,7 +130,7 @@ func (r *gamenetRepository) GetByEmail(email string) (*models.Gamenet, error) {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("gamenet not found")
+			return nil, notFound("gamenet")
 		}
 		return nil, fmt.Errorf("failed to get gamenet: %w", err)
 	}
@@ -250,7 +250,7 @@ func (r *gamenetRepository) Delete(id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("gamenet not found")
+		return notFound("gamenet")
 	}
 
 	return nil
@@ -0,0 +1,371 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// LoginAuditRepositoryInterface defines the interface for login activity data operations
+type LoginAuditRepositoryInterface interface {
+	RecordSuccessfulLogin(userID int, userType string, ipAddress, userAgent, deviceInfo *string) error
+	RecordFailedLogin(email string, ipAddress, userAgent, deviceInfo *string, reason string) error
+	ListLoginEvents(filter *models.LoginEventFilter) (*models.LoginEventListResponse, error)
+	ExportLoginEvents(filter *models.LoginEventFilter, limit int) ([]models.LoginEvent, error)
+	ListLoginAttempts(filter *models.LoginAttemptFilter) (*models.LoginAttemptListResponse, error)
+	CountLoginAttemptsBy(filter *models.LoginAttemptFilter, groupBy string, limit int) ([]models.LoginAttemptCount, error)
+}
+
+// LoginAuditRepository implements LoginAuditRepositoryInterface
+type LoginAuditRepository struct {
+	db *sql.DB
+}
+
+// NewLoginAuditRepository creates a new login audit repository
+func NewLoginAuditRepository(db *sql.DB) LoginAuditRepositoryInterface {
+	return &LoginAuditRepository{db: db}
+}
+
+// loginEventsQuery combines successful logins and failed attempts into a single
+// derived table so both can be filtered and paginated together. Failed attempts are
+// attributed to the account type the typed identifier belongs to, checked in the same
+// order as login looks accounts up, and have no user type when it matches none.
+const loginEventsQuery = `
+	SELECT id, source, success, user_id, user_type, email, ip_address, user_agent, device_info, reason, created_at
+	FROM (
+		SELECT l.id, 'login' AS source, TRUE AS success, l.user_id, l.user_type,
+		       COALESCE(u.email, a.email, g.email) AS email,
+		       l.ip_address, l.user_agent, l.device_info, NULL AS reason, l.created_at
+		FROM successful_logins l
+		LEFT JOIN users u ON l.user_type = 'user' AND u.id = l.user_id
+		LEFT JOIN admins a ON l.user_type = 'admin' AND a.id = l.user_id
+		LEFT JOIN gamenets g ON l.user_type = 'gamenet' AND g.id = l.user_id
+		UNION ALL
+		SELECT f.id, 'attempt' AS source, FALSE AS success, NULL AS user_id,
+		       CASE
+		           WHEN EXISTS (SELECT 1 FROM users WHERE (email = f.email OR mobile = f.email) AND deleted_at IS NULL) THEN 'user'
+		           WHEN EXISTS (SELECT 1 FROM admins WHERE email = f.email) THEN 'admin'
+		           WHEN EXISTS (SELECT 1 FROM gamenets WHERE email = f.email) THEN 'gamenet'
+		       END AS user_type,
+		       f.email, f.ip_address, f.user_agent, f.device_info, f.reason, f.created_at
+		FROM login_attempts f
+	) AS login_events
+`
+
+// RecordSuccessfulLogin stores a successful login of an account
+func (r *LoginAuditRepository) RecordSuccessfulLogin(userID int, userType string, ipAddress, userAgent, deviceInfo *string) error {
+	query := `
+		INSERT INTO successful_logins (user_id, user_type, ip_address, user_agent, device_info)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, userID, userType, ipAddress, userAgent, deviceInfo)
+	if err != nil {
+		return fmt.Errorf("failed to record successful login: %w", err)
+	}
+
+	return nil
+}
+
+// RecordFailedLogin stores an unsuccessful login attempt with its reason category
+func (r *LoginAuditRepository) RecordFailedLogin(email string, ipAddress, userAgent, deviceInfo *string, reason string) error {
+	query := `
+		INSERT INTO login_attempts (email, ip_address, user_agent, device_info, reason)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, email, ipAddress, userAgent, deviceInfo, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record failed login: %w", err)
+	}
+
+	return nil
+}
+
+// ListLoginEvents retrieves a filtered, paginated page of login events
+func (r *LoginAuditRepository) ListLoginEvents(filter *models.LoginEventFilter) (*models.LoginEventListResponse, error) {
+	// Set default values
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+	if filter.PageSize > 100 {
+		filter.PageSize = 100
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	whereClause, args := buildLoginEventsWhere(filter)
+
+	// Count total items
+	countQuery := `SELECT COUNT(*) FROM (` + loginEventsQuery + whereClause + `) AS filtered`
+	var totalItems int64
+	if err := r.db.QueryRow(countQuery, args...).Scan(&totalItems); err != nil {
+		return nil, fmt.Errorf("failed to count login events: %w", err)
+	}
+
+	// Calculate pagination info
+	totalPages := int((totalItems + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+	hasNext := filter.Page < totalPages
+	hasPrev := filter.Page > 1
+
+	dataQuery := loginEventsQuery + whereClause + ` ORDER BY created_at DESC, source, id DESC LIMIT ? OFFSET ?`
+	events, err := r.queryLoginEvents(dataQuery, append(args, filter.PageSize, offset)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginEventListResponse{
+		Data: events,
+		Pagination: models.PaginationInfo{
+			CurrentPage: filter.Page,
+			PageSize:    filter.PageSize,
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			HasNext:     hasNext,
+			HasPrev:     hasPrev,
+		},
+	}, nil
+}
+
+// ExportLoginEvents retrieves up to limit login events matching the filter, newest first
+func (r *LoginAuditRepository) ExportLoginEvents(filter *models.LoginEventFilter, limit int) ([]models.LoginEvent, error) {
+	whereClause, args := buildLoginEventsWhere(filter)
+	dataQuery := loginEventsQuery + whereClause + ` ORDER BY created_at DESC, source, id DESC LIMIT ?`
+	return r.queryLoginEvents(dataQuery, append(args, limit)...)
+}
+
+// queryLoginEvents runs a login events query and scans the results
+func (r *LoginAuditRepository) queryLoginEvents(query string, args ...interface{}) ([]models.LoginEvent, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query login events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.LoginEvent{}
+	for rows.Next() {
+		var event models.LoginEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.Source,
+			&event.Success,
+			&event.UserID,
+			&event.UserType,
+			&event.Email,
+			&event.IPAddress,
+			&event.UserAgent,
+			&event.DeviceInfo,
+			&event.Reason,
+			&event.CreatedAt.Time,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan login event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating login events: %w", err)
+	}
+
+	return events, nil
+}
+
+// buildLoginEventsWhere builds the WHERE clause for the login event filters
+func buildLoginEventsWhere(filter *models.LoginEventFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Success != nil {
+		conditions = append(conditions, "success = ?")
+		args = append(args, *filter.Success)
+	}
+	if filter.UserType != "" {
+		conditions = append(conditions, "user_type = ?")
+		args = append(args, filter.UserType)
+	}
+	if filter.IPAddress != "" {
+		conditions = append(conditions, "ip_address = ?")
+		args = append(args, filter.IPAddress)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// loginAttemptGroupColumns maps the supported group-by fields to their columns
+var loginAttemptGroupColumns = map[string]string{
+	models.LoginAttemptGroupByEmail:  "email",
+	models.LoginAttemptGroupByIP:     "ip_address",
+	models.LoginAttemptGroupByReason: "reason",
+}
+
+// ListLoginAttempts retrieves a filtered, paginated page of failed login attempts
+func (r *LoginAuditRepository) ListLoginAttempts(filter *models.LoginAttemptFilter) (*models.LoginAttemptListResponse, error) {
+	// Set default values
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+	if filter.PageSize > 100 {
+		filter.PageSize = 100
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	whereClause, args := buildLoginAttemptsWhere(filter)
+
+	// Count total items
+	var totalItems int64
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM login_attempts"+whereClause, args...).Scan(&totalItems); err != nil {
+		return nil, fmt.Errorf("failed to count login attempts: %w", err)
+	}
+
+	// Calculate pagination info
+	totalPages := int((totalItems + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+	hasNext := filter.Page < totalPages
+	hasPrev := filter.Page > 1
+
+	query := `
+		SELECT id, email, ip_address, user_agent, device_info, reason, created_at
+		FROM login_attempts` + whereClause + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.Query(query, append(args, filter.PageSize, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query login attempts: %w", err)
+	}
+	defer rows.Close()
+
+	attempts := []models.LoginAttempt{}
+	for rows.Next() {
+		var attempt models.LoginAttempt
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.Email,
+			&attempt.IPAddress,
+			&attempt.UserAgent,
+			&attempt.DeviceInfo,
+			&attempt.Reason,
+			&attempt.CreatedAt.Time,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan login attempt: %w", err)
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating login attempts: %w", err)
+	}
+
+	return &models.LoginAttemptListResponse{
+		Data: attempts,
+		Pagination: models.PaginationInfo{
+			CurrentPage: filter.Page,
+			PageSize:    filter.PageSize,
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			HasNext:     hasNext,
+			HasPrev:     hasPrev,
+		},
+	}, nil
+}
+
+// CountLoginAttemptsBy counts the login attempts matching the filter per value of
+// groupBy (one of the models.LoginAttemptGroupBy fields) and returns the limit most
+// frequent values, e.g. the most attacked accounts or the busiest source IPs
+func (r *LoginAuditRepository) CountLoginAttemptsBy(filter *models.LoginAttemptFilter, groupBy string, limit int) ([]models.LoginAttemptCount, error) {
+	column, ok := loginAttemptGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported login attempt grouping: %s", groupBy)
+	}
+
+	whereClause, args := buildLoginAttemptsWhere(filter)
+	// Attempts without an IP address cannot be attributed to a source
+	if column == "ip_address" {
+		if whereClause == "" {
+			whereClause = " WHERE ip_address IS NOT NULL"
+		} else {
+			whereClause += " AND ip_address IS NOT NULL"
+		}
+	}
+
+	query := `
+		SELECT ` + column + `, COUNT(*) AS attempts, MAX(created_at)
+		FROM login_attempts` + whereClause + `
+		GROUP BY ` + column + `
+		ORDER BY attempts DESC, ` + column + `
+		LIMIT ?
+	`
+	rows, err := r.db.Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count login attempts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.LoginAttemptCount{}
+	for rows.Next() {
+		var count models.LoginAttemptCount
+		if err := rows.Scan(&count.Value, &count.Count, &count.LastSeen.Time); err != nil {
+			return nil, fmt.Errorf("failed to scan login attempt count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating login attempt counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// buildLoginAttemptsWhere builds the WHERE clause for the login attempt filters
+func buildLoginAttemptsWhere(filter *models.LoginAttemptFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Reason != "" {
+		conditions = append(conditions, "reason = ?")
+		args = append(args, filter.Reason)
+	}
+	if filter.Email != "" {
+		conditions = append(conditions, "email = ?")
+		args = append(args, filter.Email)
+	}
+	if filter.IPAddress != "" {
+		conditions = append(conditions, "ip_address = ?")
+		args = append(args, filter.IPAddress)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// LoginLockoutRepositoryInterface defines the interface for the failed login counters.
+// It follows utils.AttemptLimiter, but the counts live in the database so they survive
+// restarts and every instance behind a load balancer shares them.
+type LoginLockoutRepositoryInterface interface {
+	ReserveAttempt(identifier string, maxAttempts int, window, lockout time.Duration) (bool, time.Duration, error)
+	ReleaseAttempt(identifier string) error
+	ResetAttempts(identifier string) error
+	CleanupExpiredLockouts() error
+}
+
+// LoginLockoutRepository implements LoginLockoutRepositoryInterface
+type LoginLockoutRepository struct {
+	db *sql.DB
+}
+
+// NewLoginLockoutRepository creates a new login lockout repository
+func NewLoginLockoutRepository(db *sql.DB) LoginLockoutRepositoryInterface {
+	return &LoginLockoutRepository{db: db}
+}
+
+// ReserveAttempt checks whether the identifier may make another login attempt and, if it
+// may, counts the attempt as failed in the same transaction, so concurrent logins on any
+// instance can't all pass the check. maxAttempts failures within window lock the
+// identifier for lockout from the last failure (until the window ends when lockout is
+// zero). When the identifier may not attempt, it also returns how long until it may.
+func (r *LoginLockoutRepository) ReserveAttempt(identifier string, maxAttempts int, window, lockout time.Duration) (bool, time.Duration, error) {
+	now := time.Now()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Make sure the row exists so that concurrent first attempts lock the same row
+	_, err = tx.Exec(`
+		INSERT INTO login_lockouts (identifier, failed_count, window_ends_at)
+		VALUES (?, 0, ?)
+		ON DUPLICATE KEY UPDATE identifier = identifier
+	`, identifier, now.Add(window))
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to create login lockout: %w", err)
+	}
+
+	var failedCount int
+	var windowEndsAt time.Time
+	var lockedUntil sql.NullTime
+	err = tx.QueryRow(`
+		SELECT failed_count, window_ends_at, locked_until
+		FROM login_lockouts WHERE identifier = ? FOR UPDATE
+	`, identifier).Scan(&failedCount, &windowEndsAt, &lockedUntil)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to get login lockout: %w", err)
+	}
+
+	resetAt := windowEndsAt
+	if lockedUntil.Valid {
+		resetAt = lockedUntil.Time
+	}
+	if !now.Before(resetAt) {
+		// The earlier failures have expired
+		failedCount = 0
+		windowEndsAt = now.Add(window)
+		lockedUntil = sql.NullTime{}
+	} else if failedCount >= maxAttempts {
+		return false, resetAt.Sub(now), nil
+	}
+
+	failedCount++
+	if failedCount >= maxAttempts && lockout > 0 {
+		lockedUntil = sql.NullTime{Time: now.Add(lockout), Valid: true}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE login_lockouts SET failed_count = ?, window_ends_at = ?, locked_until = ?
+		WHERE identifier = ?
+	`, failedCount, windowEndsAt, lockedUntil, identifier)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to update login lockout: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, 0, nil
+}
+
+// ReleaseAttempt gives back an attempt taken by ReserveAttempt that neither succeeded
+// nor failed, e.g. because the account could not be looked up
+func (r *LoginLockoutRepository) ReleaseAttempt(identifier string) error {
+	query := `
+		UPDATE login_lockouts SET failed_count = failed_count - 1, locked_until = NULL
+		WHERE identifier = ? AND failed_count > 0
+	`
+
+	if _, err := r.db.Exec(query, identifier); err != nil {
+		return fmt.Errorf("failed to release login attempt: %w", err)
+	}
+	return nil
+}
+
+// ResetAttempts clears the failures counted for the identifier after a successful login
+func (r *LoginLockoutRepository) ResetAttempts(identifier string) error {
+	query := `DELETE FROM login_lockouts WHERE identifier = ?`
+
+	if _, err := r.db.Exec(query, identifier); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// CleanupExpiredLockouts removes counters whose window and lockout have both ended
+func (r *LoginLockoutRepository) CleanupExpiredLockouts() error {
+	query := `DELETE FROM login_lockouts WHERE COALESCE(locked_until, window_ends_at) < ?`
+
+	if _, err := r.db.Exec(query, time.Now()); err != nil {
+		return fmt.Errorf("failed to cleanup login lockouts: %w", err)
+	}
+	return nil
+}
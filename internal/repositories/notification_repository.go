@@ -17,6 +17,7 @@ type NotificationRepository interface {
 	Delete(id int) error
 	GetPendingNotifications(limit int) ([]*models.Notification, error)
 	GetFailedNotifications(limit int) ([]*models.Notification, error)
+	GetRetryableNotifications(limit int) ([]*models.Notification, error)
 }
 
 // MySQLNotificationRepository implements NotificationRepository for MySQL
@@ -33,10 +34,10 @@ func NewMySQLNotificationRepository(db *sql.DB) *MySQLNotificationRepository {
 func (r *MySQLNotificationRepository) Create(notification *models.Notification) error {
 	query := `
 		INSERT INTO notifications (
-			type, status, priority, recipient, subject, content, 
-			template_id, template_data, metadata, scheduled_at, 
+			type, status, priority, recipient, related_user_id, related_user_type,
+			subject, content, html_content, template_id, template_data, metadata, scheduled_at, 
 			retry_count, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	templateDataJSON, _ := json.Marshal(notification.TemplateData)
@@ -48,8 +49,11 @@ func (r *MySQLNotificationRepository) Create(notification *models.Notification)
 		notification.Status,
 		notification.Priority,
 		notification.Recipient,
+		notification.RelatedUserID,
+		notification.RelatedUserType,
 		notification.Subject,
 		notification.Content,
+		notification.HTMLContent,
 		notification.TemplateID,
 		templateDataJSON,
 		metadataJSON,
@@ -75,7 +79,8 @@ func (r *MySQLNotificationRepository) Create(notification *models.Notification)
 // GetByID retrieves a notification by ID
 func (r *MySQLNotificationRepository) GetByID(id int) (*models.Notification, error) {
 	query := `
-		SELECT id, type, status, priority, recipient, subject, content,
+		SELECT id, type, status, priority, recipient, related_user_id, related_user_type,
+			   subject, content, COALESCE(html_content, ''),
 			   template_id, template_data, metadata, scheduled_at, sent_at,
 			   error_msg, retry_count, created_at, updated_at
 		FROM notifications WHERE id = ?
@@ -90,8 +95,11 @@ func (r *MySQLNotificationRepository) GetByID(id int) (*models.Notification, err
 		&notification.Status,
 		&notification.Priority,
 		&notification.Recipient,
+		&notification.RelatedUserID,
+		&notification.RelatedUserType,
 		&notification.Subject,
 		&notification.Content,
+		&notification.HTMLContent,
 		&notification.TemplateID,
 		&templateDataJSON,
 		&metadataJSON,
@@ -105,7 +113,7 @@ func (r *MySQLNotificationRepository) GetByID(id int) (*models.Notification, err
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("notification not found")
+			return nil, notFound("notification")
 		}
 		return nil, fmt.Errorf("failed to get notification: %w", err)
 	}
@@ -129,7 +137,8 @@ func (r *MySQLNotificationRepository) GetByID(id int) (*models.Notification, err
 // GetWithFilters retrieves notifications with optional filters
 func (r *MySQLNotificationRepository) GetWithFilters(filters map[string]interface{}) ([]*models.Notification, error) {
 	query := `
-		SELECT id, type, status, priority, recipient, subject, content,
+		SELECT id, type, status, priority, recipient, related_user_id, related_user_type,
+			   subject, content, COALESCE(html_content, ''),
 			   template_id, template_data, metadata, scheduled_at, sent_at,
 			   error_msg, retry_count, created_at, updated_at
 		FROM notifications
@@ -159,6 +168,22 @@ func (r *MySQLNotificationRepository) GetWithFilters(filters map[string]interfac
 		args = append(args, priority)
 	}
 
+	if relatedUserID, ok := filters["related_user_id"]; ok {
+		whereClauses = append(whereClauses, "related_user_id = ?")
+		args = append(args, relatedUserID)
+	}
+
+	if relatedUserType, ok := filters["related_user_type"]; ok {
+		whereClauses = append(whereClauses, "related_user_type = ?")
+		args = append(args, relatedUserType)
+	}
+
+	// Keyset pagination: only rows that sort after the cursor
+	if cursor, ok := filters["cursor"].(*models.NotificationCursor); ok && cursor != nil {
+		whereClauses = append(whereClauses, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
 	// Add WHERE clause if filters exist
 	if len(whereClauses) > 0 {
 		query += " WHERE " + fmt.Sprintf("%s", whereClauses[0])
@@ -167,8 +192,12 @@ func (r *MySQLNotificationRepository) GetWithFilters(filters map[string]interfac
 		}
 	}
 
-	// Add ordering and limit
-	query += " ORDER BY created_at DESC"
+	// Add ordering and limit; id breaks ties so pages are stable
+	if oldestFirst, _ := filters["oldest_first"].(bool); oldestFirst {
+		query += " ORDER BY updated_at ASC, id ASC"
+	} else {
+		query += " ORDER BY created_at DESC, id DESC"
+	}
 
 	if limit, ok := filters["limit"]; ok {
 		if limitInt, ok := limit.(int); ok && limitInt > 0 {
@@ -194,8 +223,11 @@ func (r *MySQLNotificationRepository) GetWithFilters(filters map[string]interfac
 			&notification.Status,
 			&notification.Priority,
 			&notification.Recipient,
+			&notification.RelatedUserID,
+			&notification.RelatedUserType,
 			&notification.Subject,
 			&notification.Content,
+			&notification.HTMLContent,
 			&notification.TemplateID,
 			&templateDataJSON,
 			&metadataJSON,
@@ -239,7 +271,7 @@ func (r *MySQLNotificationRepository) Update(notification *models.Notification)
 	query := `
 		UPDATE notifications SET
 			type = ?, status = ?, priority = ?, recipient = ?, subject = ?, content = ?,
-			template_id = ?, template_data = ?, metadata = ?, scheduled_at = ?, sent_at = ?,
+			html_content = ?, template_id = ?, template_data = ?, metadata = ?, scheduled_at = ?, sent_at = ?,
 			error_msg = ?, retry_count = ?, updated_at = ?
 		WHERE id = ?
 	`
@@ -255,6 +287,7 @@ func (r *MySQLNotificationRepository) Update(notification *models.Notification)
 		notification.Recipient,
 		notification.Subject,
 		notification.Content,
+		notification.HTMLContent,
 		notification.TemplateID,
 		templateDataJSON,
 		metadataJSON,
@@ -300,3 +333,13 @@ func (r *MySQLNotificationRepository) GetFailedNotifications(limit int) ([]*mode
 	}
 	return r.GetWithFilters(filters)
 }
+
+// GetRetryableNotifications retrieves failed notifications, the longest untouched first
+func (r *MySQLNotificationRepository) GetRetryableNotifications(limit int) ([]*models.Notification, error) {
+	filters := map[string]interface{}{
+		"status":       models.NotificationStatusFailed,
+		"oldest_first": true,
+		"limit":        limit,
+	}
+	return r.GetWithFilters(filters)
+}
@@ -66,7 +66,7 @@ func (r *PasswordResetRepository) GetTokenByToken(token string) (*models.Passwor
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("password reset token not found")
+			return nil, notFound("password reset token")
 		}
 		return nil, fmt.Errorf("failed to get password reset token: %w", err)
 	}
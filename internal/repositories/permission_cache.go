@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+)
+
+// CachedPermissionRepository caches permission checks in memory for a TTL, so the
+// permission middleware doesn't query the database on every request. Denials are
+// cached as well; lookup errors are not. Assigning or removing a role through it
+// drops the account's cached checks, and InvalidateRole must be called when a role's
+// permissions change. The cache is per process, so other instances see the change
+// once their entries expire.
+type CachedPermissionRepository struct {
+	PermissionRepositoryInterface
+	ttl time.Duration
+
+	mu    sync.Mutex
+	roles map[string]map[string]permissionCacheEntry
+	users map[permissionCacheAccount]map[string]permissionCacheEntry
+}
+
+// permissionCacheAccount identifies the account a cached permission check belongs to
+type permissionCacheAccount struct {
+	userID   int
+	userType string
+}
+
+// permissionCacheEntry is a cached permission check result
+type permissionCacheEntry struct {
+	allowed   bool
+	expiresAt time.Time
+}
+
+// fresh reports whether the entry has not expired yet
+func (e permissionCacheEntry) fresh() bool {
+	return time.Now().Before(e.expiresAt)
+}
+
+// NewCachedPermissionRepository wraps a permission repository with a cache whose
+// entries live for ttl
+func NewCachedPermissionRepository(repo PermissionRepositoryInterface, ttl time.Duration) *CachedPermissionRepository {
+	return &CachedPermissionRepository{
+		PermissionRepositoryInterface: repo,
+		ttl:                           ttl,
+		roles:                         make(map[string]map[string]permissionCacheEntry),
+		users:                         make(map[permissionCacheAccount]map[string]permissionCacheEntry),
+	}
+}
+
+// HasPermission checks if a role has a specific permission, using the cached result
+// while it is fresh
+func (r *CachedPermissionRepository) HasPermission(roleType, resource, action string) (bool, error) {
+	key := resource + ":" + action
+	r.mu.Lock()
+	entry, ok := r.roles[roleType][key]
+	r.mu.Unlock()
+	if ok && entry.fresh() {
+		return entry.allowed, nil
+	}
+
+	allowed, err := r.PermissionRepositoryInterface.HasPermission(roleType, resource, action)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.roles[roleType] == nil {
+		r.roles[roleType] = make(map[string]permissionCacheEntry)
+	}
+	r.roles[roleType][key] = permissionCacheEntry{allowed: allowed, expiresAt: time.Now().Add(r.ttl)}
+	return allowed, nil
+}
+
+// HasUserPermission checks if an account has a specific permission through its roles,
+// using the cached result while it is fresh
+func (r *CachedPermissionRepository) HasUserPermission(userID int, userType, resource, action string) (bool, error) {
+	account := permissionCacheAccount{userID: userID, userType: userType}
+	key := resource + ":" + action
+	r.mu.Lock()
+	entry, ok := r.users[account][key]
+	r.mu.Unlock()
+	if ok && entry.fresh() {
+		return entry.allowed, nil
+	}
+
+	allowed, err := r.PermissionRepositoryInterface.HasUserPermission(userID, userType, resource, action)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users[account] == nil {
+		r.users[account] = make(map[string]permissionCacheEntry)
+	}
+	r.users[account][key] = permissionCacheEntry{allowed: allowed, expiresAt: time.Now().Add(r.ttl)}
+	return allowed, nil
+}
+
+// AssignRoleToUser assigns a role and drops the account's cached permission checks
+func (r *CachedPermissionRepository) AssignRoleToUser(userID int, userType string, roleName string) error {
+	defer r.InvalidateUser(userID, userType)
+	return r.PermissionRepositoryInterface.AssignRoleToUser(userID, userType, roleName)
+}
+
+// RemoveRoleFromUser removes a role and drops the account's cached permission checks
+func (r *CachedPermissionRepository) RemoveRoleFromUser(userID int, userType string, roleName string) error {
+	defer r.InvalidateUser(userID, userType)
+	return r.PermissionRepositoryInterface.RemoveRoleFromUser(userID, userType, roleName)
+}
+
+// InvalidateRole drops the cached checks for a role. Account checks are resolved
+// through roles, so they are all dropped as well.
+func (r *CachedPermissionRepository) InvalidateRole(roleName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.roles, roleName)
+	r.users = make(map[permissionCacheAccount]map[string]permissionCacheEntry)
+}
+
+// InvalidateUser drops the cached checks for an account
+func (r *CachedPermissionRepository) InvalidateUser(userID int, userType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, permissionCacheAccount{userID: userID, userType: userType})
+}
@@ -142,7 +142,7 @@ func (r *PermissionRepository) GetRoleByName(roleName string) (*models.Role, err
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("role not found: %s", roleName)
+			return nil, notFound("role")
 		}
 		return nil, fmt.Errorf("failed to get role: %w", err)
 	}
@@ -342,7 +342,7 @@ func (r *PermissionRepository) RemoveRoleFromUser(userID int, userType string, r
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("role assignment not found")
+		return notFound("role assignment")
 	}
 
 	return nil
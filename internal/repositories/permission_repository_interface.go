@@ -8,6 +8,7 @@ import (
 type PermissionRepositoryInterface interface {
 	GetPermissionsByRole(roleType string) ([]models.Permission, error)
 	HasPermission(roleType, resource, action string) (bool, error)
+	HasUserPermission(userID int, userType, resource, action string) (bool, error)
 	GetRoleWithPermissions(roleType string) (*models.RoleWithPermissions, error)
 	GetRoleByName(roleName string) (*models.Role, error)
 	GetAllRoles() ([]models.Role, error)
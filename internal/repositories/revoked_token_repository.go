@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RevokedTokenRepositoryInterface defines the interface for the access token blacklist
+type RevokedTokenRepositoryInterface interface {
+	RevokeToken(jti string, expiresAt time.Time) error
+	IsTokenRevoked(jti string) (bool, error)
+	CleanupExpiredTokens() error
+}
+
+// RevokedTokenRepository implements RevokedTokenRepositoryInterface
+type RevokedTokenRepository struct {
+	db *sql.DB
+}
+
+// NewRevokedTokenRepository creates a new revoked token repository
+func NewRevokedTokenRepository(db *sql.DB) RevokedTokenRepositoryInterface {
+	return &RevokedTokenRepository{db: db}
+}
+
+// RevokeToken blacklists the token with the given jti until it expires. Revoking a
+// token twice is not an error.
+func (r *RevokedTokenRepository) RevokeToken(jti string, expiresAt time.Time) error {
+	query := `INSERT IGNORE INTO revoked_tokens (jti, expires_at) VALUES (?, ?)`
+
+	if _, err := r.db.Exec(query, jti, expiresAt); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked reports whether the token with the given jti has been revoked
+func (r *RevokedTokenRepository) IsTokenRevoked(jti string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = ?)`
+
+	var revoked bool
+	if err := r.db.QueryRow(query, jti).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("failed to check revoked token: %w", err)
+	}
+	return revoked, nil
+}
+
+// CleanupExpiredTokens removes revoked tokens past their expiry, which the token
+// validation rejects on its own
+func (r *RevokedTokenRepository) CleanupExpiredTokens() error {
+	query := `DELETE FROM revoked_tokens WHERE expires_at < NOW()`
+
+	if _, err := r.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to cleanup revoked tokens: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/gatehide/gatehide-api/internal/models"
@@ -11,10 +12,12 @@ import (
 type SessionRepositoryInterface interface {
 	CreateSession(userID int, userType, sessionToken string, deviceInfo, ipAddress, userAgent *string, expiresAt time.Time) (*models.UserSession, error)
 	GetSessionByToken(sessionToken string) (*models.UserSession, error)
+	GetSessionByID(sessionID int) (*models.UserSession, error)
 	GetActiveSessionsByUserID(userID int, userType string) ([]models.UserSession, error)
 	UpdateSessionActivity(sessionID int) error
+	RenewSession(sessionID int, sessionToken string, ipAddress, userAgent *string, expiresAt time.Time) error
 	DeactivateSession(sessionID int) error
-	DeactivateAllUserSessions(userID int, userType string) error
+	DeactivateAllUserSessions(userID int, userType string) (int, error)
 	DeactivateAllOtherUserSessions(userID int, userType string, currentSessionToken string) error
 	CleanupExpiredSessions() error
 	DeleteSession(sessionID int) error
@@ -94,6 +97,40 @@ func (r *SessionRepository) GetSessionByToken(sessionToken string) (*models.User
 	return &session, nil
 }
 
+// GetSessionByID retrieves a session by its ID
+func (r *SessionRepository) GetSessionByID(sessionID int) (*models.UserSession, error) {
+	query := `
+		SELECT id, user_id, user_type, session_token, device_info, ip_address, user_agent, 
+		       is_active, last_activity_at, created_at, expires_at
+		FROM user_sessions 
+		WHERE id = ?
+	`
+
+	var session models.UserSession
+	err := r.db.QueryRow(query, sessionID).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.UserType,
+		&session.SessionToken,
+		&session.DeviceInfo,
+		&session.IPAddress,
+		&session.UserAgent,
+		&session.IsActive,
+		&session.LastActivityAt,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
 // GetActiveSessionsByUserID retrieves all active sessions for a user
 func (r *SessionRepository) GetActiveSessionsByUserID(userID int, userType string) ([]models.UserSession, error) {
 	query := `
@@ -147,6 +184,30 @@ func (r *SessionRepository) UpdateSessionActivity(sessionID int) error {
 	return err
 }
 
+// RenewSession replaces the token of an existing active session and extends its expiry
+func (r *SessionRepository) RenewSession(sessionID int, sessionToken string, ipAddress, userAgent *string, expiresAt time.Time) error {
+	query := `
+		UPDATE user_sessions 
+		SET session_token = ?, ip_address = ?, user_agent = ?, expires_at = ?, last_activity_at = NOW() 
+		WHERE id = ? AND is_active = TRUE
+	`
+
+	result, err := r.db.Exec(query, sessionToken, ipAddress, userAgent, expiresAt, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to renew session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return notFound("session")
+	}
+
+	return nil
+}
+
 // DeactivateSession deactivates a specific session
 func (r *SessionRepository) DeactivateSession(sessionID int) error {
 	query := `
@@ -159,16 +220,25 @@ func (r *SessionRepository) DeactivateSession(sessionID int) error {
 	return err
 }
 
-// DeactivateAllUserSessions deactivates all sessions for a user
-func (r *SessionRepository) DeactivateAllUserSessions(userID int, userType string) error {
+// DeactivateAllUserSessions deactivates all sessions for a user and returns how many were active
+func (r *SessionRepository) DeactivateAllUserSessions(userID int, userType string) (int, error) {
 	query := `
 		UPDATE user_sessions 
 		SET is_active = FALSE 
-		WHERE user_id = ? AND user_type = ?
+		WHERE user_id = ? AND user_type = ? AND is_active = TRUE
 	`
 
-	_, err := r.db.Exec(query, userID, userType)
-	return err
+	result, err := r.db.Exec(query, userID, userType)
+	if err != nil {
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(affected), nil
 }
 
 // DeactivateAllOtherUserSessions deactivates all sessions for a user except the current one
@@ -0,0 +1,209 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// SMSLogRepositoryInterface defines the interface for SMS send records
+type SMSLogRepositoryInterface interface {
+	Create(log *models.SMSLog) error
+	GetByProviderMessageID(messageID int) (*models.SMSLog, error)
+	UpdateDeliveryStatus(id int, status models.SMSDeliveryState, checkedAt time.Time) error
+	CountByType(since time.Time, messageType string) ([]models.SMSUsageCount, error)
+	ListDailyUsage(filter *models.SMSUsageFilter) ([]models.SMSDailyUsage, int64, error)
+}
+
+// SMSLogRepository implements SMSLogRepositoryInterface
+type SMSLogRepository struct {
+	db *sql.DB
+}
+
+// NewSMSLogRepository creates a new SMS log repository
+func NewSMSLogRepository(db *sql.DB) SMSLogRepositoryInterface {
+	return &SMSLogRepository{db: db}
+}
+
+// Create records a single SMS send
+func (r *SMSLogRepository) Create(log *models.SMSLog) error {
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO sms_logs (recipient, message_type, status, provider_message_id, error_msg, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := r.db.Exec(query, log.Recipient, log.MessageType, log.Status, log.ProviderMessageID, log.ErrorMsg, log.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create sms log: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get sms log ID: %w", err)
+	}
+	log.ID = int(id)
+
+	return nil
+}
+
+// GetByProviderMessageID returns the latest send recorded with the given provider message ID
+func (r *SMSLogRepository) GetByProviderMessageID(messageID int) (*models.SMSLog, error) {
+	query := `
+		SELECT id, recipient, message_type, status, provider_message_id, error_msg,
+		       delivery_status, delivery_checked_at, created_at
+		FROM sms_logs
+		WHERE provider_message_id = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	log := &models.SMSLog{}
+	err := r.db.QueryRow(query, messageID).Scan(
+		&log.ID,
+		&log.Recipient,
+		&log.MessageType,
+		&log.Status,
+		&log.ProviderMessageID,
+		&log.ErrorMsg,
+		&log.DeliveryStatus,
+		&log.DeliveryCheckedAt,
+		&log.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, notFound("sms log")
+		}
+		return nil, fmt.Errorf("failed to get sms log: %w", err)
+	}
+
+	return log, nil
+}
+
+// UpdateDeliveryStatus stores the delivery status last reported by the provider for a send
+func (r *SMSLogRepository) UpdateDeliveryStatus(id int, status models.SMSDeliveryState, checkedAt time.Time) error {
+	query := `UPDATE sms_logs SET delivery_status = ?, delivery_checked_at = ? WHERE id = ?`
+
+	result, err := r.db.Exec(query, status, checkedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update sms delivery status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return notFound("sms log")
+	}
+
+	return nil
+}
+
+// CountByType returns the sent and failed counts per message type since the given time.
+// An empty messageType counts every type.
+func (r *SMSLogRepository) CountByType(since time.Time, messageType string) ([]models.SMSUsageCount, error) {
+	whereClause, args := buildSMSUsageWhere(&models.SMSUsageFilter{MessageType: messageType, From: &since})
+	query := `
+		SELECT message_type,
+		       COALESCE(SUM(status = 'sent'), 0),
+		       COALESCE(SUM(status = 'failed'), 0)
+		FROM sms_logs` + whereClause + `
+		GROUP BY message_type
+		ORDER BY message_type
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sms logs: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.SMSUsageCount{}
+	for rows.Next() {
+		var count models.SMSUsageCount
+		if err := rows.Scan(&count.MessageType, &count.Sent, &count.Failed); err != nil {
+			return nil, fmt.Errorf("failed to scan sms usage count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sms usage counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ListDailyUsage returns a paginated page of per-day, per-type usage matching the filter,
+// newest day first, along with the total number of rows
+func (r *SMSLogRepository) ListDailyUsage(filter *models.SMSUsageFilter) ([]models.SMSDailyUsage, int64, error) {
+	whereClause, args := buildSMSUsageWhere(filter)
+	groupedQuery := `
+		SELECT DATE_FORMAT(created_at, '%Y-%m-%d') AS day, message_type,
+		       COALESCE(SUM(status = 'sent'), 0) AS sent,
+		       COALESCE(SUM(status = 'failed'), 0) AS failed
+		FROM sms_logs` + whereClause + `
+		GROUP BY day, message_type
+	`
+
+	var totalItems int64
+	countQuery := `SELECT COUNT(*) FROM (` + groupedQuery + `) AS daily_usage`
+	if err := r.db.QueryRow(countQuery, args...).Scan(&totalItems); err != nil {
+		return nil, 0, fmt.Errorf("failed to count daily sms usage: %w", err)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	dataQuery := groupedQuery + ` ORDER BY day DESC, message_type LIMIT ? OFFSET ?`
+	rows, err := r.db.Query(dataQuery, append(args, filter.PageSize, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query daily sms usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []models.SMSDailyUsage{}
+	for rows.Next() {
+		var day models.SMSDailyUsage
+		if err := rows.Scan(&day.Date, &day.MessageType, &day.Sent, &day.Failed); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan daily sms usage: %w", err)
+		}
+		usage = append(usage, day)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating daily sms usage: %w", err)
+	}
+
+	return usage, totalItems, nil
+}
+
+// buildSMSUsageWhere builds the WHERE clause for the SMS usage filters
+func buildSMSUsageWhere(filter *models.SMSUsageFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.MessageType != "" {
+		conditions = append(conditions, "message_type = ?")
+		args = append(args, filter.MessageType)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StatsRepositoryInterface defines aggregate count queries used by dashboards
+type StatsRepositoryInterface interface {
+	CountUsers() (int, error)
+	CountGamenets() (int, error)
+	CountActiveSessions() (int, error)
+	CountSubscriptionPlans() (int, error)
+	CountSMSSentSince(since time.Time) (int, error)
+	CountUsersCreatedSince(since time.Time) (int, error)
+	GamenetExists(gamenetID int) (bool, error)
+	CountGamenetUsers(gamenetID int) (int, error)
+	CountGamenetUsersLinkedSince(gamenetID int, since time.Time) (int, error)
+	CountGamenetUsersActiveSince(gamenetID int, since time.Time) (int, error)
+}
+
+// StatsRepository implements StatsRepositoryInterface
+type StatsRepository struct {
+	db *sql.DB
+}
+
+// NewStatsRepository creates a new stats repository
+func NewStatsRepository(db *sql.DB) StatsRepositoryInterface {
+	return &StatsRepository{db: db}
+}
+
+// CountUsers returns the total number of users
+func (r *StatsRepository) CountUsers() (int, error) {
+	return r.count("SELECT COUNT(*) FROM users WHERE deleted_at IS NULL")
+}
+
+// CountGamenets returns the total number of gamenets
+func (r *StatsRepository) CountGamenets() (int, error) {
+	return r.count("SELECT COUNT(*) FROM gamenets")
+}
+
+// CountActiveSessions returns the number of active, non-expired sessions
+func (r *StatsRepository) CountActiveSessions() (int, error) {
+	return r.count("SELECT COUNT(*) FROM user_sessions WHERE is_active = TRUE AND expires_at > NOW()")
+}
+
+// CountSubscriptionPlans returns the total number of subscription plans
+func (r *StatsRepository) CountSubscriptionPlans() (int, error) {
+	return r.count("SELECT COUNT(*) FROM subscription_plans")
+}
+
+// CountSMSSentSince returns the number of SMS notifications sent since the given time
+func (r *StatsRepository) CountSMSSentSince(since time.Time) (int, error) {
+	return r.count("SELECT COUNT(*) FROM notifications WHERE type = 'sms' AND status = 'sent' AND sent_at >= ?", since)
+}
+
+// CountUsersCreatedSince returns the number of users created since the given time
+func (r *StatsRepository) CountUsersCreatedSince(since time.Time) (int, error) {
+	return r.count("SELECT COUNT(*) FROM users WHERE created_at >= ? AND deleted_at IS NULL", since)
+}
+
+// GamenetExists reports whether a gamenet with the given ID exists
+func (r *StatsRepository) GamenetExists(gamenetID int) (bool, error) {
+	count, err := r.count("SELECT COUNT(*) FROM gamenets WHERE id = ?", gamenetID)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountGamenetUsers returns the number of users linked to a gamenet
+func (r *StatsRepository) CountGamenetUsers(gamenetID int) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM users_gamenets ug
+		INNER JOIN users u ON u.id = ug.user_id
+		WHERE ug.gamenet_id = ? AND u.deleted_at IS NULL`
+	return r.count(query, gamenetID)
+}
+
+// CountGamenetUsersLinkedSince returns the number of users linked to a gamenet since the given time
+func (r *StatsRepository) CountGamenetUsersLinkedSince(gamenetID int, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM users_gamenets ug
+		INNER JOIN users u ON u.id = ug.user_id
+		WHERE ug.gamenet_id = ? AND ug.created_at >= ? AND u.deleted_at IS NULL`
+	return r.count(query, gamenetID, since)
+}
+
+// CountGamenetUsersActiveSince returns the number of a gamenet's users who logged in since the given time
+func (r *StatsRepository) CountGamenetUsersActiveSince(gamenetID int, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM users u
+		INNER JOIN users_gamenets ug ON u.id = ug.user_id
+		WHERE ug.gamenet_id = ? AND u.last_login_at >= ? AND u.deleted_at IS NULL`
+	return r.count(query, gamenetID, since)
+}
+
+// count runs a single-value COUNT query
+func (r *StatsRepository) count(query string, args ...interface{}) (int, error) {
+	var count int
+	if err := r.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count: %w", err)
+	}
+	return count, nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/gatehide/gatehide-api/internal/models"
@@ -12,10 +13,13 @@ type SubscriptionPlanRepositoryInterface interface {
 	Create(plan *models.SubscriptionPlan) error
 	GetByID(id int) (*models.SubscriptionPlan, error)
 	GetAll(limit, offset int, isActive *bool) ([]*models.SubscriptionPlan, error)
-	Update(id int, plan *models.SubscriptionPlan) error
+	Update(id int, plan *models.SubscriptionPlan, history *models.PlanHistory) error
+	GetHistory(planID int) ([]*models.PlanHistory, error)
 	Delete(id int) error
 	Count(isActive *bool) (int, error)
 	HasActiveSubscriptions(planID int) (bool, error)
+	CountActiveSubscriptions(planID int) (int, error)
+	ExistsByName(name string) (bool, error)
 }
 
 // SubscriptionPlanRepository handles subscription plan database operations
@@ -28,7 +32,8 @@ func NewSubscriptionPlanRepository(db *sql.DB) *SubscriptionPlanRepository {
 	return &SubscriptionPlanRepository{db: db}
 }
 
-// Create creates a new subscription plan
+// Create creates a new subscription plan. A name another plan already uses is
+// reported as ErrDuplicate.
 func (r *SubscriptionPlanRepository) Create(plan *models.SubscriptionPlan) error {
 	query := `
 		INSERT INTO subscription_plans (
@@ -46,6 +51,9 @@ func (r *SubscriptionPlanRepository) Create(plan *models.SubscriptionPlan) error
 		plan.IsActive,
 	)
 
+	if isDuplicateEntry(err) {
+		return fmt.Errorf("subscription plan %q %w", plan.Name, ErrDuplicate)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to create subscription plan: %w", err)
 	}
@@ -87,7 +95,7 @@ func (r *SubscriptionPlanRepository) GetByID(id int) (*models.SubscriptionPlan,
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("subscription plan not found")
+			return nil, notFound("subscription plan")
 		}
 		return nil, fmt.Errorf("failed to get subscription plan: %w", err)
 	}
@@ -152,8 +160,27 @@ func (r *SubscriptionPlanRepository) GetAll(limit, offset int, isActive *bool) (
 	return plans, nil
 }
 
-// Update updates an existing subscription plan
-func (r *SubscriptionPlanRepository) Update(id int, plan *models.SubscriptionPlan) error {
+// Update updates an existing subscription plan. When history is provided it is
+// recorded in the same transaction so the change log can't diverge from the plan.
+// Active subscriptions keep their subscribed price unless history asks to reprice
+// them, in which case they move to the new price within the same transaction and the
+// number repriced is stored on the history entry.
+func (r *SubscriptionPlanRepository) Update(id int, plan *models.SubscriptionPlan, history *models.PlanHistory) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	reprice := history != nil && history.RepriceSubscriptions
+	if !reprice {
+		// Lock in the current price for active subscriptions before the plan changes,
+		// so a new price only applies to new subscriptions
+		if err := r.lockSubscribedPrices(tx, id); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE subscription_plans 
 		SET name = ?, plan_type = ?, price = ?, annual_discount_percentage = ?, 
@@ -161,7 +188,7 @@ func (r *SubscriptionPlanRepository) Update(id int, plan *models.SubscriptionPla
 		WHERE id = ?
 	`
 
-	result, err := r.db.Exec(query,
+	result, err := tx.Exec(query,
 		plan.Name,
 		plan.PlanType,
 		plan.Price,
@@ -171,6 +198,9 @@ func (r *SubscriptionPlanRepository) Update(id int, plan *models.SubscriptionPla
 		id,
 	)
 
+	if isDuplicateEntry(err) {
+		return fmt.Errorf("subscription plan %q %w", plan.Name, ErrDuplicate)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update subscription plan: %w", err)
 	}
@@ -181,12 +211,150 @@ func (r *SubscriptionPlanRepository) Update(id int, plan *models.SubscriptionPla
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("subscription plan not found")
+		return notFound("subscription plan")
+	}
+
+	if reprice {
+		repriced, err := r.repriceSubscriptions(tx, id, plan.Price)
+		if err != nil {
+			return err
+		}
+		history.RepricedSubscriptions = &repriced
+	}
+
+	if history != nil {
+		if err := r.insertHistory(tx, id, history); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	return nil
 }
 
+// lockSubscribedPrices stores the plan's current price on active and trial
+// subscriptions that do not have a subscribed price yet
+func (r *SubscriptionPlanRepository) lockSubscribedPrices(tx *sql.Tx, planID int) error {
+	query := `
+		UPDATE user_subscriptions us
+		JOIN subscription_plans sp ON sp.id = us.plan_id
+		SET us.subscribed_price = sp.price
+		WHERE us.plan_id = ? AND us.status IN ('active', 'trial') AND us.subscribed_price IS NULL
+	`
+
+	if _, err := tx.Exec(query, planID); err != nil {
+		return fmt.Errorf("failed to lock subscribed prices: %w", err)
+	}
+
+	return nil
+}
+
+// repriceSubscriptions moves the active and trial subscriptions of a plan to the
+// given price within a transaction and returns how many subscriptions were changed
+func (r *SubscriptionPlanRepository) repriceSubscriptions(tx *sql.Tx, planID int, price float64) (int64, error) {
+	query := `
+		UPDATE user_subscriptions
+		SET subscribed_price = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE plan_id = ? AND status IN ('active', 'trial')
+	`
+
+	result, err := tx.Exec(query, price, planID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reprice subscriptions: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+// insertHistory records a plan history entry within a transaction
+func (r *SubscriptionPlanRepository) insertHistory(tx *sql.Tx, planID int, history *models.PlanHistory) error {
+	before, err := json.Marshal(history.Before)
+	if err != nil {
+		return fmt.Errorf("failed to encode plan history: %w", err)
+	}
+	after, err := json.Marshal(history.After)
+	if err != nil {
+		return fmt.Errorf("failed to encode plan history: %w", err)
+	}
+
+	query := `
+		INSERT INTO plan_history (
+			plan_id, action, changed_by_id, changed_by_type, before_data, after_data, repriced_subscriptions
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err = tx.Exec(query, planID, history.Action, history.ChangedByID, history.ChangedByType, before, after, history.RepricedSubscriptions)
+	if err != nil {
+		return fmt.Errorf("failed to record plan history: %w", err)
+	}
+
+	return nil
+}
+
+// GetHistory retrieves the change log of a subscription plan in chronological order
+func (r *SubscriptionPlanRepository) GetHistory(planID int) ([]*models.PlanHistory, error) {
+	query := `
+		SELECT id, plan_id, action, changed_by_id, changed_by_type, before_data, after_data,
+		       repriced_subscriptions, created_at
+		FROM plan_history
+		WHERE plan_id = ?
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []*models.PlanHistory{}
+	for rows.Next() {
+		entry := &models.PlanHistory{}
+		var before, after []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.PlanID,
+			&entry.Action,
+			&entry.ChangedByID,
+			&entry.ChangedByType,
+			&before,
+			&after,
+			&entry.RepricedSubscriptions,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan plan history: %w", err)
+		}
+
+		if len(before) > 0 {
+			if err := json.Unmarshal(before, &entry.Before); err != nil {
+				return nil, fmt.Errorf("failed to decode plan history: %w", err)
+			}
+		}
+		if len(after) > 0 {
+			if err := json.Unmarshal(after, &entry.After); err != nil {
+				return nil, fmt.Errorf("failed to decode plan history: %w", err)
+			}
+		}
+
+		history = append(history, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating plan history: %w", err)
+	}
+
+	return history, nil
+}
+
 // Delete deletes a subscription plan
 func (r *SubscriptionPlanRepository) Delete(id int) error {
 	query := `DELETE FROM subscription_plans WHERE id = ?`
@@ -202,7 +370,7 @@ func (r *SubscriptionPlanRepository) Delete(id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("subscription plan not found")
+		return notFound("subscription plan")
 	}
 
 	return nil
@@ -229,6 +397,16 @@ func (r *SubscriptionPlanRepository) Count(isActive *bool) (int, error) {
 
 // HasActiveSubscriptions checks if a plan has any active subscriptions
 func (r *SubscriptionPlanRepository) HasActiveSubscriptions(planID int) (bool, error) {
+	count, err := r.CountActiveSubscriptions(planID)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// CountActiveSubscriptions counts a plan's active and trial subscriptions
+func (r *SubscriptionPlanRepository) CountActiveSubscriptions(planID int) (int, error) {
 	query := `
 		SELECT COUNT(*) 
 		FROM user_subscriptions 
@@ -238,7 +416,20 @@ func (r *SubscriptionPlanRepository) HasActiveSubscriptions(planID int) (bool, e
 	var count int
 	err := r.db.QueryRow(query, planID).Scan(&count)
 	if err != nil {
-		return false, fmt.Errorf("failed to check active subscriptions: %w", err)
+		return 0, fmt.Errorf("failed to check active subscriptions: %w", err)
+	}
+
+	return count, nil
+}
+
+// ExistsByName checks if a subscription plan with the given name exists
+func (r *SubscriptionPlanRepository) ExistsByName(name string) (bool, error) {
+	query := `SELECT COUNT(*) FROM subscription_plans WHERE name = ?`
+
+	var count int
+	err := r.db.QueryRow(query, name).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check plan name: %w", err)
 	}
 
 	return count > 0, nil
@@ -90,7 +90,7 @@ func (r *MySQLTemplateRepository) GetByID(id int) (*models.NotificationTemplate,
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("template not found")
+			return nil, notFound("template")
 		}
 		return nil, fmt.Errorf("failed to get template: %w", err)
 	}
@@ -128,7 +128,7 @@ func (r *MySQLTemplateRepository) GetByNameAndType(name string, templateType mod
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("template not found")
+			return nil, notFound("template")
 		}
 		return nil, fmt.Errorf("failed to get template: %w", err)
 	}
@@ -3,6 +3,8 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gatehide/gatehide-api/internal/models"
 )
@@ -13,10 +15,14 @@ type UserRepository interface {
 	GetAllByGamenet(gamenetID int) ([]models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	GetByMobile(mobile string) (*models.User, error)
+	GetByEmailIncludingDeleted(email string) (*models.User, error)
+	GetByMobileIncludingDeleted(mobile string) (*models.User, error)
 	GetByID(id int) (*models.User, error)
+	GetByIDIncludingDeleted(id int) (*models.User, error)
 	Create(user *models.User) error
 	Update(id int, user *models.UserUpdateRequest) error
 	Delete(id int) error
+	Restore(id int) error
 	Search(req *models.UserSearchRequest) (*models.UserSearchResponse, error)
 	SearchByGamenet(req *models.UserSearchRequest, gamenetID int) (*models.UserSearchResponse, error)
 	UpdateLastLogin(id int) error
@@ -25,7 +31,11 @@ type UserRepository interface {
 	UpdateEmail(id int, email string) error
 	LinkToGamenet(userID, gamenetID int) error
 	UnlinkFromGamenet(userID, gamenetID int) error
+	IsLinkedToGamenet(userID, gamenetID int) (bool, error)
 	GetGamenetIDByUser(userID int) (*int, error)
+	GetRecentlyActive(since time.Time, limit int) ([]models.RecentlyActiveUser, error)
+	ApplyBulkAction(action string, ids []int) ([]models.UserBulkActionResult, error)
+	CreateBatch(users []*models.User, gamenetID *int) error
 }
 
 // AdminRepository defines the interface for admin data operations
@@ -36,6 +46,12 @@ type AdminRepository interface {
 	UpdatePassword(id int, hashedPassword string) error
 	UpdateProfile(id int, name, mobile, image string) error
 	UpdateEmail(id int, email string) error
+	GetAllEmails() ([]string, error)
+	SetTOTPSecret(id int, secret string) error
+	EnableTOTP(id int) error
+	ClaimTOTPStep(id int, step int64) (bool, error)
+	GetAll() ([]models.Admin, error)
+	Search(req *models.UserSearchRequest) (*models.AdminSearchResponse, error)
 }
 
 // userRepository implements UserRepository interface
@@ -61,8 +77,9 @@ func NewAdminRepository(db *sql.DB) AdminRepository {
 // GetAll retrieves all users
 func (r *userRepository) GetAll() ([]models.User, error) {
 	query := `
-		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, created_at, updated_at
-		FROM users 
+		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, suspended_at, created_at, updated_at
+		FROM users
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 	`
 
@@ -85,6 +102,7 @@ func (r *userRepository) GetAll() ([]models.User, error) {
 			&user.Balance,
 			&user.Debt,
 			&user.LastLoginAt,
+			&user.SuspendedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -104,10 +122,10 @@ func (r *userRepository) GetAll() ([]models.User, error) {
 // GetAllByGamenet retrieves all users for a specific gamenet
 func (r *userRepository) GetAllByGamenet(gamenetID int) ([]models.User, error) {
 	query := `
-		SELECT u.id, u.name, u.mobile, u.email, u.password, u.image, u.balance, u.debt, u.last_login_at, u.created_at, u.updated_at
+		SELECT u.id, u.name, u.mobile, u.email, u.password, u.image, u.balance, u.debt, u.last_login_at, u.suspended_at, u.created_at, u.updated_at
 		FROM users u
 		INNER JOIN users_gamenets ug ON u.id = ug.user_id
-		WHERE ug.gamenet_id = ?
+		WHERE ug.gamenet_id = ? AND u.deleted_at IS NULL
 		ORDER BY u.created_at DESC
 	`
 
@@ -130,6 +148,7 @@ func (r *userRepository) GetAllByGamenet(gamenetID int) ([]models.User, error) {
 			&user.Balance,
 			&user.Debt,
 			&user.LastLoginAt,
+			&user.SuspendedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -146,49 +165,39 @@ func (r *userRepository) GetAllByGamenet(gamenetID int) ([]models.User, error) {
 	return users, nil
 }
 
-// GetByEmail retrieves a user by email
+// GetByEmail retrieves a user by email. Deleted users are not found.
 func (r *userRepository) GetByEmail(email string) (*models.User, error) {
-	query := `
-		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, created_at, updated_at
-		FROM users 
-		WHERE email = ?
-	`
+	return r.getByField("email", email, false)
+}
 
-	user := &models.User{}
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.Name,
-		&user.Mobile,
-		&user.Email,
-		&user.Password,
-		&user.Image,
-		&user.Balance,
-		&user.Debt,
-		&user.LastLoginAt,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+// GetByEmailIncludingDeleted retrieves a user by email even when it has been deleted
+func (r *userRepository) GetByEmailIncludingDeleted(email string) (*models.User, error) {
+	return r.getByField("email", email, true)
+}
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
-		}
-		return nil, fmt.Errorf("failed to get user: %w", err)
-	}
+// GetByMobile retrieves a user by mobile number. Deleted users are not found.
+func (r *userRepository) GetByMobile(mobile string) (*models.User, error) {
+	return r.getByField("mobile", mobile, false)
+}
 
-	return user, nil
+// GetByMobileIncludingDeleted retrieves a user by mobile number even when it has been deleted
+func (r *userRepository) GetByMobileIncludingDeleted(mobile string) (*models.User, error) {
+	return r.getByField("mobile", mobile, true)
 }
 
-// GetByMobile retrieves a user by mobile number
-func (r *userRepository) GetByMobile(mobile string) (*models.User, error) {
+// getByField retrieves a user by a unique column, optionally including deleted users
+func (r *userRepository) getByField(column, value string, includeDeleted bool) (*models.User, error) {
 	query := `
-		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, created_at, updated_at
-		FROM users 
-		WHERE mobile = ?
+		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, suspended_at, deleted_at, created_at, updated_at
+		FROM users
+		WHERE ` + column + ` = ?
 	`
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
 
 	user := &models.User{}
-	err := r.db.QueryRow(query, mobile).Scan(
+	err := r.db.QueryRow(query, value).Scan(
 		&user.ID,
 		&user.Name,
 		&user.Mobile,
@@ -198,13 +207,15 @@ func (r *userRepository) GetByMobile(mobile string) (*models.User, error) {
 		&user.Balance,
 		&user.Debt,
 		&user.LastLoginAt,
+		&user.SuspendedAt,
+		&user.DeletedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, notFound("user")
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -212,13 +223,26 @@ func (r *userRepository) GetByMobile(mobile string) (*models.User, error) {
 	return user, nil
 }
 
-// GetByID retrieves a user by ID
+// GetByID retrieves a user by ID. Deleted users are not found.
 func (r *userRepository) GetByID(id int) (*models.User, error) {
+	return r.getByID(id, false)
+}
+
+// GetByIDIncludingDeleted retrieves a user by ID even when it has been deleted
+func (r *userRepository) GetByIDIncludingDeleted(id int) (*models.User, error) {
+	return r.getByID(id, true)
+}
+
+// getByID retrieves a user by ID, optionally including deleted users
+func (r *userRepository) getByID(id int, includeDeleted bool) (*models.User, error) {
 	query := `
-		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, created_at, updated_at
-		FROM users 
+		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, suspended_at, deleted_at, created_at, updated_at
+		FROM users
 		WHERE id = ?
 	`
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
 
 	user := &models.User{}
 	err := r.db.QueryRow(query, id).Scan(
@@ -231,13 +255,15 @@ func (r *userRepository) GetByID(id int) (*models.User, error) {
 		&user.Balance,
 		&user.Debt,
 		&user.LastLoginAt,
+		&user.SuspendedAt,
+		&user.DeletedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, notFound("user")
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -273,6 +299,76 @@ func (r *userRepository) Create(user *models.User) error {
 	return nil
 }
 
+// CreateBatch inserts all users in a single transaction and sets their IDs. Each user
+// gets the user role and, when gamenetID is set, is linked to that gamenet in the same
+// transaction. If any step fails nothing is created and a *BatchRowError names the
+// failing user.
+func (r *userRepository) CreateBatch(users []*models.User, gamenetID *int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO users (name, mobile, email, password, image)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	ids := make([]int, len(users))
+	for i, user := range users {
+		result, err := tx.Exec(query, user.Name, user.Mobile, user.Email, user.Password, user.Image)
+		if err != nil {
+			return &BatchRowError{Index: i, Err: fmt.Errorf("failed to create user: %w", err)}
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return &BatchRowError{Index: i, Err: fmt.Errorf("failed to get last insert ID: %w", err)}
+		}
+		ids[i] = int(id)
+
+		if err := assignUserRole(tx, ids[i]); err != nil {
+			return &BatchRowError{Index: i, Err: err}
+		}
+		if gamenetID != nil && *gamenetID > 0 {
+			if _, err := tx.Exec(linkToGamenetQuery, ids[i], *gamenetID); err != nil {
+				return &BatchRowError{Index: i, Err: fmt.Errorf("failed to link user to gamenet: %w", err)}
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Only hand out IDs once the users really exist
+	for i, user := range users {
+		user.ID = ids[i]
+	}
+	return nil
+}
+
+// assignUserRole gives a newly inserted user the user role within tx
+func assignUserRole(tx *sql.Tx, userID int) error {
+	result, err := tx.Exec(`
+		INSERT INTO user_roles (user_id, user_type, role_id, created_at, updated_at)
+		SELECT ?, ?, id, NOW(), NOW() FROM roles WHERE name = ?
+	`, userID, models.RoleUser, models.RoleUser)
+	if err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to get role: %w", notFound("role"))
+	}
+	return nil
+}
+
 // Update updates an existing user
 func (r *userRepository) Update(id int, updateData *models.UserUpdateRequest) error {
 	// Build dynamic query based on provided fields
@@ -316,11 +412,16 @@ func (r *userRepository) Update(id int, updateData *models.UserUpdateRequest) er
 	return nil
 }
 
-// Delete deletes a user by ID
+// Delete soft-deletes a user by ID and signs it out everywhere. The row is kept so its
+// history and references survive, and it can be brought back with Restore.
 func (r *userRepository) Delete(id int) error {
-	query := "DELETE FROM users WHERE id = ?"
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	result, err := r.db.Exec(query, id)
+	result, err := tx.Exec("UPDATE users SET deleted_at = NOW() WHERE id = ? AND deleted_at IS NULL", id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
@@ -331,7 +432,36 @@ func (r *userRepository) Delete(id int) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return notFound("user")
+	}
+
+	if err := deactivateUserSessions(tx, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Restore brings back a soft-deleted user
+func (r *userRepository) Restore(id int) error {
+	query := "UPDATE users SET deleted_at = NULL, updated_at = NOW() WHERE id = ? AND deleted_at IS NOT NULL"
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return notFound("deleted user")
 	}
 
 	return nil
@@ -353,14 +483,8 @@ func (r *userRepository) Search(req *models.UserSearchRequest) (*models.UserSear
 	offset := (req.Page - 1) * req.PageSize
 
 	// Build search query
-	var whereClause string
-	var args []interface{}
-
-	if req.Query != "" {
-		whereClause = `WHERE name LIKE ? OR mobile LIKE ? OR email LIKE ?`
-		searchTerm := "%" + req.Query + "%"
-		args = []interface{}{searchTerm, searchTerm, searchTerm}
-	}
+	conditions, args := buildUserSearchConditions(req, "")
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 	// Count total items
 	countQuery := `SELECT COUNT(*) FROM users ` + whereClause
@@ -377,8 +501,8 @@ func (r *userRepository) Search(req *models.UserSearchRequest) (*models.UserSear
 
 	// Build data query
 	dataQuery := `
-		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, created_at, updated_at
-		FROM users 
+		SELECT id, name, mobile, email, password, image, balance, debt, last_login_at, suspended_at, created_at, updated_at
+		FROM users
 		` + whereClause + `
 		ORDER BY created_at DESC
 		LIMIT ? OFFSET ?
@@ -406,6 +530,7 @@ func (r *userRepository) Search(req *models.UserSearchRequest) (*models.UserSear
 			&user.Balance,
 			&user.Debt,
 			&user.LastLoginAt,
+			&user.SuspendedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -438,6 +563,46 @@ func (r *userRepository) Search(req *models.UserSearchRequest) (*models.UserSear
 	}, nil
 }
 
+// buildUserSearchConditions builds the WHERE conditions for a user search: the query
+// term matched against name, mobile and email, and the balance, debt and last login
+// filters. prefix qualifies the users columns, e.g. "u." when the table is aliased.
+func buildUserSearchConditions(req *models.UserSearchRequest, prefix string) ([]string, []interface{}) {
+	// Deleted users are never listed
+	conditions := []string{prefix + "deleted_at IS NULL"}
+	var args []interface{}
+
+	if req.Query != "" {
+		conditions = append(conditions, fmt.Sprintf("(%[1]sname LIKE ? OR %[1]smobile LIKE ? OR %[1]semail LIKE ?)", prefix))
+		searchTerm := "%" + req.Query + "%"
+		args = append(args, searchTerm, searchTerm, searchTerm)
+	}
+	if req.MinBalance != nil {
+		conditions = append(conditions, prefix+"balance >= ?")
+		args = append(args, *req.MinBalance)
+	}
+	if req.MaxBalance != nil {
+		conditions = append(conditions, prefix+"balance <= ?")
+		args = append(args, *req.MaxBalance)
+	}
+	if req.MinDebt != nil {
+		conditions = append(conditions, prefix+"debt >= ?")
+		args = append(args, *req.MinDebt)
+	}
+	if req.HasDebt != nil {
+		if *req.HasDebt {
+			conditions = append(conditions, prefix+"debt > 0")
+		} else {
+			conditions = append(conditions, prefix+"debt = 0")
+		}
+	}
+	if req.LastLoginBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("(%[1]slast_login_at IS NULL OR %[1]slast_login_at < ?)", prefix))
+		args = append(args, *req.LastLoginBefore)
+	}
+
+	return conditions, args
+}
+
 // UpdateLastLogin updates the last login timestamp for a user
 func (r *userRepository) UpdateLastLogin(id int) error {
 	query := `UPDATE users SET last_login_at = NOW() WHERE id = ?`
@@ -502,19 +667,10 @@ func (r *userRepository) SearchByGamenet(req *models.UserSearchRequest, gamenetI
 	offset := (req.Page - 1) * req.PageSize
 
 	// Build search query with gamenet join
-	var whereClause string
-	var args []interface{}
-
-	baseWhere := "WHERE ug.gamenet_id = ?"
-	args = append(args, gamenetID)
-
-	if req.Query != "" {
-		whereClause = baseWhere + ` AND (u.name LIKE ? OR u.mobile LIKE ? OR u.email LIKE ?)`
-		searchTerm := "%" + req.Query + "%"
-		args = append(args, searchTerm, searchTerm, searchTerm)
-	} else {
-		whereClause = baseWhere
-	}
+	conditions, filterArgs := buildUserSearchConditions(req, "u.")
+	conditions = append([]string{"ug.gamenet_id = ?"}, conditions...)
+	args := append([]interface{}{gamenetID}, filterArgs...)
+	whereClause := "WHERE " + strings.Join(conditions, " AND ")
 
 	// Count total items
 	countQuery := `SELECT COUNT(*) FROM users u INNER JOIN users_gamenets ug ON u.id = ug.user_id ` + whereClause
@@ -531,7 +687,7 @@ func (r *userRepository) SearchByGamenet(req *models.UserSearchRequest, gamenetI
 
 	// Build data query
 	dataQuery := `
-		SELECT u.id, u.name, u.mobile, u.email, u.password, u.image, u.balance, u.debt, u.last_login_at, u.created_at, u.updated_at
+		SELECT u.id, u.name, u.mobile, u.email, u.password, u.image, u.balance, u.debt, u.last_login_at, u.suspended_at, u.created_at, u.updated_at
 		FROM users u
 		INNER JOIN users_gamenets ug ON u.id = ug.user_id
 		` + whereClause + `
@@ -561,6 +717,7 @@ func (r *userRepository) SearchByGamenet(req *models.UserSearchRequest, gamenetI
 			&user.Balance,
 			&user.Debt,
 			&user.LastLoginAt,
+			&user.SuspendedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -593,11 +750,12 @@ func (r *userRepository) SearchByGamenet(req *models.UserSearchRequest, gamenetI
 	}, nil
 }
 
+// linkToGamenetQuery links a user to a gamenet, refreshing an existing link
+const linkToGamenetQuery = `INSERT INTO users_gamenets (user_id, gamenet_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE updated_at = CURRENT_TIMESTAMP`
+
 // LinkToGamenet links a user to a gamenet
 func (r *userRepository) LinkToGamenet(userID, gamenetID int) error {
-	query := `INSERT INTO users_gamenets (user_id, gamenet_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE updated_at = CURRENT_TIMESTAMP`
-
-	_, err := r.db.Exec(query, userID, gamenetID)
+	_, err := r.db.Exec(linkToGamenetQuery, userID, gamenetID)
 	if err != nil {
 		return fmt.Errorf("failed to link user to gamenet: %w", err)
 	}
@@ -626,6 +784,19 @@ func (r *userRepository) UnlinkFromGamenet(userID, gamenetID int) error {
 	return nil
 }
 
+// IsLinkedToGamenet checks if a user is linked to a gamenet
+func (r *userRepository) IsLinkedToGamenet(userID, gamenetID int) (bool, error) {
+	query := `SELECT COUNT(*) FROM users_gamenets WHERE user_id = ? AND gamenet_id = ?`
+
+	var count int
+	err := r.db.QueryRow(query, userID, gamenetID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check gamenet link: %w", err)
+	}
+
+	return count > 0, nil
+}
+
 // GetGamenetIDByUser gets the gamenet ID that created a user (first linked gamenet)
 func (r *userRepository) GetGamenetIDByUser(userID int) (*int, error) {
 	query := `SELECT gamenet_id FROM users_gamenets WHERE user_id = ? ORDER BY created_at ASC LIMIT 1`
@@ -642,11 +813,131 @@ func (r *userRepository) GetGamenetIDByUser(userID int) (*int, error) {
 	return &gamenetID, nil
 }
 
+// GetRecentlyActive retrieves users who logged in at or after since, most recent first
+func (r *userRepository) GetRecentlyActive(since time.Time, limit int) ([]models.RecentlyActiveUser, error) {
+	query := `
+		SELECT id, name, last_login_at
+		FROM users
+		WHERE last_login_at >= ? AND deleted_at IS NULL
+		ORDER BY last_login_at DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recently active users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []models.RecentlyActiveUser{}
+	for rows.Next() {
+		var user models.RecentlyActiveUser
+		if err := rows.Scan(&user.ID, &user.Name, &user.LastLoginAt.Time); err != nil {
+			return nil, fmt.Errorf("failed to scan recently active user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recently active users: %w", err)
+	}
+
+	return users, nil
+}
+
+// ApplyBulkAction applies a delete, suspend or reactivate action to each user in a single
+// transaction. Each user's changes are made under a savepoint, so a failure for one user
+// rolls back only that user's changes; it is reported in its result and doesn't stop the
+// others. Sessions of deleted and suspended users are deactivated.
+func (r *userRepository) ApplyBulkAction(action string, ids []int) ([]models.UserBulkActionResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	results := make([]models.UserBulkActionResult, 0, len(ids))
+	for _, id := range ids {
+		if _, err := tx.Exec(`SAVEPOINT bulk_user_action`); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		result := models.UserBulkActionResult{UserID: id, Success: true}
+		if err := applyUserBulkAction(tx, action, id); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT bulk_user_action`); err != nil {
+				return nil, fmt.Errorf("failed to roll back user %d: %w", id, err)
+			}
+		}
+		if _, err := tx.Exec(`RELEASE SAVEPOINT bulk_user_action`); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return results, nil
+}
+
+// applyUserBulkAction applies a bulk action to a single user within a transaction
+func applyUserBulkAction(tx *sql.Tx, action string, id int) error {
+	var suspendedAt sql.NullTime
+	err := tx.QueryRow(`SELECT suspended_at FROM users WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&suspendedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound("user")
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	switch action {
+	case models.UserBulkActionDelete:
+		if _, err := tx.Exec(`UPDATE users SET deleted_at = NOW() WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+	case models.UserBulkActionSuspend:
+		if suspendedAt.Valid {
+			return fmt.Errorf("user is already suspended")
+		}
+		if _, err := tx.Exec(`UPDATE users SET suspended_at = NOW() WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to suspend user: %w", err)
+		}
+	case models.UserBulkActionReactivate:
+		if !suspendedAt.Valid {
+			return fmt.Errorf("user is not suspended")
+		}
+		if _, err := tx.Exec(`UPDATE users SET suspended_at = NULL WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to reactivate user: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported action: %s", action)
+	}
+
+	// Sign the user out everywhere
+	return deactivateUserSessions(tx, id)
+}
+
+// deactivateUserSessions signs a user out of every session within a transaction
+func deactivateUserSessions(tx *sql.Tx, id int) error {
+	query := `UPDATE user_sessions SET is_active = FALSE WHERE user_id = ? AND user_type = 'user' AND is_active = TRUE`
+	if _, err := tx.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to deactivate sessions: %w", err)
+	}
+
+	return nil
+}
+
 // GetByEmail retrieves an admin by email
 func (r *adminRepository) GetByEmail(email string) (*models.Admin, error) {
 	query := `
-		SELECT id, name, mobile, email, password, image, last_login_at, created_at, updated_at
-		FROM admins 
+		SELECT id, name, mobile, email, password, image, last_login_at, created_at, updated_at,
+		       totp_secret, totp_enabled_at
+		FROM admins
 		WHERE email = ?
 	`
 
@@ -661,11 +952,13 @@ func (r *adminRepository) GetByEmail(email string) (*models.Admin, error) {
 		&admin.LastLoginAt,
 		&admin.CreatedAt,
 		&admin.UpdatedAt,
+		&admin.TOTPSecret,
+		&admin.TOTPEnabledAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("admin not found")
+			return nil, notFound("admin")
 		}
 		return nil, fmt.Errorf("failed to get admin: %w", err)
 	}
@@ -676,8 +969,9 @@ func (r *adminRepository) GetByEmail(email string) (*models.Admin, error) {
 // GetByID retrieves an admin by ID
 func (r *adminRepository) GetByID(id int) (*models.Admin, error) {
 	query := `
-		SELECT id, name, mobile, email, password, image, last_login_at, created_at, updated_at
-		FROM admins 
+		SELECT id, name, mobile, email, password, image, last_login_at, created_at, updated_at,
+		       totp_secret, totp_enabled_at
+		FROM admins
 		WHERE id = ?
 	`
 
@@ -692,11 +986,13 @@ func (r *adminRepository) GetByID(id int) (*models.Admin, error) {
 		&admin.LastLoginAt,
 		&admin.CreatedAt,
 		&admin.UpdatedAt,
+		&admin.TOTPSecret,
+		&admin.TOTPEnabledAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("admin not found")
+			return nil, notFound("admin")
 		}
 		return nil, fmt.Errorf("failed to get admin: %w", err)
 	}
@@ -704,6 +1000,134 @@ func (r *adminRepository) GetByID(id int) (*models.Admin, error) {
 	return admin, nil
 }
 
+// GetAll retrieves all admins
+func (r *adminRepository) GetAll() ([]models.Admin, error) {
+	query := `
+		SELECT id, name, mobile, email, password, image, last_login_at, created_at, updated_at,
+		       totp_secret, totp_enabled_at
+		FROM admins
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admins: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAdmins(rows)
+}
+
+// Search searches admins by name, mobile or email with pagination
+func (r *adminRepository) Search(req *models.UserSearchRequest) (*models.AdminSearchResponse, error) {
+	// Set default values
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+
+	// Build search query
+	var whereClause string
+	var args []interface{}
+
+	if req.Query != "" {
+		whereClause = `WHERE name LIKE ? OR mobile LIKE ? OR email LIKE ?`
+		searchTerm := "%" + req.Query + "%"
+		args = []interface{}{searchTerm, searchTerm, searchTerm}
+	}
+
+	// Count total items
+	countQuery := `SELECT COUNT(*) FROM admins ` + whereClause
+	var totalItems int64
+	err := r.db.QueryRow(countQuery, args...).Scan(&totalItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count admins: %w", err)
+	}
+
+	// Calculate pagination info
+	totalPages := int((totalItems + int64(req.PageSize) - 1) / int64(req.PageSize))
+
+	// Build data query
+	dataQuery := `
+		SELECT id, name, mobile, email, password, image, last_login_at, created_at, updated_at,
+		       totp_secret, totp_enabled_at
+		FROM admins
+		` + whereClause + `
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	// Add limit and offset to args
+	args = append(args, req.PageSize, offset)
+
+	rows, err := r.db.Query(dataQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admins: %w", err)
+	}
+	defer rows.Close()
+
+	admins, err := scanAdmins(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to response format
+	responses := make([]models.AdminResponse, 0, len(admins))
+	for _, admin := range admins {
+		responses = append(responses, admin.ToResponse())
+	}
+
+	return &models.AdminSearchResponse{
+		Data: responses,
+		Pagination: models.PaginationInfo{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			HasNext:     req.Page < totalPages,
+			HasPrev:     req.Page > 1,
+		},
+	}, nil
+}
+
+// scanAdmins reads every admin row selected by GetAll and Search
+func scanAdmins(rows *sql.Rows) ([]models.Admin, error) {
+	var admins []models.Admin
+	for rows.Next() {
+		var admin models.Admin
+		err := rows.Scan(
+			&admin.ID,
+			&admin.Name,
+			&admin.Mobile,
+			&admin.Email,
+			&admin.Password,
+			&admin.Image,
+			&admin.LastLoginAt,
+			&admin.CreatedAt,
+			&admin.UpdatedAt,
+			&admin.TOTPSecret,
+			&admin.TOTPEnabledAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan admin: %w", err)
+		}
+		admins = append(admins, admin)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating admins: %w", err)
+	}
+
+	return admins, nil
+}
+
 // UpdateLastLogin updates the last login timestamp for an admin
 func (r *adminRepository) UpdateLastLogin(id int) error {
 	query := `UPDATE admins SET last_login_at = NOW() WHERE id = ?`
@@ -751,3 +1175,73 @@ func (r *adminRepository) UpdateEmail(id int, email string) error {
 
 	return nil
 }
+
+// GetAllEmails retrieves the email address of every admin
+func (r *adminRepository) GetAllEmails() ([]string, error) {
+	rows, err := r.db.Query(`SELECT email FROM admins ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin emails: %w", err)
+	}
+	defer rows.Close()
+
+	emails := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, fmt.Errorf("failed to scan admin email: %w", err)
+		}
+		emails = append(emails, email)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating admin emails: %w", err)
+	}
+
+	return emails, nil
+}
+
+// SetTOTPSecret stores a new, not yet confirmed TOTP secret for an admin
+func (r *adminRepository) SetTOTPSecret(id int, secret string) error {
+	query := `UPDATE admins SET totp_secret = ?, totp_enabled_at = NULL, totp_last_step = NULL, updated_at = NOW() WHERE id = ?`
+
+	_, err := r.db.Exec(query, secret, id)
+	if err != nil {
+		return fmt.Errorf("failed to set totp secret: %w", err)
+	}
+
+	return nil
+}
+
+// EnableTOTP marks an admin's stored TOTP secret as confirmed, so logins require a code
+func (r *adminRepository) EnableTOTP(id int) error {
+	query := `UPDATE admins SET totp_enabled_at = NOW(), updated_at = NOW() WHERE id = ? AND totp_secret IS NOT NULL`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimTOTPStep records step as the admin's last accepted TOTP time step. It returns
+// false without changing anything when a code of the same or a later step was already
+// accepted, so each code is only good once even across concurrent logins.
+func (r *adminRepository) ClaimTOTPStep(id int, step int64) (bool, error) {
+	query := `
+		UPDATE admins SET totp_last_step = ?
+		WHERE id = ? AND (totp_last_step IS NULL OR totp_last_step < ?)
+	`
+
+	result, err := r.db.Exec(query, step, id, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record totp step: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
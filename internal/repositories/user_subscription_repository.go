@@ -0,0 +1,214 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// ErrSubscriptionOverlap is returned by Create when the gamenet already has an active or
+// trial subscription that has not ended
+var ErrSubscriptionOverlap = errors.New("gamenet already has a current subscription")
+
+// UserSubscriptionRepositoryInterface defines the interface for gamenet subscription repository operations
+type UserSubscriptionRepositoryInterface interface {
+	Create(subscription *models.UserSubscription) error
+	GetByID(id int) (*models.UserSubscription, error)
+	GetActiveByGamenetID(gamenetID int) (*models.UserSubscription, error)
+	Cancel(id int) error
+}
+
+// UserSubscriptionRepository handles gamenet subscription database operations
+type UserSubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewUserSubscriptionRepository creates a new user subscription repository
+func NewUserSubscriptionRepository(db *sql.DB) *UserSubscriptionRepository {
+	return &UserSubscriptionRepository{db: db}
+}
+
+const userSubscriptionColumns = `
+	id, gamenet_id, plan_id, subscribed_price, status, started_at,
+	expires_at, auto_renew, created_at, updated_at
+`
+
+// Create creates a subscription and records it in the subscription history. The
+// gamenet's row is locked while checking for a current subscription, so concurrent
+// subscribes for the same gamenet are serialized; it returns ErrSubscriptionOverlap when
+// the gamenet already has one.
+func (r *UserSubscriptionRepository) Create(subscription *models.UserSubscription) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var gamenetID int
+	err = tx.QueryRow(`SELECT id FROM gamenets WHERE id = ? FOR UPDATE`, subscription.GamenetID).Scan(&gamenetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound("gamenet")
+		}
+		return fmt.Errorf("failed to lock gamenet: %w", err)
+	}
+
+	var overlapping bool
+	err = tx.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM user_subscriptions
+			WHERE gamenet_id = ? AND status IN ('active', 'trial')
+			  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+		)
+	`, subscription.GamenetID).Scan(&overlapping)
+	if err != nil {
+		return fmt.Errorf("failed to check current subscription: %w", err)
+	}
+	if overlapping {
+		return ErrSubscriptionOverlap
+	}
+
+	query := `
+		INSERT INTO user_subscriptions (
+			gamenet_id, plan_id, subscribed_price, status, started_at, expires_at, auto_renew
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := tx.Exec(query,
+		subscription.GamenetID,
+		subscription.PlanID,
+		subscription.SubscribedPrice,
+		subscription.Status,
+		subscription.StartedAt,
+		subscription.ExpiresAt,
+		subscription.AutoRenew,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	if err := r.insertHistory(tx, subscription.GamenetID, subscription.PlanID, models.SubscriptionHistoryActionCreated); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	subscription.ID = int(id)
+	return nil
+}
+
+// GetByID retrieves a subscription by ID
+func (r *UserSubscriptionRepository) GetByID(id int) (*models.UserSubscription, error) {
+	query := `SELECT ` + userSubscriptionColumns + ` FROM user_subscriptions WHERE id = ?`
+
+	subscription, err := scanUserSubscription(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, notFound("subscription")
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// GetActiveByGamenetID retrieves the gamenet's current active or trial subscription,
+// ignoring subscriptions that have run past their end date
+func (r *UserSubscriptionRepository) GetActiveByGamenetID(gamenetID int) (*models.UserSubscription, error) {
+	query := `SELECT ` + userSubscriptionColumns + `
+		FROM user_subscriptions
+		WHERE gamenet_id = ? AND status IN ('active', 'trial')
+		  AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+		ORDER BY started_at DESC, id DESC
+		LIMIT 1
+	`
+
+	subscription, err := scanUserSubscription(r.db.QueryRow(query, gamenetID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, notFound("subscription")
+		}
+		return nil, fmt.Errorf("failed to get active subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// Cancel marks a subscription as cancelled, stops its renewal and records the
+// cancellation in the subscription history
+func (r *UserSubscriptionRepository) Cancel(id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var gamenetID, planID int
+	err = tx.QueryRow(`SELECT gamenet_id, plan_id FROM user_subscriptions WHERE id = ? FOR UPDATE`, id).Scan(&gamenetID, &planID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound("subscription")
+		}
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	query := `
+		UPDATE user_subscriptions
+		SET status = ?, auto_renew = FALSE, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+	if _, err := tx.Exec(query, models.SubscriptionStatusCancelled, id); err != nil {
+		return fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+
+	if err := r.insertHistory(tx, gamenetID, planID, models.SubscriptionHistoryActionCancelled); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// insertHistory records a subscription history entry within a transaction
+func (r *UserSubscriptionRepository) insertHistory(tx *sql.Tx, gamenetID, planID int, action string) error {
+	query := `INSERT INTO subscription_history (gamenet_id, plan_id, action) VALUES (?, ?, ?)`
+
+	if _, err := tx.Exec(query, gamenetID, planID, action); err != nil {
+		return fmt.Errorf("failed to record subscription history: %w", err)
+	}
+
+	return nil
+}
+
+// scanUserSubscription scans a row selected with userSubscriptionColumns
+func scanUserSubscription(row *sql.Row) (*models.UserSubscription, error) {
+	subscription := &models.UserSubscription{}
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.GamenetID,
+		&subscription.PlanID,
+		&subscription.SubscribedPrice,
+		&subscription.Status,
+		&subscription.StartedAt,
+		&subscription.ExpiresAt,
+		&subscription.AutoRenew,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
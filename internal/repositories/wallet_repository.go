@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// WalletRepositoryInterface defines the interface for wallet data operations
+type WalletRepositoryInterface interface {
+	GetBalance(userID int) (*models.WalletBalance, error)
+	ApplyTransaction(userID int, txType models.WalletTransactionType, amount float64, reason string, allowDebt bool) (*models.WalletTransaction, error)
+	ListTransactions(userID int, filter *models.WalletTransactionFilter) (*models.WalletTransactionListResponse, error)
+}
+
+// WalletRepository implements WalletRepositoryInterface
+type WalletRepository struct {
+	db *sql.DB
+}
+
+// NewWalletRepository creates a new wallet repository
+func NewWalletRepository(db *sql.DB) WalletRepositoryInterface {
+	return &WalletRepository{db: db}
+}
+
+// GetBalance returns the balance and debt of a user. Deleted users are not found.
+func (r *WalletRepository) GetBalance(userID int) (*models.WalletBalance, error) {
+	query := `SELECT balance, debt FROM users WHERE id = ? AND deleted_at IS NULL`
+
+	wallet := &models.WalletBalance{UserID: userID}
+	if err := r.db.QueryRow(query, userID).Scan(&wallet.Balance, &wallet.Debt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, notFound("user")
+		}
+		return nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	return wallet, nil
+}
+
+// ApplyTransaction credits or debits a user's wallet and logs the transaction in a
+// single database transaction. The user's row is locked while the new balance is
+// worked out, so concurrent transactions on the same wallet are applied one after
+// the other instead of overwriting each other.
+func (r *WalletRepository) ApplyTransaction(userID int, txType models.WalletTransactionType, amount float64, reason string, allowDebt bool) (*models.WalletTransaction, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	wallet := &models.WalletBalance{UserID: userID}
+	err = tx.QueryRow(`SELECT balance, debt FROM users WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, userID).
+		Scan(&wallet.Balance, &wallet.Debt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, notFound("user")
+		}
+		return nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+
+	switch txType {
+	case models.WalletTransactionCredit:
+		wallet.Credit(amount)
+	case models.WalletTransactionDebit:
+		if err := wallet.Debit(amount, allowDebt); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported wallet transaction type: %s", txType)
+	}
+
+	query := `UPDATE users SET balance = ?, debt = ?, updated_at = NOW() WHERE id = ?`
+	if _, err := tx.Exec(query, wallet.Balance, wallet.Debt, userID); err != nil {
+		return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	}
+
+	query = `
+		INSERT INTO wallet_transactions (user_id, type, amount, balance_after, debt_after, reason)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := tx.Exec(query, userID, txType, amount, wallet.Balance, wallet.Debt, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to log wallet transaction: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	var createdAt sql.NullTime
+	if err := tx.QueryRow(`SELECT created_at FROM wallet_transactions WHERE id = ?`, id).Scan(&createdAt); err != nil {
+		return nil, fmt.Errorf("failed to get wallet transaction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &models.WalletTransaction{
+		ID:           int(id),
+		UserID:       userID,
+		Type:         txType,
+		Amount:       amount,
+		BalanceAfter: wallet.Balance,
+		DebtAfter:    wallet.Debt,
+		Reason:       reason,
+		CreatedAt:    createdAt.Time,
+	}, nil
+}
+
+// ListTransactions retrieves a filtered, paginated page of a user's wallet transactions, newest first
+func (r *WalletRepository) ListTransactions(userID int, filter *models.WalletTransactionFilter) (*models.WalletTransactionListResponse, error) {
+	// Set default values
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+	if filter.PageSize > 100 {
+		filter.PageSize = 100
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+
+	conditions := []string{"user_id = ?"}
+	args := []interface{}{userID}
+	if filter.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, filter.Type)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, *filter.To)
+	}
+	whereClause := " WHERE " + strings.Join(conditions, " AND ")
+
+	// Count total items
+	var totalItems int64
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM wallet_transactions`+whereClause, args...).Scan(&totalItems); err != nil {
+		return nil, fmt.Errorf("failed to count wallet transactions: %w", err)
+	}
+
+	// Calculate pagination info
+	totalPages := int((totalItems + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+	hasNext := filter.Page < totalPages
+	hasPrev := filter.Page > 1
+
+	dataQuery := `
+		SELECT id, user_id, type, amount, balance_after, debt_after, reason, created_at
+		FROM wallet_transactions` + whereClause + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := r.db.Query(dataQuery, append(args, filter.PageSize, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query wallet transactions: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := []models.WalletTransaction{}
+	for rows.Next() {
+		var transaction models.WalletTransaction
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.UserID,
+			&transaction.Type,
+			&transaction.Amount,
+			&transaction.BalanceAfter,
+			&transaction.DebtAfter,
+			&transaction.Reason,
+			&transaction.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan wallet transaction: %w", err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating wallet transactions: %w", err)
+	}
+
+	return &models.WalletTransactionListResponse{
+		Data: transactions,
+		Pagination: models.PaginationInfo{
+			CurrentPage: filter.Page,
+			PageSize:    filter.PageSize,
+			TotalItems:  totalItems,
+			TotalPages:  totalPages,
+			HasNext:     hasNext,
+			HasPrev:     hasPrev,
+		},
+	}, nil
+}
@@ -1,9 +1,12 @@
 package routes
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/database/seeders"
 	"github.com/gatehide/gatehide-api/internal/handlers"
 	"github.com/gatehide/gatehide-api/internal/middlewares"
 	"github.com/gatehide/gatehide-api/internal/repositories"
@@ -12,8 +15,10 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRoutes configures all application routes
-func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) {
+// SetupRoutes configures all application routes. It returns a shutdown function
+// that waits for background work started here, such as queued notifications, and the
+// notification service so the background workers can retry failed notifications.
+func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) (func(ctx context.Context) error, *services.NotificationService) {
 	// Apply global middlewares
 	router.Use(middlewares.Logger())
 	router.Use(middlewares.CORS())
@@ -28,56 +33,121 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) {
 	adminRepo := repositories.NewAdminRepository(db)
 	passwordResetRepo := repositories.NewPasswordResetRepository(db)
 	sessionRepo := repositories.NewSessionRepository(db)
+	revokedTokenRepo := repositories.NewRevokedTokenRepository(db)
 	emailVerificationRepo := repositories.NewEmailVerificationRepository(db)
 	notificationRepo := repositories.NewMySQLNotificationRepository(db)
 	gamenetRepo := repositories.NewGamenetRepository(db)
 	subscriptionPlanRepo := repositories.NewSubscriptionPlanRepository(db)
-	permissionRepo := repositories.NewPermissionRepository(db)
+	userSubscriptionRepo := repositories.NewUserSubscriptionRepository(db)
+	var permissionRepo repositories.PermissionRepositoryInterface = repositories.NewPermissionRepository(db)
+	if ttl := cfg.Security.PermissionCacheTTLSeconds; ttl > 0 {
+		// Every protected request checks a permission; keep those off the database
+		permissionRepo = repositories.NewCachedPermissionRepository(permissionRepo, time.Duration(ttl)*time.Second)
+	}
+	statsRepo := repositories.NewStatsRepository(db)
+	loginAuditRepo := repositories.NewLoginAuditRepository(db)
+	templateRepo := repositories.NewMySQLTemplateRepository(db)
+	smsLogRepo := repositories.NewSMSLogRepository(db)
+	walletRepo := repositories.NewWalletRepository(db)
 
 	// Initialize services
 	emailService := services.NewEmailService(&cfg.Notification.Email)
+	emailService.SetFrontendLinks(utils.NewFrontendLinks(cfg.App.FrontendBaseURL))
+	templateService := services.NewTemplateService(templateRepo)
 	smsService := services.NewSMSService(&cfg.Notification.SMS)
+	smsService.SetTemplateService(templateService)
+	smsService.SetLogRepository(smsLogRepo)
+	if cfg.NotificationSandboxActive() {
+		// Staging: keep email and SMS away from real users
+		emailService.SetSandbox(&cfg.Notification.Sandbox)
+		smsService.SetSandbox(&cfg.Notification.Sandbox)
+	}
+	// With SMS disabled every send returns ErrSMSDisabled without touching Kavenegar
+	var smsSender services.SMSServiceInterface = smsService
+	if !cfg.Notification.SMS.Enabled {
+		smsSender = services.NewNoopSMSService()
+	}
 	notificationService := services.NewNotificationService(
-		emailService, smsService, nil, nil, notificationRepo, cfg)
+		emailService, smsSender, nil, templateService, notificationRepo, adminRepo, cfg)
+	notificationService.StartQueue(cfg.Notification.Queue.Workers, cfg.Notification.Queue.Size)
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, loginAuditRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService.SetRevokedTokenRepository(revokedTokenRepo)
+	authService.SetLoginLockoutRepository(repositories.NewLoginLockoutRepository(db))
+	authService.SetTemplateService(templateService)
 	sessionService := services.NewSessionService(sessionRepo, cfg)
-	gamenetService := services.NewGamenetService(gamenetRepo, permissionRepo, smsService, emailService)
-	userService := services.NewUserService(userRepo, permissionRepo, smsService, emailService)
+	sessionService.SetAccountRepositories(userRepo, adminRepo, gamenetRepo)
+	gamenetService := services.NewGamenetService(gamenetRepo, permissionRepo, smsSender, emailService)
+	emailValidator := utils.NewEmailDomainValidator(&cfg.EmailPolicy)
+	userService := services.NewUserService(userRepo, permissionRepo, smsSender, emailService, emailValidator)
+	adminService := services.NewAdminService(adminRepo)
 	subscriptionPlanService := services.NewSubscriptionPlanService(subscriptionPlanRepo)
+	subscriptionPlanService.SetMaxTrialDurationDays(cfg.Subscription.MaxTrialDurationDays)
+	subscriptionPlanService.SetRepriceExistingSubscribers(cfg.Subscription.RepriceExistingSubscribers)
+	subscriptionService := services.NewSubscriptionService(userSubscriptionRepo, subscriptionPlanRepo)
+	statsService := services.NewStatsService(statsRepo)
+	smsUsageService := services.NewSMSUsageService(smsLogRepo)
+	loginAuditService := services.NewLoginAuditService(loginAuditRepo)
+	walletService := services.NewWalletService(walletRepo)
+	seederService := services.NewSeederService(seeders.NewSafeSeeders(db))
 
 	// Initialize file uploader
 	fileUploader := utils.NewFileUploader(&cfg.FileStorage)
 
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(cfg)
+	healthHandler.SetReadinessChecks(db, notificationService)
+	healthHandler.SetSMSCircuit(smsService)
 	authHandler := handlers.NewAuthHandler(authService, fileUploader)
+	authHandler.SetExposeVerificationCodes(cfg.ExposeVerificationCodesActive())
 	sessionHandler := handlers.NewSessionHandler(sessionService)
 	notificationHandler := handlers.NewNotificationHandler(
-		notificationService, nil, nil, authService.GetJWTManager())
+		notificationService, templateService, nil, authService.GetJWTManager())
+	notificationHandler.SetSMSService(smsSender, cfg.Notification.SMS.MaxBulkSize)
 	gamenetHandler := handlers.NewGamenetHandler(gamenetService, fileUploader)
+	gamenetHandler.SetUserService(userService)
 	userHandler := handlers.NewUserHandler(userService)
+	userHandler.SetConfig(cfg)
+	userHandler.SetFileUploader(fileUploader)
+	adminHandler := handlers.NewAdminHandler(adminService)
 	subscriptionPlanHandler := handlers.NewSubscriptionPlanHandler(subscriptionPlanService)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService)
+	statsHandler := handlers.NewStatsHandler(statsService)
+	smsUsageHandler := handlers.NewSMSUsageHandler(smsUsageService)
+	loginAuditHandler := handlers.NewLoginAuditHandler(loginAuditService)
+	walletHandler := handlers.NewWalletHandler(walletService, permissionService)
+	permissionHandler := handlers.NewPermissionHandler(permissionService)
+	seederHandler := handlers.NewSeederHandler(seederService)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
+	v1.Use(middlewares.APIVersion())
+	v1.Use(middlewares.RequireJSON(
+		// Multipart upload routes
+		"/api/v1/profile/upload-image",
+		"/api/v1/gamenets/",
+		"/api/v1/gamenets/:id",
+		"/api/v1/users/import",
+	))
 	{
 		// Public routes (no authentication required)
 		public := v1.Group("/")
 		{
 			// Health check endpoint
 			public.GET("/health", healthHandler.Check)
+			public.GET("/health/ready", healthHandler.Ready)
 
 			// Authentication routes
 			auth := public.Group("/auth")
 			{
 				// Unified login endpoint (automatically determines user type)
-				auth.POST("/login", authHandler.Login)
+				auth.POST("/login", middlewares.RateLimit(cfg.Security.LoginRateLimitPerMinute, time.Minute), authHandler.Login)
+				auth.POST("/2fa/verify", middlewares.RateLimit(cfg.Security.LoginRateLimitPerMinute, time.Minute), authHandler.VerifyTOTP)
 				auth.POST("/refresh", authHandler.RefreshToken)
 				auth.POST("/logout", authHandler.Logout)
 
 				// Password reset routes
-				auth.POST("/forgot-password", authHandler.ForgotPassword)
+				auth.POST("/forgot-password", middlewares.RateLimit(cfg.Security.ForgotPasswordRateLimitPerMinute, time.Minute), authHandler.ForgotPassword)
 				auth.POST("/reset-password", authHandler.ResetPassword)
 				auth.GET("/validate-reset-token", authHandler.ValidateResetToken)
 			}
@@ -91,14 +161,25 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) {
 			protected.GET("/profile", authHandler.GetProfile)
 			protected.PUT("/profile", authHandler.UpdateProfile)
 			protected.POST("/profile/upload-image", authHandler.UploadProfileImage)
+			protected.GET("/profile/sessions", authHandler.GetSessions)
+			protected.POST("/profile/sessions/revoke-others", authHandler.RevokeOtherSessions)
+			protected.POST("/profile/2fa", middlewares.AdminMiddleware(), authHandler.EnableTOTP)
+			protected.POST("/profile/2fa/confirm", middlewares.AdminMiddleware(), authHandler.ConfirmTOTP)
 			protected.POST("/change-password", authHandler.ChangePassword)
 			protected.POST("/send-email-verification", authHandler.SendEmailVerification)
 			protected.POST("/verify-email-code", authHandler.VerifyEmailCode)
 
+			// Step-up re-authentication for sensitive actions
+			protected.POST("/auth/verify-password", authHandler.VerifyPassword)
+
+			// Single-step email change for admins (password confirmation instead of a code)
+			protected.POST("/auth/change-email", middlewares.AdminMiddleware(), authHandler.ChangeEmail)
+
 			// Session management routes
 			sessions := protected.Group("/sessions")
 			{
 				sessions.GET("/", sessionHandler.GetActiveSessions)
+				sessions.GET("/:session_id", sessionHandler.GetSession)
 				sessions.POST("/:session_id/logout", sessionHandler.LogoutSession)
 				sessions.POST("/logout-others", sessionHandler.LogoutAllOtherSessions)
 				sessions.POST("/logout-all", sessionHandler.LogoutAllSessions)
@@ -109,6 +190,8 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) {
 			{
 				notifications.GET("/", notificationHandler.GetNotifications)
 				notifications.GET("/:id", notificationHandler.GetNotification)
+				notifications.POST("/sms/bulk", middlewares.AdminMiddleware(), notificationHandler.SendBulkSMS)
+				notifications.GET("/sms/:id/status", middlewares.AdminMiddleware(), notificationHandler.GetSMSDeliveryStatus)
 			}
 
 			// Gamenet routes (admin only)
@@ -119,10 +202,43 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) {
 				gamenets.POST("/", middlewares.RequirePermission(permissionService, "gamenets", "create"), gamenetHandler.CreateGamenet)
 				gamenets.GET("/:id", gamenetHandler.GetGamenetByID)
 				gamenets.PUT("/:id", middlewares.RequirePermission(permissionService, "gamenets", "update"), gamenetHandler.UpdateGamenet)
-				gamenets.DELETE("/:id", middlewares.RequirePermission(permissionService, "gamenets", "delete"), gamenetHandler.DeleteGamenet)
+				gamenets.DELETE("/:id", middlewares.RequirePermission(permissionService, "gamenets", "delete"), middlewares.RequireDestructiveConfirmation(cfg, "gamenet delete"), gamenetHandler.DeleteGamenet)
 				gamenets.POST("/:id/resend-credentials", middlewares.RequirePermission(permissionService, "gamenets", "update"), gamenetHandler.ResendCredentials)
+				gamenets.POST("/:id/terminate-sessions", middlewares.RequirePermission(permissionService, "gamenets", "update"), sessionHandler.TerminateAccountSessions("gamenet"))
+				gamenets.POST("/:id/users/:userId", middlewares.RequirePermission(permissionService, "gamenets", "update"), gamenetHandler.LinkUser)
+				gamenets.DELETE("/:id/users/:userId", middlewares.RequirePermission(permissionService, "gamenets", "update"), gamenetHandler.UnlinkUser)
+				gamenets.GET("/:id/subscription", subscriptionHandler.GetActiveSubscription)
+				gamenets.POST("/:id/subscription", middlewares.RequirePermission(permissionService, "gamenets", "update"), subscriptionHandler.Subscribe)
+				gamenets.POST("/:id/subscription/cancel", middlewares.RequirePermission(permissionService, "gamenets", "update"), subscriptionHandler.CancelSubscription)
 			}
 
+			// Gamenet dashboard statistics; registered outside the gamenets group since the
+			// gamenet role doesn't carry gamenets:read. The handler scopes gamenet callers to
+			// their own gamenet.
+			protected.GET("/gamenets/:id/stats", middlewares.RequirePermission(permissionService, "analytics", "view"), statsHandler.GetGamenetStats)
+
+			// Admin accounts
+			protected.GET("/admins", middlewares.AdminMiddleware(), adminHandler.GetAllAdmins)
+
+			// Wallet history; users see their own wallet and gamenets the wallets of their users
+			protected.GET("/users/:id/wallet/transactions", middlewares.RequirePermission(permissionService, "wallet", "view"), walletHandler.ListTransactions)
+
+			// Admin-only user routes; registered outside the users group since the
+			// administrator role doesn't carry the users:* permissions
+			protected.GET("/users/recently-active", middlewares.AdminMiddleware(), userHandler.GetRecentlyActiveUsers)
+			protected.POST("/users/bulk-action", middlewares.AdminMiddleware(), userHandler.BulkAction)
+			protected.POST("/users/:id/restore", middlewares.AdminMiddleware(), userHandler.RestoreUser)
+			protected.POST("/users/:id/terminate-sessions", middlewares.AdminMiddleware(), sessionHandler.TerminateAccountSessions("user"))
+			protected.GET("/users/:id/notifications", middlewares.AdminMiddleware(), notificationHandler.GetUserNotifications)
+			protected.POST("/users/:id/reset-token", middlewares.AdminMiddleware(), middlewares.RequirePermission(permissionService, "users", "issue_reset_token"), middlewares.RequireReauth(authService), authHandler.IssueUserResetLink)
+
+			// Role management (admin only)
+			protected.GET("/permissions", middlewares.AdminMiddleware(), middlewares.RequirePermission(permissionService, "roles", "manage"), permissionHandler.ListPermissions)
+			protected.GET("/users/:id/roles", middlewares.AdminMiddleware(), middlewares.RequirePermission(permissionService, "roles", "manage"), permissionHandler.GetUserRoles)
+			protected.GET("/users/:id/permissions", middlewares.AdminMiddleware(), middlewares.RequirePermission(permissionService, "roles", "manage"), permissionHandler.GetUserPermissions)
+			protected.POST("/users/:id/roles", middlewares.AdminMiddleware(), middlewares.RequirePermission(permissionService, "roles", "manage"), permissionHandler.AssignUserRole)
+			protected.DELETE("/users/:id/roles/:role", middlewares.AdminMiddleware(), middlewares.RequirePermission(permissionService, "roles", "manage"), permissionHandler.RemoveUserRole)
+
 			// User routes (gamenets can manage their users, admins can manage all)
 			users := protected.Group("/users")
 			users.Use(middlewares.RequirePermission(permissionService, "users", "read"))
@@ -130,9 +246,12 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) {
 				users.GET("/", userHandler.GetAllUsers)
 				users.GET("/search-by-identifier", userHandler.SearchUserByIdentifier)
 				users.POST("/", middlewares.RequirePermission(permissionService, "users", "create"), userHandler.CreateUser)
+				users.POST("/batch", middlewares.RequirePermission(permissionService, "users", "create"), userHandler.CreateUsersBatch)
+				users.POST("/import", middlewares.RequirePermission(permissionService, "users", "create"), userHandler.ImportUsers)
+				users.GET("/export", userHandler.ExportUsers)
 				users.GET("/:id", middlewares.RequireResourceOwnership(permissionService, "users"), userHandler.GetUserByID)
 				users.PUT("/:id", middlewares.RequirePermissionAndOwnership(permissionService, "users", "update"), userHandler.UpdateUser)
-				users.DELETE("/:id", middlewares.RequirePermissionAndOwnership(permissionService, "users", "delete"), userHandler.DeleteUser)
+				users.DELETE("/:id", middlewares.RequirePermissionAndOwnership(permissionService, "users", "delete"), middlewares.RequireDestructiveConfirmation(cfg, "user delete"), userHandler.DeleteUser)
 				users.POST("/:id/resend-credentials", middlewares.RequirePermissionAndOwnership(permissionService, "users", "update"), userHandler.ResendCredentials)
 				users.POST("/:id/attach", middlewares.RequirePermission(permissionService, "users", "update"), userHandler.AttachUserToGamenet)
 				users.POST("/:id/detach", middlewares.RequirePermission(permissionService, "users", "update"), userHandler.DetachUserFromGamenet)
@@ -145,17 +264,36 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) {
 				plans.GET("/", subscriptionPlanHandler.GetAllPlans)
 				plans.POST("/", middlewares.RequirePermission(permissionService, "subscription_plans", "create"), subscriptionPlanHandler.CreatePlan)
 				plans.GET("/:id", subscriptionPlanHandler.GetPlan)
+				plans.GET("/:id/history", middlewares.AdminMiddleware(), subscriptionPlanHandler.GetPlanHistory)
+				plans.GET("/:id/delete-impact", middlewares.RequirePermission(permissionService, "subscription_plans", "delete"), subscriptionPlanHandler.GetDeleteImpact)
+				plans.POST("/:id/clone", middlewares.RequirePermission(permissionService, "subscription_plans", "create"), subscriptionPlanHandler.ClonePlan)
 				plans.PUT("/:id", middlewares.RequirePermission(permissionService, "subscription_plans", "update"), subscriptionPlanHandler.UpdatePlan)
-				plans.DELETE("/:id", middlewares.RequirePermission(permissionService, "subscription_plans", "delete"), subscriptionPlanHandler.DeletePlan)
+				plans.POST("/:id/activate", middlewares.RequirePermission(permissionService, "subscription_plans", "update"), subscriptionPlanHandler.ActivatePlan)
+				plans.POST("/:id/deactivate", middlewares.RequirePermission(permissionService, "subscription_plans", "update"), subscriptionPlanHandler.DeactivatePlan)
+				plans.DELETE("/:id", middlewares.RequirePermission(permissionService, "subscription_plans", "delete"), middlewares.RequireDestructiveConfirmation(cfg, "subscription plan delete"), subscriptionPlanHandler.DeletePlan)
 			}
 
 			// Dashboard routes with permission checks
 			admin := protected.Group("/admin")
 			admin.Use(middlewares.RequirePermission(permissionService, "dashboard", "view"))
 			{
-				admin.GET("/dashboard", func(c *gin.Context) {
-					c.JSON(200, gin.H{"message": "Admin dashboard", "user": c.GetString("user_name")})
-				})
+				admin.GET("/dashboard", middlewares.AdminMiddleware(), statsHandler.GetAdminOverview)
+				admin.GET("/stats/overview", middlewares.AdminMiddleware(), statsHandler.GetAdminOverview)
+				admin.GET("/logins", middlewares.AdminMiddleware(), loginAuditHandler.ListLogins)
+				admin.GET("/logins/export", middlewares.AdminMiddleware(), loginAuditHandler.ExportLogins)
+				admin.GET("/login-attempts", middlewares.AdminMiddleware(), loginAuditHandler.ListLoginAttempts)
+				admin.GET("/notifications/rate-limits", middlewares.AdminMiddleware(), notificationHandler.GetRateLimitStats)
+				admin.POST("/notifications/templates/preview", middlewares.AdminMiddleware(), notificationHandler.PreviewTemplate)
+				admin.GET("/seeders", middlewares.AdminMiddleware(), middlewares.RequirePermission(permissionService, "seeders", "run"), seederHandler.ListSeeders)
+				admin.POST("/seeders/:name/run", middlewares.AdminMiddleware(), middlewares.RequirePermission(permissionService, "seeders", "run"), seederHandler.RunSeeder)
+				admin.POST("/admins/:id/terminate-sessions", middlewares.AdminMiddleware(), sessionHandler.TerminateAccountSessions("admin"))
+			}
+
+			// Third-party integration routes (admin only)
+			integrations := protected.Group("/integrations")
+			integrations.Use(middlewares.AdminMiddleware())
+			{
+				integrations.GET("/sms/stats", smsUsageHandler.GetSMSStats)
 			}
 
 			// User dashboard routes
@@ -180,4 +318,7 @@ func SetupRoutes(router *gin.Engine, cfg *config.Config, db *sql.DB) {
 
 	// Root health endpoint (for load balancers)
 	router.GET("/health", healthHandler.Check)
+	router.GET("/health/ready", healthHandler.Ready)
+
+	return notificationService.Shutdown, notificationService
 }
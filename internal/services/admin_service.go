@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+)
+
+// adminService implements AdminServiceInterface
+type adminService struct {
+	adminRepo repositories.AdminRepository
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(adminRepo repositories.AdminRepository) AdminServiceInterface {
+	return &adminService{adminRepo: adminRepo}
+}
+
+// GetAll retrieves all admins
+func (s *adminService) GetAll(ctx context.Context) ([]models.AdminResponse, error) {
+	admins, err := s.adminRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admins: %w", err)
+	}
+
+	responses := make([]models.AdminResponse, 0, len(admins))
+	for _, admin := range admins {
+		responses = append(responses, admin.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// Search searches admins with pagination
+func (s *adminService) Search(ctx context.Context, req *models.UserSearchRequest) (*models.AdminSearchResponse, error) {
+	result, err := s.adminRepo.Search(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search admins: %w", err)
+	}
+
+	return result, nil
+}
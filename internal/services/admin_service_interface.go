@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// AdminServiceInterface defines the interface for admin account business logic
+type AdminServiceInterface interface {
+	// GetAll retrieves all admins
+	GetAll(ctx context.Context) ([]models.AdminResponse, error)
+
+	// Search searches admins by name, mobile or email with pagination
+	Search(ctx context.Context, req *models.UserSearchRequest) (*models.AdminSearchResponse, error)
+}
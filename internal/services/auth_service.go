@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"crypto/rand"
-	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gatehide/gatehide-api/config"
@@ -14,6 +16,68 @@ import (
 	"github.com/gatehide/gatehide-api/internal/utils"
 )
 
+// ErrAccountSuspended is returned when a suspended user tries to log in
+var ErrAccountSuspended = errors.New("account suspended")
+
+// ErrLoginMethodDisabled is returned when a login method is switched off in the configuration
+var ErrLoginMethodDisabled = errors.New("this login method is disabled")
+
+// ErrInvalidCredentials is returned when a login email or password is wrong. The message
+// never says which, so login responses cannot be used to probe for accounts.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// errUnknownEmail and errWrongPassword tell the two invalid credential cases apart for
+// the login audit; both match and read as ErrInvalidCredentials
+var (
+	errUnknownEmail  = fmt.Errorf("%w", ErrInvalidCredentials)
+	errWrongPassword = fmt.Errorf("%w", ErrInvalidCredentials)
+)
+
+// ErrInvalidPassword is returned when a password confirmation does not match
+var ErrInvalidPassword = errors.New("invalid password")
+
+// ErrTooManyReauthAttempts is matched by errors returned when an account has exceeded its
+// failed password confirmations
+var ErrTooManyReauthAttempts = errors.New("too many password verification attempts")
+
+// ErrTooManyLoginAttempts is matched by errors returned while an email is locked out
+// after repeated failed logins
+var ErrTooManyLoginAttempts = errors.New("too many login attempts, try again later")
+
+// ErrMobileInUse is returned when a profile update takes a mobile number held by
+// another user
+var ErrMobileInUse = errors.New("mobile number already in use")
+
+// ErrVerificationCodeCooldown is matched by errors returned when a new email verification
+// code is requested before the resend cooldown has passed
+var ErrVerificationCodeCooldown = errors.New("please wait before requesting another code")
+
+// TooManyAttemptsError reports how long an account is blocked from further attempts
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+	// Kind is the error matched by errors.Is; ErrTooManyReauthAttempts when nil
+	Kind error
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", e.kind(), e.RetryAfter.Round(time.Second))
+}
+
+// Is makes errors.Is(err, e.Kind) match
+func (e *TooManyAttemptsError) Is(target error) bool {
+	return target == e.kind()
+}
+
+func (e *TooManyAttemptsError) kind() error {
+	if e.Kind == nil {
+		return ErrTooManyReauthAttempts
+	}
+	return e.Kind
+}
+
+// reauthAttemptWindow is the window over which failed password confirmations are counted
+const reauthAttemptWindow = 15 * time.Minute
+
 // AuthService handles authentication business logic
 type AuthService struct {
 	userRepo              repositories.UserRepository
@@ -21,10 +85,19 @@ type AuthService struct {
 	gamenetRepo           repositories.GamenetRepository
 	passwordResetRepo     repositories.PasswordResetRepositoryInterface
 	sessionRepo           repositories.SessionRepositoryInterface
-	emailVerificationRepo *repositories.EmailVerificationRepository
+	loginAuditRepo        repositories.LoginAuditRepositoryInterface
+	revokedTokenRepo      repositories.RevokedTokenRepositoryInterface
+	loginLockoutRepo      repositories.LoginLockoutRepositoryInterface
+	emailVerificationRepo repositories.EmailVerificationRepositoryInterface
 	notificationService   NotificationServiceInterface
 	permissionService     PermissionServiceInterface
+	templateRenderer      *TemplateRenderer
 	jwtManager            *utils.JWTManager
+	emailValidator        *utils.EmailDomainValidator
+	links                 *utils.FrontendLinks
+	reauthLimiter         *utils.AttemptLimiter
+	loginLimiter          *utils.AttemptLimiter
+	totpLimiter           *utils.AttemptLimiter
 	config                *config.Config
 }
 
@@ -35,7 +108,8 @@ func NewAuthService(
 	gamenetRepo repositories.GamenetRepository,
 	passwordResetRepo repositories.PasswordResetRepositoryInterface,
 	sessionRepo repositories.SessionRepositoryInterface,
-	emailVerificationRepo *repositories.EmailVerificationRepository,
+	loginAuditRepo repositories.LoginAuditRepositoryInterface,
+	emailVerificationRepo repositories.EmailVerificationRepositoryInterface,
 	notificationService NotificationServiceInterface,
 	permissionService PermissionServiceInterface,
 	cfg *config.Config,
@@ -46,30 +120,172 @@ func NewAuthService(
 		gamenetRepo:           gamenetRepo,
 		passwordResetRepo:     passwordResetRepo,
 		sessionRepo:           sessionRepo,
+		loginAuditRepo:        loginAuditRepo,
 		emailVerificationRepo: emailVerificationRepo,
 		notificationService:   notificationService,
 		permissionService:     permissionService,
+		templateRenderer:      NewTemplateRenderer(nil),
 		jwtManager:            utils.NewJWTManager(cfg),
-		config:                cfg,
+		emailValidator:        utils.NewEmailDomainValidator(&cfg.EmailPolicy),
+		links:                 utils.NewFrontendLinks(cfg.App.FrontendBaseURL),
+		reauthLimiter:         utils.NewAttemptLimiter(cfg.Security.ReauthMaxAttempts, reauthAttemptWindow),
+		loginLimiter: utils.NewAttemptLimiterWithLockout(
+			cfg.Security.MaxLoginAttempts,
+			time.Duration(cfg.Security.LoginAttemptWindowMinutes)*time.Minute,
+			time.Duration(cfg.Security.LoginLockoutMinutes)*time.Minute,
+		),
+		totpLimiter: utils.NewAttemptLimiter(totpMaxAttempts, utils.TOTPChallengeTTL),
+		config:      cfg,
 	}
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// SetRevokedTokenRepository enables the token blacklist: logging out revokes the token,
+// and revoked tokens are rejected until they expire
+func (s *AuthService) SetRevokedTokenRepository(revokedTokenRepo repositories.RevokedTokenRepositoryInterface) {
+	s.revokedTokenRepo = revokedTokenRepo
+}
+
+// SetLoginLockoutRepository keeps the failed login counts in the database, so lockouts
+// survive restarts and apply across instances. Without it they are kept in memory.
+func (s *AuthService) SetLoginLockoutRepository(loginLockoutRepo repositories.LoginLockoutRepositoryInterface) {
+	s.loginLockoutRepo = loginLockoutRepo
+}
+
+// SetTemplateService sets the template service used to render account emails.
+// Without it the built-in templates are used.
+func (s *AuthService) SetTemplateService(templates TemplateServiceInterface) {
+	s.templateRenderer = NewTemplateRenderer(templates)
+}
+
+// ValidateToken validates a JWT token and returns the claims. Revoked tokens are
+// rejected with utils.ErrTokenRevoked.
 func (s *AuthService) ValidateToken(tokenString string) (*utils.JWTClaims, error) {
-	return s.jwtManager.ValidateToken(tokenString)
+	claims, err := s.jwtManager.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTokenRevoked(claims.ID); err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
 
-// LoginWithSession performs login and creates a session
-func (s *AuthService) LoginWithSession(email, password string, rememberMe bool, deviceInfo, ipAddress, userAgent string) (*models.LoginResponse, error) {
+// checkTokenRevoked returns utils.ErrTokenRevoked when the token with the given jti
+// has been revoked. Tokens issued before jti claims were added cannot be revoked.
+func (s *AuthService) checkTokenRevoked(jti string) error {
+	if s.revokedTokenRepo == nil || jti == "" {
+		return nil
+	}
+
+	revoked, err := s.revokedTokenRepo.IsTokenRevoked(jti)
+	if err != nil {
+		return fmt.Errorf("%w: %v", utils.ErrTokenInvalid, err)
+	}
+	if revoked {
+		return utils.ErrTokenRevoked
+	}
+	return nil
+}
+
+// revokeToken blacklists a token until it can no longer be used or refreshed
+func (s *AuthService) revokeToken(token string) error {
+	if s.revokedTokenRepo == nil {
+		return nil
+	}
+
+	claims, err := s.jwtManager.ValidateToken(token)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		// Expired or unidentifiable tokens have nothing to revoke
+		return nil
+	}
+
+	return s.revokedTokenRepo.RevokeToken(claims.ID, claims.ExpiresAt.Add(s.jwtManager.RefreshGracePeriod()))
+}
+
+// LogoutSession revokes the given token and deactivates the session created for it at
+// login, so it no longer shows up among the account's active sessions. A token without
+// a session is not an error.
+func (s *AuthService) LogoutSession(token string) error {
+	if err := s.revokeToken(token); err != nil {
+		return err
+	}
+
+	session, err := s.sessionRepo.GetSessionByToken(token)
+	if err != nil {
+		return fmt.Errorf("failed to look up session: %w", err)
+	}
+	if session == nil || !session.IsActive {
+		return nil
+	}
+
+	if err := s.sessionRepo.DeactivateSession(session.ID); err != nil {
+		return fmt.Errorf("failed to deactivate session: %w", err)
+	}
+	return nil
+}
+
+// GetActiveSessions returns the account's active sessions. The responses never carry
+// the session token, since it is the bearer token itself.
+func (s *AuthService) GetActiveSessions(userID int, userType string) ([]models.SessionResponse, error) {
+	sessions, err := s.sessionRepo.GetActiveSessionsByUserID(userID, userType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	responses := make([]models.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, session.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// RevokeOtherSessions deactivates every session of the account except the one created
+// for currentToken, so the caller stays logged in while other devices are logged out
+func (s *AuthService) RevokeOtherSessions(userID int, userType, currentToken string) error {
+	if err := s.sessionRepo.DeactivateAllOtherUserSessions(userID, userType, currentToken); err != nil {
+		return fmt.Errorf("failed to revoke other sessions: %w", err)
+	}
+	return nil
+}
+
+// RequireLoginMethod returns an error wrapping ErrLoginMethodDisabled when the given
+// login method (e.g. config.LoginMethodPassword) is switched off.
+// Every login entry point checks its method before looking at the credentials.
+func (s *AuthService) RequireLoginMethod(method string) error {
+	if !s.config.LoginMethodEnabled(method) {
+		return fmt.Errorf("%s login: %w", method, ErrLoginMethodDisabled)
+	}
+	return nil
+}
+
+// LoginWithSession performs login and creates a session. sessionToken is the token
+// the client was signed in with before, if any; its session is renewed instead of
+// opening another one when it belongs to the same account and is still active.
+func (s *AuthService) LoginWithSession(email, password string, rememberMe bool, deviceInfo, ipAddress, userAgent, sessionToken string) (*models.LoginResponse, error) {
 	loginResponse, err := s.Login(email, password, rememberMe)
 	if err != nil {
+		s.recordFailedLogin(email, deviceInfo, ipAddress, userAgent, loginFailureReason(err))
+		return nil, err
+	}
+
+	// Two-factor logins get their session once the code is confirmed
+	if loginResponse.TwoFactorRequired() {
+		return loginResponse, nil
+	}
+
+	if err := s.createLoginSession(loginResponse, deviceInfo, ipAddress, userAgent, sessionToken); err != nil {
 		return nil, err
 	}
+	return loginResponse, nil
+}
 
+// createLoginSession records the session for a token just issued by a login
+func (s *AuthService) createLoginSession(loginResponse *models.LoginResponse, deviceInfo, ipAddress, userAgent, sessionToken string) error {
 	// Create session for the login
 	claims, err := s.jwtManager.ValidateToken(loginResponse.Token)
 	if err != nil {
-		return nil, fmt.Errorf("failed to validate generated token: %w", err)
+		return fmt.Errorf("failed to validate generated token: %w", err)
 	}
 
 	// Create session in database
@@ -84,6 +300,19 @@ func (s *AuthService) LoginWithSession(email, password string, rememberMe bool,
 		userAgentPtr = &userAgent
 	}
 
+	s.recordSuccessfulLogin(claims.UserID, claims.UserType, ipAddressPtr, userAgentPtr, deviceInfoPtr)
+
+	// Renew the session the client was already signed in with instead of piling up duplicates
+	if session := s.findReusableSession(claims.UserID, claims.UserType, sessionToken); session != nil {
+		err = s.sessionRepo.RenewSession(session.ID, loginResponse.Token, ipAddressPtr, userAgentPtr, loginResponse.ExpiresAt.Time)
+		if err == nil {
+			return nil
+		}
+		fmt.Printf("Warning: failed to renew session %d, creating a new one: %v\n", session.ID, err)
+	}
+
+	s.evictOldestSessions(claims.UserID, claims.UserType)
+
 	_, err = s.sessionRepo.CreateSession(
 		claims.UserID,
 		claims.UserType,
@@ -91,30 +320,270 @@ func (s *AuthService) LoginWithSession(email, password string, rememberMe bool,
 		deviceInfoPtr,
 		ipAddressPtr,
 		userAgentPtr,
-		loginResponse.ExpiresAt,
+		loginResponse.ExpiresAt.Time,
 	)
 	if err != nil {
 		// Log error but don't fail the login
 		fmt.Printf("Warning: failed to create session for user %d: %v\n", claims.UserID, err)
 	}
 
-	return loginResponse, nil
+	return nil
+}
+
+// evictOldestSessions makes room for a new session by deactivating the account's oldest
+// active sessions, so that with the new one it stays within Security.MaxConcurrentSessions
+func (s *AuthService) evictOldestSessions(userID int, userType string) {
+	limit := s.config.Security.MaxConcurrentSessions
+	if limit <= 0 {
+		return
+	}
+
+	sessions, err := s.sessionRepo.GetActiveSessionsByUserID(userID, userType)
+	if err != nil {
+		fmt.Printf("Warning: failed to look up sessions to enforce the limit for %s %d: %v\n", userType, userID, err)
+		return
+	}
+	if len(sessions) < limit {
+		return
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	for _, session := range sessions[:len(sessions)-limit+1] {
+		if err := s.sessionRepo.DeactivateSession(session.ID); err != nil {
+			fmt.Printf("Warning: failed to end session %d over the concurrent session limit: %v\n", session.ID, err)
+		}
+	}
+}
+
+// findReusableSession returns the session of the token the client was signed in with,
+// if it is active and belongs to the account logging in. Sessions are only reused for
+// the client holding their token, so two devices never end up sharing one.
+func (s *AuthService) findReusableSession(userID int, userType, sessionToken string) *models.UserSession {
+	if sessionToken == "" {
+		return nil
+	}
+
+	session, err := s.sessionRepo.GetSessionByToken(sessionToken)
+	if err != nil {
+		fmt.Printf("Warning: failed to look up the previous session for %s %d: %v\n", userType, userID, err)
+		return nil
+	}
+	if session == nil || !session.IsActive || session.UserID != userID || session.UserType != userType {
+		return nil
+	}
+
+	return session
+}
+
+// loginFailureReason returns the reason category recorded for a failed login
+func loginFailureReason(err error) string {
+	switch {
+	case errors.Is(err, errUnknownEmail):
+		return models.LoginFailureUnknownEmail
+	case errors.Is(err, errWrongPassword):
+		return models.LoginFailureWrongPassword
+	case errors.Is(err, ErrTooManyLoginAttempts):
+		return models.LoginFailureLocked
+	case errors.Is(err, ErrAccountSuspended):
+		return models.LoginFailureSuspended
+	case errors.Is(err, ErrLoginMethodDisabled):
+		return models.LoginFailureMethodDisabled
+	case errors.Is(err, ErrInvalidTOTPCode):
+		return models.LoginFailureInvalidTOTP
+	default:
+		return models.LoginFailureOther
+	}
+}
+
+// recordSuccessfulLogin stores a successful login for the login activity feed
+func (s *AuthService) recordSuccessfulLogin(userID int, userType string, ipAddress, userAgent, deviceInfo *string) {
+	if s.loginAuditRepo == nil {
+		return
+	}
+
+	if err := s.loginAuditRepo.RecordSuccessfulLogin(userID, userType, ipAddress, userAgent, deviceInfo); err != nil {
+		fmt.Printf("Warning: failed to record successful login for %s %d: %v\n", userType, userID, err)
+	}
 }
 
-// RefreshToken generates a new token with extended expiration
+// maxAttemptedEmailLength is the size of the login_attempts email column
+const maxAttemptedEmailLength = 255
+
+// recordFailedLogin stores a failed login attempt for the login activity feed. The
+// email is whatever was typed in and may not belong to any account, so it is only
+// normalized and truncated to fit, never linked to an account.
+func (s *AuthService) recordFailedLogin(email, deviceInfo, ipAddress, userAgent, reason string) {
+	if s.loginAuditRepo == nil {
+		return
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if runes := []rune(email); len(runes) > maxAttemptedEmailLength {
+		email = string(runes[:maxAttemptedEmailLength])
+	}
+
+	var deviceInfoPtr, ipAddressPtr, userAgentPtr *string
+	if deviceInfo != "" {
+		deviceInfoPtr = &deviceInfo
+	}
+	if ipAddress != "" {
+		ipAddressPtr = &ipAddress
+	}
+	if userAgent != "" {
+		userAgentPtr = &userAgent
+	}
+
+	if err := s.loginAuditRepo.RecordFailedLogin(email, ipAddressPtr, userAgentPtr, deviceInfoPtr, reason); err != nil {
+		fmt.Printf("Warning: failed to record failed login for %s: %v\n", email, err)
+	}
+}
+
+// rehashPasswordIfNeeded upgrades a verified password hash to the configured algorithm/parameters
+func (s *AuthService) rehashPasswordIfNeeded(userType string, userID int, password, hash string) {
+	if !models.PasswordNeedsRehash(hash) {
+		return
+	}
+
+	newHash, err := models.HashPassword(password)
+	if err != nil {
+		fmt.Printf("Warning: failed to rehash password for %s %d: %v\n", userType, userID, err)
+		return
+	}
+
+	switch userType {
+	case "user":
+		err = s.userRepo.UpdatePassword(userID, newHash)
+	case "admin":
+		err = s.adminRepo.UpdatePassword(userID, newHash)
+	case "gamenet":
+		err = s.gamenetRepo.Update(userID, &models.GamenetUpdateRequest{Password: &newHash})
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to store rehashed password for %s %d: %v\n", userType, userID, err)
+	}
+}
+
+// RefreshToken generates a new token with extended expiration. Revoked tokens cannot be
+// refreshed.
 func (s *AuthService) RefreshToken(tokenString string, rememberMe bool) (string, error) {
+	// The jti is read unverified here; RefreshToken verifies the token itself
+	if err := s.checkTokenRevoked(utils.TokenID(tokenString)); err != nil {
+		return "", err
+	}
 	return s.jwtManager.RefreshToken(tokenString, rememberMe)
 }
 
 // Login unified authentication that determines user type by email
 func (s *AuthService) Login(email, password string, rememberMe bool) (*models.LoginResponse, error) {
+	if err := s.RequireLoginMethod(config.LoginMethodPassword); err != nil {
+		return nil, err
+	}
+
+	// Lock out an email after repeated failures, even if the next password is correct.
+	// The attempt is counted up front so concurrent guesses can't all get past the check.
+	key := loginAttemptKey(email)
+	allowed, retryAfter, err := s.reserveLoginAttempt(key)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		fmt.Printf("Login locked out: Email=%s, Time=%s\n", key, time.Now().Format(time.RFC3339))
+		return nil, &TooManyAttemptsError{RetryAfter: retryAfter, Kind: ErrTooManyLoginAttempts}
+	}
+
+	response, err := s.authenticate(email, password, rememberMe)
+	switch {
+	case err == nil:
+		s.resetLoginAttempts(key)
+	case !errors.Is(err, ErrInvalidCredentials):
+		// Only wrong passwords count towards the lockout
+		s.releaseLoginAttempt(key)
+	}
+	return response, err
+}
+
+// reserveLoginAttempt counts a login attempt for key up front, in the database when a
+// lockout repository is set and in memory otherwise. It reports whether the attempt may
+// go ahead and, when it may not, how long until it may.
+func (s *AuthService) reserveLoginAttempt(key string) (bool, time.Duration, error) {
+	if s.loginLockoutRepo == nil {
+		allowed, retryAfter := s.loginLimiter.Reserve(key)
+		return allowed, retryAfter, nil
+	}
+
+	security := s.config.Security
+	if security.MaxLoginAttempts <= 0 {
+		return true, 0, nil
+	}
+	allowed, retryAfter, err := s.loginLockoutRepo.ReserveAttempt(key, security.MaxLoginAttempts,
+		time.Duration(security.LoginAttemptWindowMinutes)*time.Minute,
+		time.Duration(security.LoginLockoutMinutes)*time.Minute)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to check login attempts: %w", err)
+	}
+	return allowed, retryAfter, nil
+}
+
+// releaseLoginAttempt gives back an attempt reserved for key that was not a wrong password
+func (s *AuthService) releaseLoginAttempt(key string) {
+	if s.loginLockoutRepo == nil {
+		s.loginLimiter.Release(key)
+		return
+	}
+	if err := s.loginLockoutRepo.ReleaseAttempt(key); err != nil {
+		fmt.Printf("Warning: failed to release login attempt for %s: %v\n", key, err)
+	}
+}
+
+// resetLoginAttempts clears the failures counted for key after a successful login
+func (s *AuthService) resetLoginAttempts(key string) {
+	if s.loginLockoutRepo == nil {
+		s.loginLimiter.Reset(key)
+		return
+	}
+	if err := s.loginLockoutRepo.ResetAttempts(key); err != nil {
+		fmt.Printf("Warning: failed to reset login attempts for %s: %v\n", key, err)
+	}
+}
+
+// loginAttemptKey identifies the account a login is for whichever way it was typed
+func loginAttemptKey(identifier string) string {
+	if mobile, ok := utils.NormalizeIranianMobile(identifier); ok {
+		return mobile
+	}
+	return strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// authenticate checks the email and password against users, admins and gamenets in
+// turn and issues a token for the first account they match. Users may also log in
+// with their mobile number; admins and gamenets log in by email only.
+func (s *AuthService) authenticate(email, password string, rememberMe bool) (*models.LoginResponse, error) {
+	mobile, byMobile := utils.NormalizeIranianMobile(email)
+
 	// First, try to find the user as a regular user
-	user, userErr := s.userRepo.GetByEmail(email)
+	var user *models.User
+	var userErr error
+	if byMobile {
+		user, userErr = s.userRepo.GetByMobile(mobile)
+	} else {
+		user, userErr = s.userRepo.GetByEmail(email)
+	}
+	if userErr != nil && !errors.Is(userErr, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to look up user: %w", userErr)
+	}
 	if userErr == nil {
 		// Verify password for user
-		if models.CheckPassword(password, user.Password) {
+		if models.CheckPassword(password, string(user.Password)) {
+			if user.IsSuspended() {
+				return nil, ErrAccountSuspended
+			}
+
+			s.rehashPasswordIfNeeded("user", user.ID, password, string(user.Password))
+
 			// Generate JWT token for user
-			token, err := s.jwtManager.GenerateToken(user.ID, "user", user.Email, user.Name, rememberMe)
+			token, expiresAt, err := s.jwtManager.GenerateTokenWithExpiry(user.ID, "user", user.Email, user.Name, rememberMe)
 			if err != nil {
 				return nil, fmt.Errorf("failed to generate token: %w", err)
 			}
@@ -124,9 +593,6 @@ func (s *AuthService) Login(email, password string, rememberMe bool) (*models.Lo
 				fmt.Printf("Warning: failed to update last login for user %d: %v\n", user.ID, err)
 			}
 
-			// Calculate token expiration
-			expiresAt := time.Now().Add(time.Duration(s.config.Security.JWTExpiration) * time.Hour)
-
 			// Get user permissions
 			permissions, err := s.permissionService.GetUserPermissionsByID(user.ID, "user")
 			if err != nil {
@@ -139,54 +605,49 @@ func (s *AuthService) Login(email, password string, rememberMe bool) (*models.Lo
 				UserType:    "user",
 				User:        user.ToResponse(),
 				Permissions: permissions,
-				ExpiresAt:   expiresAt,
+				ExpiresAt:   models.NewTimestamp(expiresAt),
 			}, nil
 		}
 	}
 
 	// If user login failed, try admin login
-	admin, adminErr := s.adminRepo.GetByEmail(email)
+	var admin *models.Admin
+	adminErr := repositories.ErrNotFound
+	if !byMobile {
+		admin, adminErr = s.adminRepo.GetByEmail(email)
+	}
+	if adminErr != nil && !errors.Is(adminErr, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to look up admin: %w", adminErr)
+	}
 	if adminErr == nil {
 		// Verify password for admin
-		if models.CheckPassword(password, admin.Password) {
-			// Generate JWT token for admin
-			token, err := s.jwtManager.GenerateToken(admin.ID, "admin", admin.Email, admin.Name, rememberMe)
-			if err != nil {
-				return nil, fmt.Errorf("failed to generate token: %w", err)
-			}
+		if models.CheckPassword(password, string(admin.Password)) {
+			s.rehashPasswordIfNeeded("admin", admin.ID, password, string(admin.Password))
 
-			// Update last login
-			if err := s.adminRepo.UpdateLastLogin(admin.ID); err != nil {
-				fmt.Printf("Warning: failed to update last login for admin %d: %v\n", admin.ID, err)
+			// Admins with two-factor authentication must confirm a TOTP code first
+			if admin.TOTPEnabled() {
+				return s.totpChallenge(admin, rememberMe)
 			}
-
-			// Calculate token expiration
-			expiresAt := time.Now().Add(time.Duration(s.config.Security.JWTExpiration) * time.Hour)
-
-			// Get admin permissions
-			permissions, err := s.permissionService.GetUserPermissionsByID(admin.ID, "admin")
-			if err != nil {
-				fmt.Printf("Warning: failed to get admin permissions: %v\n", err)
-				permissions = []string{} // Default to empty permissions
-			}
-
-			return &models.LoginResponse{
-				Token:       token,
-				UserType:    "admin",
-				User:        admin.ToResponse(),
-				Permissions: permissions,
-				ExpiresAt:   expiresAt,
-			}, nil
+			return s.issueAdminLogin(admin, rememberMe)
 		}
 	}
 
 	// If both failed, try gamenet login
-	gamenet, gamenetErr := s.gamenetRepo.GetByEmail(email)
+	var gamenet *models.Gamenet
+	gamenetErr := repositories.ErrNotFound
+	if !byMobile {
+		gamenet, gamenetErr = s.gamenetRepo.GetByEmail(email)
+	}
+	if gamenetErr != nil && !errors.Is(gamenetErr, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to look up gamenet: %w", gamenetErr)
+	}
 	if gamenetErr == nil {
 		// Verify password for gamenet
-		if models.CheckPassword(password, gamenet.Password) {
+		if models.CheckPassword(password, string(gamenet.Password)) {
+			s.rehashPasswordIfNeeded("gamenet", gamenet.ID, password, string(gamenet.Password))
+
 			// Generate JWT token for gamenet
-			token, err := s.jwtManager.GenerateToken(gamenet.ID, "gamenet", gamenet.Email, gamenet.Name, rememberMe)
+			token, expiresAt, err := s.jwtManager.GenerateTokenWithExpiry(gamenet.ID, "gamenet", gamenet.Email, gamenet.Name, rememberMe)
 			if err != nil {
 				return nil, fmt.Errorf("failed to generate token: %w", err)
 			}
@@ -196,9 +657,6 @@ func (s *AuthService) Login(email, password string, rememberMe bool) (*models.Lo
 				fmt.Printf("Warning: failed to update last login for gamenet %d: %v\n", gamenet.ID, err)
 			}
 
-			// Calculate token expiration
-			expiresAt := time.Now().Add(time.Duration(s.config.Security.JWTExpiration) * time.Hour)
-
 			// Get gamenet permissions
 			permissions, err := s.permissionService.GetUserPermissionsByID(gamenet.ID, "gamenet")
 			if err != nil {
@@ -211,13 +669,45 @@ func (s *AuthService) Login(email, password string, rememberMe bool) (*models.Lo
 				UserType:    "gamenet",
 				User:        gamenet.ToResponse(),
 				Permissions: permissions,
-				ExpiresAt:   expiresAt,
+				ExpiresAt:   models.NewTimestamp(expiresAt),
 			}, nil
 		}
 	}
 
 	// If all three failed, return invalid credentials error
-	return nil, fmt.Errorf("invalid credentials")
+	if userErr != nil && adminErr != nil && gamenetErr != nil {
+		return nil, errUnknownEmail
+	}
+	return nil, errWrongPassword
+}
+
+// issueAdminLogin issues a token for an admin whose credentials have been verified
+func (s *AuthService) issueAdminLogin(admin *models.Admin, rememberMe bool) (*models.LoginResponse, error) {
+	// Generate JWT token for admin
+	token, expiresAt, err := s.jwtManager.GenerateTokenWithExpiry(admin.ID, "admin", admin.Email, admin.Name, rememberMe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	// Update last login
+	if err := s.adminRepo.UpdateLastLogin(admin.ID); err != nil {
+		fmt.Printf("Warning: failed to update last login for admin %d: %v\n", admin.ID, err)
+	}
+
+	// Get admin permissions
+	permissions, err := s.permissionService.GetUserPermissionsByID(admin.ID, "admin")
+	if err != nil {
+		fmt.Printf("Warning: failed to get admin permissions: %v\n", err)
+		permissions = []string{} // Default to empty permissions
+	}
+
+	return &models.LoginResponse{
+		Token:       token,
+		UserType:    "admin",
+		User:        admin.ToResponse(),
+		Permissions: permissions,
+		ExpiresAt:   models.NewTimestamp(expiresAt),
+	}, nil
 }
 
 // GetUserFromToken extracts user information from a JWT token
@@ -245,13 +735,24 @@ func (s *AuthService) GetGamenetByID(gamenetID int) (*models.Gamenet, error) {
 	return s.gamenetRepo.GetByID(gamenetID)
 }
 
-// UpdateUserProfile updates a user's profile
+// UpdateUserProfile updates a user's profile. It returns ErrMobileInUse when the new
+// mobile number is held by another user, including a deleted one.
 func (s *AuthService) UpdateUserProfile(userID int, name, mobile, image string) (*models.UserResponse, error) {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
+	if mobile != "" && mobile != user.Mobile {
+		existingUser, err := s.userRepo.GetByMobileIncludingDeleted(mobile)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to check mobile: %w", err)
+		}
+		if err == nil && existingUser.ID != userID {
+			return nil, ErrMobileInUse
+		}
+	}
+
 	// Update fields
 	user.Name = name
 	user.Mobile = mobile
@@ -370,6 +871,50 @@ func (s *AuthService) UpdateAdminEmail(adminID int, newEmail string) (*models.Ad
 	return &response, nil
 }
 
+// ChangeAdminEmail changes an admin's email in a single step after confirming the current password
+func (s *AuthService) ChangeAdminEmail(adminID int, currentPassword, newEmail string) (*models.AdminResponse, error) {
+	admin, err := s.adminRepo.GetByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("admin not found: %w", err)
+	}
+
+	// Verify current password
+	if !models.CheckPassword(currentPassword, string(admin.Password)) {
+		return nil, fmt.Errorf("invalid password")
+	}
+
+	if newEmail == admin.Email {
+		return nil, fmt.Errorf("new email must be different from current email")
+	}
+
+	// Check if email already exists in the system
+	emailExists, err := s.CheckEmailExists(newEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check email availability: %w", err)
+	}
+	if emailExists {
+		return nil, fmt.Errorf("email already in use")
+	}
+
+	oldEmail := admin.Email
+	response, err := s.UpdateAdminEmail(adminID, newEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	// Log the email change for security auditing
+	fmt.Printf("Admin email change: ID=%d, OldEmail=%s, NewEmail=%s, Time=%s\n",
+		adminID, oldEmail, newEmail, time.Now().Format(time.RFC3339))
+
+	// Notify the previous address about the change
+	if err := s.sendEmailChangeNotification(adminID, oldEmail, admin.Name, newEmail); err != nil {
+		fmt.Printf("Warning: failed to send email change notification: %v\n", err)
+		// Don't return error here, as the email was changed successfully
+	}
+
+	return response, nil
+}
+
 // UpdateGamenetEmail updates a gamenet's email
 func (s *AuthService) UpdateGamenetEmail(gamenetID int, newEmail string) (*models.GamenetResponse, error) {
 	// Check if gamenet exists
@@ -427,6 +972,9 @@ func (s *AuthService) generateResetToken() (string, error) {
 func (s *AuthService) ForgotPassword(email string) error {
 	// First, try to find the user as a regular user
 	user, userErr := s.userRepo.GetByEmail(email)
+	if userErr != nil && !errors.Is(userErr, repositories.ErrNotFound) {
+		return fmt.Errorf("failed to look up user: %w", userErr)
+	}
 	if userErr == nil {
 		// Invalidate any existing tokens for this user
 		if err := s.passwordResetRepo.InvalidateUserTokens(user.ID, "user"); err != nil {
@@ -448,7 +996,7 @@ func (s *AuthService) ForgotPassword(email string) error {
 		}
 
 		// Send password reset email
-		if err := s.sendPasswordResetEmail(user.Email, user.Name, token); err != nil {
+		if err := s.sendPasswordResetEmail(user.ID, "user", user.Email, user.Name, token); err != nil {
 			fmt.Printf("Warning: failed to send password reset email to %s: %v\n", email, err)
 			// Don't return error here, as the token was created successfully
 		}
@@ -458,6 +1006,9 @@ func (s *AuthService) ForgotPassword(email string) error {
 
 	// If user not found, try admin
 	admin, adminErr := s.adminRepo.GetByEmail(email)
+	if adminErr != nil && !errors.Is(adminErr, repositories.ErrNotFound) {
+		return fmt.Errorf("failed to look up admin: %w", adminErr)
+	}
 	if adminErr == nil {
 		// Invalidate any existing tokens for this admin
 		if err := s.passwordResetRepo.InvalidateUserTokens(admin.ID, "admin"); err != nil {
@@ -479,7 +1030,7 @@ func (s *AuthService) ForgotPassword(email string) error {
 		}
 
 		// Send password reset email
-		if err := s.sendPasswordResetEmail(admin.Email, admin.Name, token); err != nil {
+		if err := s.sendPasswordResetEmail(admin.ID, "admin", admin.Email, admin.Name, token); err != nil {
 			fmt.Printf("Warning: failed to send password reset email to %s: %v\n", email, err)
 			// Don't return error here, as the token was created successfully
 		}
@@ -489,6 +1040,9 @@ func (s *AuthService) ForgotPassword(email string) error {
 
 	// If both not found, try gamenet
 	gamenet, gamenetErr := s.gamenetRepo.GetByEmail(email)
+	if gamenetErr != nil && !errors.Is(gamenetErr, repositories.ErrNotFound) {
+		return fmt.Errorf("failed to look up gamenet: %w", gamenetErr)
+	}
 	if gamenetErr == nil {
 		// Invalidate any existing tokens for this gamenet
 		if err := s.passwordResetRepo.InvalidateUserTokens(gamenet.ID, "gamenet"); err != nil {
@@ -510,7 +1064,7 @@ func (s *AuthService) ForgotPassword(email string) error {
 		}
 
 		// Send password reset email
-		if err := s.sendPasswordResetEmail(gamenet.Email, gamenet.Name, token); err != nil {
+		if err := s.sendPasswordResetEmail(gamenet.ID, "gamenet", gamenet.Email, gamenet.Name, token); err != nil {
 			fmt.Printf("Warning: failed to send password reset email to %s: %v\n", email, err)
 			// Don't return error here, as the token was created successfully
 		}
@@ -530,8 +1084,11 @@ func (s *AuthService) ResetPassword(token, email, newPassword, confirmPassword s
 	}
 
 	// Validate password strength
-	if len(newPassword) < 6 {
-		return fmt.Errorf("password must be at least 6 characters long")
+	if err := utils.ValidatePasswordStrength(newPassword, s.config.Security.PasswordPolicy); err != nil {
+		return err
+	}
+	if len(newPassword) > models.MaxPasswordBytes {
+		return models.ErrPasswordTooLong
 	}
 
 	// Get the token from database
@@ -618,21 +1175,90 @@ func (s *AuthService) ValidateResetToken(token string) error {
 	return nil
 }
 
-// ChangePassword changes the password for an authenticated user
-func (s *AuthService) ChangePassword(userID int, userType, currentPassword, newPassword, confirmPassword string) error {
+// VerifyPassword confirms the current password of an authenticated account and issues a
+// short-lived re-authentication token that sensitive endpoints require (step-up auth).
+// Failed confirmations are limited per account.
+func (s *AuthService) VerifyPassword(userID int, userType, password string) (*models.ReauthResponse, error) {
+	key := fmt.Sprintf("%s:%d", userType, userID)
+	// Reserve counts the attempt up front, so concurrent guesses can't all pass the check
+	if allowed, retryAfter := s.reauthLimiter.Reserve(key); !allowed {
+		return nil, &TooManyAttemptsError{RetryAfter: retryAfter}
+	}
+
+	hash, err := s.passwordHashFor(userID, userType)
+	if err != nil {
+		s.reauthLimiter.Release(key)
+		return nil, err
+	}
+
+	if !models.CheckPassword(password, hash) {
+		fmt.Printf("Re-authentication failed: Account=%s:%d, Time=%s\n",
+			userType, userID, time.Now().Format(time.RFC3339))
+		return nil, ErrInvalidPassword
+	}
+	s.reauthLimiter.Reset(key)
+
+	token, expiresAt, err := s.jwtManager.GenerateReauthToken(userID, userType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate re-authentication token: %w", err)
+	}
+
+	return &models.ReauthResponse{
+		ReauthToken: token,
+		ExpiresAt:   models.NewTimestamp(expiresAt),
+	}, nil
+}
+
+// ValidateReauthToken validates a re-authentication token and returns its claims
+func (s *AuthService) ValidateReauthToken(tokenString string) (*utils.ReauthClaims, error) {
+	return s.jwtManager.ValidateReauthToken(tokenString)
+}
+
+// passwordHashFor returns the stored password hash of an account
+func (s *AuthService) passwordHashFor(userID int, userType string) (string, error) {
+	switch userType {
+	case "user":
+		user, err := s.userRepo.GetByID(userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get user: %w", err)
+		}
+		return string(user.Password), nil
+	case "admin":
+		admin, err := s.adminRepo.GetByID(userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get admin: %w", err)
+		}
+		return string(admin.Password), nil
+	case "gamenet":
+		gamenet, err := s.gamenetRepo.GetByID(userID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get gamenet: %w", err)
+		}
+		return string(gamenet.Password), nil
+	default:
+		return "", fmt.Errorf("invalid user type: %s", userType)
+	}
+}
+
+// ChangePassword changes the password for an authenticated user and logs out every
+// other device; the session of currentToken, the one making the change, is kept
+func (s *AuthService) ChangePassword(userID int, userType, currentPassword, newPassword, confirmPassword, currentToken string) error {
 	// Validate passwords match
 	if newPassword != confirmPassword {
 		return fmt.Errorf("رمز عبور جدید و تأیید رمز عبور مطابقت ندارند")
 	}
 
 	// Validate password strength
-	if len(newPassword) < 6 {
-		return fmt.Errorf("رمز عبور باید حداقل 6 کاراکتر باشد")
+	if err := utils.ValidatePasswordStrength(newPassword, s.config.Security.PasswordPolicy); err != nil {
+		return err
+	}
+	if len(newPassword) > models.MaxPasswordBytes {
+		return fmt.Errorf("رمز عبور نباید بیشتر از %d بایت باشد: %w", models.MaxPasswordBytes, models.ErrPasswordTooLong)
 	}
 
 	// Validate current password and get user
 	var currentHashedPassword string
-	var email string
+	var email, name string
 
 	switch userType {
 	case "user":
@@ -640,24 +1266,27 @@ func (s *AuthService) ChangePassword(userID int, userType, currentPassword, newP
 		if err != nil {
 			return fmt.Errorf("کاربر یافت نشد")
 		}
-		currentHashedPassword = user.Password
+		currentHashedPassword = string(user.Password)
 		email = user.Email
+		name = user.Name
 
 	case "admin":
 		admin, err := s.adminRepo.GetByID(userID)
 		if err != nil {
 			return fmt.Errorf("مدیر یافت نشد")
 		}
-		currentHashedPassword = admin.Password
+		currentHashedPassword = string(admin.Password)
 		email = admin.Email
+		name = admin.Name
 
 	case "gamenet":
 		gamenet, err := s.gamenetRepo.GetByID(userID)
 		if err != nil {
 			return fmt.Errorf("گیم‌نت یافت نشد")
 		}
-		currentHashedPassword = gamenet.Password
+		currentHashedPassword = string(gamenet.Password)
 		email = gamenet.Email
+		name = gamenet.OwnerName
 
 	default:
 		return fmt.Errorf("نوع کاربر نامعتبر است")
@@ -695,8 +1324,13 @@ func (s *AuthService) ChangePassword(userID int, userType, currentPassword, newP
 		return fmt.Errorf("نوع کاربر نامعتبر است")
 	}
 
+	// Whoever knew the old password must not stay logged in elsewhere
+	if err := s.RevokeOtherSessions(userID, userType, currentToken); err != nil {
+		fmt.Printf("Warning: failed to log out other sessions after password change: %v\n", err)
+	}
+
 	// Send password change notification email
-	if err := s.sendPasswordChangeNotification(email, userType); err != nil {
+	if err := s.sendPasswordChangeNotification(userID, userType, email, name); err != nil {
 		fmt.Printf("Warning: failed to send password change notification: %v\n", err)
 		// Don't return error here, as the password was changed successfully
 	}
@@ -710,14 +1344,25 @@ func (s *AuthService) SendEmailVerification(userID int, userType, newEmail strin
 		return "", fmt.Errorf("notification service not available")
 	}
 
+	// Reject disposable email domains
+	if err := s.emailValidator.Validate(newEmail); err != nil {
+		return "", err
+	}
+
 	// Generate verification code
 	verificationCode := utils.GenerateVerificationCode()
 
-	// Store verification code in database with 10-minute expiration
+	// Store verification code in database with 10-minute expiration. Only one code
+	// may be sent per cooldown, whichever address it goes to.
 	expiresAt := time.Now().Add(10 * time.Minute)
-	if err := s.emailVerificationRepo.StoreCode(userID, userType, newEmail, verificationCode, expiresAt); err != nil {
+	cooldown := max(time.Duration(s.config.Security.EmailVerificationCooldownSeconds)*time.Second, 0)
+	wait, err := s.emailVerificationRepo.ReserveCode(userID, userType, newEmail, verificationCode, expiresAt, cooldown)
+	if err != nil {
 		return "", fmt.Errorf("failed to store verification code: %w", err)
 	}
+	if wait > 0 {
+		return "", &TooManyAttemptsError{RetryAfter: wait, Kind: ErrVerificationCodeCooldown}
+	}
 
 	// Get user information for personalization
 	var userName string
@@ -747,47 +1392,37 @@ func (s *AuthService) SendEmailVerification(userID int, userType, newEmail strin
 		currentEmail = user.Email
 	}
 
-	unsubscribeLink := "http://localhost:3000/unsubscribe?email=" + newEmail
-	supportLink := "http://localhost:3000/support"
-
-	// Create email content
-	subject := fmt.Sprintf("تأیید تغییر ایمیل - %s", s.config.App.Name)
-	content := fmt.Sprintf(`%s عزیز،
-
-درخواست تغییر ایمیل برای حساب کاربری شما در %s دریافت شده است.
+	unsubscribeLink := s.links.Unsubscribe(newEmail)
+	supportLink := s.links.Support()
 
-ایمیل فعلی: %s
-ایمیل جدید: %s
-
-کد تأیید شما: %s
-
-لطفاً این کد را در صفحه تنظیمات وارد کنید تا تغییر ایمیل تکمیل شود.
-
-اگر شما این درخواست را انجام نداده‌اید، لطفاً فوراً با تیم پشتیبانی تماس بگیرید.
-
-با احترام،
-تیم %s`, userName, s.config.App.Name, currentEmail, newEmail, verificationCode, s.config.App.Name)
+	templateData := map[string]interface{}{
+		"app_name":          s.config.App.Name,
+		"user_name":         userName,
+		"current_email":     currentEmail,
+		"new_email":         newEmail,
+		"verification_code": verificationCode,
+		"unsubscribe_link":  unsubscribeLink,
+		"support_link":      supportLink,
+	}
+	ctx := context.Background()
+	email, err := s.templateRenderer.Render(ctx, EmailTemplateEmailVerification, models.NotificationTypeEmail, templateData)
+	if err != nil {
+		return "", fmt.Errorf("failed to render verification email: %w", err)
+	}
 
 	// Create notification request
 	notification := &models.CreateNotificationRequest{
-		Type:      models.NotificationTypeEmail,
-		Priority:  models.NotificationPriorityHigh,
-		Recipient: newEmail,
-		Subject:   subject,
-		Content:   content,
-		TemplateData: map[string]interface{}{
-			"app_name":          s.config.App.Name,
-			"user_name":         userName,
-			"current_email":     currentEmail,
-			"new_email":         newEmail,
-			"verification_code": verificationCode,
-			"unsubscribe_link":  unsubscribeLink,
-			"support_link":      supportLink,
-		},
+		Type:         models.NotificationTypeEmail,
+		Priority:     models.NotificationPriorityHigh,
+		Recipient:    newEmail,
+		Subject:      email.Subject,
+		Content:      email.Content,
+		HTMLContent:  email.HTMLContent,
+		TemplateData: templateData,
 	}
+	notification.SetRelatedUser(userID, userType)
 
 	// Send the notification
-	ctx := context.Background()
 	if err := s.notificationService.SendNotification(ctx, notification); err != nil {
 		return "", fmt.Errorf("failed to send verification email: %w", err)
 	}
@@ -800,61 +1435,105 @@ func (s *AuthService) VerifyEmailCode(userID int, userType, email, code string)
 	return s.emailVerificationRepo.VerifyCode(userID, userType, email, code)
 }
 
+// IssueUserResetLink creates a password reset token for a user on behalf of an admin and
+// returns the reset link instead of emailing it, for users who can't receive the reset email
+func (s *AuthService) IssueUserResetLink(userID int, actor *models.Actor) (*models.PasswordResetLinkResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate any existing tokens for this user
+	if err := s.passwordResetRepo.InvalidateUserTokens(user.ID, "user"); err != nil {
+		fmt.Printf("Warning: failed to invalidate existing tokens for user %d: %v\n", user.ID, err)
+	}
+
+	token, err := s.generateResetToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate reset token: %w", err)
+	}
+
+	// Set token expiration (15 minutes from now)
+	expiresAt := time.Now().Add(15 * time.Minute)
+
+	if err := s.passwordResetRepo.CreateToken(user.ID, "user", token, expiresAt); err != nil {
+		return nil, fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	// Log the action for security auditing (never the token itself)
+	fmt.Printf("Password reset link issued: Target=user:%d, By=%s:%d, Time=%s\n",
+		user.ID, actor.Type, actor.ID, time.Now().Format(time.RFC3339))
+
+	return &models.PasswordResetLinkResponse{
+		UserID:    user.ID,
+		ResetLink: s.links.ResetPassword(token, user.Email),
+		ExpiresAt: models.NewTimestamp(expiresAt),
+	}, nil
+}
+
 // sendPasswordResetEmail sends a password reset email using the notification service
-func (s *AuthService) sendPasswordResetEmail(email, name, token string) error {
+func (s *AuthService) sendPasswordResetEmail(userID int, userType, email, name, token string) error {
 	if s.notificationService == nil {
 		return fmt.Errorf("notification service not available")
 	}
 
 	// Create reset link with email parameter
-	resetLink := fmt.Sprintf("http://localhost:3000/reset-password?token=%s&email=%s", token, email)
-	unsubscribeLink := "http://localhost:3000/unsubscribe?email=" + email
-	supportLink := "http://localhost:3000/support"
+	resetLink := s.links.ResetPassword(token, email)
+	unsubscribeLink := s.links.Unsubscribe(email)
+	supportLink := s.links.Support()
+
+	templateData := map[string]interface{}{
+		"app_name":         s.config.App.Name,
+		"user_name":        name,
+		"reset_link":       resetLink,
+		"expiry_hours":     "0.25", // 15 minutes
+		"unsubscribe_link": unsubscribeLink,
+		"support_link":     supportLink,
+	}
+	ctx := context.Background()
+	rendered, err := s.templateRenderer.Render(ctx, EmailTemplatePasswordReset, models.NotificationTypeEmail, templateData)
+	if err != nil {
+		return fmt.Errorf("failed to render password reset email: %w", err)
+	}
 
 	// Create notification request
 	notification := &models.CreateNotificationRequest{
-		Type:      models.NotificationTypeEmail,
-		Priority:  models.NotificationPriorityHigh,
-		Recipient: email,
-		Subject:   fmt.Sprintf("بازنشانی رمز عبور - %s", s.config.App.Name),
-		Content:   fmt.Sprintf("کاربر گرامی %s،\n\nدرخواست بازنشانی رمز عبور برای حساب کاربری شما در %s دریافت شده است.\n\nبرای تنظیم رمز عبور جدید، لطفاً روی لینک زیر کلیک کنید:\n%s\n\nاین لینک تا 0.25 ساعت معتبر است.\n\nاگر شما این درخواست را انجام نداده\u200cاید، لطفاً این ایمیل را نادیده بگیرید.\n\nبا احترام،\nتیم %s", name, s.config.App.Name, resetLink, s.config.App.Name),
-		TemplateData: map[string]interface{}{
-			"app_name":         s.config.App.Name,
-			"user_name":        name,
-			"reset_link":       resetLink,
-			"expiry_hours":     "0.25", // 15 minutes
-			"unsubscribe_link": unsubscribeLink,
-			"support_link":     supportLink,
-		},
+		Type:         models.NotificationTypeEmail,
+		Priority:     models.NotificationPriorityHigh,
+		Recipient:    email,
+		Subject:      rendered.Subject,
+		Content:      rendered.Content,
+		HTMLContent:  rendered.HTMLContent,
+		TemplateData: templateData,
 	}
+	notification.SetRelatedUser(userID, userType)
 
 	// Send the notification
-	ctx := context.Background()
 	return s.notificationService.SendNotification(ctx, notification)
 }
 
-// sendPasswordChangeNotification sends a password change notification email
-func (s *AuthService) sendPasswordChangeNotification(email, userType string) error {
+// sendPasswordChangeNotification sends a password change notification email addressed
+// to the account holder by name, or with a generic greeting when the name is empty
+func (s *AuthService) sendPasswordChangeNotification(userID int, userType, email, name string) error {
 	if s.notificationService == nil {
 		return fmt.Errorf("notification service not available")
 	}
 
-	// Get user name from the email (we could improve this by passing the name)
-	var name string
-	switch userType {
-	case "user":
-		// We could get the name from the user repository, but for now use email
-		name = "کاربر گرامی"
-	case "admin":
-		name = "مدیر گرامی"
-	case "gamenet":
-		name = "گیم‌نت گرامی"
-	default:
-		name = "کاربر گرامی"
+	greeting := name + " عزیز"
+	if strings.TrimSpace(name) == "" {
+		switch userType {
+		case "admin":
+			name = "مدیر گرامی"
+		case "gamenet":
+			name = "گیم‌نت گرامی"
+		default:
+			name = "کاربر گرامی"
+		}
+		greeting = name
 	}
 
-	unsubscribeLink := "http://localhost:3000/unsubscribe?email=" + email
-	supportLink := "http://localhost:3000/support"
+	unsubscribeLink := s.links.Unsubscribe(email)
+	supportLink := s.links.Support()
 
 	// Create notification request
 	notification := &models.CreateNotificationRequest{
@@ -862,7 +1541,7 @@ func (s *AuthService) sendPasswordChangeNotification(email, userType string) err
 		Priority:  models.NotificationPriorityHigh,
 		Recipient: email,
 		Subject:   fmt.Sprintf("تغییر رمز عبور - %s", s.config.App.Name),
-		Content:   fmt.Sprintf("%s،\n\nرمز عبور حساب کاربری شما در %s با موفقیت تغییر یافت.\n\nاگر شما این تغییر را انجام نداده\u200cاید، لطفاً فوراً با تیم پشتیبانی تماس بگیرید.\n\nبا احترام،\nتیم %s", name, s.config.App.Name, s.config.App.Name),
+		Content:   fmt.Sprintf("%s،\n\nرمز عبور حساب کاربری شما در %s با موفقیت تغییر یافت.\n\nاگر شما این تغییر را انجام نداده\u200cاید، لطفاً فوراً با تیم پشتیبانی تماس بگیرید.\n\nبا احترام،\nتیم %s", greeting, s.config.App.Name, s.config.App.Name),
 		TemplateData: map[string]interface{}{
 			"app_name":         s.config.App.Name,
 			"user_name":        name,
@@ -870,20 +1549,52 @@ func (s *AuthService) sendPasswordChangeNotification(email, userType string) err
 			"support_link":     supportLink,
 		},
 	}
+	notification.SetRelatedUser(userID, userType)
 
-	// Send the notification
+	// Informational only, so the request doesn't wait for the email to go out
 	ctx := context.Background()
-	return s.notificationService.SendNotification(ctx, notification)
+	return s.notificationService.EnqueueNotification(ctx, notification)
+}
+
+// sendEmailChangeNotification notifies the previous email address that the account email was changed
+func (s *AuthService) sendEmailChangeNotification(adminID int, oldEmail, name, newEmail string) error {
+	if s.notificationService == nil {
+		return fmt.Errorf("notification service not available")
+	}
+
+	supportLink := s.links.Support()
+
+	// Create notification request
+	notification := &models.CreateNotificationRequest{
+		Type:      models.NotificationTypeEmail,
+		Priority:  models.NotificationPriorityHigh,
+		Recipient: oldEmail,
+		Subject:   fmt.Sprintf("تغییر ایمیل - %s", s.config.App.Name),
+		Content:   fmt.Sprintf("%s عزیز،\n\nایمیل حساب کاربری شما در %s از %s به %s تغییر یافت.\n\nاگر شما این تغییر را انجام نداده\u200cاید، لطفاً فوراً با تیم پشتیبانی تماس بگیرید.\n\nبا احترام،\nتیم %s", name, s.config.App.Name, oldEmail, newEmail, s.config.App.Name),
+		TemplateData: map[string]interface{}{
+			"app_name":      s.config.App.Name,
+			"user_name":     name,
+			"current_email": oldEmail,
+			"new_email":     newEmail,
+			"support_link":  supportLink,
+		},
+	}
+	notification.SetRelatedUser(adminID, "admin")
+
+	// Informational only, so the request doesn't wait for the email to go out
+	ctx := context.Background()
+	return s.notificationService.EnqueueNotification(ctx, notification)
 }
 
-// CheckEmailExists checks if an email already exists in the system (users, admins, or gamenets)
+// CheckEmailExists checks if an email already exists in the system (users, admins, or
+// gamenets). Deleted users keep their email so they can be restored, so it counts too.
 func (s *AuthService) CheckEmailExists(email string) (bool, error) {
 	// Check if email exists in users table
-	_, err := s.userRepo.GetByEmail(email)
+	_, err := s.userRepo.GetByEmailIncludingDeleted(email)
 	if err == nil {
 		return true, nil // Email exists in users table
 	}
-	if err != sql.ErrNoRows {
+	if !errors.Is(err, repositories.ErrNotFound) {
 		return false, fmt.Errorf("failed to check user email: %w", err)
 	}
 
@@ -892,7 +1603,7 @@ func (s *AuthService) CheckEmailExists(email string) (bool, error) {
 	if err == nil {
 		return true, nil // Email exists in admins table
 	}
-	if err != sql.ErrNoRows {
+	if !errors.Is(err, repositories.ErrNotFound) {
 		return false, fmt.Errorf("failed to check admin email: %w", err)
 	}
 
@@ -901,7 +1612,7 @@ func (s *AuthService) CheckEmailExists(email string) (bool, error) {
 	if err == nil {
 		return true, nil // Email exists in gamenets table
 	}
-	if err != sql.ErrNoRows {
+	if !errors.Is(err, repositories.ErrNotFound) {
 		return false, fmt.Errorf("failed to check gamenet email: %w", err)
 	}
 
@@ -8,7 +8,13 @@ import (
 // AuthServiceInterface defines the interface for authentication services
 type AuthServiceInterface interface {
 	Login(email, password string, rememberMe bool) (*models.LoginResponse, error)
-	LoginWithSession(email, password string, rememberMe bool, deviceInfo, ipAddress, userAgent string) (*models.LoginResponse, error)
+	LoginWithSession(email, password string, rememberMe bool, deviceInfo, ipAddress, userAgent, sessionToken string) (*models.LoginResponse, error)
+	VerifyTOTP(challengeToken, code, deviceInfo, ipAddress, userAgent, sessionToken string) (*models.LoginResponse, error)
+	EnableTOTP(adminID int) (string, string, error)
+	ConfirmTOTP(adminID int, code string) error
+	LogoutSession(token string) error
+	GetActiveSessions(userID int, userType string) ([]models.SessionResponse, error)
+	RevokeOtherSessions(userID int, userType, currentToken string) error
 	ValidateToken(tokenString string) (*utils.JWTClaims, error)
 	RefreshToken(tokenString string, rememberMe bool) (string, error)
 	GetUserFromToken(tokenString string) (*utils.JWTClaims, error)
@@ -21,10 +27,14 @@ type AuthServiceInterface interface {
 	UpdateUserEmail(userID int, newEmail string) (*models.UserResponse, error)
 	UpdateAdminEmail(adminID int, newEmail string) (*models.AdminResponse, error)
 	UpdateGamenetEmail(gamenetID int, newEmail string) (*models.GamenetResponse, error)
+	ChangeAdminEmail(adminID int, currentPassword, newEmail string) (*models.AdminResponse, error)
 	ForgotPassword(email string) error
 	ResetPassword(token, email, newPassword, confirmPassword string) error
 	ValidateResetToken(token string) error
-	ChangePassword(userID int, userType, currentPassword, newPassword, confirmPassword string) error
+	IssueUserResetLink(userID int, actor *models.Actor) (*models.PasswordResetLinkResponse, error)
+	ChangePassword(userID int, userType, currentPassword, newPassword, confirmPassword, currentToken string) error
+	VerifyPassword(userID int, userType, password string) (*models.ReauthResponse, error)
+	ValidateReauthToken(tokenString string) (*utils.ReauthClaims, error)
 	SendEmailVerification(userID int, userType, newEmail string) (string, error)
 	VerifyEmailCode(userID int, userType, email, code string) (bool, error)
 	CheckEmailExists(email string) (bool, error)
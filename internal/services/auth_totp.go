@@ -0,0 +1,195 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// ErrTOTPAlreadyEnabled is returned when setting up or confirming TOTP for an admin
+// who already has it enabled
+var ErrTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+
+// ErrTOTPNotSetUp is returned when confirming TOTP before a secret was generated
+var ErrTOTPNotSetUp = errors.New("two-factor authentication has not been set up")
+
+// ErrInvalidTOTPCode is returned when a TOTP code does not match the admin's secret
+var ErrInvalidTOTPCode = errors.New("invalid two-factor authentication code")
+
+// ErrInvalidTOTPChallenge is returned for a missing, expired or tampered two-factor
+// login challenge token
+var ErrInvalidTOTPChallenge = errors.New("invalid or expired two-factor challenge")
+
+// ErrTooManyTOTPAttempts is matched by errors returned when an admin has exceeded
+// their failed TOTP codes
+var ErrTooManyTOTPAttempts = errors.New("too many two-factor authentication attempts")
+
+// totpMaxAttempts is how many wrong TOTP codes an admin may enter per challenge lifetime
+const totpMaxAttempts = 5
+
+// totpValidateOpts are the TOTP parameters authenticator apps use by default: six
+// digits every 30 seconds, accepting the codes one step either side for clock drift
+var totpValidateOpts = totp.ValidateOpts{
+	Period:    30,
+	Skew:      1,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// EnableTOTP generates a new TOTP secret for an admin and returns it along with an
+// otpauth:// URL for authenticator apps. The secret is only enforced at login once
+// ConfirmTOTP has checked a code generated from it.
+func (s *AuthService) EnableTOTP(adminID int) (string, string, error) {
+	admin, err := s.adminRepo.GetByID(adminID)
+	if err != nil {
+		return "", "", err
+	}
+	if admin.TOTPEnabled() {
+		return "", "", ErrTOTPAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.config.App.Name,
+		AccountName: admin.Email,
+		Period:      totpValidateOpts.Period,
+		Digits:      totpValidateOpts.Digits,
+		Algorithm:   totpValidateOpts.Algorithm,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate totp secret: %w", err)
+	}
+
+	if err := s.adminRepo.SetTOTPSecret(adminID, key.Secret()); err != nil {
+		return "", "", err
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+// ConfirmTOTP enables two-factor authentication for an admin once code matches the
+// secret generated by EnableTOTP
+func (s *AuthService) ConfirmTOTP(adminID int, code string) error {
+	admin, err := s.adminRepo.GetByID(adminID)
+	if err != nil {
+		return err
+	}
+	if admin.TOTPEnabled() {
+		return ErrTOTPAlreadyEnabled
+	}
+	if admin.TOTPSecret == nil {
+		return ErrTOTPNotSetUp
+	}
+
+	if err := s.checkTOTPCode(admin, code); err != nil {
+		return err
+	}
+
+	if err := s.adminRepo.EnableTOTP(adminID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Two-factor authentication enabled: AdminID=%d, Time=%s\n", adminID, time.Now().Format(time.RFC3339))
+	return nil
+}
+
+// VerifyTOTP completes a two-factor login: it exchanges the challenge token returned
+// by Login and a TOTP code for an access token, and creates the session, renewing the
+// one of sessionToken as LoginWithSession does
+func (s *AuthService) VerifyTOTP(challengeToken, code, deviceInfo, ipAddress, userAgent, sessionToken string) (*models.LoginResponse, error) {
+	claims, err := s.jwtManager.ValidateTOTPChallengeToken(challengeToken)
+	if err != nil || claims.UserType != "admin" {
+		return nil, ErrInvalidTOTPChallenge
+	}
+
+	admin, err := s.adminRepo.GetByID(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin: %w", err)
+	}
+	if !admin.TOTPEnabled() {
+		return nil, ErrInvalidTOTPChallenge
+	}
+
+	if err := s.checkTOTPCode(admin, code); err != nil {
+		if errors.Is(err, ErrInvalidTOTPCode) {
+			s.recordFailedLogin(admin.Email, deviceInfo, ipAddress, userAgent, loginFailureReason(err))
+		}
+		return nil, err
+	}
+
+	loginResponse, err := s.issueAdminLogin(admin, claims.RememberMe)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.createLoginSession(loginResponse, deviceInfo, ipAddress, userAgent, sessionToken); err != nil {
+		return nil, err
+	}
+	return loginResponse, nil
+}
+
+// totpChallenge answers a correct admin password when two-factor authentication is
+// enabled: instead of a token, the admin gets a short-lived challenge token to send
+// back with a TOTP code
+func (s *AuthService) totpChallenge(admin *models.Admin, rememberMe bool) (*models.LoginResponse, error) {
+	challengeToken, expiresAt, err := s.jwtManager.GenerateTOTPChallengeToken(admin.ID, "admin", rememberMe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate two-factor challenge: %w", err)
+	}
+
+	return &models.LoginResponse{
+		UserType:       "admin",
+		Status:         models.LoginStatusTwoFactorRequired,
+		ChallengeToken: challengeToken,
+		ExpiresAt:      models.NewTimestamp(expiresAt),
+	}, nil
+}
+
+// checkTOTPCode validates a code against the admin's secret. Each code is accepted
+// once: the time step it belongs to must be later than the last one accepted for the
+// admin, so a code seen by someone else cannot be replayed within its validity window.
+// Wrong and replayed codes are limited per admin, across challenges.
+func (s *AuthService) checkTOTPCode(admin *models.Admin, code string) error {
+	key := fmt.Sprintf("admin:%d", admin.ID)
+	if allowed, retryAfter := s.totpLimiter.Reserve(key); !allowed {
+		return &TooManyAttemptsError{RetryAfter: retryAfter, Kind: ErrTooManyTOTPAttempts}
+	}
+
+	step, valid := matchTOTPStep(code, *admin.TOTPSecret, time.Now().UTC())
+	if valid {
+		claimed, err := s.adminRepo.ClaimTOTPStep(admin.ID, step)
+		if err != nil {
+			s.totpLimiter.Release(key)
+			return err
+		}
+		valid = claimed
+	}
+	if !valid {
+		fmt.Printf("Two-factor code rejected: AdminID=%d, Time=%s\n", admin.ID, time.Now().Format(time.RFC3339))
+		return ErrInvalidTOTPCode
+	}
+
+	s.totpLimiter.Reset(key)
+	return nil
+}
+
+// matchTOTPStep returns the time step, within the allowed clock skew of now, whose code
+// is code, preferring the latest
+func matchTOTPStep(code, secret string, now time.Time) (int64, bool) {
+	period := int64(totpValidateOpts.Period)
+	current := now.Unix() / period
+	for step := current + int64(totpValidateOpts.Skew); step >= current-int64(totpValidateOpts.Skew); step-- {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*period, 0).UTC(), totpValidateOpts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
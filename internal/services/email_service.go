@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"html"
 	"net"
 	"net/smtp"
 	"regexp"
@@ -12,11 +13,14 @@ import (
 
 	"github.com/gatehide/gatehide-api/config"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/utils"
 )
 
 // EmailService implements EmailServiceInterface for SMTP email sending
 type EmailService struct {
-	config *config.EmailConfig
+	config  *config.EmailConfig
+	sandbox *config.SandboxConfig
+	links   *utils.FrontendLinks
 }
 
 // NewEmailService creates a new email service instance
@@ -26,12 +30,34 @@ func NewEmailService(cfg *config.EmailConfig) *EmailService {
 	}
 }
 
+// SetSandbox reroutes every email sent from now on to the sandbox email recipient,
+// or drops them when it is empty
+func (s *EmailService) SetSandbox(sandbox *config.SandboxConfig) {
+	s.sandbox = sandbox
+}
+
+// SetFrontendLinks sets the frontend used for the unsubscribe link in the List-Unsubscribe
+// header. Without it the header only offers the mailto address.
+func (s *EmailService) SetFrontendLinks(links *utils.FrontendLinks) {
+	s.links = links
+}
+
 // SendEmail sends an email using SMTP
 func (s *EmailService) SendEmail(ctx context.Context, email *models.EmailNotification) error {
 	if !s.config.Enabled {
 		return fmt.Errorf("email service is disabled")
 	}
 
+	if s.sandbox != nil {
+		original := strings.Join(append(append(append([]string{}, email.To...), email.CC...), email.BCC...), ", ")
+		if s.sandbox.EmailRecipient == "" {
+			fmt.Printf("Sandbox: email %q to %s dropped, Time=%s\n", email.Subject, original, time.Now().Format(time.RFC3339))
+			return nil
+		}
+		fmt.Printf("Sandbox: email %q to %s rerouted to %s, Time=%s\n", email.Subject, original, s.sandbox.EmailRecipient, time.Now().Format(time.RFC3339))
+		email = RerouteEmail(email, s.sandbox.EmailRecipient)
+	}
+
 	// Validate email addresses
 	if err := s.validateEmailAddresses(email.To); err != nil {
 		return fmt.Errorf("invalid recipient addresses: %w", err)
@@ -76,6 +102,31 @@ func (s *EmailService) SendEmail(ctx context.Context, email *models.EmailNotific
 	return nil
 }
 
+// RerouteEmail returns a copy of email addressed only to recipient, with the original
+// To, CC and BCC recipients noted at the top of each body
+func RerouteEmail(email *models.EmailNotification, recipient string) *models.EmailNotification {
+	rerouted := *email
+	rerouted.To = []string{recipient}
+	rerouted.CC = nil
+	rerouted.BCC = nil
+
+	note := fmt.Sprintf("[SANDBOX] Originally to: %s", strings.Join(email.To, ", "))
+	if len(email.CC) > 0 {
+		note += fmt.Sprintf("; cc: %s", strings.Join(email.CC, ", "))
+	}
+	if len(email.BCC) > 0 {
+		note += fmt.Sprintf("; bcc: %s", strings.Join(email.BCC, ", "))
+	}
+
+	if email.Body != "" {
+		rerouted.Body = note + "\n\n" + email.Body
+	}
+	if email.HTMLBody != "" {
+		rerouted.HTMLBody = fmt.Sprintf("<p>%s</p>\n%s", html.EscapeString(note), email.HTMLBody)
+	}
+	return &rerouted
+}
+
 // SendBulkEmail sends multiple emails
 func (s *EmailService) SendBulkEmail(ctx context.Context, emails []*models.EmailNotification) error {
 	if !s.config.Enabled {
@@ -189,7 +240,11 @@ func (s *EmailService) createMessage(email *models.EmailNotification) ([]byte, e
 	// Gmail and Yahoo compliance headers
 	message.WriteString("X-Mailer: GateHide API v1.0\r\n")
 	message.WriteString("X-Report-Abuse: Please report abuse to abuse@gatehide.com\r\n")
-	message.WriteString("List-Unsubscribe: <http://localhost:3000/unsubscribe>, <mailto:unsubscribe@gatehide.com>\r\n")
+	if s.links != nil {
+		message.WriteString(fmt.Sprintf("List-Unsubscribe: <%s>, <mailto:unsubscribe@gatehide.com>\r\n", s.links.Unsubscribe("")))
+	} else {
+		message.WriteString("List-Unsubscribe: <mailto:unsubscribe@gatehide.com>\r\n")
+	}
 	message.WriteString("List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
 	message.WriteString("Precedence: bulk\r\n")
 	message.WriteString("X-Auto-Response-Suppress: All\r\n")
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/gatehide/gatehide-api/internal/models"
@@ -9,16 +10,19 @@ import (
 	"github.com/gatehide/gatehide-api/internal/utils"
 )
 
+// ErrGamenetEmailTaken is returned when creating a gamenet with an email another gamenet already uses
+var ErrGamenetEmailTaken = errors.New("gamenet with this email already exists")
+
 // gamenetService implements GamenetServiceInterface
 type gamenetService struct {
 	gamenetRepo    repositories.GamenetRepository
 	permissionRepo repositories.PermissionRepositoryInterface
-	smsService     *SMSService
+	smsService     SMSServiceInterface
 	emailService   *EmailService
 }
 
 // NewGamenetService creates a new gamenet service
-func NewGamenetService(gamenetRepo repositories.GamenetRepository, permissionRepo repositories.PermissionRepositoryInterface, smsService *SMSService, emailService *EmailService) GamenetServiceInterface {
+func NewGamenetService(gamenetRepo repositories.GamenetRepository, permissionRepo repositories.PermissionRepositoryInterface, smsService SMSServiceInterface, emailService *EmailService) GamenetServiceInterface {
 	return &gamenetService{
 		gamenetRepo:    gamenetRepo,
 		permissionRepo: permissionRepo,
@@ -53,8 +57,18 @@ func (s *gamenetService) GetByID(ctx context.Context, id int) (*models.GamenetRe
 	return &response, nil
 }
 
-// Create creates a new gamenet
+// Create creates a new gamenet with a random password, which is sent to the owner by
+// SMS unless the request opts out
 func (s *gamenetService) Create(ctx context.Context, req *models.GamenetCreateRequest) (*models.GamenetResponse, error) {
+	// Check if gamenet with email already exists
+	existing, err := s.gamenetRepo.GetByEmail(req.Email)
+	if err == nil && existing != nil {
+		return nil, ErrGamenetEmailTaken
+	}
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+
 	// Generate random 8-digit password
 	randomPassword, err := utils.GenerateRandomPassword()
 	if err != nil {
@@ -73,7 +87,7 @@ func (s *gamenetService) Create(ctx context.Context, req *models.GamenetCreateRe
 		OwnerMobile:       req.OwnerMobile,
 		Address:           req.Address,
 		Email:             req.Email,
-		Password:          hashedPassword,
+		Password:          models.PasswordHash(hashedPassword),
 		LicenseAttachment: req.LicenseAttachment,
 	}
 
@@ -90,12 +104,12 @@ func (s *gamenetService) Create(ctx context.Context, req *models.GamenetCreateRe
 	}
 
 	// Send credentials via SMS using Kavenegar Verify Lookup
-	if s.smsService != nil {
+	if s.smsService != nil && req.ShouldSendCredentials() {
 		err = s.smsService.SendGamenetCredentials(ctx, req.OwnerMobile, req.Email, randomPassword)
-		if err != nil {
+		if err != nil && !errors.Is(err, ErrSMSDisabled) {
 			// Log the error but don't fail the creation
 			fmt.Printf("Warning: Failed to send credentials SMS to %s: %v\n", req.OwnerMobile, err)
-		} else {
+		} else if err == nil {
 			fmt.Printf("Successfully sent credentials SMS to %s\n", req.OwnerMobile)
 		}
 	}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+)
+
+// maxLoginEventsExport caps the number of rows returned by a CSV export
+const maxLoginEventsExport = 10000
+
+// Number of values returned when login attempts are aggregated
+const (
+	defaultLoginAttemptTop = 10
+	maxLoginAttemptTop     = 100
+)
+
+// ErrInvalidLoginFilter is returned when a login activity filter has an invalid value
+var ErrInvalidLoginFilter = errors.New("invalid login filter")
+
+// LoginAuditServiceInterface defines the interface for the login activity feed
+type LoginAuditServiceInterface interface {
+	ListLoginEvents(ctx context.Context, filter *models.LoginEventFilter) (*models.LoginEventListResponse, error)
+	ExportLoginEvents(ctx context.Context, filter *models.LoginEventFilter) ([]models.LoginEvent, error)
+	ListLoginAttempts(ctx context.Context, filter *models.LoginAttemptFilter) (*models.LoginAttemptListResponse, error)
+	AggregateLoginAttempts(ctx context.Context, filter *models.LoginAttemptFilter, groupBy string, limit int) ([]models.LoginAttemptCount, error)
+}
+
+// LoginAuditService implements LoginAuditServiceInterface
+type LoginAuditService struct {
+	loginAuditRepo repositories.LoginAuditRepositoryInterface
+}
+
+// NewLoginAuditService creates a new login audit service
+func NewLoginAuditService(loginAuditRepo repositories.LoginAuditRepositoryInterface) LoginAuditServiceInterface {
+	return &LoginAuditService{
+		loginAuditRepo: loginAuditRepo,
+	}
+}
+
+// ListLoginEvents returns a filtered, paginated page of login events
+func (s *LoginAuditService) ListLoginEvents(ctx context.Context, filter *models.LoginEventFilter) (*models.LoginEventListResponse, error) {
+	if err := validateLoginEventFilter(filter); err != nil {
+		return nil, err
+	}
+
+	return s.loginAuditRepo.ListLoginEvents(filter)
+}
+
+// ExportLoginEvents returns the login events matching the filter, capped at maxLoginEventsExport
+func (s *LoginAuditService) ExportLoginEvents(ctx context.Context, filter *models.LoginEventFilter) ([]models.LoginEvent, error) {
+	if err := validateLoginEventFilter(filter); err != nil {
+		return nil, err
+	}
+
+	return s.loginAuditRepo.ExportLoginEvents(filter, maxLoginEventsExport)
+}
+
+// ListLoginAttempts returns a filtered, paginated page of failed login attempts
+func (s *LoginAuditService) ListLoginAttempts(ctx context.Context, filter *models.LoginAttemptFilter) (*models.LoginAttemptListResponse, error) {
+	if err := validateLoginAttemptFilter(filter); err != nil {
+		return nil, err
+	}
+
+	return s.loginAuditRepo.ListLoginAttempts(filter)
+}
+
+// AggregateLoginAttempts returns the most frequent values of groupBy among the failed
+// login attempts matching the filter, such as the top attacked accounts (email) or
+// top source IPs (ip_address). limit defaults to 10 and is capped at 100.
+func (s *LoginAuditService) AggregateLoginAttempts(ctx context.Context, filter *models.LoginAttemptFilter, groupBy string, limit int) ([]models.LoginAttemptCount, error) {
+	if err := validateLoginAttemptFilter(filter); err != nil {
+		return nil, err
+	}
+
+	switch groupBy {
+	case models.LoginAttemptGroupByEmail, models.LoginAttemptGroupByIP, models.LoginAttemptGroupByReason:
+	default:
+		return nil, fmt.Errorf("%w: invalid group_by: %s", ErrInvalidLoginFilter, groupBy)
+	}
+
+	if limit <= 0 {
+		limit = defaultLoginAttemptTop
+	}
+	if limit > maxLoginAttemptTop {
+		limit = maxLoginAttemptTop
+	}
+
+	return s.loginAuditRepo.CountLoginAttemptsBy(filter, groupBy, limit)
+}
+
+// validateLoginAttemptFilter checks the login attempt filter values
+func validateLoginAttemptFilter(filter *models.LoginAttemptFilter) error {
+	if filter.Reason != "" && !slices.Contains(models.LoginFailureReasons, filter.Reason) {
+		return fmt.Errorf("%w: invalid reason: %s", ErrInvalidLoginFilter, filter.Reason)
+	}
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return fmt.Errorf("%w: from date must be before to date", ErrInvalidLoginFilter)
+	}
+
+	return nil
+}
+
+// validateLoginEventFilter checks the filter values
+func validateLoginEventFilter(filter *models.LoginEventFilter) error {
+	switch filter.UserType {
+	case "", "user", "admin", "gamenet":
+	default:
+		return fmt.Errorf("%w: invalid user type: %s", ErrInvalidLoginFilter, filter.UserType)
+	}
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return fmt.Errorf("%w: from date must be before to date", ErrInvalidLoginFilter)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// queuedNotification is a saved notification waiting for a queue worker
+type queuedNotification struct {
+	ctx          context.Context
+	notification *models.Notification
+}
+
+// notificationQueue is a buffered channel of notifications drained by a pool of workers
+type notificationQueue struct {
+	jobs    chan queuedNotification
+	mu      sync.RWMutex // guards closed so nothing is sent on a closed channel
+	closed  bool
+	workers sync.WaitGroup
+}
+
+// StartQueue starts workers that send queued notifications in the background. size
+// is how many notifications may wait at once; when the queue is full, notifications
+// are sent synchronously instead. Calling it again has no effect.
+func (s *NotificationService) StartQueue(workers, size int) {
+	if s.queue != nil || workers <= 0 {
+		return
+	}
+	if size < 0 {
+		size = 0
+	}
+
+	q := &notificationQueue{jobs: make(chan queuedNotification, size)}
+	for i := 0; i < workers; i++ {
+		q.workers.Add(1)
+		go s.runQueueWorker(q)
+	}
+	s.queue = q
+}
+
+// runQueueWorker sends queued notifications until the queue is closed and drained
+func (s *NotificationService) runQueueWorker(q *notificationQueue) {
+	defer q.workers.Done()
+	for job := range q.jobs {
+		s.deliverQueued(job)
+	}
+}
+
+// deliverQueued sends one queued notification, logging a panic so the worker keeps going
+func (s *NotificationService) deliverQueued(job queuedNotification) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("Warning: notification %d panicked in queue worker: %v, Time=%s\n", job.notification.ID, r, time.Now().Format(time.RFC3339))
+		}
+	}()
+
+	if err := s.deliverNotification(job.ctx, job.notification); err != nil {
+		fmt.Printf("Warning: queued notification %d failed: %v\n", job.notification.ID, err)
+	}
+}
+
+// EnqueueNotification saves a notification and returns without waiting for it to be
+// sent; a queue worker sends it and updates its status. When the queue is not running,
+// is full or has shut down, the notification is sent before returning instead, so it
+// is never lost. Only the send's error is returned in that case.
+func (s *NotificationService) EnqueueNotification(ctx context.Context, notification *models.CreateNotificationRequest) error {
+	notificationRecord, err := s.createNotificationRecord(notification)
+	if err != nil {
+		return err
+	}
+
+	// The request context ends when the handler returns; the send must outlive it
+	if s.queue != nil && s.queue.push(queuedNotification{ctx: context.WithoutCancel(ctx), notification: notificationRecord}) {
+		return nil
+	}
+
+	return s.deliverNotification(ctx, notificationRecord)
+}
+
+// push adds a job without blocking and reports whether it was queued
+func (q *notificationQueue) push(job queuedNotification) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	select {
+	case q.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// QueueLength returns the number of notifications waiting for a worker
+func (s *NotificationService) QueueLength() int {
+	if s.queue == nil {
+		return 0
+	}
+	return len(s.queue.jobs)
+}
+
+// Shutdown stops accepting queued notifications and waits for the workers to send
+// the ones already queued. It returns the context's error if ctx ends first; the
+// remaining notifications stay pending.
+func (s *NotificationService) Shutdown(ctx context.Context) error {
+	q := s.queue
+	if q == nil {
+		return nil
+	}
+
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		q.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("notification queue did not drain: %w", ctx.Err())
+	}
+}
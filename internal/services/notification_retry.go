@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/utils"
+)
+
+// NotificationRetryPolicy controls the automatic retry of failed notifications
+type NotificationRetryPolicy struct {
+	// MaxRetries is the retry count at which a notification is given up on
+	MaxRetries int
+	// Backoff is the wait after the first failure; it doubles for each further
+	// retry up to MaxBackoff (zero means no cap)
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// BatchSize is the most failed notifications looked at per sweep
+	BatchSize int
+}
+
+// NotificationRetrySummary reports what a retry sweep did
+type NotificationRetrySummary struct {
+	Retried int // notifications sent again
+	Sent    int // retries that succeeded
+	Skipped int // retries skipped because their channel is now disabled
+	GaveUp  int // notifications marked permanently failed
+}
+
+// RetryFailedNotifications sends failed notifications again once their backoff has
+// passed. Notifications whose retry count has reached the policy's MaxRetries are
+// marked permanently failed instead, so later sweeps skip them.
+func (s *NotificationService) RetryFailedNotifications(ctx context.Context, policy NotificationRetryPolicy) (*NotificationRetrySummary, error) {
+	notifications, err := s.notificationRepo.GetRetryableNotifications(policy.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failed notifications: %w", err)
+	}
+
+	backoff := utils.RetryBackoff{Base: policy.Backoff, Max: policy.MaxBackoff}
+	summary := &NotificationRetrySummary{}
+	now := time.Now()
+
+	for _, notification := range notifications {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		if notification.RetryCount >= policy.MaxRetries {
+			if err := s.giveUpNotification(notification); err != nil {
+				return summary, err
+			}
+			summary.GaveUp++
+			continue
+		}
+
+		if now.Before(notification.UpdatedAt.Add(backoff.Delay(notification.RetryCount))) {
+			continue
+		}
+
+		processErr, err := s.retryNotification(ctx, notification)
+		if err != nil {
+			return summary, fmt.Errorf("failed to retry notification %d: %w", notification.ID, err)
+		}
+		summary.Retried++
+		if processErr == nil {
+			if notification.Status == models.NotificationStatusSkipped {
+				summary.Skipped++
+			} else {
+				summary.Sent++
+			}
+			continue
+		}
+
+		if notification.RetryCount >= policy.MaxRetries {
+			if err := s.giveUpNotification(notification); err != nil {
+				return summary, err
+			}
+			summary.GaveUp++
+		}
+	}
+
+	return summary, nil
+}
+
+// giveUpNotification marks a failed notification as permanently failed
+func (s *NotificationService) giveUpNotification(notification *models.Notification) error {
+	notification.Status = models.NotificationStatusPermanentlyFailed
+	notification.UpdatedAt = time.Now()
+	if err := s.notificationRepo.Update(notification); err != nil {
+		return fmt.Errorf("failed to mark notification %d permanently failed: %w", notification.ID, err)
+	}
+
+	fmt.Printf("Notification gave up: ID=%d, Type=%s, Retries=%d, Time=%s\n",
+		notification.ID, notification.Type, notification.RetryCount, time.Now().Format(time.RFC3339))
+	return nil
+}
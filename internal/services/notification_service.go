@@ -2,14 +2,23 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/gatehide/gatehide-api/config"
 	"github.com/gatehide/gatehide-api/internal/models"
 	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/utils"
 )
 
+// ErrTransportDisabled is returned when checking a notification transport that is turned off
+var ErrTransportDisabled = errors.New("notification transport is disabled")
+
+// errNotificationSkipped marks a send that was deliberately not attempted, such as an
+// SMS while SMS is disabled. It is recorded as skipped rather than failed.
+var errNotificationSkipped = errors.New("notification skipped")
+
 // NotificationService implements NotificationServiceInterface
 type NotificationService struct {
 	emailService          EmailServiceInterface
@@ -17,7 +26,12 @@ type NotificationService struct {
 	dbNotificationService DatabaseNotificationServiceInterface
 	templateService       TemplateServiceInterface
 	notificationRepo      repositories.NotificationRepository
+	adminRepo             repositories.AdminRepository
 	config                *config.Config
+	// rateLimiters pace sends per channel so bursts don't overwhelm the providers
+	rateLimiters map[models.NotificationType]*utils.TokenBucket
+	// queue hands notifications to background workers; nil until StartQueue
+	queue *notificationQueue
 }
 
 // NewNotificationService creates a new notification service instance
@@ -27,6 +41,7 @@ func NewNotificationService(
 	dbNotificationService DatabaseNotificationServiceInterface,
 	templateService TemplateServiceInterface,
 	notificationRepo repositories.NotificationRepository,
+	adminRepo repositories.AdminRepository,
 	cfg *config.Config,
 ) *NotificationService {
 	return &NotificationService{
@@ -35,26 +50,125 @@ func NewNotificationService(
 		dbNotificationService: dbNotificationService,
 		templateService:       templateService,
 		notificationRepo:      notificationRepo,
+		adminRepo:             adminRepo,
 		config:                cfg,
+		rateLimiters:          newChannelRateLimiters(cfg),
+	}
+}
+
+// newChannelRateLimiters creates a token bucket for each rate-limited channel
+func newChannelRateLimiters(cfg *config.Config) map[models.NotificationType]*utils.TokenBucket {
+	limiters := make(map[models.NotificationType]*utils.TokenBucket)
+	if cfg == nil {
+		return limiters
+	}
+
+	email := cfg.Notification.Email.RateLimit
+	sms := cfg.Notification.SMS.RateLimit
+	limiters[models.NotificationTypeEmail] = utils.NewTokenBucket(email.PerMinute, email.Burst)
+	limiters[models.NotificationTypeSMS] = utils.NewTokenBucket(sms.PerMinute, sms.Burst)
+	return limiters
+}
+
+// waitForChannel blocks until the channel's rate limit allows another send. Queue
+// workers and direct sends wait their turn instead of failing; request paths with a
+// saved notification hand it to the queue instead (see deliverOrQueue).
+func (s *NotificationService) waitForChannel(ctx context.Context, channel models.NotificationType) error {
+	limiter, ok := s.rateLimiters[channel]
+	if !ok {
+		return nil
+	}
+
+	if err := limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("%s rate limit wait cancelled: %w", channel, err)
+	}
+	return nil
+}
+
+// RateLimitStats returns the current rate and queue metrics for each channel
+func (s *NotificationService) RateLimitStats() map[models.NotificationType]utils.TokenBucketStats {
+	stats := make(map[models.NotificationType]utils.TokenBucketStats, len(s.rateLimiters))
+	for channel, limiter := range s.rateLimiters {
+		stats[channel] = limiter.Stats()
+	}
+	return stats
+}
+
+// TestEmailConnection dials and authenticates with the SMTP relay without sending
+// anything. It returns ErrTransportDisabled when email is disabled.
+func (s *NotificationService) TestEmailConnection(ctx context.Context) error {
+	if s.config == nil || !s.config.Notification.Email.Enabled || s.emailService == nil {
+		return fmt.Errorf("email: %w", ErrTransportDisabled)
+	}
+	return s.emailService.TestConnection(ctx)
+}
+
+// TestSMSConnection checks that the SMS provider is reachable with the configured
+// credentials. It returns ErrTransportDisabled when SMS is disabled.
+func (s *NotificationService) TestSMSConnection(ctx context.Context) error {
+	if s.config == nil || !s.config.Notification.SMS.Enabled || s.smsService == nil {
+		return fmt.Errorf("sms: %w", ErrTransportDisabled)
 	}
+	return s.smsService.TestConnection(ctx)
 }
 
-// SendNotification sends a notification of any type
+// SendNotification sends a notification of any type. High and urgent notifications
+// are sent before it returns, so their delivery error reaches the caller, unless their
+// channel is over its rate limit; others are handed to the notification queue when it
+// is running (see EnqueueNotification).
 func (s *NotificationService) SendNotification(ctx context.Context, notification *models.CreateNotificationRequest) error {
-	// Create notification record
+	if !isCriticalPriority(notification.Priority) {
+		return s.EnqueueNotification(ctx, notification)
+	}
+
+	notificationRecord, err := s.createNotificationRecord(notification)
+	if err != nil {
+		return err
+	}
+
+	return s.deliverOrQueue(ctx, notificationRecord)
+}
+
+// deliverOrQueue sends a saved notification right away when its channel has capacity.
+// Over the rate limit the notification is handed to the queue and the caller returns
+// without waiting; only when the queue is not running or is full does the caller wait
+// for its turn.
+func (s *NotificationService) deliverOrQueue(ctx context.Context, notificationRecord *models.Notification) error {
+	if limiter, ok := s.rateLimiters[notificationRecord.Type]; ok {
+		if allowed, _ := limiter.Allow(); !allowed {
+			if s.queue != nil && s.queue.push(queuedNotification{ctx: context.WithoutCancel(ctx), notification: notificationRecord}) {
+				return nil
+			}
+			return s.deliverNotification(ctx, notificationRecord)
+		}
+	}
+
+	return s.recordDelivery(notificationRecord, s.processNotification(ctx, notificationRecord))
+}
+
+// isCriticalPriority reports whether a notification must be sent synchronously
+func isCriticalPriority(priority models.NotificationPriority) bool {
+	return priority == models.NotificationPriorityHigh || priority == models.NotificationPriorityUrgent
+}
+
+// createNotificationRecord saves a pending notification record for the request
+func (s *NotificationService) createNotificationRecord(notification *models.CreateNotificationRequest) (*models.Notification, error) {
 	notificationRecord := &models.Notification{
-		Type:         notification.Type,
-		Status:       models.NotificationStatusPending,
-		Priority:     notification.Priority,
-		Recipient:    notification.Recipient,
-		Subject:      notification.Subject,
-		Content:      notification.Content,
-		TemplateID:   notification.TemplateID,
-		TemplateData: notification.TemplateData,
-		Metadata:     notification.Metadata,
-		ScheduledAt:  notification.ScheduledAt,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		Type:            notification.Type,
+		Status:          models.NotificationStatusPending,
+		Priority:        notification.Priority,
+		Recipient:       notification.Recipient,
+		RelatedUserID:   notification.RelatedUserID,
+		RelatedUserType: notification.RelatedUserType,
+		Subject:         notification.Subject,
+		Content:         notification.Content,
+		HTMLContent:     notification.HTMLContent,
+		TemplateID:      notification.TemplateID,
+		TemplateData:    notification.TemplateData,
+		Metadata:        notification.Metadata,
+		ScheduledAt:     notification.ScheduledAt,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
 	}
 
 	// Set default priority if not specified
@@ -64,24 +178,46 @@ func (s *NotificationService) SendNotification(ctx context.Context, notification
 
 	// Save notification record
 	if err := s.notificationRepo.Create(notificationRecord); err != nil {
-		return fmt.Errorf("failed to create notification record: %w", err)
+		return nil, fmt.Errorf("failed to create notification record: %w", err)
+	}
+
+	return notificationRecord, nil
+}
+
+// deliverNotification waits for the channel's rate limit, sends a saved notification
+// and records the outcome on it
+func (s *NotificationService) deliverNotification(ctx context.Context, notificationRecord *models.Notification) error {
+	err := s.waitForChannel(ctx, notificationRecord.Type)
+	if err == nil {
+		err = s.processNotification(ctx, notificationRecord)
 	}
 
-	// Process the notification based on type
-	var err error
+	return s.recordDelivery(notificationRecord, err)
+}
+
+// processNotification sends a saved notification based on its type
+func (s *NotificationService) processNotification(ctx context.Context, notification *models.Notification) error {
 	switch notification.Type {
 	case models.NotificationTypeEmail:
-		err = s.processEmailNotification(ctx, notificationRecord)
+		return s.processEmailNotification(ctx, notification)
 	case models.NotificationTypeSMS:
-		err = s.processSMSNotification(ctx, notificationRecord)
+		return s.processSMSNotification(ctx, notification)
 	case models.NotificationTypeDatabase:
-		err = s.processDatabaseNotification(ctx, notificationRecord)
+		return s.processDatabaseNotification(ctx, notification)
 	default:
-		err = fmt.Errorf("unsupported notification type: %s", notification.Type)
+		return fmt.Errorf("unsupported notification type: %s", notification.Type)
 	}
+}
 
+// recordDelivery saves the outcome of a send on the notification and returns the send's error
+func (s *NotificationService) recordDelivery(notificationRecord *models.Notification, err error) error {
 	// Update notification status
-	if err != nil {
+	if errors.Is(err, errNotificationSkipped) {
+		errorMsg := err.Error()
+		notificationRecord.Status = models.NotificationStatusSkipped
+		notificationRecord.ErrorMsg = &errorMsg
+		err = nil
+	} else if err != nil {
 		errorMsg := err.Error()
 		notificationRecord.Status = models.NotificationStatusFailed
 		notificationRecord.ErrorMsg = &errorMsg
@@ -100,6 +236,49 @@ func (s *NotificationService) SendNotification(ctx context.Context, notification
 	return err
 }
 
+// NotifyAdmins emails an internal alert to every admin, or only to the configured
+// alerts recipient when one is set. Delivery continues past individual failures.
+func (s *NotificationService) NotifyAdmins(ctx context.Context, subject, body string) error {
+	recipients, err := s.alertRecipients()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, recipient := range recipients {
+		err := s.SendNotification(ctx, &models.CreateNotificationRequest{
+			Type:      models.NotificationTypeEmail,
+			Priority:  models.NotificationPriorityHigh,
+			Recipient: recipient,
+			Subject:   subject,
+			Content:   body,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify %s: %w", recipient, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// alertRecipients returns the email addresses internal alerts are sent to
+func (s *NotificationService) alertRecipients() ([]string, error) {
+	if s.config != nil && s.config.Notification.AlertsRecipient != "" {
+		return []string{s.config.Notification.AlertsRecipient}, nil
+	}
+
+	if s.adminRepo == nil {
+		return nil, fmt.Errorf("admin repository not available")
+	}
+
+	emails, err := s.adminRepo.GetAllEmails()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin emails: %w", err)
+	}
+
+	return emails, nil
+}
+
 // SendEmail sends an email notification
 func (s *NotificationService) SendEmail(ctx context.Context, email *models.SendEmailRequest) error {
 	// Convert to EmailNotification
@@ -119,15 +298,32 @@ func (s *NotificationService) SendEmail(ctx context.Context, email *models.SendE
 		emailNotification.Priority = models.NotificationPriorityNormal
 	}
 
+	if err := s.waitForChannel(ctx, models.NotificationTypeEmail); err != nil {
+		return err
+	}
+
 	return s.emailService.SendEmail(ctx, emailNotification)
 }
 
 // SendSMS sends an SMS notification
 func (s *NotificationService) SendSMS(ctx context.Context, sms *models.SendSMSRequest) error {
+	message := sms.Message
+	if sms.Template != "" {
+		content, err := s.renderSMSTemplate(ctx, sms.Template, sms.TemplateData)
+		if err != nil && message == "" {
+			return err
+		}
+		if err != nil {
+			fmt.Printf("Warning: %v, sending raw message instead\n", err)
+		} else {
+			message = content
+		}
+	}
+
 	// Convert to SMSNotification
 	smsNotification := &models.SMSNotification{
 		To:       sms.To,
-		Message:  sms.Message,
+		Message:  message,
 		Priority: sms.Priority,
 	}
 
@@ -136,9 +332,35 @@ func (s *NotificationService) SendSMS(ctx context.Context, sms *models.SendSMSRe
 		smsNotification.Priority = models.NotificationPriorityNormal
 	}
 
+	if err := s.waitForChannel(ctx, models.NotificationTypeSMS); err != nil {
+		return err
+	}
+
 	return s.smsService.SendSMS(ctx, smsNotification)
 }
 
+// renderSMSTemplate renders the named SMS template with the given data
+func (s *NotificationService) renderSMSTemplate(ctx context.Context, name string, data map[string]interface{}) (string, error) {
+	if s.templateService == nil {
+		return "", fmt.Errorf("template service not configured")
+	}
+
+	template, err := s.templateService.GetTemplateByName(ctx, name, models.NotificationTypeSMS)
+	if err != nil {
+		return "", fmt.Errorf("failed to get SMS template %s: %w", name, err)
+	}
+	if !template.IsActive {
+		return "", fmt.Errorf("SMS template %s is inactive", name)
+	}
+
+	_, content, err := s.templateService.RenderTemplate(ctx, template, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render SMS template %s: %w", name, err)
+	}
+
+	return content, nil
+}
+
 // SendDatabaseNotification sends a database notification
 func (s *NotificationService) SendDatabaseNotification(ctx context.Context, dbNotification *models.DatabaseNotification) error {
 	// Set default priority if not specified
@@ -189,6 +411,13 @@ func (s *NotificationService) RetryFailedNotification(ctx context.Context, id in
 		return fmt.Errorf("notification is not in failed status")
 	}
 
+	_, err = s.retryNotification(ctx, notification)
+	return err
+}
+
+// retryNotification sends a failed notification again, counting the attempt. It
+// returns the send's error and the error saving the outcome.
+func (s *NotificationService) retryNotification(ctx context.Context, notification *models.Notification) (processErr error, err error) {
 	// Reset status and retry
 	notification.Status = models.NotificationStatusPending
 	notification.ErrorMsg = nil
@@ -196,24 +425,22 @@ func (s *NotificationService) RetryFailedNotification(ctx context.Context, id in
 	notification.UpdatedAt = time.Now()
 
 	if err := s.notificationRepo.Update(notification); err != nil {
-		return fmt.Errorf("failed to update notification: %w", err)
+		return nil, fmt.Errorf("failed to update notification: %w", err)
 	}
 
 	// Process the notification again
-	var processErr error
-	switch notification.Type {
-	case models.NotificationTypeEmail:
-		processErr = s.processEmailNotification(ctx, notification)
-	case models.NotificationTypeSMS:
-		processErr = s.processSMSNotification(ctx, notification)
-	case models.NotificationTypeDatabase:
-		processErr = s.processDatabaseNotification(ctx, notification)
-	default:
-		processErr = fmt.Errorf("unsupported notification type: %s", notification.Type)
+	processErr = s.waitForChannel(ctx, notification.Type)
+	if processErr == nil {
+		processErr = s.processNotification(ctx, notification)
 	}
 
 	// Update status based on result
-	if processErr != nil {
+	if errors.Is(processErr, errNotificationSkipped) {
+		errorMsg := processErr.Error()
+		notification.Status = models.NotificationStatusSkipped
+		notification.ErrorMsg = &errorMsg
+		processErr = nil
+	} else if processErr != nil {
 		errorMsg := processErr.Error()
 		notification.Status = models.NotificationStatusFailed
 		notification.ErrorMsg = &errorMsg
@@ -224,7 +451,7 @@ func (s *NotificationService) RetryFailedNotification(ctx context.Context, id in
 	}
 
 	notification.UpdatedAt = time.Now()
-	return s.notificationRepo.Update(notification)
+	return processErr, s.notificationRepo.Update(notification)
 }
 
 // processEmailNotification processes an email notification
@@ -256,6 +483,7 @@ func (s *NotificationService) processEmailNotification(ctx context.Context, noti
 			To:       []string{notification.Recipient},
 			Subject:  notification.Subject,
 			Body:     notification.Content,
+			HTMLBody: notification.HTMLContent,
 			Priority: notification.Priority,
 		}
 	}
@@ -294,7 +522,12 @@ func (s *NotificationService) processSMSNotification(ctx context.Context, notifi
 		}
 	}
 
-	return s.smsService.SendSMS(ctx, smsNotification)
+	err := s.smsService.SendSMS(ctx, smsNotification)
+	if errors.Is(err, ErrSMSDisabled) {
+		// Retrying cannot help while SMS is turned off
+		return fmt.Errorf("%w: %w", errNotificationSkipped, err)
+	}
+	return err
 }
 
 // processDatabaseNotification processes a database notification
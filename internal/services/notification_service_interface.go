@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/utils"
 )
 
 // NotificationServiceInterface defines the contract for notification services
@@ -11,6 +12,12 @@ type NotificationServiceInterface interface {
 	// SendNotification sends a notification of any type
 	SendNotification(ctx context.Context, notification *models.CreateNotificationRequest) error
 
+	// EnqueueNotification saves a notification and sends it in the background
+	EnqueueNotification(ctx context.Context, notification *models.CreateNotificationRequest) error
+
+	// NotifyAdmins emails an internal alert to the admins (or the configured alerts recipient)
+	NotifyAdmins(ctx context.Context, subject, body string) error
+
 	// SendEmail sends an email notification
 	SendEmail(ctx context.Context, email *models.SendEmailRequest) error
 
@@ -31,6 +38,15 @@ type NotificationServiceInterface interface {
 
 	// RetryFailedNotification retries a failed notification
 	RetryFailedNotification(ctx context.Context, id int) error
+
+	// RateLimitStats returns the current rate and queue metrics for each channel
+	RateLimitStats() map[models.NotificationType]utils.TokenBucketStats
+
+	// TestEmailConnection checks the SMTP relay without sending an email
+	TestEmailConnection(ctx context.Context) error
+
+	// TestSMSConnection checks the SMS provider without sending a message
+	TestSMSConnection(ctx context.Context) error
 }
 
 // EmailServiceInterface defines the contract for email services
@@ -56,11 +72,26 @@ type SMSServiceInterface interface {
 	// SendBulkSMS sends multiple SMS messages
 	SendBulkSMS(ctx context.Context, smsMessages []*models.SMSNotification) error
 
+	// SendBulkSMSWithResults sends multiple SMS messages and reports the outcome of each
+	SendBulkSMSWithResults(ctx context.Context, smsMessages []*models.SMSNotification) ([]models.BulkSMSResult, error)
+
 	// ValidatePhoneNumber validates a phone number
 	ValidatePhoneNumber(phone string) bool
 
 	// TestConnection tests the SMS service connection
 	TestConnection(ctx context.Context) error
+
+	// SendTemplate sends a templated SMS using Verify Lookup
+	SendTemplate(ctx context.Context, mobile, templateName string, tokens map[string]string) error
+
+	// SendUserCredentials sends login credentials to a newly created user
+	SendUserCredentials(ctx context.Context, mobile, email, password string) error
+
+	// SendGamenetCredentials sends login credentials to a newly created gamenet
+	SendGamenetCredentials(ctx context.Context, mobile, email, password string) error
+
+	// GetDeliveryStatus reports whether a sent message reached the recipient
+	GetDeliveryStatus(ctx context.Context, messageID int) (*models.SMSDeliveryStatus, error)
 }
 
 // DatabaseNotificationServiceInterface defines the contract for database notification services
@@ -2,7 +2,9 @@ package services
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gatehide/gatehide-api/internal/models"
 	"github.com/gatehide/gatehide-api/internal/repositories"
@@ -17,16 +19,31 @@ type PermissionServiceInterface interface {
 	CanAccessResource(userType string, resourceType string, resourceID int, userID int) (bool, error)
 	GetRoleWithPermissions(roleType string) (*models.RoleWithPermissions, error)
 	HasPermission(userType, resource, action string) (bool, error)
+	GetAllPermissions() ([]models.Permission, error)
+	GetUserRoles(userID int, userType string) ([]models.Role, error)
+	AssignRole(userID int, userType, roleName string, actor *models.Actor) error
+	RemoveRole(userID int, userType, roleName string, actor *models.Actor) error
 }
 
+// ErrPermissionDenied is returned by the permission checks when the permission is missing,
+// as opposed to the permission lookup failing
+var ErrPermissionDenied = errors.New("permission denied")
+
+// ErrRoleAlreadyAssigned is returned when assigning a role the account already has
+var ErrRoleAlreadyAssigned = errors.New("role already assigned")
+
+// ErrRoleNotAssignable is returned when assigning a role reserved for another account
+// type, such as administrator to an end user
+var ErrRoleNotAssignable = errors.New("role cannot be assigned to this account type")
+
 // PermissionService handles permission business logic
 type PermissionService struct {
-	permissionRepo *repositories.PermissionRepository
+	permissionRepo repositories.PermissionRepositoryInterface
 	db             *sql.DB
 }
 
 // NewPermissionService creates a new permission service
-func NewPermissionService(permissionRepo *repositories.PermissionRepository, db *sql.DB) *PermissionService {
+func NewPermissionService(permissionRepo repositories.PermissionRepositoryInterface, db *sql.DB) *PermissionService {
 	return &PermissionService{
 		permissionRepo: permissionRepo,
 		db:             db,
@@ -44,7 +61,7 @@ func (s *PermissionService) CheckPermission(userType, resource, action string) e
 	}
 
 	if !hasPermission {
-		return fmt.Errorf("permission denied: %s:%s for role %s", resource, action, userType)
+		return fmt.Errorf("%w: %s:%s for role %s", ErrPermissionDenied, resource, action, userType)
 	}
 
 	return nil
@@ -58,7 +75,7 @@ func (s *PermissionService) CheckUserPermission(userID int, userType, resource,
 	}
 
 	if !hasPermission {
-		return fmt.Errorf("permission denied: %s:%s for user %d", resource, action, userID)
+		return fmt.Errorf("%w: %s:%s for user %d", ErrPermissionDenied, resource, action, userID)
 	}
 
 	return nil
@@ -82,7 +99,8 @@ func (s *PermissionService) GetUserPermissions(userType string) ([]string, error
 	return permissionStrings, nil
 }
 
-// GetUserPermissionsByID retrieves all permissions for a specific user
+// GetUserPermissionsByID retrieves the permissions of a specific user: the union of the
+// permissions of every role assigned to them, each listed once
 func (s *PermissionService) GetUserPermissionsByID(userID int, userType string) ([]string, error) {
 	permissions, err := s.permissionRepo.GetUserPermissions(userID, userType)
 	if err != nil {
@@ -90,8 +108,14 @@ func (s *PermissionService) GetUserPermissionsByID(userID int, userType string)
 	}
 
 	var permissionStrings []string
+	seen := make(map[string]bool, len(permissions))
 	for _, perm := range permissions {
-		permissionStrings = append(permissionStrings, perm.PermissionString())
+		permission := perm.PermissionString()
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		permissionStrings = append(permissionStrings, permission)
 	}
 
 	return permissionStrings, nil
@@ -223,3 +247,111 @@ func (s *PermissionService) ValidateUserAccess(userType string, userID int, reso
 
 	return nil
 }
+
+// GetAllPermissions lists every permission
+func (s *PermissionService) GetAllPermissions() ([]models.Permission, error) {
+	permissions, err := s.permissionRepo.GetAllPermissions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+	return permissions, nil
+}
+
+// GetUserRoles lists the roles assigned to an account. It returns repositories.ErrNotFound
+// when the account does not exist.
+func (s *PermissionService) GetUserRoles(userID int, userType string) ([]models.Role, error) {
+	if err := s.ensureAccountExists(userID, userType); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.permissionRepo.GetUserRoles(userID, userType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user roles: %w", err)
+	}
+	return roles, nil
+}
+
+// AssignRole assigns a role to an account by role name. It returns ErrRoleNotAssignable
+// for a role reserved for another account type, ErrRoleAlreadyAssigned when the account
+// already has the role, and repositories.ErrNotFound when the account or the role does
+// not exist.
+func (s *PermissionService) AssignRole(userID int, userType, roleName string, actor *models.Actor) error {
+	if !roleAssignable(userType, roleName) {
+		return fmt.Errorf("%w: %s to %s", ErrRoleNotAssignable, roleName, userType)
+	}
+	if err := s.ensureAccountExists(userID, userType); err != nil {
+		return err
+	}
+
+	hasRole, err := s.permissionRepo.HasUserRole(userID, userType, roleName)
+	if err != nil {
+		return fmt.Errorf("failed to check user role: %w", err)
+	}
+	if hasRole {
+		return ErrRoleAlreadyAssigned
+	}
+
+	if err := s.permissionRepo.AssignRoleToUser(userID, userType, roleName); err != nil {
+		return err
+	}
+
+	fmt.Printf("Role assigned: Target=%s:%d, Role=%s, By=%s:%d, Time=%s\n",
+		userType, userID, roleName, actor.Type, actor.ID, time.Now().Format(time.RFC3339))
+	return nil
+}
+
+// RemoveRole removes a role from an account. It returns repositories.ErrNotFound when
+// the role does not exist or the account does not have it.
+func (s *PermissionService) RemoveRole(userID int, userType, roleName string, actor *models.Actor) error {
+	if err := s.permissionRepo.RemoveRoleFromUser(userID, userType, roleName); err != nil {
+		return err
+	}
+
+	fmt.Printf("Role removed: Target=%s:%d, Role=%s, By=%s:%d, Time=%s\n",
+		userType, userID, roleName, actor.Type, actor.ID, time.Now().Format(time.RFC3339))
+	return nil
+}
+
+// accountTypeRoles maps account types to the role granting that account type's access.
+// Administrators pass every ownership check, so these roles are never handed to
+// accounts of another type.
+var accountTypeRoles = map[string]string{
+	"admin":            models.RoleAdministrator,
+	models.RoleUser:    models.RoleUser,
+	models.RoleGamenet: models.RoleGamenet,
+}
+
+// roleAssignable reports whether roleName may be assigned to an account of userType
+func roleAssignable(userType, roleName string) bool {
+	for accountType, role := range accountTypeRoles {
+		if role == roleName && accountType != userType {
+			return false
+		}
+	}
+	return true
+}
+
+// accountTables maps account types to the table holding them
+var accountTables = map[string]string{
+	models.RoleUser:    "users",
+	"admin":            "admins",
+	models.RoleGamenet: "gamenets",
+}
+
+// ensureAccountExists returns repositories.ErrNotFound unless the account exists
+func (s *PermissionService) ensureAccountExists(userID int, userType string) error {
+	table, ok := accountTables[userType]
+	if !ok {
+		return fmt.Errorf("invalid user type: %s", userType)
+	}
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = ?", table)
+	if err := s.db.QueryRow(query, userID).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check account: %w", err)
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %s %d", repositories.ErrNotFound, userType, userID)
+	}
+	return nil
+}
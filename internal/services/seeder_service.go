@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gatehide/gatehide-api/database/seeders"
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// SeederServiceInterface defines the interface for re-running seeders at runtime
+type SeederServiceInterface interface {
+	ListSeeders(ctx context.Context) []string
+	RunSeeder(ctx context.Context, name string, actor *models.Actor) (*models.SeedResult, error)
+}
+
+// SeederService implements SeederServiceInterface for the safe, idempotent seeders
+type SeederService struct {
+	seeders seeders.SafeSeeders
+}
+
+// NewSeederService creates a new seeder service
+func NewSeederService(safeSeeders seeders.SafeSeeders) SeederServiceInterface {
+	return &SeederService{seeders: safeSeeders}
+}
+
+// ListSeeders returns the seeders that may be re-run
+func (s *SeederService) ListSeeders(ctx context.Context) []string {
+	return s.seeders.List()
+}
+
+// RunSeeder runs a safe seeder and audit-logs the outcome.
+// Returns an error wrapping seeders.ErrSeederNotAllowed for names outside the safe set.
+func (s *SeederService) RunSeeder(ctx context.Context, name string, actor *models.Actor) (*models.SeedResult, error) {
+	if actor == nil {
+		return nil, fmt.Errorf("actor is required")
+	}
+
+	result, err := s.seeders.Run(name)
+	if err != nil {
+		fmt.Printf("Seeder run failed: Seeder=%s, By=%s:%d, Error=%v, Time=%s\n",
+			name, actor.Type, actor.ID, err, time.Now().Format(time.RFC3339))
+		return nil, err
+	}
+
+	fmt.Printf("Seeder run: Seeder=%s, By=%s:%d, Inserted=%d, Skipped=%d, Failed=%d, Time=%s\n",
+		name, actor.Type, actor.ID, len(result.Inserted), len(result.Skipped), len(result.Failed),
+		time.Now().Format(time.RFC3339))
+
+	return result, nil
+}
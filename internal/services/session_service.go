@@ -11,15 +11,21 @@ import (
 	"github.com/gatehide/gatehide-api/internal/utils"
 )
 
+// ErrSessionNotFound is returned when a session does not exist
+var ErrSessionNotFound = errors.New("session not found")
+
 // SessionService implements SessionServiceInterface
 type SessionService struct {
 	sessionRepo repositories.SessionRepositoryInterface
+	userRepo    repositories.UserRepository
+	adminRepo   repositories.AdminRepository
+	gamenetRepo repositories.GamenetRepository
 	jwtManager  *utils.JWTManager
 	cfg         *config.Config
 }
 
 // NewSessionService creates a new session service
-func NewSessionService(sessionRepo repositories.SessionRepositoryInterface, cfg *config.Config) SessionServiceInterface {
+func NewSessionService(sessionRepo repositories.SessionRepositoryInterface, cfg *config.Config) *SessionService {
 	return &SessionService{
 		sessionRepo: sessionRepo,
 		jwtManager:  utils.NewJWTManager(cfg),
@@ -27,21 +33,22 @@ func NewSessionService(sessionRepo repositories.SessionRepositoryInterface, cfg
 	}
 }
 
+// SetAccountRepositories lets TerminateAccountSessions check that the target account
+// exists. Without them every account is assumed to exist.
+func (s *SessionService) SetAccountRepositories(userRepo repositories.UserRepository, adminRepo repositories.AdminRepository, gamenetRepo repositories.GamenetRepository) {
+	s.userRepo = userRepo
+	s.adminRepo = adminRepo
+	s.gamenetRepo = gamenetRepo
+}
+
 // CreateSession creates a new user session and returns both session and JWT token
 func (s *SessionService) CreateSession(userID int, userType, deviceInfo, ipAddress, userAgent string, rememberMe bool) (*models.UserSession, string, error) {
 	// Generate JWT token
-	token, err := s.jwtManager.GenerateToken(userID, userType, "", "", rememberMe)
+	token, expiresAt, err := s.jwtManager.GenerateTokenWithExpiry(userID, userType, "", "", rememberMe)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
-	// Calculate expiration time
-	expiration := time.Duration(s.cfg.Security.JWTExpiration) * time.Hour
-	if rememberMe {
-		expiration = expiration * 24 * 7 // 7 days for remember me
-	}
-	expiresAt := time.Now().Add(expiration)
-
 	// Create session in database
 	var deviceInfoPtr, ipAddressPtr, userAgentPtr *string
 	if deviceInfo != "" {
@@ -116,6 +123,21 @@ func (s *SessionService) GetActiveSessions(userID int, userType string, currentS
 	return responses, nil
 }
 
+// GetSession retrieves a single session's details by ID
+func (s *SessionService) GetSession(sessionID int, currentSessionToken string) (*models.SessionResponse, error) {
+	session, err := s.sessionRepo.GetSessionByID(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	response := session.ToResponse()
+	response.IsCurrent = session.SessionToken == currentSessionToken
+	return &response, nil
+}
+
 // LogoutSession deactivates a specific session
 func (s *SessionService) LogoutSession(sessionID int, userID int, userType string) error {
 	// Verify the session belongs to the user
@@ -157,7 +179,7 @@ func (s *SessionService) LogoutAllOtherSessions(userID int, userType string, cur
 
 // LogoutAllSessions deactivates all sessions for a user
 func (s *SessionService) LogoutAllSessions(userID int, userType string) error {
-	err := s.sessionRepo.DeactivateAllUserSessions(userID, userType)
+	_, err := s.sessionRepo.DeactivateAllUserSessions(userID, userType)
 	if err != nil {
 		return fmt.Errorf("failed to logout all sessions: %w", err)
 	}
@@ -165,6 +187,43 @@ func (s *SessionService) LogoutAllSessions(userID int, userType string) error {
 	return nil
 }
 
+// TerminateAccountSessions deactivates every session of an account on behalf of an administrator
+// (e.g. when the account is compromised) and returns the number of sessions terminated.
+// An account that doesn't exist is reported as repositories.ErrNotFound.
+func (s *SessionService) TerminateAccountSessions(userID int, userType string, actorID int, actorType, reason string) (int, error) {
+	if err := s.checkAccountExists(userID, userType); err != nil {
+		return 0, err
+	}
+
+	count, err := s.sessionRepo.DeactivateAllUserSessions(userID, userType)
+	if err != nil {
+		return 0, fmt.Errorf("failed to terminate sessions: %w", err)
+	}
+
+	// Log the termination for security auditing
+	fmt.Printf("Sessions terminated: Target=%s:%d, By=%s:%d, Reason=%q, Count=%d, Time=%s\n",
+		userType, userID, actorType, actorID, reason, count, time.Now().Format(time.RFC3339))
+
+	return count, nil
+}
+
+// checkAccountExists looks up the account with the repository for its type, if set
+func (s *SessionService) checkAccountExists(userID int, userType string) error {
+	var err error
+	switch {
+	case userType == "user" && s.userRepo != nil:
+		_, err = s.userRepo.GetByID(userID)
+	case userType == "admin" && s.adminRepo != nil:
+		_, err = s.adminRepo.GetByID(userID)
+	case userType == "gamenet" && s.gamenetRepo != nil:
+		_, err = s.gamenetRepo.GetByID(userID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", userType, err)
+	}
+	return nil
+}
+
 // CleanupExpiredSessions removes expired sessions from the database
 func (s *SessionService) CleanupExpiredSessions() error {
 	err := s.sessionRepo.CleanupExpiredSessions()
@@ -9,8 +9,10 @@ type SessionServiceInterface interface {
 	CreateSession(userID int, userType, deviceInfo, ipAddress, userAgent string, rememberMe bool) (*models.UserSession, string, error)
 	ValidateAndUpdateSession(sessionToken string) (*models.UserSession, error)
 	GetActiveSessions(userID int, userType string, currentSessionToken string) ([]models.SessionResponse, error)
+	GetSession(sessionID int, currentSessionToken string) (*models.SessionResponse, error)
 	LogoutSession(sessionID int, userID int, userType string) error
 	LogoutAllOtherSessions(userID int, userType string, currentSessionToken string) error
 	LogoutAllSessions(userID int, userType string) error
+	TerminateAccountSessions(userID int, userType string, actorID int, actorType, reason string) (int, error)
 	CleanupExpiredSessions() error
 }
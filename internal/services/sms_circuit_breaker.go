@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gatehide/gatehide-api/internal/utils"
+	"github.com/kavenegar/kavenegar-go"
+)
+
+// ErrSMSCircuitOpen is returned without contacting Kavenegar while the SMS circuit breaker is open
+var ErrSMSCircuitOpen = fmt.Errorf("sms provider unavailable: %w", utils.ErrCircuitOpen)
+
+// circuitBreakerClient wraps a KavenegarClient with a circuit breaker so that calls
+// fail fast while the provider is down instead of waiting for every timeout
+type circuitBreakerClient struct {
+	client  KavenegarClient
+	breaker *utils.CircuitBreaker
+}
+
+func (c *circuitBreakerClient) SendMessage(sender string, receptor []string, message string, params *kavenegar.MessageSendParam) ([]kavenegar.Message, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return nil, ErrSMSCircuitOpen
+	}
+	res, err := c.client.SendMessage(sender, receptor, message, params)
+	c.record(err)
+	return res, err
+}
+
+func (c *circuitBreakerClient) VerifyLookup(receptor, template, token string, params *kavenegar.VerifyLookupParam) (kavenegar.Message, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return kavenegar.Message{}, ErrSMSCircuitOpen
+	}
+	res, err := c.client.VerifyLookup(receptor, template, token, params)
+	c.record(err)
+	return res, err
+}
+
+func (c *circuitBreakerClient) AccountInfo() (kavenegar.AccountInfo, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return kavenegar.AccountInfo{}, ErrSMSCircuitOpen
+	}
+	res, err := c.client.AccountInfo()
+	c.record(err)
+	return res, err
+}
+
+func (c *circuitBreakerClient) MessageStatus(messageIDs []string) ([]kavenegar.MessageStatus, error) {
+	if err := c.breaker.Allow(); err != nil {
+		return nil, ErrSMSCircuitOpen
+	}
+	res, err := c.client.MessageStatus(messageIDs)
+	c.record(err)
+	return res, err
+}
+
+// record reports a call's outcome to the breaker. API errors mean Kavenegar answered
+// (e.g. an invalid receptor or missing template), so only transport and HTTP errors
+// count as provider failures.
+func (c *circuitBreakerClient) record(err error) {
+	var apiErr *kavenegar.APIError
+	if err == nil || errors.As(err, &apiErr) {
+		c.breaker.Success()
+		return
+	}
+	c.breaker.Failure()
+}
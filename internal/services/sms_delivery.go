@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/kavenegar/kavenegar-go"
+)
+
+// ErrSMSMessageNotFound is returned when the provider does not know a message ID
+var ErrSMSMessageNotFound = errors.New("SMS message not found")
+
+// smsDeliveryStates maps Kavenegar message statuses to a delivery state. Statuses
+// not listed (queued, scheduled, sent to the operator) are still pending.
+var smsDeliveryStates = map[kavenegar.MessageStatusType]models.SMSDeliveryState{
+	kavenegar.Type_MessageStatus_Delivered:   models.SMSDeliveryDelivered,
+	kavenegar.Type_MessageStatus_Failed:      models.SMSDeliveryFailed,
+	kavenegar.Type_MessageStatus_Undelivered: models.SMSDeliveryFailed,
+	kavenegar.Type_MessageStatus_Canceled:    models.SMSDeliveryFailed,
+	kavenegar.Type_MessageStatus_Filtered:    models.SMSDeliveryFailed,
+}
+
+// GetDeliveryStatus asks Kavenegar whether the message with the given provider
+// message ID reached the recipient. When the send was recorded, the record is
+// updated with the status and its details are included in the result.
+func (s *SMSService) GetDeliveryStatus(ctx context.Context, messageID int) (*models.SMSDeliveryStatus, error) {
+	if !s.config.Enabled {
+		return nil, ErrSMSDisabled
+	}
+
+	if s.client == nil {
+		return nil, ErrSMSNotConfigured
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.client.MessageStatus([]string{strconv.Itoa(messageID)})
+	if err != nil {
+		return nil, s.handleKavenegarError(err)
+	}
+
+	var entry *kavenegar.MessageStatus
+	for i := range entries {
+		if entries[i].MessageId == messageID {
+			entry = &entries[i]
+			break
+		}
+	}
+	// Kavenegar answers unknown IDs with the "incorrect" status
+	if entry == nil || kavenegar.MessageStatusType(entry.Status) == kavenegar.Type_MessageStatus_Incorrect {
+		return nil, ErrSMSMessageNotFound
+	}
+
+	delivery, ok := smsDeliveryStates[kavenegar.MessageStatusType(entry.Status)]
+	if !ok {
+		delivery = models.SMSDeliveryPending
+	}
+
+	checkedAt := time.Now()
+	status := &models.SMSDeliveryStatus{
+		MessageID:      messageID,
+		Delivery:       delivery,
+		ProviderStatus: entry.Status,
+		StatusText:     entry.StatusText,
+		CheckedAt:      models.NewTimestamp(checkedAt),
+	}
+
+	if s.logs != nil {
+		s.updateDeliveryRecord(status, checkedAt)
+	}
+
+	return status, nil
+}
+
+// updateDeliveryRecord stores the delivery status on the send record and copies the
+// record's details into status. Failures are logged and never fail the status check.
+func (s *SMSService) updateDeliveryRecord(status *models.SMSDeliveryStatus, checkedAt time.Time) {
+	log, err := s.logs.GetByProviderMessageID(status.MessageID)
+	if err != nil {
+		if !errors.Is(err, repositories.ErrNotFound) {
+			fmt.Printf("Warning: failed to load SMS record for message %d: %v\n", status.MessageID, err)
+		}
+		return
+	}
+
+	status.Recipient = log.Recipient
+	status.MessageType = log.MessageType
+	sentAt := models.NewTimestamp(log.CreatedAt)
+	status.SentAt = &sentAt
+
+	if err := s.logs.UpdateDeliveryStatus(log.ID, status.Delivery, checkedAt); err != nil {
+		fmt.Printf("Warning: failed to record delivery status for message %d: %v\n", status.MessageID, err)
+	}
+}
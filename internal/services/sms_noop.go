@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+)
+
+// NoopSMSService is the SMS service used when SMS is disabled. Nothing is sent and
+// every send returns ErrSMSDisabled, so callers can tell a skipped message from a
+// failed one.
+type NoopSMSService struct{}
+
+var _ SMSServiceInterface = (*NoopSMSService)(nil)
+
+// NewNoopSMSService creates an SMS service that never sends anything
+func NewNoopSMSService() *NoopSMSService {
+	return &NoopSMSService{}
+}
+
+func (s *NoopSMSService) SendSMS(ctx context.Context, sms *models.SMSNotification) error {
+	return ErrSMSDisabled
+}
+
+func (s *NoopSMSService) SendBulkSMS(ctx context.Context, smsMessages []*models.SMSNotification) error {
+	return ErrSMSDisabled
+}
+
+func (s *NoopSMSService) SendBulkSMSWithResults(ctx context.Context, smsMessages []*models.SMSNotification) ([]models.BulkSMSResult, error) {
+	return nil, ErrSMSDisabled
+}
+
+// ValidatePhoneNumber validates phone numbers like the Kavenegar service does, so
+// requests are checked the same way whether or not SMS is enabled
+func (s *NoopSMSService) ValidatePhoneNumber(phone string) bool {
+	return validatePhoneNumber(phone)
+}
+
+func (s *NoopSMSService) TestConnection(ctx context.Context) error {
+	return ErrSMSDisabled
+}
+
+func (s *NoopSMSService) SendTemplate(ctx context.Context, mobile, templateName string, tokens map[string]string) error {
+	return ErrSMSDisabled
+}
+
+func (s *NoopSMSService) SendUserCredentials(ctx context.Context, mobile, email, password string) error {
+	return ErrSMSDisabled
+}
+
+func (s *NoopSMSService) SendGamenetCredentials(ctx context.Context, mobile, email, password string) error {
+	return ErrSMSDisabled
+}
+
+func (s *NoopSMSService) GetDeliveryStatus(ctx context.Context, messageID int) (*models.SMSDeliveryStatus, error) {
+	return nil, ErrSMSDisabled
+}
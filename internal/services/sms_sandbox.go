@@ -0,0 +1,66 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/kavenegar/kavenegar-go"
+)
+
+// ErrSMSSandboxed is returned instead of sending when the sandbox has no SMS recipient.
+// It matches ErrSMSDisabled, so callers skip the message as they do when SMS is off.
+var ErrSMSSandboxed = fmt.Errorf("%w: no sandbox recipient configured", ErrSMSDisabled)
+
+// sandboxClient wraps a KavenegarClient so that no message reaches a real recipient:
+// every receptor is replaced by the sandbox number, or the message is dropped with
+// ErrSMSSandboxed when no sandbox number is configured.
+type sandboxClient struct {
+	client    KavenegarClient
+	recipient string
+}
+
+func (c *sandboxClient) SendMessage(sender string, receptor []string, message string, params *kavenegar.MessageSendParam) ([]kavenegar.Message, error) {
+	original := strings.Join(receptor, ", ")
+	if c.recipient == "" {
+		fmt.Printf("Sandbox: SMS to %s dropped, Time=%s\n", original, time.Now().Format(time.RFC3339))
+		return nil, ErrSMSSandboxed
+	}
+
+	fmt.Printf("Sandbox: SMS to %s rerouted to %s, Time=%s\n", original, c.recipient, time.Now().Format(time.RFC3339))
+	rerouted := make([]string, len(receptor))
+	for i := range receptor {
+		rerouted[i] = c.recipient
+	}
+	return c.client.SendMessage(sender, rerouted, fmt.Sprintf("[SANDBOX to %s] %s", original, message), params)
+}
+
+// VerifyLookup reroutes a template message. The template text cannot carry a note,
+// so the original receptor is only recorded in the log.
+func (c *sandboxClient) VerifyLookup(receptor, template, token string, params *kavenegar.VerifyLookupParam) (kavenegar.Message, error) {
+	if c.recipient == "" {
+		fmt.Printf("Sandbox: SMS template %s to %s dropped, Time=%s\n", template, receptor, time.Now().Format(time.RFC3339))
+		return kavenegar.Message{}, ErrSMSSandboxed
+	}
+
+	fmt.Printf("Sandbox: SMS template %s to %s rerouted to %s, Time=%s\n", template, receptor, c.recipient, time.Now().Format(time.RFC3339))
+	return c.client.VerifyLookup(c.recipient, template, token, params)
+}
+
+func (c *sandboxClient) AccountInfo() (kavenegar.AccountInfo, error) {
+	return c.client.AccountInfo()
+}
+
+func (c *sandboxClient) MessageStatus(messageIDs []string) ([]kavenegar.MessageStatus, error) {
+	return c.client.MessageStatus(messageIDs)
+}
+
+// SetSandbox reroutes every message sent from now on to the sandbox SMS recipient,
+// or drops them when it is empty. It has no effect when SMS is not configured.
+func (s *SMSService) SetSandbox(sandbox *config.SandboxConfig) {
+	if s.client == nil {
+		return
+	}
+	s.client = &sandboxClient{client: s.client, recipient: sandbox.SMSRecipient}
+}
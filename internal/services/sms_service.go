@@ -20,6 +20,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -27,15 +28,103 @@ import (
 
 	"github.com/gatehide/gatehide-api/config"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/utils"
 	"github.com/kavenegar/kavenegar-go"
 )
 
+// Kavenegar Verify Lookup token slots
+const (
+	SMSTokenSlot1  = "token"
+	SMSTokenSlot2  = "token2"
+	SMSTokenSlot3  = "token3"
+	SMSTokenSlot10 = "token10"
+	SMSTokenSlot20 = "token20"
+)
+
+// smsTokenMaxLength is the maximum length of a single template token
+const smsTokenMaxLength = 100
+
+// defaultSMSSendTimeout bounds sending one message when SendTimeoutSeconds is not set
+const defaultSMSSendTimeout = 30 * time.Second
+
+// smsTokenSlotMaxSpaces is the number of spaces each token slot accepts
+var smsTokenSlotMaxSpaces = map[string]int{
+	SMSTokenSlot1:  0,
+	SMSTokenSlot2:  0,
+	SMSTokenSlot3:  0,
+	SMSTokenSlot10: 5,
+	SMSTokenSlot20: 8,
+}
+
+// ErrSMSTemplateUnavailable is returned when a Verify Lookup template is missing or rejected
+var ErrSMSTemplateUnavailable = errors.New("sms template unavailable")
+
+// ErrSMSDisabled is returned by every send when SMS is turned off in the configuration.
+// Callers can check for it and skip the message instead of reporting a failure.
+var ErrSMSDisabled = errors.New("SMS service is disabled")
+
+// ErrSMSNotConfigured is returned when SMS is enabled but no Kavenegar client is set up,
+// e.g. because the API key is missing
+var ErrSMSNotConfigured = errors.New("SMS service not properly configured")
+
+// SMS delivery strategies
+const (
+	SMSStrategyVerifyOnly    = "verify_only"
+	SMSStrategySMSOnly       = "sms_only"
+	SMSStrategyVerifyThenSMS = "verify_then_sms"
+)
+
+// SMS message types, used to look up the Verify Lookup template name in SMSConfig.Templates
+// and to break down SMS usage statistics
+const (
+	SMSMessageUserCredentials    = "user_credentials"
+	SMSMessageGamenetCredentials = "gamenet_credentials"
+	SMSMessageOTP                = "otp"
+	SMSMessageNotification       = "notification"
+)
+
+// KavenegarClient is the subset of the Kavenegar API used by SMSService
+type KavenegarClient interface {
+	SendMessage(sender string, receptor []string, message string, params *kavenegar.MessageSendParam) ([]kavenegar.Message, error)
+	VerifyLookup(receptor, template, token string, params *kavenegar.VerifyLookupParam) (kavenegar.Message, error)
+	AccountInfo() (kavenegar.AccountInfo, error)
+	MessageStatus(messageIDs []string) ([]kavenegar.MessageStatus, error)
+}
+
+// kavenegarAdapter adapts the Kavenegar SDK to KavenegarClient
+type kavenegarAdapter struct {
+	api *kavenegar.Kavenegar
+}
+
+func (a *kavenegarAdapter) SendMessage(sender string, receptor []string, message string, params *kavenegar.MessageSendParam) ([]kavenegar.Message, error) {
+	return a.api.Message.Send(sender, receptor, message, params)
+}
+
+func (a *kavenegarAdapter) VerifyLookup(receptor, template, token string, params *kavenegar.VerifyLookupParam) (kavenegar.Message, error) {
+	return a.api.Verify.Lookup(receptor, template, token, params)
+}
+
+func (a *kavenegarAdapter) AccountInfo() (kavenegar.AccountInfo, error) {
+	return a.api.Account.Info()
+}
+
+func (a *kavenegarAdapter) MessageStatus(messageIDs []string) ([]kavenegar.MessageStatus, error) {
+	return a.api.Message.Status(messageIDs)
+}
+
 // SMSService implements SMSServiceInterface using Kavenegar
 type SMSService struct {
-	client *kavenegar.Kavenegar
-	config *config.SMSConfig
+	client    KavenegarClient
+	config    *config.SMSConfig
+	templates TemplateServiceInterface
+	logs      repositories.SMSLogRepositoryInterface
+	breaker   *utils.CircuitBreaker
 }
 
+// Ensure SMSService satisfies SMSServiceInterface
+var _ SMSServiceInterface = (*SMSService)(nil)
+
 // NewSMSService creates a new SMS service instance
 func NewSMSService(cfg *config.SMSConfig) *SMSService {
 	if !cfg.Enabled || cfg.APIKey == "" {
@@ -45,26 +134,108 @@ func NewSMSService(cfg *config.SMSConfig) *SMSService {
 		}
 	}
 
-	client := kavenegar.New(cfg.APIKey)
+	return NewSMSServiceWithClient(cfg, &kavenegarAdapter{api: kavenegar.New(cfg.APIKey)})
+}
+
+// NewSMSServiceWithClient creates a new SMS service using the given Kavenegar client.
+// Calls go through a circuit breaker configured by cfg.CircuitBreaker.
+func NewSMSServiceWithClient(cfg *config.SMSConfig, client KavenegarClient) *SMSService {
+	breaker := utils.NewCircuitBreaker(
+		cfg.CircuitBreaker.FailureThreshold,
+		time.Duration(cfg.CircuitBreaker.OpenSeconds)*time.Second,
+	)
 	return &SMSService{
-		client: client,
-		config: cfg,
+		client:  &circuitBreakerClient{client: client, breaker: breaker},
+		config:  cfg,
+		breaker: breaker,
 	}
 }
 
+// CircuitState returns the state of the circuit breaker around Kavenegar:
+// closed, open or half_open. It is empty when SMS is not configured.
+func (s *SMSService) CircuitState() string {
+	if s.breaker == nil {
+		return ""
+	}
+	return s.breaker.State()
+}
+
+// SetTemplateService sets the template service used to render plain-SMS copy.
+// Without one, the built-in default templates are used.
+func (s *SMSService) SetTemplateService(templates TemplateServiceInterface) {
+	s.templates = templates
+}
+
+// SetLogRepository sets the repository used to record every SMS handed to the provider.
+// Without one, sends are not recorded and usage statistics stay empty.
+func (s *SMSService) SetLogRepository(logs repositories.SMSLogRepositoryInterface) {
+	s.logs = logs
+}
+
+// recordSend stores the outcome of a send for usage statistics. Failures to record are
+// logged and never fail the send itself. Messages dropped because SMS is disabled or
+// sandboxed never reached the provider and are not recorded.
+func (s *SMSService) recordSend(messageType, recipient string, messageID int, sendErr error) {
+	if s.logs == nil || !s.config.Enabled || errors.Is(sendErr, ErrSMSDisabled) {
+		return
+	}
+	if messageType == "" {
+		messageType = SMSMessageNotification
+	}
+
+	log := &models.SMSLog{
+		Recipient:   s.normalizePhoneNumber(recipient),
+		MessageType: messageType,
+		Status:      models.SMSLogStatusSent,
+	}
+	if messageID != 0 {
+		log.ProviderMessageID = &messageID
+	}
+	if sendErr != nil {
+		errMsg := sendErr.Error()
+		log.Status = models.SMSLogStatusFailed
+		log.ErrorMsg = &errMsg
+	}
+
+	if err := s.logs.Create(log); err != nil {
+		fmt.Printf("Warning: failed to record %s SMS to %s: %v\n", messageType, recipient, err)
+	}
+}
+
+// renderTemplate renders the named SMS template, preferring the stored copy and
+// falling back to the built-in default when it is missing or inactive
+func (s *SMSService) renderTemplate(ctx context.Context, name string, data map[string]interface{}) (string, error) {
+	template := loadStoredTemplate(ctx, s.templates, name, models.NotificationTypeSMS)
+	if template == nil {
+		template = defaultTemplate(name, models.NotificationTypeSMS)
+	}
+	if template == nil {
+		return "", fmt.Errorf("SMS template not found: %s", name)
+	}
+
+	return renderTemplateString(template.Content, data), nil
+}
+
 // SendSMS sends an SMS message using Kavenegar
 func (s *SMSService) SendSMS(ctx context.Context, sms *models.SMSNotification) error {
+	messageID, err := s.sendSMS(ctx, sms)
+	s.recordSend(sms.Type, sms.To, messageID, err)
+	return err
+}
+
+// sendSMS sends a single SMS with retry logic and returns the provider message ID
+func (s *SMSService) sendSMS(ctx context.Context, sms *models.SMSNotification) (int, error) {
 	if !s.config.Enabled {
-		return fmt.Errorf("SMS service is disabled")
+		return 0, ErrSMSDisabled
 	}
 
 	if s.client == nil {
-		return fmt.Errorf("SMS service not properly configured")
+		return 0, ErrSMSNotConfigured
 	}
 
 	// Validate phone number
 	if !s.ValidatePhoneNumber(sms.To) {
-		return fmt.Errorf("invalid phone number: %s", sms.To)
+		return 0, fmt.Errorf("invalid phone number: %s", sms.To)
 	}
 
 	// Normalize phone number
@@ -73,138 +244,216 @@ func (s *SMSService) SendSMS(ctx context.Context, sms *models.SMSNotification) e
 	// Prepare message
 	message := strings.TrimSpace(sms.Message)
 	if message == "" {
-		return fmt.Errorf("message cannot be empty")
+		return 0, fmt.Errorf("message cannot be empty")
 	}
 
 	// Set timeout for the request
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, s.sendTimeout())
 	defer cancel()
 
+	if s.config.TestMode {
+		// In test mode, mark the message so it can't be mistaken for a real one
+		message = fmt.Sprintf("[TEST] %s", message)
+	}
+
 	// Send SMS with retry logic
 	var lastErr error
 	for attempt := 1; attempt <= s.config.MaxRetries; attempt++ {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return 0, ctx.Err()
 		default:
 		}
 
 		// Send the SMS
 		receptor := []string{phoneNumber}
-		sender := s.config.Sender
-		if s.config.TestMode {
-			// In test mode, we might want to use a different sender or add test prefix
-			message = fmt.Sprintf("[TEST] %s", message)
-		}
-
-		res, err := s.client.Message.Send(sender, receptor, message, nil)
+		res, err := s.client.SendMessage(s.config.Sender, receptor, message, nil)
 		if err != nil {
 			lastErr = err
-			if attempt < s.config.MaxRetries {
-				// Wait before retry (exponential backoff)
-				time.Sleep(time.Duration(attempt) * time.Second)
+			// Retrying cannot succeed while the breaker is open or the sandbox drops messages
+			if attempt < s.config.MaxRetries && !errors.Is(err, ErrSMSCircuitOpen) && !errors.Is(err, ErrSMSDisabled) {
+				if err := s.waitBeforeRetry(ctx, attempt); err != nil {
+					return 0, err
+				}
 				continue
 			}
-			return s.handleKavenegarError(err)
+			return 0, s.handleKavenegarError(err)
 		}
 
 		// Check if the response indicates success
 		if len(res) > 0 && res[0].Status == 1 {
-			return nil
+			return res[0].MessageID, nil
 		}
 
 		// If we get here, the response indicates failure
-		lastErr = fmt.Errorf("SMS sending failed with status: %d", res[0].Status)
+		if len(res) == 0 {
+			lastErr = fmt.Errorf("SMS sending failed: no response from Kavenegar")
+		} else {
+			lastErr = fmt.Errorf("SMS sending failed with status: %d", res[0].Status)
+		}
 		if attempt < s.config.MaxRetries {
-			time.Sleep(time.Duration(attempt) * time.Second)
-			continue
+			if err := s.waitBeforeRetry(ctx, attempt); err != nil {
+				return 0, err
+			}
 		}
 	}
 
-	return fmt.Errorf("SMS sending failed after %d attempts: %w", s.config.MaxRetries, lastErr)
+	return 0, fmt.Errorf("SMS sending failed after %d attempts: %w", s.config.MaxRetries, lastErr)
 }
 
-// SendBulkSMS sends multiple SMS messages
+// SendBulkSMS sends multiple SMS messages.
+// Every message is attempted; an error summarising the failures is returned if any message failed.
 func (s *SMSService) SendBulkSMS(ctx context.Context, smsMessages []*models.SMSNotification) error {
+	results, err := s.SendBulkSMSWithResults(ctx, smsMessages)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	var firstErr string
+	for i, result := range results {
+		if result.Error != "" {
+			if failed == 0 {
+				firstErr = fmt.Sprintf("message %d: %s", i+1, result.Error)
+			}
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to send %d of %d SMS messages (%s)", failed, len(results), firstErr)
+	}
+
+	return nil
+}
+
+// SendBulkSMSWithResults sends multiple SMS messages, continuing past individual failures.
+// It returns one result per message, in input order. Each message is bounded by the
+// configured send timeout. An error is returned only when the batch cannot be attempted
+// at all or the context ends before every message was tried.
+func (s *SMSService) SendBulkSMSWithResults(ctx context.Context, smsMessages []*models.SMSNotification) ([]models.BulkSMSResult, error) {
 	if !s.config.Enabled {
-		return fmt.Errorf("SMS service is disabled")
+		return nil, ErrSMSDisabled
 	}
 
 	if s.client == nil {
-		return fmt.Errorf("SMS service not properly configured")
+		return nil, ErrSMSNotConfigured
 	}
 
 	if len(smsMessages) == 0 {
-		return fmt.Errorf("no SMS messages to send")
+		return nil, fmt.Errorf("no SMS messages to send")
 	}
 
-	// Validate all phone numbers and messages
+	results := make([]models.BulkSMSResult, len(smsMessages))
 	for i, sms := range smsMessages {
+		results[i].To = sms.To
+
+		if err := ctx.Err(); err != nil {
+			// Mark everything that was never attempted
+			for j := i; j < len(smsMessages); j++ {
+				results[j].To = smsMessages[j].To
+				results[j].Error = err.Error()
+			}
+			return results, err
+		}
+
 		if !s.ValidatePhoneNumber(sms.To) {
-			return fmt.Errorf("invalid phone number at index %d: %s", i, sms.To)
+			results[i].Error = fmt.Sprintf("invalid phone number: %s", sms.To)
+			continue
 		}
 		if strings.TrimSpace(sms.Message) == "" {
-			return fmt.Errorf("empty message at index %d", i)
+			results[i].Error = "empty message"
+			continue
 		}
-	}
 
-	// Set timeout for the request
-	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
-	defer cancel()
+		// Each message gets its own timeout, so a large batch isn't cut short
+		// by a deadline sized for a few messages
+		msgCtx, cancel := context.WithTimeout(ctx, s.sendTimeout())
+		res, err := s.sendBulkMessage(msgCtx, sms)
+		cancel()
+		if err != nil {
+			s.recordSend(sms.Type, sms.To, 0, err)
+			results[i].Error = err.Error()
+			continue
+		}
+		s.recordSend(sms.Type, sms.To, res.MessageID, nil)
+		results[i].MessageID = res.MessageID
+		results[i].Status = int(res.Status)
+	}
 
-	// Send each SMS individually with retry logic
-	var lastErr error
-	for i, sms := range smsMessages {
-		phoneNumber := s.normalizePhoneNumber(sms.To)
-		message := strings.TrimSpace(sms.Message)
+	return results, nil
+}
 
-		if s.config.TestMode {
-			message = fmt.Sprintf("[TEST] %s", message)
-		}
+// sendBulkMessage sends one message of a bulk batch with retry logic
+func (s *SMSService) sendBulkMessage(ctx context.Context, sms *models.SMSNotification) (*kavenegar.Message, error) {
+	phoneNumber := s.normalizePhoneNumber(sms.To)
+	message := strings.TrimSpace(sms.Message)
 
-		// Send individual SMS with retry logic
-		for attempt := 1; attempt <= s.config.MaxRetries; attempt++ {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
+	if s.config.TestMode {
+		message = fmt.Sprintf("[TEST] %s", message)
+	}
 
-			// Send the SMS
-			res, err := s.client.Message.Send(s.config.Sender, []string{phoneNumber}, message, nil)
-			if err != nil {
-				lastErr = err
-				if attempt < s.config.MaxRetries {
-					time.Sleep(time.Duration(attempt) * time.Second)
-					continue
-				}
-				return s.handleKavenegarError(err)
-			}
+	var lastErr error
+	for attempt := 1; attempt <= s.config.MaxRetries; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
 
-			// Check if the response indicates success
-			if len(res) > 0 && res[0].Status == 1 {
-				break // Success, move to next SMS
-			}
+		res, err := s.client.SendMessage(s.config.Sender, []string{phoneNumber}, message, nil)
+		switch {
+		case errors.Is(err, ErrSMSCircuitOpen), errors.Is(err, ErrSMSDisabled):
+			return nil, err
+		case err != nil:
+			lastErr = s.handleKavenegarError(err)
+		case len(res) == 0:
+			lastErr = fmt.Errorf("SMS sending failed: no response from Kavenegar")
+		case res[0].Status == 1:
+			return &res[0], nil
+		default:
+			lastErr = fmt.Errorf("SMS sending failed with status: %d", res[0].Status)
+		}
 
-			// If we get here, the response indicates failure
-			lastErr = fmt.Errorf("SMS sending failed for message %d with status: %d", i+1, res[0].Status)
-			if attempt < s.config.MaxRetries {
-				time.Sleep(time.Duration(attempt) * time.Second)
-				continue
+		if attempt < s.config.MaxRetries {
+			if err := s.waitBeforeRetry(ctx, attempt); err != nil {
+				return nil, err
 			}
 		}
+	}
 
-		// If we couldn't send this SMS after all retries, return error
-		if lastErr != nil {
-			return fmt.Errorf("failed to send SMS %d after %d attempts: %w", i+1, s.config.MaxRetries, lastErr)
-		}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("SMS sending was not attempted")
 	}
+	return nil, fmt.Errorf("failed after %d attempts: %w", s.config.MaxRetries, lastErr)
+}
 
-	return nil
+// sendTimeout returns how long sending one message, including its retries, may take
+func (s *SMSService) sendTimeout() time.Duration {
+	if s.config.SendTimeoutSeconds <= 0 {
+		return defaultSMSSendTimeout
+	}
+	return time.Duration(s.config.SendTimeoutSeconds) * time.Second
+}
+
+// waitBeforeRetry waits out the backoff before the given retry, returning early with
+// the context's error when it ends first
+func (s *SMSService) waitBeforeRetry(ctx context.Context, retry int) error {
+	backoff := utils.RetryBackoff{
+		Base: time.Duration(s.config.RetryBaseDelayMS) * time.Millisecond,
+		Max:  time.Duration(s.config.RetryMaxDelayMS) * time.Millisecond,
+	}
+	return utils.SleepContext(ctx, backoff.Delay(retry))
 }
 
 // ValidatePhoneNumber validates a phone number format
 func (s *SMSService) ValidatePhoneNumber(phone string) bool {
+	return validatePhoneNumber(phone)
+}
+
+// validatePhoneNumber reports whether phone is an Iranian mobile number, in local or
+// international format
+func validatePhoneNumber(phone string) bool {
 	if phone == "" {
 		return false
 	}
@@ -239,11 +488,11 @@ func (s *SMSService) ValidatePhoneNumber(phone string) bool {
 // TestConnection tests the SMS service connection
 func (s *SMSService) TestConnection(ctx context.Context) error {
 	if !s.config.Enabled {
-		return fmt.Errorf("SMS service is disabled")
+		return ErrSMSDisabled
 	}
 
 	if s.client == nil {
-		return fmt.Errorf("SMS service not properly configured")
+		return ErrSMSNotConfigured
 	}
 
 	// Set timeout for the request
@@ -251,7 +500,7 @@ func (s *SMSService) TestConnection(ctx context.Context) error {
 	defer cancel()
 
 	// Try to get account info to test the connection
-	_, err := s.client.Account.Info()
+	_, err := s.client.AccountInfo()
 	if err != nil {
 		return s.handleKavenegarError(err)
 	}
@@ -288,184 +537,270 @@ func (s *SMSService) normalizePhoneNumber(phone string) string {
 	return cleaned
 }
 
-// SendGamenetCredentials sends gamenet credentials using Kavenegar Verify Lookup or regular SMS as fallback
+// SendGamenetCredentials sends gamenet credentials using the configured delivery strategy
 func (s *SMSService) SendGamenetCredentials(ctx context.Context, mobile, email, password string) error {
-	if !s.config.Enabled {
-		return fmt.Errorf("SMS service is disabled")
+	tokens := map[string]string{
+		SMSTokenSlot1: email,
+		SMSTokenSlot2: email,
+		SMSTokenSlot3: password,
 	}
 
-	if s.client == nil {
-		return fmt.Errorf("SMS service not properly configured")
+	return s.deliver(ctx, mobile, SMSMessageGamenetCredentials, tokens, func(phoneNumber string) (int, error) {
+		return s.sendCredentialsViaSMS(ctx, phoneNumber, email, password)
+	})
+}
+
+// sendCredentialsViaSMS sends credentials using regular SMS (fallback method)
+func (s *SMSService) sendCredentialsViaSMS(ctx context.Context, phoneNumber, email, password string) (int, error) {
+	message, err := s.renderTemplate(ctx, SMSTemplateGamenetCredentials, map[string]interface{}{
+		"email":    email,
+		"password": password,
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	// Validate phone number
-	if !s.ValidatePhoneNumber(mobile) {
-		return fmt.Errorf("invalid phone number: %s", mobile)
+	// Send the SMS (NO RETRIES to avoid duplicate credentials)
+	receptor := []string{phoneNumber}
+
+	// Use empty sender to use account's default sender line (as per Kavenegar SDK docs)
+	// Reference: https://github.com/kavenegar/kavenegar-go
+	sender := ""
+
+	fmt.Printf("📤 Sending credentials SMS to %s...\n", phoneNumber)
+	res, err := s.client.SendMessage(sender, receptor, message, nil)
+	if err != nil {
+		fmt.Printf("❌ SMS Error: %v\n", err)
+		return 0, s.handleKavenegarError(err)
 	}
 
-	// Normalize phone number
-	phoneNumber := s.normalizePhoneNumber(mobile)
+	// Check if the response indicates message was accepted
+	if len(res) > 0 {
+		fmt.Printf("📱 SMS Response: Status = %d, MessageID = %d\n", res[0].Status, res[0].MessageID)
 
-	// Set timeout for the request
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+		// Kavenegar Status Codes:
+		// 1 = Queued (normal success)
+		// 5 = Sent with sender warning (still delivered)
+		// Accept both status 1 and 5 as success since message is delivered
+		if res[0].Status == 1 || res[0].Status == 5 {
+			fmt.Printf("✅ Credentials SMS sent successfully to %s (MessageID: %d)\n", phoneNumber, res[0].MessageID)
+			return res[0].MessageID, nil
+		}
 
-	// Template name from Kavenegar panel
-	template := "gamenet-credentials"
+		return 0, fmt.Errorf("SMS sending failed with status: %d", res[0].Status)
+	}
 
-	// Try Verify Lookup first (preferred method with template)
-	params := &kavenegar.VerifyLookupParam{
-		Token2: email,
-		Token3: password,
+	return 0, fmt.Errorf("SMS sending failed: no response from Kavenegar")
+}
+
+// SendUserCredentials sends user credentials using the configured delivery strategy
+func (s *SMSService) SendUserCredentials(ctx context.Context, mobile, email, password string) error {
+	tokens := map[string]string{
+		SMSTokenSlot1: email,
+		SMSTokenSlot2: email,
+		SMSTokenSlot3: password,
 	}
 
-	res, err := s.client.Verify.Lookup(phoneNumber, template, email, params)
-	if err != nil {
-		// Check if it's a template not found error (424)
-		if apiErr, ok := err.(*kavenegar.APIError); ok && apiErr.Status == 424 {
-			// Template doesn't exist, use fallback to regular SMS
-			fmt.Printf("Template not found, using regular SMS fallback\n")
-			return s.sendCredentialsViaSMS(ctx, phoneNumber, email, password)
+	return s.deliver(ctx, mobile, SMSMessageUserCredentials, tokens, func(phoneNumber string) (int, error) {
+		return s.sendUserCredentialsViaSMS(ctx, phoneNumber, email, password)
+	})
+}
+
+// deliver sends a message of the given type according to the configured strategy:
+// Verify Lookup only, plain SMS only, or Verify Lookup with plain SMS as fallback.
+// sendPlain sends the plain SMS and returns its provider message ID.
+func (s *SMSService) deliver(ctx context.Context, mobile, messageType string, tokens map[string]string, sendPlain func(phoneNumber string) (int, error)) error {
+	messageID, err := s.deliverByStrategy(ctx, mobile, messageType, tokens, sendPlain)
+	s.recordSend(messageType, mobile, messageID, err)
+	return err
+}
+
+// deliverByStrategy performs the delivery for deliver and returns the provider message ID
+func (s *SMSService) deliverByStrategy(ctx context.Context, mobile, messageType string, tokens map[string]string, sendPlain func(phoneNumber string) (int, error)) (int, error) {
+	var messageID int
+	var err error
+
+	switch s.config.Strategy {
+	case SMSStrategySMSOnly:
+		if !s.config.Enabled {
+			return 0, ErrSMSDisabled
 		}
-		return s.handleKavenegarError(err)
-	}
+		if s.client == nil {
+			return 0, ErrSMSNotConfigured
+		}
+		if !s.ValidatePhoneNumber(mobile) {
+			return 0, fmt.Errorf("invalid phone number: %s", mobile)
+		}
+		return sendPlain(s.normalizePhoneNumber(mobile))
 
-	// Check if the response indicates success
-	if res.Status == 200 {
-		fmt.Printf("Successfully sent credentials SMS via Verify Lookup to %s\n", mobile)
-		return nil
+	case SMSStrategyVerifyOnly:
+		messageID, err = s.sendTemplate(ctx, mobile, s.templateName(messageType), tokens)
+		if err != nil {
+			return 0, err
+		}
+
+	default: // SMSStrategyVerifyThenSMS
+		messageID, err = s.sendTemplate(ctx, mobile, s.templateName(messageType), tokens)
+		if errors.Is(err, ErrSMSTemplateUnavailable) {
+			// Template doesn't exist or was rejected, use fallback to regular SMS
+			fmt.Printf("%v, using regular SMS fallback\n", err)
+			return sendPlain(s.normalizePhoneNumber(mobile))
+		}
+		if err != nil {
+			return 0, err
+		}
 	}
 
-	// If Verify Lookup failed, try regular SMS as fallback
-	fmt.Printf("Verify Lookup returned status %d, using regular SMS fallback\n", res.Status)
-	return s.sendCredentialsViaSMS(ctx, phoneNumber, email, password)
+	fmt.Printf("Successfully sent %s SMS via Verify Lookup to %s\n", messageType, mobile)
+	return messageID, nil
 }
 
-// sendCredentialsViaSMS sends credentials using regular SMS (fallback method)
-func (s *SMSService) sendCredentialsViaSMS(ctx context.Context, phoneNumber, email, password string) error {
-	// ctx parameter is required by interface but not used in this implementation
-	// Construct message
-	message := fmt.Sprintf("اطلاعات ورود به سیستم گیت نت:\nایمیل: %s\nرمز عبور: %s", email, password)
+// templateName returns the Verify Lookup template configured for a message type
+func (s *SMSService) templateName(messageType string) string {
+	if name, ok := s.config.Templates[messageType]; ok && name != "" {
+		return name
+	}
+	return strings.ReplaceAll(messageType, "_", "-")
+}
+
+// sendUserCredentialsViaSMS sends user credentials using regular SMS (fallback method)
+func (s *SMSService) sendUserCredentialsViaSMS(ctx context.Context, phoneNumber, email, password string) (int, error) {
+	message, err := s.renderTemplate(ctx, SMSTemplateUserCredentials, map[string]interface{}{
+		"email":    email,
+		"password": password,
+	})
+	if err != nil {
+		return 0, err
+	}
 
 	// Send the SMS (NO RETRIES to avoid duplicate credentials)
 	receptor := []string{phoneNumber}
 
-	// Use empty sender to use account's default sender line (as per Kavenegar SDK docs)
-	// Reference: https://github.com/kavenegar/kavenegar-go
+	// Use empty sender to use account's default sender line
 	sender := ""
 
-	fmt.Printf("📤 Sending credentials SMS to %s...\n", phoneNumber)
-	res, err := s.client.Message.Send(sender, receptor, message, nil)
+	fmt.Printf("📤 Sending user credentials SMS to %s...\n", phoneNumber)
+	res, err := s.client.SendMessage(sender, receptor, message, nil)
 	if err != nil {
 		fmt.Printf("❌ SMS Error: %v\n", err)
-		return s.handleKavenegarError(err)
+		return 0, s.handleKavenegarError(err)
 	}
 
 	// Check if the response indicates message was accepted
 	if len(res) > 0 {
 		fmt.Printf("📱 SMS Response: Status = %d, MessageID = %d\n", res[0].Status, res[0].MessageID)
 
-		// Kavenegar Status Codes:
-		// 1 = Queued (normal success)
-		// 5 = Sent with sender warning (still delivered)
-		// Accept both status 1 and 5 as success since message is delivered
+		// Accept both status 1 and 5 as success
 		if res[0].Status == 1 || res[0].Status == 5 {
-			fmt.Printf("✅ Credentials SMS sent successfully to %s (MessageID: %d)\n", phoneNumber, res[0].MessageID)
-			return nil
+			fmt.Printf("✅ User credentials SMS sent successfully to %s (MessageID: %d)\n", phoneNumber, res[0].MessageID)
+			return res[0].MessageID, nil
 		}
 
-		return fmt.Errorf("SMS sending failed with status: %d", res[0].Status)
+		return 0, fmt.Errorf("SMS sending failed with status: %d", res[0].Status)
 	}
 
-	return fmt.Errorf("SMS sending failed: no response from Kavenegar")
+	return 0, fmt.Errorf("SMS sending failed: no response from Kavenegar")
 }
 
-// SendUserCredentials sends user credentials using Kavenegar Verify Lookup or regular SMS as fallback
-func (s *SMSService) SendUserCredentials(ctx context.Context, mobile, email, password string) error {
+// SendTemplate sends a templated SMS using Kavenegar Verify Lookup.
+// Tokens are keyed by slot name (SMSTokenSlot1 ... SMSTokenSlot20); SMSTokenSlot1 is required.
+// Returns an error wrapping ErrSMSTemplateUnavailable when the template is missing or the
+// lookup is rejected, so callers can decide whether to fall back to a plain SMS.
+func (s *SMSService) SendTemplate(ctx context.Context, mobile, templateName string, tokens map[string]string) error {
+	_, err := s.sendTemplate(ctx, mobile, templateName, tokens)
+	return err
+}
+
+// sendTemplate sends a Verify Lookup message for SendTemplate and returns its provider message ID
+func (s *SMSService) sendTemplate(ctx context.Context, mobile, templateName string, tokens map[string]string) (int, error) {
 	if !s.config.Enabled {
-		return fmt.Errorf("SMS service is disabled")
+		return 0, ErrSMSDisabled
 	}
 
 	if s.client == nil {
-		return fmt.Errorf("SMS service not properly configured")
+		return 0, ErrSMSNotConfigured
 	}
 
 	// Validate phone number
 	if !s.ValidatePhoneNumber(mobile) {
-		return fmt.Errorf("invalid phone number: %s", mobile)
+		return 0, fmt.Errorf("invalid phone number: %s", mobile)
+	}
+
+	if templateName == "" {
+		return 0, fmt.Errorf("template name cannot be empty")
+	}
+
+	if err := ValidateTemplateTokens(tokens); err != nil {
+		return 0, err
 	}
 
 	// Normalize phone number
 	phoneNumber := s.normalizePhoneNumber(mobile)
 
 	// Set timeout for the request
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, s.sendTimeout())
 	defer cancel()
 
-	// Template name from Kavenegar panel
-	template := "user-credentials"
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
 
-	// Try Verify Lookup first (preferred method with template)
 	params := &kavenegar.VerifyLookupParam{
-		Token2: email,
-		Token3: password,
+		Token2: tokens[SMSTokenSlot2],
+		Token3: tokens[SMSTokenSlot3],
+		Tokens: map[string]string{},
+	}
+	for _, slot := range []string{SMSTokenSlot10, SMSTokenSlot20} {
+		if value, ok := tokens[slot]; ok {
+			params.Tokens[slot] = value
+		}
 	}
 
-	res, err := s.client.Verify.Lookup(phoneNumber, template, email, params)
+	res, err := s.client.VerifyLookup(phoneNumber, templateName, tokens[SMSTokenSlot1], params)
 	if err != nil {
-		// Check if it's a template not found error (424)
+		// Template not found (424)
 		if apiErr, ok := err.(*kavenegar.APIError); ok && apiErr.Status == 424 {
-			// Template doesn't exist, use fallback to regular SMS
-			fmt.Printf("Template not found, using regular SMS fallback\n")
-			return s.sendUserCredentialsViaSMS(ctx, phoneNumber, email, password)
+			return 0, fmt.Errorf("template %s not found: %w", templateName, ErrSMSTemplateUnavailable)
 		}
-		return s.handleKavenegarError(err)
+		return 0, s.handleKavenegarError(err)
 	}
 
 	// Check if the response indicates success
-	if res.Status == 200 {
-		fmt.Printf("Successfully sent user credentials SMS via Verify Lookup to %s\n", mobile)
-		return nil
+	if res.Status != 200 {
+		return 0, fmt.Errorf("verify lookup for template %s returned status %d: %w", templateName, res.Status, ErrSMSTemplateUnavailable)
 	}
 
-	// If Verify Lookup failed, try regular SMS as fallback
-	fmt.Printf("Verify Lookup returned status %d, using regular SMS fallback\n", res.Status)
-	return s.sendUserCredentialsViaSMS(ctx, phoneNumber, email, password)
+	return res.MessageID, nil
 }
 
-// sendUserCredentialsViaSMS sends user credentials using regular SMS (fallback method)
-func (s *SMSService) sendUserCredentialsViaSMS(ctx context.Context, phoneNumber, email, password string) error {
-	// ctx parameter is required by interface but not used in this implementation
-	// Construct message
-	message := fmt.Sprintf("اطلاعات ورود به سیستم:\nایمیل: %s\nرمز عبور: %s", email, password)
-
-	// Send the SMS (NO RETRIES to avoid duplicate credentials)
-	receptor := []string{phoneNumber}
-
-	// Use empty sender to use account's default sender line
-	sender := ""
-
-	fmt.Printf("📤 Sending user credentials SMS to %s...\n", phoneNumber)
-	res, err := s.client.Message.Send(sender, receptor, message, nil)
-	if err != nil {
-		fmt.Printf("❌ SMS Error: %v\n", err)
-		return s.handleKavenegarError(err)
+// ValidateTemplateTokens checks tokens against Kavenegar's Verify Lookup slot constraints
+func ValidateTemplateTokens(tokens map[string]string) error {
+	if strings.TrimSpace(tokens[SMSTokenSlot1]) == "" {
+		return fmt.Errorf("template token %q is required", SMSTokenSlot1)
 	}
 
-	// Check if the response indicates message was accepted
-	if len(res) > 0 {
-		fmt.Printf("📱 SMS Response: Status = %d, MessageID = %d\n", res[0].Status, res[0].MessageID)
-
-		// Accept both status 1 and 5 as success
-		if res[0].Status == 1 || res[0].Status == 5 {
-			fmt.Printf("✅ User credentials SMS sent successfully to %s (MessageID: %d)\n", phoneNumber, res[0].MessageID)
-			return nil
+	for slot, value := range tokens {
+		maxSpaces, ok := smsTokenSlotMaxSpaces[slot]
+		if !ok {
+			return fmt.Errorf("unknown template token slot %q", slot)
+		}
+		if len([]rune(value)) > smsTokenMaxLength {
+			return fmt.Errorf("template token %q exceeds %d characters", slot, smsTokenMaxLength)
+		}
+		if strings.ContainsAny(value, "\n\r\t") {
+			return fmt.Errorf("template token %q cannot contain line breaks or tabs", slot)
+		}
+		if spaces := strings.Count(value, " "); spaces > maxSpaces {
+			if maxSpaces == 0 {
+				return fmt.Errorf("template token %q cannot contain spaces", slot)
+			}
+			return fmt.Errorf("template token %q can contain at most %d spaces", slot, maxSpaces)
 		}
-
-		return fmt.Errorf("SMS sending failed with status: %d", res[0].Status)
 	}
 
-	return fmt.Errorf("SMS sending failed: no response from Kavenegar")
+	return nil
 }
 
 // handleKavenegarError handles Kavenegar-specific errors
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+)
+
+// defaultSMSUsageDays is the daily breakdown range used when no from date is given
+const defaultSMSUsageDays = 30
+
+// smsUsageMessageTypes are the message types always reported in usage windows, even with zero sends
+var smsUsageMessageTypes = []string{
+	SMSMessageUserCredentials,
+	SMSMessageGamenetCredentials,
+	SMSMessageOTP,
+	SMSMessageNotification,
+}
+
+// SMSUsageServiceInterface defines the interface for SMS usage statistics
+type SMSUsageServiceInterface interface {
+	GetUsageStats(ctx context.Context, filter *models.SMSUsageFilter) (*models.SMSUsageStatsResponse, error)
+}
+
+// SMSUsageService implements SMSUsageServiceInterface
+type SMSUsageService struct {
+	smsLogRepo repositories.SMSLogRepositoryInterface
+}
+
+// NewSMSUsageService creates a new SMS usage service
+func NewSMSUsageService(smsLogRepo repositories.SMSLogRepositoryInterface) SMSUsageServiceInterface {
+	return &SMSUsageService{
+		smsLogRepo: smsLogRepo,
+	}
+}
+
+// GetUsageStats returns SMS sent/failed counts for today and the last 7 and 30 days,
+// broken down by message type, plus a paginated daily breakdown for the filtered range
+func (s *SMSUsageService) GetUsageStats(ctx context.Context, filter *models.SMSUsageFilter) (*models.SMSUsageStatsResponse, error) {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	// Set default values
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+	if filter.PageSize > 100 {
+		filter.PageSize = 100
+	}
+	if filter.To == nil {
+		filter.To = &now
+	}
+	if filter.From == nil {
+		to := *filter.To
+		from := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, to.Location()).AddDate(0, 0, -(defaultSMSUsageDays - 1))
+		filter.From = &from
+	}
+	if filter.From.After(*filter.To) {
+		return nil, fmt.Errorf("from date must be before to date")
+	}
+
+	stats := &models.SMSUsageStatsResponse{
+		From:        models.NewTimestamp(*filter.From),
+		To:          models.NewTimestamp(*filter.To),
+		GeneratedAt: models.NewTimestamp(now),
+	}
+
+	windows := []struct {
+		target *models.SMSUsageWindow
+		since  time.Time
+	}{
+		{&stats.Today, startOfDay},
+		{&stats.Last7Days, startOfDay.AddDate(0, 0, -6)},
+		{&stats.Last30Days, startOfDay.AddDate(0, 0, -29)},
+	}
+	for _, w := range windows {
+		counts, err := s.smsLogRepo.CountByType(w.since, filter.MessageType)
+		if err != nil {
+			return nil, err
+		}
+		*w.target = buildSMSUsageWindow(w.since, counts, filter.MessageType)
+	}
+
+	daily, totalItems, err := s.smsLogRepo.ListDailyUsage(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int((totalItems + int64(filter.PageSize) - 1) / int64(filter.PageSize))
+	stats.Daily = daily
+	stats.Pagination = models.PaginationInfo{
+		CurrentPage: filter.Page,
+		PageSize:    filter.PageSize,
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		HasNext:     filter.Page < totalPages,
+		HasPrev:     filter.Page > 1,
+	}
+
+	return stats, nil
+}
+
+// buildSMSUsageWindow totals the per-type counts of a window. Known message types are
+// always listed so the breakdown has a stable shape; a type filter lists only that type.
+func buildSMSUsageWindow(since time.Time, counts []models.SMSUsageCount, messageType string) models.SMSUsageWindow {
+	window := models.SMSUsageWindow{Since: since, ByType: []models.SMSUsageCount{}}
+
+	byType := make(map[string]models.SMSUsageCount, len(counts))
+	for _, count := range counts {
+		byType[count.MessageType] = count
+	}
+
+	types := smsUsageMessageTypes
+	if messageType != "" {
+		types = []string{messageType}
+	}
+	for _, t := range types {
+		count, ok := byType[t]
+		if !ok {
+			count = models.SMSUsageCount{MessageType: t}
+		}
+		delete(byType, t)
+		window.ByType = append(window.ByType, count)
+	}
+
+	// Types recorded by other senders are reported after the known ones
+	for _, count := range counts {
+		if _, ok := byType[count.MessageType]; ok {
+			window.ByType = append(window.ByType, count)
+		}
+	}
+
+	for _, count := range window.ByType {
+		window.Sent += count.Sent
+		window.Failed += count.Failed
+	}
+
+	return window
+}
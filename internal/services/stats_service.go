@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+)
+
+const (
+	// overviewCacheTTL is how long a computed overview is served before recomputing
+	overviewCacheTTL = 30 * time.Second
+	// recentSignupDays is the window used for the recent signups metric
+	recentSignupDays = 7
+	// gamenetActiveUserDays is the login window for a gamenet's active users metric
+	gamenetActiveUserDays = 30
+)
+
+// StatsServiceInterface defines the interface for dashboard statistics
+type StatsServiceInterface interface {
+	GetAdminOverview(ctx context.Context) (*models.AdminOverviewResponse, error)
+	GetGamenetStats(ctx context.Context, gamenetID int) (*models.GamenetStatsResponse, error)
+}
+
+// StatsService implements StatsServiceInterface
+type StatsService struct {
+	statsRepo repositories.StatsRepositoryInterface
+
+	mu       sync.Mutex
+	cached   *models.AdminOverviewResponse
+	cachedAt time.Time
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(statsRepo repositories.StatsRepositoryInterface) StatsServiceInterface {
+	return &StatsService{
+		statsRepo: statsRepo,
+	}
+}
+
+// GetAdminOverview returns aggregated statistics for the admin dashboard. The lock
+// only guards the cached overview; the count queries run without it, so a slow
+// recompute doesn't hold up concurrent callers.
+func (s *StatsService) GetAdminOverview(ctx context.Context) (*models.AdminOverviewResponse, error) {
+	s.mu.Lock()
+	if s.cached != nil && time.Since(s.cachedAt) < overviewCacheTTL {
+		cached := s.cached
+		s.mu.Unlock()
+		return cached, nil
+	}
+	s.mu.Unlock()
+
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	overview := &models.AdminOverviewResponse{
+		TotalUsers:        s.metric("total users", s.statsRepo.CountUsers),
+		TotalGamenets:     s.metric("total gamenets", s.statsRepo.CountGamenets),
+		ActiveSessions:    s.metric("active sessions", s.statsRepo.CountActiveSessions),
+		SubscriptionPlans: s.metric("subscription plans", s.statsRepo.CountSubscriptionPlans),
+		SMSSentToday: s.metric("sms sent today", func() (int, error) {
+			return s.statsRepo.CountSMSSentSince(startOfDay)
+		}),
+		RecentSignups: s.metric("recent signups", func() (int, error) {
+			return s.statsRepo.CountUsersCreatedSince(now.AddDate(0, 0, -recentSignupDays))
+		}),
+		RecentSignupDays: recentSignupDays,
+		GeneratedAt:      models.NewTimestamp(now),
+	}
+
+	// Keep the newest overview when concurrent recomputes finish out of order
+	s.mu.Lock()
+	if now.After(s.cachedAt) {
+		s.cached = overview
+		s.cachedAt = now
+	}
+	s.mu.Unlock()
+
+	return overview, nil
+}
+
+// GetGamenetStats returns dashboard statistics for a single gamenet's users.
+// Returns an error wrapping repositories.ErrNotFound when the gamenet doesn't exist.
+func (s *StatsService) GetGamenetStats(ctx context.Context, gamenetID int) (*models.GamenetStatsResponse, error) {
+	exists, err := s.statsRepo.GamenetExists(gamenetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up gamenet: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("gamenet %d: %w", gamenetID, repositories.ErrNotFound)
+	}
+
+	now := time.Now()
+	linkedSince := func(days int) func() (int, error) {
+		return func() (int, error) {
+			return s.statsRepo.CountGamenetUsersLinkedSince(gamenetID, now.AddDate(0, 0, -days))
+		}
+	}
+
+	return &models.GamenetStatsResponse{
+		GamenetID: gamenetID,
+		LinkedUsers: s.metric("gamenet linked users", func() (int, error) {
+			return s.statsRepo.CountGamenetUsers(gamenetID)
+		}),
+		NewUsersLast7Days:  s.metric("gamenet new users (7 days)", linkedSince(7)),
+		NewUsersLast30Days: s.metric("gamenet new users (30 days)", linkedSince(30)),
+		ActiveUsers: s.metric("gamenet active users", func() (int, error) {
+			return s.statsRepo.CountGamenetUsersActiveSince(gamenetID, now.AddDate(0, 0, -gamenetActiveUserDays))
+		}),
+		ActiveUserDays: gamenetActiveUserDays,
+		GeneratedAt:    models.NewTimestamp(now),
+	}, nil
+}
+
+// metric runs a single count query, returning nil instead of failing the whole overview
+func (s *StatsService) metric(name string, query func() (int, error)) *int {
+	value, err := query()
+	if err != nil {
+		fmt.Printf("Warning: failed to compute %s metric: %v\n", name, err)
+		return nil
+	}
+	return &value
+}
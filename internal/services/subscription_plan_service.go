@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gatehide/gatehide-api/internal/models"
 	"github.com/gatehide/gatehide-api/internal/repositories"
@@ -12,18 +13,47 @@ type SubscriptionPlanServiceInterface interface {
 	CreatePlan(req *models.CreatePlanRequest) (*models.PlanResponse, error)
 	GetPlan(id int) (*models.PlanResponse, error)
 	GetAllPlans(limit, offset int, isActive *bool) ([]*models.PlanResponse, int, error)
-	UpdatePlan(id int, req *models.UpdatePlanRequest) (*models.PlanResponse, error)
+	UpdatePlan(id int, req *models.UpdatePlanRequest, actor *models.Actor) (*models.PlanResponse, error)
+	SetActive(id int, active bool, actor *models.Actor) (*models.PlanResponse, error)
 	DeletePlan(id int) error
+	GetDeleteImpact(id int) (*models.PlanDeleteImpact, error)
+	GetPlanHistory(id int) ([]*models.PlanHistory, error)
+	Clone(id int) (*models.PlanResponse, error)
 }
 
+// DefaultMaxTrialDurationDays is the longest trial a plan may offer unless configured otherwise
+const DefaultMaxTrialDurationDays = 365
+
 // SubscriptionPlanService handles subscription plan business logic
 type SubscriptionPlanService struct {
-	repo repositories.SubscriptionPlanRepositoryInterface
+	repo                 repositories.SubscriptionPlanRepositoryInterface
+	maxTrialDurationDays int
+	// repriceExistingSubscribers moves existing subscribers to a plan's new price;
+	// by default they keep the price they subscribed at
+	repriceExistingSubscribers bool
 }
 
 // NewSubscriptionPlanService creates a new subscription plan service
 func NewSubscriptionPlanService(repo repositories.SubscriptionPlanRepositoryInterface) *SubscriptionPlanService {
-	return &SubscriptionPlanService{repo: repo}
+	return &SubscriptionPlanService{
+		repo:                 repo,
+		maxTrialDurationDays: DefaultMaxTrialDurationDays,
+	}
+}
+
+// SetMaxTrialDurationDays sets the longest allowed trial duration.
+// Non-positive values restore DefaultMaxTrialDurationDays.
+func (s *SubscriptionPlanService) SetMaxTrialDurationDays(days int) {
+	if days <= 0 {
+		days = DefaultMaxTrialDurationDays
+	}
+	s.maxTrialDurationDays = days
+}
+
+// SetRepriceExistingSubscribers controls whether a plan price change also applies to
+// its active subscriptions. When disabled (the default) they keep their subscribed price.
+func (s *SubscriptionPlanService) SetRepriceExistingSubscribers(reprice bool) {
+	s.repriceExistingSubscribers = reprice
 }
 
 // CreatePlan creates a new subscription plan
@@ -82,13 +112,14 @@ func (s *SubscriptionPlanService) GetAllPlans(limit, offset int, isActive *bool)
 	return responses, total, nil
 }
 
-// UpdatePlan updates an existing subscription plan
-func (s *SubscriptionPlanService) UpdatePlan(id int, req *models.UpdatePlanRequest) (*models.PlanResponse, error) {
+// UpdatePlan updates an existing subscription plan and records the change in its history
+func (s *SubscriptionPlanService) UpdatePlan(id int, req *models.UpdatePlanRequest, actor *models.Actor) (*models.PlanResponse, error) {
 	// Get existing plan
 	existingPlan, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get existing plan: %w", err)
 	}
+	before := existingPlan.ToResponse()
 
 	// Update fields if provided
 	if req.Name != nil {
@@ -115,14 +146,127 @@ func (s *SubscriptionPlanService) UpdatePlan(id int, req *models.UpdatePlanReque
 		return nil, err
 	}
 
-	if err := s.repo.Update(id, existingPlan); err != nil {
+	response := existingPlan.ToResponse()
+	history := newPlanHistory(&before, &response, actor)
+	priceChanged := before.Price != response.Price
+	history.RepriceSubscriptions = priceChanged && s.repriceExistingSubscribers
+
+	if err := s.repo.Update(id, existingPlan, history); err != nil {
 		return nil, fmt.Errorf("failed to update plan: %w", err)
 	}
 
-	response := existingPlan.ToResponse()
+	if priceChanged {
+		logPriceChange(history)
+	}
+
 	return &response, nil
 }
 
+// SetActive activates or deactivates a plan and records the change in its history.
+// Unlike DeletePlan, deactivation is allowed while the plan has active subscriptions:
+// they keep running, but no new subscriptions can be made. Setting the current state
+// again returns the plan unchanged.
+func (s *SubscriptionPlanService) SetActive(id int, active bool, actor *models.Actor) (*models.PlanResponse, error) {
+	plan, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+
+	before := plan.ToResponse()
+	if plan.IsActive == active {
+		return &before, nil
+	}
+
+	plan.IsActive = active
+	response := plan.ToResponse()
+	history := newPlanHistory(&before, &response, actor)
+
+	if err := s.repo.Update(id, plan, history); err != nil {
+		return nil, fmt.Errorf("failed to update plan: %w", err)
+	}
+
+	return &response, nil
+}
+
+// logPriceChange logs a recorded plan price change. The repository has either locked
+// the existing subscribers' price or, when configured, repriced them in the update.
+func logPriceChange(history *models.PlanHistory) {
+	var repriced int64
+	if history.RepricedSubscriptions != nil {
+		repriced = *history.RepricedSubscriptions
+	}
+
+	by, byID := "system", 0
+	if history.ChangedByID != nil && history.ChangedByType != nil {
+		by, byID = *history.ChangedByType, *history.ChangedByID
+	}
+	fmt.Printf("Plan price changed: Plan=%d, From=%.2f, To=%.2f, Repriced=%d, By=%s:%d, Time=%s\n",
+		history.PlanID, history.Before.Price, history.After.Price, repriced, by, byID, time.Now().Format(time.RFC3339))
+}
+
+// GetPlanHistory retrieves the chronological change log of a subscription plan
+func (s *SubscriptionPlanService) GetPlanHistory(id int) ([]*models.PlanHistory, error) {
+	// Check if plan exists
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+
+	history, err := s.repo.GetHistory(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan history: %w", err)
+	}
+
+	return history, nil
+}
+
+// newPlanHistory builds a history entry for a plan change; a change that only
+// toggles is_active is recorded as an activation/deactivation
+func newPlanHistory(before, after *models.PlanResponse, actor *models.Actor) *models.PlanHistory {
+	history := &models.PlanHistory{
+		PlanID: before.ID,
+		Action: models.PlanHistoryActionUpdate,
+		Before: before,
+		After:  after,
+	}
+
+	if before.IsActive != after.IsActive && samePlanDetails(before, after) {
+		if after.IsActive {
+			history.Action = models.PlanHistoryActionActivate
+		} else {
+			history.Action = models.PlanHistoryActionDeactivate
+		}
+	}
+
+	if actor != nil {
+		history.ChangedByID = &actor.ID
+		history.ChangedByType = &actor.Type
+	}
+
+	return history
+}
+
+// samePlanDetails reports whether two versions of a plan differ in nothing but
+// is_active. Optional fields are compared by value, since an update request
+// carries its own pointers even when it repeats the current value.
+func samePlanDetails(a, b *models.PlanResponse) bool {
+	return a.ID == b.ID &&
+		a.Name == b.Name &&
+		a.PlanType == b.PlanType &&
+		a.Price == b.Price &&
+		equalPtr(a.AnnualDiscountPercentage, b.AnnualDiscountPercentage) &&
+		a.EffectiveAnnualPrice == b.EffectiveAnnualPrice &&
+		a.MonthlyEquivalentPrice == b.MonthlyEquivalentPrice &&
+		equalPtr(a.TrialDurationDays, b.TrialDurationDays)
+}
+
+// equalPtr reports whether two optional values are both unset or both set to the same value
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // DeletePlan deletes a subscription plan
 func (s *SubscriptionPlanService) DeletePlan(id int) error {
 	// Check if plan exists
@@ -148,6 +292,82 @@ func (s *SubscriptionPlanService) DeletePlan(id int) error {
 	return nil
 }
 
+// GetDeleteImpact reports what deleting a plan would affect and whether DeletePlan
+// would allow it, without deleting anything
+func (s *SubscriptionPlanService) GetDeleteImpact(id int) (*models.PlanDeleteImpact, error) {
+	plan, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+
+	count, err := s.repo.CountActiveSubscriptions(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check active subscriptions: %w", err)
+	}
+
+	impact := &models.PlanDeleteImpact{
+		PlanID:              plan.ID,
+		PlanName:            plan.Name,
+		ActiveSubscriptions: count,
+		CanDelete:           count == 0,
+	}
+	if !impact.CanDelete {
+		impact.Reason = "plan has active subscriptions"
+	}
+
+	return impact, nil
+}
+
+// Clone creates an inactive copy of an existing subscription plan under a unique name
+func (s *SubscriptionPlanService) Clone(id int) (*models.PlanResponse, error) {
+	source, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+
+	name, err := s.uniqueCloneName(source.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &models.SubscriptionPlan{
+		Name:     name,
+		PlanType: source.PlanType,
+		Price:    source.Price,
+		IsActive: false,
+	}
+	if source.AnnualDiscountPercentage != nil {
+		discount := *source.AnnualDiscountPercentage
+		clone.AnnualDiscountPercentage = &discount
+	}
+	if source.TrialDurationDays != nil {
+		days := *source.TrialDurationDays
+		clone.TrialDurationDays = &days
+	}
+
+	if err := s.repo.Create(clone); err != nil {
+		return nil, fmt.Errorf("failed to clone plan: %w", err)
+	}
+
+	response := clone.ToResponse()
+	return &response, nil
+}
+
+// uniqueCloneName returns "<name> (copy)", or "<name> (copy N)" if that is already taken
+func (s *SubscriptionPlanService) uniqueCloneName(name string) (string, error) {
+	candidate := name + " (copy)"
+	for n := 2; ; n++ {
+		exists, err := s.repo.ExistsByName(candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check plan name: %w", err)
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s (copy %d)", name, n)
+	}
+}
+
 // validatePlanRequest validates plan creation request
 func (s *SubscriptionPlanService) validatePlanRequest(req *models.CreatePlanRequest) error {
 	// Trial plans must have trial duration
@@ -155,6 +375,10 @@ func (s *SubscriptionPlanService) validatePlanRequest(req *models.CreatePlanRequ
 		return fmt.Errorf("trial plans must have a valid trial duration")
 	}
 
+	if err := s.validateTrialDuration(req.TrialDurationDays); err != nil {
+		return err
+	}
+
 	// Non-trial plans should have a price
 	if req.PlanType != "trial" && req.Price <= 0 {
 		return fmt.Errorf("non-trial plans must have a positive price")
@@ -182,6 +406,10 @@ func (s *SubscriptionPlanService) validatePlanUpdate(plan *models.SubscriptionPl
 		return fmt.Errorf("trial plans must have a valid trial duration")
 	}
 
+	if err := s.validateTrialDuration(plan.TrialDurationDays); err != nil {
+		return err
+	}
+
 	// Non-trial plans should have a price
 	if plan.PlanType != "trial" && plan.Price <= 0 {
 		return fmt.Errorf("non-trial plans must have a positive price")
@@ -201,3 +429,11 @@ func (s *SubscriptionPlanService) validatePlanUpdate(plan *models.SubscriptionPl
 
 	return nil
 }
+
+// validateTrialDuration checks the trial duration against the configured maximum
+func (s *SubscriptionPlanService) validateTrialDuration(days *int) error {
+	if days != nil && *days > s.maxTrialDurationDays {
+		return fmt.Errorf("trial duration exceeds maximum of %d days", s.maxTrialDurationDays)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+)
+
+// ErrActiveSubscriptionExists is returned when subscribing a gamenet that already has
+// an active or trial subscription
+var ErrActiveSubscriptionExists = errors.New("gamenet already has an active subscription")
+
+// ErrPlanNotAvailable is returned when subscribing to an inactive plan
+var ErrPlanNotAvailable = errors.New("subscription plan is not available")
+
+// ErrSubscriptionNotActive is returned when cancelling a subscription that is no longer active
+var ErrSubscriptionNotActive = errors.New("subscription is not active")
+
+// SubscriptionServiceInterface defines the interface for gamenet subscription operations
+type SubscriptionServiceInterface interface {
+	Subscribe(gamenetID, planID int) (*models.SubscriptionResponse, error)
+	Cancel(subscriptionID int) error
+	GetActiveSubscription(gamenetID int) (*models.SubscriptionResponse, error)
+}
+
+// SubscriptionService subscribes gamenets to subscription plans
+type SubscriptionService struct {
+	repo     repositories.UserSubscriptionRepositoryInterface
+	planRepo repositories.SubscriptionPlanRepositoryInterface
+}
+
+// NewSubscriptionService creates a new subscription service
+func NewSubscriptionService(repo repositories.UserSubscriptionRepositoryInterface, planRepo repositories.SubscriptionPlanRepositoryInterface) *SubscriptionService {
+	return &SubscriptionService{
+		repo:     repo,
+		planRepo: planRepo,
+	}
+}
+
+// Subscribe starts a subscription of a gamenet to a plan. The subscription ends a
+// month or a year from now for monthly and annual plans, and after the plan's trial
+// duration for trials, and lock in the plan's effective price. It returns
+// ErrActiveSubscriptionExists when the gamenet already has a subscription that has
+// not ended.
+func (s *SubscriptionService) Subscribe(gamenetID, planID int) (*models.SubscriptionResponse, error) {
+	plan, err := s.planRepo.GetByID(planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	if !plan.IsActive {
+		return nil, ErrPlanNotAvailable
+	}
+
+	startedAt := time.Now()
+	expiresAt, err := plan.SubscriptionEndDate(startedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute subscription end date: %w", err)
+	}
+
+	status := models.SubscriptionStatusActive
+	if plan.PlanType == "trial" {
+		status = models.SubscriptionStatusTrial
+	}
+	// Annual subscribers pay the discounted annual price
+	price := plan.GetEffectivePrice()
+
+	subscription := &models.UserSubscription{
+		GamenetID:       gamenetID,
+		PlanID:          plan.ID,
+		SubscribedPrice: &price,
+		Status:          status,
+		StartedAt:       startedAt,
+		ExpiresAt:       &expiresAt,
+		// Trials end instead of rolling over into a paid period
+		AutoRenew: plan.PlanType != "trial",
+	}
+	if err := s.repo.Create(subscription); err != nil {
+		if errors.Is(err, repositories.ErrSubscriptionOverlap) {
+			return nil, ErrActiveSubscriptionExists
+		}
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	response := subscription.ToResponse()
+	planResponse := plan.ToResponse()
+	response.Plan = &planResponse
+	return &response, nil
+}
+
+// Cancel cancels an active or trial subscription. It returns ErrSubscriptionNotActive
+// when the subscription was already cancelled or has expired.
+func (s *SubscriptionService) Cancel(subscriptionID int) error {
+	subscription, err := s.repo.GetByID(subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if !subscription.IsActive() {
+		return ErrSubscriptionNotActive
+	}
+
+	if err := s.repo.Cancel(subscriptionID); err != nil {
+		return fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveSubscription retrieves the gamenet's current subscription with its plan.
+// It returns repositories.ErrNotFound when the gamenet has none.
+func (s *SubscriptionService) GetActiveSubscription(gamenetID int) (*models.SubscriptionResponse, error) {
+	subscription, err := s.repo.GetActiveByGamenetID(gamenetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active subscription: %w", err)
+	}
+
+	response := subscription.ToResponse()
+	plan, err := s.planRepo.GetByID(subscription.PlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %w", err)
+	}
+	planResponse := plan.ToResponse()
+	response.Plan = &planResponse
+
+	return &response, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+)
+
+// Names of the email templates used by AuthService
+const (
+	EmailTemplatePasswordReset     = "password_reset_email"
+	EmailTemplateEmailVerification = "email_verification_email"
+)
+
+// ErrTemplateNotFound is returned when neither a stored nor a built-in template exists
+var ErrTemplateNotFound = errors.New("template not found")
+
+// ErrTemplateVariableMissing is returned when a template placeholder has no value in the data
+var ErrTemplateVariableMissing = errors.New("template variable missing")
+
+// RenderedTemplate is a notification template rendered with its data
+type RenderedTemplate struct {
+	Subject     string
+	Content     string
+	HTMLContent string
+}
+
+// TemplateRenderer renders notification templates by name. Unlike the lenient
+// renderTemplateString it refuses to leave a placeholder unfilled, so a template
+// that expects data the caller does not provide fails instead of being sent as-is.
+type TemplateRenderer struct {
+	templates TemplateServiceInterface
+}
+
+// NewTemplateRenderer creates a template renderer. With a nil template service only
+// the built-in templates are used.
+func NewTemplateRenderer(templates TemplateServiceInterface) *TemplateRenderer {
+	return &TemplateRenderer{templates: templates}
+}
+
+// Render renders the named template, preferring the active stored copy. The built-in
+// default is used when the stored template is missing, inactive or cannot be rendered
+// with the data.
+func (r *TemplateRenderer) Render(ctx context.Context, name string, templateType models.NotificationType, data map[string]interface{}) (*RenderedTemplate, error) {
+	if stored := loadStoredTemplate(ctx, r.templates, name, templateType); stored != nil {
+		rendered, err := r.RenderTemplate(stored, data)
+		if err == nil {
+			return rendered, nil
+		}
+		fmt.Printf("Warning: failed to render %s template %s, using default: %v\n", templateType, name, err)
+	}
+
+	template := defaultTemplate(name, templateType)
+	if template == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	return r.RenderTemplate(template, data)
+}
+
+// RenderTemplate renders the subject, text and HTML content of a template. Values are
+// HTML-escaped in the HTML content.
+func (r *TemplateRenderer) RenderTemplate(template *models.NotificationTemplate, data map[string]interface{}) (*RenderedTemplate, error) {
+	if missing := missingTemplateVariables(data, template.Subject, template.Content, template.HTMLContent); len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateVariableMissing, strings.Join(missing, ", "))
+	}
+
+	rendered := &RenderedTemplate{
+		Subject: renderTemplateString(template.Subject, data),
+		Content: renderTemplateString(template.Content, data),
+	}
+	if template.HTMLContent != "" {
+		escaped := make(map[string]interface{}, len(data))
+		for key, value := range data {
+			escaped[key] = html.EscapeString(fmt.Sprintf("%v", value))
+		}
+		rendered.HTMLContent = renderTemplateString(template.HTMLContent, escaped)
+	}
+	return rendered, nil
+}
+
+// loadStoredTemplate returns the active stored template with the given name and type,
+// or nil when there is none or it cannot be loaded
+func loadStoredTemplate(ctx context.Context, templates TemplateServiceInterface, name string, templateType models.NotificationType) *models.NotificationTemplate {
+	if templates == nil {
+		return nil
+	}
+	stored, err := templates.GetTemplateByName(ctx, name, templateType)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to load %s template %s, using default: %v\n", templateType, name, err)
+		return nil
+	}
+	if !stored.IsActive {
+		return nil
+	}
+	return stored
+}
+
+// missingTemplateVariables returns the sorted placeholder names in strs that have no value in data
+func missingTemplateVariables(data map[string]interface{}, strs ...string) []string {
+	missing := make(map[string]bool)
+	for _, str := range strs {
+		for _, match := range templateVariableRegex.FindAllStringSubmatch(str, -1) {
+			name := strings.TrimSpace(match[1])
+			if _, exists := data[name]; !exists {
+				missing[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
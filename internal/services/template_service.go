@@ -11,6 +11,15 @@ import (
 	"github.com/gatehide/gatehide-api/internal/repositories"
 )
 
+// Names of the SMS templates used by SMSService for plain-SMS delivery
+const (
+	SMSTemplateUserCredentials    = "user_credentials_sms"
+	SMSTemplateGamenetCredentials = "gamenet_credentials_sms"
+)
+
+// templateVariableRegex matches {{variable}} placeholders
+var templateVariableRegex = regexp.MustCompile(`\{\{([^}]+)\}\}`)
+
 // TemplateService implements TemplateServiceInterface for managing notification templates
 type TemplateService struct {
 	templateRepo repositories.NotificationTemplateRepository
@@ -144,14 +153,13 @@ func (s *TemplateService) validateTemplate(template *models.NotificationTemplate
 
 // extractVariables extracts variable placeholders from template strings
 func (s *TemplateService) extractVariables(strs ...string) []string {
-	variableRegex := regexp.MustCompile(`\{\{([^}]+)\}\}`)
 	variables := make(map[string]bool)
 
 	for _, str := range strs {
 		if str == "" {
 			continue
 		}
-		matches := variableRegex.FindAllStringSubmatch(str, -1)
+		matches := templateVariableRegex.FindAllStringSubmatch(str, -1)
 		for _, match := range matches {
 			if len(match) > 1 {
 				variables[match[1]] = true
@@ -169,9 +177,13 @@ func (s *TemplateService) extractVariables(strs ...string) []string {
 
 // renderString renders a template string with provided data
 func (s *TemplateService) renderString(template string, data map[string]interface{}) (string, error) {
-	variableRegex := regexp.MustCompile(`\{\{([^}]+)\}\}`)
+	return renderTemplateString(template, data), nil
+}
 
-	result := variableRegex.ReplaceAllStringFunc(template, func(match string) string {
+// renderTemplateString replaces {{variable}} placeholders with values from data.
+// Placeholders without a matching value are left untouched.
+func renderTemplateString(template string, data map[string]interface{}) string {
+	return templateVariableRegex.ReplaceAllStringFunc(template, func(match string) string {
 		// Extract variable name
 		variableName := strings.TrimSpace(match[2 : len(match)-2])
 
@@ -183,12 +195,25 @@ func (s *TemplateService) renderString(template string, data map[string]interfac
 		// Return original match if variable not found
 		return match
 	})
-
-	return result, nil
 }
 
 // GetDefaultTemplates returns a list of default templates
 func (s *TemplateService) GetDefaultTemplates() []*models.NotificationTemplate {
+	return defaultNotificationTemplates()
+}
+
+// defaultTemplate returns the built-in default template with the given name and type, or nil
+func defaultTemplate(name string, templateType models.NotificationType) *models.NotificationTemplate {
+	for _, template := range defaultNotificationTemplates() {
+		if template.Name == name && template.Type == templateType {
+			return template
+		}
+	}
+	return nil
+}
+
+// defaultNotificationTemplates returns the built-in notification templates
+func defaultNotificationTemplates() []*models.NotificationTemplate {
 	return []*models.NotificationTemplate{
 		{
 			Name:        "welcome_email",
@@ -202,15 +227,24 @@ func (s *TemplateService) GetDefaultTemplates() []*models.NotificationTemplate {
 			UpdatedAt:   time.Now(),
 		},
 		{
-			Name:        "password_reset_email",
-			Type:        models.NotificationTypeEmail,
-			Subject:     "Password Reset - {{app_name}}",
-			Content:     "Hi {{user_name}},\n\nYou requested a password reset for your {{app_name}} account.\n\nClick the link below to reset your password:\n{{reset_link}}\n\nThis link will expire in {{expiry_hours}} hours.\n\nIf you didn't request this, please ignore this email.\n\nBest regards,\nThe {{app_name}} Team",
-			HTMLContent: "<h2>Password Reset - {{app_name}}</h2><p>Hi {{user_name}},</p><p>You requested a password reset for your {{app_name}} account.</p><p><a href=\"{{reset_link}}\">Click here to reset your password</a></p><p>This link will expire in {{expiry_hours}} hours.</p><p>If you didn't request this, please ignore this email.</p><p>Best regards,<br>The {{app_name}} Team</p>",
-			Variables:   []string{"app_name", "user_name", "reset_link", "expiry_hours"},
-			IsActive:    true,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			Name:      EmailTemplatePasswordReset,
+			Type:      models.NotificationTypeEmail,
+			Subject:   models.PasswordResetEmailSubject,
+			Content:   models.PasswordResetEmailContent,
+			Variables: []string{"app_name", "user_name", "reset_link", "expiry_hours"},
+			IsActive:  true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		{
+			Name:      EmailTemplateEmailVerification,
+			Type:      models.NotificationTypeEmail,
+			Subject:   models.EmailVerificationEmailSubject,
+			Content:   models.EmailVerificationEmailContent,
+			Variables: []string{"app_name", "user_name", "current_email", "new_email", "verification_code"},
+			IsActive:  true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
 		},
 		{
 			Name:        "login_notification_email",
@@ -243,6 +277,26 @@ func (s *TemplateService) GetDefaultTemplates() []*models.NotificationTemplate {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
+		{
+			Name:      SMSTemplateUserCredentials,
+			Type:      models.NotificationTypeSMS,
+			Subject:   models.UserCredentialsSMSSubject,
+			Content:   models.UserCredentialsSMSContent,
+			Variables: []string{"email", "password"},
+			IsActive:  true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		{
+			Name:      SMSTemplateGamenetCredentials,
+			Type:      models.NotificationTypeSMS,
+			Subject:   models.GamenetCredentialsSMSSubject,
+			Content:   models.GamenetCredentialsSMSContent,
+			Variables: []string{"email", "password"},
+			IsActive:  true,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
 		{
 			Name:      "system_notification",
 			Type:      models.NotificationTypeDatabase,
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/utils"
+)
+
+// MaxImportRows caps the number of users a single CSV import reads
+const MaxImportRows = 1000
+
+// ErrInvalidImportFile is returned when a CSV import cannot be read at all, such as
+// an empty file or a header missing a required column
+var ErrInvalidImportFile = errors.New("invalid import file")
+
+// userImportColumns are the columns a CSV import must have, in any order
+var userImportColumns = []string{"name", "mobile", "email"}
+
+// userExportColumns are the columns of a CSV export
+var userExportColumns = []string{"id", "name", "mobile", "email", "balance", "debt", "last_login_at", "created_at"}
+
+// ImportCSV creates users from a CSV with name, mobile and email columns, reading it
+// one line at a time. Each line is created on its own: lines whose email or mobile
+// already belongs to a user, including one created earlier in the same file, are
+// skipped and invalid lines are reported as errored without stopping the import.
+func (s *userService) ImportCSV(ctx context.Context, r io.Reader, gamenetID *int) (*models.UserImportResponse, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidImportFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+	columns, err := importColumnIndexes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.UserImportResponse{
+		Skipped: []models.UserImportLine{},
+		Errored: []models.UserImportLine{},
+	}
+	for rows := 0; ; rows++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			response.Errored = append(response.Errored, models.UserImportLine{Line: parseErr.StartLine, Reason: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		if rows >= MaxImportRows {
+			response.Errored = append(response.Errored, models.UserImportLine{
+				Line:   line,
+				Reason: fmt.Sprintf("import is limited to %d users, this and the following lines were not read", MaxImportRows),
+			})
+			break
+		}
+
+		skipped, reason, err := s.importUser(ctx, importRequest(record, columns), gamenetID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case skipped:
+			response.Skipped = append(response.Skipped, models.UserImportLine{Line: line, Reason: reason})
+		case reason != "":
+			response.Errored = append(response.Errored, models.UserImportLine{Line: line, Reason: reason})
+		default:
+			response.Created++
+		}
+	}
+
+	return response, nil
+}
+
+// importColumnIndexes maps each required import column to its position in the header
+func importColumnIndexes(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet exports often start with a byte order mark
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range userImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q, expected %s", ErrInvalidImportFile, name, strings.Join(userImportColumns, ","))
+		}
+	}
+	return columns, nil
+}
+
+// importRequest reads the import columns of a CSV record. Missing trailing cells are empty.
+func importRequest(record []string, columns map[string]int) *models.UserCreateRequest {
+	cell := func(name string) string {
+		if i := columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	return &models.UserCreateRequest{
+		Name:   cell("name"),
+		Email:  cell("email"),
+		Mobile: cell("mobile"),
+	}
+}
+
+// importUser validates and creates one imported user. It reports whether the line was
+// skipped as a duplicate and why the line was not imported; the error is only set
+// when a lookup failed.
+func (s *userService) importUser(ctx context.Context, req *models.UserCreateRequest, gamenetID *int) (bool, string, error) {
+	if len([]rune(req.Name)) < 2 {
+		return false, "name must be at least 2 characters", nil
+	}
+	if address, err := mail.ParseAddress(req.Email); err != nil || address.Address != req.Email {
+		return false, "invalid email address", nil
+	}
+	mobile, ok := utils.NormalizeIranianMobile(req.Mobile)
+	if !ok {
+		return false, "invalid mobile number", nil
+	}
+	req.Mobile = mobile
+
+	// Reject disposable email domains for gamenet-created accounts (admin-created accounts are trusted)
+	if gamenetID != nil {
+		if err := s.emailValidator.Validate(req.Email); err != nil {
+			return false, err.Error(), nil
+		}
+	}
+
+	if err := s.checkIdentifiersAvailable(&req.Email, &req.Mobile, 0); err != nil {
+		var taken *identifierTakenError
+		if errors.As(err, &taken) {
+			return true, err.Error(), nil
+		}
+		return false, "", err
+	}
+
+	user, randomPassword, err := newUserWithPassword(req)
+	if err != nil {
+		return false, "", err
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return false, fmt.Sprintf("failed to create user: %v", err), nil
+	}
+
+	s.setUpCreatedUser(ctx, user, randomPassword, gamenetID)
+	return false, "", nil
+}
+
+// ExportCSV writes the users as CSV, limited to the gamenet's own users when
+// gamenetID is set
+func (s *userService) ExportCSV(ctx context.Context, w io.Writer, gamenetID *int) error {
+	var users []models.User
+	var err error
+	if gamenetID != nil {
+		users, err = s.userRepo.GetAllByGamenet(*gamenetID)
+	} else {
+		users, err = s.userRepo.GetAll()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get users: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(userExportColumns); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	for _, user := range users {
+		lastLogin := ""
+		if user.LastLoginAt != nil {
+			lastLogin = user.LastLoginAt.Format(time.RFC3339)
+		}
+		record := []string{
+			strconv.Itoa(user.ID),
+			csvSafe(user.Name),
+			user.Mobile,
+			csvSafe(user.Email),
+			strconv.FormatFloat(user.Balance, 'f', -1, 64),
+			strconv.FormatFloat(user.Debt, 'f', -1, 64),
+			lastLogin,
+			user.CreatedAt.Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// csvSafe keeps spreadsheets from running a user-entered cell as a formula
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
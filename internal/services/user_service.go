@@ -2,28 +2,66 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/gatehide/gatehide-api/internal/models"
 	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/utils"
 )
 
+// Limits for the recently-active users listing
+const (
+	DefaultRecentlyActiveLimit = 20
+	MaxRecentlyActiveLimit     = 100
+)
+
+// MaxBulkActionSize caps the number of users a single bulk action can target
+const MaxBulkActionSize = 100
+
+// MaxBatchCreateSize caps the number of users a single batch create can add
+const MaxBatchCreateSize = 100
+
+// ErrBatchCreateFailed is returned with the per-row results when a batch create was
+// rolled back because at least one row failed
+var ErrBatchCreateFailed = errors.New("batch was not created because some rows failed")
+
+// ErrInvalidBatchSize is returned when a batch create is empty or larger than
+// MaxBatchCreateSize
+var ErrInvalidBatchSize = errors.New("invalid batch size")
+
+// ErrUserNotDeleted is returned when restoring a user that has not been deleted
+var ErrUserNotDeleted = errors.New("user is not deleted")
+
+// ErrIdentifierBelongsToDeletedUser is returned when creating or updating a user with an
+// email or mobile number still held by a deleted user, which has to be restored instead
+var ErrIdentifierBelongsToDeletedUser = errors.New("belongs to a deleted user; restore it")
+
+// ErrUserAlreadyLinked is returned when attaching a user to a gamenet it is already linked to
+var ErrUserAlreadyLinked = errors.New("user is already linked to this gamenet")
+
+// ErrUserNotLinked is returned when detaching a user from a gamenet it is not linked to
+var ErrUserNotLinked = errors.New("user is not linked to this gamenet")
+
 // userService implements UserServiceInterface
 type userService struct {
 	userRepo       repositories.UserRepository
 	permissionRepo repositories.PermissionRepositoryInterface
-	smsService     *SMSService
+	smsService     SMSServiceInterface
 	emailService   *EmailService
+	emailValidator *utils.EmailDomainValidator
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repositories.UserRepository, permissionRepo repositories.PermissionRepositoryInterface, smsService *SMSService, emailService *EmailService) UserServiceInterface {
+func NewUserService(userRepo repositories.UserRepository, permissionRepo repositories.PermissionRepositoryInterface, smsService SMSServiceInterface, emailService *EmailService, emailValidator *utils.EmailDomainValidator) UserServiceInterface {
 	return &userService{
 		userRepo:       userRepo,
 		permissionRepo: permissionRepo,
 		smsService:     smsService,
 		emailService:   emailService,
+		emailValidator: emailValidator,
 	}
 }
 
@@ -92,44 +130,60 @@ func (s *userService) GetByMobile(ctx context.Context, mobile string) (*models.U
 
 // Create creates a new user
 func (s *userService) Create(ctx context.Context, req *models.UserCreateRequest, gamenetID *int) (*models.UserResponse, error) {
-	// Check if user with email already exists
-	existingUser, err := s.userRepo.GetByEmail(req.Email)
-	if err == nil && existingUser != nil {
-		return nil, fmt.Errorf("user with this email already exists")
+	// Reject disposable email domains for gamenet-created accounts (admin-created accounts are trusted)
+	if gamenetID != nil {
+		if err := s.emailValidator.Validate(req.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkIdentifiersAvailable(&req.Email, &req.Mobile, 0); err != nil {
+		return nil, err
 	}
 
-	// Check if user with mobile already exists
-	existingUser, err = s.userRepo.GetByMobile(req.Mobile)
-	if err == nil && existingUser != nil {
-		return nil, fmt.Errorf("user with this mobile number already exists")
+	user, randomPassword, err := newUserWithPassword(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepo.Create(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Generate random 8-digit password
+	s.setUpCreatedUser(ctx, user, randomPassword, gamenetID)
+
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// newUserWithPassword builds a user from a create request with a random 8-digit
+// password, returned in plain text so it can be sent to the user
+func newUserWithPassword(req *models.UserCreateRequest) (*models.User, string, error) {
 	randomPassword, err := utils.GenerateRandomPassword()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate password: %w", err)
+		return nil, "", fmt.Errorf("failed to generate password: %w", err)
 	}
 
-	// Hash the password
 	hashedPassword, err := models.HashPassword(randomPassword)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %w", err)
+		return nil, "", fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	user := &models.User{
+	return &models.User{
 		Name:     req.Name,
 		Email:    req.Email,
 		Mobile:   req.Mobile,
-		Password: hashedPassword,
-	}
-
-	err = s.userRepo.Create(user)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
-	}
+		Password: models.PasswordHash(hashedPassword),
+	}, randomPassword, nil
+}
 
+// setUpCreatedUser assigns the user role to a newly created user, links it to the
+// creating gamenet and sends its credentials. Failures are logged, not returned, so
+// they never undo the creation.
+func (s *userService) setUpCreatedUser(ctx context.Context, user *models.User, randomPassword string, gamenetID *int) {
 	// Assign user role to the newly created user
-	err = s.permissionRepo.AssignRoleToUser(user.ID, "user", "user")
+	err := s.permissionRepo.AssignRoleToUser(user.ID, "user", "user")
 	if err != nil {
 		// Log error but don't fail creation
 		fmt.Printf("Warning: Failed to assign user role to user %d: %v\n", user.ID, err)
@@ -144,19 +198,166 @@ func (s *userService) Create(ctx context.Context, req *models.UserCreateRequest,
 		}
 	}
 
-	// Send credentials via SMS using Kavenegar Verify Lookup
-	if s.smsService != nil {
-		err = s.smsService.SendUserCredentials(ctx, req.Mobile, req.Email, randomPassword)
+	s.sendCredentials(ctx, user, randomPassword)
+}
+
+// sendCredentials sends a newly created user its password by SMS using Kavenegar
+// Verify Lookup. Failures are logged, not returned.
+func (s *userService) sendCredentials(ctx context.Context, user *models.User, randomPassword string) {
+	if s.smsService == nil {
+		return
+	}
+
+	err := s.smsService.SendUserCredentials(ctx, user.Mobile, user.Email, randomPassword)
+	if err != nil && !errors.Is(err, ErrSMSDisabled) {
+		// Log the error but don't fail the creation
+		fmt.Printf("Warning: Failed to send credentials SMS to %s: %v\n", user.Mobile, err)
+	} else if err == nil {
+		fmt.Printf("Successfully sent credentials SMS to %s\n", user.Mobile)
+	}
+}
+
+// CreateBatch creates many users at once. Every row is validated first, then all users
+// are inserted, given the user role and linked to the gamenet in a single transaction:
+// either all of them are created or none are. Credentials are only sent once the
+// transaction has committed. When any row fails the per-row results are returned with
+// ErrBatchCreateFailed.
+func (s *userService) CreateBatch(ctx context.Context, reqs []*models.UserCreateRequest, gamenetID *int) (*models.UserBatchCreateResponse, error) {
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("%w: at least one user is required", ErrInvalidBatchSize)
+	}
+	if len(reqs) > MaxBatchCreateSize {
+		return nil, fmt.Errorf("%w: cannot create more than %d users at once", ErrInvalidBatchSize, MaxBatchCreateSize)
+	}
+
+	response := &models.UserBatchCreateResponse{Results: make([]models.UserBatchCreateResult, len(reqs))}
+	failed := false
+	emails := make(map[string]int, len(reqs))
+	mobiles := make(map[string]int, len(reqs))
+	for i, req := range reqs {
+		response.Results[i].Index = i
+		rowErr, err := s.validateBatchRow(req, gamenetID, emails, mobiles, i)
 		if err != nil {
-			// Log the error but don't fail the creation
-			fmt.Printf("Warning: Failed to send credentials SMS to %s: %v\n", req.Mobile, err)
-		} else {
-			fmt.Printf("Successfully sent credentials SMS to %s\n", req.Mobile)
+			return nil, err
 		}
+		if rowErr != "" {
+			response.Results[i].Error = rowErr
+			failed = true
+		}
+	}
+	if failed {
+		return response.MarkRolledBack(), ErrBatchCreateFailed
 	}
 
-	response := user.ToResponse()
-	return &response, nil
+	users := make([]*models.User, len(reqs))
+	passwords := make([]string, len(reqs))
+	for i, req := range reqs {
+		user, password, err := newUserWithPassword(req)
+		if err != nil {
+			return nil, err
+		}
+		users[i] = user
+		passwords[i] = password
+	}
+
+	if err := s.userRepo.CreateBatch(users, gamenetID); err != nil {
+		var rowErr *repositories.BatchRowError
+		if !errors.As(err, &rowErr) || rowErr.Index >= len(reqs) {
+			return nil, fmt.Errorf("failed to create users: %w", err)
+		}
+		response.Results[rowErr.Index].Error = rowErr.Err.Error()
+		return response.MarkRolledBack(), ErrBatchCreateFailed
+	}
+
+	for i, user := range users {
+		s.sendCredentials(ctx, user, passwords[i])
+
+		userResponse := user.ToResponse()
+		response.Results[i].Success = true
+		response.Results[i].User = &userResponse
+	}
+	response.Created = len(users)
+
+	return response, nil
+}
+
+// validateBatchRow checks one row of a batch create the way Create checks a single
+// user, and also rejects emails and mobiles repeated within the batch. It returns the
+// row's error message, or an error when the check itself failed.
+func (s *userService) validateBatchRow(req *models.UserCreateRequest, gamenetID *int, emails, mobiles map[string]int, index int) (string, error) {
+	// Reject disposable email domains for gamenet-created accounts (admin-created accounts are trusted)
+	if gamenetID != nil {
+		if err := s.emailValidator.Validate(req.Email); err != nil {
+			return err.Error(), nil
+		}
+	}
+
+	if first, ok := emails[strings.ToLower(req.Email)]; ok {
+		return fmt.Sprintf("email is already used by row %d", first), nil
+	}
+	emails[strings.ToLower(req.Email)] = index
+	if first, ok := mobiles[req.Mobile]; ok {
+		return fmt.Sprintf("mobile number is already used by row %d", first), nil
+	}
+	mobiles[req.Mobile] = index
+
+	if err := s.checkIdentifiersAvailable(&req.Email, &req.Mobile, 0); err != nil {
+		var taken *identifierTakenError
+		if errors.As(err, &taken) {
+			return err.Error(), nil
+		}
+		return "", err
+	}
+
+	return "", nil
+}
+
+// identifierTakenError reports an email or mobile number already held by another user.
+// It wraps ErrIdentifierBelongsToDeletedUser when that user has been deleted.
+type identifierTakenError struct {
+	field   string
+	deleted bool
+}
+
+func (e *identifierTakenError) Error() string {
+	if e.deleted {
+		return fmt.Sprintf("%s %s", e.field, ErrIdentifierBelongsToDeletedUser)
+	}
+	return fmt.Sprintf("user with this %s already exists", e.field)
+}
+
+func (e *identifierTakenError) Unwrap() error {
+	if e.deleted {
+		return ErrIdentifierBelongsToDeletedUser
+	}
+	return nil
+}
+
+// checkIdentifiersAvailable checks that the given email and mobile number, when set, are
+// not held by any user other than excludeID, including deleted users, whose rows keep
+// their identifiers so they can be restored
+func (s *userService) checkIdentifiersAvailable(email, mobile *string, excludeID int) error {
+	if email != nil {
+		existingUser, err := s.userRepo.GetByEmailIncludingDeleted(*email)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("failed to check email: %w", err)
+		}
+		if err == nil && existingUser.ID != excludeID {
+			return &identifierTakenError{field: "email", deleted: existingUser.IsDeleted()}
+		}
+	}
+
+	if mobile != nil {
+		existingUser, err := s.userRepo.GetByMobileIncludingDeleted(*mobile)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("failed to check mobile: %w", err)
+		}
+		if err == nil && existingUser.ID != excludeID {
+			return &identifierTakenError{field: "mobile number", deleted: existingUser.IsDeleted()}
+		}
+	}
+
+	return nil
 }
 
 // Update updates an existing user
@@ -167,20 +368,9 @@ func (s *userService) Update(ctx context.Context, id int, req *models.UserUpdate
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	// If email is being updated, check if it's already taken by another user
-	if req.Email != nil {
-		existingUser, err := s.userRepo.GetByEmail(*req.Email)
-		if err == nil && existingUser != nil && existingUser.ID != id {
-			return nil, fmt.Errorf("user with this email already exists")
-		}
-	}
-
-	// If mobile is being updated, check if it's already taken by another user
-	if req.Mobile != nil {
-		existingUser, err := s.userRepo.GetByMobile(*req.Mobile)
-		if err == nil && existingUser != nil && existingUser.ID != id {
-			return nil, fmt.Errorf("user with this mobile number already exists")
-		}
+	// If the email or mobile is being updated, check it isn't taken by another user
+	if err := s.checkIdentifiersAvailable(req.Email, req.Mobile, id); err != nil {
+		return nil, err
 	}
 
 	err = s.userRepo.Update(id, req)
@@ -198,7 +388,7 @@ func (s *userService) Update(ctx context.Context, id int, req *models.UserUpdate
 	return &response, nil
 }
 
-// Delete deletes a user
+// Delete soft-deletes a user; it can be brought back with Restore
 func (s *userService) Delete(ctx context.Context, id int) error {
 	// Check if user exists
 	_, err := s.userRepo.GetByID(id)
@@ -214,6 +404,25 @@ func (s *userService) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// Restore brings back a soft-deleted user
+func (s *userService) Restore(ctx context.Context, id int) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetByIDIncludingDeleted(id)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if !user.IsDeleted() {
+		return nil, ErrUserNotDeleted
+	}
+
+	if err := s.userRepo.Restore(id); err != nil {
+		return nil, fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	user.DeletedAt = nil
+	response := user.ToResponse()
+	return &response, nil
+}
+
 // Search searches users with pagination
 func (s *userService) Search(ctx context.Context, req *models.UserSearchRequest) (*models.UserSearchResponse, error) {
 	result, err := s.userRepo.Search(req)
@@ -234,7 +443,69 @@ func (s *userService) SearchByGamenet(ctx context.Context, req *models.UserSearc
 	return result, nil
 }
 
-// AttachToGamenet attaches a user to a gamenet
+// GetRecentlyActive retrieves users who logged in since the given time, capped at MaxRecentlyActiveLimit
+func (s *userService) GetRecentlyActive(ctx context.Context, since time.Time, limit int) ([]models.RecentlyActiveUser, error) {
+	if limit <= 0 {
+		limit = DefaultRecentlyActiveLimit
+	}
+	if limit > MaxRecentlyActiveLimit {
+		limit = MaxRecentlyActiveLimit
+	}
+
+	users, err := s.userRepo.GetRecentlyActive(since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recently active users: %w", err)
+	}
+
+	return users, nil
+}
+
+// BulkAction applies a delete, suspend or reactivate action to many users at once and
+// reports the outcome for each user
+func (s *userService) BulkAction(ctx context.Context, req *models.UserBulkActionRequest, actor *models.Actor) (*models.UserBulkActionResponse, error) {
+	// Drop duplicate IDs so each user is processed once
+	ids := make([]int, 0, len(req.UserIDs))
+	seen := make(map[int]bool, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one user ID is required")
+	}
+	if len(ids) > MaxBulkActionSize {
+		return nil, fmt.Errorf("cannot process more than %d users at once", MaxBulkActionSize)
+	}
+
+	results, err := s.userRepo.ApplyBulkAction(req.Action, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply bulk action: %w", err)
+	}
+
+	response := &models.UserBulkActionResponse{
+		Action:  req.Action,
+		Results: results,
+	}
+	for _, result := range results {
+		if result.Success {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+
+	// Log the action for security auditing
+	fmt.Printf("Bulk user action: Action=%s, By=%s:%d, Users=%v, Succeeded=%d, Failed=%d, Time=%s\n",
+		req.Action, actor.Type, actor.ID, ids, response.Succeeded, response.Failed, time.Now().Format(time.RFC3339))
+
+	return response, nil
+}
+
+// AttachToGamenet attaches a user to a gamenet. It returns ErrUserAlreadyLinked when
+// the user is already linked to it.
 func (s *userService) AttachToGamenet(ctx context.Context, userID, gamenetID int) error {
 	// Check if user exists
 	_, err := s.userRepo.GetByID(userID)
@@ -242,6 +513,14 @@ func (s *userService) AttachToGamenet(ctx context.Context, userID, gamenetID int
 		return fmt.Errorf("user not found: %w", err)
 	}
 
+	linked, err := s.userRepo.IsLinkedToGamenet(userID, gamenetID)
+	if err != nil {
+		return err
+	}
+	if linked {
+		return ErrUserAlreadyLinked
+	}
+
 	err = s.userRepo.LinkToGamenet(userID, gamenetID)
 	if err != nil {
 		return fmt.Errorf("failed to attach user to gamenet: %w", err)
@@ -250,7 +529,8 @@ func (s *userService) AttachToGamenet(ctx context.Context, userID, gamenetID int
 	return nil
 }
 
-// DetachFromGamenet detaches a user from a gamenet
+// DetachFromGamenet detaches a user from a gamenet. It returns ErrUserNotLinked when
+// the user is not linked to it.
 func (s *userService) DetachFromGamenet(ctx context.Context, userID, gamenetID int) error {
 	// Check if user exists
 	_, err := s.userRepo.GetByID(userID)
@@ -258,6 +538,14 @@ func (s *userService) DetachFromGamenet(ctx context.Context, userID, gamenetID i
 		return fmt.Errorf("user not found: %w", err)
 	}
 
+	linked, err := s.userRepo.IsLinkedToGamenet(userID, gamenetID)
+	if err != nil {
+		return err
+	}
+	if !linked {
+		return ErrUserNotLinked
+	}
+
 	err = s.userRepo.UnlinkFromGamenet(userID, gamenetID)
 	if err != nil {
 		return fmt.Errorf("failed to detach user from gamenet: %w", err)
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/gatehide/gatehide-api/internal/models"
 )
@@ -14,12 +16,18 @@ type UserServiceInterface interface {
 	GetByEmail(ctx context.Context, email string) (*models.UserResponse, error)
 	GetByMobile(ctx context.Context, mobile string) (*models.UserResponse, error)
 	Create(ctx context.Context, req *models.UserCreateRequest, gamenetID *int) (*models.UserResponse, error)
+	CreateBatch(ctx context.Context, reqs []*models.UserCreateRequest, gamenetID *int) (*models.UserBatchCreateResponse, error)
+	ImportCSV(ctx context.Context, r io.Reader, gamenetID *int) (*models.UserImportResponse, error)
+	ExportCSV(ctx context.Context, w io.Writer, gamenetID *int) error
 	Update(ctx context.Context, id int, req *models.UserUpdateRequest) (*models.UserResponse, error)
 	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) (*models.UserResponse, error)
 	Search(ctx context.Context, req *models.UserSearchRequest) (*models.UserSearchResponse, error)
 	SearchByGamenet(ctx context.Context, req *models.UserSearchRequest, gamenetID int) (*models.UserSearchResponse, error)
 	AttachToGamenet(ctx context.Context, userID, gamenetID int) error
 	DetachFromGamenet(ctx context.Context, userID, gamenetID int) error
 	CanModifyUser(ctx context.Context, userID, requesterID int, requesterType string) (bool, error)
 	ResendCredentials(ctx context.Context, id int) error
+	GetRecentlyActive(ctx context.Context, since time.Time, limit int) ([]models.RecentlyActiveUser, error)
+	BulkAction(ctx context.Context, req *models.UserBulkActionRequest, actor *models.Actor) (*models.UserBulkActionResponse, error)
 }
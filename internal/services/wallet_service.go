@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+)
+
+// ErrInvalidWalletAmount is returned when a credit or debit amount is not a positive
+// amount of at most two decimals
+var ErrInvalidWalletAmount = errors.New("amount must be a positive number with at most two decimals")
+
+// ErrWalletReasonRequired is returned when a credit or debit has no reason
+var ErrWalletReasonRequired = errors.New("a reason is required")
+
+// ErrInvalidWalletFilter is returned when a wallet transaction listing has an invalid filter
+var ErrInvalidWalletFilter = errors.New("invalid wallet transaction filter")
+
+// maxWalletReasonLength is the size of the wallet_transactions.reason column
+const maxWalletReasonLength = 255
+
+// WalletServiceInterface defines the interface for wallet business logic
+type WalletServiceInterface interface {
+	Credit(ctx context.Context, userID int, amount float64, reason string) (*models.WalletTransaction, error)
+	Debit(ctx context.Context, userID int, amount float64, reason string, allowDebt bool) (*models.WalletTransaction, error)
+	GetBalance(ctx context.Context, userID int) (*models.WalletBalance, error)
+	ListTransactions(ctx context.Context, userID int, filter *models.WalletTransactionFilter) (*models.WalletTransactionListResponse, error)
+}
+
+// WalletService implements WalletServiceInterface
+type WalletService struct {
+	walletRepo repositories.WalletRepositoryInterface
+}
+
+// NewWalletService creates a new wallet service
+func NewWalletService(walletRepo repositories.WalletRepositoryInterface) WalletServiceInterface {
+	return &WalletService{walletRepo: walletRepo}
+}
+
+// Credit adds amount to a user's wallet, paying off any debt first
+func (s *WalletService) Credit(ctx context.Context, userID int, amount float64, reason string) (*models.WalletTransaction, error) {
+	return s.apply(userID, models.WalletTransactionCredit, amount, reason, false)
+}
+
+// Debit takes amount from a user's wallet. It fails with models.ErrInsufficientBalance
+// when the balance does not cover it, unless allowDebt lets the shortfall become debt.
+func (s *WalletService) Debit(ctx context.Context, userID int, amount float64, reason string, allowDebt bool) (*models.WalletTransaction, error) {
+	return s.apply(userID, models.WalletTransactionDebit, amount, reason, allowDebt)
+}
+
+// GetBalance returns a user's balance and debt
+func (s *WalletService) GetBalance(ctx context.Context, userID int) (*models.WalletBalance, error) {
+	wallet, err := s.walletRepo.GetBalance(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet balance: %w", err)
+	}
+	return wallet, nil
+}
+
+// ListTransactions retrieves a paginated page of a user's wallet transactions, newest first
+func (s *WalletService) ListTransactions(ctx context.Context, userID int, filter *models.WalletTransactionFilter) (*models.WalletTransactionListResponse, error) {
+	if filter.Type != "" && filter.Type != models.WalletTransactionCredit && filter.Type != models.WalletTransactionDebit {
+		return nil, fmt.Errorf("%w: type must be credit or debit", ErrInvalidWalletFilter)
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidWalletFilter)
+	}
+
+	result, err := s.walletRepo.ListTransactions(userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list wallet transactions: %w", err)
+	}
+	return result, nil
+}
+
+// apply validates and records a wallet transaction
+func (s *WalletService) apply(userID int, txType models.WalletTransactionType, amount float64, reason string, allowDebt bool) (*models.WalletTransaction, error) {
+	// The columns store two decimals, so anything finer would be silently rounded
+	if amount <= 0 || math.Abs(amount*100-math.Round(amount*100)) > 1e-6 {
+		return nil, ErrInvalidWalletAmount
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrWalletReasonRequired
+	}
+	if len([]rune(reason)) > maxWalletReasonLength {
+		return nil, fmt.Errorf("reason must not be longer than %d characters", maxWalletReasonLength)
+	}
+
+	transaction, err := s.walletRepo.ApplyTransaction(userID, txType, amount, reason, allowDebt)
+	if err != nil {
+		if errors.Is(err, models.ErrInsufficientBalance) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to %s wallet: %w", txType, err)
+	}
+
+	fmt.Printf("Wallet: %s of %.2f for user %d (%s), balance=%.2f, debt=%.2f, Time=%s\n",
+		txType, amount, userID, reason, transaction.BalanceAfter, transaction.DebtAfter, time.Now().Format(time.RFC3339))
+	return transaction, nil
+}
@@ -0,0 +1,181 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// AttemptLimiter counts failed attempts per key over a fixed window. Once a key
+// reaches the maximum it is blocked until its window expires, or for the lockout
+// duration when one is set. Unlike TokenBucket, callers over the limit are rejected
+// rather than delayed.
+//
+// Counts are kept in memory, so they are per instance and lost on restart; behind a
+// load balancer each instance allows maxAttempts of its own. Expired keys are swept
+// at most once per window so keys that never come back don't accumulate.
+type AttemptLimiter struct {
+	mu          sync.Mutex
+	maxAttempts int
+	window      time.Duration
+	lockout     time.Duration
+	attempts    map[string]*attemptWindow
+	nextSweep   time.Time
+}
+
+// attemptWindow tracks the failures recorded for one key
+type attemptWindow struct {
+	count     int
+	windowEnd time.Time // end of the counting window, before any lockout
+	resetAt   time.Time
+}
+
+// NewAttemptLimiter creates a limiter allowing maxAttempts failures per key within
+// window. A non-positive maxAttempts disables limiting.
+func NewAttemptLimiter(maxAttempts int, window time.Duration) *AttemptLimiter {
+	return &AttemptLimiter{
+		maxAttempts: maxAttempts,
+		window:      window,
+		attempts:    make(map[string]*attemptWindow),
+	}
+}
+
+// NewAttemptLimiterWithLockout creates a limiter allowing maxAttempts failures per key
+// within window; a key reaching the maximum is then blocked for lockout from its last
+// failure. A non-positive maxAttempts disables limiting.
+func NewAttemptLimiterWithLockout(maxAttempts int, window, lockout time.Duration) *AttemptLimiter {
+	limiter := NewAttemptLimiter(maxAttempts, window)
+	limiter.lockout = lockout
+	return limiter
+}
+
+// Allow reports whether the key may make another attempt. When it may not, it also
+// returns how long until the key is unblocked.
+func (l *AttemptLimiter) Allow(key string) (bool, time.Duration) {
+	if l.maxAttempts <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := l.current(key, time.Now())
+	if entry == nil || entry.count < l.maxAttempts {
+		return true, 0
+	}
+	return false, time.Until(entry.resetAt)
+}
+
+// Reserve checks whether the key may make another attempt and, if it may, counts the
+// attempt as failed in the same step, so concurrent callers can't all pass the check
+// before any failure is recorded. Call Reset when the attempt succeeds, or Release when
+// it ends without the secret being checked. When the key may not attempt, Reserve also
+// returns how long until it is unblocked.
+func (l *AttemptLimiter) Reserve(key string) (bool, time.Duration) {
+	if l.maxAttempts <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if entry := l.current(key, now); entry != nil && entry.count >= l.maxAttempts {
+		return false, entry.resetAt.Sub(now)
+	}
+	l.record(key, now)
+	return true, 0
+}
+
+// Release gives back an attempt taken by Reserve that neither succeeded nor failed,
+// e.g. because the account could not be looked up
+func (l *AttemptLimiter) Release(key string) {
+	if l.maxAttempts <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := l.current(key, time.Now())
+	if entry == nil {
+		return
+	}
+	entry.count--
+	if entry.count <= 0 {
+		delete(l.attempts, key)
+		return
+	}
+	if entry.count < l.maxAttempts {
+		entry.resetAt = entry.windowEnd
+	}
+}
+
+// Fail records a failed attempt for the key
+func (l *AttemptLimiter) Fail(key string) {
+	if l.maxAttempts <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.record(key, time.Now())
+}
+
+// Len returns the number of keys with failures still being counted
+func (l *AttemptLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(time.Now())
+	return len(l.attempts)
+}
+
+// record counts a failed attempt for the key; the caller must hold the lock
+func (l *AttemptLimiter) record(key string, now time.Time) {
+	l.sweep(now)
+
+	entry := l.current(key, now)
+	if entry == nil {
+		entry = &attemptWindow{windowEnd: now.Add(l.window), resetAt: now.Add(l.window)}
+		l.attempts[key] = entry
+	}
+	entry.count++
+	if entry.count >= l.maxAttempts && l.lockout > 0 {
+		entry.resetAt = now.Add(l.lockout)
+	}
+}
+
+// sweep drops every expired key, at most once per window; the caller must hold the lock
+func (l *AttemptLimiter) sweep(now time.Time) {
+	if now.Before(l.nextSweep) {
+		return
+	}
+	for key, entry := range l.attempts {
+		if !now.Before(entry.resetAt) {
+			delete(l.attempts, key)
+		}
+	}
+	l.nextSweep = now.Add(l.window)
+}
+
+// Reset clears the failures recorded for the key, e.g. after a successful attempt
+func (l *AttemptLimiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.attempts, key)
+}
+
+// current returns the key's window, dropping it if it has expired
+func (l *AttemptLimiter) current(key string, now time.Time) *attemptWindow {
+	entry, ok := l.attempts[key]
+	if !ok {
+		return nil
+	}
+	if !now.Before(entry.resetAt) {
+		delete(l.attempts, key)
+		return nil
+	}
+	return entry
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryBackoff computes the delay before each retry: Base doubled for every earlier
+// retry, capped at Max (a zero Max means no cap). Half of each delay is random jitter
+// so that clients failing together don't retry in lockstep.
+type RetryBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay returns the delay before the given retry, counting from 1
+func (b RetryBackoff) Delay(retry int) time.Duration {
+	if b.Base <= 0 || retry < 1 {
+		return 0
+	}
+
+	delay := b.Base
+	for i := 1; i < retry && (b.Max <= 0 || delay < b.Max); i++ {
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// SleepContext waits for d, returning the context's error early if it ends first
+func SleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+)
+
+// ErrDisposableEmail is returned when an email belongs to a disposable email provider
+var ErrDisposableEmail = errors.New("disposable email addresses are not allowed")
+
+// EmailDomainValidator rejects emails from disposable/temporary email domains
+type EmailDomainValidator struct {
+	config  *config.EmailPolicyConfig
+	mu      sync.RWMutex
+	domains map[string]struct{}
+	modTime time.Time
+}
+
+// NewEmailDomainValidator creates a new email domain validator
+func NewEmailDomainValidator(cfg *config.EmailPolicyConfig) *EmailDomainValidator {
+	v := &EmailDomainValidator{
+		config:  cfg,
+		domains: make(map[string]struct{}),
+	}
+
+	if cfg.BlockDisposable {
+		if err := v.Reload(); err != nil {
+			fmt.Printf("Warning: failed to load disposable email domains: %v\n", err)
+		}
+	}
+
+	return v
+}
+
+// Enabled reports whether disposable email checks are turned on
+func (v *EmailDomainValidator) Enabled() bool {
+	return v != nil && v.config.BlockDisposable
+}
+
+// Validate returns ErrDisposableEmail if the email's domain is on the disposable list
+func (v *EmailDomainValidator) Validate(email string) error {
+	if !v.Enabled() {
+		return nil
+	}
+
+	// Pick up edits to the domain list without a restart
+	v.reloadIfChanged()
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	// Match the domain itself and any parent domain (e.g. mx.mailinator.com)
+	for {
+		if _, ok := v.domains[domain]; ok {
+			return ErrDisposableEmail
+		}
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return nil
+		}
+		domain = domain[dot+1:]
+	}
+}
+
+// Reload reads the domain list from the configured file
+func (v *EmailDomainValidator) Reload() error {
+	file, err := os.Open(v.config.DisposableDomainsFile)
+	if err != nil {
+		return fmt.Errorf("failed to open disposable domains file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat disposable domains file: %w", err)
+	}
+
+	domains := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains[line] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read disposable domains file: %w", err)
+	}
+
+	v.mu.Lock()
+	v.domains = domains
+	v.modTime = info.ModTime()
+	v.mu.Unlock()
+
+	return nil
+}
+
+// reloadIfChanged reloads the domain list when the file has been modified
+func (v *EmailDomainValidator) reloadIfChanged() {
+	info, err := os.Stat(v.config.DisposableDomainsFile)
+	if err != nil {
+		return
+	}
+
+	v.mu.RLock()
+	changed := !info.ModTime().Equal(v.modTime)
+	v.mu.RUnlock()
+
+	if changed {
+		if err := v.Reload(); err != nil {
+			fmt.Printf("Warning: failed to reload disposable email domains: %v\n", err)
+		}
+	}
+}
@@ -52,9 +52,7 @@ func (fu *FileUploader) UploadFile(file *multipart.FileHeader, subfolder string)
 	}
 
 	// Generate unique filename
-	ext := filepath.Ext(file.Filename)
-	baseName := strings.TrimSuffix(file.Filename, ext)
-	uniqueName := fmt.Sprintf("%s_%d%s", baseName, time.Now().UnixNano(), ext)
+	uniqueName := fu.uniqueFileName(file.Filename)
 	filePath := filepath.Join(uploadDir, uniqueName)
 
 	// Open uploaded file
@@ -88,6 +86,36 @@ func (fu *FileUploader) UploadFile(file *multipart.FileHeader, subfolder string)
 	}, nil
 }
 
+// OpenFile checks an uploaded file's size and extension and opens it for reading
+// without storing it. allowedTypes overrides the configured types when given.
+func (fu *FileUploader) OpenFile(file *multipart.FileHeader, allowedTypes ...string) (multipart.File, error) {
+	// Validate file size
+	if file.Size > fu.config.MaxFileSize {
+		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", fu.config.MaxFileSize)
+	}
+
+	// Validate file type
+	if len(allowedTypes) == 0 {
+		allowedTypes = fu.config.AllowedTypes
+	}
+	if !hasAllowedExtension(file.Filename, allowedTypes) {
+		return nil, fmt.Errorf("file type not allowed. Allowed types: %v", allowedTypes)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	return src, nil
+}
+
+// uniqueFileName appends a timestamp to a file name to avoid collisions
+func (fu *FileUploader) uniqueFileName(filename string) string {
+	ext := filepath.Ext(filename)
+	baseName := strings.TrimSuffix(filename, ext)
+	return fmt.Sprintf("%s_%d%s", baseName, time.Now().UnixNano(), ext)
+}
+
 // DeleteFile deletes a file from the filesystem
 func (fu *FileUploader) DeleteFile(filePath string) error {
 	if err := os.Remove(filePath); err != nil {
@@ -98,8 +126,13 @@ func (fu *FileUploader) DeleteFile(filePath string) error {
 
 // isAllowedFileType checks if the file type is allowed
 func (fu *FileUploader) isAllowedFileType(filename string) bool {
+	return hasAllowedExtension(filename, fu.config.AllowedTypes)
+}
+
+// hasAllowedExtension checks if the file name ends in one of the allowed extensions
+func hasAllowedExtension(filename string, allowedTypes []string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
-	for _, allowedType := range fu.config.AllowedTypes {
+	for _, allowedType := range allowedTypes {
 		if ext == allowedType {
 			return true
 		}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FrontendLinks builds links to pages of the frontend app, such as those sent in emails
+type FrontendLinks struct {
+	baseURL string
+}
+
+// NewFrontendLinks creates a link builder for the frontend at baseURL. A trailing
+// slash is ignored.
+func NewFrontendLinks(baseURL string) *FrontendLinks {
+	return &FrontendLinks{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// ResetPassword returns the link to the password reset page for a token
+func (l *FrontendLinks) ResetPassword(token, email string) string {
+	return l.baseURL + "/reset-password?token=" + url.QueryEscape(token) + "&email=" + url.QueryEscape(email)
+}
+
+// Unsubscribe returns the link to unsubscribe an email address from notifications.
+// Without an email it links to the unsubscribe page itself.
+func (l *FrontendLinks) Unsubscribe(email string) string {
+	if email == "" {
+		return l.baseURL + "/unsubscribe"
+	}
+	return l.baseURL + "/unsubscribe?email=" + url.QueryEscape(email)
+}
+
+// Support returns the link to the support page
+func (l *FrontendLinks) Support() string {
+	return l.baseURL + "/support"
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrImageTooSmall is returned when an uploaded image is below the minimum dimension
+var ErrImageTooSmall = errors.New("image is too small")
+
+// ErrImageTooLarge is returned when an uploaded image declares more pixels than the configured maximum
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// ErrUnsupportedImage is returned when an upload cannot be decoded as a JPEG, PNG or GIF image
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// jpegQuality is the quality used when re-encoding resized JPEG images
+const jpegQuality = 90
+
+// ImageUploadResult represents a stored image and its thumbnail variant
+type ImageUploadResult struct {
+	Image     *FileUploadResult
+	Thumbnail *FileUploadResult // nil when thumbnails are disabled
+	Width     int
+	Height    int
+}
+
+// UploadImage decodes an uploaded image, rejects it if it declares more pixels than the
+// configured maximum or is below the configured minimum dimension, scales it down to fit the configured maximum and stores it together with a
+// thumbnail variant. The original format (JPEG, PNG or GIF) is preserved.
+func (fu *FileUploader) UploadImage(file *multipart.FileHeader, subfolder string) (*ImageUploadResult, error) {
+	// Validate file size
+	if file.Size > fu.config.MaxFileSize {
+		return nil, fmt.Errorf("file size exceeds maximum allowed size of %d bytes", fu.config.MaxFileSize)
+	}
+
+	// Validate file type
+	if !fu.isAllowedFileType(file.Filename) {
+		return nil, fmt.Errorf("file type not allowed. Allowed types: %v", fu.config.AllowedTypes)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer src.Close()
+
+	cfg := fu.config.Images
+
+	// Check the dimensions from the header before decoding, since the decoded bitmap
+	// can be far larger than the compressed file
+	header, _, err := image.DecodeConfig(io.LimitReader(src, fu.config.MaxFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if cfg.MaxPixels > 0 && int64(header.Width)*int64(header.Height) > int64(cfg.MaxPixels) {
+		return nil, fmt.Errorf("%w: %dx%d, maximum is %d pixels",
+			ErrImageTooLarge, header.Width, header.Height, cfg.MaxPixels)
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+
+	img, format, err := image.Decode(io.LimitReader(src, fu.config.MaxFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	bounds := img.Bounds()
+	if cfg.MinDimension > 0 && (bounds.Dx() < cfg.MinDimension || bounds.Dy() < cfg.MinDimension) {
+		return nil, fmt.Errorf("%w: %dx%d, minimum is %dx%d",
+			ErrImageTooSmall, bounds.Dx(), bounds.Dy(), cfg.MinDimension, cfg.MinDimension)
+	}
+
+	// Create upload directory if it doesn't exist
+	uploadDir := filepath.Join(fu.config.UploadPath, subfolder)
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+
+	uniqueName := fu.uniqueFileName(file.Filename)
+	ext := filepath.Ext(uniqueName)
+	contentType := "image/" + format
+
+	resized := fitImage(img, cfg.MaxWidth, cfg.MaxHeight)
+	stored, err := fu.saveImage(resized, format, subfolder, uniqueName, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImageUploadResult{
+		Image:  stored,
+		Width:  resized.Bounds().Dx(),
+		Height: resized.Bounds().Dy(),
+	}
+
+	if cfg.ThumbnailSize > 0 {
+		thumbName := strings.TrimSuffix(uniqueName, ext) + "_thumb" + ext
+		thumb := fitImage(resized, cfg.ThumbnailSize, cfg.ThumbnailSize)
+		result.Thumbnail, err = fu.saveImage(thumb, format, subfolder, thumbName, contentType)
+		if err != nil {
+			os.Remove(stored.FilePath)
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// saveImage encodes an image in the given format and writes it to the upload directory
+func (fu *FileUploader) saveImage(img image.Image, format, subfolder, name, contentType string) (*FileUploadResult, error) {
+	var buf bytes.Buffer
+	if err := encodeImage(&buf, img, format); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	filePath := filepath.Join(fu.config.UploadPath, subfolder, name)
+	if err := os.WriteFile(filePath, buf.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write image: %w", err)
+	}
+
+	return &FileUploadResult{
+		FileName:    name,
+		FilePath:    filePath,
+		FileSize:    int64(buf.Len()),
+		ContentType: contentType,
+		PublicURL:   fmt.Sprintf("%s/uploads/%s/%s", fu.config.PublicURL, subfolder, name),
+	}, nil
+}
+
+// encodeImage encodes an image in the format it was decoded from
+func encodeImage(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		return png.Encode(w, img)
+	case "gif":
+		return gif.Encode(w, img, nil)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedImage, format)
+	}
+}
+
+// fitImage scales an image down, preserving its aspect ratio, so that it fits within
+// maxWidth x maxHeight. Images that already fit are returned unchanged; a zero bound
+// leaves that dimension unconstrained.
+func fitImage(img image.Image, maxWidth, maxHeight int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	scale := 1.0
+	if maxWidth > 0 && width > maxWidth {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && height > maxHeight {
+		if s := float64(maxHeight) / float64(height); s < scale {
+			scale = s
+		}
+	}
+	if scale == 1.0 {
+		return img
+	}
+
+	newWidth := max(1, int(float64(width)*scale+0.5))
+	newHeight := max(1, int(float64(height)*scale+0.5))
+	return resizeImage(img, newWidth, newHeight)
+}
+
+// resizeImage scales an image down to width x height by averaging the source pixels
+// that fall into each destination pixel (box filter)
+func resizeImage(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		srcY0 := bounds.Min.Y + y*bounds.Dy()/height
+		srcY1 := max(srcY0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+
+		for x := 0; x < width; x++ {
+			srcX0 := bounds.Min.X + x*bounds.Dx()/width
+			srcX1 := max(srcX0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := srcY0; sy < srcY1; sy++ {
+				for sx := srcX0; sx < srcX1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
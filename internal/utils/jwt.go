@@ -1,8 +1,12 @@
 package utils
 
 import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
 	"time"
 
 	"github.com/gatehide/gatehide-api/config"
@@ -18,30 +22,88 @@ type JWTClaims struct {
 	jwt.RegisteredClaims
 }
 
+// reauthAudience marks re-authentication tokens so they are never accepted as access tokens
+const reauthAudience = "reauth"
+
+// defaultReauthTokenTTL is used when no re-authentication token lifetime is configured
+const defaultReauthTokenTTL = 5 * time.Minute
+
+// ReauthClaims represents the claims of a short-lived re-authentication token,
+// issued after the account owner confirmed their password
+type ReauthClaims struct {
+	UserID   int    `json:"user_id"`
+	UserType string `json:"user_type"`
+	jwt.RegisteredClaims
+}
+
+// totpChallengeAudience marks two-factor login challenge tokens so they are never
+// accepted as access tokens
+const totpChallengeAudience = "totp_challenge"
+
+// TOTPChallengeTTL is how long a two-factor login challenge token stays valid
+const TOTPChallengeTTL = 5 * time.Minute
+
+// TOTPChallengeClaims represents the claims of a two-factor login challenge token,
+// issued after an admin with TOTP enabled confirmed their password
+type TOTPChallengeClaims struct {
+	UserID     int    `json:"user_id"`
+	UserType   string `json:"user_type"`
+	RememberMe bool   `json:"remember_me"`
+	jwt.RegisteredClaims
+}
+
+// ErrTokenExpired is returned for a correctly signed token that is past its expiry,
+// which the client can still replace by refreshing
+var ErrTokenExpired = errors.New("token expired")
+
+// ErrTokenInvalid is returned for a malformed, tampered or otherwise unusable token
+var ErrTokenInvalid = errors.New("token invalid")
+
+// ErrTokenRevoked is returned for a token revoked by logging out. It also matches
+// ErrTokenInvalid.
+var ErrTokenRevoked = fmt.Errorf("%w: revoked", ErrTokenInvalid)
+
+// ErrRefreshWindowExpired is returned when refreshing a token that expired longer ago
+// than the refresh grace period; the client has to log in again. It also matches
+// ErrTokenExpired.
+var ErrRefreshWindowExpired = errors.New("token expired beyond the refresh grace period")
+
 // JWTManager handles JWT operations
 type JWTManager struct {
-	secret             []byte
-	expiration         time.Duration
-	rememberExpiration time.Duration
+	secret []byte
+	cfg    *config.Config
 }
 
 // NewJWTManager creates a new JWT manager
 func NewJWTManager(cfg *config.Config) *JWTManager {
 	return &JWTManager{
-		secret:             []byte(cfg.Security.JWTSecret),
-		expiration:         time.Duration(cfg.Security.JWTExpiration) * time.Hour,
-		rememberExpiration: time.Duration(cfg.Security.JWTExpiration) * time.Hour * 24 * 7, // 7 days for remember me
+		secret: []byte(cfg.Security.JWTSecret),
+		cfg:    cfg,
 	}
 }
 
+// ExpirationFor returns the token lifetime for the given user type and remember me choice
+func (j *JWTManager) ExpirationFor(userType string, rememberMe bool) time.Duration {
+	return time.Duration(j.cfg.GetTokenExpiration(userType, rememberMe)) * time.Hour
+}
+
 // GenerateToken generates a new JWT token for the given user
 func (j *JWTManager) GenerateToken(userID int, userType, email, name string, rememberMe bool) (string, error) {
+	token, _, err := j.GenerateTokenWithExpiry(userID, userType, email, name, rememberMe)
+	return token, err
+}
+
+// GenerateTokenWithExpiry generates a new JWT token for the given user and returns its
+// exp claim, so sessions and responses can record exactly when the token expires
+func (j *JWTManager) GenerateTokenWithExpiry(userID int, userType, email, name string, rememberMe bool) (string, time.Time, error) {
 	now := time.Now()
 
-	// Choose expiration based on remember me
-	expiration := j.expiration
-	if rememberMe {
-		expiration = j.rememberExpiration
+	// Choose expiration based on user type and remember me
+	expiration := j.ExpirationFor(userType, rememberMe)
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate token id: %w", err)
 	}
 
 	claims := JWTClaims{
@@ -55,38 +117,186 @@ func (j *JWTManager) GenerateToken(userID int, userType, email, name string, rem
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "gatehide-api",
 			Subject:   fmt.Sprintf("%d", userID),
+			ID:        tokenID,
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+	signed, err := token.SignedString(j.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, claims.ExpiresAt.Time, nil
 }
 
-// ValidateToken validates and parses a JWT token
+// newTokenID generates a random jti, which identifies a token for revocation
+func newTokenID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := crand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// RefreshGracePeriod returns how long after expiring a token may still be refreshed
+func (j *JWTManager) RefreshGracePeriod() time.Duration {
+	return time.Duration(j.cfg.Security.RefreshGracePeriodMinutes) * time.Minute
+}
+
+// TokenID returns the jti of a token without verifying it, or "" when it has none.
+// Only use it on tokens that are verified separately.
+func TokenID(tokenString string) string {
+	claims := &JWTClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return ""
+	}
+	return claims.ID
+}
+
+// ValidateToken validates and parses a JWT token. The error wraps ErrTokenExpired when
+// the token is authentic but expired, and ErrTokenInvalid otherwise.
 func (j *JWTManager) ValidateToken(tokenString string) (*JWTClaims, error) {
+	return j.parseToken(tokenString)
+}
+
+// parseToken validates and parses an access token with extra parser options, such as
+// a leeway on its expiry
+func (j *JWTManager) parseToken(tokenString string, opts ...jwt.ParserOption) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Verify the signing method
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 		return j.secret, nil
-	})
+	}, opts...)
 
 	if err != nil {
-		return nil, err
+		// The parser checks the signature before the expiry, so an expired token is authentic
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, fmt.Errorf("%w: %v", ErrTokenExpired, err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrTokenInvalid, err)
 	}
 
 	if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
+		if slices.Contains(claims.Audience, reauthAudience) || slices.Contains(claims.Audience, totpChallengeAudience) {
+			return nil, ErrTokenInvalid
+		}
+		return claims, nil
+	}
+
+	return nil, ErrTokenInvalid
+}
+
+// GenerateReauthToken generates a short-lived re-authentication token for the given account
+func (j *JWTManager) GenerateReauthToken(userID int, userType string) (string, time.Time, error) {
+	now := time.Now()
+	ttl := time.Duration(j.cfg.Security.ReauthTokenMinutes) * time.Minute
+	if ttl <= 0 {
+		ttl = defaultReauthTokenTTL
+	}
+	expiresAt := now.Add(ttl)
+
+	claims := ReauthClaims{
+		UserID:   userID,
+		UserType: userType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "gatehide-api",
+			Subject:   fmt.Sprintf("%d", userID),
+			Audience:  jwt.ClaimStrings{reauthAudience},
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(j.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ValidateReauthToken validates and parses a re-authentication token
+func (j *JWTManager) ValidateReauthToken(tokenString string) (*ReauthClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ReauthClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// Verify the signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return j.secret, nil
+	}, jwt.WithAudience(reauthAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*ReauthClaims); ok && token.Valid {
 		return claims, nil
 	}
 
 	return nil, fmt.Errorf("invalid token")
 }
 
-// RefreshToken generates a new token with extended expiration
+// GenerateTOTPChallengeToken generates a two-factor login challenge token for the
+// given account, remembering whether the login asked to be remembered
+func (j *JWTManager) GenerateTOTPChallengeToken(userID int, userType string, rememberMe bool) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(TOTPChallengeTTL)
+
+	claims := TOTPChallengeClaims{
+		UserID:     userID,
+		UserType:   userType,
+		RememberMe: rememberMe,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "gatehide-api",
+			Subject:   fmt.Sprintf("%d", userID),
+			Audience:  jwt.ClaimStrings{totpChallengeAudience},
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(j.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ValidateTOTPChallengeToken validates and parses a two-factor login challenge token
+func (j *JWTManager) ValidateTOTPChallengeToken(tokenString string) (*TOTPChallengeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &TOTPChallengeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// Verify the signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return j.secret, nil
+	}, jwt.WithAudience(totpChallengeAudience))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*TOTPChallengeClaims); ok && token.Valid {
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+// RefreshToken generates a new token with extended expiration. Tokens that expired
+// within Security.RefreshGracePeriodMinutes can still be refreshed; older ones are
+// rejected with ErrRefreshWindowExpired.
 func (j *JWTManager) RefreshToken(tokenString string, rememberMe bool) (string, error) {
-	claims, err := j.ValidateToken(tokenString)
+	claims, err := j.parseToken(tokenString, jwt.WithLeeway(j.RefreshGracePeriod()))
 	if err != nil {
+		if errors.Is(err, ErrTokenExpired) {
+			return "", fmt.Errorf("%w: %w", ErrRefreshWindowExpired, err)
+		}
 		return "", err
 	}
 
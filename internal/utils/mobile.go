@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// iranianMobilePattern matches a mobile number in local form, e.g. 09123456789
+var iranianMobilePattern = regexp.MustCompile(`^09\d{9}$`)
+
+// NormalizeIranianMobile converts an Iranian mobile number written as 09123456789,
+// 9123456789, +989123456789, 989123456789 or 00989123456789 (spaces and dashes
+// allowed) to the local form stored on accounts. It reports false when the value is
+// not a mobile number.
+func NormalizeIranianMobile(value string) (string, bool) {
+	cleaned := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(value))
+
+	switch {
+	case strings.HasPrefix(cleaned, "+98"):
+		cleaned = "0" + strings.TrimPrefix(cleaned, "+98")
+	case strings.HasPrefix(cleaned, "0098"):
+		cleaned = "0" + strings.TrimPrefix(cleaned, "0098")
+	case strings.HasPrefix(cleaned, "98") && len(cleaned) == 12:
+		cleaned = "0" + strings.TrimPrefix(cleaned, "98")
+	case strings.HasPrefix(cleaned, "9") && len(cleaned) == 10:
+		cleaned = "0" + cleaned
+	}
+
+	if !iranianMobilePattern.MatchString(cleaned) {
+		return "", false
+	}
+	return cleaned, true
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gatehide/gatehide-api/config"
+)
+
+// ErrWeakPassword is matched by every error returned by ValidatePasswordStrength
+var ErrWeakPassword = errors.New("password does not meet the password policy")
+
+// Password policy rules a password can fail; each is matched by the returned error
+var (
+	ErrPasswordTooShort = errors.New("password is too short")
+	ErrPasswordNoDigit  = errors.New("password must contain a digit")
+	ErrPasswordNoUpper  = errors.New("password must contain an uppercase letter")
+	ErrPasswordNoLower  = errors.New("password must contain a lowercase letter")
+	ErrPasswordNoSymbol = errors.New("password must contain a symbol")
+)
+
+// WeakPasswordError reports the first password policy rule a password fails
+type WeakPasswordError struct {
+	// Rule is one of the ErrPassword* rule errors
+	Rule      error
+	MinLength int
+}
+
+func (e *WeakPasswordError) Error() string {
+	if e.Rule == ErrPasswordTooShort {
+		return fmt.Sprintf("password must be at least %d characters long", e.MinLength)
+	}
+	return e.Rule.Error()
+}
+
+// Is makes errors.Is match both ErrWeakPassword and the failed rule
+func (e *WeakPasswordError) Is(target error) bool {
+	return target == ErrWeakPassword || target == e.Rule
+}
+
+// ValidatePasswordStrength checks a new password against the password policy. The
+// minimum length counts characters, not bytes; the 72 byte upper bound is checked
+// separately when the password is hashed.
+func ValidatePasswordStrength(password string, policy config.PasswordPolicyConfig) error {
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		return &WeakPasswordError{Rule: ErrPasswordTooShort, MinLength: policy.MinLength}
+	}
+
+	var hasDigit, hasUpper, hasLower, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	switch {
+	case policy.RequireDigit && !hasDigit:
+		return &WeakPasswordError{Rule: ErrPasswordNoDigit, MinLength: policy.MinLength}
+	case policy.RequireUpper && !hasUpper:
+		return &WeakPasswordError{Rule: ErrPasswordNoUpper, MinLength: policy.MinLength}
+	case policy.RequireLower && !hasLower:
+		return &WeakPasswordError{Rule: ErrPasswordNoLower, MinLength: policy.MinLength}
+	case policy.RequireSymbol && !hasSymbol:
+		return &WeakPasswordError{Rule: ErrPasswordNoSymbol, MinLength: policy.MinLength}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a token bucket rate limiter. Callers that exceed the rate wait
+// for their turn instead of being rejected, so bursts are smoothed out.
+type TokenBucket struct {
+	mu        sync.Mutex
+	rate      float64 // tokens added per second
+	burst     int
+	tokens    float64
+	last      time.Time
+	waiting   int
+	reserved  int64
+	delayed   int64
+	unlimited bool
+}
+
+// TokenBucketStats is a snapshot of a token bucket's state
+type TokenBucketStats struct {
+	RatePerMinute int   `json:"rate_per_minute"`
+	Burst         int   `json:"burst"`
+	Available     int   `json:"available"`
+	Waiting       int   `json:"waiting"`
+	Reserved      int64 `json:"reserved"`
+	Delayed       int64 `json:"delayed"`
+}
+
+// NewTokenBucket creates a limiter allowing ratePerMinute events per minute with
+// bursts of up to burst events. A non-positive rate disables limiting.
+func NewTokenBucket(ratePerMinute, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:      float64(ratePerMinute) / 60,
+		burst:     burst,
+		tokens:    float64(burst),
+		last:      time.Now(),
+		unlimited: ratePerMinute <= 0,
+	}
+}
+
+// NewTokenBucketPer creates a limiter allowing limit events per window, all of which
+// may come in one burst. A non-positive limit or window disables limiting.
+func NewTokenBucketPer(limit int, window time.Duration) *TokenBucket {
+	if limit <= 0 || window <= 0 {
+		return NewTokenBucket(0, 1)
+	}
+	return &TokenBucket{
+		rate:   float64(limit) / window.Seconds(),
+		burst:  limit,
+		tokens: float64(limit),
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a token if one is available. Otherwise it takes nothing and returns how
+// long until a token is available, for callers that reject rather than wait.
+func (b *TokenBucket) Allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.unlimited {
+		b.reserved++
+		return true, 0
+	}
+
+	b.refill(time.Now())
+	if b.tokens >= 1 {
+		b.tokens--
+		b.reserved++
+		return true, 0
+	}
+
+	b.delayed++
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Full reports whether the bucket has refilled completely, i.e. it has been idle for
+// long enough that dropping it and starting a new one would change nothing
+func (b *TokenBucket) Full() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.unlimited {
+		return true
+	}
+	b.refill(time.Now())
+	return b.tokens >= float64(b.burst)
+}
+
+// Reserve takes a token and returns how long the caller must wait before using it
+func (b *TokenBucket) Reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reserved++
+	if b.unlimited {
+		return 0
+	}
+
+	b.refill(time.Now())
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	b.delayed++
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available or the context is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	delay := b.Reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	b.waiting++
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.waiting--
+		b.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Hand the unused token back
+		b.mu.Lock()
+		b.tokens++
+		b.reserved--
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the limiter's state
+func (b *TokenBucket) Stats() TokenBucketStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := TokenBucketStats{
+		Burst:    b.burst,
+		Waiting:  b.waiting,
+		Reserved: b.reserved,
+		Delayed:  b.delayed,
+	}
+	if b.unlimited {
+		return stats
+	}
+
+	b.refill(time.Now())
+	stats.RatePerMinute = int(b.rate * 60)
+	if b.tokens > 0 {
+		stats.Available = int(b.tokens)
+	}
+	return stats
+}
+
+// refill adds the tokens accumulated since the last update, up to the burst size
+func (b *TokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.tokens += elapsed * b.rate
+	if b.tokens > float64(b.burst) {
+		b.tokens = float64(b.burst)
+	}
+}
@@ -0,0 +1,95 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Task is a unit of work run on every tick of a periodic worker
+type Task func(ctx context.Context) error
+
+// Backoff computes the delay before the next run: the base interval while runs
+// succeed, doubling after each consecutive failure up to Max. A Max below Base,
+// including zero, disables backoff; a failing run never retries sooner than Base.
+type Backoff struct {
+	Base     time.Duration
+	Max      time.Duration
+	failures int
+}
+
+// Next records the outcome of a run and returns the delay before the next one
+func (b *Backoff) Next(err error) time.Duration {
+	if err == nil {
+		b.failures = 0
+		return b.Base
+	}
+
+	b.failures++
+	limit := max(b.Max, b.Base)
+	delay := b.Base
+	for i := 0; i < b.failures && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// Failures returns the number of consecutive failed runs
+func (b *Backoff) Failures() int {
+	return b.failures
+}
+
+// PeriodicWorker runs a task on an interval until its context is cancelled.
+// Task errors (e.g. a transient DB outage) are logged and retried with backoff
+// instead of stopping the worker; the sql.DB pool reconnects on its own.
+type PeriodicWorker struct {
+	name    string
+	task    Task
+	backoff *Backoff
+}
+
+// NewPeriodicWorker creates a new periodic worker
+func NewPeriodicWorker(name string, interval, maxInterval time.Duration, task Task) *PeriodicWorker {
+	return &PeriodicWorker{
+		name:    name,
+		task:    task,
+		backoff: &Backoff{Base: interval, Max: maxInterval},
+	}
+}
+
+// Run executes the task immediately and then on every tick until ctx is done
+func (w *PeriodicWorker) Run(ctx context.Context) {
+	for {
+		err := w.runOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		wasFailing := w.backoff.Failures() > 0
+		delay := w.backoff.Next(err)
+		if err != nil {
+			fmt.Printf("Warning: worker %s failed (%d consecutive): %v; retrying in %s\n", w.name, w.backoff.Failures(), err, delay)
+		} else if wasFailing {
+			fmt.Printf("Worker %s recovered\n", w.name)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// runOnce runs the task, turning a panic into an error so the worker keeps going
+func (w *PeriodicWorker) runOnce(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return w.task(ctx)
+}
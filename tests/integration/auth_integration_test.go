@@ -436,6 +436,48 @@ func TestAuthenticationIntegration_Logout(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, response, "message")
 	assert.Equal(t, "Logout successful", response["message"])
+
+	// The login session must no longer be active
+	var isActive bool
+	err = db.QueryRow("SELECT is_active FROM user_sessions WHERE session_token = ?", token).Scan(&isActive)
+	assert.NoError(t, err)
+	assert.False(t, isActive)
+}
+
+func TestAuthenticationIntegration_RevokeOtherSessions(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	cfg := testutils.TestConfig()
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDBForce(t, db)
+
+	user := testutils.CreateTestUser(t, db, "revoke@example.com", "password123", "Revoke Test User")
+	router := setupTestRouter(cfg, db)
+	token := getAuthToken(t, router, "revoke@example.com", "password123")
+
+	// A session on another device
+	_, err := db.Exec(`
+		INSERT INTO user_sessions (user_id, user_type, session_token, device_info, expires_at)
+		VALUES (?, 'user', 'other-device-token', 'Other Device', DATE_ADD(NOW(), INTERVAL 1 DAY))
+	`, user.ID)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/api/v1/profile/sessions/revoke-others", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var currentActive, otherActive bool
+	err = db.QueryRow("SELECT is_active FROM user_sessions WHERE session_token = ?", token).Scan(&currentActive)
+	assert.NoError(t, err)
+	assert.True(t, currentActive)
+
+	err = db.QueryRow("SELECT is_active FROM user_sessions WHERE session_token = 'other-device-token'").Scan(&otherActive)
+	assert.NoError(t, err)
+	assert.False(t, otherActive)
 }
 
 // Helper functions
@@ -459,11 +501,12 @@ func setupTestRouter(cfg *config.Config, db *sql.DB) *gin.Engine {
 
 	// Initialize services
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, nil, emailVerificationRepo, notificationService, permissionService, cfg)
 
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(cfg)
 	authHandler := handlers.NewAuthHandler(authService, fileUploader)
+	authHandler.SetExposeVerificationCodes(cfg.ExposeVerificationCodesActive())
 
 	// Setup routes
 	v1 := router.Group("/api/v1")
@@ -486,6 +529,7 @@ func setupTestRouter(cfg *config.Config, db *sql.DB) *gin.Engine {
 		protected.Use(middlewares.AuthMiddleware(authService))
 		{
 			protected.GET("/profile", authHandler.GetProfile)
+			protected.POST("/profile/sessions/revoke-others", authHandler.RevokeOtherSessions)
 
 			admin := protected.Group("/admin")
 			admin.Use(middlewares.AdminMiddleware())
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/routes"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGamenetIntegration_CreateAndRead(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer db.Exec("DELETE FROM gamenets WHERE email = 'arena@example.com'")
+	db.Exec("DELETE FROM gamenets WHERE email = 'arena@example.com'")
+
+	cfg := testutils.TestConfig()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	routes.SetupRoutes(router, cfg, db)
+
+	// Sign in as an administrator
+	admin := testutils.CreateTestAdmin(t, db, "gamenet-admin@test.com", "adminpass", "Gamenet Admin")
+	defer db.Exec("DELETE FROM admins WHERE id = ?", admin.ID)
+	_, err := db.Exec(`
+		INSERT INTO user_roles (user_id, user_type, role_id)
+		SELECT ?, 'admin', id FROM roles WHERE name = 'administrator'
+	`, admin.ID)
+	require.NoError(t, err)
+
+	loginBody, _ := json.Marshal(map[string]string{"email": "gamenet-admin@test.com", "password": "adminpass"})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBuffer(loginBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var loginResponse struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &loginResponse))
+	token := loginResponse.Data.Token
+
+	// Create the gamenet through the API
+	fields := map[string]string{
+		"name":             "Arena",
+		"owner_name":       "Arena Owner",
+		"owner_mobile":     "09121234567",
+		"address":          "Tehran",
+		"email":            "arena@example.com",
+		"send_credentials": "false",
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	writer.Close()
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/gamenets/", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created struct {
+		Data struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	require.NotZero(t, created.Data.ID)
+
+	// Read it back
+	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/gamenets/%d", created.Data.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var fetched struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Equal(t, float64(created.Data.ID), fetched.Data["id"])
+	for name, value := range fields {
+		if name == "send_credentials" {
+			continue
+		}
+		assert.Equal(t, value, fetched.Data[name], name)
+	}
+	assert.NotContains(t, fetched.Data, "password")
+
+	// Creating it again is a conflict, not a server error
+	body = &bytes.Buffer{}
+	writer = multipart.NewWriter(body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+	writer.Close()
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/gamenets/", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+}
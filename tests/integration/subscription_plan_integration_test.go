@@ -11,6 +11,7 @@ import (
 
 	"github.com/gatehide/gatehide-api/internal/handlers"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gatehide/gatehide-api/tests/utils"
 	"github.com/gin-gonic/gin"
@@ -54,7 +55,7 @@ func TestSubscriptionPlanIntegration(t *testing.T) {
 		// Mock expectations for the complete flow
 		mockRepo.On("Create", mock.AnythingOfType("*models.SubscriptionPlan")).Return(nil).Once()
 		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Integration Test Plan", "monthly", 29.99), nil).Times(3) // Get, Update, Delete
-		mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan")).Return(nil).Once()
+		mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).Return(nil).Once()
 		mockRepo.On("HasActiveSubscriptions", 1).Return(false, nil).Once()
 		mockRepo.On("Delete", 1).Return(nil).Once()
 
@@ -397,7 +398,7 @@ func TestSubscriptionPlanErrorHandlingIntegration(t *testing.T) {
 			method:      http.MethodGet,
 			requestBody: nil,
 			mockSetup: func(mockRepo *utils.MockSubscriptionPlanRepository) {
-				mockRepo.On("GetByID", 999).Return(nil, fmt.Errorf("subscription plan not found"))
+				mockRepo.On("GetByID", 999).Return(nil, repositories.ErrNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedError:  "Plan not found",
@@ -410,7 +411,7 @@ func TestSubscriptionPlanErrorHandlingIntegration(t *testing.T) {
 				Name: func() *string { v := "Updated Plan"; return &v }(),
 			},
 			mockSetup: func(mockRepo *utils.MockSubscriptionPlanRepository) {
-				mockRepo.On("GetByID", 999).Return(nil, fmt.Errorf("subscription plan not found"))
+				mockRepo.On("GetByID", 999).Return(nil, repositories.ErrNotFound)
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "Failed to update plan",
@@ -434,7 +435,7 @@ func TestSubscriptionPlanErrorHandlingIntegration(t *testing.T) {
 			method:      http.MethodDelete,
 			requestBody: nil,
 			mockSetup: func(mockRepo *utils.MockSubscriptionPlanRepository) {
-				mockRepo.On("GetByID", 999).Return(nil, fmt.Errorf("subscription plan not found"))
+				mockRepo.On("GetByID", 999).Return(nil, repositories.ErrNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedError:  "Plan not found",
@@ -238,7 +238,7 @@ func (suite *UserIntegrationTestSuite) TestGetAllUsers() {
 		Name:     "Test User",
 		Email:    "testgetall@example.com",
 		Mobile:   "09123456789",
-		Password: hashedPassword,
+		Password: models.PasswordHash(hashedPassword),
 	}
 	userRepo.Create(testUser)
 
@@ -271,7 +271,7 @@ func (suite *UserIntegrationTestSuite) TestGetUserByID() {
 		Name:     "Test User",
 		Email:    "testgetbyid@example.com",
 		Mobile:   "09123456789",
-		Password: hashedPassword,
+		Password: models.PasswordHash(hashedPassword),
 	}
 	userRepo.Create(testUser)
 
@@ -305,7 +305,7 @@ func (suite *UserIntegrationTestSuite) TestUpdateUser() {
 		Name:     "Test User",
 		Email:    "testupdate@example.com",
 		Mobile:   "09123456789",
-		Password: hashedPassword,
+		Password: models.PasswordHash(hashedPassword),
 	}
 	userRepo.Create(testUser)
 
@@ -346,7 +346,7 @@ func (suite *UserIntegrationTestSuite) TestDeleteUser() {
 		Name:     "Test User",
 		Email:    "testdelete@example.com",
 		Mobile:   "09123456789",
-		Password: hashedPassword,
+		Password: models.PasswordHash(hashedPassword),
 	}
 	userRepo.Create(testUser)
 
@@ -379,9 +379,9 @@ func (suite *UserIntegrationTestSuite) TestSearchUsers() {
 	hashedPassword, _ := models.HashPassword("password123")
 
 	users := []*models.User{
-		{Name: "John Doe", Email: "testsearch1@example.com", Mobile: "09111111111", Password: hashedPassword},
-		{Name: "Jane Doe", Email: "testsearch2@example.com", Mobile: "09222222222", Password: hashedPassword},
-		{Name: "Bob Smith", Email: "testsearch3@example.com", Mobile: "09333333333", Password: hashedPassword},
+		{Name: "John Doe", Email: "testsearch1@example.com", Mobile: "09111111111", Password: models.PasswordHash(hashedPassword)},
+		{Name: "Jane Doe", Email: "testsearch2@example.com", Mobile: "09222222222", Password: models.PasswordHash(hashedPassword)},
+		{Name: "Bob Smith", Email: "testsearch3@example.com", Mobile: "09333333333", Password: models.PasswordHash(hashedPassword)},
 	}
 
 	for _, user := range users {
@@ -406,6 +406,49 @@ func (suite *UserIntegrationTestSuite) TestSearchUsers() {
 	assert.GreaterOrEqual(t, len(data), 2, "Should find at least 2 users with 'Doe'")
 }
 
+func (suite *UserIntegrationTestSuite) TestListAdminsPaginated() {
+	t := suite.T()
+
+	// Create admins sharing a searchable email prefix
+	hashedPassword, _ := models.HashPassword("adminpass")
+	for i := 0; i < 3; i++ {
+		_, err := suite.db.Exec("INSERT INTO admins (name, email, mobile, password) VALUES (?, ?, ?, ?)",
+			fmt.Sprintf("Listed Admin %d", i), fmt.Sprintf("adminlisted%d@test.com", i), fmt.Sprintf("0935000000%d", i), hashedPassword)
+		assert.NoError(t, err)
+	}
+
+	getPage := func(page int) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/admins?query=adminlisted&page=%d&page_size=2", page), nil)
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	first := getPage(1)
+	assert.Len(t, first["data"], 2)
+	pagination := first["pagination"].(map[string]interface{})
+	assert.Equal(t, float64(3), pagination["total_items"])
+	assert.Equal(t, float64(2), pagination["total_pages"])
+	assert.Equal(t, true, pagination["has_next"])
+
+	second := getPage(2)
+	assert.Len(t, second["data"], 1)
+	pagination = second["pagination"].(map[string]interface{})
+	assert.Equal(t, false, pagination["has_next"])
+	assert.Equal(t, true, pagination["has_prev"])
+
+	// Admin responses never carry the password hash or TOTP secret
+	admin := second["data"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, admin, "password")
+	assert.NotContains(t, admin, "totp_secret")
+}
+
 func (suite *UserIntegrationTestSuite) TestCreateUserInvalidData() {
 	t := suite.T()
 
@@ -465,6 +508,195 @@ func (suite *UserIntegrationTestSuite) TestCreateUserInvalidData() {
 	}
 }
 
+func (suite *UserIntegrationTestSuite) TestLinkAndUnlinkGamenetUser() {
+	t := suite.T()
+
+	gamenetRepo := repositories.NewGamenetRepository(suite.db)
+	gamenetPassword, _ := models.HashPassword("password123")
+	gamenet := &models.Gamenet{
+		Name:        "Test Gamenet",
+		OwnerName:   "Test Owner",
+		OwnerMobile: "09123456788",
+		Address:     "Test Address",
+		Email:       "testlinkgamenet@example.com",
+		Password:    models.PasswordHash(gamenetPassword),
+	}
+	if err := gamenetRepo.Create(gamenet); err != nil {
+		t.Fatalf("Failed to create gamenet: %v", err)
+	}
+	defer suite.db.Exec("DELETE FROM gamenets WHERE id = ?", gamenet.ID)
+
+	userRepo := repositories.NewUserRepository(suite.db)
+	userPassword, _ := models.HashPassword("password123")
+	testUser := &models.User{
+		Name:     "Test User",
+		Email:    "testlink@example.com",
+		Mobile:   "09123456789",
+		Password: models.PasswordHash(userPassword),
+	}
+	if err := userRepo.Create(testUser); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	path := fmt.Sprintf("/api/v1/gamenets/%d/users/%d", gamenet.ID, testUser.ID)
+	request := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Link the user
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, path).Code)
+	linked, err := userRepo.IsLinkedToGamenet(testUser.ID, gamenet.ID)
+	assert.NoError(t, err)
+	assert.True(t, linked)
+
+	// Linking again is a conflict and leaves the link in place
+	assert.Equal(t, http.StatusConflict, request(http.MethodPost, path).Code)
+	linked, err = userRepo.IsLinkedToGamenet(testUser.ID, gamenet.ID)
+	assert.NoError(t, err)
+	assert.True(t, linked)
+
+	// Unlink the user
+	assert.Equal(t, http.StatusOK, request(http.MethodDelete, path).Code)
+	linked, err = userRepo.IsLinkedToGamenet(testUser.ID, gamenet.ID)
+	assert.NoError(t, err)
+	assert.False(t, linked)
+
+	// Unlinking a user that is not linked is not found
+	assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, path).Code)
+
+	// Unknown gamenets and users are not found
+	assert.Equal(t, http.StatusNotFound, request(http.MethodPost, fmt.Sprintf("/api/v1/gamenets/%d/users/%d", gamenet.ID+1000000, testUser.ID)).Code)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodPost, fmt.Sprintf("/api/v1/gamenets/%d/users/%d", gamenet.ID, testUser.ID+1000000)).Code)
+}
+
+func (suite *UserIntegrationTestSuite) TestAssignAndRemoveUserRole() {
+	t := suite.T()
+
+	userRepo := repositories.NewUserRepository(suite.db)
+	userPassword, _ := models.HashPassword("password123")
+	testUser := &models.User{
+		Name:     "Test User",
+		Email:    "testroles@example.com",
+		Mobile:   "09123456787",
+		Password: models.PasswordHash(userPassword),
+	}
+	if err := userRepo.Create(testUser); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	defer suite.db.Exec("DELETE FROM user_roles WHERE user_id = ? AND user_type = 'user'", testUser.ID)
+
+	permissionRepo := repositories.NewPermissionRepository(suite.db)
+	rolesPath := fmt.Sprintf("/api/v1/users/%d/roles", testUser.ID)
+	request := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var payload bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&payload).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &payload)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+suite.token)
+		w := httptest.NewRecorder()
+		suite.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Assign a role
+	assert.Equal(t, http.StatusOK, request(http.MethodPost, rolesPath, map[string]string{"role": models.RoleGamenet}).Code)
+	hasRole, err := permissionRepo.HasUserRole(testUser.ID, "user", models.RoleGamenet)
+	assert.NoError(t, err)
+	assert.True(t, hasRole)
+
+	w := request(http.MethodGet, rolesPath, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data []models.Role `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, models.RoleGamenet, response.Data[0].Name)
+	}
+
+	// Assigning it again is a conflict
+	assert.Equal(t, http.StatusConflict, request(http.MethodPost, rolesPath, map[string]string{"role": models.RoleGamenet}).Code)
+
+	// Unknown roles and users are not found
+	assert.Equal(t, http.StatusNotFound, request(http.MethodPost, rolesPath, map[string]string{"role": "no_such_role"}).Code)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/roles", testUser.ID+1000000), nil).Code)
+
+	// Remove the role
+	assert.Equal(t, http.StatusOK, request(http.MethodDelete, rolesPath+"/"+models.RoleGamenet, nil).Code)
+	hasRole, err = permissionRepo.HasUserRole(testUser.ID, "user", models.RoleGamenet)
+	assert.NoError(t, err)
+	assert.False(t, hasRole)
+
+	// Removing a role the user does not have is not found
+	assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, rolesPath+"/"+models.RoleGamenet, nil).Code)
+}
+
+func (suite *UserIntegrationTestSuite) TestUserPermissionsAcrossRoles() {
+	t := suite.T()
+
+	userRepo := repositories.NewUserRepository(suite.db)
+	userPassword, _ := models.HashPassword("password123")
+	testUser := &models.User{
+		Name:     "Test User",
+		Email:    "testmultirole@example.com",
+		Mobile:   "09123456786",
+		Password: models.PasswordHash(userPassword),
+	}
+	if err := userRepo.Create(testUser); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	defer suite.db.Exec("DELETE FROM user_roles WHERE user_id = ? AND user_type = 'user'", testUser.ID)
+
+	// The user and gamenet roles both grant support:access and settings:manage
+	permissionRepo := repositories.NewPermissionRepository(suite.db)
+	assert.NoError(t, permissionRepo.AssignRoleToUser(testUser.ID, "user", models.RoleUser))
+	assert.NoError(t, permissionRepo.AssignRoleToUser(testUser.ID, "user", models.RoleGamenet))
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/users/%d/permissions", testUser.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data models.UserPermissionsResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Data.Roles, 2)
+	// The union of both roles, each permission once
+	assert.Contains(t, response.Data.Permissions, models.PermissionReservationManage)
+	assert.Contains(t, response.Data.Permissions, models.PermissionUsersRead)
+	seen := make(map[string]bool)
+	for _, permission := range response.Data.Permissions {
+		assert.False(t, seen[permission], "duplicate permission %s", permission)
+		seen[permission] = true
+	}
+	assert.True(t, seen[models.PermissionSupportAccess])
+	assert.True(t, seen[models.PermissionSettingsManage])
+}
+
+func (suite *UserIntegrationTestSuite) TestListPermissions() {
+	t := suite.T()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/permissions", nil)
+	req.Header.Set("Authorization", "Bearer "+suite.token)
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data []models.Permission `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.Data)
+}
+
 func TestUserIntegrationTestSuite(t *testing.T) {
 	suite.Run(t, new(UserIntegrationTestSuite))
 }
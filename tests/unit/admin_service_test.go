@@ -0,0 +1,103 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminService_GetAll(t *testing.T) {
+	t.Run("returns admins without sensitive data", func(t *testing.T) {
+		adminRepo := new(testutils.MockAdminRepository)
+		admin := testutils.CreateMockAdmin(1, "admin@example.com", "Admin")
+		secret := "JBSWY3DPEHPK3PXP"
+		enabledAt := time.Now()
+		admin.TOTPSecret = &secret
+		admin.TOTPEnabledAt = &enabledAt
+		adminRepo.On("GetAll").Return([]models.Admin{*admin}, nil)
+
+		admins, err := services.NewAdminService(adminRepo).GetAll(context.Background())
+
+		require.NoError(t, err)
+		require.Len(t, admins, 1)
+		assert.Equal(t, "admin@example.com", admins[0].Email)
+		assert.True(t, admins[0].TOTPEnabled)
+	})
+
+	t.Run("wraps repository errors", func(t *testing.T) {
+		adminRepo := new(testutils.MockAdminRepository)
+		dbErr := errors.New("connection refused")
+		adminRepo.On("GetAll").Return(nil, dbErr)
+
+		admins, err := services.NewAdminService(adminRepo).GetAll(context.Background())
+
+		assert.Nil(t, admins)
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
+
+func TestAdminService_Search(t *testing.T) {
+	t.Run("returns the repository page", func(t *testing.T) {
+		adminRepo := new(testutils.MockAdminRepository)
+		req := &models.UserSearchRequest{Query: "admin", Page: 2, PageSize: 1}
+		page := &models.AdminSearchResponse{
+			Data:       []models.AdminResponse{{ID: 2, Email: "admin2@example.com"}},
+			Pagination: models.PaginationInfo{CurrentPage: 2, PageSize: 1, TotalItems: 2, TotalPages: 2, HasPrev: true},
+		}
+		adminRepo.On("Search", req).Return(page, nil)
+
+		result, err := services.NewAdminService(adminRepo).Search(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, page, result)
+	})
+
+	t.Run("wraps repository errors", func(t *testing.T) {
+		adminRepo := new(testutils.MockAdminRepository)
+		req := &models.UserSearchRequest{}
+		dbErr := errors.New("connection refused")
+		adminRepo.On("Search", req).Return(nil, dbErr)
+
+		result, err := services.NewAdminService(adminRepo).Search(context.Background(), req)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
+
+func TestAdminHandler_GetAllAdmins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	adminRepo := new(testutils.MockAdminRepository)
+	expected := &models.UserSearchRequest{Query: "ali", Page: 3, PageSize: 100}
+	adminRepo.On("Search", expected).Return(&models.AdminSearchResponse{
+		Data:       []models.AdminResponse{},
+		Pagination: models.PaginationInfo{CurrentPage: 3, PageSize: 100},
+	}, nil)
+	handler := handlers.NewAdminHandler(services.NewAdminService(adminRepo))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admins?query=ali&page=3&page_size=500", nil)
+
+	handler.GetAllAdmins(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []interface{}{}, response["data"])
+	assert.Contains(t, response, "pagination")
+	adminRepo.AssertExpectations(t)
+}
@@ -0,0 +1,141 @@
+package unit
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIVersionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		headers         map[string]string
+		expectedStatus  int
+		expectedVersion int
+	}{
+		{name: "defaults to v1", headers: nil, expectedStatus: http.StatusOK, expectedVersion: 1},
+		{name: "X-API-Version header", headers: map[string]string{"X-API-Version": "2"}, expectedStatus: http.StatusOK, expectedVersion: 2},
+		{name: "Accept-Version header with prefix", headers: map[string]string{"Accept-Version": "v2"}, expectedStatus: http.StatusOK, expectedVersion: 2},
+		{name: "X-API-Version takes precedence", headers: map[string]string{"X-API-Version": "1", "Accept-Version": "2"}, expectedStatus: http.StatusOK, expectedVersion: 1},
+		{name: "unsupported version", headers: map[string]string{"X-API-Version": "9"}, expectedStatus: http.StatusBadRequest},
+		{name: "malformed version", headers: map[string]string{"X-API-Version": "latest"}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middlewares.APIVersion())
+
+			var version int
+			router.GET("/test", func(c *gin.Context) {
+				version = middlewares.GetAPIVersion(c)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedVersion, version)
+				assert.Equal(t, strconv.Itoa(tt.expectedVersion), w.Header().Get("X-API-Version"))
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Login_Versions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `{"email":"user@example.com","password":"password123","device":{"id":"abc-123","name":"iPhone 15","platform":"ios"}}`
+	response := &models.LoginResponse{
+		Token:     "valid.jwt.token",
+		UserType:  "user",
+		User:      models.UserResponse{ID: 1, Email: "user@example.com"},
+		ExpiresAt: models.NewTimestamp(time.Now().Add(24 * time.Hour)),
+	}
+
+	tests := []struct {
+		name               string
+		version            string
+		expectedDeviceInfo string
+	}{
+		{
+			name:               "v1 uses X-Device-Info header and ignores body device",
+			version:            "",
+			expectedDeviceInfo: "Legacy Device",
+		},
+		{
+			name:               "v2 uses structured device from body",
+			version:            "2",
+			expectedDeviceInfo: `{"id":"abc-123","name":"iPhone 15","platform":"ios"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(testutils.MockAuthService)
+			mockService.On("LoginWithSession", "user@example.com", "password123", false, tt.expectedDeviceInfo, "192.0.2.1", "", "").Return(response, nil)
+
+			cfg := testutils.TestConfig()
+			handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+			router := gin.New()
+			router.Use(middlewares.APIVersion())
+			router.POST("/auth/login", handler.Login)
+
+			req := httptest.NewRequest("POST", "/auth/login", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Device-Info", "Legacy Device")
+			if tt.version != "" {
+				req.Header.Set("X-API-Version", tt.version)
+			}
+			req.RemoteAddr = "192.0.2.1:1234"
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("v2 falls back to X-Device-Info without body device", func(t *testing.T) {
+		mockService := new(testutils.MockAuthService)
+		mockService.On("LoginWithSession", "user@example.com", "password123", false, "Legacy Device", "192.0.2.1", "", "").Return(response, nil)
+
+		cfg := testutils.TestConfig()
+		handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+		router := gin.New()
+		router.Use(middlewares.APIVersion())
+		router.POST("/auth/login", handler.Login)
+
+		req := httptest.NewRequest("POST", "/auth/login", bytes.NewBufferString(`{"email":"user@example.com","password":"password123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Device-Info", "Legacy Device")
+		req.Header.Set("X-API-Version", "2")
+		req.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -3,17 +3,22 @@ package unit
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/middlewares"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gatehide/gatehide-api/internal/utils"
 	testutils "github.com/gatehide/gatehide-api/tests/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAuthHandler_Login(t *testing.T) {
@@ -38,9 +43,9 @@ func TestAuthHandler_Login(t *testing.T) {
 					UserType:    "user",
 					User:        models.UserResponse{ID: 1, Email: "user@example.com", Name: "Test User"},
 					Permissions: []string{"reservation:manage", "support:access", "settings:manage", "wallet:view"},
-					ExpiresAt:   time.Now().Add(24 * time.Hour),
+					ExpiresAt:   models.NewTimestamp(time.Now().Add(24 * time.Hour)),
 				}
-				m.On("LoginWithSession", "user@example.com", "password123", false, "", "192.0.2.1", "").Return(response, nil)
+				m.On("LoginWithSession", "user@example.com", "password123", false, "", "192.0.2.1", "", "").Return(response, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedError:  false,
@@ -57,9 +62,9 @@ func TestAuthHandler_Login(t *testing.T) {
 					UserType:    "admin",
 					User:        models.AdminResponse{ID: 1, Email: "admin@example.com", Name: "Test Admin"},
 					Permissions: []string{"dashboard:view", "gamenets:create", "gamenets:read", "gamenets:update", "gamenets:delete", "subscription_plans:create", "subscription_plans:read", "subscription_plans:update", "subscription_plans:delete", "analytics:view", "payments:view", "transactions:view", "invoices:view", "settings:manage", "support:access"},
-					ExpiresAt:   time.Now().Add(24 * time.Hour),
+					ExpiresAt:   models.NewTimestamp(time.Now().Add(24 * time.Hour)),
 				}
-				m.On("LoginWithSession", "admin@example.com", "admin123", false, "", "192.0.2.1", "").Return(response, nil)
+				m.On("LoginWithSession", "admin@example.com", "admin123", false, "", "192.0.2.1", "", "").Return(response, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedError:  false,
@@ -71,11 +76,24 @@ func TestAuthHandler_Login(t *testing.T) {
 				Password: "wrongpassword",
 			},
 			mockSetup: func(m *testutils.MockAuthService) {
-				m.On("LoginWithSession", "user@example.com", "wrongpassword", false, "", "192.0.2.1", "").Return((*models.LoginResponse)(nil), assert.AnError)
+				m.On("LoginWithSession", "user@example.com", "wrongpassword", false, "", "192.0.2.1", "", "").Return((*models.LoginResponse)(nil), assert.AnError)
 			},
 			expectedStatus: http.StatusUnauthorized,
 			expectedError:  true,
 		},
+		{
+			name: "password login disabled",
+			requestBody: models.LoginRequest{
+				Email:    "user@example.com",
+				Password: "password123",
+			},
+			mockSetup: func(m *testutils.MockAuthService) {
+				m.On("LoginWithSession", "user@example.com", "password123", false, "", "192.0.2.1", "", "").
+					Return((*models.LoginResponse)(nil), fmt.Errorf("password login: %w", services.ErrLoginMethodDisabled))
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -124,6 +142,30 @@ func TestAuthHandler_Login(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Login_PassesCurrentSessionToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(testutils.MockAuthService)
+	response := &models.LoginResponse{Token: "new.jwt.token", UserType: "user", ExpiresAt: models.NewTimestamp(time.Now().Add(time.Hour))}
+	mockService.On("LoginWithSession", "user@example.com", "password123", false, "", "192.0.2.1", "", "previous.jwt.token").Return(response, nil)
+
+	cfg := testutils.TestConfig()
+	handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+	jsonBody, _ := json.Marshal(models.LoginRequest{Email: "user@example.com", Password: "password123"})
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer previous.jwt.token")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.Login(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestAuthHandler_RefreshToken(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -133,6 +175,7 @@ func TestAuthHandler_RefreshToken(t *testing.T) {
 		mockSetup      func(*testutils.MockAuthService)
 		expectedStatus int
 		expectedError  bool
+		expectedCode   string
 	}{
 		{
 			name:       "valid token refresh",
@@ -152,6 +195,16 @@ func TestAuthHandler_RefreshToken(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 			expectedError:  true,
 		},
+		{
+			name:       "token expired beyond the grace period",
+			authHeader: "Bearer expired.jwt.token",
+			mockSetup: func(m *testutils.MockAuthService) {
+				m.On("RefreshToken", "expired.jwt.token", false).Return("", fmt.Errorf("%w: %w", utils.ErrRefreshWindowExpired, utils.ErrTokenExpired))
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  true,
+			expectedCode:   middlewares.TokenErrorRefreshExpired,
+		},
 		{
 			name:           "missing authorization header",
 			authHeader:     "",
@@ -204,6 +257,9 @@ func TestAuthHandler_RefreshToken(t *testing.T) {
 				err := json.Unmarshal(w.Body.Bytes(), &response)
 				assert.NoError(t, err)
 				assert.Contains(t, response, "error")
+				if tt.expectedCode != "" {
+					assert.Equal(t, tt.expectedCode, response["code"])
+				}
 			} else {
 				var response map[string]interface{}
 				err := json.Unmarshal(w.Body.Bytes(), &response)
@@ -229,6 +285,7 @@ func TestAuthHandler_Logout(t *testing.T) {
 		Email:    "test@example.com",
 		Name:     "Test User",
 	}, nil)
+	mockService.On("LogoutSession", "valid.jwt.token").Return(nil)
 	cfg := testutils.TestConfig()
 	fileUploader := utils.NewFileUploader(&cfg.FileStorage)
 	handler := handlers.NewAuthHandler(mockService, fileUploader)
@@ -253,6 +310,7 @@ func TestAuthHandler_Logout(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, response, "message")
 	assert.Equal(t, "Logout successful", response["message"])
+	mockService.AssertExpectations(t)
 }
 
 func TestAuthHandler_GetProfile(t *testing.T) {
@@ -339,3 +397,330 @@ func TestAuthHandler_GetProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_GetProfile_ConditionalRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	updatedAt := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	permissions := []string{"users:read", "profile:update"}
+
+	getProfile := func(t *testing.T, updatedAt time.Time, permissions []string, headers map[string]string) *httptest.ResponseRecorder {
+		mockService := new(testutils.MockAuthService)
+		mockService.On("GetUserByID", 1).Return(&models.User{ID: 1, Name: "Test User", Email: "user@example.com", UpdatedAt: updatedAt.Add(250 * time.Millisecond)}, nil)
+		mockService.On("GetUserPermissionsByID", 1, "user").Return(permissions, nil)
+
+		cfg := testutils.TestConfig()
+		handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+		req := httptest.NewRequest("GET", "/profile", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Set("user", &utils.JWTClaims{UserID: 1, UserType: "user"})
+
+		handler.GetProfile(c)
+
+		mockService.AssertExpectations(t)
+		return w
+	}
+
+	first := getProfile(t, updatedAt, permissions, nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, updatedAt.Format(http.TimeFormat), first.Header().Get("Last-Modified"))
+
+	tests := []struct {
+		name           string
+		updatedAt      time.Time
+		permissions    []string
+		headers        map[string]string
+		expectedStatus int
+		changed        bool
+	}{
+		{
+			name:           "unchanged profile",
+			updatedAt:      updatedAt,
+			permissions:    permissions,
+			headers:        map[string]string{"If-None-Match": etag},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "permissions listed in another order",
+			updatedAt:      updatedAt,
+			permissions:    []string{"profile:update", "users:read"},
+			headers:        map[string]string{"If-None-Match": `W/"other", ` + etag},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "wildcard",
+			updatedAt:      updatedAt,
+			permissions:    permissions,
+			headers:        map[string]string{"If-None-Match": "*"},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "permission revoked",
+			updatedAt:      updatedAt,
+			permissions:    []string{"profile:update"},
+			headers:        map[string]string{"If-None-Match": etag},
+			expectedStatus: http.StatusOK,
+			changed:        true,
+		},
+		{
+			name:           "profile updated",
+			updatedAt:      updatedAt.Add(time.Minute),
+			permissions:    permissions,
+			headers:        map[string]string{"If-None-Match": etag},
+			expectedStatus: http.StatusOK,
+			changed:        true,
+		},
+		{
+			name:           "If-Modified-Since alone cannot see permission changes",
+			updatedAt:      updatedAt,
+			permissions:    []string{"profile:update"},
+			headers:        map[string]string{"If-Modified-Since": updatedAt.Add(time.Hour).Format(http.TimeFormat)},
+			expectedStatus: http.StatusOK,
+			changed:        true,
+		},
+		{
+			name:           "no conditional header",
+			updatedAt:      updatedAt,
+			permissions:    permissions,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getProfile(t, tt.updatedAt, tt.permissions, tt.headers)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.changed {
+				assert.NotEqual(t, etag, w.Header().Get("ETag"))
+			} else {
+				assert.Equal(t, etag, w.Header().Get("ETag"))
+			}
+			if tt.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestAuthHandler_IssueUserResetLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	admin := &models.Actor{ID: 1, Type: "admin"}
+	expiresAt := time.Now().Add(15 * time.Minute)
+
+	tests := []struct {
+		name           string
+		userID         string
+		mockSetup      func(*testutils.MockAuthService)
+		expectedStatus int
+	}{
+		{
+			name:   "link issued",
+			userID: "5",
+			mockSetup: func(m *testutils.MockAuthService) {
+				m.On("IssueUserResetLink", 5, admin).Return(&models.PasswordResetLinkResponse{
+					UserID:    5,
+					ResetLink: "http://localhost:3000/reset-password?token=abc&email=user@example.com",
+					ExpiresAt: models.NewTimestamp(expiresAt),
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "user not found",
+			userID: "99",
+			mockSetup: func(m *testutils.MockAuthService) {
+				m.On("IssueUserResetLink", 99, admin).Return(nil, repositories.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid user ID",
+			userID:         "abc",
+			mockSetup:      func(m *testutils.MockAuthService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(testutils.MockAuthService)
+			tt.mockSetup(mockService)
+
+			cfg := testutils.TestConfig()
+			handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/users/"+tt.userID+"/reset-token", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.userID}}
+			c.Set("user", &utils.JWTClaims{UserID: admin.ID, UserType: admin.Type})
+
+			handler.IssueUserResetLink(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+				assert.Contains(t, w.Body.String(), "reset_link")
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_GetSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	claims := &utils.JWTClaims{UserID: 1, UserType: "user", Email: "user@example.com"}
+
+	tests := []struct {
+		name          string
+		sessions      []models.SessionResponse
+		expectedCount int
+	}{
+		{
+			name: "user with two sessions",
+			sessions: []models.SessionResponse{
+				{ID: 7, UserID: 1, UserType: "user", IsActive: true},
+				{ID: 8, UserID: 1, UserType: "user", IsActive: true},
+			},
+			expectedCount: 2,
+		},
+		{
+			name:          "user with none",
+			sessions:      []models.SessionResponse{},
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(testutils.MockAuthService)
+			mockService.On("GetActiveSessions", 1, "user").Return(tt.sessions, nil)
+			cfg := testutils.TestConfig()
+			handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/profile/sessions", nil)
+			c.Set("user", claims)
+			c.Set("session", &models.UserSession{ID: 8, SessionToken: "secret-token"})
+
+			handler.GetSessions(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotContains(t, w.Body.String(), "secret-token")
+
+			var response struct {
+				Data []models.SessionResponse `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.NotNil(t, response.Data)
+			assert.Len(t, response.Data, tt.expectedCount)
+			for _, session := range response.Data {
+				assert.Equal(t, session.ID == 8, session.IsCurrent)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_RevokeOtherSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	claims := &utils.JWTClaims{UserID: 1, UserType: "user", Email: "user@example.com"}
+
+	t.Run("passes the current token through", func(t *testing.T) {
+		mockService := new(testutils.MockAuthService)
+		mockService.On("RevokeOtherSessions", 1, "user", "current.jwt.token").Return(nil)
+		cfg := testutils.TestConfig()
+		handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/profile/sessions/revoke-others", nil)
+		c.Request.Header.Set("Authorization", "Bearer current.jwt.token")
+		c.Set("user", claims)
+
+		handler.RevokeOtherSessions(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		mockService := new(testutils.MockAuthService)
+		cfg := testutils.TestConfig()
+		handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/profile/sessions/revoke-others", nil)
+		c.Set("user", claims)
+
+		handler.RevokeOtherSessions(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockService.AssertNotCalled(t, "RevokeOtherSessions", 1, "user", "")
+	})
+}
+
+func TestAuthHandler_SendEmailVerification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	claims := &utils.JWTClaims{UserID: 1, UserType: "user", Email: "user@example.com"}
+
+	send := func(expose bool) (*httptest.ResponseRecorder, map[string]interface{}) {
+		mockService := new(testutils.MockAuthService)
+		mockService.On("CheckEmailExists", "new@example.com").Return(false, nil)
+		mockService.On("SendEmailVerification", 1, "user", "new@example.com").Return("482915", nil)
+		cfg := testutils.TestConfig()
+		handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+		handler.SetExposeVerificationCodes(expose)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/profile/send-email-verification", bytes.NewBufferString(`{"new_email":"new@example.com"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user", claims)
+
+		handler.SendEmailVerification(c)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	t.Run("code is not returned by default", func(t *testing.T) {
+		w, response := send(false)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, response, "code")
+		assert.NotContains(t, w.Body.String(), "482915")
+	})
+
+	t.Run("code is returned when exposed for tests", func(t *testing.T) {
+		w, response := send(true)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "482915", response["code"])
+	})
+}
+
+func TestConfig_ExposeVerificationCodesActive(t *testing.T) {
+	cfg := testutils.TestConfig()
+	assert.True(t, cfg.ExposeVerificationCodesActive())
+
+	cfg.Server.GinMode = "release"
+	assert.False(t, cfg.ExposeVerificationCodesActive())
+
+	cfg.Server.GinMode = "test"
+	cfg.Server.ExposeVerificationCodes = false
+	assert.False(t, cfg.ExposeVerificationCodesActive())
+}
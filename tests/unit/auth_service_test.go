@@ -28,7 +28,7 @@ func TestAuthService_LoginUser(t *testing.T) {
 	notificationService := &testutils.MockNotificationService{}
 	permissionRepo := repositories.NewPermissionRepository(db)
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, nil, emailVerificationRepo, notificationService, permissionService, cfg)
 
 	// Create a test user with unique email
 	_ = testutils.CreateTestUser(t, db, "user1@example.com", "password123", "Test User 1")
@@ -118,7 +118,7 @@ func TestAuthService_LoginAdmin(t *testing.T) {
 	notificationService := &testutils.MockNotificationService{}
 	permissionRepo := repositories.NewPermissionRepository(db)
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, nil, emailVerificationRepo, notificationService, permissionService, cfg)
 
 	// Create a test admin with unique email
 	_ = testutils.CreateTestAdmin(t, db, "admin1@example.com", "admin123", "Test Admin 1")
@@ -208,7 +208,7 @@ func TestAuthService_Login_Unified(t *testing.T) {
 	notificationService := &testutils.MockNotificationService{}
 	permissionRepo := repositories.NewPermissionRepository(db)
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, nil, emailVerificationRepo, notificationService, permissionService, cfg)
 
 	// Create test user and admin with unique emails
 	user := testutils.CreateTestUser(t, db, "user2@example.com", "password123", "Test User 2")
@@ -308,7 +308,7 @@ func TestAuthService_ValidateToken(t *testing.T) {
 	notificationService := &testutils.MockNotificationService{}
 	permissionRepo := repositories.NewPermissionRepository(db)
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, nil, emailVerificationRepo, notificationService, permissionService, cfg)
 
 	// Create a test user and get a valid token
 	testUser := testutils.CreateTestUser(t, db, "user3@example.com", "password123", "Test User 3")
@@ -382,7 +382,7 @@ func TestAuthService_RefreshToken(t *testing.T) {
 	notificationService := &testutils.MockNotificationService{}
 	permissionRepo := repositories.NewPermissionRepository(db)
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, nil, emailVerificationRepo, notificationService, permissionService, cfg)
 
 	// Create a test user and get a valid token
 	testUser := testutils.CreateTestUser(t, db, "user4@example.com", "password123", "Test User 4")
@@ -452,7 +452,7 @@ func TestAuthService_GetUserFromToken(t *testing.T) {
 	notificationService := &testutils.MockNotificationService{}
 	permissionRepo := repositories.NewPermissionRepository(db)
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, nil, emailVerificationRepo, notificationService, permissionService, cfg)
 
 	// Create a test user and get a valid token
 	testUser := testutils.CreateTestUser(t, db, "user5@example.com", "password123", "Test User 5")
@@ -521,7 +521,7 @@ func TestAuthService_UserTypeDetection(t *testing.T) {
 	notificationService := &testutils.MockNotificationService{}
 	permissionRepo := repositories.NewPermissionRepository(db)
 	permissionService := services.NewPermissionService(permissionRepo, db)
-	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, emailVerificationRepo, notificationService, permissionService, cfg)
+	authService := services.NewAuthService(userRepo, adminRepo, gamenetRepo, passwordResetRepo, sessionRepo, nil, emailVerificationRepo, notificationService, permissionService, cfg)
 
 	// Create test user and admin with unique emails
 	_ = testutils.CreateTestUser(t, db, "user6@example.com", "password123", "Test User 6")
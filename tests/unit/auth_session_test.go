@@ -0,0 +1,556 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// authServiceMocks groups the mocked dependencies of an AuthService
+type authServiceMocks struct {
+	userRepo          *MockUserRepository
+	adminRepo         *testutils.MockAdminRepository
+	gamenetRepo       *testutils.MockGamenetRepository
+	sessionRepo       *testutils.MockSessionRepository
+	loginAuditRepo    *testutils.MockLoginAuditRepository
+	permissionService *testutils.MockPermissionService
+}
+
+// newMockedAuthService creates an AuthService backed entirely by mocks
+func newMockedAuthService() (*services.AuthService, *authServiceMocks) {
+	m := &authServiceMocks{
+		userRepo:          new(MockUserRepository),
+		adminRepo:         new(testutils.MockAdminRepository),
+		gamenetRepo:       new(testutils.MockGamenetRepository),
+		sessionRepo:       new(testutils.MockSessionRepository),
+		loginAuditRepo:    new(testutils.MockLoginAuditRepository),
+		permissionService: new(testutils.MockPermissionService),
+	}
+
+	authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, nil, m.sessionRepo, m.loginAuditRepo, nil, nil, m.permissionService, testutils.TestConfig())
+	return authService, m
+}
+
+// expectUserLogin sets up the mocks for a successful user login
+func (m *authServiceMocks) expectUserLogin(user *models.User) {
+	m.userRepo.On("GetByEmail", user.Email).Return(user, nil)
+	m.userRepo.On("UpdateLastLogin", user.ID).Return(nil)
+	m.permissionService.On("GetUserPermissionsByID", user.ID, "user").Return([]string{}, nil)
+	m.loginAuditRepo.On("RecordSuccessfulLogin", user.ID, "user", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+func TestAuthService_LoginWithSession_RenewsPresentedSession(t *testing.T) {
+	authService, m := newMockedAuthService()
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	m.expectUserLogin(user)
+
+	m.sessionRepo.On("GetSessionByToken", "previous-token").Return(&models.UserSession{ID: 7, UserID: user.ID, UserType: "user", IsActive: true}, nil)
+	m.sessionRepo.On("RenewSession", 7, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+
+	response, err := authService.LoginWithSession(user.Email, "password123", false, "iPhone 15", "10.0.0.2", "Safari", "previous-token")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	m.sessionRepo.AssertExpectations(t)
+	m.sessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_LoginWithSession_DoesNotReuseSessionsByDeviceInfo(t *testing.T) {
+	authService, m := newMockedAuthService()
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	m.expectUserLogin(user)
+
+	m.sessionRepo.On("CreateSession", user.ID, "user", mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).
+		Return(&models.UserSession{ID: 8}, nil)
+
+	// Another phone of the same model sends the same device info but no token
+	response, err := authService.LoginWithSession(user.Email, "password123", false, "iPhone 15", "10.0.0.1", "Safari", "")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	m.sessionRepo.AssertExpectations(t)
+	m.sessionRepo.AssertNotCalled(t, "RenewSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	m.loginAuditRepo.AssertCalled(t, "RecordSuccessfulLogin", user.ID, "user", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_LoginWithSession_IgnoresUnusablePresentedSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		session *models.UserSession
+	}{
+		{name: "unknown token", session: nil},
+		{name: "ended session", session: &models.UserSession{ID: 7, UserID: 1, UserType: "user", IsActive: false}},
+		{name: "another account's session", session: &models.UserSession{ID: 7, UserID: 2, UserType: "user", IsActive: true}},
+		{name: "same id of another account type", session: &models.UserSession{ID: 7, UserID: 1, UserType: "admin", IsActive: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService, m := newMockedAuthService()
+			user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+			m.expectUserLogin(user)
+
+			m.sessionRepo.On("GetSessionByToken", "previous-token").Return(tt.session, nil)
+			m.sessionRepo.On("CreateSession", user.ID, "user", mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).
+				Return(&models.UserSession{ID: 8}, nil)
+
+			_, err := authService.LoginWithSession(user.Email, "password123", false, "", "10.0.0.1", "Safari", "previous-token")
+
+			assert.NoError(t, err)
+			m.sessionRepo.AssertExpectations(t)
+			m.sessionRepo.AssertNotCalled(t, "RenewSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestAuthService_LoginWithSession_CreatesSessionWhenRenewalFails(t *testing.T) {
+	authService, m := newMockedAuthService()
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	m.expectUserLogin(user)
+
+	m.sessionRepo.On("GetSessionByToken", "previous-token").Return(&models.UserSession{ID: 7, UserID: user.ID, UserType: "user", IsActive: true}, nil)
+	// The session ended between the lookup and the renewal
+	m.sessionRepo.On("RenewSession", 7, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).Return(repositories.ErrNotFound)
+	m.sessionRepo.On("CreateSession", user.ID, "user", mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).
+		Return(&models.UserSession{ID: 8}, nil)
+
+	_, err := authService.LoginWithSession(user.Email, "password123", false, "", "10.0.0.1", "Safari", "previous-token")
+
+	assert.NoError(t, err)
+	m.sessionRepo.AssertExpectations(t)
+}
+
+func TestAuthService_LoginWithSession_RecordsFailedLogin(t *testing.T) {
+	authService, m := newMockedAuthService()
+	email := "unknown@example.com"
+	m.userRepo.On("GetByEmail", email).Return(nil, repositories.ErrNotFound)
+	m.adminRepo.On("GetByEmail", email).Return(nil, repositories.ErrNotFound)
+	m.gamenetRepo.On("GetByEmail", email).Return(nil, repositories.ErrNotFound)
+	m.loginAuditRepo.On("RecordFailedLogin", email, mock.Anything, mock.Anything, mock.Anything, models.LoginFailureUnknownEmail).Return(nil)
+
+	response, err := authService.LoginWithSession(email, "wrong", false, "", "10.0.0.1", "Safari", "")
+
+	assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+	assert.Nil(t, response)
+	m.loginAuditRepo.AssertExpectations(t)
+	m.sessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_LoginWithSession_RejectsSuspendedUser(t *testing.T) {
+	authService, m := newMockedAuthService()
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	suspendedAt := time.Now().Add(-time.Hour)
+	user.SuspendedAt = &suspendedAt
+	m.userRepo.On("GetByEmail", user.Email).Return(user, nil)
+	m.loginAuditRepo.On("RecordFailedLogin", user.Email, mock.Anything, mock.Anything, mock.Anything, models.LoginFailureSuspended).Return(nil)
+
+	response, err := authService.LoginWithSession(user.Email, "password123", false, "", "10.0.0.1", "Safari", "")
+
+	assert.ErrorIs(t, err, services.ErrAccountSuspended)
+	assert.Nil(t, response)
+	m.userRepo.AssertNotCalled(t, "UpdateLastLogin", mock.Anything)
+	m.sessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	m.loginAuditRepo.AssertExpectations(t)
+}
+
+func TestAuthService_LoginWithSession_RecordsFailureReason(t *testing.T) {
+	t.Run("wrong password", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+		m.userRepo.On("GetByEmail", user.Email).Return(user, nil)
+		m.adminRepo.On("GetByEmail", user.Email).Return(nil, repositories.ErrNotFound)
+		m.gamenetRepo.On("GetByEmail", user.Email).Return(nil, repositories.ErrNotFound)
+		m.loginAuditRepo.On("RecordFailedLogin", user.Email, mock.Anything, mock.Anything, mock.Anything, models.LoginFailureWrongPassword).Return(nil)
+
+		_, err := authService.LoginWithSession(user.Email, "wrong-password", false, "", "10.0.0.1", "Safari", "")
+
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+		assert.Equal(t, "invalid credentials", err.Error())
+		m.loginAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("login method disabled", func(t *testing.T) {
+		_, m := newMockedAuthService()
+		cfg := testutils.TestConfig()
+		cfg.Security.EnablePasswordLogin = false
+		authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, nil, m.sessionRepo, m.loginAuditRepo, nil, nil, m.permissionService, cfg)
+		m.loginAuditRepo.On("RecordFailedLogin", "user@example.com", mock.Anything, mock.Anything, mock.Anything, models.LoginFailureMethodDisabled).Return(nil)
+
+		_, err := authService.LoginWithSession("user@example.com", "password123", false, "", "10.0.0.1", "Safari", "")
+
+		assert.ErrorIs(t, err, services.ErrLoginMethodDisabled)
+		m.loginAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("attempted email is normalized and truncated", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		typed := "  " + strings.Repeat("A", 300) + "@Example.com "
+		m.userRepo.On("GetByEmail", typed).Return(nil, repositories.ErrNotFound)
+		m.adminRepo.On("GetByEmail", typed).Return(nil, repositories.ErrNotFound)
+		m.gamenetRepo.On("GetByEmail", typed).Return(nil, repositories.ErrNotFound)
+		m.loginAuditRepo.On("RecordFailedLogin", strings.Repeat("a", 255), mock.Anything, mock.Anything, mock.Anything, models.LoginFailureUnknownEmail).Return(nil)
+
+		_, err := authService.LoginWithSession(typed, "password123", false, "", "10.0.0.1", "Safari", "")
+
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+		m.loginAuditRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_CheckEmailExists(t *testing.T) {
+	email := "someone@example.com"
+
+	t.Run("not found in any table", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.userRepo.On("GetByEmailIncludingDeleted", email).Return(nil, repositories.ErrNotFound)
+		m.adminRepo.On("GetByEmail", email).Return(nil, repositories.ErrNotFound)
+		m.gamenetRepo.On("GetByEmail", email).Return(nil, repositories.ErrNotFound)
+
+		exists, err := authService.CheckEmailExists(email)
+
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("found in admins", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.userRepo.On("GetByEmailIncludingDeleted", email).Return(nil, repositories.ErrNotFound)
+		m.adminRepo.On("GetByEmail", email).Return(&models.Admin{ID: 1, Email: email}, nil)
+
+		exists, err := authService.CheckEmailExists(email)
+
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("found among deleted users", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		deletedAt := time.Now()
+		m.userRepo.On("GetByEmailIncludingDeleted", email).Return(&models.User{ID: 4, Email: email, DeletedAt: &deletedAt}, nil)
+
+		exists, err := authService.CheckEmailExists(email)
+
+		assert.NoError(t, err)
+		assert.True(t, exists)
+		m.adminRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	})
+
+	t.Run("database errors are not treated as not found", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.userRepo.On("GetByEmailIncludingDeleted", email).Return(nil, errors.New("connection refused"))
+
+		exists, err := authService.CheckEmailExists(email)
+
+		assert.Error(t, err)
+		assert.False(t, exists)
+		m.adminRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	})
+}
+
+func TestAuthService_LookupErrorsPropagate(t *testing.T) {
+	dbErr := errors.New("connection refused")
+
+	t.Run("login reports the database error instead of invalid credentials", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.userRepo.On("GetByEmail", "user@example.com").Return(nil, repositories.ErrNotFound)
+		m.adminRepo.On("GetByEmail", "user@example.com").Return(nil, dbErr)
+
+		response, err := authService.Login("user@example.com", "password123", false)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, dbErr)
+		assert.NotErrorIs(t, err, services.ErrInvalidCredentials)
+		m.gamenetRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	})
+
+	t.Run("forgot password reports the database error instead of email not found", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.userRepo.On("GetByEmail", "user@example.com").Return(nil, dbErr)
+
+		err := authService.ForgotPassword("user@example.com")
+
+		assert.ErrorIs(t, err, dbErr)
+		m.adminRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	})
+}
+
+func TestAuthService_LoginMethodFlags(t *testing.T) {
+	newService := func(passwordLogin bool) (*services.AuthService, *authServiceMocks) {
+		_, m := newMockedAuthService()
+		cfg := testutils.TestConfig()
+		cfg.Security.EnablePasswordLogin = passwordLogin
+		authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, nil, m.sessionRepo, m.loginAuditRepo, nil, nil, m.permissionService, cfg)
+		return authService, m
+	}
+
+	t.Run("password login disabled", func(t *testing.T) {
+		authService, m := newService(false)
+		m.loginAuditRepo.On("RecordFailedLogin", "user@example.com", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		response, err := authService.LoginWithSession("user@example.com", "password123", false, "", "10.0.0.1", "", "")
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, services.ErrLoginMethodDisabled)
+		m.userRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	})
+
+	t.Run("password login enabled", func(t *testing.T) {
+		authService, m := newService(true)
+		user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+		m.expectUserLogin(user)
+
+		response, err := authService.Login(user.Email, "password123", false)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+	})
+
+	t.Run("enabled by default", func(t *testing.T) {
+		cfg := config.Load()
+
+		assert.True(t, cfg.LoginMethodEnabled(config.LoginMethodPassword))
+		assert.False(t, cfg.LoginMethodEnabled("otp"))
+	})
+}
+
+func TestAuthService_LogoutSession(t *testing.T) {
+	t.Run("deactivates the token's session", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.sessionRepo.On("GetSessionByToken", "token").Return(&models.UserSession{ID: 7, IsActive: true}, nil)
+		m.sessionRepo.On("DeactivateSession", 7).Return(nil)
+
+		assert.NoError(t, authService.LogoutSession("token"))
+		m.sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing session is not an error", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.sessionRepo.On("GetSessionByToken", "token").Return((*models.UserSession)(nil), nil)
+
+		assert.NoError(t, authService.LogoutSession("token"))
+		m.sessionRepo.AssertNotCalled(t, "DeactivateSession", mock.Anything)
+	})
+
+	t.Run("lookup failure is reported", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.sessionRepo.On("GetSessionByToken", "token").Return((*models.UserSession)(nil), errors.New("connection refused"))
+
+		assert.Error(t, authService.LogoutSession("token"))
+	})
+}
+
+func TestAuthService_GetActiveSessions(t *testing.T) {
+	t.Run("user with two sessions", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		phone, laptop := "iPhone 15", "MacBook"
+		m.sessionRepo.On("GetActiveSessionsByUserID", 1, "user").Return([]models.UserSession{
+			{ID: 7, UserID: 1, UserType: "user", SessionToken: "secret-token-1", DeviceInfo: &phone, IsActive: true},
+			{ID: 8, UserID: 1, UserType: "user", SessionToken: "secret-token-2", DeviceInfo: &laptop, IsActive: true},
+		}, nil)
+
+		sessions, err := authService.GetActiveSessions(1, "user")
+
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 2)
+		assert.Equal(t, 7, sessions[0].ID)
+		assert.Equal(t, &phone, sessions[0].DeviceInfo)
+		assert.Equal(t, &laptop, sessions[1].DeviceInfo)
+	})
+
+	t.Run("user with none", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.sessionRepo.On("GetActiveSessionsByUserID", 2, "user").Return([]models.UserSession{}, nil)
+
+		sessions, err := authService.GetActiveSessions(2, "user")
+
+		assert.NoError(t, err)
+		assert.NotNil(t, sessions)
+		assert.Empty(t, sessions)
+	})
+}
+
+func TestAuthService_RevokeOtherSessions(t *testing.T) {
+	t.Run("keeps the current session", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.sessionRepo.On("DeactivateAllOtherUserSessions", 1, "user", "current-token").Return(nil)
+
+		assert.NoError(t, authService.RevokeOtherSessions(1, "user", "current-token"))
+		m.sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository failure is reported", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.sessionRepo.On("DeactivateAllOtherUserSessions", 1, "user", "current-token").Return(errors.New("connection refused"))
+
+		assert.Error(t, authService.RevokeOtherSessions(1, "user", "current-token"))
+	})
+}
+
+func TestAuthService_ChangePassword_RevokesOtherSessions(t *testing.T) {
+	authService, m := newMockedAuthService()
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	m.userRepo.On("GetByID", user.ID).Return(user, nil)
+	m.userRepo.On("UpdatePassword", user.ID, mock.AnythingOfType("string")).Return(nil)
+	m.sessionRepo.On("DeactivateAllOtherUserSessions", user.ID, "user", "current-token").Return(nil)
+
+	err := authService.ChangePassword(user.ID, "user", "password123", "newpassword1", "newpassword1", "current-token")
+
+	assert.NoError(t, err)
+	m.sessionRepo.AssertExpectations(t)
+}
+
+// memorySessionRepository keeps sessions in memory so that a series of logins can be
+// checked end to end; methods it does not override fall through to the mock
+type memorySessionRepository struct {
+	*testutils.MockSessionRepository
+	sessions []models.UserSession
+}
+
+func (r *memorySessionRepository) CreateSession(userID int, userType, sessionToken string, deviceInfo, ipAddress, userAgent *string, expiresAt time.Time) (*models.UserSession, error) {
+	session := models.UserSession{
+		ID:           len(r.sessions) + 1,
+		UserID:       userID,
+		UserType:     userType,
+		SessionToken: sessionToken,
+		DeviceInfo:   deviceInfo,
+		IsActive:     true,
+		// Logins in a test run within the same second, so order them explicitly
+		CreatedAt: time.Now().Add(time.Duration(len(r.sessions)) * time.Minute),
+		ExpiresAt: expiresAt,
+	}
+	r.sessions = append(r.sessions, session)
+	return &session, nil
+}
+
+func (r *memorySessionRepository) GetActiveSessionsByUserID(userID int, userType string) ([]models.UserSession, error) {
+	var active []models.UserSession
+	// Newest first, like the database query
+	for i := len(r.sessions) - 1; i >= 0; i-- {
+		session := r.sessions[i]
+		if session.UserID == userID && session.UserType == userType && session.IsActive {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+func (r *memorySessionRepository) DeactivateSession(sessionID int) error {
+	r.sessions[sessionID-1].IsActive = false
+	return nil
+}
+
+func TestAuthService_LoginWithSession_MaxConcurrentSessions(t *testing.T) {
+	login := func(t *testing.T, maxSessions, logins int) *memorySessionRepository {
+		_, m := newMockedAuthService()
+		sessionRepo := &memorySessionRepository{MockSessionRepository: m.sessionRepo}
+		cfg := testutils.TestConfig()
+		cfg.Security.MaxConcurrentSessions = maxSessions
+		authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, nil, sessionRepo, m.loginAuditRepo, nil, nil, m.permissionService, cfg)
+
+		user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+		m.expectUserLogin(user)
+
+		for i := 1; i <= logins; i++ {
+			_, err := authService.LoginWithSession(user.Email, "password123", false, fmt.Sprintf("Device %d", i), "10.0.0.1", "Safari", "")
+			require.NoError(t, err)
+		}
+		return sessionRepo
+	}
+
+	t.Run("oldest session ends beyond the limit", func(t *testing.T) {
+		sessionRepo := login(t, 2, 3)
+
+		require.Len(t, sessionRepo.sessions, 3)
+		assert.False(t, sessionRepo.sessions[0].IsActive)
+		assert.True(t, sessionRepo.sessions[1].IsActive)
+		assert.True(t, sessionRepo.sessions[2].IsActive)
+	})
+
+	t.Run("zero means unlimited", func(t *testing.T) {
+		sessionRepo := login(t, 0, 3)
+
+		for _, session := range sessionRepo.sessions {
+			assert.True(t, session.IsActive)
+		}
+	})
+}
+
+func TestAuthService_LoginWithSession_RememberMeExpiry(t *testing.T) {
+	authService, m := newMockedAuthService()
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	m.expectUserLogin(user)
+
+	var sessionExpiresAt time.Time
+	m.sessionRepo.On("CreateSession", user.ID, "user", mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) { sessionExpiresAt = args.Get(6).(time.Time) }).
+		Return(&models.UserSession{ID: 1}, nil)
+
+	response, err := authService.LoginWithSession(user.Email, "password123", true, "", "", "", "")
+	require.NoError(t, err)
+
+	claims, err := authService.ValidateToken(response.Token)
+	require.NoError(t, err)
+
+	// The token, the response and the session row all expire after the remember me TTL
+	assert.Equal(t, 7*24*time.Hour, claims.ExpiresAt.Time.Sub(claims.IssuedAt.Time))
+	assert.True(t, response.ExpiresAt.Equal(claims.ExpiresAt.Time))
+	assert.True(t, sessionExpiresAt.Equal(claims.ExpiresAt.Time))
+}
+
+func TestSessionRepository_RenewSession(t *testing.T) {
+	t.Run("renews an active session", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnExec("UPDATE user_sessions", 1)
+
+		err := repositories.NewSessionRepository(db).RenewSession(7, "new-token", nil, nil, time.Now().Add(time.Hour))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("reports a session that is gone or ended", func(t *testing.T) {
+		db, _ := testutils.NewFakeDB(t)
+
+		err := repositories.NewSessionRepository(db).RenewSession(7, "new-token", nil, nil, time.Now().Add(time.Hour))
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestAuthService_UpdateUserProfile_RejectsTakenMobile(t *testing.T) {
+	newMobile := "09120000009"
+
+	t.Run("held by a deleted user", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.userRepo.On("GetByID", 1).Return(testutils.CreateMockUser(1, "user@example.com", "Test User"), nil)
+		deletedAt := time.Now()
+		m.userRepo.On("GetByMobileIncludingDeleted", newMobile).Return(&models.User{ID: 4, Mobile: newMobile, DeletedAt: &deletedAt}, nil)
+
+		_, err := authService.UpdateUserProfile(1, "Test User", newMobile, "")
+
+		assert.ErrorIs(t, err, services.ErrMobileInUse)
+		m.userRepo.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("free", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.userRepo.On("GetByID", 1).Return(testutils.CreateMockUser(1, "user@example.com", "Test User"), nil)
+		m.userRepo.On("GetByMobileIncludingDeleted", newMobile).Return(nil, repositories.ErrNotFound)
+		m.userRepo.On("UpdateProfile", 1, "Test User", newMobile, "").Return(nil)
+
+		response, err := authService.UpdateUserProfile(1, "Test User", newMobile, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, newMobile, response.Mobile)
+	})
+}
@@ -1,15 +1,20 @@
 package unit
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gatehide/gatehide-api/internal/utils"
 	testutils "github.com/gatehide/gatehide-api/tests/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestAuthMiddleware(t *testing.T) {
@@ -662,3 +667,109 @@ func TestGamenetMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddleware_TokenErrorCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := testutils.TestConfig()
+	jwtManager := utils.NewJWTManager(cfg)
+
+	valid, err := jwtManager.GenerateToken(1, "user", "user@example.com", "Test User", false)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "valid token", token: valid, expectedStatus: http.StatusOK},
+		{name: "expired token", token: signExpiredToken(t, cfg), expectedStatus: http.StatusUnauthorized, expectedCode: middlewares.TokenErrorExpired},
+		{name: "malformed token", token: "not.a.token", expectedStatus: http.StatusUnauthorized, expectedCode: middlewares.TokenErrorInvalid},
+		{name: "tampered token", token: tamperToken(valid), expectedStatus: http.StatusUnauthorized, expectedCode: middlewares.TokenErrorInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The mock hands back exactly what the real JWT manager returns
+			claims, validateErr := jwtManager.ValidateToken(tt.token)
+			mockService := new(testutils.MockAuthService)
+			mockService.On("ValidateToken", tt.token).Return(claims, validateErr)
+
+			router := gin.New()
+			router.Use(middlewares.AuthMiddleware(mockService))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedCode != "" {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCode, response["code"])
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareWithSession_TokenErrorCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := testutils.TestConfig()
+	jwtManager := utils.NewJWTManager(cfg)
+
+	valid, err := jwtManager.GenerateToken(1, "user", "user@example.com", "Test User", false)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "valid token", token: valid, expectedStatus: http.StatusOK},
+		{name: "expired token", token: signExpiredToken(t, cfg), expectedStatus: http.StatusUnauthorized, expectedCode: middlewares.TokenErrorExpired},
+		{name: "malformed token", token: "not.a.token", expectedStatus: http.StatusUnauthorized, expectedCode: middlewares.TokenErrorInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, validateErr := jwtManager.ValidateToken(tt.token)
+			mockService := new(testutils.MockAuthService)
+			mockService.On("ValidateToken", tt.token).Return(claims, validateErr)
+
+			sessionRepo := new(testutils.MockSessionRepository)
+			sessionRepo.On("GetSessionByToken", tt.token).Return(&models.UserSession{
+				ID:        7,
+				UserID:    1,
+				UserType:  "user",
+				IsActive:  true,
+				ExpiresAt: time.Now().Add(time.Hour),
+			}, nil)
+			sessionRepo.On("UpdateSessionActivity", 7).Return(nil)
+			sessionService := services.NewSessionService(sessionRepo, cfg)
+
+			router := gin.New()
+			router.Use(middlewares.AuthMiddlewareWithSession(mockService, sessionService))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedCode != "" {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedCode, response["code"])
+				sessionRepo.AssertNotCalled(t, "GetSessionByToken", mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/kavenegar/kavenegar-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationHandler_SendBulkSMS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(client *testutils.MockKavenegarClient, maxBulkSize int) *gin.Engine {
+		handler := handlers.NewNotificationHandler(nil, nil, nil, nil)
+		handler.SetSMSService(services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client), maxBulkSize)
+		router := gin.New()
+		router.POST("/notifications/sms/bulk", handler.SendBulkSMS)
+		return router
+	}
+
+	send := func(router *gin.Engine, body map[string]interface{}) *httptest.ResponseRecorder {
+		requestBody, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/notifications/sms/bulk", bytes.NewBuffer(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) models.BulkSMSResponse {
+		var response struct {
+			Data models.BulkSMSResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data
+	}
+
+	t.Run("sends a valid batch", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "10008663", mock.Anything, mock.Anything, mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 7}}, nil)
+
+		w := send(newRouter(client, 10), map[string]interface{}{
+			"messages": []map[string]string{
+				{"to": "09121111111", "message": "first"},
+				{"to": "09122222222", "message": "second"},
+			},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := decode(t, w)
+		assert.Equal(t, 2, response.Sent)
+		assert.Equal(t, 0, response.Failed)
+		require.Len(t, response.Results, 2)
+		assert.Equal(t, "09121111111", response.Results[0].To)
+		assert.Equal(t, 7, response.Results[0].MessageID)
+		client.AssertNumberOfCalls(t, "SendMessage", 2)
+	})
+
+	t.Run("sends one message to every recipient", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "10008663", mock.Anything, "hello", mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 7}}, nil)
+
+		w := send(newRouter(client, 10), map[string]interface{}{
+			"message":    "hello",
+			"recipients": []string{"09121111111", "09122222222", "09123333333"},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 3, decode(t, w).Sent)
+		client.AssertNumberOfCalls(t, "SendMessage", 3)
+	})
+
+	t.Run("reports an invalid number in the middle without sending it", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "10008663", mock.Anything, mock.Anything, mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 7}}, nil)
+
+		w := send(newRouter(client, 10), map[string]interface{}{
+			"message":    "hello",
+			"recipients": []string{"09121111111", "12345", "09123333333"},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		response := decode(t, w)
+		assert.Equal(t, 2, response.Sent)
+		assert.Equal(t, 1, response.Failed)
+		require.Len(t, response.Results, 3)
+		assert.Empty(t, response.Results[0].Error)
+		assert.Equal(t, "12345", response.Results[1].To)
+		assert.Contains(t, response.Results[1].Error, "invalid phone number")
+		assert.Empty(t, response.Results[2].Error)
+		assert.Equal(t, "09123333333", response.Results[2].To)
+		client.AssertNumberOfCalls(t, "SendMessage", 2)
+	})
+
+	t.Run("rejects a batch over the limit", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		recipients := make([]string, 3)
+		for i := range recipients {
+			recipients[i] = fmt.Sprintf("0912111111%d", i)
+		}
+
+		w := send(newRouter(client, 2), map[string]interface{}{
+			"message":    "hello",
+			"recipients": recipients,
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		client.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects mixing both forms", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+
+		w := send(newRouter(client, 10), map[string]interface{}{
+			"messages":   []map[string]string{{"to": "09121111111", "message": "first"}},
+			"message":    "hello",
+			"recipients": []string{"09122222222"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
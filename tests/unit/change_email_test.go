@@ -0,0 +1,224 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_ChangeAdminEmail(t *testing.T) {
+	newService := func() (*services.AuthService, *authServiceMocks, *testutils.MockNotificationService) {
+		m := &authServiceMocks{
+			userRepo:          new(MockUserRepository),
+			adminRepo:         new(testutils.MockAdminRepository),
+			gamenetRepo:       new(testutils.MockGamenetRepository),
+			sessionRepo:       new(testutils.MockSessionRepository),
+			loginAuditRepo:    new(testutils.MockLoginAuditRepository),
+			permissionService: new(testutils.MockPermissionService),
+		}
+		notificationService := new(testutils.MockNotificationService)
+
+		cfg := testutils.TestConfig()
+		cfg.App.FrontendBaseURL = "https://panel.example.com/"
+		authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, nil, m.sessionRepo, m.loginAuditRepo, nil, notificationService, m.permissionService, cfg)
+		return authService, m, notificationService
+	}
+
+	expectEmailFree := func(m *authServiceMocks, email string) {
+		m.userRepo.On("GetByEmailIncludingDeleted", email).Return(nil, repositories.ErrNotFound)
+		m.adminRepo.On("GetByEmail", email).Return(nil, repositories.ErrNotFound)
+		m.gamenetRepo.On("GetByEmail", email).Return(nil, repositories.ErrNotFound)
+	}
+
+	t.Run("changes the email and notifies the previous address", func(t *testing.T) {
+		authService, m, notificationService := newService()
+		m.adminRepo.On("GetByID", 3).Return(testutils.CreateMockAdmin(3, "admin@example.com", "Admin"), nil)
+		expectEmailFree(m, "new@example.com")
+		m.adminRepo.On("UpdateEmail", 3, "new@example.com").Return(nil).Once()
+		notificationService.On("EnqueueNotification", mock.Anything, mock.MatchedBy(func(n *models.CreateNotificationRequest) bool {
+			return n.Recipient == "admin@example.com" &&
+				n.TemplateData["new_email"] == "new@example.com" &&
+				n.TemplateData["support_link"] == "https://panel.example.com/support"
+		})).Return(nil).Once()
+
+		admin, err := authService.ChangeAdminEmail(3, "admin123", "new@example.com")
+
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", admin.Email)
+		m.adminRepo.AssertExpectations(t)
+		notificationService.AssertExpectations(t)
+	})
+
+	t.Run("a failed notification does not fail the change", func(t *testing.T) {
+		authService, m, notificationService := newService()
+		m.adminRepo.On("GetByID", 3).Return(testutils.CreateMockAdmin(3, "admin@example.com", "Admin"), nil)
+		expectEmailFree(m, "new@example.com")
+		m.adminRepo.On("UpdateEmail", 3, "new@example.com").Return(nil).Once()
+		notificationService.On("EnqueueNotification", mock.Anything, mock.Anything).Return(errors.New("queue full"))
+
+		admin, err := authService.ChangeAdminEmail(3, "admin123", "new@example.com")
+
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", admin.Email)
+	})
+
+	t.Run("rejects an incorrect password", func(t *testing.T) {
+		authService, m, _ := newService()
+		m.adminRepo.On("GetByID", 3).Return(testutils.CreateMockAdmin(3, "admin@example.com", "Admin"), nil)
+
+		admin, err := authService.ChangeAdminEmail(3, "wrong-password", "new@example.com")
+
+		assert.EqualError(t, err, "invalid password")
+		assert.Nil(t, admin)
+		m.adminRepo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects the current email", func(t *testing.T) {
+		authService, m, _ := newService()
+		m.adminRepo.On("GetByID", 3).Return(testutils.CreateMockAdmin(3, "admin@example.com", "Admin"), nil)
+
+		_, err := authService.ChangeAdminEmail(3, "admin123", "admin@example.com")
+
+		assert.EqualError(t, err, "new email must be different from current email")
+		m.adminRepo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an email used by another account", func(t *testing.T) {
+		authService, m, _ := newService()
+		m.adminRepo.On("GetByID", 3).Return(testutils.CreateMockAdmin(3, "admin@example.com", "Admin"), nil)
+		m.userRepo.On("GetByEmailIncludingDeleted", "taken@example.com").Return(testutils.CreateMockUser(1, "taken@example.com", "User"), nil)
+
+		_, err := authService.ChangeAdminEmail(3, "admin123", "taken@example.com")
+
+		assert.EqualError(t, err, "email already in use")
+		m.adminRepo.AssertNotCalled(t, "UpdateEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown admin", func(t *testing.T) {
+		authService, m, _ := newService()
+		m.adminRepo.On("GetByID", 3).Return(nil, repositories.ErrNotFound)
+
+		_, err := authService.ChangeAdminEmail(3, "admin123", "new@example.com")
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestAuthHandler_ChangeEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validBody := `{"current_password":"admin123","new_email":"new@example.com"}`
+
+	tests := []struct {
+		name           string
+		userType       string
+		body           string
+		serviceErr     error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "changes the email",
+			userType:       "admin",
+			body:           validBody,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "incorrect password",
+			userType:       "admin",
+			body:           validBody,
+			serviceErr:     errors.New("invalid password"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Current password is incorrect",
+		},
+		{
+			name:           "unchanged email",
+			userType:       "admin",
+			body:           validBody,
+			serviceErr:     errors.New("new email must be different from current email"),
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "New email must be different from current email",
+		},
+		{
+			name:           "email in use",
+			userType:       "admin",
+			body:           validBody,
+			serviceErr:     errors.New("email already in use"),
+			expectedStatus: http.StatusConflict,
+			expectedError:  "This email address is already in use",
+		},
+		{
+			name:           "service failure",
+			userType:       "admin",
+			body:           validBody,
+			serviceErr:     errors.New("failed to update admin email: database error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "Failed to update email",
+		},
+		{
+			name:           "invalid email",
+			userType:       "admin",
+			body:           `{"current_password":"admin123","new_email":"not-an-email"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request data",
+		},
+		{
+			name:           "missing password",
+			userType:       "admin",
+			body:           `{"new_email":"new@example.com"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request data",
+		},
+		{
+			name:           "non-admin account",
+			userType:       "user",
+			body:           validBody,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "Admin access required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(testutils.MockAuthService)
+			if tt.serviceErr != nil {
+				mockService.On("ChangeAdminEmail", 3, "admin123", "new@example.com").Return(nil, tt.serviceErr)
+			} else {
+				mockService.On("ChangeAdminEmail", 3, "admin123", "new@example.com").
+					Return(&models.AdminResponse{ID: 3, Email: "new@example.com"}, nil)
+			}
+			cfg := testutils.TestConfig()
+			handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/auth/change-email", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user", &utils.JWTClaims{UserID: 3, UserType: tt.userType})
+
+			handler.ChangeEmail(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			} else {
+				assert.Contains(t, w.Body.String(), "new@example.com")
+			}
+			if tt.expectedStatus == http.StatusForbidden || tt.expectedError == "Invalid request data" {
+				mockService.AssertNotCalled(t, "ChangeAdminEmail", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
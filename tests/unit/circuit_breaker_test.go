@@ -0,0 +1,134 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/kavenegar/kavenegar-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	breaker := utils.NewCircuitBreaker(3, 50*time.Millisecond)
+	assert.Equal(t, utils.CircuitClosed, breaker.State())
+
+	// Failures below the threshold keep the breaker closed
+	for i := 0; i < 2; i++ {
+		require.NoError(t, breaker.Allow())
+		breaker.Failure()
+	}
+	assert.Equal(t, utils.CircuitClosed, breaker.State())
+
+	// A success resets the consecutive failure count
+	require.NoError(t, breaker.Allow())
+	breaker.Success()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, breaker.Allow())
+		breaker.Failure()
+	}
+	assert.Equal(t, utils.CircuitClosed, breaker.State())
+
+	// The third consecutive failure opens it
+	require.NoError(t, breaker.Allow())
+	breaker.Failure()
+	assert.Equal(t, utils.CircuitOpen, breaker.State())
+	assert.ErrorIs(t, breaker.Allow(), utils.ErrCircuitOpen)
+
+	// After the timeout a single probe is let through
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, utils.CircuitHalfOpen, breaker.State())
+	require.NoError(t, breaker.Allow())
+	assert.ErrorIs(t, breaker.Allow(), utils.ErrCircuitOpen, "only one probe while half-open")
+
+	// A failed probe opens it again
+	breaker.Failure()
+	assert.Equal(t, utils.CircuitOpen, breaker.State())
+
+	// A successful probe closes it
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, breaker.Allow())
+	breaker.Success()
+	assert.Equal(t, utils.CircuitClosed, breaker.State())
+	assert.NoError(t, breaker.Allow())
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	breaker := utils.NewCircuitBreaker(0, time.Minute)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, breaker.Allow())
+		breaker.Failure()
+	}
+	assert.Equal(t, utils.CircuitClosed, breaker.State())
+}
+
+func TestSMSService_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	sms := &models.SMSNotification{To: "09123456789", Message: "Hello"}
+	providerDown := &kavenegar.HTTPError{Status: 503, Message: "service unavailable"}
+
+	newService := func(client *testutils.MockKavenegarClient) *services.SMSService {
+		cfg := newTestSMSConfig(services.SMSStrategySMSOnly)
+		cfg.CircuitBreaker.FailureThreshold = 2
+		cfg.CircuitBreaker.OpenSeconds = 1
+		return services.NewSMSServiceWithClient(cfg, client)
+	}
+
+	t.Run("opens after consecutive failures and fails fast", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, providerDown).Twice()
+		service := newService(client)
+
+		assert.Error(t, service.SendSMS(ctx, sms))
+		assert.Equal(t, utils.CircuitClosed, service.CircuitState())
+		assert.Error(t, service.SendSMS(ctx, sms))
+		assert.Equal(t, utils.CircuitOpen, service.CircuitState())
+
+		err := service.SendSMS(ctx, sms)
+		assert.ErrorIs(t, err, services.ErrSMSCircuitOpen)
+		client.AssertNumberOfCalls(t, "SendMessage", 2)
+	})
+
+	t.Run("half-opens and closes after a successful probe", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, providerDown).Twice()
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 42}}, nil)
+		service := newService(client)
+
+		assert.Error(t, service.SendSMS(ctx, sms))
+		assert.Error(t, service.SendSMS(ctx, sms))
+		assert.Equal(t, utils.CircuitOpen, service.CircuitState())
+
+		time.Sleep(1100 * time.Millisecond)
+		assert.Equal(t, utils.CircuitHalfOpen, service.CircuitState())
+
+		assert.NoError(t, service.SendSMS(ctx, sms))
+		assert.Equal(t, utils.CircuitClosed, service.CircuitState())
+		client.AssertNumberOfCalls(t, "SendMessage", 3)
+	})
+
+	t.Run("api errors do not open the breaker", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, &kavenegar.APIError{Status: 411, Message: "invalid receptor"})
+		service := newService(client)
+
+		for i := 0; i < 3; i++ {
+			err := service.SendSMS(ctx, sms)
+			assert.Error(t, err)
+			assert.False(t, errors.Is(err, services.ErrSMSCircuitOpen))
+		}
+		assert.Equal(t, utils.CircuitClosed, service.CircuitState())
+	})
+}
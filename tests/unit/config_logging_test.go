@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_LogEffective_RedactsSecrets(t *testing.T) {
+	cfg := testutils.TestConfig()
+	cfg.Security.APISecret = "api-secret-value-1234"
+	cfg.Security.JWTSecret = "jwt-secret-value-5678"
+	cfg.Database.Password = "db-password-value-9012"
+	cfg.Notification.Email.SMTPPass = "smtp-password-value-3456"
+	cfg.Notification.SMS.APIKey = "kavenegar-api-key-7890"
+	cfg.Notification.SMS.Enabled = true
+
+	var out bytes.Buffer
+	cfg.LogEffective(log.New(&out, "", 0))
+	logged := out.String()
+
+	secrets := []string{
+		cfg.Security.APISecret,
+		cfg.Security.JWTSecret,
+		cfg.Database.Password,
+		cfg.Notification.Email.SMTPPass,
+		cfg.Notification.SMS.APIKey,
+	}
+	for _, secret := range secrets {
+		assert.NotContains(t, logged, secret)
+		// Not even a recognisable fragment of the secret
+		assert.NotContains(t, logged, secret[:8])
+	}
+
+	assert.Contains(t, logged, "jwt_secret=***")
+	assert.Contains(t, logged, "api_key=***")
+	assert.Contains(t, logged, "password=***")
+	assert.Contains(t, logged, "host="+cfg.Database.Host)
+	assert.Contains(t, logged, "name="+cfg.Database.DBName)
+	assert.Contains(t, logged, "sms: enabled=true")
+}
+
+func TestConfig_LogEffective_UnsetSecretsAreEmpty(t *testing.T) {
+	cfg := testutils.TestConfig()
+	cfg.Database.Password = ""
+
+	var out bytes.Buffer
+	cfg.LogEffective(log.New(&out, "", 0))
+
+	assert.Contains(t, out.String(), "password= ")
+}
@@ -0,0 +1,62 @@
+package unit
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/config"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_DatabasePool(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv("DB_MAX_OPEN_CONNS", "")
+		t.Setenv("DB_MAX_IDLE_CONNS", "")
+		t.Setenv("DB_CONN_MAX_LIFETIME_SECONDS", "")
+
+		cfg := config.Load()
+
+		assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+		assert.Equal(t, 10, cfg.Database.MaxIdleConns)
+		assert.Equal(t, 300, cfg.Database.ConnMaxLifetimeSeconds)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Setenv("DB_MAX_OPEN_CONNS", "50")
+		t.Setenv("DB_MAX_IDLE_CONNS", "5")
+		t.Setenv("DB_CONN_MAX_LIFETIME_SECONDS", "60")
+
+		cfg := config.Load()
+
+		assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+		assert.Equal(t, 5, cfg.Database.MaxIdleConns)
+		assert.Equal(t, 60, cfg.Database.ConnMaxLifetimeSeconds)
+	})
+
+	t.Run("invalid values fall back to defaults", func(t *testing.T) {
+		t.Setenv("DB_MAX_OPEN_CONNS", "many")
+
+		cfg := config.Load()
+
+		assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+	})
+}
+
+func TestDatabaseConfig_ConfigurePool(t *testing.T) {
+	cfg := testutils.TestConfig()
+	cfg.Database.MaxOpenConns = 7
+	cfg.Database.MaxIdleConns = 3
+	cfg.Database.ConnMaxLifetimeSeconds = 120
+
+	// sql.Open does not connect, so no database is needed
+	db, err := sql.Open("mysql", cfg.GetDSN())
+	require.NoError(t, err)
+	defer db.Close()
+
+	cfg.Database.ConfigurePool(db)
+
+	assert.Equal(t, 7, db.Stats().MaxOpenConnections)
+}
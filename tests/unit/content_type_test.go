@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireJSONMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{name: "json body", method: "POST", path: "/items", contentType: "application/json", body: `{}`, expectedStatus: http.StatusOK},
+		{name: "json with charset", method: "PUT", path: "/items/1", contentType: "application/json; charset=utf-8", body: `{}`, expectedStatus: http.StatusOK},
+		{name: "form body", method: "POST", path: "/items", contentType: "application/x-www-form-urlencoded", body: "name=test", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "missing content type", method: "PATCH", path: "/items/1", body: `{}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "empty body", method: "POST", path: "/items", expectedStatus: http.StatusOK},
+		{name: "read request", method: "GET", path: "/items", contentType: "text/plain", body: "x", expectedStatus: http.StatusOK},
+		{name: "exempt upload route", method: "POST", path: "/upload", contentType: "multipart/form-data; boundary=x", body: "--x--", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middlewares.RequireJSON("/upload"))
+
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.GET("/items", ok)
+			router.POST("/items", ok)
+			router.PUT("/items/:id", ok)
+			router.PATCH("/items/:id", ok)
+			router.POST("/upload", ok)
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
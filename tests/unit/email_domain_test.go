@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDomainsFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestEmailDomainValidator_Validate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.txt")
+	writeDomainsFile(t, path, "# comment\nmailinator.com\n\nYopmail.com\n")
+
+	validator := utils.NewEmailDomainValidator(&config.EmailPolicyConfig{
+		BlockDisposable:       true,
+		DisposableDomainsFile: path,
+	})
+
+	tests := []struct {
+		name    string
+		email   string
+		wantErr bool
+	}{
+		{name: "regular domain", email: "user@example.com", wantErr: false},
+		{name: "disposable domain", email: "user@mailinator.com", wantErr: true},
+		{name: "case insensitive", email: "user@YOPMAIL.COM", wantErr: true},
+		{name: "subdomain of disposable domain", email: "user@mx.mailinator.com", wantErr: true},
+		{name: "similar but different domain", email: "user@notmailinator.com", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.email)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, utils.ErrDisposableEmail)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEmailDomainValidator_Disabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.txt")
+	writeDomainsFile(t, path, "mailinator.com\n")
+
+	validator := utils.NewEmailDomainValidator(&config.EmailPolicyConfig{
+		BlockDisposable:       false,
+		DisposableDomainsFile: path,
+	})
+
+	assert.NoError(t, validator.Validate("user@mailinator.com"))
+
+	// A nil validator is treated as disabled
+	var nilValidator *utils.EmailDomainValidator
+	assert.NoError(t, nilValidator.Validate("user@mailinator.com"))
+}
+
+func TestEmailDomainValidator_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.txt")
+	writeDomainsFile(t, path, "mailinator.com\n")
+
+	validator := utils.NewEmailDomainValidator(&config.EmailPolicyConfig{
+		BlockDisposable:       true,
+		DisposableDomainsFile: path,
+	})
+	assert.NoError(t, validator.Validate("user@tempmail.com"))
+
+	writeDomainsFile(t, path, "mailinator.com\ntempmail.com\n")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, future, future))
+
+	assert.ErrorIs(t, validator.Validate("user@tempmail.com"), utils.ErrDisposableEmail)
+}
@@ -0,0 +1,142 @@
+package unit
+
+import (
+	"database/sql/driver"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newVerificationAuthService creates an AuthService that sends email verification codes
+// with a 60 second resend cooldown
+func newVerificationAuthService() (*services.AuthService, *authServiceMocks, *testutils.MockEmailVerificationRepository, *testutils.MockNotificationService) {
+	m := &authServiceMocks{
+		userRepo:          new(MockUserRepository),
+		adminRepo:         new(testutils.MockAdminRepository),
+		gamenetRepo:       new(testutils.MockGamenetRepository),
+		sessionRepo:       new(testutils.MockSessionRepository),
+		loginAuditRepo:    new(testutils.MockLoginAuditRepository),
+		permissionService: new(testutils.MockPermissionService),
+	}
+	verificationRepo := new(testutils.MockEmailVerificationRepository)
+	notificationService := new(testutils.MockNotificationService)
+
+	cfg := testutils.TestConfig()
+	cfg.Security.EmailVerificationCooldownSeconds = 60
+
+	authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, nil, m.sessionRepo, m.loginAuditRepo, verificationRepo, notificationService, m.permissionService, cfg)
+	return authService, m, verificationRepo, notificationService
+}
+
+func TestAuthService_SendEmailVerification_Cooldown(t *testing.T) {
+	t.Run("rejects a request right after a code was sent", func(t *testing.T) {
+		authService, _, verificationRepo, notificationService := newVerificationAuthService()
+		verificationRepo.On("ReserveCode", 1, "user", "new@example.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), 60*time.Second).
+			Return(50*time.Second, nil)
+
+		code, err := authService.SendEmailVerification(1, "user", "new@example.com")
+
+		assert.Empty(t, code)
+		assert.ErrorIs(t, err, services.ErrVerificationCodeCooldown)
+		var tooMany *services.TooManyAttemptsError
+		require.ErrorAs(t, err, &tooMany)
+		assert.Equal(t, 50*time.Second, tooMany.RetryAfter)
+		notificationService.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+	})
+
+	t.Run("sends a new code once the cooldown has passed", func(t *testing.T) {
+		authService, m, verificationRepo, notificationService := newVerificationAuthService()
+		user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+		var stored string
+		verificationRepo.On("ReserveCode", user.ID, "user", "new@example.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), 60*time.Second).
+			Run(func(args mock.Arguments) { stored = args.String(3) }).
+			Return(time.Duration(0), nil)
+		m.userRepo.On("GetByID", user.ID).Return(user, nil)
+		notificationService.On("SendNotification", mock.Anything, mock.Anything).Return(nil)
+
+		code, err := authService.SendEmailVerification(user.ID, "user", "new@example.com")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, code)
+		assert.Equal(t, stored, code)
+		verificationRepo.AssertExpectations(t)
+		notificationService.AssertExpectations(t)
+	})
+}
+
+func TestEmailVerificationRepository_ReserveCode(t *testing.T) {
+	columns := []string{"last_sent_at"}
+
+	t.Run("stores the first code", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnQuery("FROM email_verification_sends", columns, []driver.Value{nil})
+		repo := repositories.NewEmailVerificationRepository(db)
+
+		wait, err := repo.ReserveCode(1, "user", "new@example.com", "123456", time.Now().Add(10*time.Minute), time.Minute)
+
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+		assert.True(t, fake.Ran("FOR UPDATE"))
+		assert.True(t, fake.Ran("UPDATE email_verification_sends SET last_sent_at"))
+		assert.True(t, fake.Ran("INSERT INTO email_verification_codes"))
+	})
+
+	t.Run("refuses within the cooldown without storing a code", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnQuery("FROM email_verification_sends", columns, []driver.Value{time.Now().Add(-10 * time.Second)})
+		repo := repositories.NewEmailVerificationRepository(db)
+
+		wait, err := repo.ReserveCode(1, "user", "new@example.com", "123456", time.Now().Add(10*time.Minute), time.Minute)
+
+		require.NoError(t, err)
+		assert.InDelta(t, (50 * time.Second).Seconds(), wait.Seconds(), 1)
+		assert.False(t, fake.Ran("UPDATE email_verification_sends SET last_sent_at"))
+		assert.False(t, fake.Ran("INSERT INTO email_verification_codes"))
+	})
+
+	t.Run("stores a code once the cooldown has passed", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnQuery("FROM email_verification_sends", columns, []driver.Value{time.Now().Add(-61 * time.Second)})
+		repo := repositories.NewEmailVerificationRepository(db)
+
+		wait, err := repo.ReserveCode(1, "user", "new@example.com", "123456", time.Now().Add(10*time.Minute), time.Minute)
+
+		require.NoError(t, err)
+		assert.Zero(t, wait)
+		assert.True(t, fake.Ran("INSERT INTO email_verification_codes"))
+	})
+}
+
+func TestAuthHandler_SendEmailVerification_RetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(testutils.MockAuthService)
+	mockService.On("CheckEmailExists", "new@example.com").Return(false, nil)
+	mockService.On("SendEmailVerification", 1, "user", "new@example.com").
+		Return("", &services.TooManyAttemptsError{RetryAfter: 49200 * time.Millisecond, Kind: services.ErrVerificationCodeCooldown})
+	cfg := testutils.TestConfig()
+	handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/auth/send-email-verification", strings.NewReader(`{"new_email":"new@example.com"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user", &utils.JWTClaims{UserID: 1, UserType: "user", Email: "user@example.com"})
+
+	handler.SendEmailVerification(c)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "50", w.Header().Get("Retry-After"))
+}
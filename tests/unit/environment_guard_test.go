@@ -0,0 +1,162 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/routes"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_IsProduction(t *testing.T) {
+	tests := []struct {
+		environment string
+		ginMode     string
+		expected    bool
+	}{
+		{"development", "debug", false},
+		{"staging", "debug", false},
+		{"production", "debug", true},
+		{"PROD", "debug", true},
+		{"", "release", true},
+		{"", "test", false},
+	}
+
+	for _, tt := range tests {
+		cfg := &config.Config{Server: config.ServerConfig{Environment: tt.environment, GinMode: tt.ginMode}}
+		assert.Equal(t, tt.expected, cfg.IsProduction(), "environment=%q gin mode=%q", tt.environment, tt.ginMode)
+	}
+}
+
+func TestConfig_GuardDestructive(t *testing.T) {
+	production := &config.Config{Server: config.ServerConfig{Environment: "production"}}
+	development := &config.Config{Server: config.ServerConfig{Environment: "development"}}
+
+	assert.ErrorIs(t, production.GuardDestructive("migrate down", false), config.ErrDestructiveInProduction)
+	assert.NoError(t, production.GuardDestructive("migrate down", true))
+	assert.NoError(t, development.GuardDestructive("migrate down", false))
+}
+
+func TestUserHandler_BulkDelete_ProductionGuard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(environment string, repo *MockUserRepository) *gin.Engine {
+		handler := handlers.NewUserHandler(services.NewUserService(repo, new(MockPermissionRepository), nil, nil, nil))
+		handler.SetConfig(&config.Config{Server: config.ServerConfig{Environment: environment}})
+
+		router := gin.New()
+		router.POST("/users/bulk-action", func(c *gin.Context) {
+			c.Set("user", &utils.JWTClaims{UserID: 1, UserType: "admin"})
+			c.Next()
+		}, handler.BulkAction)
+		return router
+	}
+
+	bulkAction := func(router *gin.Engine, action, confirmation string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{"user_ids": []int{1, 2}, "action": action})
+		req := httptest.NewRequest(http.MethodPost, "/users/bulk-action", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if confirmation != "" {
+			req.Header.Set(middlewares.DestructiveConfirmationHeader, confirmation)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	results := []models.UserBulkActionResult{{UserID: 1, Success: true}, {UserID: 2, Success: true}}
+
+	t.Run("delete refused in production without confirmation", func(t *testing.T) {
+		repo := new(MockUserRepository)
+
+		w := bulkAction(newRouter("production", repo), "delete", "")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		repo.AssertNotCalled(t, "ApplyBulkAction", "delete", []int{1, 2})
+	})
+
+	t.Run("wrong confirmation is refused", func(t *testing.T) {
+		repo := new(MockUserRepository)
+
+		w := bulkAction(newRouter("production", repo), "delete", "yes")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("confirmed delete runs in production", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("ApplyBulkAction", "delete", []int{1, 2}).Return(results, nil).Once()
+
+		w := bulkAction(newRouter("production", repo), "delete", config.DestructiveConfirmation)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("non-destructive actions are not guarded", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("ApplyBulkAction", "suspend", []int{1, 2}).Return(results, nil).Once()
+
+		w := bulkAction(newRouter("production", repo), "suspend", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("delete is not guarded outside production", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("ApplyBulkAction", "delete", []int{1, 2}).Return(results, nil).Once()
+
+		w := bulkAction(newRouter("development", repo), "delete", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestRoutes_DeletesRequireConfirmationInProduction(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	paths := []string{
+		"/api/v1/users/42",
+		"/api/v1/gamenets/42",
+		"/api/v1/subscription-plans/42",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			db, fake := testutils.NewFakeDB(t)
+			cfg := testutils.TestConfig()
+			cfg.Server.Environment = "production"
+			router := gin.New()
+			shutdown, _ := routes.SetupRoutes(router, cfg, db)
+			t.Cleanup(func() { shutdown(context.Background()) })
+			token := signInAs(t, fake, 1, "admin")
+			fake.OnQuery("FROM permissions p", []string{"count"}, []driver.Value{int64(1)})
+			fake.OnQuery("FROM user_roles ur", []string{"count"}, []driver.Value{int64(1)})
+
+			req := httptest.NewRequest(http.MethodDelete, path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), "Operation refused in production")
+			assert.False(t, fake.Ran("DELETE FROM"))
+			assert.False(t, fake.Ran("SET deleted_at"))
+		})
+	}
+}
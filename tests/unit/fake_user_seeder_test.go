@@ -0,0 +1,56 @@
+package unit
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/database/seeders"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGenerateFakeUsers(t *testing.T) {
+	users := seeders.GenerateFakeUsers(1200)
+	require.Len(t, users, 1200)
+
+	mobilePattern := regexp.MustCompile(`^09\d{9}$`)
+	mobiles := make(map[string]bool)
+	emails := make(map[string]bool)
+	for _, user := range users {
+		assert.NotEmpty(t, user.Name)
+		assert.Regexp(t, mobilePattern, user.Mobile)
+		assert.False(t, mobiles[user.Mobile], "duplicate mobile %s", user.Mobile)
+		assert.False(t, emails[user.Email], "duplicate email %s", user.Email)
+		mobiles[user.Mobile] = true
+		emails[user.Email] = true
+	}
+}
+
+func TestFakeUserSeeder_Seed(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	inserted, err := seeders.NewFakeUserSeederWithDB(db).Seed(10)
+	require.NoError(t, err)
+	assert.Equal(t, 10, inserted)
+
+	rows, err := db.Query("SELECT mobile, password FROM users WHERE email LIKE 'loadtest.%'")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	mobiles := make(map[string]bool)
+	for rows.Next() {
+		var mobile, password string
+		require.NoError(t, rows.Scan(&mobile, &password))
+		assert.False(t, mobiles[mobile], "duplicate mobile %s", mobile)
+		mobiles[mobile] = true
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(password), []byte(seeders.FakeUserPassword)))
+	}
+	require.NoError(t, rows.Err())
+	assert.Len(t, mobiles, 10)
+}
@@ -0,0 +1,80 @@
+package unit
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryPasswordResetRepository keeps the last created reset token in memory
+type memoryPasswordResetRepository struct {
+	token string
+}
+
+func (r *memoryPasswordResetRepository) CreateToken(userID int, userType, token string, expiresAt time.Time) error {
+	r.token = token
+	return nil
+}
+
+func (r *memoryPasswordResetRepository) GetTokenByToken(token string) (*models.PasswordResetToken, error) {
+	return nil, nil
+}
+
+func (r *memoryPasswordResetRepository) MarkTokenAsUsed(token string) error {
+	return nil
+}
+
+func (r *memoryPasswordResetRepository) CleanupExpiredTokens() error {
+	return nil
+}
+
+func (r *memoryPasswordResetRepository) GetActiveTokensForUser(userID int, userType string) ([]*models.PasswordResetToken, error) {
+	return nil, nil
+}
+
+func (r *memoryPasswordResetRepository) InvalidateUserTokens(userID int, userType string) error {
+	return nil
+}
+
+func TestFrontendLinks(t *testing.T) {
+	links := utils.NewFrontendLinks("https://app.example.com/")
+
+	t.Run("encodes the reset token and email", func(t *testing.T) {
+		link := links.ResetPassword("a+b/c=", "sara+test@example.com")
+
+		assert.Equal(t, "https://app.example.com/reset-password?token=a%2Bb%2Fc%3D&email=sara%2Btest%40example.com", link)
+		parsed, err := url.Parse(link)
+		require.NoError(t, err)
+		assert.Equal(t, "a+b/c=", parsed.Query().Get("token"))
+		assert.Equal(t, "sara+test@example.com", parsed.Query().Get("email"))
+	})
+
+	t.Run("builds the unsubscribe and support links", func(t *testing.T) {
+		assert.Equal(t, "https://app.example.com/unsubscribe?email=user%40example.com", links.Unsubscribe("user@example.com"))
+		assert.Equal(t, "https://app.example.com/unsubscribe", links.Unsubscribe(""))
+		assert.Equal(t, "https://app.example.com/support", links.Support())
+	})
+}
+
+func TestAuthService_IssueUserResetLink_UsesFrontendBaseURL(t *testing.T) {
+	_, m := newMockedAuthService()
+	resetRepo := &memoryPasswordResetRepository{}
+	cfg := testutils.TestConfig()
+	cfg.App.FrontendBaseURL = "https://staging.example.com"
+	authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, resetRepo, m.sessionRepo, m.loginAuditRepo, nil, nil, m.permissionService, cfg)
+
+	user := testutils.CreateMockUser(5, "user+1@example.com", "Test User")
+	m.userRepo.On("GetByID", user.ID).Return(user, nil)
+
+	response, err := authService.IssueUserResetLink(user.ID, &models.Actor{ID: 1, Type: "admin"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com/reset-password?token="+url.QueryEscape(resetRepo.token)+"&email=user%2B1%40example.com", response.ResetLink)
+}
@@ -0,0 +1,234 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newGamenetCreateRequest() *models.GamenetCreateRequest {
+	return &models.GamenetCreateRequest{
+		Name:        "Arena",
+		OwnerName:   "Owner",
+		OwnerMobile: "09121234567",
+		Address:     "Tehran",
+		Email:       "arena@example.com",
+	}
+}
+
+func TestGamenetService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hashes the password and sends credentials", func(t *testing.T) {
+		repo := new(testutils.MockGamenetRepository)
+		permissionRepo := new(MockPermissionRepository)
+		smsService := new(MockSMSService)
+		gamenetService := services.NewGamenetService(repo, permissionRepo, smsService, nil)
+
+		var created *models.Gamenet
+		repo.On("GetByEmail", "arena@example.com").Return(nil, repositories.ErrNotFound)
+		repo.On("Create", mock.AnythingOfType("*models.Gamenet")).
+			Run(func(args mock.Arguments) {
+				created = args.Get(0).(*models.Gamenet)
+				created.ID = 4
+			}).
+			Return(nil)
+		permissionRepo.On("AssignRoleToUser", 4, "gamenet", "gamenet").Return(nil)
+
+		var sentPassword string
+		smsService.On("SendGamenetCredentials", ctx, "09121234567", "arena@example.com", mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { sentPassword = args.String(3) }).
+			Return(nil)
+
+		gamenet, err := gamenetService.Create(ctx, newGamenetCreateRequest())
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, gamenet.ID)
+		assert.NotEmpty(t, sentPassword)
+		assert.NotEqual(t, sentPassword, string(created.Password))
+		assert.True(t, models.CheckPassword(sentPassword, string(created.Password)))
+		smsService.AssertExpectations(t)
+		permissionRepo.AssertExpectations(t)
+	})
+
+	t.Run("skips the SMS when asked to", func(t *testing.T) {
+		repo := new(testutils.MockGamenetRepository)
+		permissionRepo := new(MockPermissionRepository)
+		smsService := new(MockSMSService)
+		gamenetService := services.NewGamenetService(repo, permissionRepo, smsService, nil)
+
+		repo.On("GetByEmail", mock.Anything).Return(nil, repositories.ErrNotFound)
+		repo.On("Create", mock.Anything).Return(nil)
+		permissionRepo.On("AssignRoleToUser", mock.Anything, "gamenet", "gamenet").Return(nil)
+
+		req := newGamenetCreateRequest()
+		sendCredentials := false
+		req.SendCredentials = &sendCredentials
+
+		_, err := gamenetService.Create(ctx, req)
+
+		require.NoError(t, err)
+		smsService.AssertNotCalled(t, "SendGamenetCredentials", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a taken email", func(t *testing.T) {
+		repo := new(testutils.MockGamenetRepository)
+		gamenetService := services.NewGamenetService(repo, new(MockPermissionRepository), nil, nil)
+		repo.On("GetByEmail", "arena@example.com").Return(&models.Gamenet{ID: 1}, nil)
+
+		_, err := gamenetService.Create(ctx, newGamenetCreateRequest())
+
+		assert.ErrorIs(t, err, services.ErrGamenetEmailTaken)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("fails when the email lookup fails", func(t *testing.T) {
+		repo := new(testutils.MockGamenetRepository)
+		gamenetService := services.NewGamenetService(repo, new(MockPermissionRepository), nil, nil)
+		repo.On("GetByEmail", mock.Anything).Return(nil, errors.New("connection lost"))
+
+		_, err := gamenetService.Create(ctx, newGamenetCreateRequest())
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, services.ErrGamenetEmailTaken)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestGamenetService_UpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("updates an existing gamenet", func(t *testing.T) {
+		repo := new(testutils.MockGamenetRepository)
+		gamenetService := services.NewGamenetService(repo, new(MockPermissionRepository), nil, nil)
+		name := "Renamed Arena"
+		req := &models.GamenetUpdateRequest{Name: &name}
+		repo.On("GetByID", 4).Return(&models.Gamenet{ID: 4, Name: "Arena"}, nil).Once()
+		repo.On("Update", 4, req).Return(nil)
+		repo.On("GetByID", 4).Return(&models.Gamenet{ID: 4, Name: name}, nil).Once()
+
+		gamenet, err := gamenetService.Update(ctx, 4, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, name, gamenet.Name)
+	})
+
+	t.Run("does not delete an unknown gamenet", func(t *testing.T) {
+		repo := new(testutils.MockGamenetRepository)
+		gamenetService := services.NewGamenetService(repo, new(MockPermissionRepository), nil, nil)
+		repo.On("GetByID", 99).Return(nil, repositories.ErrNotFound)
+
+		err := gamenetService.Delete(ctx, 99)
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+		repo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestGamenetHandler_CreateGamenet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRequest := func(fields map[string]string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for name, value := range fields {
+			writer.WriteField(name, value)
+		}
+		writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/gamenets", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+	validFields := map[string]string{
+		"name":             "Arena",
+		"owner_name":       "Owner",
+		"owner_mobile":     "09121234567",
+		"address":          "Tehran",
+		"email":            "arena@example.com",
+		"send_credentials": "false",
+	}
+
+	tests := []struct {
+		name           string
+		fields         map[string]string
+		setup          func(repo *testutils.MockGamenetRepository, permissionRepo *MockPermissionRepository)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:   "creates the gamenet",
+			fields: validFields,
+			setup: func(repo *testutils.MockGamenetRepository, permissionRepo *MockPermissionRepository) {
+				repo.On("GetByEmail", "arena@example.com").Return(nil, repositories.ErrNotFound)
+				repo.On("Create", mock.AnythingOfType("*models.Gamenet")).Return(nil)
+				permissionRepo.On("AssignRoleToUser", mock.Anything, "gamenet", "gamenet").Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "taken email",
+			fields: validFields,
+			setup: func(repo *testutils.MockGamenetRepository, permissionRepo *MockPermissionRepository) {
+				repo.On("GetByEmail", "arena@example.com").Return(&models.Gamenet{ID: 1}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  services.ErrGamenetEmailTaken.Error(),
+		},
+		{
+			name:   "database failure",
+			fields: validFields,
+			setup: func(repo *testutils.MockGamenetRepository, permissionRepo *MockPermissionRepository) {
+				repo.On("GetByEmail", "arena@example.com").Return(nil, repositories.ErrNotFound)
+				repo.On("Create", mock.AnythingOfType("*models.Gamenet")).Return(errors.New("connection lost"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "Failed to create gamenet",
+		},
+		{
+			name:           "invalid email",
+			fields:         map[string]string{"name": "Arena", "owner_name": "Owner", "owner_mobile": "09121234567", "address": "Tehran", "email": "not-an-email"},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid request data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(testutils.MockGamenetRepository)
+			permissionRepo := new(MockPermissionRepository)
+			if tt.setup != nil {
+				tt.setup(repo, permissionRepo)
+			}
+			cfg := testutils.TestConfig()
+			handler := handlers.NewGamenetHandler(services.NewGamenetService(repo, permissionRepo, nil, nil), utils.NewFileUploader(&cfg.FileStorage))
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = newRequest(tt.fields)
+
+			handler.CreateGamenet(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			} else {
+				assert.Contains(t, w.Body.String(), "arena@example.com")
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPinger is a DatabasePinger returning a fixed error
+type stubPinger struct {
+	err error
+}
+
+func (p stubPinger) PingContext(ctx context.Context) error {
+	return p.err
+}
+
+func TestNotificationService_TestEmailConnection(t *testing.T) {
+	t.Run("relay reachable", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		emailService.On("TestConnection", mock.Anything).Return(nil)
+		cfg := testutils.TestConfig()
+		cfg.Notification.Email.Enabled = true
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, nil, nil, cfg)
+
+		assert.NoError(t, service.TestEmailConnection(context.Background()))
+		emailService.AssertExpectations(t)
+	})
+
+	t.Run("relay failure", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		emailService.On("TestConnection", mock.Anything).Return(errors.New("SMTP authentication failed"))
+		cfg := testutils.TestConfig()
+		cfg.Notification.Email.Enabled = true
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, nil, nil, cfg)
+		err := service.TestEmailConnection(context.Background())
+
+		assert.EqualError(t, err, "SMTP authentication failed")
+		assert.NotErrorIs(t, err, services.ErrTransportDisabled)
+	})
+
+	t.Run("email disabled skips the relay", func(t *testing.T) {
+		emailService := new(MockEmailService)
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, nil, nil, testutils.TestConfig())
+		err := service.TestEmailConnection(context.Background())
+
+		assert.ErrorIs(t, err, services.ErrTransportDisabled)
+		emailService.AssertNotCalled(t, "TestConnection", mock.Anything)
+	})
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		dbErr          error
+		smsErr         error
+		emailErr       error
+		expectedStatus int
+		expectedReady  string
+		expectedEmail  string
+	}{
+		{
+			name:           "all dependencies up",
+			expectedStatus: http.StatusOK,
+			expectedReady:  models.ReadinessReady,
+			expectedEmail:  models.DependencyUp,
+		},
+		{
+			name:           "smtp failure is reported without failing the probe",
+			emailErr:       errors.New("failed to connect to SMTP server smtp.internal:587: connection refused"),
+			expectedStatus: http.StatusOK,
+			expectedReady:  models.ReadinessReady,
+			expectedEmail:  models.DependencyDown,
+		},
+		{
+			name:           "disabled transports do not fail the probe",
+			smsErr:         fmt.Errorf("sms: %w", services.ErrTransportDisabled),
+			emailErr:       fmt.Errorf("email: %w", services.ErrTransportDisabled),
+			expectedStatus: http.StatusOK,
+			expectedReady:  models.ReadinessReady,
+			expectedEmail:  models.DependencyDisabled,
+		},
+		{
+			name:           "database failure fails the probe",
+			dbErr:          errors.New("dial tcp db.internal:3306: connection refused"),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReady:  models.ReadinessNotReady,
+			expectedEmail:  models.DependencyUp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifications := new(testutils.MockNotificationService)
+			notifications.On("TestSMSConnection", mock.Anything).Return(tt.smsErr)
+			notifications.On("TestEmailConnection", mock.Anything).Return(tt.emailErr)
+
+			handler := handlers.NewHealthHandler(testutils.TestConfig())
+			handler.SetReadinessChecks(stubPinger{err: tt.dbErr}, notifications)
+
+			router := gin.New()
+			router.GET("/health/ready", handler.Ready)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.NotContains(t, w.Body.String(), ".internal", "error details must not be exposed")
+
+			var response models.ReadinessResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedReady, response.Status)
+			assert.Equal(t, tt.expectedEmail, response.Checks["email"].Status)
+			assert.NotNil(t, response.Checks["email"].CheckedAt)
+			assert.Contains(t, response.Checks, "database")
+			assert.Contains(t, response.Checks, "sms")
+		})
+	}
+}
+
+func TestHealthHandler_Ready_CachesNotificationChecks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	notifications := new(testutils.MockNotificationService)
+	notifications.On("TestSMSConnection", mock.Anything).Return(nil)
+	notifications.On("TestEmailConnection", mock.Anything).Return(nil)
+
+	handler := handlers.NewHealthHandler(testutils.TestConfig())
+	handler.SetReadinessChecks(stubPinger{}, notifications)
+
+	router := gin.New()
+	router.GET("/health/ready", handler.Ready)
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	notifications.AssertNumberOfCalls(t, "TestSMSConnection", 1)
+	notifications.AssertNumberOfCalls(t, "TestEmailConnection", 1)
+}
+
+// stubCircuit is a CircuitStateReporter returning a fixed state
+type stubCircuit string
+
+func (c stubCircuit) CircuitState() string {
+	return string(c)
+}
+
+func TestHealthHandler_Ready_ReportsSMSCircuitState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	notifications := new(testutils.MockNotificationService)
+	notifications.On("TestSMSConnection", mock.Anything).Return(services.ErrSMSCircuitOpen)
+	notifications.On("TestEmailConnection", mock.Anything).Return(nil)
+
+	handler := handlers.NewHealthHandler(testutils.TestConfig())
+	handler.SetReadinessChecks(nil, notifications)
+	handler.SetSMSCircuit(stubCircuit(utils.CircuitOpen))
+
+	router := gin.New()
+	router.GET("/health/ready", handler.Ready)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+	router.ServeHTTP(w, req)
+
+	// An open SMS circuit is reported but doesn't fail the probe
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ReadinessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.DependencyDown, response.Checks["sms"].Status)
+	assert.Equal(t, utils.CircuitOpen, response.Checks["sms"].CircuitState)
+	assert.NotContains(t, response.Checks, "database")
+}
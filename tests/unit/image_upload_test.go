@@ -0,0 +1,181 @@
+package unit
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newImageFileHeader encodes a solid image of the given size and format and wraps it
+// in a multipart.FileHeader, as received from a form upload
+func newImageFileHeader(t *testing.T, filename, format string, width, height int) *multipart.FileHeader {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	var data bytes.Buffer
+	switch format {
+	case "jpeg":
+		require.NoError(t, jpeg.Encode(&data, img, nil))
+	case "png":
+		require.NoError(t, png.Encode(&data, img))
+	case "gif":
+		require.NoError(t, gif.Encode(&data, img, nil))
+	default:
+		data.WriteString("not an image")
+	}
+
+	return newMultipartFileHeader(t, filename, data.Bytes())
+}
+
+func newMultipartFileHeader(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(body.Len()) + 1024)
+	require.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+
+	return form.File["image"][0]
+}
+
+func newTestImageUploader(t *testing.T) *utils.FileUploader {
+	return utils.NewFileUploader(&config.FileStorageConfig{
+		UploadPath:   t.TempDir(),
+		MaxFileSize:  10 * 1024 * 1024,
+		AllowedTypes: []string{".jpg", ".jpeg", ".png", ".gif", ".pdf"},
+		PublicURL:    "http://localhost:8080",
+		Images: config.ImageConfig{
+			MaxWidth:      400,
+			MaxHeight:     400,
+			MinDimension:  32,
+			ThumbnailSize: 100,
+		},
+	})
+}
+
+// decodeStored decodes a stored image file and returns its format and size
+func decodeStored(t *testing.T, path string) (string, int, int) {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	cfg, format, err := image.DecodeConfig(f)
+	require.NoError(t, err)
+	return format, cfg.Width, cfg.Height
+}
+
+func TestFileUploader_UploadImage(t *testing.T) {
+	tests := []struct {
+		name                   string
+		filename               string
+		format                 string
+		width, height          int
+		expectedW, expectedH   int
+		expectedTW, expectedTH int
+	}{
+		{"large landscape jpeg is scaled down", "photo.jpg", "jpeg", 1600, 800, 400, 200, 100, 50},
+		{"large portrait png is scaled down", "avatar.png", "png", 300, 900, 133, 400, 33, 100},
+		{"image within bounds keeps its size", "small.png", "png", 200, 150, 200, 150, 100, 75},
+		{"gif keeps its format", "anim.gif", "gif", 500, 500, 400, 400, 100, 100},
+		{"minimum size is accepted", "tiny.jpg", "jpeg", 32, 32, 32, 32, 32, 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploader := newTestImageUploader(t)
+			file := newImageFileHeader(t, tt.filename, tt.format, tt.width, tt.height)
+
+			result, err := uploader.UploadImage(file, "profiles")
+			require.NoError(t, err)
+
+			format, w, h := decodeStored(t, result.Image.FilePath)
+			assert.Equal(t, tt.format, format)
+			assert.Equal(t, tt.expectedW, w)
+			assert.Equal(t, tt.expectedH, h)
+			assert.Equal(t, tt.expectedW, result.Width)
+			assert.Equal(t, tt.expectedH, result.Height)
+			assert.Contains(t, result.Image.PublicURL, "http://localhost:8080/uploads/profiles/")
+
+			require.NotNil(t, result.Thumbnail)
+			format, w, h = decodeStored(t, result.Thumbnail.FilePath)
+			assert.Equal(t, tt.format, format)
+			assert.Equal(t, tt.expectedTW, w)
+			assert.Equal(t, tt.expectedTH, h)
+			assert.Contains(t, result.Thumbnail.FileName, "_thumb")
+		})
+	}
+}
+
+func TestFileUploader_UploadImage_Rejections(t *testing.T) {
+	t.Run("below minimum dimension", func(t *testing.T) {
+		uploader := newTestImageUploader(t)
+		file := newImageFileHeader(t, "narrow.png", "png", 31, 200)
+
+		result, err := uploader.UploadImage(file, "profiles")
+
+		assert.ErrorIs(t, err, utils.ErrImageTooSmall)
+		assert.Nil(t, result)
+	})
+
+	t.Run("above maximum pixel count", func(t *testing.T) {
+		uploader := utils.NewFileUploader(&config.FileStorageConfig{
+			UploadPath:   t.TempDir(),
+			MaxFileSize:  10 * 1024 * 1024,
+			AllowedTypes: []string{".png"},
+			PublicURL:    "http://localhost:8080",
+			Images:       config.ImageConfig{MaxPixels: 100 * 100},
+		})
+		file := newImageFileHeader(t, "huge.png", "png", 101, 100)
+
+		result, err := uploader.UploadImage(file, "profiles")
+
+		assert.ErrorIs(t, err, utils.ErrImageTooLarge)
+		assert.Nil(t, result)
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		uploader := newTestImageUploader(t)
+		file := newImageFileHeader(t, "document.pdf", "pdf", 0, 0)
+
+		result, err := uploader.UploadImage(file, "profiles")
+
+		assert.ErrorIs(t, err, utils.ErrUnsupportedImage)
+		assert.Nil(t, result)
+	})
+
+	t.Run("thumbnails disabled", func(t *testing.T) {
+		uploader := utils.NewFileUploader(&config.FileStorageConfig{
+			UploadPath:   t.TempDir(),
+			MaxFileSize:  10 * 1024 * 1024,
+			AllowedTypes: []string{".png"},
+			PublicURL:    "http://localhost:8080",
+		})
+		file := newImageFileHeader(t, "avatar.png", "png", 2000, 1000)
+
+		result, err := uploader.UploadImage(file, "profiles")
+
+		require.NoError(t, err)
+		assert.Nil(t, result.Thumbnail)
+		assert.Equal(t, 2000, result.Width)
+	})
+}
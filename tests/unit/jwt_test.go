@@ -1,13 +1,44 @@
 package unit
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gatehide/gatehide-api/config"
 	"github.com/gatehide/gatehide-api/internal/utils"
 	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/golang-jwt/jwt/v5"
 )
 
+// signExpiredToken signs an access token with the configured secret that expired an hour ago
+func signExpiredToken(t *testing.T, cfg *config.Config) string {
+	t.Helper()
+	return signTokenExpiredAgo(t, cfg, time.Hour)
+}
+
+// signTokenExpiredAgo signs a one hour access token with the configured secret that expired ago
+func signTokenExpiredAgo(t *testing.T, cfg *config.Config, ago time.Duration) string {
+	t.Helper()
+	past := time.Now().Add(-ago - time.Hour)
+	claims := utils.JWTClaims{
+		UserID:   1,
+		UserType: "user",
+		Email:    "test@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(past.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(past),
+			NotBefore: jwt.NewNumericDate(past),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Security.JWTSecret))
+	if err != nil {
+		t.Fatalf("Failed to sign expired token: %v", err)
+	}
+	return token
+}
+
 func TestJWTManager_GenerateToken(t *testing.T) {
 	cfg := testutils.TestConfig()
 	jwtManager := utils.NewJWTManager(cfg)
@@ -187,7 +218,7 @@ func TestJWTManager_TokenExpiration(t *testing.T) {
 	}
 
 	// Check that expiration is set to approximately 1 hour from now
-	expectedExpiration := time.Now().Add(time.Duration(cfg.Security.JWTExpiration) * time.Hour)
+	expectedExpiration := time.Now().Add(time.Duration(cfg.Security.JWTExpirationHours) * time.Hour)
 	timeDiff := expectedExpiration.Sub(claims.ExpiresAt.Time)
 
 	// Allow 5 minutes tolerance
@@ -265,3 +296,230 @@ func TestJWTManager_TokenStructure(t *testing.T) {
 		t.Errorf("Subject mismatch: expected 123, got %s", claims.Subject)
 	}
 }
+
+func TestJWTManager_PerUserTypeExpiration(t *testing.T) {
+	cfg := testutils.TestConfig()
+	cfg.Security.JWTExpirationHours = 24
+	cfg.Security.AdminJWTExpiration = 2
+	cfg.Security.UserJWTExpiration = 48
+	cfg.Security.GamenetJWTExpiration = 0 // falls back to global value
+	jwtManager := utils.NewJWTManager(cfg)
+
+	tests := []struct {
+		userType string
+		expected time.Duration
+	}{
+		{userType: "admin", expected: 2 * time.Hour},
+		{userType: "user", expected: 48 * time.Hour},
+		{userType: "gamenet", expected: 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run("user_type_"+tt.userType, func(t *testing.T) {
+			if got := jwtManager.ExpirationFor(tt.userType, false); got != tt.expected {
+				t.Errorf("Expected expiration %v, got %v", tt.expected, got)
+			}
+
+			token, err := jwtManager.GenerateToken(1, tt.userType, "test@example.com", "Test User", false)
+			if err != nil {
+				t.Fatalf("Failed to generate token: %v", err)
+			}
+
+			claims, err := jwtManager.ValidateToken(token)
+			if err != nil {
+				t.Fatalf("Failed to validate token: %v", err)
+			}
+
+			lifetime := claims.ExpiresAt.Time.Sub(claims.IssuedAt.Time)
+			if lifetime != tt.expected {
+				t.Errorf("Expected token lifetime %v, got %v", tt.expected, lifetime)
+			}
+		})
+	}
+}
+
+func TestJWTManager_RefreshGracePeriod(t *testing.T) {
+	cfg := testutils.TestConfig()
+	cfg.Security.RefreshGracePeriodMinutes = 30
+	jwtManager := utils.NewJWTManager(cfg)
+
+	t.Run("fresh token", func(t *testing.T) {
+		token, err := jwtManager.GenerateToken(1, "user", "test@example.com", "Test User", false)
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+
+		if _, err := jwtManager.RefreshToken(token, false); err != nil {
+			t.Errorf("Expected fresh token to refresh, got %v", err)
+		}
+	})
+
+	t.Run("expired within grace", func(t *testing.T) {
+		refreshed, err := jwtManager.RefreshToken(signTokenExpiredAgo(t, cfg, 10*time.Minute), false)
+		if err != nil {
+			t.Fatalf("Expected token within grace to refresh, got %v", err)
+		}
+
+		// The new token is valid on its own, without the grace period
+		if _, err := jwtManager.ValidateToken(refreshed); err != nil {
+			t.Errorf("Refreshed token is invalid: %v", err)
+		}
+	})
+
+	t.Run("expired past grace", func(t *testing.T) {
+		_, err := jwtManager.RefreshToken(signTokenExpiredAgo(t, cfg, time.Hour), false)
+		if !errors.Is(err, utils.ErrRefreshWindowExpired) {
+			t.Errorf("Expected ErrRefreshWindowExpired, got %v", err)
+		}
+		if !errors.Is(err, utils.ErrTokenExpired) {
+			t.Errorf("Expected error to also match ErrTokenExpired, got %v", err)
+		}
+	})
+
+	t.Run("tampered token within grace", func(t *testing.T) {
+		_, err := jwtManager.RefreshToken(tamperToken(signTokenExpiredAgo(t, cfg, 10*time.Minute)), false)
+		if !errors.Is(err, utils.ErrTokenInvalid) {
+			t.Errorf("Expected ErrTokenInvalid, got %v", err)
+		}
+	})
+
+	t.Run("expired token is still rejected by validation", func(t *testing.T) {
+		_, err := jwtManager.ValidateToken(signTokenExpiredAgo(t, cfg, 10*time.Minute))
+		if !errors.Is(err, utils.ErrTokenExpired) {
+			t.Errorf("Expected ErrTokenExpired, got %v", err)
+		}
+	})
+}
+
+func TestJWTManager_RememberMeExpiration(t *testing.T) {
+	cfg := testutils.TestConfig()
+	cfg.Security.AdminJWTExpiration = 2
+	cfg.Security.RememberMeExpirationHours = 72
+	jwtManager := utils.NewJWTManager(cfg)
+
+	for _, userType := range []string{"user", "gamenet"} {
+		t.Run("user_type_"+userType, func(t *testing.T) {
+			token, expiresAt, err := jwtManager.GenerateTokenWithExpiry(1, userType, "test@example.com", "Test User", true)
+			if err != nil {
+				t.Fatalf("Failed to generate token: %v", err)
+			}
+
+			claims, err := jwtManager.ValidateToken(token)
+			if err != nil {
+				t.Fatalf("Failed to validate token: %v", err)
+			}
+
+			if lifetime := claims.ExpiresAt.Time.Sub(claims.IssuedAt.Time); lifetime != 72*time.Hour {
+				t.Errorf("Expected remember me token lifetime %v, got %v", 72*time.Hour, lifetime)
+			}
+			if !expiresAt.Equal(claims.ExpiresAt.Time) {
+				t.Errorf("Returned expiry %v does not match exp claim %v", expiresAt, claims.ExpiresAt.Time)
+			}
+		})
+	}
+
+	t.Run("admins keep the shorter admin lifetime", func(t *testing.T) {
+		token, err := jwtManager.GenerateToken(1, "admin", "admin@example.com", "Admin", true)
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+
+		claims, err := jwtManager.ValidateToken(token)
+		if err != nil {
+			t.Fatalf("Failed to validate token: %v", err)
+		}
+		if lifetime := claims.ExpiresAt.Time.Sub(claims.IssuedAt.Time); lifetime != 2*time.Hour {
+			t.Errorf("Expected admin token lifetime %v, got %v", 2*time.Hour, lifetime)
+		}
+
+		refreshed, err := jwtManager.RefreshToken(token, true)
+		if err != nil {
+			t.Fatalf("Failed to refresh token: %v", err)
+		}
+		claims, err = jwtManager.ValidateToken(refreshed)
+		if err != nil {
+			t.Fatalf("Failed to validate token: %v", err)
+		}
+		if lifetime := claims.ExpiresAt.Time.Sub(claims.IssuedAt.Time); lifetime != 2*time.Hour {
+			t.Errorf("Expected refreshed admin token lifetime %v, got %v", 2*time.Hour, lifetime)
+		}
+	})
+
+	t.Run("refresh keeps the remember me lifetime", func(t *testing.T) {
+		token, err := jwtManager.GenerateToken(1, "user", "test@example.com", "Test User", false)
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+
+		refreshed, err := jwtManager.RefreshToken(token, true)
+		if err != nil {
+			t.Fatalf("Failed to refresh token: %v", err)
+		}
+
+		claims, err := jwtManager.ValidateToken(refreshed)
+		if err != nil {
+			t.Fatalf("Failed to validate token: %v", err)
+		}
+		if lifetime := claims.ExpiresAt.Time.Sub(claims.IssuedAt.Time); lifetime != 72*time.Hour {
+			t.Errorf("Expected refreshed token lifetime %v, got %v", 72*time.Hour, lifetime)
+		}
+	})
+
+	t.Run("zero falls back to the normal lifetime", func(t *testing.T) {
+		cfg.Security.RememberMeExpirationHours = 0
+		if got := jwtManager.ExpirationFor("admin", true); got != 2*time.Hour {
+			t.Errorf("Expected remember me expiration %v, got %v", 2*time.Hour, got)
+		}
+	})
+}
+
+// tamperToken changes the first character of the token's signature
+func tamperToken(token string) string {
+	i := strings.LastIndex(token, ".") + 1
+	replacement := "A"
+	if token[i:i+1] == replacement {
+		replacement = "B"
+	}
+	return token[:i] + replacement + token[i+1:]
+}
+
+func TestJWTManager_ValidateToken_ErrorKinds(t *testing.T) {
+	cfg := testutils.TestConfig()
+	jwtManager := utils.NewJWTManager(cfg)
+
+	token, err := jwtManager.GenerateToken(1, "user", "test@example.com", "Test User", false)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	otherCfg := testutils.TestConfig()
+	otherCfg.Security.JWTSecret = "some-other-secret"
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid token", token: token},
+		{name: "expired token", token: signExpiredToken(t, cfg), wantErr: utils.ErrTokenExpired},
+		{name: "malformed token", token: "not.a.token", wantErr: utils.ErrTokenInvalid},
+		{name: "tampered token", token: tamperToken(token), wantErr: utils.ErrTokenInvalid},
+		// An expired token signed with another key is a forgery, not an expired session
+		{name: "expired token with wrong signature", token: signExpiredToken(t, otherCfg), wantErr: utils.ErrTokenInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jwtManager.ValidateToken(tt.token)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("JWTManager.ValidateToken() unexpected error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("JWTManager.ValidateToken() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package unit
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginAuditService_ListLoginEvents(t *testing.T) {
+	t.Run("Passes Filter To Repository", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		service := services.NewLoginAuditService(repo)
+
+		success := false
+		filter := &models.LoginEventFilter{Success: &success, UserType: "admin", Page: 1, PageSize: 10}
+		expected := &models.LoginEventListResponse{Data: []models.LoginEvent{{ID: 1}}}
+		repo.On("ListLoginEvents", filter).Return(expected, nil)
+
+		result, err := service.ListLoginEvents(context.Background(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid User Type", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		service := services.NewLoginAuditService(repo)
+
+		_, err := service.ListLoginEvents(context.Background(), &models.LoginEventFilter{UserType: "root"})
+
+		assert.ErrorIs(t, err, services.ErrInvalidLoginFilter)
+		repo.AssertNotCalled(t, "ListLoginEvents", mock.Anything)
+	})
+
+	t.Run("Inverted Date Range", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		service := services.NewLoginAuditService(repo)
+
+		from := time.Now()
+		to := from.Add(-time.Hour)
+		_, err := service.ListLoginEvents(context.Background(), &models.LoginEventFilter{From: &from, To: &to})
+
+		assert.ErrorIs(t, err, services.ErrInvalidLoginFilter)
+		repo.AssertNotCalled(t, "ListLoginEvents", mock.Anything)
+	})
+}
+
+func TestLoginAuditService_ExportLoginEvents(t *testing.T) {
+	repo := new(testutils.MockLoginAuditRepository)
+	service := services.NewLoginAuditService(repo)
+
+	filter := &models.LoginEventFilter{IPAddress: "10.0.0.1"}
+	repo.On("ExportLoginEvents", filter, mock.AnythingOfType("int")).Return([]models.LoginEvent{{ID: 1}, {ID: 2}}, nil)
+
+	events, err := service.ExportLoginEvents(context.Background(), filter)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	repo.AssertExpectations(t)
+}
+
+func TestLoginAuditService_ListLoginAttempts(t *testing.T) {
+	t.Run("Passes Filter To Repository", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		service := services.NewLoginAuditService(repo)
+
+		filter := &models.LoginAttemptFilter{Reason: models.LoginFailureWrongPassword, Email: "user@example.com", Page: 1, PageSize: 10}
+		expected := &models.LoginAttemptListResponse{Data: []models.LoginAttempt{{ID: 1, Reason: models.LoginFailureWrongPassword}}}
+		repo.On("ListLoginAttempts", filter).Return(expected, nil)
+
+		result, err := service.ListLoginAttempts(context.Background(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Reason", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		service := services.NewLoginAuditService(repo)
+
+		_, err := service.ListLoginAttempts(context.Background(), &models.LoginAttemptFilter{Reason: "bad_luck"})
+
+		assert.ErrorIs(t, err, services.ErrInvalidLoginFilter)
+		repo.AssertNotCalled(t, "ListLoginAttempts", mock.Anything)
+	})
+}
+
+func TestLoginAuditService_AggregateLoginAttempts(t *testing.T) {
+	t.Run("Top Source IPs With Default Limit", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		service := services.NewLoginAuditService(repo)
+
+		filter := &models.LoginAttemptFilter{}
+		expected := []models.LoginAttemptCount{{Value: "10.0.0.1", Count: 42}}
+		repo.On("CountLoginAttemptsBy", filter, models.LoginAttemptGroupByIP, 10).Return(expected, nil)
+
+		result, err := service.AggregateLoginAttempts(context.Background(), filter, models.LoginAttemptGroupByIP, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Limit Is Capped", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		service := services.NewLoginAuditService(repo)
+
+		filter := &models.LoginAttemptFilter{}
+		repo.On("CountLoginAttemptsBy", filter, models.LoginAttemptGroupByEmail, 100).Return([]models.LoginAttemptCount{}, nil)
+
+		_, err := service.AggregateLoginAttempts(context.Background(), filter, models.LoginAttemptGroupByEmail, 5000)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Invalid Group By", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		service := services.NewLoginAuditService(repo)
+
+		_, err := service.AggregateLoginAttempts(context.Background(), &models.LoginAttemptFilter{}, "user_agent", 10)
+
+		assert.ErrorIs(t, err, services.ErrInvalidLoginFilter)
+		repo.AssertNotCalled(t, "CountLoginAttemptsBy", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestLoginAuditHandler_ListLogins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(repo *testutils.MockLoginAuditRepository) *gin.Engine {
+		handler := handlers.NewLoginAuditHandler(services.NewLoginAuditService(repo))
+		router := gin.New()
+		router.GET("/admin/logins", handler.ListLogins)
+		router.GET("/admin/login-attempts", handler.ListLoginAttempts)
+		return router
+	}
+
+	t.Run("Invalid User Type Is A Bad Request", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		w := httptest.NewRecorder()
+		newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/logins?user_type=root", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		repo.AssertNotCalled(t, "ListLoginEvents", mock.Anything)
+	})
+
+	t.Run("Invalid Reason Is A Bad Request", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		w := httptest.NewRecorder()
+		newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/login-attempts?reason=bad_luck", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Repository Failure Is A Server Error", func(t *testing.T) {
+		repo := new(testutils.MockLoginAuditRepository)
+		repo.On("CountLoginAttemptsBy", mock.Anything, models.LoginAttemptGroupByIP, 10).Return(nil, errors.New("connection refused"))
+		w := httptest.NewRecorder()
+		newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/login-attempts?group_by=ip_address", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestLoginAuditRepository_ListLoginEvents(t *testing.T) {
+	db, fake := testutils.NewFakeDB(t)
+	repo := repositories.NewLoginAuditRepository(db)
+
+	columns := []string{"id", "source", "success", "user_id", "user_type", "email", "ip_address", "user_agent", "device_info", "reason", "created_at"}
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fake.OnQuery("SELECT COUNT(*)", []string{"count"}, []driver.Value{int64(2)})
+	fake.OnQuery("ORDER BY created_at DESC", columns,
+		[]driver.Value{int64(5), "login", true, int64(1), "admin", "admin@example.com", nil, nil, nil, nil, createdAt},
+		[]driver.Value{int64(5), "attempt", false, nil, "admin", "admin@example.com", nil, nil, nil, "wrong_password", createdAt},
+	)
+
+	result, err := repo.ListLoginEvents(&models.LoginEventFilter{UserType: "admin"})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Data, 2)
+	assert.Equal(t, models.LoginEventSourceLogin, result.Data[0].Source)
+	assert.Equal(t, models.LoginEventSourceAttempt, result.Data[1].Source)
+	assert.True(t, fake.Ran("FROM successful_logins"))
+	assert.False(t, fake.Ran("FROM user_sessions"))
+}
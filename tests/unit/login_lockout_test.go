@@ -0,0 +1,309 @@
+package unit
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAttemptLimiterWithLockout(t *testing.T) {
+	t.Run("window expiring forgets earlier failures", func(t *testing.T) {
+		limiter := utils.NewAttemptLimiterWithLockout(2, 30*time.Millisecond, time.Minute)
+
+		limiter.Fail("a")
+		time.Sleep(40 * time.Millisecond)
+		limiter.Fail("a")
+
+		allowed, _ := limiter.Allow("a")
+		assert.True(t, allowed)
+	})
+
+	t.Run("reaching the limit locks out for the lockout duration", func(t *testing.T) {
+		limiter := utils.NewAttemptLimiterWithLockout(2, time.Minute, 30*time.Millisecond)
+
+		limiter.Fail("a")
+		limiter.Fail("a")
+		allowed, retryAfter := limiter.Allow("a")
+		assert.False(t, allowed)
+		assert.LessOrEqual(t, retryAfter, 30*time.Millisecond)
+
+		time.Sleep(40 * time.Millisecond)
+		allowed, _ = limiter.Allow("a")
+		assert.True(t, allowed)
+	})
+
+	t.Run("concurrent reservations never exceed the limit", func(t *testing.T) {
+		limiter := utils.NewAttemptLimiterWithLockout(3, time.Minute, time.Minute)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		allowedCount := 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if allowed, _ := limiter.Reserve("a"); allowed {
+					mu.Lock()
+					allowedCount++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 3, allowedCount)
+	})
+
+	t.Run("released reservations are not counted", func(t *testing.T) {
+		limiter := utils.NewAttemptLimiterWithLockout(2, time.Minute, time.Hour)
+
+		limiter.Reserve("a")
+		limiter.Reserve("a")
+		limiter.Release("a")
+
+		allowed, _ := limiter.Allow("a")
+		assert.True(t, allowed)
+		allowed, _ = limiter.Reserve("a")
+		assert.True(t, allowed)
+		allowed, retryAfter := limiter.Reserve("a")
+		assert.False(t, allowed)
+		assert.Greater(t, retryAfter, time.Minute)
+	})
+
+	t.Run("expired keys are swept", func(t *testing.T) {
+		limiter := utils.NewAttemptLimiter(5, 20*time.Millisecond)
+
+		for i := 0; i < 100; i++ {
+			limiter.Fail(fmt.Sprintf("key-%d", i))
+		}
+		assert.Equal(t, 100, limiter.Len())
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, 0, limiter.Len())
+	})
+}
+
+// newLockoutAuthService creates an AuthService that locks an email out after maxAttempts failures
+func newLockoutAuthService(maxAttempts int) (*services.AuthService, *authServiceMocks, *models.User) {
+	_, m := newMockedAuthService()
+	cfg := testutils.TestConfig()
+	cfg.Security.MaxLoginAttempts = maxAttempts
+	cfg.Security.LoginAttemptWindowMinutes = 15
+	cfg.Security.LoginLockoutMinutes = 15
+	authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, nil, m.sessionRepo, m.loginAuditRepo, nil, nil, m.permissionService, cfg)
+
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	m.expectUserLogin(user)
+	m.adminRepo.On("GetByEmail", user.Email).Return(nil, repositories.ErrNotFound)
+	m.gamenetRepo.On("GetByEmail", user.Email).Return(nil, repositories.ErrNotFound)
+	return authService, m, user
+}
+
+func TestAuthService_Login_Lockout(t *testing.T) {
+	t.Run("locks out after repeated failures even with the right password", func(t *testing.T) {
+		authService, _, user := newLockoutAuthService(2)
+
+		for i := 0; i < 2; i++ {
+			_, err := authService.Login(user.Email, "wrong-password", false)
+			assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+		}
+
+		response, err := authService.Login(user.Email, "password123", false)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, services.ErrTooManyLoginAttempts)
+		var tooMany *services.TooManyAttemptsError
+		if assert.ErrorAs(t, err, &tooMany) {
+			assert.InDelta(t, (15 * time.Minute).Seconds(), tooMany.RetryAfter.Seconds(), 1)
+		}
+	})
+
+	t.Run("email case does not escape the lockout", func(t *testing.T) {
+		authService, m, user := newLockoutAuthService(1)
+		upper := strings.ToUpper(user.Email)
+		m.userRepo.On("GetByEmail", upper).Return(nil, repositories.ErrNotFound)
+		m.adminRepo.On("GetByEmail", upper).Return(nil, repositories.ErrNotFound)
+		m.gamenetRepo.On("GetByEmail", upper).Return(nil, repositories.ErrNotFound)
+
+		_, err := authService.Login(upper, "wrong-password", false)
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+
+		_, err = authService.Login(user.Email, "password123", false)
+		assert.ErrorIs(t, err, services.ErrTooManyLoginAttempts)
+	})
+
+	t.Run("successful login resets the counter", func(t *testing.T) {
+		authService, _, user := newLockoutAuthService(2)
+
+		_, err := authService.Login(user.Email, "wrong-password", false)
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+		_, err = authService.Login(user.Email, "password123", false)
+		assert.NoError(t, err)
+		_, err = authService.Login(user.Email, "wrong-password", false)
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+
+		response, err := authService.Login(user.Email, "password123", false)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+	})
+
+	t.Run("concurrent wrong passwords cannot exceed the limit", func(t *testing.T) {
+		authService, _, user := newLockoutAuthService(2)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := authService.Login(user.Email, "wrong-password", false)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		checked := 0
+		for err := range errs {
+			if errors.Is(err, services.ErrInvalidCredentials) {
+				checked++
+			} else {
+				assert.ErrorIs(t, err, services.ErrTooManyLoginAttempts)
+			}
+		}
+		assert.Equal(t, 2, checked)
+	})
+
+	t.Run("locked out attempts are recorded as locked", func(t *testing.T) {
+		authService, m, user := newLockoutAuthService(1)
+		m.loginAuditRepo.On("RecordFailedLogin", user.Email, mock.Anything, mock.Anything, mock.Anything, models.LoginFailureWrongPassword).Return(nil)
+		m.loginAuditRepo.On("RecordFailedLogin", user.Email, mock.Anything, mock.Anything, mock.Anything, models.LoginFailureLocked).Return(nil)
+
+		_, err := authService.LoginWithSession(user.Email, "wrong-password", false, "", "10.0.0.1", "Safari", "")
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+		_, err = authService.LoginWithSession(user.Email, "password123", false, "", "10.0.0.1", "Safari", "")
+		assert.ErrorIs(t, err, services.ErrTooManyLoginAttempts)
+
+		m.loginAuditRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthHandler_Login_LockedOut(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(testutils.MockAuthService)
+	mockService.On("LoginWithSession", "user@example.com", "password123", false, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return((*models.LoginResponse)(nil), &services.TooManyAttemptsError{RetryAfter: 90 * time.Second, Kind: services.ErrTooManyLoginAttempts})
+	cfg := testutils.TestConfig()
+	handler := handlers.NewAuthHandler(mockService, utils.NewFileUploader(&cfg.FileStorage))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/auth/login", strings.NewReader(`{"email":"user@example.com","password":"password123"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler.Login(c)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), services.ErrTooManyLoginAttempts.Error())
+}
+
+// memoryLoginLockoutRepository keeps the failed login counters in memory, standing in
+// for the login_lockouts table that every instance shares
+type memoryLoginLockoutRepository struct {
+	mu      sync.Mutex
+	limiter *utils.AttemptLimiter
+}
+
+func (r *memoryLoginLockoutRepository) ReserveAttempt(identifier string, maxAttempts int, window, lockout time.Duration) (bool, time.Duration, error) {
+	r.mu.Lock()
+	if r.limiter == nil {
+		r.limiter = utils.NewAttemptLimiterWithLockout(maxAttempts, window, lockout)
+	}
+	r.mu.Unlock()
+	allowed, retryAfter := r.limiter.Reserve(identifier)
+	return allowed, retryAfter, nil
+}
+
+func (r *memoryLoginLockoutRepository) ReleaseAttempt(identifier string) error {
+	r.limiter.Release(identifier)
+	return nil
+}
+
+func (r *memoryLoginLockoutRepository) ResetAttempts(identifier string) error {
+	r.limiter.Reset(identifier)
+	return nil
+}
+
+func (r *memoryLoginLockoutRepository) CleanupExpiredLockouts() error {
+	return nil
+}
+
+func TestAuthService_Login_LockoutSharedBetweenInstances(t *testing.T) {
+	lockouts := &memoryLoginLockoutRepository{}
+	first, _, user := newLockoutAuthService(2)
+	first.SetLoginLockoutRepository(lockouts)
+	second, _, _ := newLockoutAuthService(2)
+	second.SetLoginLockoutRepository(lockouts)
+
+	for _, instance := range []*services.AuthService{first, second} {
+		_, err := instance.Login(user.Email, "wrong-password", false)
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+	}
+
+	// A restarted instance starts with an empty in-memory limiter but the same counters
+	restarted, _, _ := newLockoutAuthService(2)
+	restarted.SetLoginLockoutRepository(lockouts)
+	_, err := restarted.Login(user.Email, "password123", false)
+
+	assert.ErrorIs(t, err, services.ErrTooManyLoginAttempts)
+}
+
+func TestLoginLockoutRepository_ReserveAttempt(t *testing.T) {
+	columns := []string{"failed_count", "window_ends_at", "locked_until"}
+
+	t.Run("refuses a locked identifier", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		lockedUntil := time.Now().Add(10 * time.Minute)
+		fake.OnQuery("FROM login_lockouts", columns, []driver.Value{int64(5), time.Now().Add(time.Minute), lockedUntil})
+		repo := repositories.NewLoginLockoutRepository(db)
+
+		allowed, retryAfter, err := repo.ReserveAttempt("user@example.com", 5, 15*time.Minute, 15*time.Minute)
+
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.InDelta(t, (10 * time.Minute).Seconds(), retryAfter.Seconds(), 1)
+		assert.False(t, fake.Ran("UPDATE login_lockouts"))
+	})
+
+	t.Run("counts the attempt once the lockout has ended", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		expired := time.Now().Add(-time.Minute)
+		fake.OnQuery("FROM login_lockouts", columns, []driver.Value{int64(5), expired, expired})
+		repo := repositories.NewLoginLockoutRepository(db)
+
+		allowed, _, err := repo.ReserveAttempt("user@example.com", 5, 15*time.Minute, 15*time.Minute)
+
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		assert.True(t, fake.Ran("UPDATE login_lockouts"))
+	})
+}
@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeIranianMobile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"09123456789", "09123456789", true},
+		{"9123456789", "09123456789", true},
+		{"+989123456789", "09123456789", true},
+		{"989123456789", "09123456789", true},
+		{"00989123456789", "09123456789", true},
+		{" 0912-345-6789 ", "09123456789", true},
+		{"user@example.com", "", false},
+		{"0912345678", "", false},
+		{"08123456789", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			mobile, ok := utils.NormalizeIranianMobile(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, mobile)
+		})
+	}
+}
+
+func TestAuthService_Login_ByMobile(t *testing.T) {
+	t.Run("user logs in with mobile", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+		user.Mobile = "09123456789"
+		m.userRepo.On("GetByMobile", "09123456789").Return(user, nil)
+		m.userRepo.On("UpdateLastLogin", user.ID).Return(nil)
+		m.permissionService.On("GetUserPermissionsByID", user.ID, "user").Return([]string{}, nil)
+
+		response, err := authService.Login("+98 912 345 6789", "password123", false)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, response) {
+			assert.Equal(t, "user", response.UserType)
+		}
+		m.userRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+		m.adminRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	})
+
+	t.Run("user logs in with email", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+		m.expectUserLogin(user)
+
+		response, err := authService.Login(user.Email, "password123", false)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+		m.userRepo.AssertNotCalled(t, "GetByMobile", mock.Anything)
+	})
+
+	t.Run("unknown mobile reads as invalid credentials", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		m.userRepo.On("GetByMobile", "09120000000").Return(nil, repositories.ErrNotFound)
+
+		response, err := authService.Login("09120000000", "password123", false)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, services.ErrInvalidCredentials)
+		assert.Equal(t, "invalid credentials", err.Error())
+		m.adminRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+		m.gamenetRepo.AssertNotCalled(t, "GetByEmail", mock.Anything)
+	})
+}
@@ -0,0 +1,524 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/migrations"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMigrationRunner is a mock implementation of migrations.MigrationRunner
+type MockMigrationRunner struct {
+	mock.Mock
+}
+
+func (m *MockMigrationRunner) CreateMigrationTable() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockMigrationRunner) GetAppliedMigrations() ([]migrations.Migration, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]migrations.Migration), args.Error(1)
+}
+
+func (m *MockMigrationRunner) ApplyMigration(version, description, upSQL string) error {
+	args := m.Called(version, description, upSQL)
+	return args.Error(0)
+}
+
+func (m *MockMigrationRunner) RollbackMigration(version, downSQL string) error {
+	args := m.Called(version, downSQL)
+	return args.Error(0)
+}
+
+func (m *MockMigrationRunner) CheckDatabaseExists() (bool, error) {
+	args := m.Called()
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMigrationRunner) CreateDatabase() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockMigrationRunner) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string
+	}{
+		{
+			name:     "single statement without trailing semicolon",
+			script:   "DROP TABLE IF EXISTS example",
+			expected: []string{"DROP TABLE IF EXISTS example"},
+		},
+		{
+			name:     "multiple statements",
+			script:   "INSERT INTO a VALUES (1);\nINSERT INTO b VALUES (2);\n",
+			expected: []string{"INSERT INTO a VALUES (1)", "INSERT INTO b VALUES (2)"},
+		},
+		{
+			name:     "semicolons inside strings and identifiers",
+			script:   "INSERT INTO t (`a;b`, c) VALUES ('x; y', \"it\\\"s; fine\");\nUPDATE t SET c = 'o''clock;'",
+			expected: []string{"INSERT INTO t (`a;b`, c) VALUES ('x; y', \"it\\\"s; fine\")", "UPDATE t SET c = 'o''clock;'"},
+		},
+		{
+			name:     "comments are dropped",
+			script:   "-- Assign permission; to admins\nINSERT INTO a VALUES (1); /* block; comment */\n# hash; comment\n",
+			expected: []string{"INSERT INTO a VALUES (1)"},
+		},
+		{
+			name:     "empty script",
+			script:   " ;\n; ",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, migrations.SplitStatements(tt.script))
+		})
+	}
+}
+
+// versionsOf returns the versions of the given migration files
+func versionsOf(files []migrations.MigrationFile) []string {
+	var versions []string
+	for _, f := range files {
+		versions = append(versions, f.Version)
+	}
+	return versions
+}
+
+func TestPendingUpTo(t *testing.T) {
+	available := []migrations.MigrationFile{
+		{Version: "001_create_users"},
+		{Version: "002_create_admins"},
+		{Version: "003_add_wallet"},
+		{Version: "004_add_index"},
+		{Version: "005_create_logs"},
+	}
+	applied := []migrations.Migration{{Version: "001_create_users"}, {Version: "002_create_admins"}}
+	pending := migrations.PendingMigrations(available, applied)
+	require.Equal(t, []string{"003_add_wallet", "004_add_index", "005_create_logs"}, versionsOf(pending))
+
+	t.Run("target in the middle of the pending list", func(t *testing.T) {
+		selected, err := migrations.PendingUpTo(pending, applied, "004_add_index")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"003_add_wallet", "004_add_index"}, versionsOf(selected))
+	})
+
+	t.Run("target is the last pending migration", func(t *testing.T) {
+		selected, err := migrations.PendingUpTo(pending, applied, "005_create_logs")
+
+		require.NoError(t, err)
+		assert.Len(t, selected, 3)
+	})
+
+	t.Run("target already applied", func(t *testing.T) {
+		selected, err := migrations.PendingUpTo(pending, applied, "002_create_admins")
+
+		assert.ErrorIs(t, err, migrations.ErrTargetAlreadyApplied)
+		assert.Nil(t, selected)
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		selected, err := migrations.PendingUpTo(pending, applied, "999_missing")
+
+		assert.ErrorIs(t, err, migrations.ErrUnknownTarget)
+		assert.Contains(t, err.Error(), "999_missing")
+		assert.Nil(t, selected)
+	})
+}
+
+func TestAppliedAfter(t *testing.T) {
+	applied := []migrations.Migration{
+		{Version: "001_create_users"},
+		{Version: "002_create_admins"},
+		{Version: "003_add_wallet"},
+	}
+
+	newer, err := migrations.AppliedAfter(applied, "001_create_users")
+	require.NoError(t, err)
+	require.Len(t, newer, 2)
+	assert.Equal(t, "002_create_admins", newer[0].Version)
+	assert.Equal(t, "003_add_wallet", newer[1].Version)
+
+	newer, err = migrations.AppliedAfter(applied, "003_add_wallet")
+	require.NoError(t, err)
+	assert.Empty(t, newer)
+
+	_, err = migrations.AppliedAfter(applied, "004_pending")
+	assert.ErrorIs(t, err, migrations.ErrTargetNotApplied)
+}
+
+func TestIsImplicitCommit(t *testing.T) {
+	assert.True(t, migrations.IsImplicitCommit("CREATE TABLE t (id INT)"))
+	assert.True(t, migrations.IsImplicitCommit("alter  table t ADD COLUMN c INT"))
+	assert.True(t, migrations.IsImplicitCommit("DROP TABLE IF EXISTS t"))
+	assert.True(t, migrations.IsImplicitCommit("TRUNCATE t"))
+	assert.False(t, migrations.IsImplicitCommit("INSERT INTO t VALUES (1)"))
+	assert.False(t, migrations.IsImplicitCommit("UPDATE t SET created_at = NOW()"))
+	assert.False(t, migrations.IsImplicitCommit("DELETE FROM drops WHERE id = 1"))
+}
+
+func TestMySQLRunner_ApplyMigration_RollsBackOnFailure(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS migration_tx_test (id INT PRIMARY KEY) ENGINE=InnoDB")
+	require.NoError(t, err)
+	defer db.Exec("DROP TABLE IF EXISTS migration_tx_test")
+
+	runner := migrations.NewMySQLRunnerWithDB(db, testutils.TestConfig())
+
+	// The second statement references a missing table
+	err = runner.ApplyMigration("900_tx_test", "Transaction test",
+		"INSERT INTO migration_tx_test (id) VALUES (1);\nINSERT INTO missing_table (id) VALUES (2);")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "statement 2 of 2")
+
+	var rows int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM migration_tx_test").Scan(&rows))
+	assert.Equal(t, 0, rows, "the first statement's insert must be rolled back")
+
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM migrations WHERE version = '900_tx_test'").Scan(&rows))
+	assert.Equal(t, 0, rows, "a failed migration must not be recorded")
+
+	// The same rollback guarantee applies to down migrations
+	require.NoError(t, runner.ApplyMigration("900_tx_test", "Transaction test", "INSERT INTO migration_tx_test (id) VALUES (1)"))
+	err = runner.RollbackMigration("900_tx_test", "DELETE FROM migration_tx_test;\nDELETE FROM missing_table;")
+	require.Error(t, err)
+
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM migration_tx_test").Scan(&rows))
+	assert.Equal(t, 1, rows)
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM migrations WHERE version = '900_tx_test'").Scan(&rows))
+	assert.Equal(t, 1, rows)
+}
+
+func TestBuildStatus(t *testing.T) {
+	available := []migrations.MigrationFile{
+		{Version: "001_create_users", UpSQL: "CREATE TABLE users (id INT)"},
+		{Version: "002_create_admins", UpSQL: "CREATE TABLE admins (id INT, name VARCHAR(255))"},
+		{Version: "003_legacy", UpSQL: "CREATE TABLE legacy (id INT)"},
+		{Version: "004_add_wallet", UpSQL: "ALTER TABLE users ADD COLUMN wallet INT"},
+	}
+	applied := []migrations.Migration{
+		{Version: "001_create_users", Checksum: migrations.Checksum("CREATE TABLE users (id INT)")},
+		{Version: "002_create_admins", Checksum: migrations.Checksum("CREATE TABLE admins (id INT)")},
+		{Version: "003_legacy"}, // applied before checksums were recorded
+	}
+
+	statuses := migrations.BuildStatus(available, applied)
+
+	require.Len(t, statuses, 4)
+	assert.Equal(t, migrations.StatusApplied, statuses[0].Status)
+	assert.Equal(t, migrations.StatusModified, statuses[1].Status)
+	assert.Equal(t, migrations.StatusApplied, statuses[2].Status)
+	assert.Equal(t, migrations.StatusPending, statuses[3].Status)
+	assert.Equal(t, []string{"002_create_admins"}, migrations.ModifiedVersions(statuses))
+}
+
+func TestMySQLRunner_StatusReportsModifiedMigration(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+	defer db.Exec("DROP TABLE IF EXISTS migration_checksum_test")
+
+	runner := migrations.NewMySQLRunnerWithDB(db, testutils.TestConfig())
+	require.NoError(t, runner.CreateMigrationTable())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "901_checksum_test.sql")
+	writeMigration := func(upSQL string) {
+		content := "-- version: 901_checksum_test\n-- description: Checksum test\n\n-- UP\n" + upSQL +
+			"\n\n-- DOWN\nDROP TABLE IF EXISTS migration_checksum_test;\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	status := func() string {
+		available, err := migrations.LoadMigrationFiles(dir)
+		require.NoError(t, err)
+		applied, err := runner.GetAppliedMigrations()
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		migrations.WriteStatus(&out, migrations.BuildStatus(available, applied))
+		return out.String()
+	}
+
+	writeMigration("CREATE TABLE migration_checksum_test (id INT PRIMARY KEY);")
+	available, err := migrations.LoadMigrationFiles(dir)
+	require.NoError(t, err)
+	require.Len(t, available, 1)
+	require.NoError(t, runner.ApplyMigration(available[0].Version, available[0].Description, available[0].UpSQL))
+
+	assert.Contains(t, status(), migrations.StatusApplied)
+
+	// Edit the already-applied file
+	writeMigration("CREATE TABLE migration_checksum_test (id INT PRIMARY KEY, name VARCHAR(50));")
+
+	output := status()
+	assert.Contains(t, output, "901_checksum_test")
+	assert.Contains(t, output, migrations.StatusModified)
+}
+
+// dryRunFiles returns migration files for the dry-run tests
+func dryRunFiles() []migrations.MigrationFile {
+	return []migrations.MigrationFile{
+		{Version: "001_create_users", Description: "Create users", UpSQL: "CREATE TABLE users (id INT);", DownSQL: "DROP TABLE users;"},
+		{Version: "002_add_wallet", Description: "Add wallet", UpSQL: "ALTER TABLE users ADD COLUMN wallet INT;", DownSQL: "ALTER TABLE users DROP COLUMN wallet;"},
+		{Version: "003_create_logs", Description: "Create logs", UpSQL: "CREATE TABLE logs (id INT);", DownSQL: "DROP TABLE logs;"},
+	}
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	t.Run("up prints pending SQL without applying", func(t *testing.T) {
+		runner := new(MockMigrationRunner)
+		runner.On("GetAppliedMigrations").Return([]migrations.Migration{{Version: "001_create_users"}}, nil)
+
+		var out bytes.Buffer
+		err := migrations.Up(runner, dryRunFiles(), migrations.RunOptions{Steps: 10, DryRun: true, Out: &out})
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "2 migration(s) would be applied")
+		assert.Contains(t, out.String(), "-- Migration 002_add_wallet: Add wallet")
+		assert.Contains(t, out.String(), "ALTER TABLE users ADD COLUMN wallet INT;")
+		assert.Contains(t, out.String(), "CREATE TABLE logs (id INT);")
+		assert.NotContains(t, out.String(), "CREATE TABLE users")
+		runner.AssertNotCalled(t, "CreateMigrationTable")
+		runner.AssertNotCalled(t, "ApplyMigration", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("up respects the target", func(t *testing.T) {
+		runner := new(MockMigrationRunner)
+		runner.On("GetAppliedMigrations").Return([]migrations.Migration{}, nil)
+
+		var out bytes.Buffer
+		err := migrations.Up(runner, dryRunFiles(), migrations.RunOptions{Target: "002_add_wallet", DryRun: true, Out: &out})
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "2 migration(s) would be applied")
+		assert.NotContains(t, out.String(), "003_create_logs")
+	})
+
+	t.Run("up treats a missing migrations table as nothing applied", func(t *testing.T) {
+		runner := new(MockMigrationRunner)
+		runner.On("GetAppliedMigrations").Return(nil, errors.New("Error 1146: Table 'gatehide.migrations' doesn't exist"))
+
+		var out bytes.Buffer
+		err := migrations.Up(runner, dryRunFiles(), migrations.RunOptions{Steps: 10, DryRun: true, Out: &out})
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "3 migration(s) would be applied")
+		runner.AssertNotCalled(t, "CreateMigrationTable")
+	})
+
+	t.Run("down prints rollback SQL without rolling back", func(t *testing.T) {
+		runner := new(MockMigrationRunner)
+		runner.On("GetAppliedMigrations").Return([]migrations.Migration{
+			{Version: "001_create_users", Description: "Create users"},
+			{Version: "002_add_wallet", Description: "Add wallet"},
+		}, nil)
+
+		var out bytes.Buffer
+		err := migrations.Down(runner, dryRunFiles(), migrations.RunOptions{Steps: 1, DryRun: true, Out: &out})
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "1 migration(s) would be rolled back")
+		assert.Contains(t, out.String(), "ALTER TABLE users DROP COLUMN wallet;")
+		assert.NotContains(t, out.String(), "DROP TABLE users;")
+		runner.AssertNotCalled(t, "RollbackMigration", mock.Anything, mock.Anything)
+	})
+}
+
+// fakeMigrationRunner keeps the migrations table in memory; ApplyMigration fails
+// for the versions listed in failUp
+type fakeMigrationRunner struct {
+	MockMigrationRunner
+	applied []migrations.Migration
+	failUp  map[string]bool
+}
+
+func (f *fakeMigrationRunner) GetAppliedMigrations() ([]migrations.Migration, error) {
+	return append([]migrations.Migration(nil), f.applied...), nil
+}
+
+func (f *fakeMigrationRunner) ApplyMigration(version, description, upSQL string) error {
+	if f.failUp[version] {
+		return errors.New("syntax error")
+	}
+	f.applied = append(f.applied, migrations.Migration{Version: version, Description: description, Checksum: migrations.Checksum(upSQL)})
+	return nil
+}
+
+func (f *fakeMigrationRunner) RollbackMigration(version, downSQL string) error {
+	for i, m := range f.applied {
+		if m.Version == version {
+			f.applied = append(f.applied[:i], f.applied[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("migration not applied")
+}
+
+func newFakeMigrationRunner(files []migrations.MigrationFile) *fakeMigrationRunner {
+	runner := &fakeMigrationRunner{failUp: map[string]bool{}}
+	for _, f := range files {
+		runner.applied = append(runner.applied, migrations.Migration{Version: f.Version, Description: f.Description, Checksum: f.Checksum()})
+	}
+	return runner
+}
+
+func TestMigrate_Redo(t *testing.T) {
+	t.Run("redo leaves the migrations table unchanged", func(t *testing.T) {
+		files := dryRunFiles()
+		runner := newFakeMigrationRunner(files)
+		before, _ := runner.GetAppliedMigrations()
+
+		var out bytes.Buffer
+		err := migrations.Redo(runner, files, migrations.RunOptions{Steps: 2, Out: &out})
+
+		require.NoError(t, err)
+		after, _ := runner.GetAppliedMigrations()
+		assert.Equal(t, before, after)
+		assert.Contains(t, out.String(), "Rolling back migration 003_create_logs")
+		assert.Contains(t, out.String(), "Rolling back migration 002_add_wallet")
+		assert.Contains(t, out.String(), "Applying migration 002_add_wallet")
+		assert.Contains(t, out.String(), "Applying migration 003_create_logs")
+		assert.NotContains(t, out.String(), "001_create_users")
+	})
+
+	t.Run("failing up leaves the rollback applied", func(t *testing.T) {
+		files := dryRunFiles()
+		runner := newFakeMigrationRunner(files)
+		runner.failUp["003_create_logs"] = true
+
+		var out bytes.Buffer
+		err := migrations.Redo(runner, files, migrations.RunOptions{Steps: 1, Out: &out})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to re-apply migration 003_create_logs")
+		after, _ := runner.GetAppliedMigrations()
+		require.Len(t, after, 2)
+		assert.Equal(t, "002_add_wallet", after[1].Version)
+	})
+
+	t.Run("no applied migrations", func(t *testing.T) {
+		runner := &fakeMigrationRunner{}
+
+		var out bytes.Buffer
+		err := migrations.Redo(runner, dryRunFiles(), migrations.RunOptions{Steps: 1, Out: &out})
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "No applied migrations to redo.")
+	})
+
+	t.Run("missing migration file changes nothing", func(t *testing.T) {
+		files := dryRunFiles()
+		runner := newFakeMigrationRunner(files)
+
+		var out bytes.Buffer
+		err := migrations.Redo(runner, files[:2], migrations.RunOptions{Steps: 1, Out: &out})
+
+		require.Error(t, err)
+		after, _ := runner.GetAppliedMigrations()
+		assert.Len(t, after, 3)
+	})
+}
+
+func TestMySQLRunner_DryRunRecordsNothing(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+	defer db.Exec("DROP TABLE IF EXISTS migration_dry_run_test")
+
+	runner := migrations.NewMySQLRunnerWithDB(db, testutils.TestConfig())
+	files := []migrations.MigrationFile{{
+		Version:     "902_dry_run_test",
+		Description: "Dry run test",
+		UpSQL:       "CREATE TABLE migration_dry_run_test (id INT PRIMARY KEY);",
+		DownSQL:     "DROP TABLE IF EXISTS migration_dry_run_test;",
+	}}
+
+	var out bytes.Buffer
+	require.NoError(t, migrations.Up(runner, files, migrations.RunOptions{Steps: 1, DryRun: true, Out: &out}))
+	assert.Contains(t, out.String(), "CREATE TABLE migration_dry_run_test")
+
+	var rows int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM migrations").Scan(&rows))
+	assert.Equal(t, 0, rows, "a dry run must not record migrations")
+
+	require.NoError(t, db.QueryRow(
+		"SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'migration_dry_run_test'").Scan(&rows))
+	assert.Equal(t, 0, rows, "a dry run must not execute migration SQL")
+}
+
+func TestMySQLRunner_LockAllowsOneRun(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+
+	runner := migrations.NewMySQLRunnerWithDB(db, testutils.TestConfig())
+
+	var (
+		wg        sync.WaitGroup
+		proceeded int32
+		errs      = make(chan error, 2)
+		release   = make(chan struct{})
+	)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := runner.Lock(time.Second)
+			if err != nil {
+				errs <- err
+				return
+			}
+			atomic.AddInt32(&proceeded, 1)
+			<-release
+			errs <- unlock()
+		}()
+	}
+
+	// The loser gives up after the lock timeout while the winner still holds the lock
+	require.ErrorIs(t, <-errs, migrations.ErrMigrationInProgress)
+	close(release)
+	wg.Wait()
+	assert.NoError(t, <-errs)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&proceeded))
+
+	// Once released, the lock can be acquired again
+	unlock, err := runner.Lock(time.Second)
+	require.NoError(t, err)
+	assert.NoError(t, unlock())
+}
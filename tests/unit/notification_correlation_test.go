@@ -0,0 +1,209 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// relatedTo matches a notification request linked to the given account
+func relatedTo(userID int, userType string) interface{} {
+	return mock.MatchedBy(func(n *models.CreateNotificationRequest) bool {
+		return n.RelatedUserID != nil && *n.RelatedUserID == userID &&
+			n.RelatedUserType != nil && *n.RelatedUserType == userType
+	})
+}
+
+func TestNotificationService_SendNotification_PersistsRelatedUser(t *testing.T) {
+	ctx := context.Background()
+	notificationRepo := new(MockNotificationRepository)
+	emailService := new(MockEmailService)
+
+	notificationRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.RelatedUserID != nil && *n.RelatedUserID == 12 &&
+			n.RelatedUserType != nil && *n.RelatedUserType == "gamenet"
+	})).Return(nil)
+	notificationRepo.On("Update", mock.Anything).Return(nil)
+	emailService.On("SendEmail", ctx, sentTo("gamenet@example.com")).Return(nil)
+
+	request := &models.CreateNotificationRequest{
+		Type:      models.NotificationTypeEmail,
+		Recipient: "gamenet@example.com",
+		Subject:   "Password changed",
+		Content:   "Your password was changed",
+	}
+	request.SetRelatedUser(12, "gamenet")
+
+	service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, nil, testutils.TestConfig())
+	err := service.SendNotification(ctx, request)
+
+	assert.NoError(t, err)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestAuthService_ChangePassword_LinksNotificationToUser(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(testutils.MockSessionRepository)
+	notificationService := new(testutils.MockNotificationService)
+	user := testutils.CreateMockUser(5, "user@example.com", "Test User")
+
+	userRepo.On("GetByID", user.ID).Return(user, nil)
+	userRepo.On("UpdatePassword", user.ID, mock.AnythingOfType("string")).Return(nil)
+	sessionRepo.On("DeactivateAllOtherUserSessions", user.ID, "user", "current-token").Return(nil)
+	notificationService.On("EnqueueNotification", mock.Anything, relatedTo(user.ID, "user")).Return(nil)
+
+	authService := services.NewAuthService(userRepo, nil, nil, nil, sessionRepo, nil, nil, notificationService, nil, testutils.TestConfig())
+	err := authService.ChangePassword(user.ID, "user", "password123", "newpassword1", "newpassword1", "current-token")
+
+	require.NoError(t, err)
+	notificationService.AssertExpectations(t)
+}
+
+func TestAuthService_ChangePassword_NotificationUsesName(t *testing.T) {
+	userRepo := new(MockUserRepository)
+	sessionRepo := new(testutils.MockSessionRepository)
+	notificationService := new(testutils.MockNotificationService)
+	user := testutils.CreateMockUser(5, "user@example.com", "Sara Ahmadi")
+
+	userRepo.On("GetByID", user.ID).Return(user, nil)
+	userRepo.On("UpdatePassword", user.ID, mock.AnythingOfType("string")).Return(nil)
+	sessionRepo.On("DeactivateAllOtherUserSessions", user.ID, "user", "current-token").Return(nil)
+
+	var sent *models.CreateNotificationRequest
+	notificationService.On("EnqueueNotification", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(*models.CreateNotificationRequest) }).
+		Return(nil)
+
+	authService := services.NewAuthService(userRepo, nil, nil, nil, sessionRepo, nil, nil, notificationService, nil, testutils.TestConfig())
+	err := authService.ChangePassword(user.ID, "user", "password123", "newpassword1", "newpassword1", "current-token")
+
+	require.NoError(t, err)
+	require.NotNil(t, sent)
+	assert.Equal(t, "Sara Ahmadi", sent.TemplateData["user_name"])
+	assert.Contains(t, sent.Content, "Sara Ahmadi عزیز،")
+}
+
+func TestAuthService_ChangePassword_GamenetNotificationUsesOwnerName(t *testing.T) {
+	newService := func(gamenet *models.Gamenet) (*services.AuthService, *models.CreateNotificationRequest) {
+		gamenetRepo := new(testutils.MockGamenetRepository)
+		sessionRepo := new(testutils.MockSessionRepository)
+		notificationService := new(testutils.MockNotificationService)
+
+		gamenetRepo.On("GetByID", gamenet.ID).Return(gamenet, nil)
+		gamenetRepo.On("Update", gamenet.ID, mock.AnythingOfType("*models.GamenetUpdateRequest")).Return(nil)
+		sessionRepo.On("DeactivateAllOtherUserSessions", gamenet.ID, "gamenet", "current-token").Return(nil)
+
+		sent := &models.CreateNotificationRequest{}
+		notificationService.On("EnqueueNotification", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { *sent = *args.Get(1).(*models.CreateNotificationRequest) }).
+			Return(nil)
+
+		authService := services.NewAuthService(nil, nil, gamenetRepo, nil, sessionRepo, nil, nil, notificationService, nil, testutils.TestConfig())
+		return authService, sent
+	}
+	newGamenet := func(ownerName string) *models.Gamenet {
+		hashed, _ := models.HashPassword("password123")
+		return &models.Gamenet{ID: 7, Name: "Arena", OwnerName: ownerName, Email: "arena@example.com", Password: models.PasswordHash(hashed)}
+	}
+
+	t.Run("greets the owner by name", func(t *testing.T) {
+		authService, sent := newService(newGamenet("Reza Karimi"))
+
+		err := authService.ChangePassword(7, "gamenet", "password123", "newpassword1", "newpassword1", "current-token")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Reza Karimi", sent.TemplateData["user_name"])
+		assert.True(t, strings.HasPrefix(sent.Content, "Reza Karimi عزیز،"))
+		assert.NotContains(t, sent.Content, "Arena")
+	})
+
+	t.Run("falls back to the generic greeting without a name", func(t *testing.T) {
+		authService, sent := newService(newGamenet(""))
+
+		err := authService.ChangePassword(7, "gamenet", "password123", "newpassword1", "newpassword1", "current-token")
+
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(sent.Content, "گیم\u200cنت گرامی،"), sent.Content)
+		assert.NotContains(t, sent.Content, " عزیز،")
+	})
+}
+
+func TestNotificationHandler_GetUserNotifications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := 7
+	userType := "user"
+	seeded := []*models.Notification{
+		{ID: 3, Type: models.NotificationTypeEmail, Recipient: "user@example.com", RelatedUserID: &userID, RelatedUserType: &userType},
+		{ID: 2, Type: models.NotificationTypeEmail, Recipient: "user@example.com", RelatedUserID: &userID, RelatedUserType: &userType},
+	}
+
+	newRouter := func(repo *MockNotificationRepository) *gin.Engine {
+		service := services.NewNotificationService(nil, nil, nil, nil, repo, nil, testutils.TestConfig())
+		handler := handlers.NewNotificationHandler(service, nil, nil, nil)
+		router := gin.New()
+		router.GET("/users/:id/notifications", handler.GetUserNotifications)
+		return router
+	}
+
+	get := func(router *gin.Engine, url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		return w
+	}
+
+	t.Run("filters by the related user", func(t *testing.T) {
+		repo := new(MockNotificationRepository)
+		repo.On("GetWithFilters", mock.MatchedBy(func(filters map[string]interface{}) bool {
+			return filters["related_user_id"] == 7 && filters["related_user_type"] == "user" &&
+				filters["limit"] == 2 && filters["type"] == "email"
+		})).Return(seeded, nil)
+
+		w := get(newRouter(repo), "/users/7/notifications?type=email&limit=2")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Notifications []models.NotificationResponse `json:"notifications"`
+			NextCursor    *string                       `json:"next_cursor"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Notifications, 2)
+		assert.Equal(t, 7, *response.Notifications[0].RelatedUserID)
+		assert.NotNil(t, response.NextCursor)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("accepts other account types", func(t *testing.T) {
+		repo := new(MockNotificationRepository)
+		repo.On("GetWithFilters", mock.MatchedBy(func(filters map[string]interface{}) bool {
+			return filters["related_user_id"] == 4 && filters["related_user_type"] == "gamenet"
+		})).Return([]*models.Notification{}, nil)
+
+		w := get(newRouter(repo), "/users/4/notifications?user_type=gamenet")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		repo := new(MockNotificationRepository)
+		router := newRouter(repo)
+
+		assert.Equal(t, http.StatusBadRequest, get(router, "/users/abc/notifications").Code)
+		assert.Equal(t, http.StatusBadRequest, get(router, "/users/7/notifications?user_type=robot").Code)
+		assert.Equal(t, http.StatusBadRequest, get(router, "/users/7/notifications?cursor=bogus!").Code)
+		repo.AssertNotCalled(t, "GetWithFilters", mock.Anything)
+	})
+}
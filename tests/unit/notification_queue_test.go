@@ -0,0 +1,176 @@
+package unit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// queueTestMocks holds the mocks behind a queued notification service. sent counts
+// the notifications updated as sent; it is safe to read while workers run.
+type queueTestMocks struct {
+	emailService     *MockEmailService
+	notificationRepo *MockNotificationRepository
+	sent             *atomic.Int32
+}
+
+// newQueuedNotificationService creates a notification service whose emails and
+// records are mocked, with a running queue
+func newQueuedNotificationService(workers, size int) (*services.NotificationService, *queueTestMocks) {
+	return newQueuedNotificationServiceWithConfig(testutils.TestConfig(), workers, size)
+}
+
+// newQueuedNotificationServiceWithConfig is newQueuedNotificationService with the given config
+func newQueuedNotificationServiceWithConfig(cfg *config.Config, workers, size int) (*services.NotificationService, *queueTestMocks) {
+	m := &queueTestMocks{
+		emailService:     new(MockEmailService),
+		notificationRepo: new(MockNotificationRepository),
+		sent:             new(atomic.Int32),
+	}
+	m.notificationRepo.On("Create", mock.Anything).Return(nil)
+	m.notificationRepo.On("Update", mock.Anything).Run(func(args mock.Arguments) {
+		if args.Get(0).(*models.Notification).Status == models.NotificationStatusSent {
+			m.sent.Add(1)
+		}
+	}).Return(nil)
+
+	service := services.NewNotificationService(m.emailService, nil, nil, nil, m.notificationRepo, nil, cfg)
+	service.StartQueue(workers, size)
+	return service, m
+}
+
+func emailRequest(recipient string, priority models.NotificationPriority) *models.CreateNotificationRequest {
+	return &models.CreateNotificationRequest{
+		Type:      models.NotificationTypeEmail,
+		Priority:  priority,
+		Recipient: recipient,
+		Subject:   "Hello",
+		Content:   "Hello from GateHide",
+	}
+}
+
+func TestNotificationService_Queue(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("processes enqueued notifications in the background", func(t *testing.T) {
+		service, m := newQueuedNotificationService(2, 10)
+		m.emailService.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, service.EnqueueNotification(ctx, emailRequest(fmt.Sprintf("user%d@example.com", i), "")))
+		}
+
+		assert.Eventually(t, func() bool {
+			return m.sent.Load() == 5
+		}, 2*time.Second, 10*time.Millisecond)
+		require.NoError(t, service.Shutdown(ctx))
+		m.emailService.AssertNumberOfCalls(t, "SendEmail", 5)
+	})
+
+	t.Run("returns before the notification is sent", func(t *testing.T) {
+		service, m := newQueuedNotificationService(1, 10)
+		release := make(chan time.Time)
+		m.emailService.On("SendEmail", mock.Anything, mock.Anything).WaitUntil(release).Return(nil)
+
+		done := make(chan error, 1)
+		go func() {
+			done <- service.SendNotification(ctx, emailRequest("user@example.com", models.NotificationPriorityNormal))
+		}()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("SendNotification waited for the email to be sent")
+		}
+
+		close(release)
+		require.NoError(t, service.Shutdown(ctx))
+		m.emailService.AssertNumberOfCalls(t, "SendEmail", 1)
+	})
+
+	t.Run("sends critical notifications synchronously", func(t *testing.T) {
+		service, m := newQueuedNotificationService(1, 10)
+		m.emailService.On("SendEmail", mock.Anything, mock.Anything).Return(fmt.Errorf("smtp error"))
+
+		err := service.SendNotification(ctx, emailRequest("user@example.com", models.NotificationPriorityHigh))
+
+		assert.ErrorContains(t, err, "smtp error")
+		m.notificationRepo.AssertCalled(t, "Update", mock.MatchedBy(func(n *models.Notification) bool {
+			return n.Status == models.NotificationStatusFailed
+		}))
+		require.NoError(t, service.Shutdown(ctx))
+	})
+
+	t.Run("queues critical notifications over the rate limit", func(t *testing.T) {
+		cfg := testutils.TestConfig()
+		cfg.Notification.Email.RateLimit.PerMinute = 60
+		cfg.Notification.Email.RateLimit.Burst = 1
+		service, m := newQueuedNotificationServiceWithConfig(cfg, 1, 10)
+		m.emailService.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+
+		require.NoError(t, service.SendNotification(ctx, emailRequest("first@example.com", models.NotificationPriorityHigh)))
+		assert.Equal(t, int32(1), m.sent.Load())
+
+		// The bucket is empty; the second send must not wait a second for the next token
+		started := time.Now()
+		require.NoError(t, service.SendNotification(ctx, emailRequest("second@example.com", models.NotificationPriorityHigh)))
+		assert.Less(t, time.Since(started), 500*time.Millisecond)
+
+		assert.Eventually(t, func() bool {
+			return m.sent.Load() == 2
+		}, 3*time.Second, 10*time.Millisecond)
+		require.NoError(t, service.Shutdown(ctx))
+		m.emailService.AssertNumberOfCalls(t, "SendEmail", 2)
+	})
+
+	t.Run("drains the queue on shutdown", func(t *testing.T) {
+		service, m := newQueuedNotificationService(1, 10)
+		m.emailService.On("SendEmail", mock.Anything, mock.Anything).After(10 * time.Millisecond).Return(nil)
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, service.EnqueueNotification(ctx, emailRequest(fmt.Sprintf("user%d@example.com", i), "")))
+		}
+
+		shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		require.NoError(t, service.Shutdown(shutdownCtx))
+
+		m.emailService.AssertNumberOfCalls(t, "SendEmail", 5)
+		assert.Equal(t, int32(5), m.sent.Load())
+		assert.Equal(t, 0, service.QueueLength())
+	})
+
+	t.Run("gives up waiting when shutdown times out", func(t *testing.T) {
+		service, m := newQueuedNotificationService(1, 10)
+		release := make(chan time.Time)
+		defer close(release)
+		m.emailService.On("SendEmail", mock.Anything, mock.Anything).WaitUntil(release).Return(nil)
+
+		require.NoError(t, service.EnqueueNotification(ctx, emailRequest("user@example.com", "")))
+
+		shutdownCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, service.Shutdown(shutdownCtx), context.DeadlineExceeded)
+	})
+
+	t.Run("sends synchronously once shut down", func(t *testing.T) {
+		service, m := newQueuedNotificationService(1, 10)
+		m.emailService.On("SendEmail", mock.Anything, mock.Anything).Return(nil)
+		require.NoError(t, service.Shutdown(ctx))
+
+		require.NoError(t, service.EnqueueNotification(ctx, emailRequest("user@example.com", "")))
+
+		m.emailService.AssertNumberOfCalls(t, "SendEmail", 1)
+	})
+}
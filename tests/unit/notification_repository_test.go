@@ -0,0 +1,138 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationCursor_EncodeDecode(t *testing.T) {
+	cursor := &models.NotificationCursor{CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), ID: 42}
+
+	decoded, err := models.DecodeNotificationCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+
+	for _, invalid := range []string{"not base64!", "Zm9v", cursor.Encode()[:4]} {
+		_, err := models.DecodeNotificationCursor(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestNotificationRepository_GetWithFilters_Cursor(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+
+	// Seed notifications where several share a created_at so ordering relies on the id tiebreak
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	const total = 7
+	for i := 0; i < total; i++ {
+		createdAt := base.Add(time.Duration(i/3) * time.Minute)
+		require.NoError(t, repo.Create(&models.Notification{
+			Type:      models.NotificationTypeEmail,
+			Status:    models.NotificationStatusSent,
+			Priority:  models.NotificationPriorityNormal,
+			Recipient: "cursor@example.com",
+			Subject:   "Seeded",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}))
+	}
+
+	// Walk the pages and collect IDs
+	var seen []int
+	var cursor *models.NotificationCursor
+	for page := 0; page < total; page++ {
+		filters := map[string]interface{}{"recipient": "cursor@example.com", "limit": 3}
+		if cursor != nil {
+			filters["cursor"] = cursor
+		}
+
+		notifications, err := repo.GetWithFilters(filters)
+		require.NoError(t, err)
+		for _, n := range notifications {
+			seen = append(seen, n.ID)
+		}
+		if len(notifications) < 3 {
+			break
+		}
+		cursor = models.NewNotificationCursor(notifications[len(notifications)-1])
+	}
+
+	// Every notification appears exactly once, newest first
+	all, err := repo.GetWithFilters(map[string]interface{}{"recipient": "cursor@example.com"})
+	require.NoError(t, err)
+	require.Len(t, all, total)
+
+	expected := make([]int, 0, total)
+	for _, n := range all {
+		expected = append(expected, n.ID)
+	}
+	assert.Equal(t, expected, seen)
+	for i := 1; i < len(all); i++ {
+		prev, cur := all[i-1], all[i]
+		assert.True(t, prev.CreatedAt.After(cur.CreatedAt) || (prev.CreatedAt.Equal(cur.CreatedAt) && prev.ID > cur.ID))
+	}
+}
+
+func TestNotificationRepository_GetWithFilters_RelatedUser(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	repo := repositories.NewMySQLNotificationRepository(db)
+
+	create := func(userID int, userType string) {
+		notification := &models.Notification{
+			Type:      models.NotificationTypeEmail,
+			Status:    models.NotificationStatusSent,
+			Priority:  models.NotificationPriorityNormal,
+			Recipient: "related@example.com",
+			Subject:   "Seeded",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if userID > 0 {
+			notification.RelatedUserID = &userID
+			notification.RelatedUserType = &userType
+		}
+		require.NoError(t, repo.Create(notification))
+	}
+
+	create(7, "user")
+	create(7, "user")
+	create(7, "admin")
+	create(8, "user")
+	create(0, "")
+
+	notifications, err := repo.GetWithFilters(map[string]interface{}{
+		"related_user_id":   7,
+		"related_user_type": "user",
+	})
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	for _, n := range notifications {
+		require.NotNil(t, n.RelatedUserID)
+		require.NotNil(t, n.RelatedUserType)
+		assert.Equal(t, 7, *n.RelatedUserID)
+		assert.Equal(t, "user", *n.RelatedUserType)
+	}
+
+	// Notifications without a related account still load
+	all, err := repo.GetWithFilters(map[string]interface{}{"recipient": "related@example.com"})
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+}
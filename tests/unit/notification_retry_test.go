@@ -0,0 +1,163 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// failedEmailNotification returns an email notification that failed retryCount times,
+// last updated at updatedAt
+func failedEmailNotification(id, retryCount int, updatedAt time.Time) *models.Notification {
+	errorMsg := "smtp error"
+	return &models.Notification{
+		ID:         id,
+		Type:       models.NotificationTypeEmail,
+		Status:     models.NotificationStatusFailed,
+		Priority:   models.NotificationPriorityNormal,
+		Recipient:  "user@example.com",
+		Subject:    "Hello",
+		Content:    "Hello from GateHide",
+		ErrorMsg:   &errorMsg,
+		RetryCount: retryCount,
+		UpdatedAt:  updatedAt,
+	}
+}
+
+func TestNotificationService_RetryFailedNotifications(t *testing.T) {
+	ctx := context.Background()
+	policy := services.NotificationRetryPolicy{MaxRetries: 3, Backoff: time.Minute, BatchSize: 100}
+
+	t.Run("retries a failed notification once its backoff has passed", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		notificationRepo := new(MockNotificationRepository)
+		notification := failedEmailNotification(1, 1, time.Now().Add(-time.Hour))
+		notificationRepo.On("GetRetryableNotifications", 100).Return([]*models.Notification{notification}, nil)
+		notificationRepo.On("Update", notification).Return(nil)
+		emailService.On("SendEmail", ctx, sentTo("user@example.com")).Return(nil)
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, nil, testutils.TestConfig())
+		summary, err := service.RetryFailedNotifications(ctx, policy)
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.NotificationRetrySummary{Retried: 1, Sent: 1}, summary)
+		assert.Equal(t, models.NotificationStatusSent, notification.Status)
+		assert.Equal(t, 2, notification.RetryCount)
+		assert.Nil(t, notification.ErrorMsg)
+		emailService.AssertExpectations(t)
+	})
+
+	t.Run("waits out the backoff", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		notificationRepo := new(MockNotificationRepository)
+		notification := failedEmailNotification(1, 1, time.Now())
+		notificationRepo.On("GetRetryableNotifications", 100).Return([]*models.Notification{notification}, nil)
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, nil, testutils.TestConfig())
+		summary, err := service.RetryFailedNotifications(ctx, policy)
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.NotificationRetrySummary{}, summary)
+		emailService.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything)
+		notificationRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("gives up after exhausting retries", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		notificationRepo := new(MockNotificationRepository)
+		notification := failedEmailNotification(1, 1, time.Now().Add(-time.Hour))
+		notificationRepo.On("GetRetryableNotifications", 100).Return([]*models.Notification{notification}, nil).Twice()
+		notificationRepo.On("GetRetryableNotifications", 100).Return([]*models.Notification{}, nil)
+		notificationRepo.On("Update", notification).Return(nil)
+		emailService.On("SendEmail", ctx, mock.Anything).Return(errors.New("smtp error"))
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, nil, testutils.TestConfig())
+		noBackoff := policy
+		noBackoff.Backoff = 0
+
+		summary, err := service.RetryFailedNotifications(ctx, noBackoff)
+		require.NoError(t, err)
+		assert.Equal(t, &services.NotificationRetrySummary{Retried: 1}, summary)
+		assert.Equal(t, models.NotificationStatusFailed, notification.Status)
+		assert.Equal(t, 2, notification.RetryCount)
+
+		summary, err = service.RetryFailedNotifications(ctx, noBackoff)
+		require.NoError(t, err)
+		assert.Equal(t, &services.NotificationRetrySummary{Retried: 1, GaveUp: 1}, summary)
+		assert.Equal(t, models.NotificationStatusPermanentlyFailed, notification.Status)
+		assert.Equal(t, 3, notification.RetryCount)
+
+		// Permanently failed notifications are no longer picked up
+		summary, err = service.RetryFailedNotifications(ctx, noBackoff)
+		require.NoError(t, err)
+		assert.Equal(t, &services.NotificationRetrySummary{}, summary)
+		emailService.AssertNumberOfCalls(t, "SendEmail", 2)
+	})
+
+	t.Run("gives up on a notification already at the limit without sending it", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		notificationRepo := new(MockNotificationRepository)
+		notification := failedEmailNotification(1, 3, time.Now().Add(-time.Hour))
+		notificationRepo.On("GetRetryableNotifications", 100).Return([]*models.Notification{notification}, nil)
+		notificationRepo.On("Update", notification).Return(nil)
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, nil, testutils.TestConfig())
+		summary, err := service.RetryFailedNotifications(ctx, policy)
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.NotificationRetrySummary{GaveUp: 1}, summary)
+		assert.Equal(t, models.NotificationStatusPermanentlyFailed, notification.Status)
+		emailService.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotificationService_SkipsDisabledSMS(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("records the send as skipped, not failed", func(t *testing.T) {
+		notificationRepo := new(MockNotificationRepository)
+		var saved *models.Notification
+		notificationRepo.On("Create", mock.AnythingOfType("*models.Notification")).
+			Run(func(args mock.Arguments) { saved = args.Get(0).(*models.Notification) }).
+			Return(nil)
+		notificationRepo.On("Update", mock.AnythingOfType("*models.Notification")).Return(nil)
+
+		service := services.NewNotificationService(nil, services.NewNoopSMSService(), nil, nil, notificationRepo, nil, testutils.TestConfig())
+		err := service.SendNotification(ctx, &models.CreateNotificationRequest{
+			Type:      models.NotificationTypeSMS,
+			Priority:  models.NotificationPriorityHigh,
+			Recipient: "09121234567",
+			Content:   "Hello",
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.NotificationStatusSkipped, saved.Status)
+		assert.Equal(t, 0, saved.RetryCount)
+		require.NotNil(t, saved.ErrorMsg)
+		assert.Contains(t, *saved.ErrorMsg, services.ErrSMSDisabled.Error())
+	})
+
+	t.Run("a retried SMS is skipped once SMS is disabled", func(t *testing.T) {
+		notificationRepo := new(MockNotificationRepository)
+		notification := failedEmailNotification(1, 1, time.Now().Add(-time.Hour))
+		notification.Type = models.NotificationTypeSMS
+		notification.Recipient = "09121234567"
+		notificationRepo.On("GetRetryableNotifications", 100).Return([]*models.Notification{notification}, nil)
+		notificationRepo.On("Update", notification).Return(nil)
+
+		service := services.NewNotificationService(nil, services.NewNoopSMSService(), nil, nil, notificationRepo, nil, testutils.TestConfig())
+		summary, err := service.RetryFailedNotifications(ctx, services.NotificationRetryPolicy{MaxRetries: 3, Backoff: time.Minute, BatchSize: 100})
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.NotificationRetrySummary{Retried: 1, Skipped: 1}, summary)
+		assert.Equal(t, models.NotificationStatusSkipped, notification.Status)
+	})
+}
@@ -0,0 +1,143 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/kavenegar/kavenegar-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSMSService_Sandbox(t *testing.T) {
+	ctx := context.Background()
+	sandboxNumber := "09120000000"
+
+	t.Run("reroutes plain SMS and notes the original receptor", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "10008663", []string{sandboxNumber},
+			mock.MatchedBy(func(message string) bool {
+				return strings.Contains(message, "9121111111") && strings.HasSuffix(message, "hello")
+			}), mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 7}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetSandbox(&config.SandboxConfig{Enabled: true, SMSRecipient: sandboxNumber})
+
+		err := smsService.SendSMS(ctx, &models.SMSNotification{To: "09121111111", Message: "hello"})
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("reroutes template SMS", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("VerifyLookup", sandboxNumber, "custom-user-template", "user@example.com", mock.Anything).
+			Return(kavenegar.Message{Status: 200}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategyVerifyOnly), client)
+		smsService.SetSandbox(&config.SandboxConfig{Enabled: true, SMSRecipient: sandboxNumber})
+
+		err := smsService.SendUserCredentials(ctx, "09121111111", "user@example.com", "12345678")
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("drops messages without a sandbox number", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetSandbox(&config.SandboxConfig{Enabled: true})
+
+		err := smsService.SendSMS(ctx, &models.SMSNotification{To: "09121111111", Message: "hello"})
+
+		assert.ErrorIs(t, err, services.ErrSMSSandboxed)
+		assert.ErrorIs(t, err, services.ErrSMSDisabled)
+		client.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("drops template messages the same way", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategyVerifyOnly), client)
+		smsService.SetSandbox(&config.SandboxConfig{Enabled: true})
+
+		err := smsService.SendUserCredentials(ctx, "09121111111", "user@example.com", "12345678")
+
+		assert.ErrorIs(t, err, services.ErrSMSSandboxed)
+		client.AssertNotCalled(t, "VerifyLookup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEmailService_Sandbox(t *testing.T) {
+	t.Run("rewrites recipients and notes the originals", func(t *testing.T) {
+		email := &models.EmailNotification{
+			To:       []string{"user@example.com"},
+			CC:       []string{"cc@example.com"},
+			BCC:      []string{"bcc@example.com"},
+			Subject:  "Welcome",
+			Body:     "Hello",
+			HTMLBody: "<p>Hello</p>",
+		}
+
+		rerouted := services.RerouteEmail(email, "sandbox@example.com")
+
+		assert.Equal(t, []string{"sandbox@example.com"}, rerouted.To)
+		assert.Empty(t, rerouted.CC)
+		assert.Empty(t, rerouted.BCC)
+		assert.Equal(t, "Welcome", rerouted.Subject)
+		assert.Contains(t, rerouted.Body, "user@example.com")
+		assert.Contains(t, rerouted.Body, "cc@example.com")
+		assert.Contains(t, rerouted.Body, "bcc@example.com")
+		assert.True(t, strings.HasSuffix(rerouted.Body, "Hello"))
+		assert.Contains(t, rerouted.HTMLBody, "user@example.com")
+		assert.True(t, strings.HasSuffix(rerouted.HTMLBody, "<p>Hello</p>"))
+
+		// The original notification is left untouched
+		assert.Equal(t, []string{"user@example.com"}, email.To)
+		assert.Equal(t, "Hello", email.Body)
+	})
+
+	t.Run("drops emails without a sandbox address", func(t *testing.T) {
+		// The SMTP host is unreachable, so reaching it would fail the send
+		emailService := services.NewEmailService(&config.EmailConfig{Enabled: true, SMTPHost: "invalid.invalid", SMTPPort: 1})
+		emailService.SetSandbox(&config.SandboxConfig{Enabled: true})
+
+		err := emailService.SendEmail(context.Background(), &models.EmailNotification{
+			To:      []string{"user@example.com"},
+			Subject: "Welcome",
+			Body:    "Hello",
+		})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestConfig_NotificationSandboxActive(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		environment string
+		expected    bool
+	}{
+		{"enabled in staging", true, "staging", true},
+		{"disabled in staging", false, "staging", false},
+		{"ignored in production", true, "production", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Server.Environment = tt.environment
+			cfg.Notification.Sandbox.Enabled = tt.enabled
+
+			assert.Equal(t, tt.expected, cfg.NotificationSandboxActive())
+		})
+	}
+}
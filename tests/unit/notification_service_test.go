@@ -0,0 +1,173 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockNotificationRepository is a mock implementation of NotificationRepository
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *models.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByID(id int) (*models.Notification, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetWithFilters(filters map[string]interface{}) ([]*models.Notification, error) {
+	args := m.Called(filters)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) Update(notification *models.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetPendingNotifications(limit int) ([]*models.Notification, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetFailedNotifications(limit int) ([]*models.Notification, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetRetryableNotifications(limit int) ([]*models.Notification, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Notification), args.Error(1)
+}
+
+// sentTo matches an email notification addressed to the given recipient
+func sentTo(recipient string) interface{} {
+	return mock.MatchedBy(func(email *models.EmailNotification) bool {
+		return len(email.To) == 1 && email.To[0] == recipient
+	})
+}
+
+func TestNotificationService_NotifyAdmins(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Sends to every admin", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		notificationRepo := new(MockNotificationRepository)
+		adminRepo := new(testutils.MockAdminRepository)
+		cfg := testutils.TestConfig()
+		cfg.Notification.AlertsRecipient = ""
+
+		admins := []string{"admin1@example.com", "admin2@example.com", "admin3@example.com"}
+		adminRepo.On("GetAllEmails").Return(admins, nil)
+		notificationRepo.On("Create", mock.Anything).Return(nil)
+		notificationRepo.On("Update", mock.Anything).Return(nil)
+		for _, admin := range admins {
+			emailService.On("SendEmail", ctx, sentTo(admin)).Return(nil).Once()
+		}
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, adminRepo, cfg)
+		err := service.NotifyAdmins(ctx, "Low SMS balance", "Top up the SMS account")
+
+		assert.NoError(t, err)
+		emailService.AssertExpectations(t)
+		emailService.AssertNumberOfCalls(t, "SendEmail", len(admins))
+		adminRepo.AssertExpectations(t)
+	})
+
+	t.Run("Continues past a failed recipient", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		notificationRepo := new(MockNotificationRepository)
+		adminRepo := new(testutils.MockAdminRepository)
+		cfg := testutils.TestConfig()
+		cfg.Notification.AlertsRecipient = ""
+
+		adminRepo.On("GetAllEmails").Return([]string{"admin1@example.com", "admin2@example.com"}, nil)
+		notificationRepo.On("Create", mock.Anything).Return(nil)
+		notificationRepo.On("Update", mock.Anything).Return(nil)
+		emailService.On("SendEmail", ctx, sentTo("admin1@example.com")).Return(errors.New("smtp error"))
+		emailService.On("SendEmail", ctx, sentTo("admin2@example.com")).Return(nil)
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, adminRepo, cfg)
+		err := service.NotifyAdmins(ctx, "New login", "A new device signed in")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "admin1@example.com")
+		emailService.AssertExpectations(t)
+	})
+
+	t.Run("Uses the alerts recipient override", func(t *testing.T) {
+		emailService := new(MockEmailService)
+		notificationRepo := new(MockNotificationRepository)
+		adminRepo := new(testutils.MockAdminRepository)
+		cfg := testutils.TestConfig()
+		cfg.Notification.AlertsRecipient = "alerts@example.com"
+
+		notificationRepo.On("Create", mock.Anything).Return(nil)
+		notificationRepo.On("Update", mock.Anything).Return(nil)
+		emailService.On("SendEmail", ctx, sentTo("alerts@example.com")).Return(nil)
+
+		service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, adminRepo, cfg)
+		err := service.NotifyAdmins(ctx, "Low SMS balance", "Top up the SMS account")
+
+		assert.NoError(t, err)
+		emailService.AssertExpectations(t)
+		adminRepo.AssertNotCalled(t, "GetAllEmails")
+	})
+}
+
+func TestNotificationService_SendsHTMLContent(t *testing.T) {
+	ctx := context.Background()
+	emailService := new(MockEmailService)
+	notificationRepo := new(MockNotificationRepository)
+	notificationRepo.On("Create", mock.Anything).Return(nil)
+	notificationRepo.On("Update", mock.Anything).Return(nil)
+	emailService.On("SendEmail", ctx, mock.MatchedBy(func(email *models.EmailNotification) bool {
+		return email.Body == "Hello" && email.HTMLBody == "<p>Hello</p>"
+	})).Return(nil).Once()
+
+	service := services.NewNotificationService(emailService, nil, nil, nil, notificationRepo, nil, testutils.TestConfig())
+	err := service.SendNotification(ctx, &models.CreateNotificationRequest{
+		Type:        models.NotificationTypeEmail,
+		Priority:    models.NotificationPriorityHigh,
+		Recipient:   "user@example.com",
+		Subject:     "Hi",
+		Content:     "Hello",
+		HTMLContent: "<p>Hello</p>",
+	})
+
+	require.NoError(t, err)
+	emailService.AssertExpectations(t)
+}
@@ -0,0 +1,249 @@
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/models"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// useTestPasswordHasher swaps the package-level hasher for the duration of a test
+func useTestPasswordHasher(t *testing.T, hasher models.PasswordHasher) {
+	previous := models.GetPasswordHasher()
+	models.SetPasswordHasher(hasher)
+	t.Cleanup(func() { models.SetPasswordHasher(previous) })
+}
+
+// newFastArgon2idHasher returns an Argon2id hasher with cheap parameters for tests
+func newFastArgon2idHasher() *models.Argon2idHasher {
+	return models.NewArgon2idHasher(1024, 1, 1)
+}
+
+func TestPasswordHasher_Bcrypt(t *testing.T) {
+	hasher := models.NewBcryptHasher(4)
+
+	hash, err := hasher.Hash("secret123")
+	require.NoError(t, err)
+
+	assert.True(t, hasher.Matches(hash))
+	assert.True(t, hasher.Verify("secret123", hash))
+	assert.False(t, hasher.Verify("wrong", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+	assert.True(t, models.NewBcryptHasher(5).NeedsRehash(hash))
+}
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	hasher := newFastArgon2idHasher()
+
+	hash, err := hasher.Hash("secret123")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	assert.True(t, hasher.Matches(hash))
+	assert.True(t, hasher.Verify("secret123", hash))
+	assert.False(t, hasher.Verify("wrong", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+	assert.True(t, models.NewArgon2idHasher(2048, 1, 1).NeedsRehash(hash))
+
+	// Salts are random, so hashing twice yields different strings
+	other, err := hasher.Hash("secret123")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	// Malformed hashes never verify
+	assert.False(t, hasher.Verify("secret123", "$argon2id$v=19$m=1024$bad"))
+}
+
+func TestCheckPassword_VerifiesAnyAlgorithm(t *testing.T) {
+	bcryptHash, err := models.NewBcryptHasher(4).Hash("secret123")
+	require.NoError(t, err)
+	argonHash, err := newFastArgon2idHasher().Hash("secret123")
+	require.NoError(t, err)
+
+	for _, current := range []models.PasswordHasher{models.NewBcryptHasher(4), newFastArgon2idHasher()} {
+		t.Run("Configured "+current.Algorithm(), func(t *testing.T) {
+			useTestPasswordHasher(t, current)
+
+			assert.True(t, models.CheckPassword("secret123", bcryptHash))
+			assert.True(t, models.CheckPassword("secret123", argonHash))
+			assert.False(t, models.CheckPassword("wrong", bcryptHash))
+			assert.False(t, models.CheckPassword("wrong", argonHash))
+			assert.False(t, models.CheckPassword("secret123", "plaintext"))
+		})
+	}
+}
+
+func TestHashPassword_UsesConfiguredHasher(t *testing.T) {
+	useTestPasswordHasher(t, newFastArgon2idHasher())
+
+	hash, err := models.HashPassword("secret123")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$"))
+	assert.False(t, models.PasswordNeedsRehash(hash))
+
+	bcryptHash, err := models.NewBcryptHasher(4).Hash("secret123")
+	require.NoError(t, err)
+	assert.True(t, models.PasswordNeedsRehash(bcryptHash))
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	t.Run("Bcrypt Default", func(t *testing.T) {
+		hasher, err := models.NewPasswordHasher(&config.SecurityConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, models.HashAlgorithmBcrypt, hasher.Algorithm())
+	})
+
+	t.Run("Argon2id", func(t *testing.T) {
+		hasher, err := models.NewPasswordHasher(&config.SecurityConfig{HashAlgorithm: "argon2id", Argon2Memory: 1024, Argon2Iterations: 1, Argon2Parallelism: 1})
+		require.NoError(t, err)
+		assert.Equal(t, models.HashAlgorithmArgon2id, hasher.Algorithm())
+	})
+
+	t.Run("Unsupported Algorithm", func(t *testing.T) {
+		_, err := models.NewPasswordHasher(&config.SecurityConfig{HashAlgorithm: "md5"})
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthService_Login_RehashesLegacyPassword(t *testing.T) {
+	useTestPasswordHasher(t, newFastArgon2idHasher())
+
+	authService, m := newMockedAuthService()
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	bcryptHash, err := models.NewBcryptHasher(4).Hash("password123")
+	require.NoError(t, err)
+	user.Password = models.PasswordHash(bcryptHash)
+	m.expectUserLogin(user)
+	m.userRepo.On("UpdatePassword", user.ID, mock.MatchedBy(func(hash string) bool {
+		return strings.HasPrefix(hash, "$argon2id$")
+	})).Return(nil)
+
+	response, err := authService.Login(user.Email, "password123", false)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, response.Token)
+	m.userRepo.AssertExpectations(t)
+}
+
+func TestPasswordHash_NeverSerialized(t *testing.T) {
+	const hash = "$2a$10$abcdefghijklmnopqrstuuExampleHashValueForTesting01234"
+
+	t.Run("direct marshal", func(t *testing.T) {
+		data, err := json.Marshal(models.PasswordHash(hash))
+		require.NoError(t, err)
+		assert.Equal(t, "null", string(data))
+
+		text, err := models.PasswordHash(hash).MarshalText()
+		require.NoError(t, err)
+		assert.Empty(t, text)
+	})
+
+	t.Run("models", func(t *testing.T) {
+		values := []interface{}{
+			models.User{ID: 1, Email: "user@example.com", Password: hash},
+			models.Admin{ID: 1, Email: "admin@example.com", Password: hash},
+			models.Gamenet{ID: 1, Email: "gamenet@example.com", Password: hash},
+			map[string]interface{}{"password": models.PasswordHash(hash)},
+		}
+		for _, value := range values {
+			data, err := json.Marshal(value)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), hash)
+		}
+	})
+
+	t.Run("formatting", func(t *testing.T) {
+		user := models.User{ID: 1, Password: hash}
+		for _, format := range []string{"%s", "%v", "%+v", "%#v", "%q"} {
+			assert.NotContains(t, fmt.Sprintf(format, user), hash, format)
+		}
+	})
+}
+
+func TestCheckPassword_EdgeCases(t *testing.T) {
+	hashers := map[string]models.PasswordHasher{
+		"bcrypt":   models.NewBcryptHasher(4),
+		"argon2id": newFastArgon2idHasher(),
+	}
+
+	exactly72 := strings.Repeat("a", models.MaxPasswordBytes)
+	persian := "رمز‌عبور-امن-۱۴۰۳"
+
+	for name, hasher := range hashers {
+		t.Run(name, func(t *testing.T) {
+			useTestPasswordHasher(t, hasher)
+
+			tests := []struct {
+				name     string
+				password string
+				attempts map[string]bool
+			}{
+				{"ascii", "secret123", map[string]bool{"secret123": true, "secret124": false, "Secret123": false, "": false}},
+				{"empty password", "", map[string]bool{"": true, " ": false, "secret123": false}},
+				{"unicode", persian, map[string]bool{persian: true, "رمز‌عبور-امن-۱۴۰۴": false, "رمزعبور-امن-۱۴۰۳": false}},
+				{"exactly 72 bytes", exactly72, map[string]bool{exactly72: true, exactly72[:71]: false, "b" + exactly72[1:]: false}},
+			}
+
+			for _, tt := range tests {
+				hash, err := models.HashPassword(tt.password)
+				require.NoError(t, err, tt.name)
+
+				for attempt, expected := range tt.attempts {
+					assert.Equal(t, expected, models.CheckPassword(attempt, hash), "%s: %q", tt.name, attempt)
+				}
+			}
+		})
+	}
+}
+
+func TestHashPassword_RejectsPasswordsOver72Bytes(t *testing.T) {
+	useTestPasswordHasher(t, models.NewBcryptHasher(4))
+
+	// 37 two-byte Persian letters are 74 bytes but only 37 characters
+	long := strings.Repeat("ر", 37)
+	require.Greater(t, len(long), models.MaxPasswordBytes)
+
+	_, err := models.HashPassword(long)
+	assert.ErrorIs(t, err, models.ErrPasswordTooLong)
+
+	_, err = models.HashPassword(strings.Repeat("a", models.MaxPasswordBytes+1))
+	assert.ErrorIs(t, err, models.ErrPasswordTooLong)
+
+	_, err = models.NewBcryptHasher(4).Hash(long)
+	assert.ErrorIs(t, err, models.ErrPasswordTooLong)
+}
+
+func TestCheckPassword_AcceptsLongPasswordsSetBeforeTheLimit(t *testing.T) {
+	long := strings.Repeat("p", models.MaxPasswordBytes) + "-set-before-the-limit"
+
+	// Argon2id hashes the whole password, so a long one was stored as-is
+	argon2Hash, err := newFastArgon2idHasher().Hash(long)
+	require.NoError(t, err)
+	assert.True(t, models.CheckPassword(long, argon2Hash))
+	assert.False(t, models.CheckPassword(long[:models.MaxPasswordBytes], argon2Hash))
+
+	// Older bcrypt versions hashed the first 72 bytes of a long password
+	bcryptHash, err := models.NewBcryptHasher(4).Hash(long[:models.MaxPasswordBytes])
+	require.NoError(t, err)
+	assert.True(t, models.CheckPassword(long, bcryptHash))
+	assert.True(t, models.NewBcryptHasher(4).Verify(long, bcryptHash))
+}
+
+func TestAuthService_RejectsPasswordsOver72Bytes(t *testing.T) {
+	authService, _ := newMockedAuthService()
+	long := strings.Repeat("a", models.MaxPasswordBytes+1)
+
+	err := authService.ResetPassword("token", "user@example.com", long, long)
+	assert.ErrorIs(t, err, models.ErrPasswordTooLong)
+
+	err = authService.ChangePassword(1, "user", "password123", long, long, "token")
+	assert.ErrorIs(t, err, models.ErrPasswordTooLong)
+}
@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePasswordStrength(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   config.PasswordPolicyConfig
+		password string
+		expected error
+	}{
+		{"min length met", config.PasswordPolicyConfig{MinLength: 8}, "abcdefgh", nil},
+		{"min length not met", config.PasswordPolicyConfig{MinLength: 8}, "abcdefg", utils.ErrPasswordTooShort},
+		{"min length counts characters", config.PasswordPolicyConfig{MinLength: 6}, "رمزعبور", nil},
+		{"no min length", config.PasswordPolicyConfig{}, "a", nil},
+		{"digit required and present", config.PasswordPolicyConfig{RequireDigit: true}, "abc1", nil},
+		{"digit required and missing", config.PasswordPolicyConfig{RequireDigit: true}, "abcd", utils.ErrPasswordNoDigit},
+		{"digit not required", config.PasswordPolicyConfig{}, "abcd", nil},
+		{"upper required and present", config.PasswordPolicyConfig{RequireUpper: true}, "abcD", nil},
+		{"upper required and missing", config.PasswordPolicyConfig{RequireUpper: true}, "abcd", utils.ErrPasswordNoUpper},
+		{"upper not required", config.PasswordPolicyConfig{}, "abcd", nil},
+		{"lower required and present", config.PasswordPolicyConfig{RequireLower: true}, "ABCd", nil},
+		{"lower required and missing", config.PasswordPolicyConfig{RequireLower: true}, "ABCD", utils.ErrPasswordNoLower},
+		{"lower not required", config.PasswordPolicyConfig{}, "ABCD", nil},
+		{"symbol required and present", config.PasswordPolicyConfig{RequireSymbol: true}, "abc!", nil},
+		{"symbol required and missing", config.PasswordPolicyConfig{RequireSymbol: true}, "abc1", utils.ErrPasswordNoSymbol},
+		{"symbol not required", config.PasswordPolicyConfig{}, "abc1", nil},
+		{"every rule met", config.PasswordPolicyConfig{MinLength: 8, RequireDigit: true, RequireUpper: true, RequireLower: true, RequireSymbol: true}, "Secret#123", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := utils.ValidatePasswordStrength(tt.password, tt.policy)
+
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expected)
+			assert.ErrorIs(t, err, utils.ErrWeakPassword)
+		})
+	}
+}
+
+func TestValidatePasswordStrength_DefaultPolicy(t *testing.T) {
+	policy := config.Load().Security.PasswordPolicy
+
+	assert.Equal(t, config.PasswordPolicyConfig{MinLength: 6}, policy)
+	assert.NoError(t, utils.ValidatePasswordStrength("123456", policy))
+
+	err := utils.ValidatePasswordStrength("12345", policy)
+	assert.ErrorIs(t, err, utils.ErrPasswordTooShort)
+	assert.EqualError(t, err, "password must be at least 6 characters long")
+}
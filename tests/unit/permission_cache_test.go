@@ -0,0 +1,134 @@
+package unit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedPermissionRepository_HasPermission(t *testing.T) {
+	t.Run("serves repeated checks from the cache", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("HasPermission", "administrator", "subscription_plans", "create").Return(true, nil).Once()
+		cached := repositories.NewCachedPermissionRepository(repo, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			allowed, err := cached.HasPermission("administrator", "subscription_plans", "create")
+			assert.NoError(t, err)
+			assert.True(t, allowed)
+		}
+
+		repo.AssertNumberOfCalls(t, "HasPermission", 1)
+	})
+
+	t.Run("refreshes after the role is invalidated", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("HasPermission", "gamenet", "users", "delete").Return(true, nil).Once()
+		repo.On("HasPermission", "gamenet", "users", "delete").Return(false, nil).Once()
+		cached := repositories.NewCachedPermissionRepository(repo, time.Minute)
+
+		allowed, _ := cached.HasPermission("gamenet", "users", "delete")
+		assert.True(t, allowed)
+
+		cached.InvalidateRole("gamenet")
+		allowed, _ = cached.HasPermission("gamenet", "users", "delete")
+
+		assert.False(t, allowed)
+		repo.AssertNumberOfCalls(t, "HasPermission", 2)
+	})
+
+	t.Run("refreshes expired entries", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("HasPermission", "user", "wallet", "view").Return(true, nil)
+		cached := repositories.NewCachedPermissionRepository(repo, 10*time.Millisecond)
+
+		cached.HasPermission("user", "wallet", "view")
+		time.Sleep(20 * time.Millisecond)
+		cached.HasPermission("user", "wallet", "view")
+
+		repo.AssertNumberOfCalls(t, "HasPermission", 2)
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("HasPermission", "user", "wallet", "view").Return(false, errors.New("database unavailable")).Once()
+		repo.On("HasPermission", "user", "wallet", "view").Return(true, nil).Once()
+		cached := repositories.NewCachedPermissionRepository(repo, time.Minute)
+
+		_, err := cached.HasPermission("user", "wallet", "view")
+		assert.Error(t, err)
+		allowed, err := cached.HasPermission("user", "wallet", "view")
+
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	})
+}
+
+func TestCachedPermissionRepository_HasUserPermission(t *testing.T) {
+	t.Run("serves repeated checks from the cache", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("HasUserPermission", 7, "admin", "gamenets", "read").Return(true, nil).Once()
+		cached := repositories.NewCachedPermissionRepository(repo, time.Minute)
+
+		cached.HasUserPermission(7, "admin", "gamenets", "read")
+		allowed, err := cached.HasUserPermission(7, "admin", "gamenets", "read")
+
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		repo.AssertNumberOfCalls(t, "HasUserPermission", 1)
+	})
+
+	t.Run("refreshes after a role change", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("HasUserPermission", 7, "user", "users", "read").Return(false, nil).Once()
+		repo.On("HasUserPermission", 7, "user", "users", "read").Return(true, nil).Once()
+		repo.On("AssignRoleToUser", 7, "user", "gamenet").Return(nil)
+		cached := repositories.NewCachedPermissionRepository(repo, time.Minute)
+
+		allowed, _ := cached.HasUserPermission(7, "user", "users", "read")
+		assert.False(t, allowed)
+
+		assert.NoError(t, cached.AssignRoleToUser(7, "user", "gamenet"))
+		allowed, _ = cached.HasUserPermission(7, "user", "users", "read")
+
+		assert.True(t, allowed)
+		repo.AssertNumberOfCalls(t, "HasUserPermission", 2)
+	})
+
+	t.Run("refreshes after a role is invalidated", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("HasUserPermission", 7, "admin", "gamenets", "read").Return(true, nil)
+		cached := repositories.NewCachedPermissionRepository(repo, time.Minute)
+
+		cached.HasUserPermission(7, "admin", "gamenets", "read")
+		cached.InvalidateRole("administrator")
+		cached.HasUserPermission(7, "admin", "gamenets", "read")
+
+		repo.AssertNumberOfCalls(t, "HasUserPermission", 2)
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		repo := new(MockPermissionRepository)
+		repo.On("HasUserPermission", 7, "admin", "gamenets", "read").Return(true, nil)
+		repo.On("RemoveRoleFromUser", 7, "admin", "administrator").Return(nil)
+		cached := repositories.NewCachedPermissionRepository(repo, time.Minute)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				cached.HasUserPermission(7, "admin", "gamenets", "read")
+			}()
+			go func() {
+				defer wg.Done()
+				cached.RemoveRoleFromUser(7, "admin", "administrator")
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,112 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionHandler_AssignUserRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	admin := &models.Actor{ID: 1, Type: "admin"}
+
+	tests := []struct {
+		name         string
+		userID       string
+		body         string
+		setupMock    func(*testutils.MockPermissionService)
+		expectedCode int
+	}{
+		{
+			name:   "assigns the role",
+			userID: "5",
+			body:   `{"role":"support"}`,
+			setupMock: func(m *testutils.MockPermissionService) {
+				m.On("AssignRole", 5, "user", "support", admin).Return(nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "rejects a duplicate assignment",
+			userID: "5",
+			body:   `{"role":"support"}`,
+			setupMock: func(m *testutils.MockPermissionService) {
+				m.On("AssignRole", 5, "user", "support", admin).Return(services.ErrRoleAlreadyAssigned)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:   "role reserved for another account type",
+			userID: "5",
+			body:   `{"role":"administrator"}`,
+			setupMock: func(m *testutils.MockPermissionService) {
+				m.On("AssignRole", 5, "user", "administrator", admin).Return(fmt.Errorf("%w: administrator to user", services.ErrRoleNotAssignable))
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "unknown role",
+			userID: "5",
+			body:   `{"role":"no_such_role"}`,
+			setupMock: func(m *testutils.MockPermissionService) {
+				m.On("AssignRole", 5, "user", "no_such_role", admin).Return(fmt.Errorf("failed to get role: %w", repositories.ErrNotFound))
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "missing role",
+			userID:       "5",
+			body:         `{}`,
+			setupMock:    func(m *testutils.MockPermissionService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "invalid user ID",
+			userID:       "abc",
+			body:         `{"role":"support"}`,
+			setupMock:    func(m *testutils.MockPermissionService) {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "service failure",
+			userID: "5",
+			body:   `{"role":"support"}`,
+			setupMock: func(m *testutils.MockPermissionService) {
+				m.On("AssignRole", 5, "user", "support", admin).Return(errors.New("database unavailable"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissionService := new(testutils.MockPermissionService)
+			tt.setupMock(permissionService)
+			handler := handlers.NewPermissionHandler(permissionService)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: tt.userID}}
+			c.Request = httptest.NewRequest(http.MethodPost, "/users/"+tt.userID+"/roles", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user", &utils.JWTClaims{UserID: admin.ID, UserType: admin.Type})
+
+			handler.AssignUserRole(c)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			permissionService.AssertExpectations(t)
+		})
+	}
+}
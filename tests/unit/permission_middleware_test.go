@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		authenticate bool
+		setupMock    func(*testutils.MockPermissionService)
+		expectedCode int
+	}{
+		{
+			name:         "allows a granted permission",
+			authenticate: true,
+			setupMock: func(m *testutils.MockPermissionService) {
+				m.On("CheckUserPermission", 3, "admin", "subscription_plans", "create").Return(nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "denies a missing permission",
+			authenticate: true,
+			setupMock: func(m *testutils.MockPermissionService) {
+				m.On("CheckUserPermission", 3, "admin", "subscription_plans", "create").
+					Return(fmt.Errorf("%w: subscription_plans:create for user 3", services.ErrPermissionDenied))
+			},
+			expectedCode: http.StatusForbidden,
+		},
+		{
+			name:         "reports a failed lookup",
+			authenticate: true,
+			setupMock: func(m *testutils.MockPermissionService) {
+				m.On("CheckUserPermission", 3, "admin", "subscription_plans", "create").
+					Return(errors.New("failed to check user permission: database unavailable"))
+			},
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "requires an authenticated account",
+			authenticate: false,
+			setupMock:    func(m *testutils.MockPermissionService) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissionService := new(testutils.MockPermissionService)
+			tt.setupMock(permissionService)
+
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.authenticate {
+					c.Set("user_id", 3)
+					c.Set("user_type", "admin")
+				}
+				c.Next()
+			})
+			router.POST("/plans", middlewares.RequirePermission(permissionService, "subscription_plans", "create"), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/plans", nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			permissionService.AssertExpectations(t)
+		})
+	}
+}
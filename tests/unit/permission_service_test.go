@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionService_GetUserPermissionsByID_MultipleRoles(t *testing.T) {
+	// The user holds two roles, which both grant support:access and settings:manage
+	repo := new(MockPermissionRepository)
+	repo.On("GetUserPermissions", 7, "user").Return([]models.Permission{
+		{Resource: "reservation", Action: "manage"},
+		{Resource: "settings", Action: "manage"},
+		{Resource: "support", Action: "access"},
+		{Resource: "wallet", Action: "view"},
+		{Resource: "analytics", Action: "view"},
+		{Resource: "settings", Action: "manage"},
+		{Resource: "support", Action: "access"},
+		{Resource: "users", Action: "read"},
+	}, nil)
+	permissionService := services.NewPermissionService(repo, nil)
+
+	permissions, err := permissionService.GetUserPermissionsByID(7, "user")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"reservation:manage",
+		"settings:manage",
+		"support:access",
+		"wallet:view",
+		"analytics:view",
+		"users:read",
+	}, permissions)
+}
+
+func TestPermissionService_AssignRole_RejectsRolesOfOtherAccountTypes(t *testing.T) {
+	actor := &models.Actor{ID: 1, Type: "admin"}
+
+	for _, role := range []string{models.RoleAdministrator, models.RoleGamenet} {
+		t.Run(role, func(t *testing.T) {
+			repo := new(MockPermissionRepository)
+			permissionService := services.NewPermissionService(repo, nil)
+
+			err := permissionService.AssignRole(5, models.RoleUser, role, actor)
+
+			assert.ErrorIs(t, err, services.ErrRoleNotAssignable)
+			repo.AssertNotCalled(t, "AssignRoleToUser", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/migrations"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// postgresTestFiles are PostgreSQL migrations used by the runner tests
+func postgresTestFiles() []migrations.MigrationFile {
+	return []migrations.MigrationFile{
+		{
+			Version:     "901_pg_create_accounts",
+			Description: "Create accounts",
+			UpSQL:       "CREATE TABLE pg_runner_accounts (id SERIAL PRIMARY KEY, name VARCHAR(100) NOT NULL);",
+			DownSQL:     "DROP TABLE IF EXISTS pg_runner_accounts;",
+		},
+		{
+			Version:     "902_pg_add_balance",
+			Description: "Add balance",
+			UpSQL:       "ALTER TABLE pg_runner_accounts ADD COLUMN balance NUMERIC(10,2) NOT NULL DEFAULT 0;\nINSERT INTO pg_runner_accounts (name) VALUES ('it''s; fine');",
+			DownSQL:     "ALTER TABLE pg_runner_accounts DROP COLUMN balance;",
+		},
+	}
+}
+
+func newPostgresTestRunner(t *testing.T) (*migrations.PostgresRunner, *sql.DB) {
+	testutils.SkipIfNoPostgres(t)
+	os.Setenv("DB_AUTO_CREATE", "true")
+
+	cfg := testutils.PostgresTestConfig()
+	runner, err := migrations.NewPostgresRunner(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { runner.Close() })
+
+	db, err := sql.Open("postgres", cfg.GetDSN())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.Exec("DROP TABLE IF EXISTS pg_runner_accounts")
+		db.Exec("DROP TABLE IF EXISTS migrations")
+		db.Close()
+	})
+
+	return runner, db
+}
+
+// pgTableExists reports whether a table exists in the current schema
+func pgTableExists(t *testing.T, db *sql.DB, table string) bool {
+	var exists bool
+	require.NoError(t, db.QueryRow("SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists))
+	return exists
+}
+
+func TestPostgresRunner_UpDown(t *testing.T) {
+	runner, db := newPostgresTestRunner(t)
+	files := postgresTestFiles()
+
+	var out bytes.Buffer
+	require.NoError(t, migrations.Up(runner, files, migrations.RunOptions{Steps: 10, Out: &out}))
+
+	applied, err := runner.GetAppliedMigrations()
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, "902_pg_add_balance", applied[1].Version)
+	assert.Equal(t, files[1].Checksum(), applied[1].Checksum)
+
+	var name string
+	require.NoError(t, db.QueryRow("SELECT name FROM pg_runner_accounts").Scan(&name))
+	assert.Equal(t, "it's; fine", name)
+
+	require.NoError(t, migrations.Down(runner, files, migrations.RunOptions{Steps: 10, Out: &out}))
+
+	applied, err = runner.GetAppliedMigrations()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	assert.False(t, pgTableExists(t, db, "pg_runner_accounts"))
+}
+
+func TestPostgresRunner_FailedMigrationRollsBackDDL(t *testing.T) {
+	runner, db := newPostgresTestRunner(t)
+	require.NoError(t, runner.CreateMigrationTable())
+
+	// PostgreSQL DDL is transactional, so the CREATE TABLE is undone too
+	err := runner.ApplyMigration("903_pg_broken", "Broken", "CREATE TABLE pg_runner_accounts (id INT);\nINSERT INTO missing_table VALUES (1);")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "statement 2 of 2")
+
+	assert.False(t, pgTableExists(t, db, "pg_runner_accounts"))
+	applied, err := runner.GetAppliedMigrations()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
+
+func TestPostgresRunner_Lock(t *testing.T) {
+	runner, _ := newPostgresTestRunner(t)
+
+	unlock, err := runner.Lock(time.Second)
+	require.NoError(t, err)
+
+	_, err = runner.Lock(300 * time.Millisecond)
+	assert.ErrorIs(t, err, migrations.ErrMigrationInProgress)
+
+	require.NoError(t, unlock())
+	unlock, err = runner.Lock(time.Second)
+	require.NoError(t, err)
+	assert.NoError(t, unlock())
+}
@@ -0,0 +1,139 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRateLimitedRouter limits POST /login and POST /forgot-password to limit requests
+// per minute each. Like the app router, it only trusts X-Forwarded-For from the given
+// proxies.
+func newRateLimitedRouter(limit int, trustedProxies ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		panic(err)
+	}
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"message": "success"}) }
+	router.POST("/login", middlewares.RateLimit(limit, time.Minute), ok)
+	router.POST("/forgot-password", middlewares.RateLimit(limit, time.Minute), ok)
+	return router
+}
+
+func sendFrom(router *gin.Engine, path, remoteAddr string) *httptest.ResponseRecorder {
+	return sendForwardedFrom(router, path, remoteAddr, "")
+}
+
+// sendForwardedFrom sends a request with the given X-Forwarded-For header
+func sendForwardedFrom(router *gin.Engine, path, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	const limit = 5
+
+	t.Run("rejects the request over the limit", func(t *testing.T) {
+		router := newRateLimitedRouter(limit)
+
+		for i := 0; i < limit; i++ {
+			assert.Equal(t, http.StatusOK, sendFrom(router, "/login", "10.0.0.1:1234").Code)
+		}
+
+		w := sendFrom(router, "/login", "10.0.0.1:1234")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+		assert.NoError(t, err)
+		// One request's worth of the window: 60s / 5
+		assert.Equal(t, 12, retryAfter)
+		assert.Contains(t, w.Body.String(), "error")
+	})
+
+	t.Run("limits each IP separately", func(t *testing.T) {
+		router := newRateLimitedRouter(limit)
+
+		for i := 0; i < limit; i++ {
+			sendFrom(router, "/login", "10.0.0.1:1234")
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, sendFrom(router, "/login", "10.0.0.1:1234").Code)
+		assert.Equal(t, http.StatusOK, sendFrom(router, "/login", "10.0.0.2:1234").Code)
+	})
+
+	t.Run("ignores X-Forwarded-For from untrusted clients", func(t *testing.T) {
+		router := newRateLimitedRouter(limit)
+
+		for i := 0; i < limit; i++ {
+			forwarded := "203.0.113." + strconv.Itoa(i+1)
+			assert.Equal(t, http.StatusOK, sendForwardedFrom(router, "/login", "10.0.0.1:1234", forwarded).Code)
+		}
+
+		// A fresh spoofed address doesn't get a fresh bucket
+		assert.Equal(t, http.StatusTooManyRequests, sendForwardedFrom(router, "/login", "10.0.0.1:1234", "203.0.113.99").Code)
+	})
+
+	t.Run("uses X-Forwarded-For from a trusted proxy", func(t *testing.T) {
+		router := newRateLimitedRouter(limit, "10.0.0.0/8")
+
+		for i := 0; i < limit; i++ {
+			sendForwardedFrom(router, "/login", "10.0.0.1:1234", "203.0.113.1")
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, sendForwardedFrom(router, "/login", "10.0.0.1:1234", "203.0.113.1").Code)
+		assert.Equal(t, http.StatusOK, sendForwardedFrom(router, "/login", "10.0.0.1:1234", "203.0.113.2").Code)
+	})
+
+	t.Run("limits each route separately", func(t *testing.T) {
+		router := newRateLimitedRouter(limit)
+
+		for i := 0; i < limit; i++ {
+			sendFrom(router, "/login", "10.0.0.1:1234")
+		}
+
+		assert.Equal(t, http.StatusOK, sendFrom(router, "/forgot-password", "10.0.0.1:1234").Code)
+	})
+
+	t.Run("is safe for concurrent requests", func(t *testing.T) {
+		router := newRateLimitedRouter(limit)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		statuses := map[int]int{}
+		for i := 0; i < 4*limit; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				code := sendFrom(router, "/login", "10.0.0.1:1234").Code
+				mu.Lock()
+				statuses[code]++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, limit, statuses[http.StatusOK])
+		assert.Equal(t, 3*limit, statuses[http.StatusTooManyRequests])
+	})
+
+	t.Run("zero disables the limit", func(t *testing.T) {
+		router := newRateLimitedRouter(0)
+
+		for i := 0; i < 20; i++ {
+			assert.Equal(t, http.StatusOK, sendFrom(router, "/login", "10.0.0.1:1234").Code)
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReauthTestService creates an AuthService allowing maxAttempts failed password confirmations
+func newReauthTestService(maxAttempts int) (*services.AuthService, *authServiceMocks) {
+	m := &authServiceMocks{
+		userRepo:          new(MockUserRepository),
+		adminRepo:         new(testutils.MockAdminRepository),
+		gamenetRepo:       new(testutils.MockGamenetRepository),
+		sessionRepo:       new(testutils.MockSessionRepository),
+		loginAuditRepo:    new(testutils.MockLoginAuditRepository),
+		permissionService: new(testutils.MockPermissionService),
+	}
+
+	cfg := testutils.TestConfig()
+	cfg.Security.ReauthMaxAttempts = maxAttempts
+	authService := services.NewAuthService(m.userRepo, m.adminRepo, m.gamenetRepo, nil, m.sessionRepo, m.loginAuditRepo, nil, nil, m.permissionService, cfg)
+	return authService, m
+}
+
+func TestAuthService_VerifyPassword(t *testing.T) {
+	t.Run("correct password issues a re-auth token", func(t *testing.T) {
+		authService, m := newReauthTestService(5)
+		m.adminRepo.On("GetByID", 3).Return(testutils.CreateMockAdmin(3, "admin@example.com", "Admin"), nil)
+
+		reauth, err := authService.VerifyPassword(3, "admin", "admin123")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, reauth.ReauthToken)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), reauth.ExpiresAt.Time, 5*time.Second)
+
+		claims, err := authService.ValidateReauthToken(reauth.ReauthToken)
+		require.NoError(t, err)
+		assert.Equal(t, 3, claims.UserID)
+		assert.Equal(t, "admin", claims.UserType)
+
+		// A re-auth token is not an access token
+		_, err = authService.ValidateToken(reauth.ReauthToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("incorrect password", func(t *testing.T) {
+		authService, m := newReauthTestService(5)
+		m.userRepo.On("GetByID", 1).Return(testutils.CreateMockUser(1, "user@example.com", "User"), nil)
+
+		reauth, err := authService.VerifyPassword(1, "user", "wrong-password")
+
+		assert.ErrorIs(t, err, services.ErrInvalidPassword)
+		assert.Nil(t, reauth)
+	})
+
+	t.Run("failed attempts are limited per account", func(t *testing.T) {
+		authService, m := newReauthTestService(2)
+		m.userRepo.On("GetByID", 1).Return(testutils.CreateMockUser(1, "user@example.com", "User"), nil)
+		m.userRepo.On("GetByID", 2).Return(testutils.CreateMockUser(2, "other@example.com", "Other"), nil)
+
+		for i := 0; i < 2; i++ {
+			_, err := authService.VerifyPassword(1, "user", "wrong-password")
+			assert.ErrorIs(t, err, services.ErrInvalidPassword)
+		}
+
+		// Even the correct password is rejected while blocked
+		_, err := authService.VerifyPassword(1, "user", "password123")
+		assert.ErrorIs(t, err, services.ErrTooManyReauthAttempts)
+		var tooMany *services.TooManyAttemptsError
+		require.True(t, errors.As(err, &tooMany))
+		assert.Greater(t, tooMany.RetryAfter, time.Duration(0))
+
+		// Other accounts are unaffected
+		_, err = authService.VerifyPassword(2, "user", "password123")
+		assert.NoError(t, err)
+	})
+
+	t.Run("concurrent attempts cannot exceed the limit", func(t *testing.T) {
+		authService, m := newReauthTestService(2)
+		m.userRepo.On("GetByID", 1).Return(testutils.CreateMockUser(1, "user@example.com", "User"), nil)
+
+		errs := make(chan error, 10)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := authService.VerifyPassword(1, "user", "wrong-password")
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		checked := 0
+		for err := range errs {
+			if errors.Is(err, services.ErrInvalidPassword) {
+				checked++
+			} else {
+				assert.ErrorIs(t, err, services.ErrTooManyReauthAttempts)
+			}
+		}
+		assert.Equal(t, 2, checked)
+	})
+
+	t.Run("lookup failures do not count against the limit", func(t *testing.T) {
+		authService, m := newReauthTestService(1)
+		m.userRepo.On("GetByID", 1).Return(nil, errors.New("database error")).Times(3)
+		m.userRepo.On("GetByID", 1).Return(testutils.CreateMockUser(1, "user@example.com", "User"), nil)
+
+		for i := 0; i < 3; i++ {
+			_, err := authService.VerifyPassword(1, "user", "password123")
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, services.ErrTooManyReauthAttempts)
+		}
+
+		_, err := authService.VerifyPassword(1, "user", "password123")
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown user type", func(t *testing.T) {
+		authService, _ := newReauthTestService(5)
+
+		_, err := authService.VerifyPassword(1, "robot", "password123")
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, services.ErrInvalidPassword)
+	})
+}
+
+func TestAttemptLimiter(t *testing.T) {
+	limiter := utils.NewAttemptLimiter(2, time.Minute)
+
+	limiter.Fail("a")
+	allowed, _ := limiter.Allow("a")
+	assert.True(t, allowed)
+
+	limiter.Fail("a")
+	allowed, retryAfter := limiter.Allow("a")
+	assert.False(t, allowed)
+	assert.InDelta(t, time.Minute.Seconds(), retryAfter.Seconds(), 1)
+
+	limiter.Reset("a")
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed)
+
+	unlimited := utils.NewAttemptLimiter(0, time.Minute)
+	for i := 0; i < 10; i++ {
+		unlimited.Fail("a")
+	}
+	allowed, _ = unlimited.Allow("a")
+	assert.True(t, allowed)
+}
+
+func TestVerifyPasswordAndRequireReauth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authService, m := newReauthTestService(5)
+	m.adminRepo.On("GetByID", 3).Return(testutils.CreateMockAdmin(3, "admin@example.com", "Admin"), nil)
+	m.adminRepo.On("GetByID", 4).Return(testutils.CreateMockAdmin(4, "other@example.com", "Other"), nil)
+
+	// authenticateAs stands in for the session middleware
+	authenticateAs := func(userID int) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			c.Set("user_id", userID)
+			c.Set("user_type", "admin")
+			c.Set("user", &utils.JWTClaims{UserID: userID, UserType: "admin"})
+			c.Next()
+		}
+	}
+
+	authHandler := handlers.NewAuthHandler(authService, nil)
+	router := gin.New()
+	router.POST("/admin3/auth/verify-password", authenticateAs(3), authHandler.VerifyPassword)
+	router.POST("/admin4/auth/verify-password", authenticateAs(4), authHandler.VerifyPassword)
+	router.POST("/admin3/sensitive", authenticateAs(3), middlewares.RequireReauth(authService), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "done"})
+	})
+
+	verify := func(path, password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"password": password})
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	sensitive := func(reauthToken string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin3/sensitive", nil)
+		if reauthToken != "" {
+			req.Header.Set(middlewares.ReauthTokenHeader, reauthToken)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	reauthToken := func(w *httptest.ResponseRecorder) string {
+		var response struct {
+			Data struct {
+				ReauthToken string `json:"reauth_token"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Data.ReauthToken
+	}
+
+	t.Run("incorrect password", func(t *testing.T) {
+		w := verify("/admin3/auth/verify-password", "wrong-password")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("missing password", func(t *testing.T) {
+		w := verify("/admin3/auth/verify-password", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("sensitive action without re-auth token", func(t *testing.T) {
+		w := sensitive("")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "reauth_required")
+	})
+
+	t.Run("sensitive action with re-auth token", func(t *testing.T) {
+		w := verify("/admin3/auth/verify-password", "admin123")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+		w = sensitive(reauthToken(w))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("re-auth token of another account is rejected", func(t *testing.T) {
+		w := verify("/admin4/auth/verify-password", "admin123")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = sensitive(reauthToken(w))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("access token is not a re-auth token", func(t *testing.T) {
+		accessToken, err := authService.GetJWTManager().GenerateToken(3, "admin", "admin@example.com", "Admin", false)
+		require.NoError(t, err)
+
+		w := sensitive(accessToken)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
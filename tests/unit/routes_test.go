@@ -0,0 +1,149 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/routes"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRoutedTestRouter sets up the application routes against a database handle that is
+// never connected, for tests of the middleware chain in front of the handlers
+func newRoutedTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	db, err := sql.Open("mysql", "test:test@tcp(127.0.0.1:1)/unused")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return newRoutedTestRouterWithDB(t, db)
+}
+
+// newRoutedTestRouterWithDB sets up the application routes against the given database handle
+func newRoutedTestRouterWithDB(t *testing.T, db *sql.DB) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	shutdown, _ := routes.SetupRoutes(router, testutils.TestConfig(), db)
+	t.Cleanup(func() { shutdown(context.Background()) })
+	return router
+}
+
+func TestRoutes_UserImportAcceptsMultipart(t *testing.T) {
+	router := newRoutedTestRouter(t)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "users.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte("name,email\nSara,sara@example.com\n"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// The upload passes the JSON content type check and is stopped by authentication
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Other user routes still require JSON
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/users/", bytes.NewBufferString("name=Sara"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
+
+// signInAs answers the session lookups of the auth middleware for a token issued to the
+// given account and returns the token
+func signInAs(t *testing.T, fake *testutils.FakeDB, userID int, userType string) string {
+	token, err := utils.NewJWTManager(testutils.TestConfig()).GenerateToken(userID, userType, "signed-in@example.com", "Signed In", false)
+	require.NoError(t, err)
+
+	now := time.Now()
+	fake.OnQuery("FROM user_sessions", []string{
+		"id", "user_id", "user_type", "session_token", "device_info", "ip_address", "user_agent",
+		"is_active", "last_activity_at", "created_at", "expires_at",
+	}, []driver.Value{int64(1), int64(userID), userType, token, nil, nil, nil, true, now, now, now.Add(time.Hour)})
+	fake.OnQuery("FROM revoked_tokens", []string{"revoked"}, []driver.Value{false})
+
+	return token
+}
+
+func TestRoutes_AdminTerminatesUserSessions(t *testing.T) {
+	db, fake := testutils.NewFakeDB(t)
+	router := newRoutedTestRouterWithDB(t, db)
+	token := signInAs(t, fake, 1, "admin")
+	now := time.Now()
+	fake.OnQuery("FROM users", []string{
+		"id", "name", "mobile", "email", "password", "image", "balance", "debt",
+		"last_login_at", "suspended_at", "deleted_at", "created_at", "updated_at",
+	}, []driver.Value{int64(42), "Sara", "09121234567", "sara@example.com", "", nil, 0.0, 0.0, nil, nil, nil, now, now})
+	fake.OnExec("SET is_active = FALSE", 2)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/42/terminate-sessions", bytes.NewBufferString(`{"reason":"account compromised"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response struct {
+		Data struct {
+			AccountID          int    `json:"account_id"`
+			AccountType        string `json:"account_type"`
+			TerminatedSessions int    `json:"terminated_sessions"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 42, response.Data.AccountID)
+	assert.Equal(t, "user", response.Data.AccountType)
+	assert.Equal(t, 2, response.Data.TerminatedSessions)
+}
+
+func TestRoutes_TerminateSessionsOfUnknownUser(t *testing.T) {
+	db, fake := testutils.NewFakeDB(t)
+	router := newRoutedTestRouterWithDB(t, db)
+	token := signInAs(t, fake, 1, "admin")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/404/terminate-sessions", bytes.NewBufferString(`{"reason":"account compromised"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	assert.False(t, fake.Ran("SET is_active = FALSE"))
+}
+
+func TestRoutes_GamenetCannotTerminateUserSessions(t *testing.T) {
+	db, fake := testutils.NewFakeDB(t)
+	router := newRoutedTestRouterWithDB(t, db)
+	token := signInAs(t, fake, 5, "gamenet")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/42/terminate-sessions", bytes.NewBufferString(`{"reason":"account compromised"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.False(t, fake.Ran("SET is_active = FALSE"))
+}
@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/database/seeders"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySeederRunner is an in-memory SeederRunner
+type memorySeederRunner struct {
+	ran map[string]bool
+}
+
+func newMemorySeederRunner() *memorySeederRunner {
+	return &memorySeederRunner{ran: make(map[string]bool)}
+}
+
+func (r *memorySeederRunner) CreateSeederTable() error { return nil }
+
+func (r *memorySeederRunner) HasRun(name string) (bool, error) { return r.ran[name], nil }
+
+func (r *memorySeederRunner) MarkRun(name string) error {
+	r.ran[name] = true
+	return nil
+}
+
+func (r *memorySeederRunner) Close() error { return nil }
+
+// newCountingRegistry registers seeders that append a row to inserted each time they run
+func newCountingRegistry(inserted map[string]int) *seeders.Registry {
+	registry := seeders.NewRegistry()
+	for _, name := range []string{"admin", "notification_templates"} {
+		name := name
+		registry.Register(name, func(cfg *config.Config) error {
+			inserted[name]++
+			return nil
+		})
+	}
+	return registry
+}
+
+func TestRegistry_RunAllOnce(t *testing.T) {
+	cfg := testutils.TestConfig()
+
+	t.Run("second run inserts nothing new", func(t *testing.T) {
+		inserted := make(map[string]int)
+		registry := newCountingRegistry(inserted)
+		runner := newMemorySeederRunner()
+
+		require.NoError(t, registry.RunAllOnce(runner, cfg, false))
+		require.NoError(t, registry.RunAllOnce(runner, cfg, false))
+
+		assert.Equal(t, map[string]int{"admin": 1, "notification_templates": 1}, inserted)
+	})
+
+	t.Run("force runs seeders again", func(t *testing.T) {
+		inserted := make(map[string]int)
+		registry := newCountingRegistry(inserted)
+		runner := newMemorySeederRunner()
+
+		require.NoError(t, registry.RunAllOnce(runner, cfg, false))
+		require.NoError(t, registry.RunAllOnce(runner, cfg, true))
+
+		assert.Equal(t, map[string]int{"admin": 2, "notification_templates": 2}, inserted)
+	})
+
+	t.Run("single seeder is skipped once recorded", func(t *testing.T) {
+		inserted := make(map[string]int)
+		registry := newCountingRegistry(inserted)
+		runner := newMemorySeederRunner()
+
+		ran, err := registry.RunOnce(runner, "admin", cfg, false)
+		require.NoError(t, err)
+		assert.True(t, ran)
+
+		ran, err = registry.RunOnce(runner, "admin", cfg, false)
+		require.NoError(t, err)
+		assert.False(t, ran)
+		assert.Equal(t, 1, inserted["admin"])
+	})
+
+	t.Run("unknown seeder", func(t *testing.T) {
+		registry := newCountingRegistry(make(map[string]int))
+
+		_, err := registry.RunOnce(newMemorySeederRunner(), "unknown", cfg, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestSeeders_RegisteredThroughRunner(t *testing.T) {
+	names := seeders.ListSeeders()
+
+	assert.Contains(t, names, "admin")
+	assert.Contains(t, names, "notification_templates")
+}
+
+func TestMySQLSeederRunner_TracksRuns(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	runner := seeders.NewMySQLSeederRunnerWithDB(db)
+	require.NoError(t, runner.CreateSeederTable())
+
+	ran, err := runner.HasRun("admin")
+	require.NoError(t, err)
+	assert.False(t, ran)
+
+	require.NoError(t, runner.MarkRun("admin"))
+	require.NoError(t, runner.MarkRun("admin"), "marking a forced re-run must not fail")
+
+	ran, err = runner.HasRun("admin")
+	require.NoError(t, err)
+	assert.True(t, ran)
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM seeders WHERE name = ?", "admin").Scan(&count))
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/database/seeders"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeederService_ListSeeders(t *testing.T) {
+	safeSeeders := new(testutils.MockSafeSeeders)
+	safeSeeders.On("List").Return([]string{"notification_templates"})
+	service := services.NewSeederService(safeSeeders)
+
+	names := service.ListSeeders(context.Background())
+
+	assert.Equal(t, []string{"notification_templates"}, names)
+}
+
+func TestSeederService_RunSeeder(t *testing.T) {
+	ctx := context.Background()
+	actor := &models.Actor{ID: 1, Type: "admin"}
+
+	t.Run("runs the seeder and returns its report", func(t *testing.T) {
+		safeSeeders := new(testutils.MockSafeSeeders)
+		report := models.NewSeedResult("notification_templates")
+		report.Inserted = []string{"welcome_email"}
+		safeSeeders.On("Run", "notification_templates").Return(report, nil).Once()
+
+		result, err := services.NewSeederService(safeSeeders).RunSeeder(ctx, "notification_templates", actor)
+
+		require.NoError(t, err)
+		assert.Equal(t, report, result)
+		safeSeeders.AssertExpectations(t)
+	})
+
+	t.Run("returns seeder errors", func(t *testing.T) {
+		safeSeeders := new(testutils.MockSafeSeeders)
+		safeSeeders.On("Run", "admin").Return(nil, seeders.ErrSeederNotAllowed).Once()
+
+		result, err := services.NewSeederService(safeSeeders).RunSeeder(ctx, "admin", actor)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, seeders.ErrSeederNotAllowed)
+	})
+
+	t.Run("requires an actor", func(t *testing.T) {
+		safeSeeders := new(testutils.MockSafeSeeders)
+
+		result, err := services.NewSeederService(safeSeeders).RunSeeder(ctx, "notification_templates", nil)
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+		safeSeeders.AssertNotCalled(t, "Run", "notification_templates")
+	})
+}
+
+func TestSafeSeeders(t *testing.T) {
+	t.Run("lists only the safe seeders", func(t *testing.T) {
+		names := seeders.NewSafeSeeders(nil).List()
+
+		assert.Contains(t, names, "notification_templates")
+		assert.NotContains(t, names, "admin")
+		assert.NotContains(t, names, "gamenet")
+	})
+
+	t.Run("rejects seeders outside the safe set", func(t *testing.T) {
+		for _, name := range []string{"admin", "gamenet", "unknown"} {
+			result, err := seeders.NewSafeSeeders(nil).Run(name)
+
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, seeders.ErrSeederNotAllowed)
+		}
+	})
+
+	t.Run("runs on the given connection", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnQuery("SELECT COUNT(*) FROM notification_templates", []string{"count"}, []driver.Value{int64(1)})
+
+		result, err := seeders.NewSafeSeeders(db).Run("notification_templates")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.Skipped)
+		assert.Empty(t, result.Inserted)
+		assert.False(t, fake.Ran("INSERT INTO notification_templates"))
+	})
+}
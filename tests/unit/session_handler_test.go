@@ -0,0 +1,199 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionHandler_GetSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	device := "iPhone 15"
+	ownedSession := &models.UserSession{
+		ID:             7,
+		UserID:         1,
+		UserType:       "user",
+		SessionToken:   "owner.jwt.token",
+		DeviceInfo:     &device,
+		IsActive:       true,
+		LastActivityAt: time.Now(),
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		name           string
+		claims         *utils.JWTClaims
+		sessionID      string
+		token          string
+		mockSetup      func(*testutils.MockSessionRepository)
+		expectedStatus int
+		expectCurrent  bool
+	}{
+		{
+			name:      "owner sees own session",
+			claims:    &utils.JWTClaims{UserID: 1, UserType: "user"},
+			sessionID: "7",
+			token:     "owner.jwt.token",
+			mockSetup: func(repo *testutils.MockSessionRepository) {
+				repo.On("GetSessionByID", 7).Return(ownedSession, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectCurrent:  true,
+		},
+		{
+			name:      "another user's session is not found",
+			claims:    &utils.JWTClaims{UserID: 2, UserType: "user"},
+			sessionID: "7",
+			token:     "other.jwt.token",
+			mockSetup: func(repo *testutils.MockSessionRepository) {
+				repo.On("GetSessionByID", 7).Return(ownedSession, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "same ID but different user type is not the owner",
+			claims:    &utils.JWTClaims{UserID: 1, UserType: "gamenet"},
+			sessionID: "7",
+			token:     "gamenet.jwt.token",
+			mockSetup: func(repo *testutils.MockSessionRepository) {
+				repo.On("GetSessionByID", 7).Return(ownedSession, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "admin sees any session",
+			claims:    &utils.JWTClaims{UserID: 9, UserType: "admin"},
+			sessionID: "7",
+			token:     "admin.jwt.token",
+			mockSetup: func(repo *testutils.MockSessionRepository) {
+				repo.On("GetSessionByID", 7).Return(ownedSession, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "session not found",
+			claims:    &utils.JWTClaims{UserID: 1, UserType: "user"},
+			sessionID: "99",
+			token:     "owner.jwt.token",
+			mockSetup: func(repo *testutils.MockSessionRepository) {
+				repo.On("GetSessionByID", 99).Return(nil, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid session ID",
+			claims:         &utils.JWTClaims{UserID: 1, UserType: "user"},
+			sessionID:      "abc",
+			token:          "owner.jwt.token",
+			mockSetup:      func(repo *testutils.MockSessionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := new(testutils.MockSessionRepository)
+			tt.mockSetup(sessionRepo)
+
+			sessionService := services.NewSessionService(sessionRepo, testutils.TestConfig())
+			handler := handlers.NewSessionHandler(sessionService)
+
+			req := httptest.NewRequest("GET", "/sessions/"+tt.sessionID, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+			c.Params = gin.Params{{Key: "session_id", Value: tt.sessionID}}
+			c.Set("user", tt.claims)
+
+			handler.GetSession(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Session models.SessionResponse `json:"session"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, 7, response.Session.ID)
+				assert.Equal(t, "iPhone 15", *response.Session.DeviceInfo)
+				assert.Equal(t, tt.expectCurrent, response.Session.IsCurrent)
+			}
+			sessionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSessionHandler_GetSession_SeededGamenetCannotReadAdminSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The seeded gamenet role holds users:read, which must not open up other sessions
+	permissionService := testutils.NewSeededPermissionService(t)
+	require.NoError(t, permissionService.CheckUserPermission(3, "gamenet", "users", "read"))
+
+	ip := "10.0.0.9"
+	sessionRepo := new(testutils.MockSessionRepository)
+	sessionRepo.On("GetSessionByID", 12).Return(&models.UserSession{
+		ID:             12,
+		UserID:         1,
+		UserType:       "admin",
+		SessionToken:   "admin.jwt.token",
+		IPAddress:      &ip,
+		IsActive:       true,
+		LastActivityAt: time.Now(),
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}, nil)
+	handler := handlers.NewSessionHandler(services.NewSessionService(sessionRepo, testutils.TestConfig()))
+
+	req := httptest.NewRequest("GET", "/sessions/12", nil)
+	req.Header.Set("Authorization", "Bearer gamenet.jwt.token")
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "session_id", Value: "12"}}
+	c.Set("user", &utils.JWTClaims{UserID: 3, UserType: "gamenet"})
+
+	handler.GetSession(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.NotContains(t, w.Body.String(), ip)
+}
+
+func TestSessionHandler_TerminateAccountSessions_UnknownAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sessionRepo := new(testutils.MockSessionRepository)
+	userRepo := new(MockUserRepository)
+	userRepo.On("GetByID", 404).Return(nil, repositories.ErrNotFound)
+	sessionService := services.NewSessionService(sessionRepo, testutils.TestConfig())
+	sessionService.SetAccountRepositories(userRepo, new(testutils.MockAdminRepository), new(testutils.MockGamenetRepository))
+	handler := handlers.NewSessionHandler(sessionService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/users/404/terminate-sessions", strings.NewReader(`{"reason":"account compromised"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: "404"}}
+	c.Set("user", &utils.JWTClaims{UserID: 1, UserType: "admin"})
+
+	handler.TerminateAccountSessions("user")(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Account not found")
+	sessionRepo.AssertNotCalled(t, "DeactivateAllUserSessions", 404, "user")
+}
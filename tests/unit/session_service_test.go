@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSessionService_TerminateAccountSessions(t *testing.T) {
+	t.Run("Returns Terminated Count", func(t *testing.T) {
+		sessionRepo := new(testutils.MockSessionRepository)
+		sessionService := services.NewSessionService(sessionRepo, testutils.TestConfig())
+
+		sessionRepo.On("DeactivateAllUserSessions", 42, "user").Return(3, nil)
+
+		count, err := sessionService.TerminateAccountSessions(42, "user", 1, "admin", "account compromised")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		sessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		sessionRepo := new(testutils.MockSessionRepository)
+		sessionService := services.NewSessionService(sessionRepo, testutils.TestConfig())
+
+		sessionRepo.On("DeactivateAllUserSessions", 7, "gamenet").Return(0, errors.New("db down"))
+
+		count, err := sessionService.TerminateAccountSessions(7, "gamenet", 1, "admin", "account compromised")
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, count)
+		assert.Contains(t, err.Error(), "failed to terminate sessions")
+	})
+	t.Run("Unknown Account", func(t *testing.T) {
+		sessionRepo := new(testutils.MockSessionRepository)
+		gamenetRepo := new(testutils.MockGamenetRepository)
+		sessionService := services.NewSessionService(sessionRepo, testutils.TestConfig())
+		sessionService.SetAccountRepositories(new(MockUserRepository), new(testutils.MockAdminRepository), gamenetRepo)
+
+		gamenetRepo.On("GetByID", 404).Return(nil, repositories.ErrNotFound)
+
+		count, err := sessionService.TerminateAccountSessions(404, "gamenet", 1, "admin", "account compromised")
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+		assert.Equal(t, 0, count)
+		sessionRepo.AssertNotCalled(t, "DeactivateAllUserSessions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Checks The Account Exists", func(t *testing.T) {
+		sessionRepo := new(testutils.MockSessionRepository)
+		userRepo := new(MockUserRepository)
+		sessionService := services.NewSessionService(sessionRepo, testutils.TestConfig())
+		sessionService.SetAccountRepositories(userRepo, new(testutils.MockAdminRepository), new(testutils.MockGamenetRepository))
+
+		userRepo.On("GetByID", 42).Return(testutils.CreateMockUser(42, "user@example.com", "User"), nil)
+		sessionRepo.On("DeactivateAllUserSessions", 42, "user").Return(2, nil)
+
+		count, err := sessionService.TerminateAccountSessions(42, "user", 1, "admin", "account compromised")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		userRepo.AssertExpectations(t)
+	})
+}
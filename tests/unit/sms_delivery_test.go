@@ -0,0 +1,174 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/kavenegar/kavenegar-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMSService_GetDeliveryStatus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports a delivered message and updates its record", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("MessageStatus", []string{"501"}).
+			Return([]kavenegar.MessageStatus{{MessageId: 501, Status: 10, StatusText: "رسیده به گیرنده"}}, nil)
+
+		sentAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+		logs := new(MockSMSLogRepository)
+		logs.On("GetByProviderMessageID", 501).Return(&models.SMSLog{
+			ID:          12,
+			Recipient:   "989121111111",
+			MessageType: services.SMSMessageUserCredentials,
+			Status:      models.SMSLogStatusSent,
+			CreatedAt:   sentAt,
+		}, nil)
+		logs.On("UpdateDeliveryStatus", 12, models.SMSDeliveryDelivered, mock.AnythingOfType("time.Time")).Return(nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetLogRepository(logs)
+
+		status, err := smsService.GetDeliveryStatus(ctx, 501)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.SMSDeliveryDelivered, status.Delivery)
+		assert.Equal(t, 10, status.ProviderStatus)
+		assert.Equal(t, "989121111111", status.Recipient)
+		assert.Equal(t, services.SMSMessageUserCredentials, status.MessageType)
+		require.NotNil(t, status.SentAt)
+		assert.True(t, status.SentAt.Equal(sentAt))
+		logs.AssertExpectations(t)
+	})
+
+	t.Run("reports a failed message without a record", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("MessageStatus", []string{"502"}).
+			Return([]kavenegar.MessageStatus{{MessageId: 502, Status: 6, StatusText: "ارسال ناموفق"}}, nil)
+
+		logs := new(MockSMSLogRepository)
+		logs.On("GetByProviderMessageID", 502).Return(nil, repositories.ErrNotFound)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetLogRepository(logs)
+
+		status, err := smsService.GetDeliveryStatus(ctx, 502)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.SMSDeliveryFailed, status.Delivery)
+		assert.Empty(t, status.Recipient)
+		assert.Nil(t, status.SentAt)
+		logs.AssertNotCalled(t, "UpdateDeliveryStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports a queued message as pending", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("MessageStatus", []string{"503"}).
+			Return([]kavenegar.MessageStatus{{MessageId: 503, Status: 1}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+
+		status, err := smsService.GetDeliveryStatus(ctx, 503)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.SMSDeliveryPending, status.Delivery)
+	})
+
+	t.Run("rejects an unknown message ID", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("MessageStatus", []string{"504"}).
+			Return([]kavenegar.MessageStatus{{MessageId: 504, Status: 100}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+
+		_, err := smsService.GetDeliveryStatus(ctx, 504)
+
+		assert.ErrorIs(t, err, services.ErrSMSMessageNotFound)
+	})
+}
+
+func TestSMSService_CredentialsRecordMessageID(t *testing.T) {
+	client := new(testutils.MockKavenegarClient)
+	client.On("SendMessage", "", receptorEndingWith("9121111111"), mock.Anything, mock.Anything).
+		Return([]kavenegar.Message{{Status: 1, MessageID: 777}}, nil)
+
+	logs := new(MockSMSLogRepository)
+	logs.On("Create", mock.MatchedBy(func(log *models.SMSLog) bool {
+		return log.MessageType == services.SMSMessageUserCredentials &&
+			log.ProviderMessageID != nil && *log.ProviderMessageID == 777
+	})).Return(nil)
+
+	smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+	smsService.SetLogRepository(logs)
+
+	err := smsService.SendUserCredentials(context.Background(), "09121111111", "user@example.com", "12345678")
+
+	assert.NoError(t, err)
+	logs.AssertExpectations(t)
+}
+
+func TestNotificationHandler_GetSMSDeliveryStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	get := func(smsService services.SMSServiceInterface, id string) *httptest.ResponseRecorder {
+		handler := handlers.NewNotificationHandler(nil, nil, nil, nil)
+		handler.SetSMSService(smsService, 10)
+		router := gin.New()
+		router.GET("/notifications/sms/:id/status", handler.GetSMSDeliveryStatus)
+
+		req := httptest.NewRequest(http.MethodGet, "/notifications/sms/"+id+"/status", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("returns the delivery status", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("MessageStatus", []string{"501"}).
+			Return([]kavenegar.MessageStatus{{MessageId: 501, Status: 10}}, nil)
+
+		w := get(services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client), "501")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data models.SMSDeliveryStatus `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 501, response.Data.MessageID)
+		assert.Equal(t, models.SMSDeliveryDelivered, response.Data.Delivery)
+	})
+
+	t.Run("returns not found for an unknown message", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("MessageStatus", []string{"504"}).Return([]kavenegar.MessageStatus{}, nil)
+
+		w := get(services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client), "504")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("rejects an invalid message ID", func(t *testing.T) {
+		w := get(services.NewNoopSMSService(), "abc")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("reports SMS being disabled", func(t *testing.T) {
+		w := get(services.NewNoopSMSService(), "501")
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
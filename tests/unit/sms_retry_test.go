@@ -0,0 +1,142 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/kavenegar/kavenegar-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRetryBackoff_Delay(t *testing.T) {
+	backoff := utils.RetryBackoff{Base: 100 * time.Millisecond, Max: 300 * time.Millisecond}
+
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		// Capped at Max from the third retry on
+		{3, 150 * time.Millisecond, 300 * time.Millisecond},
+		{10, 150 * time.Millisecond, 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			delay := backoff.Delay(tt.retry)
+			assert.GreaterOrEqual(t, delay, tt.min, "retry %d", tt.retry)
+			assert.LessOrEqual(t, delay, tt.max, "retry %d", tt.retry)
+		}
+	}
+
+	assert.Zero(t, utils.RetryBackoff{}.Delay(1))
+}
+
+func TestSleepContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := utils.SleepContext(ctx, time.Minute)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.NoError(t, utils.SleepContext(context.Background(), time.Millisecond))
+}
+
+func TestSMSService_Retries(t *testing.T) {
+	sms := &models.SMSNotification{To: "09123456789", Message: "Hello"}
+	rejected := []kavenegar.Message{{Status: 6}}
+
+	newService := func(client *testutils.MockKavenegarClient, baseDelayMS int) *services.SMSService {
+		cfg := newTestSMSConfig(services.SMSStrategySMSOnly)
+		cfg.MaxRetries = 3
+		cfg.RetryBaseDelayMS = baseDelayMS
+		cfg.RetryMaxDelayMS = 10 * baseDelayMS
+		return services.NewSMSServiceWithClient(cfg, client)
+	}
+
+	t.Run("tries up to MaxRetries times", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(rejected, nil)
+
+		err := newService(client, 1).SendSMS(context.Background(), sms)
+
+		assert.Error(t, err)
+		client.AssertNumberOfCalls(t, "SendMessage", 3)
+	})
+
+	t.Run("stops retrying once a send succeeds", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(rejected, nil).Once()
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 9}}, nil)
+
+		err := newService(client, 1).SendSMS(context.Background(), sms)
+
+		assert.NoError(t, err)
+		client.AssertNumberOfCalls(t, "SendMessage", 2)
+	})
+
+	t.Run("cancellation aborts the backoff", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(rejected, nil)
+
+		// The first backoff is at least 30 seconds, far past the test's patience
+		start := time.Now()
+		err := newService(client, 60000).SendSMS(ctx, sms)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+		client.AssertNumberOfCalls(t, "SendMessage", 1)
+	})
+
+	t.Run("cancellation aborts the backoff in bulk sends", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(rejected, nil)
+
+		start := time.Now()
+		results, err := newService(client, 60000).SendBulkSMSWithResults(ctx, []*models.SMSNotification{sms, sms})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Len(t, results, 2)
+		client.AssertNumberOfCalls(t, "SendMessage", 1)
+	})
+
+	t.Run("bulk sends time out each message separately", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(rejected, nil).Once()
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]kavenegar.Message{{MessageID: 42, Status: 1}}, nil)
+
+		cfg := newTestSMSConfig(services.SMSStrategySMSOnly)
+		cfg.MaxRetries = 2
+		cfg.RetryBaseDelayMS = 60000
+		cfg.SendTimeoutSeconds = 1
+		results, err := services.NewSMSServiceWithClient(cfg, client).SendBulkSMSWithResults(context.Background(), []*models.SMSNotification{sms, sms})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Contains(t, results[0].Error, context.DeadlineExceeded.Error())
+		assert.Empty(t, results[1].Error)
+		assert.Equal(t, 42, results[1].MessageID)
+	})
+}
@@ -0,0 +1,319 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/kavenegar/kavenegar-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidateTemplateTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		tokens  map[string]string
+		wantErr string
+	}{
+		{
+			name:   "all slots valid",
+			tokens: map[string]string{"token": "123456", "token2": "user@example.com", "token3": "pass", "token10": "Ali Rezaei", "token20": "see you at eight tonight"},
+		},
+		{
+			name:    "missing first token",
+			tokens:  map[string]string{"token2": "value"},
+			wantErr: `template token "token" is required`,
+		},
+		{
+			name:    "unknown slot",
+			tokens:  map[string]string{"token": "123456", "token4": "value"},
+			wantErr: `unknown template token slot "token4"`,
+		},
+		{
+			name:    "space in strict slot",
+			tokens:  map[string]string{"token": "123456", "token2": "two words"},
+			wantErr: `template token "token2" cannot contain spaces`,
+		},
+		{
+			name:    "too many spaces in token10",
+			tokens:  map[string]string{"token": "123456", "token10": "a b c d e f g"},
+			wantErr: `template token "token10" can contain at most 5 spaces`,
+		},
+		{
+			name:    "line break",
+			tokens:  map[string]string{"token": "123456", "token20": "line\nbreak"},
+			wantErr: `template token "token20" cannot contain line breaks or tabs`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := services.ValidateTemplateTokens(tt.tokens)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func newTestSMSConfig(strategy string) *config.SMSConfig {
+	return &config.SMSConfig{
+		Enabled:    true,
+		APIKey:     "test-key",
+		Sender:     "10008663",
+		MaxRetries: 1,
+		Strategy:   strategy,
+		Templates: map[string]string{
+			services.SMSMessageUserCredentials: "custom-user-template",
+		},
+	}
+}
+
+func TestSMSService_SendUserCredentials_Strategies(t *testing.T) {
+	ctx := context.Background()
+	mobile := "09123456789"
+	phone := mock.AnythingOfType("string")
+	templateMissing := &kavenegar.APIError{Status: 424, Message: "template not found"}
+
+	t.Run("verify_then_sms uses template when available", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("VerifyLookup", phone, "custom-user-template", "user@example.com", mock.Anything).
+			Return(kavenegar.Message{Status: 200}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategyVerifyThenSMS), client)
+		err := smsService.SendUserCredentials(ctx, mobile, "user@example.com", "12345678")
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+		client.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("verify_then_sms falls back to plain SMS", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("VerifyLookup", phone, "custom-user-template", "user@example.com", mock.Anything).
+			Return(kavenegar.Message{}, templateMissing)
+		client.On("SendMessage", "", mock.AnythingOfType("[]string"), mock.AnythingOfType("string"), mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 10}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategyVerifyThenSMS), client)
+		err := smsService.SendUserCredentials(ctx, mobile, "user@example.com", "12345678")
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("verify_only does not fall back", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("VerifyLookup", phone, "custom-user-template", "user@example.com", mock.Anything).
+			Return(kavenegar.Message{}, templateMissing)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategyVerifyOnly), client)
+		err := smsService.SendUserCredentials(ctx, mobile, "user@example.com", "12345678")
+
+		assert.ErrorIs(t, err, services.ErrSMSTemplateUnavailable)
+		client.AssertExpectations(t)
+		client.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sms_only skips verify lookup", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "", mock.AnythingOfType("[]string"), mock.AnythingOfType("string"), mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 11}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		err := smsService.SendUserCredentials(ctx, mobile, "user@example.com", "12345678")
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+		client.AssertNotCalled(t, "VerifyLookup", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("gamenet credentials use default template name", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("VerifyLookup", phone, "gamenet-credentials", "gamenet@example.com", mock.Anything).
+			Return(kavenegar.Message{Status: 200}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategyVerifyOnly), client)
+		err := smsService.SendGamenetCredentials(ctx, mobile, "gamenet@example.com", "12345678")
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+}
+
+func TestSMSService_SendSMS(t *testing.T) {
+	ctx := context.Background()
+	sms := &models.SMSNotification{To: "09123456789", Message: "  Hello  "}
+
+	t.Run("sends the trimmed message from the configured sender", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "10008663", receptorEndingWith("9123456789"), "Hello", mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 21}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+
+		assert.NoError(t, smsService.SendSMS(ctx, sms))
+		client.AssertExpectations(t)
+	})
+
+	t.Run("fails when Kavenegar does not queue the message", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return([]kavenegar.Message{{Status: 6}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		err := smsService.SendSMS(ctx, sms)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status: 6")
+	})
+
+	t.Run("rejects an invalid number without calling Kavenegar", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		err := smsService.SendSMS(ctx, &models.SMSNotification{To: "12345", Message: "Hello"})
+
+		assert.Error(t, err)
+		client.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSMSService_CredentialsMessage(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		send     func(smsService *services.SMSService) error
+		expected []string
+	}{
+		{
+			name: "user credentials",
+			send: func(smsService *services.SMSService) error {
+				return smsService.SendUserCredentials(ctx, "09123456789", "user@example.com", "12345678")
+			},
+			expected: []string{"اطلاعات ورود به سیستم", "ایمیل: user@example.com", "رمز عبور: 12345678"},
+		},
+		{
+			name: "gamenet credentials",
+			send: func(smsService *services.SMSService) error {
+				return smsService.SendGamenetCredentials(ctx, "09123456789", "gamenet@example.com", "87654321")
+			},
+			expected: []string{"اطلاعات ورود به سیستم گیم نت", "ایمیل: gamenet@example.com", "رمز عبور: 87654321"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var message string
+			client := new(testutils.MockKavenegarClient)
+			client.On("SendMessage", "", mock.AnythingOfType("[]string"), mock.AnythingOfType("string"), mock.Anything).
+				Run(func(args mock.Arguments) { message = args.String(2) }).
+				Return([]kavenegar.Message{{Status: 1, MessageID: 12}}, nil)
+
+			smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+
+			assert.NoError(t, tt.send(smsService))
+			assert.True(t, utf8.ValidString(message))
+			for _, expected := range tt.expected {
+				assert.Contains(t, message, expected)
+			}
+		})
+	}
+}
+
+func TestSMSService_Disabled(t *testing.T) {
+	ctx := context.Background()
+	sms := &models.SMSNotification{To: "09123456789", Message: "hello"}
+
+	t.Run("disabled service returns ErrSMSDisabled", func(t *testing.T) {
+		cfg := newTestSMSConfig(services.SMSStrategySMSOnly)
+		cfg.Enabled = false
+		smsService := services.NewSMSService(cfg)
+
+		assert.ErrorIs(t, smsService.SendSMS(ctx, sms), services.ErrSMSDisabled)
+		assert.ErrorIs(t, smsService.SendUserCredentials(ctx, sms.To, "user@example.com", "12345678"), services.ErrSMSDisabled)
+		_, err := smsService.SendBulkSMSWithResults(ctx, []*models.SMSNotification{sms})
+		assert.ErrorIs(t, err, services.ErrSMSDisabled)
+	})
+
+	t.Run("enabled without an API key is a configuration error", func(t *testing.T) {
+		cfg := newTestSMSConfig(services.SMSStrategySMSOnly)
+		cfg.APIKey = ""
+		smsService := services.NewSMSService(cfg)
+
+		err := smsService.SendSMS(ctx, sms)
+
+		assert.ErrorIs(t, err, services.ErrSMSNotConfigured)
+		assert.NotErrorIs(t, err, services.ErrSMSDisabled)
+	})
+
+	t.Run("noop service sends nothing", func(t *testing.T) {
+		smsService := services.NewNoopSMSService()
+
+		assert.ErrorIs(t, smsService.SendSMS(ctx, sms), services.ErrSMSDisabled)
+		assert.ErrorIs(t, smsService.SendBulkSMS(ctx, []*models.SMSNotification{sms}), services.ErrSMSDisabled)
+		assert.ErrorIs(t, smsService.SendTemplate(ctx, sms.To, "otp", map[string]string{services.SMSTokenSlot1: "1234"}), services.ErrSMSDisabled)
+		assert.ErrorIs(t, smsService.SendGamenetCredentials(ctx, sms.To, "gamenet@example.com", "12345678"), services.ErrSMSDisabled)
+		assert.True(t, smsService.ValidatePhoneNumber("09123456789"))
+		assert.False(t, smsService.ValidatePhoneNumber("12345"))
+	})
+}
+
+func receptorEndingWith(suffix string) interface{} {
+	return mock.MatchedBy(func(receptor []string) bool {
+		return len(receptor) == 1 && strings.HasSuffix(receptor[0], suffix)
+	})
+}
+
+func TestSMSService_SendBulkSMSWithResults(t *testing.T) {
+	ctx := context.Background()
+
+	client := new(testutils.MockKavenegarClient)
+	client.On("SendMessage", "10008663", receptorEndingWith("9121111111"), "hello", mock.Anything).
+		Return([]kavenegar.Message{{Status: 1, MessageID: 101}}, nil)
+	client.On("SendMessage", "10008663", receptorEndingWith("9123333333"), "hello", mock.Anything).
+		Return(nil, &kavenegar.APIError{Status: 411, Message: "invalid receptor"})
+	client.On("SendMessage", "10008663", receptorEndingWith("9124444444"), "hello", mock.Anything).
+		Return([]kavenegar.Message{{Status: 1, MessageID: 104}}, nil)
+
+	smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+	messages := []*models.SMSNotification{
+		{To: "09121111111", Message: "hello"},
+		{To: "12345", Message: "hello"},
+		{To: "09123333333", Message: "hello"},
+		{To: "09124444444", Message: "hello"},
+	}
+
+	t.Run("continues past failures and reports each message", func(t *testing.T) {
+		results, err := smsService.SendBulkSMSWithResults(ctx, messages)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 4)
+
+		assert.Equal(t, "09121111111", results[0].To)
+		assert.Equal(t, 101, results[0].MessageID)
+		assert.Empty(t, results[0].Error)
+
+		assert.Contains(t, results[1].Error, "invalid phone number")
+		assert.NotEmpty(t, results[2].Error)
+
+		assert.Equal(t, 104, results[3].MessageID)
+		assert.Empty(t, results[3].Error)
+	})
+
+	t.Run("SendBulkSMS summarises failures", func(t *testing.T) {
+		err := smsService.SendBulkSMS(ctx, messages)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to send 2 of 4 SMS messages")
+	})
+}
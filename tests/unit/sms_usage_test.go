@@ -0,0 +1,316 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/kavenegar/kavenegar-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSMSLogRepository is a mock implementation of SMSLogRepositoryInterface
+type MockSMSLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockSMSLogRepository) Create(log *models.SMSLog) error {
+	args := m.Called(log)
+	return args.Error(0)
+}
+
+func (m *MockSMSLogRepository) GetByProviderMessageID(messageID int) (*models.SMSLog, error) {
+	args := m.Called(messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SMSLog), args.Error(1)
+}
+
+func (m *MockSMSLogRepository) UpdateDeliveryStatus(id int, status models.SMSDeliveryState, checkedAt time.Time) error {
+	args := m.Called(id, status, checkedAt)
+	return args.Error(0)
+}
+
+func (m *MockSMSLogRepository) CountByType(since time.Time, messageType string) ([]models.SMSUsageCount, error) {
+	args := m.Called(since, messageType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SMSUsageCount), args.Error(1)
+}
+
+func (m *MockSMSLogRepository) ListDailyUsage(filter *models.SMSUsageFilter) ([]models.SMSDailyUsage, int64, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]models.SMSDailyUsage), args.Get(1).(int64), args.Error(2)
+}
+
+func TestSMSService_RecordsSends(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successful send is recorded with the provider message ID", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "10008663", receptorEndingWith("9121111111"), "hello", mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 501}}, nil)
+
+		logs := new(MockSMSLogRepository)
+		logs.On("Create", mock.MatchedBy(func(log *models.SMSLog) bool {
+			return log.MessageType == services.SMSMessageNotification &&
+				log.Status == models.SMSLogStatusSent &&
+				log.ProviderMessageID != nil && *log.ProviderMessageID == 501
+		})).Return(nil).Once()
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetLogRepository(logs)
+		err := smsService.SendSMS(ctx, &models.SMSNotification{To: "09121111111", Message: "hello"})
+
+		assert.NoError(t, err)
+		logs.AssertExpectations(t)
+	})
+
+	t.Run("failed send is recorded with its type and error", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "10008663", receptorEndingWith("9122222222"), "your code", mock.Anything).
+			Return([]kavenegar.Message{{Status: 6}}, nil)
+
+		logs := new(MockSMSLogRepository)
+		logs.On("Create", mock.MatchedBy(func(log *models.SMSLog) bool {
+			return log.MessageType == services.SMSMessageOTP &&
+				log.Status == models.SMSLogStatusFailed &&
+				log.ProviderMessageID == nil && log.ErrorMsg != nil
+		})).Return(nil).Once()
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetLogRepository(logs)
+		err := smsService.SendSMS(ctx, &models.SMSNotification{
+			To:      "09122222222",
+			Message: "your code",
+			Type:    services.SMSMessageOTP,
+		})
+
+		assert.Error(t, err)
+		logs.AssertExpectations(t)
+	})
+
+	t.Run("credentials are recorded with their message type", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "", mock.AnythingOfType("[]string"), mock.AnythingOfType("string"), mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 7}}, nil)
+
+		logs := new(MockSMSLogRepository)
+		logs.On("Create", mock.MatchedBy(func(log *models.SMSLog) bool {
+			return log.MessageType == services.SMSMessageGamenetCredentials && log.Status == models.SMSLogStatusSent
+		})).Return(nil).Once()
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetLogRepository(logs)
+		err := smsService.SendGamenetCredentials(ctx, "09123333333", "gamenet@example.com", "secret123")
+
+		assert.NoError(t, err)
+		logs.AssertExpectations(t)
+	})
+
+	t.Run("recording failures do not fail the send", func(t *testing.T) {
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "10008663", receptorEndingWith("9124444444"), "hello", mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 9}}, nil)
+
+		logs := new(MockSMSLogRepository)
+		logs.On("Create", mock.Anything).Return(errors.New("database unavailable")).Once()
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetLogRepository(logs)
+		err := smsService.SendSMS(ctx, &models.SMSNotification{To: "09124444444", Message: "hello"})
+
+		assert.NoError(t, err)
+		logs.AssertExpectations(t)
+	})
+}
+
+func TestSMSUsageService_GetUsageStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("windows list every known type", func(t *testing.T) {
+		repo := new(MockSMSLogRepository)
+		repo.On("CountByType", mock.AnythingOfType("time.Time"), "").Return([]models.SMSUsageCount{
+			{MessageType: services.SMSMessageNotification, Sent: 5, Failed: 1},
+			{MessageType: services.SMSMessageUserCredentials, Sent: 2},
+			{MessageType: "marketing", Sent: 3},
+		}, nil).Times(3)
+		repo.On("ListDailyUsage", mock.AnythingOfType("*models.SMSUsageFilter")).Return([]models.SMSDailyUsage{
+			{Date: "2025-03-02", MessageType: services.SMSMessageNotification, Sent: 5, Failed: 1},
+			{Date: "2025-03-01", MessageType: services.SMSMessageUserCredentials, Sent: 2},
+		}, int64(12), nil).Once()
+
+		service := services.NewSMSUsageService(repo)
+		stats, err := service.GetUsageStats(ctx, &models.SMSUsageFilter{Page: 2, PageSize: 5})
+
+		require.NoError(t, err)
+		assert.Equal(t, 10, stats.Today.Sent)
+		assert.Equal(t, 1, stats.Today.Failed)
+		require.Len(t, stats.Today.ByType, 5)
+		assert.Equal(t, models.SMSUsageCount{MessageType: services.SMSMessageUserCredentials, Sent: 2}, stats.Today.ByType[0])
+		assert.Equal(t, models.SMSUsageCount{MessageType: services.SMSMessageOTP}, stats.Today.ByType[2])
+		assert.Equal(t, "marketing", stats.Today.ByType[4].MessageType)
+		assert.True(t, stats.Last7Days.Since.Before(stats.Today.Since))
+		assert.True(t, stats.Last30Days.Since.Before(stats.Last7Days.Since))
+
+		assert.Len(t, stats.Daily, 2)
+		assert.Equal(t, int64(12), stats.Pagination.TotalItems)
+		assert.Equal(t, 3, stats.Pagination.TotalPages)
+		assert.True(t, stats.Pagination.HasNext)
+		assert.True(t, stats.Pagination.HasPrev)
+		assert.Equal(t, 0, stats.From.Hour())
+		assert.Equal(t, stats.Last30Days.Since, stats.From.Time)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("type filter narrows the breakdown", func(t *testing.T) {
+		repo := new(MockSMSLogRepository)
+		repo.On("CountByType", mock.AnythingOfType("time.Time"), services.SMSMessageOTP).
+			Return([]models.SMSUsageCount{}, nil).Times(3)
+		repo.On("ListDailyUsage", mock.MatchedBy(func(filter *models.SMSUsageFilter) bool {
+			return filter.MessageType == services.SMSMessageOTP && filter.Page == 1 && filter.PageSize == 10
+		})).Return([]models.SMSDailyUsage{}, int64(0), nil).Once()
+
+		service := services.NewSMSUsageService(repo)
+		stats, err := service.GetUsageStats(ctx, &models.SMSUsageFilter{MessageType: services.SMSMessageOTP})
+
+		require.NoError(t, err)
+		assert.Equal(t, []models.SMSUsageCount{{MessageType: services.SMSMessageOTP}}, stats.Last30Days.ByType)
+		assert.Equal(t, 0, stats.Pagination.TotalPages)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := new(MockSMSLogRepository)
+		repo.On("CountByType", mock.AnythingOfType("time.Time"), "").Return(nil, errors.New("database error")).Once()
+
+		service := services.NewSMSUsageService(repo)
+		stats, err := service.GetUsageStats(ctx, &models.SMSUsageFilter{})
+
+		assert.Error(t, err)
+		assert.Nil(t, stats)
+	})
+}
+
+func TestSMSUsageHandler_GetSMSStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	get := func(repo *MockSMSLogRepository, query string) *httptest.ResponseRecorder {
+		handler := handlers.NewSMSUsageHandler(services.NewSMSUsageService(repo))
+		router := gin.New()
+		router.GET("/integrations/sms/stats", handler.GetSMSStats)
+
+		req := httptest.NewRequest(http.MethodGet, "/integrations/sms/stats"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("date range filter", func(t *testing.T) {
+		repo := new(MockSMSLogRepository)
+		repo.On("CountByType", mock.AnythingOfType("time.Time"), "").Return([]models.SMSUsageCount{}, nil)
+		repo.On("ListDailyUsage", mock.MatchedBy(func(filter *models.SMSUsageFilter) bool {
+			return filter.From.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) &&
+				filter.To.Equal(time.Date(2025, 3, 31, 23, 59, 59, 999999999, time.UTC)) &&
+				filter.PageSize == 20
+		})).Return([]models.SMSDailyUsage{}, int64(0), nil).Once()
+
+		w := get(repo, "?from=2025-03-01&to=2025-03-31&page_size=20")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data models.SMSUsageStatsResponse `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Data.Today.ByType, 4)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		w := get(new(MockSMSLogRepository), "?from=yesterday")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("from after to", func(t *testing.T) {
+		w := get(new(MockSMSLogRepository), "?from=2025-04-01&to=2025-03-01")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestSMSLogRepository_Usage(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	repo := repositories.NewSMSLogRepository(db)
+
+	// Seed sends spread over the last few days
+	now := time.Now().Truncate(time.Second)
+	seed := []struct {
+		messageType string
+		status      models.SMSLogStatus
+		daysAgo     int
+	}{
+		{services.SMSMessageNotification, models.SMSLogStatusSent, 0},
+		{services.SMSMessageNotification, models.SMSLogStatusFailed, 0},
+		{services.SMSMessageOTP, models.SMSLogStatusSent, 0},
+		{services.SMSMessageOTP, models.SMSLogStatusSent, 3},
+		{services.SMSMessageUserCredentials, models.SMSLogStatusSent, 3},
+		{services.SMSMessageNotification, models.SMSLogStatusSent, 20},
+		{services.SMSMessageNotification, models.SMSLogStatusSent, 45},
+	}
+	for _, s := range seed {
+		require.NoError(t, repo.Create(&models.SMSLog{
+			Recipient:   "989121234567",
+			MessageType: s.messageType,
+			Status:      s.status,
+			CreatedAt:   now.AddDate(0, 0, -s.daysAgo),
+		}))
+	}
+
+	counts, err := repo.CountByType(now.AddDate(0, 0, -7), "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.SMSUsageCount{
+		{MessageType: services.SMSMessageNotification, Sent: 1, Failed: 1},
+		{MessageType: services.SMSMessageOTP, Sent: 2},
+		{MessageType: services.SMSMessageUserCredentials, Sent: 1},
+	}, counts)
+
+	counts, err = repo.CountByType(now.AddDate(0, 0, -30), services.SMSMessageNotification)
+	require.NoError(t, err)
+	assert.Equal(t, []models.SMSUsageCount{{MessageType: services.SMSMessageNotification, Sent: 2, Failed: 1}}, counts)
+
+	from := now.AddDate(0, 0, -30)
+	filter := &models.SMSUsageFilter{From: &from, To: &now, Page: 1, PageSize: 2}
+	daily, total, err := repo.ListDailyUsage(filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	require.Len(t, daily, 2)
+	assert.Equal(t, now.Format("2006-01-02"), daily[0].Date)
+
+	filter.Page = 3
+	daily, _, err = repo.ListDailyUsage(filter)
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, services.SMSMessageNotification, daily[0].MessageType)
+	assert.Equal(t, 1, daily[0].Sent)
+}
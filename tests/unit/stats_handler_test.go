@@ -0,0 +1,139 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockStatsService is a mock implementation of StatsServiceInterface
+type MockStatsService struct {
+	mock.Mock
+}
+
+func (m *MockStatsService) GetAdminOverview(ctx context.Context) (*models.AdminOverviewResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AdminOverviewResponse), args.Error(1)
+}
+
+func (m *MockStatsService) GetGamenetStats(ctx context.Context, gamenetID int) (*models.GamenetStatsResponse, error) {
+	args := m.Called(ctx, gamenetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GamenetStatsResponse), args.Error(1)
+}
+
+func TestStatsHandler_GetGamenetStats_TenantIsolation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	linked := 12
+
+	tests := []struct {
+		name           string
+		userType       string
+		userID         int
+		gamenetID      string
+		setupMock      func(*MockStatsService)
+		expectedStatus int
+	}{
+		{
+			name:      "gamenet reads its own stats",
+			userType:  "gamenet",
+			userID:    7,
+			gamenetID: "7",
+			setupMock: func(m *MockStatsService) {
+				m.On("GetGamenetStats", mock.Anything, 7).Return(&models.GamenetStatsResponse{GamenetID: 7, LinkedUsers: &linked}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "gamenet cannot read another gamenet's stats",
+			userType:       "gamenet",
+			userID:         7,
+			gamenetID:      "8",
+			setupMock:      func(m *MockStatsService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:      "admin reads any gamenet's stats",
+			userType:  "admin",
+			userID:    1,
+			gamenetID: "8",
+			setupMock: func(m *MockStatsService) {
+				m.On("GetGamenetStats", mock.Anything, 8).Return(&models.GamenetStatsResponse{GamenetID: 8, LinkedUsers: &linked}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "admin gets not found for unknown gamenet",
+			userType:  "admin",
+			userID:    1,
+			gamenetID: "99",
+			setupMock: func(m *MockStatsService) {
+				m.On("GetGamenetStats", mock.Anything, 99).Return(nil, fmt.Errorf("gamenet 99: %w", repositories.ErrNotFound))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "regular user is refused",
+			userType:       "user",
+			userID:         7,
+			gamenetID:      "7",
+			setupMock:      func(m *MockStatsService) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "invalid gamenet id",
+			userType:       "admin",
+			userID:         1,
+			gamenetID:      "abc",
+			setupMock:      func(m *MockStatsService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockStatsService)
+			tt.setupMock(mockService)
+			handler := handlers.NewStatsHandler(mockService)
+
+			router := gin.New()
+			router.GET("/gamenets/:id/stats", func(c *gin.Context) {
+				c.Set("user_type", tt.userType)
+				c.Set("user_id", tt.userID)
+				c.Next()
+			}, handler.GetGamenetStats)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/gamenets/"+tt.gamenetID+"/stats", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response struct {
+					Data models.GamenetStatsResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.gamenetID, fmt.Sprint(response.Data.GamenetID))
+			} else {
+				mockService.AssertNotCalled(t, "GetGamenetStats", mock.Anything, 8)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
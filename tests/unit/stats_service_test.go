@@ -0,0 +1,200 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockStatsRepository is a mock implementation of StatsRepositoryInterface
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepository) CountUsers() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountGamenets() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountActiveSessions() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountSubscriptionPlans() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountSMSSentSince(since time.Time) (int, error) {
+	args := m.Called(since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountUsersCreatedSince(since time.Time) (int, error) {
+	args := m.Called(since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) GamenetExists(gamenetID int) (bool, error) {
+	args := m.Called(gamenetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountGamenetUsers(gamenetID int) (int, error) {
+	args := m.Called(gamenetID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountGamenetUsersLinkedSince(gamenetID int, since time.Time) (int, error) {
+	args := m.Called(gamenetID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStatsRepository) CountGamenetUsersActiveSince(gamenetID int, since time.Time) (int, error) {
+	args := m.Called(gamenetID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func TestStatsService_GetAdminOverview(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("All Metrics", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		mockRepo.On("CountUsers").Return(120, nil).Once()
+		mockRepo.On("CountGamenets").Return(8, nil).Once()
+		mockRepo.On("CountActiveSessions").Return(15, nil).Once()
+		mockRepo.On("CountSubscriptionPlans").Return(3, nil).Once()
+		mockRepo.On("CountSMSSentSince", mock.AnythingOfType("time.Time")).Return(42, nil).Once()
+		mockRepo.On("CountUsersCreatedSince", mock.AnythingOfType("time.Time")).Return(9, nil).Once()
+
+		statsService := services.NewStatsService(mockRepo)
+		overview, err := statsService.GetAdminOverview(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 120, *overview.TotalUsers)
+		assert.Equal(t, 8, *overview.TotalGamenets)
+		assert.Equal(t, 15, *overview.ActiveSessions)
+		assert.Equal(t, 3, *overview.SubscriptionPlans)
+		assert.Equal(t, 42, *overview.SMSSentToday)
+		assert.Equal(t, 9, *overview.RecentSignups)
+
+		// Second call within the cache window must not hit the repository again
+		cached, err := statsService.GetAdminOverview(ctx)
+		assert.NoError(t, err)
+		assert.Same(t, overview, cached)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failing Metric Does Not Fail Overview", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		mockRepo.On("CountUsers").Return(120, nil)
+		mockRepo.On("CountGamenets").Return(0, errors.New("table missing"))
+		mockRepo.On("CountActiveSessions").Return(15, nil)
+		mockRepo.On("CountSubscriptionPlans").Return(3, nil)
+		mockRepo.On("CountSMSSentSince", mock.AnythingOfType("time.Time")).Return(0, errors.New("timeout"))
+		mockRepo.On("CountUsersCreatedSince", mock.AnythingOfType("time.Time")).Return(9, nil)
+
+		statsService := services.NewStatsService(mockRepo)
+		overview, err := statsService.GetAdminOverview(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 120, *overview.TotalUsers)
+		assert.Nil(t, overview.TotalGamenets)
+		assert.Nil(t, overview.SMSSentToday)
+		assert.Equal(t, 9, *overview.RecentSignups)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Slow Recompute Does Not Block Other Callers", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		mockRepo := new(MockStatsRepository)
+		mockRepo.On("CountUsers").Run(func(mock.Arguments) {
+			close(started)
+			<-release
+		}).Return(120, nil).Once()
+		mockRepo.On("CountUsers").Return(121, nil).Once()
+		mockRepo.On("CountGamenets").Return(8, nil)
+		mockRepo.On("CountActiveSessions").Return(15, nil)
+		mockRepo.On("CountSubscriptionPlans").Return(3, nil)
+		mockRepo.On("CountSMSSentSince", mock.AnythingOfType("time.Time")).Return(42, nil)
+		mockRepo.On("CountUsersCreatedSince", mock.AnythingOfType("time.Time")).Return(9, nil)
+
+		statsService := services.NewStatsService(mockRepo)
+		slow := make(chan struct{})
+		go func() {
+			defer close(slow)
+			statsService.GetAdminOverview(ctx)
+		}()
+		<-started
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			overview, err := statsService.GetAdminOverview(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 121, *overview.TotalUsers)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Error("GetAdminOverview() blocked behind a slow recompute")
+		}
+		close(release)
+		<-slow
+		<-done
+	})
+}
+
+func TestStatsService_GetGamenetStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Counts Only The Gamenet's Users", func(t *testing.T) {
+		now := time.Now()
+		mockRepo := new(MockStatsRepository)
+		mockRepo.On("GamenetExists", 7).Return(true, nil)
+		mockRepo.On("CountGamenetUsers", 7).Return(40, nil)
+		mockRepo.On("CountGamenetUsersLinkedSince", 7, mock.MatchedBy(func(since time.Time) bool {
+			return since.Before(now.AddDate(0, 0, -29))
+		})).Return(11, nil)
+		mockRepo.On("CountGamenetUsersLinkedSince", 7, mock.MatchedBy(func(since time.Time) bool {
+			return since.After(now.AddDate(0, 0, -8))
+		})).Return(4, nil)
+		mockRepo.On("CountGamenetUsersActiveSince", 7, mock.AnythingOfType("time.Time")).Return(0, errors.New("timeout"))
+
+		stats, err := services.NewStatsService(mockRepo).GetGamenetStats(ctx, 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 7, stats.GamenetID)
+		assert.Equal(t, 40, *stats.LinkedUsers)
+		assert.Equal(t, 4, *stats.NewUsersLast7Days)
+		assert.Equal(t, 11, *stats.NewUsersLast30Days)
+		assert.Nil(t, stats.ActiveUsers)
+		assert.Equal(t, 30, stats.ActiveUserDays)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Gamenet", func(t *testing.T) {
+		mockRepo := new(MockStatsRepository)
+		mockRepo.On("GamenetExists", 99).Return(false, nil)
+
+		stats, err := services.NewStatsService(mockRepo).GetGamenetStats(ctx, 99)
+
+		assert.Nil(t, stats)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+		mockRepo.AssertNotCalled(t, "CountGamenetUsers", mock.Anything)
+	})
+}
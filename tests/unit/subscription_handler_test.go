@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSubscriptionService is a mock implementation of SubscriptionServiceInterface
+type MockSubscriptionService struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionService) Subscribe(gamenetID, planID int) (*models.SubscriptionResponse, error) {
+	args := m.Called(gamenetID, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SubscriptionResponse), args.Error(1)
+}
+
+func (m *MockSubscriptionService) Cancel(subscriptionID int) error {
+	return m.Called(subscriptionID).Error(0)
+}
+
+func (m *MockSubscriptionService) GetActiveSubscription(gamenetID int) (*models.SubscriptionResponse, error) {
+	args := m.Called(gamenetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SubscriptionResponse), args.Error(1)
+}
+
+func newSubscriptionRouter(service *MockSubscriptionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewSubscriptionHandler(service)
+
+	router := gin.New()
+	router.GET("/gamenets/:id/subscription", handler.GetActiveSubscription)
+	router.POST("/gamenets/:id/subscription", handler.Subscribe)
+	router.POST("/gamenets/:id/subscription/cancel", handler.CancelSubscription)
+	return router
+}
+
+func TestSubscriptionHandler_Subscribe(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		err            error
+		expectedStatus int
+	}{
+		{"subscribes the gamenet", `{"plan_id": 2}`, nil, http.StatusCreated},
+		{"requires a plan", `{}`, nil, http.StatusBadRequest},
+		{"refuses an overlapping subscription", `{"plan_id": 2}`, services.ErrActiveSubscriptionExists, http.StatusConflict},
+		{"refuses an inactive plan", `{"plan_id": 2}`, services.ErrPlanNotAvailable, http.StatusUnprocessableEntity},
+		{"reports a missing plan", `{"plan_id": 2}`, repositories.ErrNotFound, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockSubscriptionService)
+			if tt.err != nil {
+				service.On("Subscribe", 5, 2).Return(nil, tt.err)
+			} else {
+				service.On("Subscribe", 5, 2).Return(&models.SubscriptionResponse{ID: 10, GamenetID: 5, PlanID: 2}, nil)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/gamenets/5/subscription", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newSubscriptionRouter(service).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestSubscriptionHandler_CancelSubscription(t *testing.T) {
+	t.Run("cancels the current subscription", func(t *testing.T) {
+		service := new(MockSubscriptionService)
+		service.On("GetActiveSubscription", 5).Return(&models.SubscriptionResponse{ID: 10, GamenetID: 5}, nil)
+		service.On("Cancel", 10).Return(nil)
+
+		w := httptest.NewRecorder()
+		newSubscriptionRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/gamenets/5/subscription/cancel", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("reports a gamenet without a subscription", func(t *testing.T) {
+		service := new(MockSubscriptionService)
+		service.On("GetActiveSubscription", 5).Return(nil, repositories.ErrNotFound)
+
+		w := httptest.NewRecorder()
+		newSubscriptionRouter(service).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/gamenets/5/subscription/cancel", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		service.AssertNotCalled(t, "Cancel", mock.Anything)
+	})
+}
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gatehide/gatehide-api/internal/handlers"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/tests/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -45,8 +47,16 @@ func (m *MockSubscriptionPlanService) GetAllPlans(limit, offset int, isActive *b
 	return args.Get(0).([]*models.PlanResponse), args.Int(1), args.Error(2)
 }
 
-func (m *MockSubscriptionPlanService) UpdatePlan(id int, req *models.UpdatePlanRequest) (*models.PlanResponse, error) {
-	args := m.Called(id, req)
+func (m *MockSubscriptionPlanService) UpdatePlan(id int, req *models.UpdatePlanRequest, actor *models.Actor) (*models.PlanResponse, error) {
+	args := m.Called(id, req, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PlanResponse), args.Error(1)
+}
+
+func (m *MockSubscriptionPlanService) SetActive(id int, active bool, actor *models.Actor) (*models.PlanResponse, error) {
+	args := m.Called(id, active, actor)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -58,6 +68,30 @@ func (m *MockSubscriptionPlanService) DeletePlan(id int) error {
 	return args.Error(0)
 }
 
+func (m *MockSubscriptionPlanService) Clone(id int) (*models.PlanResponse, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PlanResponse), args.Error(1)
+}
+
+func (m *MockSubscriptionPlanService) GetDeleteImpact(id int) (*models.PlanDeleteImpact, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PlanDeleteImpact), args.Error(1)
+}
+
+func (m *MockSubscriptionPlanService) GetPlanHistory(id int) ([]*models.PlanHistory, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PlanHistory), args.Error(1)
+}
+
 func TestSubscriptionPlanHandler_CreatePlan(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -117,6 +151,21 @@ func TestSubscriptionPlanHandler_CreatePlan(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "Failed to create plan",
 		},
+		{
+			name: "duplicate plan name",
+			requestBody: models.CreatePlanRequest{
+				Name:     "Basic Monthly",
+				PlanType: "monthly",
+				Price:    29.99,
+				IsActive: true,
+			},
+			mockSetup: func(mockService *MockSubscriptionPlanService) {
+				mockService.On("CreatePlan", mock.AnythingOfType("*models.CreatePlanRequest")).
+					Return(nil, fmt.Errorf("failed to create plan: subscription plan %q %w", "Basic Monthly", repositories.ErrDuplicate))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  "A plan with this name already exists",
+		},
 	}
 
 	for _, tt := range tests {
@@ -197,7 +246,7 @@ func TestSubscriptionPlanHandler_GetPlan(t *testing.T) {
 			name:   "plan not found",
 			planID: "999",
 			mockSetup: func(mockService *MockSubscriptionPlanService) {
-				mockService.On("GetPlan", 999).Return(nil, errors.New("subscription plan not found"))
+				mockService.On("GetPlan", 999).Return(nil, repositories.ErrNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedError:  "Plan not found",
@@ -208,8 +257,8 @@ func TestSubscriptionPlanHandler_GetPlan(t *testing.T) {
 			mockSetup: func(mockService *MockSubscriptionPlanService) {
 				mockService.On("GetPlan", 1).Return(nil, errors.New("database error"))
 			},
-			expectedStatus: http.StatusNotFound,
-			expectedError:  "Plan not found",
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "Failed to get plan",
 		},
 	}
 
@@ -403,7 +452,7 @@ func TestSubscriptionPlanHandler_UpdatePlan(t *testing.T) {
 			},
 			mockSetup: func(mockService *MockSubscriptionPlanService) {
 				response := utils.CreateMockPlanResponse(1, "Updated Basic Monthly", "monthly", 39.99)
-				mockService.On("UpdatePlan", 1, mock.AnythingOfType("*models.UpdatePlanRequest")).Return(response, nil)
+				mockService.On("UpdatePlan", 1, mock.AnythingOfType("*models.UpdatePlanRequest"), mock.Anything).Return(response, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -432,7 +481,7 @@ func TestSubscriptionPlanHandler_UpdatePlan(t *testing.T) {
 				Name: func() *string { v := "Updated Plan"; return &v }(),
 			},
 			mockSetup: func(mockService *MockSubscriptionPlanService) {
-				mockService.On("UpdatePlan", 999, mock.AnythingOfType("*models.UpdatePlanRequest")).Return(nil, errors.New("subscription plan not found"))
+				mockService.On("UpdatePlan", 999, mock.AnythingOfType("*models.UpdatePlanRequest"), mock.Anything).Return(nil, repositories.ErrNotFound)
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "Failed to update plan",
@@ -444,11 +493,24 @@ func TestSubscriptionPlanHandler_UpdatePlan(t *testing.T) {
 				Name: func() *string { v := "Updated Plan"; return &v }(),
 			},
 			mockSetup: func(mockService *MockSubscriptionPlanService) {
-				mockService.On("UpdatePlan", 1, mock.AnythingOfType("*models.UpdatePlanRequest")).Return(nil, errors.New("validation error"))
+				mockService.On("UpdatePlan", 1, mock.AnythingOfType("*models.UpdatePlanRequest"), mock.Anything).Return(nil, errors.New("validation error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "Failed to update plan",
 		},
+		{
+			name:   "duplicate plan name",
+			planID: "1",
+			requestBody: models.UpdatePlanRequest{
+				Name: func() *string { v := "Pro Annual"; return &v }(),
+			},
+			mockSetup: func(mockService *MockSubscriptionPlanService) {
+				mockService.On("UpdatePlan", 1, mock.AnythingOfType("*models.UpdatePlanRequest"), mock.Anything).
+					Return(nil, fmt.Errorf("failed to update plan: subscription plan %q %w", "Pro Annual", repositories.ErrDuplicate))
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  "A plan with this name already exists",
+		},
 	}
 
 	for _, tt := range tests {
@@ -538,7 +600,7 @@ func TestSubscriptionPlanHandler_DeletePlan(t *testing.T) {
 			name:   "plan not found",
 			planID: "999",
 			mockSetup: func(mockService *MockSubscriptionPlanService) {
-				mockService.On("DeletePlan", 999).Return(errors.New("subscription plan not found"))
+				mockService.On("DeletePlan", 999).Return(repositories.ErrNotFound)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedError:  "Plan not found",
@@ -594,3 +656,247 @@ func TestSubscriptionPlanHandler_DeletePlan(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriptionPlanHandler_GetDeleteImpact(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		planID         string
+		mockSetup      func(*MockSubscriptionPlanService)
+		expectedStatus int
+		expectedError  string
+		expectedImpact *models.PlanDeleteImpact
+	}{
+		{
+			name:   "plan without active subscriptions",
+			planID: "1",
+			mockSetup: func(mockService *MockSubscriptionPlanService) {
+				mockService.On("GetDeleteImpact", 1).Return(&models.PlanDeleteImpact{
+					PlanID: 1, PlanName: "Basic Monthly", CanDelete: true,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedImpact: &models.PlanDeleteImpact{PlanID: 1, PlanName: "Basic Monthly", CanDelete: true},
+		},
+		{
+			name:   "plan with active subscriptions",
+			planID: "2",
+			mockSetup: func(mockService *MockSubscriptionPlanService) {
+				mockService.On("GetDeleteImpact", 2).Return(&models.PlanDeleteImpact{
+					PlanID: 2, PlanName: "Pro Monthly", ActiveSubscriptions: 3, Reason: "plan has active subscriptions",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedImpact: &models.PlanDeleteImpact{
+				PlanID: 2, PlanName: "Pro Monthly", ActiveSubscriptions: 3, Reason: "plan has active subscriptions",
+			},
+		},
+		{
+			name:           "invalid plan ID",
+			planID:         "invalid",
+			mockSetup:      func(mockService *MockSubscriptionPlanService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid plan ID",
+		},
+		{
+			name:   "plan not found",
+			planID: "999",
+			mockSetup: func(mockService *MockSubscriptionPlanService) {
+				mockService.On("GetDeleteImpact", 999).Return(nil, repositories.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Plan not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSubscriptionPlanService)
+			tt.mockSetup(mockService)
+			handler := handlers.NewSubscriptionPlanHandler(mockService)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/subscription-plans/"+tt.planID+"/delete-impact", nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.planID}}
+
+			handler.GetDeleteImpact(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Contains(t, response["error"], tt.expectedError)
+			} else {
+				var response struct {
+					Data models.PlanDeleteImpact `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, *tt.expectedImpact, response.Data)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscriptionPlanHandler_SetPlanActive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	activePlan := utils.CreateMockPlanResponse(1, "Basic Monthly", "monthly", 29.99)
+	inactivePlan := utils.CreateMockPlanResponse(1, "Basic Monthly", "monthly", 29.99)
+	inactivePlan.IsActive = false
+
+	tests := []struct {
+		name            string
+		planID          string
+		activate        bool
+		mockSetup       func(*MockSubscriptionPlanService)
+		expectedStatus  int
+		expectedMessage string
+		expectedError   string
+		expectedActive  bool
+	}{
+		{
+			name:     "activates a plan",
+			planID:   "1",
+			activate: true,
+			mockSetup: func(mockService *MockSubscriptionPlanService) {
+				mockService.On("SetActive", 1, true, mock.Anything).Return(activePlan, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Plan activated successfully",
+			expectedActive:  true,
+		},
+		{
+			name:     "deactivates a plan",
+			planID:   "1",
+			activate: false,
+			mockSetup: func(mockService *MockSubscriptionPlanService) {
+				mockService.On("SetActive", 1, false, mock.Anything).Return(inactivePlan, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Plan deactivated successfully",
+			expectedActive:  false,
+		},
+		{
+			name:     "plan not found",
+			planID:   "999",
+			activate: true,
+			mockSetup: func(mockService *MockSubscriptionPlanService) {
+				mockService.On("SetActive", 999, true, mock.Anything).Return(nil, repositories.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Plan not found",
+		},
+		{
+			name:           "invalid plan ID",
+			planID:         "invalid",
+			activate:       false,
+			mockSetup:      func(mockService *MockSubscriptionPlanService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid plan ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSubscriptionPlanService)
+			tt.mockSetup(mockService)
+			handler := handlers.NewSubscriptionPlanHandler(mockService)
+
+			action := "deactivate"
+			if tt.activate {
+				action = "activate"
+			}
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/subscription-plans/"+tt.planID+"/"+action, nil)
+			c.Params = gin.Params{{Key: "id", Value: tt.planID}}
+
+			if tt.activate {
+				handler.ActivatePlan(c)
+			} else {
+				handler.DeactivatePlan(c)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Contains(t, response["error"], tt.expectedError)
+			} else {
+				var response struct {
+					Message string              `json:"message"`
+					Data    models.PlanResponse `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedMessage, response.Message)
+				assert.Equal(t, tt.expectedActive, response.Data.IsActive)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscriptionPlanHandler_CreatePlan_FieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		requestBody    map[string]interface{}
+		expectedFields map[string]string
+	}{
+		{
+			name:        "missing and invalid fields",
+			requestBody: map[string]interface{}{"plan_type": "weekly", "price": -1},
+			expectedFields: map[string]string{
+				"name":      "name is required",
+				"plan_type": "plan_type must be one of trial/monthly/annual",
+				"price":     "price must be at least 0",
+			},
+		},
+		{
+			name:        "wrong field type",
+			requestBody: map[string]interface{}{"name": "Basic", "plan_type": "monthly", "price": "free"},
+			expectedFields: map[string]string{
+				"price": "price must be a number",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSubscriptionPlanService)
+			handler := handlers.NewSubscriptionPlanHandler(mockService)
+
+			requestBody, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/subscription-plans", bytes.NewBuffer(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.CreatePlan(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var response struct {
+				Error  string                `json:"error"`
+				Fields []handlers.FieldError `json:"fields"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Invalid request data", response.Error)
+
+			fields := map[string]string{}
+			for _, field := range response.Fields {
+				fields[field.Field] = field.Message
+			}
+			assert.Equal(t, tt.expectedFields, fields)
+		})
+	}
+}
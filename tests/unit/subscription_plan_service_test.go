@@ -5,10 +5,12 @@ import (
 	"testing"
 
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/services"
 	"github.com/gatehide/gatehide-api/tests/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSubscriptionPlanService_CreatePlan(t *testing.T) {
@@ -223,6 +225,76 @@ func TestSubscriptionPlanService_GetPlan(t *testing.T) {
 	}
 }
 
+func TestSubscriptionPlanService_GetPlan_EffectivePrices(t *testing.T) {
+	percentage := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name                 string
+		planType             string
+		price                float64
+		discount             *float64
+		expectedAnnualPrice  float64
+		expectedMonthlyPrice float64
+	}{
+		{
+			name:                 "annual plan without discount",
+			planType:             "annual",
+			price:                1200,
+			discount:             percentage(0),
+			expectedAnnualPrice:  1200,
+			expectedMonthlyPrice: 100,
+		},
+		{
+			name:                 "annual plan with 20% discount",
+			planType:             "annual",
+			price:                999.99,
+			discount:             percentage(20),
+			expectedAnnualPrice:  799.99,
+			expectedMonthlyPrice: 66.67,
+		},
+		{
+			name:                 "annual plan with 100% discount",
+			planType:             "annual",
+			price:                1200,
+			discount:             percentage(100),
+			expectedAnnualPrice:  0,
+			expectedMonthlyPrice: 0,
+		},
+		{
+			name:                 "monthly plan ignores the discount",
+			planType:             "monthly",
+			price:                29.99,
+			discount:             percentage(20),
+			expectedAnnualPrice:  29.99,
+			expectedMonthlyPrice: 29.99,
+		},
+		{
+			name:                 "trial plan",
+			planType:             "trial",
+			price:                0,
+			expectedAnnualPrice:  0,
+			expectedMonthlyPrice: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+			plan := utils.CreateMockSubscriptionPlan(1, "Plan", tt.planType, tt.price)
+			plan.AnnualDiscountPercentage = tt.discount
+			mockRepo.On("GetByID", 1).Return(plan, nil)
+			service := services.NewSubscriptionPlanService(mockRepo)
+
+			result, err := service.GetPlan(1)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.price, result.Price)
+			assert.Equal(t, tt.expectedAnnualPrice, result.EffectiveAnnualPrice)
+			assert.Equal(t, tt.expectedMonthlyPrice, result.MonthlyEquivalentPrice)
+		})
+	}
+}
+
 func TestSubscriptionPlanService_GetAllPlans(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -340,7 +412,7 @@ func TestSubscriptionPlanService_UpdatePlan(t *testing.T) {
 			mockSetup: func(mockRepo *utils.MockSubscriptionPlanRepository) {
 				existingPlan := utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99)
 				mockRepo.On("GetByID", 1).Return(existingPlan, nil)
-				mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan")).Return(nil)
+				mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).Return(nil)
 			},
 			expectedError: "",
 		},
@@ -388,7 +460,7 @@ func TestSubscriptionPlanService_UpdatePlan(t *testing.T) {
 			mockSetup: func(mockRepo *utils.MockSubscriptionPlanRepository) {
 				existingPlan := utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99)
 				mockRepo.On("GetByID", 1).Return(existingPlan, nil)
-				mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan")).Return(errors.New("database error"))
+				mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).Return(errors.New("database error"))
 			},
 			expectedError: "failed to update plan: database error",
 		},
@@ -402,7 +474,7 @@ func TestSubscriptionPlanService_UpdatePlan(t *testing.T) {
 			service := services.NewSubscriptionPlanService(mockRepo)
 
 			// Execute
-			result, err := service.UpdatePlan(tt.planID, tt.request)
+			result, err := service.UpdatePlan(tt.planID, tt.request, nil)
 
 			// Assert
 			if tt.expectedError != "" {
@@ -501,3 +573,405 @@ func TestSubscriptionPlanService_DeletePlan(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriptionPlanService_UpdatePlan_RecordsHistory(t *testing.T) {
+	t.Run("records before/after and actor", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+
+		var recorded *models.PlanHistory
+		mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).
+			Run(func(args mock.Arguments) { recorded = args.Get(2).(*models.PlanHistory) }).
+			Return(nil)
+
+		newPrice := 39.99
+		_, err := service.UpdatePlan(1, &models.UpdatePlanRequest{Price: &newPrice}, &models.Actor{ID: 5, Type: "admin"})
+
+		assert.NoError(t, err)
+		require.NotNil(t, recorded)
+		assert.Equal(t, models.PlanHistoryActionUpdate, recorded.Action)
+		assert.Equal(t, 29.99, recorded.Before.Price)
+		assert.Equal(t, 39.99, recorded.After.Price)
+		assert.Equal(t, 5, *recorded.ChangedByID)
+		assert.Equal(t, "admin", *recorded.ChangedByType)
+	})
+
+	t.Run("records deactivation", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+
+		var recorded *models.PlanHistory
+		mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).
+			Run(func(args mock.Arguments) { recorded = args.Get(2).(*models.PlanHistory) }).
+			Return(nil)
+
+		inactive := false
+		_, err := service.UpdatePlan(1, &models.UpdatePlanRequest{IsActive: &inactive}, nil)
+
+		assert.NoError(t, err)
+		require.NotNil(t, recorded)
+		assert.Equal(t, models.PlanHistoryActionDeactivate, recorded.Action)
+		assert.Nil(t, recorded.ChangedByID)
+	})
+
+	t.Run("compares optional fields by value", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			discount float64
+			action   string
+		}{
+			{"repeated discount is still a deactivation", 10, models.PlanHistoryActionDeactivate},
+			{"changed discount is an update", 15, models.PlanHistoryActionUpdate},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+				service := services.NewSubscriptionPlanService(mockRepo)
+
+				plan := utils.CreateMockSubscriptionPlan(1, "Basic Annual", "annual", 299.99)
+				discount := 10.0
+				plan.AnnualDiscountPercentage = &discount
+				mockRepo.On("GetByID", 1).Return(plan, nil)
+
+				var recorded *models.PlanHistory
+				mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).
+					Run(func(args mock.Arguments) { recorded = args.Get(2).(*models.PlanHistory) }).
+					Return(nil)
+
+				inactive := false
+				requested := tt.discount
+				_, err := service.UpdatePlan(1, &models.UpdatePlanRequest{IsActive: &inactive, AnnualDiscountPercentage: &requested}, nil)
+
+				assert.NoError(t, err)
+				require.NotNil(t, recorded)
+				assert.Equal(t, tt.action, recorded.Action)
+			})
+		}
+	})
+}
+
+func TestSubscriptionPlanService_UpdatePlan_PriceChange(t *testing.T) {
+	recordHistory := func(mockRepo *utils.MockSubscriptionPlanRepository, recorded **models.PlanHistory) {
+		mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).
+			Run(func(args mock.Arguments) { *recorded = args.Get(2).(*models.PlanHistory) }).
+			Return(nil)
+	}
+
+	t.Run("existing subscribers keep their price by default", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		var recorded *models.PlanHistory
+		recordHistory(mockRepo, &recorded)
+
+		newPrice := 39.99
+		result, err := service.UpdatePlan(1, &models.UpdatePlanRequest{Price: &newPrice}, &models.Actor{ID: 5, Type: "admin"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 39.99, result.Price)
+		require.NotNil(t, recorded)
+		assert.False(t, recorded.RepriceSubscriptions)
+	})
+
+	t.Run("asks the update to reprice existing subscribers when enabled", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+		service.SetRepriceExistingSubscribers(true)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		var recorded *models.PlanHistory
+		recordHistory(mockRepo, &recorded)
+
+		newPrice := 39.99
+		_, err := service.UpdatePlan(1, &models.UpdatePlanRequest{Price: &newPrice}, nil)
+
+		assert.NoError(t, err)
+		require.NotNil(t, recorded)
+		assert.True(t, recorded.RepriceSubscriptions)
+		utils.AssertSubscriptionPlanRepositoryExpectations(t, mockRepo)
+	})
+
+	t.Run("unchanged price does not reprice", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+		service.SetRepriceExistingSubscribers(true)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		var recorded *models.PlanHistory
+		recordHistory(mockRepo, &recorded)
+
+		name := "Basic"
+		_, err := service.UpdatePlan(1, &models.UpdatePlanRequest{Name: &name}, nil)
+
+		assert.NoError(t, err)
+		require.NotNil(t, recorded)
+		assert.False(t, recorded.RepriceSubscriptions)
+	})
+
+	t.Run("reprice failure fails the update", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+		service.SetRepriceExistingSubscribers(true)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).
+			Return(errors.New("failed to reprice subscriptions: database error"))
+
+		newPrice := 39.99
+		result, err := service.UpdatePlan(1, &models.UpdatePlanRequest{Price: &newPrice}, nil)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to update plan")
+		assert.Nil(t, result)
+	})
+}
+
+func TestSubscriptionPlanRepository_Update_PriceChange(t *testing.T) {
+	newHistory := func(reprice bool) *models.PlanHistory {
+		before := utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99).ToResponse()
+		after := before
+		after.Price = 39.99
+		return &models.PlanHistory{PlanID: 1, Action: models.PlanHistoryActionUpdate, Before: &before, After: &after, RepriceSubscriptions: reprice}
+	}
+
+	t.Run("locks subscribed prices by default", func(t *testing.T) {
+		db, fake := utils.NewFakeDB(t)
+		fake.OnExec("UPDATE subscription_plans", 1)
+		history := newHistory(false)
+
+		err := repositories.NewSubscriptionPlanRepository(db).Update(1, utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 39.99), history)
+
+		require.NoError(t, err)
+		assert.True(t, fake.Ran("SET us.subscribed_price = sp.price"))
+		assert.False(t, fake.Ran("SET subscribed_price = ?"))
+		assert.Nil(t, history.RepricedSubscriptions)
+		assert.True(t, fake.Ran("INSERT INTO plan_history"))
+	})
+
+	t.Run("reprices subscriptions in the update and records the count", func(t *testing.T) {
+		db, fake := utils.NewFakeDB(t)
+		fake.OnExec("UPDATE subscription_plans", 1)
+		fake.OnExec("SET subscribed_price = ?", 3)
+		history := newHistory(true)
+
+		err := repositories.NewSubscriptionPlanRepository(db).Update(1, utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 39.99), history)
+
+		require.NoError(t, err)
+		assert.False(t, fake.Ran("SET us.subscribed_price = sp.price"))
+		require.NotNil(t, history.RepricedSubscriptions)
+		assert.Equal(t, int64(3), *history.RepricedSubscriptions)
+		assert.True(t, fake.Ran("INSERT INTO plan_history"))
+	})
+
+	t.Run("does not reprice a missing plan", func(t *testing.T) {
+		db, fake := utils.NewFakeDB(t)
+
+		err := repositories.NewSubscriptionPlanRepository(db).Update(1, utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 39.99), newHistory(true))
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+		assert.False(t, fake.Ran("SET subscribed_price = ?"))
+	})
+}
+
+func TestSubscriptionPlanService_SetActive(t *testing.T) {
+	actor := &models.Actor{ID: 1, Type: "admin"}
+
+	t.Run("deactivates a plan with active subscriptions", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		plan := utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99)
+		plan.SubscriptionCount = 3
+		mockRepo.On("GetByID", 1).Return(plan, nil)
+		mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.MatchedBy(func(h *models.PlanHistory) bool {
+			return h.Action == models.PlanHistoryActionDeactivate && h.Before.IsActive && !h.After.IsActive
+		})).Return(nil)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		result, err := service.SetActive(1, false, actor)
+
+		require.NoError(t, err)
+		assert.False(t, result.IsActive)
+		mockRepo.AssertNotCalled(t, "HasActiveSubscriptions", mock.Anything)
+		utils.AssertSubscriptionPlanRepositoryExpectations(t, mockRepo)
+	})
+
+	t.Run("activates a plan", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		plan := utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99)
+		plan.IsActive = false
+		mockRepo.On("GetByID", 1).Return(plan, nil)
+		mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.MatchedBy(func(h *models.PlanHistory) bool {
+			return h.Action == models.PlanHistoryActionActivate
+		})).Return(nil)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		result, err := service.SetActive(1, true, actor)
+
+		require.NoError(t, err)
+		assert.True(t, result.IsActive)
+	})
+
+	t.Run("leaves a plan already in the requested state alone", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		result, err := service.SetActive(1, true, actor)
+
+		require.NoError(t, err)
+		assert.True(t, result.IsActive)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports a missing plan", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		mockRepo.On("GetByID", 999).Return(nil, repositories.ErrNotFound)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		_, err := service.SetActive(999, false, actor)
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestSubscriptionPlanService_GetPlanHistory(t *testing.T) {
+	t.Run("returns history", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		history := []*models.PlanHistory{{ID: 1, PlanID: 1, Action: models.PlanHistoryActionUpdate}}
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		mockRepo.On("GetHistory", 1).Return(history, nil)
+
+		result, err := service.GetPlanHistory(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, history, result)
+	})
+
+	t.Run("plan not found", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 999).Return(nil, errors.New("subscription plan not found"))
+
+		_, err := service.GetPlanHistory(999)
+
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "GetHistory", mock.Anything)
+	})
+}
+
+func TestSubscriptionPlanService_Clone(t *testing.T) {
+	t.Run("creates inactive copy", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		discount := 15.0
+		source := utils.CreateMockSubscriptionPlan(1, "Pro Annual", "annual", 299.99)
+		source.AnnualDiscountPercentage = &discount
+		source.SubscriptionCount = 12
+
+		mockRepo.On("GetByID", 1).Return(source, nil)
+		mockRepo.On("ExistsByName", "Pro Annual (copy)").Return(false, nil)
+
+		var created *models.SubscriptionPlan
+		mockRepo.On("Create", mock.AnythingOfType("*models.SubscriptionPlan")).
+			Run(func(args mock.Arguments) { created = args.Get(0).(*models.SubscriptionPlan) }).
+			Return(nil)
+
+		result, err := service.Clone(1)
+
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, "Pro Annual (copy)", result.Name)
+		assert.Equal(t, "annual", result.PlanType)
+		assert.Equal(t, 299.99, result.Price)
+		assert.False(t, result.IsActive)
+		assert.Equal(t, 0, created.SubscriptionCount)
+		assert.Equal(t, 15.0, *created.AnnualDiscountPercentage)
+		assert.NotSame(t, source.AnnualDiscountPercentage, created.AnnualDiscountPercentage)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("picks next free name", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic", "monthly", 9.99), nil)
+		mockRepo.On("ExistsByName", "Basic (copy)").Return(true, nil)
+		mockRepo.On("ExistsByName", "Basic (copy 2)").Return(true, nil)
+		mockRepo.On("ExistsByName", "Basic (copy 3)").Return(false, nil)
+		mockRepo.On("Create", mock.AnythingOfType("*models.SubscriptionPlan")).Return(nil)
+
+		result, err := service.Clone(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Basic (copy 3)", result.Name)
+	})
+
+	t.Run("source plan not found", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 999).Return(nil, errors.New("subscription plan not found"))
+
+		result, err := service.Clone(999)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestSubscriptionPlanService_GetDeleteImpact(t *testing.T) {
+	t.Run("plan without active subscriptions can be deleted", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		mockRepo.On("CountActiveSubscriptions", 1).Return(0, nil)
+
+		impact, err := service.GetDeleteImpact(1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, impact.PlanID)
+		assert.Equal(t, "Basic Monthly", impact.PlanName)
+		assert.Equal(t, 0, impact.ActiveSubscriptions)
+		assert.True(t, impact.CanDelete)
+		assert.Empty(t, impact.Reason)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("plan with active subscriptions cannot be deleted", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		mockRepo.On("CountActiveSubscriptions", 1).Return(4, nil)
+
+		impact, err := service.GetDeleteImpact(1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, impact.ActiveSubscriptions)
+		assert.False(t, impact.CanDelete)
+		assert.Equal(t, "plan has active subscriptions", impact.Reason)
+	})
+
+	t.Run("plan not found", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		mockRepo.On("GetByID", 999).Return(nil, repositories.ErrNotFound)
+
+		impact, err := service.GetDeleteImpact(999)
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+		assert.Nil(t, impact)
+	})
+}
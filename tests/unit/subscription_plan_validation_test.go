@@ -375,11 +375,11 @@ func TestSubscriptionPlanUpdateValidation(t *testing.T) {
 			// Mock expectations
 			mockRepo.On("GetByID", 1).Return(tt.existingPlan, nil)
 			if tt.expectedError == "" {
-				mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan")).Return(nil)
+				mockRepo.On("Update", 1, mock.AnythingOfType("*models.SubscriptionPlan"), mock.AnythingOfType("*models.PlanHistory")).Return(nil)
 			}
 
 			// Execute
-			result, err := service.UpdatePlan(1, tt.updateRequest)
+			result, err := service.UpdatePlan(1, tt.updateRequest, nil)
 
 			// Assert
 			if tt.expectedError != "" {
@@ -569,3 +569,65 @@ func TestSubscriptionPlanBusinessRules(t *testing.T) {
 		})
 	}
 }
+
+// TestSubscriptionPlanTrialDurationLimit tests the upper bound on trial duration
+func TestSubscriptionPlanTrialDurationLimit(t *testing.T) {
+	days := func(v int) *int { return &v }
+	newTrialRequest := func(d int) *models.CreatePlanRequest {
+		return &models.CreatePlanRequest{
+			Name:              "Trial Plan",
+			PlanType:          "trial",
+			TrialDurationDays: days(d),
+			IsActive:          true,
+		}
+	}
+
+	t.Run("default maximum accepts 365 days", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		mockRepo.On("Create", mock.AnythingOfType("*models.SubscriptionPlan")).Return(nil)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		result, err := service.CreatePlan(newTrialRequest(services.DefaultMaxTrialDurationDays))
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+	})
+
+	t.Run("default maximum rejects 366 days", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		service := services.NewSubscriptionPlanService(mockRepo)
+
+		result, err := service.CreatePlan(newTrialRequest(services.DefaultMaxTrialDurationDays + 1))
+
+		assert.EqualError(t, err, "trial duration exceeds maximum of 365 days")
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("configured maximum is enforced on create", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		mockRepo.On("Create", mock.AnythingOfType("*models.SubscriptionPlan")).Return(nil).Once()
+		service := services.NewSubscriptionPlanService(mockRepo)
+		service.SetMaxTrialDurationDays(30)
+
+		_, err := service.CreatePlan(newTrialRequest(30))
+		assert.NoError(t, err)
+
+		_, err = service.CreatePlan(newTrialRequest(31))
+		assert.EqualError(t, err, "trial duration exceeds maximum of 30 days")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("configured maximum is enforced on update", func(t *testing.T) {
+		mockRepo := utils.SetupMockSubscriptionPlanRepository(t)
+		mockRepo.On("GetByID", 1).Return(utils.CreateMockSubscriptionPlan(1, "Basic Monthly", "monthly", 29.99), nil)
+		service := services.NewSubscriptionPlanService(mockRepo)
+		service.SetMaxTrialDurationDays(30)
+
+		result, err := service.UpdatePlan(1, &models.UpdatePlanRequest{TrialDurationDays: days(31)}, nil)
+
+		assert.EqualError(t, err, "trial duration exceeds maximum of 30 days")
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,208 @@
+package unit
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionPlan_SubscriptionEndDate(t *testing.T) {
+	start := time.Date(2025, time.January, 15, 10, 0, 0, 0, time.UTC)
+	trialDays := 14
+
+	tests := []struct {
+		name     string
+		plan     *models.SubscriptionPlan
+		expected time.Time
+	}{
+		{
+			name:     "monthly plan runs one month",
+			plan:     &models.SubscriptionPlan{PlanType: "monthly"},
+			expected: time.Date(2025, time.February, 15, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "annual plan runs one year",
+			plan:     &models.SubscriptionPlan{PlanType: "annual"},
+			expected: time.Date(2026, time.January, 15, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "trial plan runs its trial duration",
+			plan:     &models.SubscriptionPlan{PlanType: "trial", TrialDurationDays: &trialDays},
+			expected: time.Date(2025, time.January, 29, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endDate, err := tt.plan.SubscriptionEndDate(start)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, endDate)
+		})
+	}
+
+	t.Run("trial plan without a duration fails", func(t *testing.T) {
+		_, err := (&models.SubscriptionPlan{PlanType: "trial"}).SubscriptionEndDate(start)
+
+		assert.Error(t, err)
+	})
+}
+
+func newSubscriptionService() (*services.SubscriptionService, *utils.MockUserSubscriptionRepository, *utils.MockSubscriptionPlanRepository) {
+	repo := new(utils.MockUserSubscriptionRepository)
+	planRepo := new(utils.MockSubscriptionPlanRepository)
+	return services.NewSubscriptionService(repo, planRepo), repo, planRepo
+}
+
+func TestSubscriptionService_Subscribe(t *testing.T) {
+	t.Run("starts a trial that ends after the trial duration", func(t *testing.T) {
+		service, repo, planRepo := newSubscriptionService()
+		trialDays := 7
+		plan := utils.CreateMockSubscriptionPlan(1, "Trial", "trial", 0)
+		plan.TrialDurationDays = &trialDays
+		planRepo.On("GetByID", 1).Return(plan, nil)
+
+		var created *models.UserSubscription
+		repo.On("Create", mock.AnythingOfType("*models.UserSubscription")).
+			Run(func(args mock.Arguments) {
+				created = args.Get(0).(*models.UserSubscription)
+				created.ID = 10
+			}).
+			Return(nil)
+
+		response, err := service.Subscribe(5, 1)
+
+		require.NoError(t, err)
+		require.NotNil(t, created)
+		assert.Equal(t, 10, response.ID)
+		assert.Equal(t, models.SubscriptionStatusTrial, created.Status)
+		assert.False(t, created.AutoRenew)
+		assert.Equal(t, created.StartedAt.AddDate(0, 0, trialDays), *created.ExpiresAt)
+		require.NotNil(t, response.Plan)
+		assert.Equal(t, "Trial", response.Plan.Name)
+	})
+
+	t.Run("locks in the plan price", func(t *testing.T) {
+		service, repo, planRepo := newSubscriptionService()
+		planRepo.On("GetByID", 2).Return(utils.CreateMockSubscriptionPlan(2, "Monthly", "monthly", 100), nil)
+		repo.On("Create", mock.AnythingOfType("*models.UserSubscription")).Return(nil)
+
+		response, err := service.Subscribe(5, 2)
+
+		require.NoError(t, err)
+		assert.Equal(t, models.SubscriptionStatusActive, response.Status)
+		require.NotNil(t, response.SubscribedPrice)
+		assert.Equal(t, 100.0, *response.SubscribedPrice)
+		assert.True(t, response.AutoRenew)
+	})
+
+	t.Run("locks in the discounted annual price", func(t *testing.T) {
+		service, repo, planRepo := newSubscriptionService()
+		discount := 20.0
+		plan := utils.CreateMockSubscriptionPlan(2, "Annual", "annual", 1200)
+		plan.AnnualDiscountPercentage = &discount
+		planRepo.On("GetByID", 2).Return(plan, nil)
+		repo.On("Create", mock.AnythingOfType("*models.UserSubscription")).Return(nil)
+
+		response, err := service.Subscribe(5, 2)
+
+		require.NoError(t, err)
+		require.NotNil(t, response.SubscribedPrice)
+		assert.Equal(t, 960.0, *response.SubscribedPrice)
+	})
+
+	t.Run("refuses an overlapping subscription", func(t *testing.T) {
+		service, repo, planRepo := newSubscriptionService()
+		planRepo.On("GetByID", 2).Return(utils.CreateMockSubscriptionPlan(2, "Monthly", "monthly", 100), nil)
+		repo.On("Create", mock.AnythingOfType("*models.UserSubscription")).Return(repositories.ErrSubscriptionOverlap)
+
+		response, err := service.Subscribe(5, 2)
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, services.ErrActiveSubscriptionExists)
+	})
+
+	t.Run("refuses an inactive plan", func(t *testing.T) {
+		service, repo, planRepo := newSubscriptionService()
+		plan := utils.CreateMockSubscriptionPlan(2, "Monthly", "monthly", 100)
+		plan.IsActive = false
+		planRepo.On("GetByID", 2).Return(plan, nil)
+
+		_, err := service.Subscribe(5, 2)
+
+		assert.ErrorIs(t, err, services.ErrPlanNotAvailable)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestSubscriptionService_Cancel(t *testing.T) {
+	t.Run("cancels an active subscription", func(t *testing.T) {
+		service, repo, _ := newSubscriptionService()
+		repo.On("GetByID", 3).Return(&models.UserSubscription{ID: 3, Status: models.SubscriptionStatusActive}, nil)
+		repo.On("Cancel", 3).Return(nil)
+
+		assert.NoError(t, service.Cancel(3))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("refuses a cancelled subscription", func(t *testing.T) {
+		service, repo, _ := newSubscriptionService()
+		repo.On("GetByID", 3).Return(&models.UserSubscription{ID: 3, Status: models.SubscriptionStatusCancelled}, nil)
+
+		assert.ErrorIs(t, service.Cancel(3), services.ErrSubscriptionNotActive)
+		repo.AssertNotCalled(t, "Cancel", mock.Anything)
+	})
+
+	t.Run("reports a missing subscription", func(t *testing.T) {
+		service, repo, _ := newSubscriptionService()
+		repo.On("GetByID", 3).Return(nil, repositories.ErrNotFound)
+
+		assert.ErrorIs(t, service.Cancel(3), repositories.ErrNotFound)
+	})
+}
+
+func TestUserSubscriptionRepository_Create(t *testing.T) {
+	newSubscription := func() *models.UserSubscription {
+		expiresAt := time.Now().AddDate(0, 1, 0)
+		return &models.UserSubscription{GamenetID: 5, PlanID: 2, Status: models.SubscriptionStatusActive, StartedAt: time.Now(), ExpiresAt: &expiresAt}
+	}
+
+	t.Run("locks the gamenet and refuses an overlapping subscription", func(t *testing.T) {
+		db, fake := utils.NewFakeDB(t)
+		fake.OnQuery("FROM gamenets WHERE id = ? FOR UPDATE", []string{"id"}, []driver.Value{int64(5)})
+		fake.OnQuery("SELECT EXISTS", []string{"overlapping"}, []driver.Value{true})
+
+		err := repositories.NewUserSubscriptionRepository(db).Create(newSubscription())
+
+		assert.ErrorIs(t, err, repositories.ErrSubscriptionOverlap)
+		assert.False(t, fake.Ran("INSERT INTO user_subscriptions"))
+	})
+
+	t.Run("creates the subscription when the gamenet has none", func(t *testing.T) {
+		db, fake := utils.NewFakeDB(t)
+		fake.OnQuery("FROM gamenets WHERE id = ? FOR UPDATE", []string{"id"}, []driver.Value{int64(5)})
+		fake.OnQuery("SELECT EXISTS", []string{"overlapping"}, []driver.Value{false})
+
+		err := repositories.NewUserSubscriptionRepository(db).Create(newSubscription())
+
+		require.NoError(t, err)
+		assert.True(t, fake.Ran("INSERT INTO user_subscriptions"))
+		assert.True(t, fake.Ran("INSERT INTO subscription_history"))
+	})
+
+	t.Run("reports a missing gamenet", func(t *testing.T) {
+		db, _ := utils.NewFakeDB(t)
+
+		err := repositories.NewUserSubscriptionRepository(db).Create(newSubscription())
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
@@ -0,0 +1,144 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPasswordResetEmailTemplate() *models.NotificationTemplate {
+	return &models.NotificationTemplate{
+		Name:        services.EmailTemplatePasswordReset,
+		Type:        models.NotificationTypeEmail,
+		Subject:     "Reset your {{app_name}} password",
+		Content:     "Hi {{user_name}}, reset it here: {{reset_link}} ({{expiry_hours}}h)",
+		HTMLContent: `<p>Hi {{user_name}}, <a href="{{reset_link}}">reset it</a></p>`,
+		IsActive:    true,
+	}
+}
+
+func passwordResetEmailData() map[string]interface{} {
+	return map[string]interface{}{
+		"app_name":     "GateHide",
+		"user_name":    "Sara <admin>",
+		"reset_link":   "https://example.com/reset?token=abc&email=sara@example.com",
+		"expiry_hours": "0.25",
+	}
+}
+
+func TestTemplateRenderer_RenderTemplate(t *testing.T) {
+	renderer := services.NewTemplateRenderer(nil)
+
+	t.Run("fills every placeholder", func(t *testing.T) {
+		rendered, err := renderer.RenderTemplate(newPasswordResetEmailTemplate(), passwordResetEmailData())
+
+		require.NoError(t, err)
+		assert.Equal(t, "Reset your GateHide password", rendered.Subject)
+		assert.Equal(t, "Hi Sara <admin>, reset it here: https://example.com/reset?token=abc&email=sara@example.com (0.25h)", rendered.Content)
+		// Values are escaped in the HTML content only
+		assert.Equal(t, `<p>Hi Sara &lt;admin&gt;, <a href="https://example.com/reset?token=abc&amp;email=sara@example.com">reset it</a></p>`, rendered.HTMLContent)
+	})
+
+	t.Run("rejects a placeholder without a value", func(t *testing.T) {
+		data := passwordResetEmailData()
+		delete(data, "reset_link")
+
+		rendered, err := renderer.RenderTemplate(newPasswordResetEmailTemplate(), data)
+
+		assert.Nil(t, rendered)
+		assert.ErrorIs(t, err, services.ErrTemplateVariableMissing)
+		assert.Contains(t, err.Error(), "reset_link")
+	})
+}
+
+func TestTemplateRenderer_Render(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("prefers the stored template", func(t *testing.T) {
+		templateRepo := new(testutils.MockTemplateRepository)
+		templateRepo.On("GetByNameAndType", services.EmailTemplatePasswordReset, models.NotificationTypeEmail).
+			Return(newPasswordResetEmailTemplate(), nil)
+		renderer := services.NewTemplateRenderer(services.NewTemplateService(templateRepo))
+
+		rendered, err := renderer.Render(ctx, services.EmailTemplatePasswordReset, models.NotificationTypeEmail, passwordResetEmailData())
+
+		require.NoError(t, err)
+		assert.Equal(t, "Reset your GateHide password", rendered.Subject)
+	})
+
+	t.Run("falls back to the default when the template is missing", func(t *testing.T) {
+		templateRepo := new(testutils.MockTemplateRepository)
+		templateRepo.On("GetByNameAndType", services.EmailTemplatePasswordReset, models.NotificationTypeEmail).
+			Return(nil, repositories.ErrNotFound)
+		renderer := services.NewTemplateRenderer(services.NewTemplateService(templateRepo))
+
+		rendered, err := renderer.Render(ctx, services.EmailTemplatePasswordReset, models.NotificationTypeEmail, passwordResetEmailData())
+
+		require.NoError(t, err)
+		assert.Equal(t, "بازنشانی رمز عبور - GateHide", rendered.Subject)
+		assert.Contains(t, rendered.Content, "https://example.com/reset?token=abc&email=sara@example.com")
+	})
+
+	t.Run("falls back to the default when the stored template needs unknown data", func(t *testing.T) {
+		stored := newPasswordResetEmailTemplate()
+		stored.Content += " {{promo_code}}"
+		templateRepo := new(testutils.MockTemplateRepository)
+		templateRepo.On("GetByNameAndType", services.EmailTemplatePasswordReset, models.NotificationTypeEmail).
+			Return(stored, nil)
+		renderer := services.NewTemplateRenderer(services.NewTemplateService(templateRepo))
+
+		rendered, err := renderer.Render(ctx, services.EmailTemplatePasswordReset, models.NotificationTypeEmail, passwordResetEmailData())
+
+		require.NoError(t, err)
+		assert.Equal(t, "بازنشانی رمز عبور - GateHide", rendered.Subject)
+	})
+
+	t.Run("fails for an unknown template", func(t *testing.T) {
+		renderer := services.NewTemplateRenderer(nil)
+
+		_, err := renderer.Render(ctx, "missing_email", models.NotificationTypeEmail, nil)
+
+		assert.ErrorIs(t, err, services.ErrTemplateNotFound)
+	})
+}
+
+func TestAuthService_SendEmailVerification_StoredTemplate(t *testing.T) {
+	authService, m, verificationRepo, notificationService := newVerificationAuthService()
+	user := testutils.CreateMockUser(1, "user@example.com", "Test User")
+	verificationRepo.On("ReserveCode", user.ID, "user", "new@example.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), 60*time.Second).
+		Return(time.Duration(0), nil)
+	m.userRepo.On("GetByID", user.ID).Return(user, nil)
+
+	templateRepo := new(testutils.MockTemplateRepository)
+	templateRepo.On("GetByNameAndType", services.EmailTemplateEmailVerification, models.NotificationTypeEmail).
+		Return(&models.NotificationTemplate{
+			Name:        services.EmailTemplateEmailVerification,
+			Type:        models.NotificationTypeEmail,
+			Subject:     "Confirm {{new_email}}",
+			Content:     "Code: {{verification_code}}",
+			HTMLContent: "<p>Code for {{new_email}}: <b>{{verification_code}}</b></p>",
+			IsActive:    true,
+		}, nil)
+	authService.SetTemplateService(services.NewTemplateService(templateRepo))
+
+	var sent *models.CreateNotificationRequest
+	notificationService.On("SendNotification", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(*models.CreateNotificationRequest) }).
+		Return(nil)
+
+	code, err := authService.SendEmailVerification(user.ID, "user", "new@example.com")
+
+	require.NoError(t, err)
+	require.NotNil(t, sent)
+	assert.Equal(t, "Confirm new@example.com", sent.Subject)
+	assert.Equal(t, "Code: "+code, sent.Content)
+	assert.Equal(t, "<p>Code for new@example.com: <b>"+code+"</b></p>", sent.HTMLContent)
+}
@@ -0,0 +1,210 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/kavenegar/kavenegar-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newCredentialsSMSTemplate(content string) *models.NotificationTemplate {
+	return &models.NotificationTemplate{
+		Name:      services.SMSTemplateUserCredentials,
+		Type:      models.NotificationTypeSMS,
+		Subject:   "Credentials",
+		Content:   content,
+		Variables: []string{"email", "password"},
+		IsActive:  true,
+	}
+}
+
+func TestTemplateService_RenderTemplate(t *testing.T) {
+	ctx := context.Background()
+	templateService := services.NewTemplateService(new(testutils.MockTemplateRepository))
+	template := newCredentialsSMSTemplate("Email: {{email}}\nPassword: {{ password }}")
+
+	t.Run("substitutes provided variables", func(t *testing.T) {
+		_, content, err := templateService.RenderTemplate(ctx, template, map[string]interface{}{
+			"email":    "user@example.com",
+			"password": 12345678,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Email: user@example.com\nPassword: 12345678", content)
+	})
+
+	t.Run("leaves placeholders without values untouched", func(t *testing.T) {
+		_, content, err := templateService.RenderTemplate(ctx, template, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Email: {{email}}\nPassword: {{ password }}", content)
+	})
+}
+
+func TestSMSService_CredentialsUseStoredTemplate(t *testing.T) {
+	ctx := context.Background()
+	mobile := "09123456789"
+
+	t.Run("renders the stored template", func(t *testing.T) {
+		templateRepo := new(testutils.MockTemplateRepository)
+		templateRepo.On("GetByNameAndType", services.SMSTemplateUserCredentials, models.NotificationTypeSMS).
+			Return(newCredentialsSMSTemplate("Login {{email}} / {{password}}"), nil)
+
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "", mock.AnythingOfType("[]string"), "Login user@example.com / 12345678", mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 12}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetTemplateService(services.NewTemplateService(templateRepo))
+		err := smsService.SendUserCredentials(ctx, mobile, "user@example.com", "12345678")
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+		templateRepo.AssertExpectations(t)
+	})
+
+	t.Run("falls back to the default template", func(t *testing.T) {
+		templateRepo := new(testutils.MockTemplateRepository)
+		templateRepo.On("GetByNameAndType", services.SMSTemplateUserCredentials, models.NotificationTypeSMS).
+			Return(nil, errors.New("template not found"))
+
+		client := new(testutils.MockKavenegarClient)
+		client.On("SendMessage", "", mock.AnythingOfType("[]string"),
+			"اطلاعات ورود به سیستم:\nایمیل: user@example.com\nرمز عبور: 12345678", mock.Anything).
+			Return([]kavenegar.Message{{Status: 1, MessageID: 13}}, nil)
+
+		smsService := services.NewSMSServiceWithClient(newTestSMSConfig(services.SMSStrategySMSOnly), client)
+		smsService.SetTemplateService(services.NewTemplateService(templateRepo))
+		err := smsService.SendUserCredentials(ctx, mobile, "user@example.com", "12345678")
+
+		assert.NoError(t, err)
+		client.AssertExpectations(t)
+	})
+}
+
+func TestNotificationService_SendSMS_Template(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+
+	t.Run("renders the template when a key is provided", func(t *testing.T) {
+		templateRepo := new(testutils.MockTemplateRepository)
+		templateRepo.On("GetByNameAndType", "otp_sms", models.NotificationTypeSMS).
+			Return(&models.NotificationTemplate{
+				Name:     "otp_sms",
+				Type:     models.NotificationTypeSMS,
+				Subject:  "OTP",
+				Content:  "Your code is {{code}}",
+				IsActive: true,
+			}, nil)
+
+		smsService := new(MockSMSService)
+		smsService.On("SendSMS", ctx, mock.MatchedBy(func(sms *models.SMSNotification) bool {
+			return sms.Message == "Your code is 4821"
+		})).Return(nil)
+
+		service := services.NewNotificationService(nil, smsService, nil, services.NewTemplateService(templateRepo), nil, nil, cfg)
+		err := service.SendSMS(ctx, &models.SendSMSRequest{
+			To:           "09123456789",
+			Template:     "otp_sms",
+			TemplateData: map[string]interface{}{"code": "4821"},
+		})
+
+		assert.NoError(t, err)
+		smsService.AssertExpectations(t)
+	})
+
+	t.Run("sends the raw message without a template key", func(t *testing.T) {
+		smsService := new(MockSMSService)
+		smsService.On("SendSMS", ctx, mock.MatchedBy(func(sms *models.SMSNotification) bool {
+			return sms.Message == "Hello"
+		})).Return(nil)
+
+		service := services.NewNotificationService(nil, smsService, nil, nil, nil, nil, cfg)
+		err := service.SendSMS(ctx, &models.SendSMSRequest{To: "09123456789", Message: "Hello"})
+
+		assert.NoError(t, err)
+		smsService.AssertExpectations(t)
+	})
+
+	t.Run("fails when the template is missing and there is no message", func(t *testing.T) {
+		templateRepo := new(testutils.MockTemplateRepository)
+		templateRepo.On("GetByNameAndType", "otp_sms", models.NotificationTypeSMS).
+			Return(nil, errors.New("template not found"))
+
+		smsService := new(MockSMSService)
+		service := services.NewNotificationService(nil, smsService, nil, services.NewTemplateService(templateRepo), nil, nil, cfg)
+		err := service.SendSMS(ctx, &models.SendSMSRequest{To: "09123456789", Template: "otp_sms"})
+
+		assert.Error(t, err)
+		smsService.AssertNotCalled(t, "SendSMS", mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotificationHandler_PreviewTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	templateRepo := new(testutils.MockTemplateRepository)
+	templateRepo.On("GetByNameAndType", services.SMSTemplateUserCredentials, models.NotificationTypeSMS).
+		Return(newCredentialsSMSTemplate("Email: {{email}}\nPassword: {{password}}"), nil)
+	templateRepo.On("GetByNameAndType", "missing_sms", models.NotificationTypeSMS).
+		Return(nil, errors.New("template not found"))
+
+	handler := handlers.NewNotificationHandler(nil, services.NewTemplateService(templateRepo), nil, nil)
+	router := gin.New()
+	router.POST("/admin/notifications/templates/preview", handler.PreviewTemplate)
+
+	preview := func(body map[string]interface{}) *httptest.ResponseRecorder {
+		requestBody, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/admin/notifications/templates/preview", bytes.NewBuffer(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("renders with variables", func(t *testing.T) {
+		w := preview(map[string]interface{}{
+			"name": services.SMSTemplateUserCredentials,
+			"data": map[string]interface{}{"email": "user@example.com", "password": "secret"},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data models.TemplatePreviewResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Email: user@example.com\nPassword: secret", response.Data.Content)
+		assert.Empty(t, response.Data.MissingVariables)
+	})
+
+	t.Run("reports missing variables", func(t *testing.T) {
+		w := preview(map[string]interface{}{"name": services.SMSTemplateUserCredentials})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data models.TemplatePreviewResponse `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Email: {{email}}\nPassword: {{password}}", response.Data.Content)
+		assert.ElementsMatch(t, []string{"email", "password"}, response.Data.MissingVariables)
+	})
+
+	t.Run("returns 404 for unknown templates", func(t *testing.T) {
+		w := preview(map[string]interface{}{"name": "missing_sms"})
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp_MarshalJSON(t *testing.T) {
+	tehran := time.FixedZone("IRST", 3*3600+30*60)
+	known := time.Date(2026, time.March, 1, 12, 0, 0, 123456789, tehran)
+
+	t.Run("emits RFC3339 in UTC", func(t *testing.T) {
+		data, err := json.Marshal(models.NewTimestamp(known))
+
+		require.NoError(t, err)
+		assert.Equal(t, `"2026-03-01T08:30:00Z"`, string(data))
+	})
+
+	t.Run("round trips", func(t *testing.T) {
+		data, err := json.Marshal(models.NewTimestamp(known))
+		require.NoError(t, err)
+
+		var decoded models.Timestamp
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.True(t, decoded.Equal(known.Truncate(time.Second)))
+		assert.Equal(t, time.UTC, decoded.Location())
+	})
+
+	t.Run("nil pointer stays null", func(t *testing.T) {
+		assert.Nil(t, models.NewTimestampPtr(nil))
+	})
+}
+
+func TestResponses_SerializeTimestampsInUTC(t *testing.T) {
+	tehran := time.FixedZone("IRST", 3*3600+30*60)
+	created := time.Date(2026, time.March, 1, 12, 0, 0, 0, tehran)
+	lastLogin := time.Date(2026, time.March, 2, 1, 15, 0, 0, tehran)
+
+	user := &models.User{ID: 1, Name: "Test User", CreatedAt: created, UpdatedAt: created, LastLoginAt: &lastLogin}
+	session := &models.UserSession{ID: 1, CreatedAt: created, LastActivityAt: created, ExpiresAt: created.Add(24 * time.Hour)}
+
+	userJSON, err := json.Marshal(user.ToResponse())
+	require.NoError(t, err)
+	sessionJSON, err := json.Marshal(session.ToResponse())
+	require.NoError(t, err)
+
+	var userBody, sessionBody map[string]interface{}
+	require.NoError(t, json.Unmarshal(userJSON, &userBody))
+	require.NoError(t, json.Unmarshal(sessionJSON, &sessionBody))
+
+	assert.Equal(t, "2026-03-01T08:30:00Z", userBody["created_at"])
+	assert.Equal(t, "2026-03-01T21:45:00Z", userBody["last_login_at"])
+	assert.Nil(t, userBody["suspended_at"])
+	assert.Equal(t, "2026-03-02T08:30:00Z", sessionBody["expires_at"])
+}
+
+func TestLoginAuditResponses_SerializeTimestampsInUTC(t *testing.T) {
+	tehran := time.FixedZone("IRST", 3*3600+30*60)
+	created := models.NewTimestamp(time.Date(2026, time.March, 1, 12, 0, 0, 0, tehran))
+
+	responses := []interface{}{
+		models.LoginEvent{ID: 1, CreatedAt: created},
+		models.LoginAttempt{ID: 1, CreatedAt: created},
+		models.LoginAttemptCount{Value: "user@example.com", LastSeen: created},
+		models.RecentlyActiveUser{ID: 1, LastLoginAt: created},
+	}
+
+	for _, response := range responses {
+		body, err := json.Marshal(response)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"2026-03-01T08:30:00Z"`)
+	}
+}
@@ -0,0 +1,118 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	// 600 per minute = one token every 100ms, burst of 2
+	bucket := utils.NewTokenBucket(600, 2)
+
+	assert.Zero(t, bucket.Reserve())
+	assert.Zero(t, bucket.Reserve())
+
+	// Further reservations are spaced one interval apart
+	interval := 100 * time.Millisecond
+	for i := 1; i <= 3; i++ {
+		assert.InDelta(t, float64(time.Duration(i)*interval), float64(bucket.Reserve()), float64(10*time.Millisecond))
+	}
+
+	stats := bucket.Stats()
+	assert.Equal(t, 600, stats.RatePerMinute)
+	assert.Equal(t, 2, stats.Burst)
+	assert.Equal(t, int64(5), stats.Reserved)
+	assert.Equal(t, int64(3), stats.Delayed)
+}
+
+func TestTokenBucket_Allow(t *testing.T) {
+	// 2 per 200ms = one token every 100ms, burst of 2
+	bucket := utils.NewTokenBucketPer(2, 200*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		allowed, _ := bucket.Allow()
+		assert.True(t, allowed)
+	}
+
+	// Rejected calls don't take a token, so the wait doesn't grow
+	for i := 0; i < 2; i++ {
+		allowed, retryAfter := bucket.Allow()
+		assert.False(t, allowed)
+		assert.InDelta(t, float64(100*time.Millisecond), float64(retryAfter), float64(10*time.Millisecond))
+	}
+	assert.False(t, bucket.Full())
+
+	time.Sleep(110 * time.Millisecond)
+	allowed, _ := bucket.Allow()
+	assert.True(t, allowed)
+}
+
+func TestTokenBucket_WaitPacesCalls(t *testing.T) {
+	// 1200 per minute = one token every 50ms, burst of 2
+	bucket := utils.NewTokenBucket(1200, 2)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		require.NoError(t, bucket.Wait(context.Background()))
+	}
+	elapsed := time.Since(start)
+
+	// Two calls use the burst, the remaining four wait 50ms each
+	assert.GreaterOrEqual(t, elapsed, 180*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestTokenBucket_WaitCancelled(t *testing.T) {
+	bucket := utils.NewTokenBucket(1, 1)
+	require.NoError(t, bucket.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := bucket.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, bucket.Stats().Waiting)
+}
+
+func TestTokenBucket_Unlimited(t *testing.T) {
+	bucket := utils.NewTokenBucket(0, 1)
+	for i := 0; i < 100; i++ {
+		assert.Zero(t, bucket.Reserve())
+	}
+	assert.Zero(t, bucket.Stats().Delayed)
+}
+
+func TestNotificationService_RateLimitsEmail(t *testing.T) {
+	ctx := context.Background()
+	emailService := new(MockEmailService)
+	emailService.On("SendEmail", ctx, mock.Anything).Return(nil)
+
+	cfg := testutils.TestConfig()
+	cfg.Notification.Email.RateLimit.PerMinute = 1200 // one every 50ms
+	cfg.Notification.Email.RateLimit.Burst = 1
+
+	service := services.NewNotificationService(emailService, nil, nil, nil, nil, nil, cfg)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		err := service.SendEmail(ctx, &models.SendEmailRequest{To: []string{"user@example.com"}, Subject: "Hi", Body: "Hello"})
+		require.NoError(t, err)
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 140*time.Millisecond)
+	emailService.AssertNumberOfCalls(t, "SendEmail", 4)
+
+	stats := service.RateLimitStats()
+	assert.Equal(t, 1200, stats[models.NotificationTypeEmail].RatePerMinute)
+	assert.Equal(t, int64(4), stats[models.NotificationTypeEmail].Reserved)
+	assert.Equal(t, int64(3), stats[models.NotificationTypeEmail].Delayed)
+}
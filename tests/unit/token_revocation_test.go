@@ -0,0 +1,146 @@
+package unit
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/middlewares"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRevokedTokenRepository keeps the token blacklist in memory
+type memoryRevokedTokenRepository struct {
+	revoked map[string]time.Time
+}
+
+func newMemoryRevokedTokenRepository() *memoryRevokedTokenRepository {
+	return &memoryRevokedTokenRepository{revoked: map[string]time.Time{}}
+}
+
+func (r *memoryRevokedTokenRepository) RevokeToken(jti string, expiresAt time.Time) error {
+	r.revoked[jti] = expiresAt
+	return nil
+}
+
+func (r *memoryRevokedTokenRepository) IsTokenRevoked(jti string) (bool, error) {
+	_, ok := r.revoked[jti]
+	return ok, nil
+}
+
+func (r *memoryRevokedTokenRepository) CleanupExpiredTokens() error {
+	return nil
+}
+
+// newRevocationAuthService creates an AuthService with an in-memory token blacklist
+func newRevocationAuthService() (*services.AuthService, *authServiceMocks, *memoryRevokedTokenRepository) {
+	authService, m := newMockedAuthService()
+	revokedTokens := newMemoryRevokedTokenRepository()
+	authService.SetRevokedTokenRepository(revokedTokens)
+	return authService, m, revokedTokens
+}
+
+func TestJWTManager_GenerateToken_UniqueTokenID(t *testing.T) {
+	authService, _ := newMockedAuthService()
+	jwtManager := authService.GetJWTManager()
+
+	first, err := jwtManager.GenerateToken(1, "user", "user@example.com", "Test User", false)
+	require.NoError(t, err)
+	second, err := jwtManager.GenerateToken(1, "user", "user@example.com", "Test User", false)
+	require.NoError(t, err)
+
+	firstClaims, err := jwtManager.ValidateToken(first)
+	require.NoError(t, err)
+	secondClaims, err := jwtManager.ValidateToken(second)
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, firstClaims.ID)
+	assert.NotEqual(t, firstClaims.ID, secondClaims.ID)
+	assert.Equal(t, firstClaims.ID, utils.TokenID(first))
+}
+
+func TestAuthMiddleware_RejectsLoggedOutToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService, m, revokedTokens := newRevocationAuthService()
+
+	token, err := authService.GetJWTManager().GenerateToken(1, "user", "user@example.com", "Test User", false)
+	require.NoError(t, err)
+	m.sessionRepo.On("GetSessionByToken", token).Return((*models.UserSession)(nil), nil)
+
+	router := gin.New()
+	router.Use(middlewares.AuthMiddleware(authService))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	request := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The token works until it is logged out
+	assert.Equal(t, http.StatusOK, request().Code)
+
+	require.NoError(t, authService.LogoutSession(token))
+
+	w := request()
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, middlewares.TokenErrorRevoked, response["code"])
+
+	// The blacklist entry outlives the token by the refresh grace period
+	claims, err := authService.GetJWTManager().ValidateToken(token)
+	require.NoError(t, err)
+	assert.True(t, revokedTokens.revoked[claims.ID].Equal(claims.ExpiresAt.Add(authService.GetJWTManager().RefreshGracePeriod())))
+}
+
+func TestAuthService_RefreshToken_RejectsRevokedToken(t *testing.T) {
+	authService, m, _ := newRevocationAuthService()
+
+	token, err := authService.GetJWTManager().GenerateToken(1, "user", "user@example.com", "Test User", false)
+	require.NoError(t, err)
+	m.sessionRepo.On("GetSessionByToken", token).Return((*models.UserSession)(nil), nil)
+	require.NoError(t, authService.LogoutSession(token))
+
+	_, err = authService.RefreshToken(token, false)
+
+	assert.ErrorIs(t, err, utils.ErrTokenRevoked)
+}
+
+func TestRoutes_RejectLoggedOutToken(t *testing.T) {
+	db, fake := testutils.NewFakeDB(t)
+	router := newRoutedTestRouterWithDB(t, db)
+
+	token, err := utils.NewJWTManager(testutils.TestConfig()).GenerateToken(1, "user", "user@example.com", "Test User", false)
+	require.NoError(t, err)
+
+	// Logout has deactivated the session and blacklisted the token
+	now := time.Now()
+	fake.OnQuery("FROM user_sessions", []string{
+		"id", "user_id", "user_type", "session_token", "device_info", "ip_address", "user_agent",
+		"is_active", "last_activity_at", "created_at", "expires_at",
+	}, []driver.Value{int64(1), int64(1), "user", token, nil, nil, nil, false, now, now, now.Add(time.Hour)})
+	fake.OnQuery("FROM revoked_tokens", []string{"revoked"}, []driver.Value{true})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, middlewares.TokenErrorRevoked, response["code"])
+}
@@ -0,0 +1,266 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testTOTPSecret is a base32 secret shared by the TOTP tests
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+// createTOTPAdmin returns an admin with the test TOTP secret, confirmed when enabled is set
+func createTOTPAdmin(enabled bool) *models.Admin {
+	admin := testutils.CreateMockAdmin(1, "admin@example.com", "Admin")
+	secret := testTOTPSecret
+	admin.TOTPSecret = &secret
+	if enabled {
+		enabledAt := time.Now()
+		admin.TOTPEnabledAt = &enabledAt
+	}
+	return admin
+}
+
+func currentTOTPCode(t *testing.T) string {
+	code, err := totp.GenerateCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+// wrongTOTPCode returns a well-formed code that differs from the current one
+func wrongTOTPCode(t *testing.T) string {
+	if currentTOTPCode(t) == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestAuthService_EnableTOTP(t *testing.T) {
+	t.Run("generates and stores an unconfirmed secret", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		admin := testutils.CreateMockAdmin(1, "admin@example.com", "Admin")
+		m.adminRepo.On("GetByID", admin.ID).Return(admin, nil)
+
+		var stored string
+		m.adminRepo.On("SetTOTPSecret", admin.ID, mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { stored = args.String(1) }).
+			Return(nil)
+
+		secret, otpauthURL, err := authService.EnableTOTP(admin.ID)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, secret)
+		assert.Equal(t, secret, stored)
+		assert.True(t, strings.HasPrefix(otpauthURL, "otpauth://totp/"))
+		assert.Contains(t, otpauthURL, "secret="+secret)
+		assert.Contains(t, otpauthURL, "period=30")
+		m.adminRepo.AssertNotCalled(t, "EnableTOTP", mock.Anything)
+	})
+
+	t.Run("refuses when already enabled", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		admin := createTOTPAdmin(true)
+		m.adminRepo.On("GetByID", admin.ID).Return(admin, nil)
+
+		_, _, err := authService.EnableTOTP(admin.ID)
+
+		assert.ErrorIs(t, err, services.ErrTOTPAlreadyEnabled)
+		m.adminRepo.AssertNotCalled(t, "SetTOTPSecret", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_ConfirmTOTP(t *testing.T) {
+	t.Run("enables TOTP with a valid code", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		admin := createTOTPAdmin(false)
+		m.adminRepo.On("GetByID", admin.ID).Return(admin, nil)
+		m.adminRepo.On("ClaimTOTPStep", admin.ID, mock.AnythingOfType("int64")).Return(true, nil)
+		m.adminRepo.On("EnableTOTP", admin.ID).Return(nil)
+
+		err := authService.ConfirmTOTP(admin.ID, currentTOTPCode(t))
+
+		assert.NoError(t, err)
+		m.adminRepo.AssertExpectations(t)
+	})
+
+	t.Run("accepts the previous step's code", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		admin := createTOTPAdmin(false)
+		m.adminRepo.On("GetByID", admin.ID).Return(admin, nil)
+		m.adminRepo.On("EnableTOTP", admin.ID).Return(nil)
+
+		now := time.Now()
+		code, err := totp.GenerateCode(testTOTPSecret, now.Add(-30*time.Second))
+		require.NoError(t, err)
+		m.adminRepo.On("ClaimTOTPStep", admin.ID, now.Unix()/30-1).Return(true, nil)
+
+		assert.NoError(t, authService.ConfirmTOTP(admin.ID, code))
+	})
+
+	t.Run("rejects a code that was already used", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		admin := createTOTPAdmin(false)
+		m.adminRepo.On("GetByID", admin.ID).Return(admin, nil)
+		m.adminRepo.On("ClaimTOTPStep", admin.ID, mock.AnythingOfType("int64")).Return(false, nil)
+
+		err := authService.ConfirmTOTP(admin.ID, currentTOTPCode(t))
+
+		assert.ErrorIs(t, err, services.ErrInvalidTOTPCode)
+		m.adminRepo.AssertNotCalled(t, "EnableTOTP", mock.Anything)
+	})
+
+	t.Run("rejects a wrong code", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		admin := createTOTPAdmin(false)
+		m.adminRepo.On("GetByID", admin.ID).Return(admin, nil)
+
+		err := authService.ConfirmTOTP(admin.ID, wrongTOTPCode(t))
+
+		assert.ErrorIs(t, err, services.ErrInvalidTOTPCode)
+		m.adminRepo.AssertNotCalled(t, "EnableTOTP", mock.Anything)
+	})
+
+	t.Run("requires setup first", func(t *testing.T) {
+		authService, m := newMockedAuthService()
+		admin := testutils.CreateMockAdmin(1, "admin@example.com", "Admin")
+		m.adminRepo.On("GetByID", admin.ID).Return(admin, nil)
+
+		err := authService.ConfirmTOTP(admin.ID, "123456")
+
+		assert.ErrorIs(t, err, services.ErrTOTPNotSetUp)
+	})
+}
+
+// startTOTPLogin logs in as an admin with TOTP enabled and returns the challenge response
+func startTOTPLogin(t *testing.T) (*services.AuthService, *authServiceMocks, *models.Admin, *models.LoginResponse) {
+	authService, m := newMockedAuthService()
+	admin := createTOTPAdmin(true)
+	m.userRepo.On("GetByEmail", admin.Email).Return(nil, repositories.ErrNotFound)
+	m.adminRepo.On("GetByEmail", admin.Email).Return(admin, nil)
+	m.adminRepo.On("GetByID", admin.ID).Return(admin, nil)
+
+	response, err := authService.LoginWithSession(admin.Email, "admin123", true, "", "10.0.0.1", "Firefox", "")
+	require.NoError(t, err)
+	return authService, m, admin, response
+}
+
+func TestAuthService_Login_TOTPRequired(t *testing.T) {
+	t.Run("returns a challenge instead of a token", func(t *testing.T) {
+		authService, m, _, response := startTOTPLogin(t)
+
+		assert.True(t, response.TwoFactorRequired())
+		assert.Equal(t, models.LoginStatusTwoFactorRequired, response.Status)
+		assert.Empty(t, response.Token)
+		assert.Nil(t, response.User)
+		assert.NotEmpty(t, response.ChallengeToken)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), response.ExpiresAt.Time, 5*time.Second)
+		m.adminRepo.AssertNotCalled(t, "UpdateLastLogin", mock.Anything)
+		m.sessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		// The challenge token is no access token
+		_, err := authService.ValidateToken(response.ChallengeToken)
+		assert.Error(t, err)
+	})
+
+	t.Run("exchanges the challenge and a valid code for a session", func(t *testing.T) {
+		authService, m, admin, challenge := startTOTPLogin(t)
+		m.adminRepo.On("UpdateLastLogin", admin.ID).Return(nil)
+		m.permissionService.On("GetUserPermissionsByID", admin.ID, "admin").Return([]string{"users:view"}, nil)
+		m.sessionRepo.On("CreateSession", admin.ID, "admin", mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).
+			Return(&models.UserSession{ID: 1}, nil)
+		m.loginAuditRepo.On("RecordSuccessfulLogin", admin.ID, "admin", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		m.adminRepo.On("ClaimTOTPStep", admin.ID, mock.AnythingOfType("int64")).Return(true, nil)
+
+		response, err := authService.VerifyTOTP(challenge.ChallengeToken, currentTOTPCode(t), "", "10.0.0.1", "Firefox", "")
+
+		require.NoError(t, err)
+		assert.False(t, response.TwoFactorRequired())
+		assert.Equal(t, "admin", response.UserType)
+		assert.Equal(t, []string{"users:view"}, response.Permissions)
+		claims, err := authService.ValidateToken(response.Token)
+		require.NoError(t, err)
+		assert.Equal(t, admin.ID, claims.UserID)
+		// remember_me from the first step does not stretch an admin token
+		assert.WithinDuration(t, time.Now().Add(time.Hour), response.ExpiresAt.Time, 5*time.Second)
+		m.sessionRepo.AssertExpectations(t)
+		m.loginAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a wrong code and records the failure", func(t *testing.T) {
+		authService, m, admin, challenge := startTOTPLogin(t)
+		m.loginAuditRepo.On("RecordFailedLogin", admin.Email, mock.Anything, mock.Anything, mock.Anything, models.LoginFailureInvalidTOTP).Return(nil)
+
+		response, err := authService.VerifyTOTP(challenge.ChallengeToken, wrongTOTPCode(t), "", "10.0.0.1", "Firefox", "")
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, services.ErrInvalidTOTPCode)
+		m.loginAuditRepo.AssertExpectations(t)
+		m.sessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a replayed code", func(t *testing.T) {
+		authService, m, admin, challenge := startTOTPLogin(t)
+		m.adminRepo.On("ClaimTOTPStep", admin.ID, mock.AnythingOfType("int64")).Return(false, nil)
+		m.loginAuditRepo.On("RecordFailedLogin", admin.Email, mock.Anything, mock.Anything, mock.Anything, models.LoginFailureInvalidTOTP).Return(nil)
+
+		response, err := authService.VerifyTOTP(challenge.ChallengeToken, currentTOTPCode(t), "", "10.0.0.1", "Firefox", "")
+
+		assert.Nil(t, response)
+		assert.ErrorIs(t, err, services.ErrInvalidTOTPCode)
+		m.sessionRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an access token as the challenge", func(t *testing.T) {
+		authService, _, admin, _ := startTOTPLogin(t)
+		accessToken, err := authService.GetJWTManager().GenerateToken(admin.ID, "admin", admin.Email, admin.Name, false)
+		require.NoError(t, err)
+
+		_, err = authService.VerifyTOTP(accessToken, currentTOTPCode(t), "", "", "", "")
+
+		assert.ErrorIs(t, err, services.ErrInvalidTOTPChallenge)
+	})
+
+	t.Run("limits wrong codes", func(t *testing.T) {
+		authService, m, admin, challenge := startTOTPLogin(t)
+		m.loginAuditRepo.On("RecordFailedLogin", admin.Email, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		for i := 0; i < 5; i++ {
+			_, err := authService.VerifyTOTP(challenge.ChallengeToken, wrongTOTPCode(t), "", "", "", "")
+			assert.ErrorIs(t, err, services.ErrInvalidTOTPCode)
+		}
+
+		_, err := authService.VerifyTOTP(challenge.ChallengeToken, currentTOTPCode(t), "", "", "", "")
+
+		assert.ErrorIs(t, err, services.ErrTooManyTOTPAttempts)
+	})
+}
+
+func TestAdminRepository_ClaimTOTPStep(t *testing.T) {
+	t.Run("claims a later step", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnExec("UPDATE admins SET totp_last_step", 1)
+
+		claimed, err := repositories.NewAdminRepository(db).ClaimTOTPStep(1, 100)
+
+		require.NoError(t, err)
+		assert.True(t, claimed)
+	})
+
+	t.Run("refuses a step that was already accepted", func(t *testing.T) {
+		db, _ := testutils.NewFakeDB(t)
+
+		claimed, err := repositories.NewAdminRepository(db).ClaimTOTPStep(1, 100)
+
+		require.NoError(t, err)
+		assert.False(t, claimed)
+	})
+}
@@ -0,0 +1,115 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newImportUserService returns a user service whose repository knows no users and
+// accepts every create
+func newImportUserService() (services.UserServiceInterface, *MockUserRepository) {
+	mockRepo := new(MockUserRepository)
+	mockPermissionRepo := new(MockPermissionRepository)
+	mockRepo.On("GetByEmailIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+	mockRepo.On("GetByMobileIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+	mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(nil)
+	mockPermissionRepo.On("AssignRoleToUser", mock.AnythingOfType("int"), "user", "user").Return(nil)
+	return services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil), mockRepo
+}
+
+func TestUserService_ImportCSV(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates every line of a well-formed file", func(t *testing.T) {
+		userService, mockRepo := newImportUserService()
+		file := "name,mobile,email\n" +
+			"First Member,09120000001,first@example.com\n" +
+			"Second Member,+989120000002,second@example.com\n"
+
+		result, err := userService.ImportCSV(ctx, strings.NewReader(file), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		assert.Empty(t, result.Skipped)
+		assert.Empty(t, result.Errored)
+		mockRepo.AssertCalled(t, "Create", mock.MatchedBy(func(user *models.User) bool {
+			return user.Email == "second@example.com" && user.Mobile == "09120000002"
+		}))
+	})
+
+	t.Run("reports a bad email with its line number", func(t *testing.T) {
+		userService, mockRepo := newImportUserService()
+		file := "email,name,mobile\n" +
+			"first@example.com,First Member,09120000001\n" +
+			"not-an-email,Second Member,09120000002\n" +
+			"third@example.com,Third Member,09120000003\n"
+
+		result, err := userService.ImportCSV(ctx, strings.NewReader(file), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, []models.UserImportLine{{Line: 3, Reason: "invalid email address"}}, result.Errored)
+		mockRepo.AssertNumberOfCalls(t, "Create", 2)
+	})
+
+	t.Run("skips existing users", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		mockRepo.On("GetByEmailIncludingDeleted", "first@example.com").Return(&models.User{ID: 1}, nil)
+
+		result, err := userService.ImportCSV(ctx, strings.NewReader("name,mobile,email\nFirst Member,09120000001,first@example.com\n"), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, []models.UserImportLine{{Line: 2, Reason: "user with this email already exists"}}, result.Skipped)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("rejects a header without the required columns", func(t *testing.T) {
+		userService, _ := newImportUserService()
+
+		_, err := userService.ImportCSV(ctx, strings.NewReader("name,email\nFirst Member,first@example.com\n"), nil)
+
+		assert.ErrorIs(t, err, services.ErrInvalidImportFile)
+	})
+}
+
+func TestUserHandler_ExportUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockUserRepository)
+	lastLogin := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	mockRepo.On("GetAllByGamenet", 7).Return([]models.User{
+		{ID: 3, Name: "=HYPERLINK()", Mobile: "09120000001", Email: "first@example.com", Balance: 12.5, LastLoginAt: &lastLogin, CreatedAt: lastLogin},
+	}, nil)
+	handler := handlers.NewUserHandler(services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/export", nil)
+	c.Set("user_type", "gamenet")
+	c.Set("user_id", 7)
+
+	handler.ExportUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Disposition"), "attachment; filename=\"users-"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "id,name,mobile,email,balance,debt,last_login_at,created_at", lines[0])
+	assert.Equal(t, "3,'=HYPERLINK(),09120000001,first@example.com,12.5,0,2024-05-01T10:00:00Z,2024-05-01T10:00:00Z", lines[1])
+	mockRepo.AssertNotCalled(t, "GetAll")
+}
@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserHandler_GetAllUsers_Filters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	getUsers := func(repo *MockUserRepository, query string) *httptest.ResponseRecorder {
+		handler := handlers.NewUserHandler(services.NewUserService(repo, new(MockPermissionRepository), nil, nil, nil))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/users/?"+query, nil)
+		c.Set("user_type", "admin")
+		c.Set("user_id", 1)
+
+		handler.GetAllUsers(c)
+		return w
+	}
+
+	t.Run("passes the filters to the search", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		var got *models.UserSearchRequest
+		repo.On("Search", mock.AnythingOfType("*models.UserSearchRequest")).
+			Run(func(args mock.Arguments) { got = args.Get(0).(*models.UserSearchRequest) }).
+			Return(&models.UserSearchResponse{}, nil)
+
+		w := getUsers(repo, "min_balance=10&max_balance=99.5&min_debt=1&has_debt=true&last_login_before=2024-01-31")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		if assert.NotNil(t, got) {
+			assert.Equal(t, 10.0, *got.MinBalance)
+			assert.Equal(t, 99.5, *got.MaxBalance)
+			assert.Equal(t, 1.0, *got.MinDebt)
+			assert.True(t, *got.HasDebt)
+			assert.True(t, got.LastLoginBefore.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)))
+		}
+	})
+
+	t.Run("leaves unset filters empty", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("Search", &models.UserSearchRequest{Page: 1, PageSize: 10}).Return(&models.UserSearchResponse{}, nil)
+
+		w := getUsers(repo, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects malformed filters", func(t *testing.T) {
+		for _, query := range []string{"min_balance=lots", "has_debt=maybe", "last_login_before=yesterday"} {
+			repo := new(MockUserRepository)
+
+			w := getUsers(repo, query)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			repo.AssertNotCalled(t, "Search", mock.Anything)
+		}
+	})
+}
+
+func TestUserHandler_CreateUsersBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	post := func(repo *MockUserRepository, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		handler := handlers.NewUserHandler(services.NewUserService(repo, new(MockPermissionRepository), nil, nil, nil))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/users/batch", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user_type", "admin")
+		c.Set("user_id", 1)
+
+		handler.CreateUsersBatch(c)
+
+		var response map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	t.Run("reports invalid rows without creating any", func(t *testing.T) {
+		repo := new(MockUserRepository)
+
+		w, response := post(repo, `[
+			{"name": "First Member", "email": "first@example.com", "mobile": "09120000001"},
+			{"name": "Second Member", "email": "not-an-email", "mobile": "09120000002"}
+		]`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		results := response["data"].(map[string]interface{})["results"].([]interface{})
+		assert.Len(t, results, 2)
+		assert.Equal(t, "not created because another row failed", results[0].(map[string]interface{})["error"])
+		assert.Contains(t, results[1].(map[string]interface{})["error"], "Email")
+		repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports a failed lookup as a server error", func(t *testing.T) {
+		repo := new(MockUserRepository)
+		repo.On("GetByEmailIncludingDeleted", mock.Anything).Return(nil, errors.New("database unavailable"))
+
+		w, response := post(repo, `[{"name": "First Member", "email": "first@example.com", "mobile": "09120000001"}]`)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, "Failed to create users", response["error"])
+		repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an empty batch", func(t *testing.T) {
+		repo := new(MockUserRepository)
+
+		w, _ := post(repo, `[]`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+}
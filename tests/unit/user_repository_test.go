@@ -1,8 +1,12 @@
 package unit
 
 import (
+	"database/sql/driver"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/gatehide/gatehide-api/internal/models"
 	"github.com/gatehide/gatehide-api/internal/repositories"
 	testutils "github.com/gatehide/gatehide-api/tests/utils"
 )
@@ -110,6 +114,184 @@ func TestUserRepository_UpdateLastLogin(t *testing.T) {
 	}
 }
 
+func TestUserRepository_GetRecentlyActive(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	userRepo := repositories.NewUserRepository(db)
+	since := time.Now().Add(-time.Hour)
+
+	// No user has logged in yet
+	users, err := userRepo.GetRecentlyActive(since, 10)
+	if err != nil {
+		t.Fatalf("UserRepository.GetRecentlyActive() error = %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("UserRepository.GetRecentlyActive() returned %d users, want 0", len(users))
+	}
+
+	active := testutils.CreateTestUser(t, db, "recent1@example.com", "password123", "Recent User")
+	testutils.CreateTestUser(t, db, "recent2@example.com", "password123", "Inactive User")
+	if err := userRepo.UpdateLastLogin(active.ID); err != nil {
+		t.Fatalf("UserRepository.UpdateLastLogin() error = %v", err)
+	}
+
+	users, err = userRepo.GetRecentlyActive(since, 10)
+	if err != nil {
+		t.Fatalf("UserRepository.GetRecentlyActive() error = %v", err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("UserRepository.GetRecentlyActive() returned %d users, want 1", len(users))
+	}
+	if users[0].ID != active.ID || users[0].Name != active.Name {
+		t.Errorf("UserRepository.GetRecentlyActive() = %+v, want user %d", users[0], active.ID)
+	}
+}
+
+func TestUserRepository_SearchFilters(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	userRepo := repositories.NewUserRepository(db)
+
+	// Seed users with distinct balances, debts and last logins
+	seed := func(email string, balance, debt float64, lastLogin *time.Time) int {
+		user := testutils.CreateTestUser(t, db, email, "password123", "Filtered User")
+		if _, err := db.Exec("UPDATE users SET balance = ?, debt = ?, last_login_at = ? WHERE id = ?", balance, debt, lastLogin, user.ID); err != nil {
+			t.Fatalf("Failed to seed user %s: %v", email, err)
+		}
+		return user.ID
+	}
+	recent := time.Now().Add(-24 * time.Hour)
+	stale := time.Now().Add(-60 * 24 * time.Hour)
+	rich := seed("filter-rich@example.com", 500, 0, &recent)
+	indebted := seed("filter-debt@example.com", 0, 120, &stale)
+	smallDebt := seed("filter-small-debt@example.com", 50, 10, &recent)
+	neverLoggedIn := seed("filter-never@example.com", 20, 0, nil)
+
+	floatPtr := func(v float64) *float64 { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+	cutoff := time.Now().Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name string
+		req  models.UserSearchRequest
+		want []int
+	}{
+		{"no filters", models.UserSearchRequest{Query: "filter-"}, []int{rich, indebted, smallDebt, neverLoggedIn}},
+		{"min balance", models.UserSearchRequest{Query: "filter-", MinBalance: floatPtr(50)}, []int{rich, smallDebt}},
+		{"max balance", models.UserSearchRequest{Query: "filter-", MaxBalance: floatPtr(20)}, []int{indebted, neverLoggedIn}},
+		{"balance range", models.UserSearchRequest{Query: "filter-", MinBalance: floatPtr(10), MaxBalance: floatPtr(100)}, []int{smallDebt, neverLoggedIn}},
+		{"min debt", models.UserSearchRequest{Query: "filter-", MinDebt: floatPtr(100)}, []int{indebted}},
+		{"has debt", models.UserSearchRequest{Query: "filter-", HasDebt: boolPtr(true)}, []int{indebted, smallDebt}},
+		{"has no debt", models.UserSearchRequest{Query: "filter-", HasDebt: boolPtr(false)}, []int{rich, neverLoggedIn}},
+		{"not logged in for 30 days", models.UserSearchRequest{Query: "filter-", LastLoginBefore: &cutoff}, []int{indebted, neverLoggedIn}},
+		{"combined", models.UserSearchRequest{Query: "filter-", HasDebt: boolPtr(true), LastLoginBefore: &cutoff}, []int{indebted}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			result, err := userRepo.Search(&req)
+			if err != nil {
+				t.Fatalf("UserRepository.Search() error = %v", err)
+			}
+
+			if result.Pagination.TotalItems != int64(len(tt.want)) {
+				t.Errorf("UserRepository.Search() total = %d, want %d", result.Pagination.TotalItems, len(tt.want))
+			}
+			var got []int
+			for _, user := range result.Data {
+				got = append(got, user.ID)
+			}
+			if !sameIDs(got, tt.want) {
+				t.Errorf("UserRepository.Search() returned users %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserRepository_SoftDelete(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	userRepo := repositories.NewUserRepository(db)
+	kept := testutils.CreateTestUser(t, db, "softdelete-kept@example.com", "password123", "Kept User")
+	deleted := testutils.CreateTestUser(t, db, "softdelete-gone@example.com", "password123", "Deleted User")
+
+	if err := userRepo.Delete(deleted.ID); err != nil {
+		t.Fatalf("UserRepository.Delete() error = %v", err)
+	}
+
+	// The deleted user disappears from lookups and listings
+	if _, err := userRepo.GetByID(deleted.ID); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("UserRepository.GetByID() error = %v, want ErrNotFound", err)
+	}
+	if _, err := userRepo.GetByEmail(deleted.Email); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("UserRepository.GetByEmail() error = %v, want ErrNotFound", err)
+	}
+	result, err := userRepo.Search(&models.UserSearchRequest{Query: "softdelete-"})
+	if err != nil {
+		t.Fatalf("UserRepository.Search() error = %v", err)
+	}
+	if len(result.Data) != 1 || result.Data[0].ID != kept.ID {
+		t.Errorf("UserRepository.Search() returned %+v, want only user %d", result.Data, kept.ID)
+	}
+
+	// The row is kept and can still be read explicitly
+	user, err := userRepo.GetByIDIncludingDeleted(deleted.ID)
+	if err != nil {
+		t.Fatalf("UserRepository.GetByIDIncludingDeleted() error = %v", err)
+	}
+	if !user.IsDeleted() {
+		t.Error("UserRepository.GetByIDIncludingDeleted() returned a user that is not deleted")
+	}
+	if err := userRepo.Delete(deleted.ID); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("UserRepository.Delete() twice error = %v, want ErrNotFound", err)
+	}
+
+	if err := userRepo.Restore(deleted.ID); err != nil {
+		t.Fatalf("UserRepository.Restore() error = %v", err)
+	}
+	user, err = userRepo.GetByID(deleted.ID)
+	if err != nil {
+		t.Fatalf("UserRepository.GetByID() after restore error = %v", err)
+	}
+	if user.IsDeleted() {
+		t.Error("UserRepository.GetByID() after restore returned a deleted user")
+	}
+	if err := userRepo.Restore(kept.ID); !errors.Is(err, repositories.ErrNotFound) {
+		t.Errorf("UserRepository.Restore() of a live user error = %v, want ErrNotFound", err)
+	}
+}
+
+// sameIDs reports whether two ID lists hold the same IDs in any order
+func sameIDs(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[int]int)
+	for _, id := range got {
+		seen[id]++
+	}
+	for _, id := range want {
+		if seen[id] == 0 {
+			return false
+		}
+		seen[id]--
+	}
+	return true
+}
+
 func TestAdminRepository_GetByEmail(t *testing.T) {
 	testutils.SkipIfNoDB(t)
 
@@ -286,3 +468,103 @@ func TestAdminRepository_EmailUniqueness(t *testing.T) {
 		t.Error("Admins with different emails should have different IDs")
 	}
 }
+
+func TestUserRepository_DeleteSignsOutSessions(t *testing.T) {
+	db, fake := testutils.NewFakeDB(t)
+	fake.OnExec("UPDATE users SET deleted_at", 1)
+	userRepo := repositories.NewUserRepository(db)
+
+	if err := userRepo.Delete(7); err != nil {
+		t.Fatalf("UserRepository.Delete() error = %v", err)
+	}
+
+	if !fake.Ran("UPDATE user_sessions SET is_active = FALSE") {
+		t.Error("UserRepository.Delete() did not deactivate the user's sessions")
+	}
+}
+
+func TestUserRepository_ApplyBulkActionIsolatesEachUser(t *testing.T) {
+	t.Run("keeps each successful user's changes", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnQuery("SELECT suspended_at FROM users", []string{"suspended_at"}, []driver.Value{nil})
+		userRepo := repositories.NewUserRepository(db)
+
+		results, err := userRepo.ApplyBulkAction(models.UserBulkActionSuspend, []int{1, 2})
+		if err != nil {
+			t.Fatalf("UserRepository.ApplyBulkAction() error = %v", err)
+		}
+
+		for _, result := range results {
+			if !result.Success {
+				t.Errorf("UserRepository.ApplyBulkAction() user %d failed: %s", result.UserID, result.Error)
+			}
+		}
+		if fake.Ran("ROLLBACK TO SAVEPOINT") {
+			t.Error("UserRepository.ApplyBulkAction() rolled back a successful user")
+		}
+		if !fake.Ran("RELEASE SAVEPOINT bulk_user_action") {
+			t.Error("UserRepository.ApplyBulkAction() did not release the savepoint")
+		}
+	})
+
+	t.Run("rolls back a failed user's partial changes", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnQuery("SELECT suspended_at FROM users", []string{"suspended_at"}, []driver.Value{time.Now()})
+		userRepo := repositories.NewUserRepository(db)
+
+		results, err := userRepo.ApplyBulkAction(models.UserBulkActionSuspend, []int{1})
+		if err != nil {
+			t.Fatalf("UserRepository.ApplyBulkAction() error = %v", err)
+		}
+
+		if len(results) != 1 || results[0].Success {
+			t.Fatalf("UserRepository.ApplyBulkAction() results = %+v, want one failure", results)
+		}
+		if !fake.Ran("ROLLBACK TO SAVEPOINT bulk_user_action") {
+			t.Error("UserRepository.ApplyBulkAction() did not roll back the failed user")
+		}
+	})
+}
+
+func TestUserRepository_CreateBatchSetsUpUsersInTransaction(t *testing.T) {
+	newUsers := func() []*models.User {
+		return []*models.User{
+			{Name: "First Member", Email: "first@example.com", Mobile: "09120000001"},
+			{Name: "Second Member", Email: "second@example.com", Mobile: "09120000002"},
+		}
+	}
+
+	t.Run("assigns the role and links the gamenet", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		fake.OnExec("INSERT INTO user_roles", 1)
+		userRepo := repositories.NewUserRepository(db)
+		gamenetID := 7
+
+		if err := userRepo.CreateBatch(newUsers(), &gamenetID); err != nil {
+			t.Fatalf("UserRepository.CreateBatch() error = %v", err)
+		}
+
+		if !fake.Ran("INSERT INTO users_gamenets") {
+			t.Error("UserRepository.CreateBatch() did not link the users to the gamenet")
+		}
+	})
+
+	t.Run("fails the batch when the role is missing", func(t *testing.T) {
+		db, fake := testutils.NewFakeDB(t)
+		userRepo := repositories.NewUserRepository(db)
+		gamenetID := 7
+
+		err := userRepo.CreateBatch(newUsers(), &gamenetID)
+
+		var rowErr *repositories.BatchRowError
+		if !errors.As(err, &rowErr) || rowErr.Index != 0 {
+			t.Fatalf("UserRepository.CreateBatch() error = %v, want a BatchRowError for row 0", err)
+		}
+		if !errors.Is(err, repositories.ErrNotFound) {
+			t.Errorf("UserRepository.CreateBatch() error = %v, want ErrNotFound", err)
+		}
+		if fake.Ran("INSERT INTO users_gamenets") {
+			t.Error("UserRepository.CreateBatch() linked a user after its role failed")
+		}
+	})
+}
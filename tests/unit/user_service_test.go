@@ -3,10 +3,16 @@ package unit
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/gatehide/gatehide-api/config"
 	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
 	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -40,6 +46,30 @@ func (m *MockUserRepository) GetByID(id int) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByIDIncludingDeleted(id int) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmailIncludingDeleted(email string) (*models.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByMobileIncludingDeleted(mobile string) (*models.User, error) {
+	args := m.Called(mobile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
 func (m *MockUserRepository) GetByEmail(email string) (*models.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
@@ -71,6 +101,11 @@ func (m *MockUserRepository) Delete(id int) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) Restore(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) Search(req *models.UserSearchRequest) (*models.UserSearchResponse, error) {
 	args := m.Called(req)
 	if args.Get(0) == nil {
@@ -97,6 +132,11 @@ func (m *MockUserRepository) UnlinkFromGamenet(userID, gamenetID int) error {
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) IsLinkedToGamenet(userID, gamenetID int) (bool, error) {
+	args := m.Called(userID, gamenetID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepository) GetGamenetIDByUser(userID int) (*int, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
@@ -105,6 +145,27 @@ func (m *MockUserRepository) GetGamenetIDByUser(userID int) (*int, error) {
 	return args.Get(0).(*int), args.Error(1)
 }
 
+func (m *MockUserRepository) GetRecentlyActive(since time.Time, limit int) ([]models.RecentlyActiveUser, error) {
+	args := m.Called(since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.RecentlyActiveUser), args.Error(1)
+}
+
+func (m *MockUserRepository) ApplyBulkAction(action string, ids []int) ([]models.UserBulkActionResult, error) {
+	args := m.Called(action, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UserBulkActionResult), args.Error(1)
+}
+
+func (m *MockUserRepository) CreateBatch(users []*models.User, gamenetID *int) error {
+	args := m.Called(users, gamenetID)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) UpdateLastLogin(id int) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -143,6 +204,11 @@ func (m *MockPermissionRepository) HasPermission(roleType, resource, action stri
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockPermissionRepository) HasUserPermission(userID int, userType, resource, action string) (bool, error) {
+	args := m.Called(userID, userType, resource, action)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockPermissionRepository) GetRoleWithPermissions(roleType string) (*models.RoleWithPermissions, error) {
 	args := m.Called(roleType)
 	if args.Get(0) == nil {
@@ -206,7 +272,7 @@ func (m *MockPermissionRepository) HasUserRole(userID int, userType string, role
 	return args.Bool(0), args.Error(1)
 }
 
-// MockSMSService is a mock implementation of SMSService
+// MockSMSService is a mock implementation of SMSServiceInterface
 type MockSMSService struct {
 	mock.Mock
 }
@@ -216,18 +282,62 @@ func (m *MockSMSService) SendSMS(ctx context.Context, sms *models.SMSNotificatio
 	return args.Error(0)
 }
 
+func (m *MockSMSService) SendBulkSMS(ctx context.Context, smsMessages []*models.SMSNotification) error {
+	args := m.Called(ctx, smsMessages)
+	return args.Error(0)
+}
+
+func (m *MockSMSService) SendBulkSMSWithResults(ctx context.Context, smsMessages []*models.SMSNotification) ([]models.BulkSMSResult, error) {
+	args := m.Called(ctx, smsMessages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.BulkSMSResult), args.Error(1)
+}
+
+func (m *MockSMSService) ValidatePhoneNumber(phone string) bool {
+	args := m.Called(phone)
+	return args.Bool(0)
+}
+
+func (m *MockSMSService) TestConnection(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockSMSService) SendTemplate(ctx context.Context, mobile, templateName string, tokens map[string]string) error {
+	args := m.Called(ctx, mobile, templateName, tokens)
+	return args.Error(0)
+}
+
 func (m *MockSMSService) SendUserCredentials(ctx context.Context, mobile, email, password string) error {
 	args := m.Called(ctx, mobile, email, password)
 	return args.Error(0)
 }
 
+func (m *MockSMSService) SendGamenetCredentials(ctx context.Context, mobile, email, password string) error {
+	args := m.Called(ctx, mobile, email, password)
+	return args.Error(0)
+}
+
+func (m *MockSMSService) GetDeliveryStatus(ctx context.Context, messageID int) (*models.SMSDeliveryStatus, error) {
+	args := m.Called(ctx, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SMSDeliveryStatus), args.Error(1)
+}
+
+// Ensure MockSMSService satisfies SMSServiceInterface
+var _ services.SMSServiceInterface = (*MockSMSService)(nil)
+
 func TestUserService_Create(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		req := &models.UserCreateRequest{
 			Name:   "Test User",
@@ -236,9 +346,9 @@ func TestUserService_Create(t *testing.T) {
 		}
 
 		// Mock GetByEmail to return not found
-		mockRepo.On("GetByEmail", req.Email).Return(nil, errors.New("user not found"))
+		mockRepo.On("GetByEmailIncludingDeleted", req.Email).Return(nil, repositories.ErrNotFound)
 		// Mock GetByMobile to return not found
-		mockRepo.On("GetByMobile", req.Mobile).Return(nil, errors.New("user not found"))
+		mockRepo.On("GetByMobileIncludingDeleted", req.Mobile).Return(nil, repositories.ErrNotFound)
 		// Mock Create to succeed
 		mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(nil)
 		// Mock AssignRoleToUser to succeed
@@ -258,7 +368,7 @@ func TestUserService_Create(t *testing.T) {
 	t.Run("Email Already Exists", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		req := &models.UserCreateRequest{
 			Name:   "Test User",
@@ -272,7 +382,7 @@ func TestUserService_Create(t *testing.T) {
 		}
 
 		// Mock GetByEmail to return existing user
-		mockRepo.On("GetByEmail", req.Email).Return(existingUser, nil)
+		mockRepo.On("GetByEmailIncludingDeleted", req.Email).Return(existingUser, nil)
 
 		user, err := userService.Create(ctx, req, nil)
 
@@ -283,10 +393,25 @@ func TestUserService_Create(t *testing.T) {
 		mockPermissionRepo.AssertExpectations(t)
 	})
 
+	t.Run("Email of a deleted user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		deletedAt := time.Now().Add(-time.Hour)
+		req := &models.UserCreateRequest{Name: "Test User", Email: "gone@example.com", Mobile: "09123456789"}
+		mockRepo.On("GetByEmailIncludingDeleted", req.Email).Return(&models.User{ID: 1, Email: req.Email, DeletedAt: &deletedAt}, nil)
+
+		user, err := userService.Create(ctx, req, nil)
+
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, services.ErrIdentifierBelongsToDeletedUser)
+		assert.Equal(t, "email belongs to a deleted user; restore it", err.Error())
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
 	t.Run("Mobile Already Exists", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		req := &models.UserCreateRequest{
 			Name:   "Test User",
@@ -300,9 +425,9 @@ func TestUserService_Create(t *testing.T) {
 		}
 
 		// Mock GetByEmail to return not found
-		mockRepo.On("GetByEmail", req.Email).Return(nil, errors.New("user not found"))
+		mockRepo.On("GetByEmailIncludingDeleted", req.Email).Return(nil, repositories.ErrNotFound)
 		// Mock GetByMobile to return existing user
-		mockRepo.On("GetByMobile", req.Mobile).Return(existingUser, nil)
+		mockRepo.On("GetByMobileIncludingDeleted", req.Mobile).Return(existingUser, nil)
 
 		user, err := userService.Create(ctx, req, nil)
 
@@ -312,6 +437,85 @@ func TestUserService_Create(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 		mockPermissionRepo.AssertExpectations(t)
 	})
+
+	t.Run("Sends Credentials SMS", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockSMS := new(MockSMSService)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, mockSMS, nil, nil)
+
+		req := &models.UserCreateRequest{
+			Name:   "Test User",
+			Email:  "test@example.com",
+			Mobile: "09123456789",
+		}
+
+		mockRepo.On("GetByEmailIncludingDeleted", req.Email).Return(nil, repositories.ErrNotFound)
+		mockRepo.On("GetByMobileIncludingDeleted", req.Mobile).Return(nil, repositories.ErrNotFound)
+		mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(nil)
+		mockPermissionRepo.On("AssignRoleToUser", mock.AnythingOfType("int"), "user", "user").Return(nil)
+		mockSMS.On("SendUserCredentials", ctx, req.Mobile, req.Email, mock.MatchedBy(func(password string) bool {
+			return len(password) == 8
+		})).Return(nil)
+
+		user, err := userService.Create(ctx, req, nil)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, user)
+		mockSMS.AssertExpectations(t)
+	})
+
+	t.Run("SMS Failure Does Not Fail Creation", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		mockSMS := new(MockSMSService)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, mockSMS, nil, nil)
+
+		req := &models.UserCreateRequest{
+			Name:   "Test User",
+			Email:  "test@example.com",
+			Mobile: "09123456789",
+		}
+
+		mockRepo.On("GetByEmailIncludingDeleted", req.Email).Return(nil, repositories.ErrNotFound)
+		mockRepo.On("GetByMobileIncludingDeleted", req.Mobile).Return(nil, repositories.ErrNotFound)
+		mockRepo.On("Create", mock.AnythingOfType("*models.User")).Return(nil)
+		mockPermissionRepo.On("AssignRoleToUser", mock.AnythingOfType("int"), "user", "user").Return(nil)
+		mockSMS.On("SendUserCredentials", ctx, req.Mobile, req.Email, mock.AnythingOfType("string")).Return(errors.New("provider down"))
+
+		user, err := userService.Create(ctx, req, nil)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, user)
+		mockSMS.AssertExpectations(t)
+	})
+
+	t.Run("Disposable Email Rejected For Gamenet", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "domains.txt")
+		assert.NoError(t, os.WriteFile(path, []byte("mailinator.com\n"), 0644))
+		emailValidator := utils.NewEmailDomainValidator(&config.EmailPolicyConfig{
+			BlockDisposable:       true,
+			DisposableDomainsFile: path,
+		})
+
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, emailValidator)
+
+		req := &models.UserCreateRequest{
+			Name:   "Test User",
+			Email:  "test@mailinator.com",
+			Mobile: "09123456789",
+		}
+		gamenetID := 1
+
+		user, err := userService.Create(ctx, req, &gamenetID)
+
+		assert.ErrorIs(t, err, utils.ErrDisposableEmail)
+		assert.Nil(t, user)
+		mockRepo.AssertExpectations(t)
+		mockPermissionRepo.AssertExpectations(t)
+	})
 }
 
 func TestUserService_GetByID(t *testing.T) {
@@ -320,7 +524,7 @@ func TestUserService_GetByID(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		expectedUser := &models.User{
 			ID:     1,
@@ -344,9 +548,9 @@ func TestUserService_GetByID(t *testing.T) {
 	t.Run("Not Found", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
-		mockRepo.On("GetByID", 999).Return(nil, errors.New("user not found"))
+		mockRepo.On("GetByID", 999).Return(nil, repositories.ErrNotFound)
 
 		user, err := userService.GetByID(ctx, 999)
 
@@ -363,7 +567,7 @@ func TestUserService_Update(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		existingUser := &models.User{
 			ID:     1,
@@ -401,14 +605,14 @@ func TestUserService_Update(t *testing.T) {
 	t.Run("User Not Found", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		newName := "New Name"
 		req := &models.UserUpdateRequest{
 			Name: &newName,
 		}
 
-		mockRepo.On("GetByID", 999).Return(nil, errors.New("user not found"))
+		mockRepo.On("GetByID", 999).Return(nil, repositories.ErrNotFound)
 
 		user, err := userService.Update(ctx, 999, req)
 
@@ -418,6 +622,21 @@ func TestUserService_Update(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 		mockPermissionRepo.AssertExpectations(t)
 	})
+	t.Run("Mobile of a deleted user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		deletedAt := time.Now().Add(-time.Hour)
+		mobile := "09120000002"
+		req := &models.UserUpdateRequest{Mobile: &mobile}
+		mockRepo.On("GetByID", 1).Return(&models.User{ID: 1}, nil)
+		mockRepo.On("GetByMobileIncludingDeleted", mobile).Return(&models.User{ID: 2, Mobile: mobile, DeletedAt: &deletedAt}, nil)
+
+		user, err := userService.Update(ctx, 1, req)
+
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, services.ErrIdentifierBelongsToDeletedUser)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
 }
 
 func TestUserService_Delete(t *testing.T) {
@@ -426,7 +645,7 @@ func TestUserService_Delete(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		existingUser := &models.User{
 			ID:   1,
@@ -446,9 +665,9 @@ func TestUserService_Delete(t *testing.T) {
 	t.Run("User Not Found", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
-		mockRepo.On("GetByID", 999).Return(nil, errors.New("user not found"))
+		mockRepo.On("GetByID", 999).Return(nil, repositories.ErrNotFound)
 
 		err := userService.Delete(ctx, 999)
 
@@ -465,7 +684,7 @@ func TestUserService_GetAll(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		expectedUsers := []models.User{
 			{ID: 1, Name: "User 1", Email: "user1@example.com"},
@@ -486,7 +705,7 @@ func TestUserService_GetAll(t *testing.T) {
 	t.Run("Repository Error", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		mockRepo.On("GetAll").Return(nil, errors.New("database error"))
 
@@ -505,7 +724,7 @@ func TestUserService_Search(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockPermissionRepo := new(MockPermissionRepository)
-		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
 
 		searchReq := &models.UserSearchRequest{
 			Query:    "test",
@@ -538,3 +757,348 @@ func TestUserService_Search(t *testing.T) {
 		mockPermissionRepo.AssertExpectations(t)
 	})
 }
+
+func TestUserService_GetRecentlyActive(t *testing.T) {
+	ctx := context.Background()
+	since := time.Now().Add(-time.Hour)
+
+	t.Run("Empty result", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		mockRepo.On("GetRecentlyActive", since, 10).Return([]models.RecentlyActiveUser{}, nil)
+
+		users, err := userService.GetRecentlyActive(ctx, since, 10)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, users)
+		assert.Empty(t, users)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Populated result", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		expected := []models.RecentlyActiveUser{
+			{ID: 2, Name: "Recent User", LastLoginAt: models.NewTimestamp(time.Now().Add(-time.Minute))},
+			{ID: 1, Name: "Earlier User", LastLoginAt: models.NewTimestamp(time.Now().Add(-30 * time.Minute))},
+		}
+		mockRepo.On("GetRecentlyActive", since, 10).Return(expected, nil)
+
+		users, err := userService.GetRecentlyActive(ctx, since, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, users)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Limit is defaulted and capped", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		mockRepo.On("GetRecentlyActive", since, services.DefaultRecentlyActiveLimit).Return([]models.RecentlyActiveUser{}, nil).Once()
+		mockRepo.On("GetRecentlyActive", since, services.MaxRecentlyActiveLimit).Return([]models.RecentlyActiveUser{}, nil).Once()
+
+		_, err := userService.GetRecentlyActive(ctx, since, 0)
+		assert.NoError(t, err)
+		_, err = userService.GetRecentlyActive(ctx, since, 1000)
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		mockRepo.On("GetRecentlyActive", since, 10).Return(nil, errors.New("database error"))
+
+		users, err := userService.GetRecentlyActive(ctx, since, 10)
+
+		assert.Error(t, err)
+		assert.Nil(t, users)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserService_BulkAction(t *testing.T) {
+	ctx := context.Background()
+	actor := &models.Actor{ID: 1, Type: "admin"}
+
+	t.Run("Mixed success and failure", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		mockRepo.On("ApplyBulkAction", models.UserBulkActionSuspend, []int{1, 2, 3}).Return([]models.UserBulkActionResult{
+			{UserID: 1, Success: true},
+			{UserID: 2, Success: false, Error: "user not found"},
+			{UserID: 3, Success: false, Error: "user is already suspended"},
+		}, nil)
+
+		result, err := userService.BulkAction(ctx, &models.UserBulkActionRequest{
+			UserIDs: []int{1, 2, 3},
+			Action:  models.UserBulkActionSuspend,
+		}, actor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.UserBulkActionSuspend, result.Action)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, 2, result.Failed)
+		assert.Len(t, result.Results, 3)
+		assert.Equal(t, "user not found", result.Results[1].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Duplicate IDs processed once", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		mockRepo.On("ApplyBulkAction", models.UserBulkActionDelete, []int{4, 5}).Return([]models.UserBulkActionResult{
+			{UserID: 4, Success: true},
+			{UserID: 5, Success: true},
+		}, nil)
+
+		result, err := userService.BulkAction(ctx, &models.UserBulkActionRequest{
+			UserIDs: []int{4, 5, 4},
+			Action:  models.UserBulkActionDelete,
+		}, actor)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 0, result.Failed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Batch too large", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		ids := make([]int, services.MaxBulkActionSize+1)
+		for i := range ids {
+			ids[i] = i + 1
+		}
+
+		result, err := userService.BulkAction(ctx, &models.UserBulkActionRequest{
+			UserIDs: ids,
+			Action:  models.UserBulkActionSuspend,
+		}, actor)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "ApplyBulkAction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Repository error", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		mockRepo.On("ApplyBulkAction", models.UserBulkActionReactivate, []int{1}).Return(nil, errors.New("failed to begin transaction"))
+
+		result, err := userService.BulkAction(ctx, &models.UserBulkActionRequest{
+			UserIDs: []int{1},
+			Action:  models.UserBulkActionReactivate,
+		}, actor)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserService_CreateBatch(t *testing.T) {
+	ctx := context.Background()
+	newRows := func() []*models.UserCreateRequest {
+		return []*models.UserCreateRequest{
+			{Name: "First Member", Email: "first@example.com", Mobile: "09120000001"},
+			{Name: "Second Member", Email: "second@example.com", Mobile: "09120000002"},
+		}
+	}
+
+	t.Run("creates every valid row", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockPermissionRepo := new(MockPermissionRepository)
+		userService := services.NewUserService(mockRepo, mockPermissionRepo, nil, nil, nil)
+		rows := newRows()
+		gamenetID := 7
+
+		mockRepo.On("GetByEmailIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+		mockRepo.On("GetByMobileIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+		// The role and the gamenet link are part of the batch transaction
+		mockRepo.On("CreateBatch", mock.MatchedBy(func(users []*models.User) bool { return len(users) == 2 }), &gamenetID).
+			Run(func(args mock.Arguments) {
+				for i, user := range args.Get(0).([]*models.User) {
+					user.ID = 100 + i
+				}
+			}).
+			Return(nil)
+
+		result, err := userService.CreateBatch(ctx, rows, &gamenetID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		for i, row := range result.Results {
+			assert.True(t, row.Success)
+			assert.Empty(t, row.Error)
+			assert.Equal(t, 100+i, row.User.ID)
+			assert.Equal(t, rows[i].Email, row.User.Email)
+		}
+		mockRepo.AssertNotCalled(t, "LinkToGamenet", mock.Anything, mock.Anything)
+		mockPermissionRepo.AssertNotCalled(t, "AssignRoleToUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("one existing email fails the whole batch", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		rows := newRows()
+
+		mockRepo.On("GetByEmailIncludingDeleted", "first@example.com").Return(nil, repositories.ErrNotFound)
+		mockRepo.On("GetByEmailIncludingDeleted", "second@example.com").Return(&models.User{ID: 1, Email: "second@example.com"}, nil)
+		mockRepo.On("GetByMobileIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+
+		result, err := userService.CreateBatch(ctx, rows, nil)
+
+		assert.ErrorIs(t, err, services.ErrBatchCreateFailed)
+		assert.Equal(t, 0, result.Created)
+		assert.False(t, result.Results[0].Success)
+		assert.Equal(t, "not created because another row failed", result.Results[0].Error)
+		assert.Equal(t, "user with this email already exists", result.Results[1].Error)
+		mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("an email repeated within the batch fails the whole batch", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		rows := newRows()
+		rows[1].Email = "FIRST@example.com"
+
+		mockRepo.On("GetByEmailIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+		mockRepo.On("GetByMobileIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+
+		result, err := userService.CreateBatch(ctx, rows, nil)
+
+		assert.ErrorIs(t, err, services.ErrBatchCreateFailed)
+		assert.Equal(t, "email is already used by row 0", result.Results[1].Error)
+		mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a failed insert rolls back the batch", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		mockRepo.On("GetByEmailIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+		mockRepo.On("GetByMobileIncludingDeleted", mock.Anything).Return(nil, repositories.ErrNotFound)
+		mockRepo.On("CreateBatch", mock.Anything, (*int)(nil)).
+			Return(&repositories.BatchRowError{Index: 1, Err: errors.New("Duplicate entry 'second@example.com'")})
+
+		result, err := userService.CreateBatch(ctx, newRows(), nil)
+
+		assert.ErrorIs(t, err, services.ErrBatchCreateFailed)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, "not created because another row failed", result.Results[0].Error)
+		assert.Contains(t, result.Results[1].Error, "Duplicate entry")
+		mockRepo.AssertNotCalled(t, "LinkToGamenet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+
+		result, err := userService.CreateBatch(ctx, []*models.UserCreateRequest{}, nil)
+
+		assert.ErrorIs(t, err, services.ErrInvalidBatchSize)
+		assert.NotErrorIs(t, err, services.ErrBatchCreateFailed)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_Restore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("restores a deleted user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		deletedAt := time.Now()
+		mockRepo.On("GetByIDIncludingDeleted", 1).Return(&models.User{ID: 1, Email: "user@example.com", DeletedAt: &deletedAt}, nil)
+		mockRepo.On("Restore", 1).Return(nil)
+
+		user, err := userService.Restore(ctx, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, user.ID)
+		assert.Nil(t, user.DeletedAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("refuses a user that is not deleted", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		mockRepo.On("GetByIDIncludingDeleted", 1).Return(&models.User{ID: 1}, nil)
+
+		_, err := userService.Restore(ctx, 1)
+
+		assert.ErrorIs(t, err, services.ErrUserNotDeleted)
+		mockRepo.AssertNotCalled(t, "Restore", mock.Anything)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		mockRepo.On("GetByIDIncludingDeleted", 99).Return(nil, repositories.ErrNotFound)
+
+		_, err := userService.Restore(ctx, 99)
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestUserService_GamenetLinks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("attaches an unlinked user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		mockRepo.On("GetByID", 1).Return(&models.User{ID: 1}, nil)
+		mockRepo.On("IsLinkedToGamenet", 1, 4).Return(false, nil)
+		mockRepo.On("LinkToGamenet", 1, 4).Return(nil)
+
+		err := userService.AttachToGamenet(ctx, 1, 4)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("refuses to attach a linked user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		mockRepo.On("GetByID", 1).Return(&models.User{ID: 1}, nil)
+		mockRepo.On("IsLinkedToGamenet", 1, 4).Return(true, nil)
+
+		err := userService.AttachToGamenet(ctx, 1, 4)
+
+		assert.ErrorIs(t, err, services.ErrUserAlreadyLinked)
+		mockRepo.AssertNotCalled(t, "LinkToGamenet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses to detach an unlinked user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		mockRepo.On("GetByID", 1).Return(&models.User{ID: 1}, nil)
+		mockRepo.On("IsLinkedToGamenet", 1, 4).Return(false, nil)
+
+		err := userService.DetachFromGamenet(ctx, 1, 4)
+
+		assert.ErrorIs(t, err, services.ErrUserNotLinked)
+		mockRepo.AssertNotCalled(t, "UnlinkFromGamenet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		userService := services.NewUserService(mockRepo, new(MockPermissionRepository), nil, nil, nil)
+		mockRepo.On("GetByID", 99).Return(nil, repositories.ErrNotFound)
+
+		err := userService.AttachToGamenet(ctx, 99, 4)
+
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/handlers"
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// getWalletTransactions calls the wallet history handler for user 5 as the given account
+func getWalletTransactions(repo *testutils.MockWalletRepository, permissionService *testutils.MockPermissionService, requesterType string, requesterID int, query string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewWalletHandler(services.NewWalletService(repo), permissionService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/users/5/wallet/transactions?"+query, nil)
+	c.Params = gin.Params{{Key: "id", Value: "5"}}
+	c.Set("user_type", requesterType)
+	c.Set("user_id", requesterID)
+
+	handler.ListTransactions(c)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestWalletHandler_ListTransactions(t *testing.T) {
+	page := &models.WalletTransactionListResponse{
+		Data: []models.WalletTransaction{{ID: 3, UserID: 5, Type: models.WalletTransactionDebit, Amount: 10}},
+		Pagination: models.PaginationInfo{
+			CurrentPage: 2, PageSize: 1, TotalItems: 2, TotalPages: 2, HasNext: false, HasPrev: true,
+		},
+	}
+
+	t.Run("passes the filters and returns the page", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+		repo.On("ListTransactions", 5, mock.MatchedBy(func(filter *models.WalletTransactionFilter) bool {
+			return filter.Type == models.WalletTransactionDebit && filter.Page == 2 && filter.PageSize == 1 &&
+				filter.From != nil && filter.To != nil && filter.To.Hour() == 23
+		})).Return(page, nil)
+
+		w, response := getWalletTransactions(repo, new(testutils.MockPermissionService), "user", 5,
+			"type=debit&page=2&page_size=1&from=2024-01-01&to=2024-01-31")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, response["data"], 1)
+		pagination := response["pagination"].(map[string]interface{})
+		assert.Equal(t, float64(2), pagination["current_page"])
+		assert.Equal(t, true, pagination["has_prev"])
+		assert.Equal(t, false, pagination["has_next"])
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an unknown type", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+
+		w, _ := getWalletTransactions(repo, new(testutils.MockPermissionService), "user", 5, "type=refund")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		repo.AssertNotCalled(t, "ListTransactions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a reversed date range", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+
+		w, _ := getWalletTransactions(repo, new(testutils.MockPermissionService), "user", 5, "from=2024-02-01&to=2024-01-01")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("users only see their own wallet", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+
+		w, _ := getWalletTransactions(repo, new(testutils.MockPermissionService), "user", 6, "")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		repo.AssertNotCalled(t, "ListTransactions", mock.Anything, mock.Anything)
+	})
+
+	t.Run("gamenets only see their users' wallets", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+		permissionService := new(testutils.MockPermissionService)
+		permissionService.On("CanAccessResource", "gamenet", "users", 5, 9).Return(false, nil)
+
+		w, _ := getWalletTransactions(repo, permissionService, "gamenet", 9, "")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		repo.AssertNotCalled(t, "ListTransactions", mock.Anything, mock.Anything)
+	})
+}
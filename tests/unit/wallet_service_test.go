@@ -0,0 +1,249 @@
+package unit
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletBalance_Credit(t *testing.T) {
+	tests := []struct {
+		name        string
+		wallet      models.WalletBalance
+		amount      float64
+		wantBalance float64
+		wantDebt    float64
+	}{
+		{"adds to the balance", models.WalletBalance{Balance: 10}, 5.5, 15.5, 0},
+		{"pays off part of the debt", models.WalletBalance{Debt: 20}, 5, 0, 15},
+		{"pays off the debt and keeps the rest", models.WalletBalance{Debt: 20}, 30, 10, 0},
+		{"rounds to cents", models.WalletBalance{Balance: 0.1}, 0.2, 0.3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := tt.wallet
+			wallet.Credit(tt.amount)
+
+			assert.Equal(t, tt.wantBalance, wallet.Balance)
+			assert.Equal(t, tt.wantDebt, wallet.Debt)
+		})
+	}
+}
+
+func TestWalletBalance_Debit(t *testing.T) {
+	t.Run("takes from the balance", func(t *testing.T) {
+		wallet := models.WalletBalance{Balance: 50}
+
+		assert.NoError(t, wallet.Debit(20, false))
+		assert.Equal(t, 30.0, wallet.Balance)
+		assert.Equal(t, 0.0, wallet.Debt)
+	})
+
+	t.Run("can empty the balance", func(t *testing.T) {
+		wallet := models.WalletBalance{Balance: 50}
+
+		assert.NoError(t, wallet.Debit(50, false))
+		assert.Equal(t, 0.0, wallet.Balance)
+	})
+
+	t.Run("rejects an overdraw", func(t *testing.T) {
+		wallet := models.WalletBalance{Balance: 10, Debt: 5}
+
+		err := wallet.Debit(20, false)
+
+		assert.ErrorIs(t, err, models.ErrInsufficientBalance)
+		assert.Equal(t, models.WalletBalance{Balance: 10, Debt: 5}, wallet)
+	})
+
+	t.Run("moves the shortfall into debt when allowed", func(t *testing.T) {
+		wallet := models.WalletBalance{Balance: 10, Debt: 5}
+
+		assert.NoError(t, wallet.Debit(25, true))
+		assert.Equal(t, 0.0, wallet.Balance)
+		assert.Equal(t, 20.0, wallet.Debt)
+	})
+}
+
+func TestWalletService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("credits a wallet", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+		repo.On("ApplyTransaction", 1, models.WalletTransactionCredit, 25.5, "top up", false).
+			Return(&models.WalletTransaction{ID: 1, UserID: 1, Type: models.WalletTransactionCredit, Amount: 25.5, BalanceAfter: 25.5}, nil)
+
+		transaction, err := services.NewWalletService(repo).Credit(ctx, 1, 25.5, "  top up ")
+
+		require.NoError(t, err)
+		assert.Equal(t, 25.5, transaction.BalanceAfter)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("debits a wallet", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+		repo.On("ApplyTransaction", 1, models.WalletTransactionDebit, 10.0, "session", true).
+			Return(&models.WalletTransaction{ID: 2, Type: models.WalletTransactionDebit, Amount: 10, DebtAfter: 10}, nil)
+
+		transaction, err := services.NewWalletService(repo).Debit(ctx, 1, 10, "session", true)
+
+		require.NoError(t, err)
+		assert.Equal(t, 10.0, transaction.DebtAfter)
+	})
+
+	t.Run("passes on an overdraw rejection", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+		repo.On("ApplyTransaction", 1, models.WalletTransactionDebit, 100.0, "session", false).
+			Return(nil, models.ErrInsufficientBalance)
+
+		_, err := services.NewWalletService(repo).Debit(ctx, 1, 100, "session", false)
+
+		assert.ErrorIs(t, err, models.ErrInsufficientBalance)
+	})
+
+	t.Run("rejects invalid amounts and reasons", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+		walletService := services.NewWalletService(repo)
+
+		_, err := walletService.Credit(ctx, 1, 0, "top up")
+		assert.ErrorIs(t, err, services.ErrInvalidWalletAmount)
+		_, err = walletService.Debit(ctx, 1, -5, "session", false)
+		assert.ErrorIs(t, err, services.ErrInvalidWalletAmount)
+		_, err = walletService.Credit(ctx, 1, 1.005, "top up")
+		assert.ErrorIs(t, err, services.ErrInvalidWalletAmount)
+		_, err = walletService.Credit(ctx, 1, 10, " ")
+		assert.ErrorIs(t, err, services.ErrWalletReasonRequired)
+		repo.AssertNotCalled(t, "ApplyTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("accepts amounts with cents", func(t *testing.T) {
+		repo := new(testutils.MockWalletRepository)
+		repo.On("ApplyTransaction", 1, models.WalletTransactionCredit, 0.29, "top up", false).
+			Return(&models.WalletTransaction{ID: 3}, nil)
+
+		_, err := services.NewWalletService(repo).Credit(ctx, 1, 0.29, "top up")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestWalletRepository_ConcurrentUpdates(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	walletRepo := repositories.NewWalletRepository(db)
+	user := testutils.CreateTestUser(t, db, "wallet@example.com", "password123", "Wallet User")
+
+	// Half of the goroutines credit and half debit. Without the row lock some of them
+	// would read the same balance and overwrite each other's update.
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			txType := models.WalletTransactionCredit
+			if i%2 == 1 {
+				txType = models.WalletTransactionDebit
+			}
+			_, err := walletRepo.ApplyTransaction(user.ID, txType, 10, "concurrent", true)
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	wallet, err := walletRepo.GetBalance(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, wallet.Balance)
+	assert.Equal(t, 0.0, wallet.Debt)
+
+	var logged int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM wallet_transactions WHERE user_id = ?", user.ID).Scan(&logged))
+	assert.Equal(t, workers, logged)
+}
+
+func TestWalletRepository_ListTransactions(t *testing.T) {
+	testutils.SkipIfNoDB(t)
+
+	db := testutils.SetupTestDB(t)
+	defer db.Close()
+	defer testutils.CleanupTestDB(t, db)
+
+	walletRepo := repositories.NewWalletRepository(db)
+	user := testutils.CreateTestUser(t, db, "wallet-history@example.com", "password123", "Wallet User")
+	other := testutils.CreateTestUser(t, db, "wallet-other@example.com", "password123", "Other User")
+
+	// Three credits and two debits for the user, one credit for someone else
+	for _, txType := range []models.WalletTransactionType{
+		models.WalletTransactionCredit, models.WalletTransactionCredit, models.WalletTransactionDebit,
+		models.WalletTransactionCredit, models.WalletTransactionDebit,
+	} {
+		_, err := walletRepo.ApplyTransaction(user.ID, txType, 10, "history", true)
+		require.NoError(t, err)
+	}
+	_, err := walletRepo.ApplyTransaction(other.ID, models.WalletTransactionCredit, 10, "history", false)
+	require.NoError(t, err)
+
+	t.Run("first page", func(t *testing.T) {
+		result, err := walletRepo.ListTransactions(user.ID, &models.WalletTransactionFilter{Page: 1, PageSize: 2})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Data, 2)
+		assert.Equal(t, models.PaginationInfo{CurrentPage: 1, PageSize: 2, TotalItems: 5, TotalPages: 3, HasNext: true, HasPrev: false}, result.Pagination)
+		// Newest first
+		assert.Greater(t, result.Data[0].ID, result.Data[1].ID)
+	})
+
+	t.Run("last partial page", func(t *testing.T) {
+		result, err := walletRepo.ListTransactions(user.ID, &models.WalletTransactionFilter{Page: 3, PageSize: 2})
+
+		require.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.False(t, result.Pagination.HasNext)
+		assert.True(t, result.Pagination.HasPrev)
+	})
+
+	t.Run("page past the end", func(t *testing.T) {
+		result, err := walletRepo.ListTransactions(user.ID, &models.WalletTransactionFilter{Page: 4, PageSize: 2})
+
+		require.NoError(t, err)
+		assert.Empty(t, result.Data)
+		assert.Equal(t, int64(5), result.Pagination.TotalItems)
+		assert.False(t, result.Pagination.HasNext)
+	})
+
+	t.Run("defaults and caps the page size", func(t *testing.T) {
+		result, err := walletRepo.ListTransactions(user.ID, &models.WalletTransactionFilter{Page: 0, PageSize: 500})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Pagination.CurrentPage)
+		assert.Equal(t, 100, result.Pagination.PageSize)
+		assert.Len(t, result.Data, 5)
+	})
+
+	t.Run("filters by type", func(t *testing.T) {
+		result, err := walletRepo.ListTransactions(user.ID, &models.WalletTransactionFilter{Type: models.WalletTransactionDebit})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Pagination.TotalItems)
+		for _, transaction := range result.Data {
+			assert.Equal(t, models.WalletTransactionDebit, transaction.Type)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gatehide/gatehide-api/config"
+	"github.com/gatehide/gatehide-api/internal/services"
+	"github.com/gatehide/gatehide-api/internal/workers"
+	testutils "github.com/gatehide/gatehide-api/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	backoff := &workers.Backoff{Base: time.Minute, Max: 10 * time.Minute}
+	dbErr := errors.New("driver: bad connection")
+
+	assert.Equal(t, time.Minute, backoff.Next(nil))
+	assert.Equal(t, 2*time.Minute, backoff.Next(dbErr))
+	assert.Equal(t, 4*time.Minute, backoff.Next(dbErr))
+	assert.Equal(t, 8*time.Minute, backoff.Next(dbErr))
+	assert.Equal(t, 10*time.Minute, backoff.Next(dbErr))
+	assert.Equal(t, 10*time.Minute, backoff.Next(dbErr))
+	assert.Equal(t, 5, backoff.Failures())
+
+	// Recovery resets to the base interval
+	assert.Equal(t, time.Minute, backoff.Next(nil))
+	assert.Equal(t, 0, backoff.Failures())
+}
+
+func TestBackoff_NoMaxDisablesBackoff(t *testing.T) {
+	backoff := &workers.Backoff{Base: time.Minute}
+
+	assert.Equal(t, time.Minute, backoff.Next(errors.New("db down")))
+	assert.Equal(t, time.Minute, backoff.Next(errors.New("db down")))
+}
+
+func TestBackoff_DefaultConfigNeverRetriesSooner(t *testing.T) {
+	t.Setenv("SESSION_CLEANUP_INTERVAL_MINUTES", "")
+	t.Setenv("WORKER_MAX_BACKOFF_MINUTES", "")
+	cfg := config.Load()
+
+	// The default cap is below the default cleanup interval
+	interval := time.Duration(cfg.Workers.SessionCleanupInterval) * time.Minute
+	backoff := &workers.Backoff{Base: interval, Max: time.Duration(cfg.Workers.MaxBackoff) * time.Minute}
+
+	for i := 0; i < 3; i++ {
+		assert.GreaterOrEqual(t, backoff.Next(errors.New("db down")), interval)
+	}
+}
+
+func TestPeriodicWorker_SurvivesIntermittentDBFailures(t *testing.T) {
+	sessionRepo := new(testutils.MockSessionRepository)
+	sessionService := services.NewSessionService(sessionRepo, testutils.TestConfig())
+
+	var calls int32
+	done := make(chan struct{})
+	dbErr := errors.New("dial tcp 127.0.0.1:3306: connect: connection refused")
+	sessionRepo.On("CleanupExpiredSessions").Return(dbErr).Times(2)
+	sessionRepo.On("CleanupExpiredSessions").Return(nil).Run(func(args mock.Arguments) {
+		if atomic.LoadInt32(&calls) == 4 {
+			close(done)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker := workers.NewPeriodicWorker("session-cleanup", time.Millisecond, 4*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return sessionService.CleanupExpiredSessions()
+	})
+
+	stopped := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(stopped)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker did not keep running after DB failures")
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not stop after context cancellation")
+	}
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&calls), int32(4))
+}
+
+func TestPeriodicWorker_RecoversFromPanic(t *testing.T) {
+	var calls int32
+	done := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker := workers.NewPeriodicWorker("panicky", time.Millisecond, 2*time.Millisecond, func(ctx context.Context) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			panic("nil pointer")
+		}
+		close(done)
+		cancel()
+		return nil
+	})
+
+	go worker.Run(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("worker did not survive a panicking task")
+	}
+}
@@ -0,0 +1,200 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// FakeDB answers SQL statements with canned results, for tests that run code which takes
+// a *sql.DB, such as the routes, without a database. Statements are matched by a
+// substring of their text; queries that match nothing return no rows and execs that
+// match nothing affect no rows.
+type FakeDB struct {
+	mu      sync.Mutex
+	queries []fakeQuery
+	execs   []fakeExec
+	// Statements lists every statement run against the database, in order
+	Statements []string
+}
+
+type fakeQuery struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+}
+
+type fakeExec struct {
+	match        string
+	rowsAffected int64
+}
+
+var (
+	fakeDBRegister sync.Once
+	fakeDBMu       sync.Mutex
+	fakeDBs        = make(map[string]*FakeDB)
+)
+
+// NewFakeDB returns a database handle whose statements are answered by the returned FakeDB
+func NewFakeDB(t *testing.T) (*sql.DB, *FakeDB) {
+	t.Helper()
+
+	fakeDBRegister.Do(func() {
+		sql.Register("gatehide-fake", fakeDriver{})
+	})
+
+	fake := &FakeDB{}
+	dsn := fmt.Sprintf("%s/%p", t.Name(), fake)
+	fakeDBMu.Lock()
+	fakeDBs[dsn] = fake
+	fakeDBMu.Unlock()
+
+	db, err := sql.Open("gatehide-fake", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open fake database: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		fakeDBMu.Lock()
+		delete(fakeDBs, dsn)
+		fakeDBMu.Unlock()
+	})
+
+	return db, fake
+}
+
+// OnQuery answers queries containing match with the given columns and rows
+func (f *FakeDB) OnQuery(match string, columns []string, rows ...[]driver.Value) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries = append(f.queries, fakeQuery{match: match, columns: columns, rows: rows})
+}
+
+// OnExec answers statements containing match as affecting rowsAffected rows
+func (f *FakeDB) OnExec(match string, rowsAffected int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.execs = append(f.execs, fakeExec{match: match, rowsAffected: rowsAffected})
+}
+
+// Ran reports whether a statement containing match was run
+func (f *FakeDB) Ran(match string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, statement := range f.Statements {
+		if strings.Contains(statement, match) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FakeDB) query(statement string) driver.Rows {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Statements = append(f.Statements, statement)
+	for _, q := range f.queries {
+		if strings.Contains(statement, q.match) {
+			return &fakeRows{columns: q.columns, rows: q.rows}
+		}
+	}
+	return &fakeRows{}
+}
+
+func (f *FakeDB) exec(statement string) driver.Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Statements = append(f.Statements, statement)
+	for _, e := range f.execs {
+		if strings.Contains(statement, e.match) {
+			return fakeResult(e.rowsAffected)
+		}
+	}
+	return fakeResult(0)
+}
+
+// fakeResult reports the rows affected by a statement; inserts get id 0
+type fakeResult int64
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+
+func (r fakeResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fakeDBMu.Lock()
+	defer fakeDBMu.Unlock()
+	fake, ok := fakeDBs[dsn]
+	if !ok {
+		return nil, fmt.Errorf("unknown fake database %q", dsn)
+	}
+	return &fakeConn{db: fake}, nil
+}
+
+type fakeConn struct {
+	db *FakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: c, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.db.query(query), nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.db.exec(query), nil
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error { return nil }
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.db.exec(s.query), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.db.query(s.query), nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error { return nil }
+
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
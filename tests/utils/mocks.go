@@ -7,6 +7,7 @@ import (
 
 	"github.com/gatehide/gatehide-api/internal/models"
 	"github.com/gatehide/gatehide-api/internal/utils"
+	"github.com/kavenegar/kavenegar-go"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -72,6 +73,187 @@ func (m *MockAdminRepository) UpdatePassword(id int, hashedPassword string) erro
 	return args.Error(0)
 }
 
+func (m *MockAdminRepository) UpdateProfile(id int, name, mobile, image string) error {
+	args := m.Called(id, name, mobile, image)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepository) UpdateEmail(id int, email string) error {
+	args := m.Called(id, email)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepository) GetAllEmails() ([]string, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAdminRepository) SetTOTPSecret(id int, secret string) error {
+	args := m.Called(id, secret)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepository) EnableTOTP(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepository) ClaimTOTPStep(id int, step int64) (bool, error) {
+	args := m.Called(id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAdminRepository) GetAll() ([]models.Admin, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Admin), args.Error(1)
+}
+
+func (m *MockAdminRepository) Search(req *models.UserSearchRequest) (*models.AdminSearchResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AdminSearchResponse), args.Error(1)
+}
+
+// MockGamenetRepository is a mock implementation of GamenetRepository
+type MockGamenetRepository struct {
+	mock.Mock
+}
+
+func (m *MockGamenetRepository) GetAll() ([]models.Gamenet, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Gamenet), args.Error(1)
+}
+
+func (m *MockGamenetRepository) GetByID(id int) (*models.Gamenet, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Gamenet), args.Error(1)
+}
+
+func (m *MockGamenetRepository) GetByEmail(email string) (*models.Gamenet, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Gamenet), args.Error(1)
+}
+
+func (m *MockGamenetRepository) Create(gamenet *models.Gamenet) error {
+	args := m.Called(gamenet)
+	return args.Error(0)
+}
+
+func (m *MockGamenetRepository) Update(id int, gamenet *models.GamenetUpdateRequest) error {
+	args := m.Called(id, gamenet)
+	return args.Error(0)
+}
+
+func (m *MockGamenetRepository) UpdateLastLogin(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockGamenetRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockGamenetRepository) Search(req *models.GamenetSearchRequest) (*models.GamenetSearchResponse, error) {
+	args := m.Called(req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.GamenetSearchResponse), args.Error(1)
+}
+
+// MockPermissionService is a mock implementation of PermissionServiceInterface
+type MockPermissionService struct {
+	mock.Mock
+}
+
+func (m *MockPermissionService) CheckPermission(userType, resource, action string) error {
+	args := m.Called(userType, resource, action)
+	return args.Error(0)
+}
+
+func (m *MockPermissionService) CheckUserPermission(userID int, userType, resource, action string) error {
+	args := m.Called(userID, userType, resource, action)
+	return args.Error(0)
+}
+
+func (m *MockPermissionService) GetUserPermissions(userType string) ([]string, error) {
+	args := m.Called(userType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockPermissionService) GetUserPermissionsByID(userID int, userType string) ([]string, error) {
+	args := m.Called(userID, userType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockPermissionService) CanAccessResource(userType string, resourceType string, resourceID int, userID int) (bool, error) {
+	args := m.Called(userType, resourceType, resourceID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPermissionService) GetRoleWithPermissions(roleType string) (*models.RoleWithPermissions, error) {
+	args := m.Called(roleType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RoleWithPermissions), args.Error(1)
+}
+
+func (m *MockPermissionService) HasPermission(userType, resource, action string) (bool, error) {
+	args := m.Called(userType, resource, action)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPermissionService) GetAllPermissions() ([]models.Permission, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Permission), args.Error(1)
+}
+
+func (m *MockPermissionService) GetUserRoles(userID int, userType string) ([]models.Role, error) {
+	args := m.Called(userID, userType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Role), args.Error(1)
+}
+
+func (m *MockPermissionService) AssignRole(userID int, userType, roleName string, actor *models.Actor) error {
+	args := m.Called(userID, userType, roleName, actor)
+	return args.Error(0)
+}
+
+func (m *MockPermissionService) RemoveRole(userID int, userType, roleName string, actor *models.Actor) error {
+	args := m.Called(userID, userType, roleName, actor)
+	return args.Error(0)
+}
+
 // CreateMockUser creates a mock user for testing
 func CreateMockUser(id int, email, name string) *models.User {
 	hashedPassword, _ := models.HashPassword("password123")
@@ -80,7 +262,7 @@ func CreateMockUser(id int, email, name string) *models.User {
 		Name:     name,
 		Mobile:   "+1234567890",
 		Email:    email,
-		Password: hashedPassword,
+		Password: models.PasswordHash(hashedPassword),
 	}
 }
 
@@ -92,7 +274,7 @@ func CreateMockAdmin(id int, email, name string) *models.Admin {
 		Name:     name,
 		Mobile:   "+1234567890",
 		Email:    email,
-		Password: hashedPassword,
+		Password: models.PasswordHash(hashedPassword),
 	}
 }
 
@@ -117,7 +299,7 @@ func CreateMockLoginResponse(userType string, user interface{}) *models.LoginRes
 		Token:     "mock.jwt.token",
 		UserType:  userType,
 		User:      user,
-		ExpiresAt: time.Now().Add(24 * time.Hour),
+		ExpiresAt: models.NewTimestamp(time.Now().Add(24 * time.Hour)),
 	}
 }
 
@@ -153,6 +335,39 @@ func (m *MockAuthService) Login(email, password string, rememberMe bool) (*model
 	return args.Get(0).(*models.LoginResponse), args.Error(1)
 }
 
+func (m *MockAuthService) VerifyTOTP(challengeToken, code, deviceInfo, ipAddress, userAgent, sessionToken string) (*models.LoginResponse, error) {
+	args := m.Called(challengeToken, code, deviceInfo, ipAddress, userAgent, sessionToken)
+	return args.Get(0).(*models.LoginResponse), args.Error(1)
+}
+
+func (m *MockAuthService) EnableTOTP(adminID int) (string, string, error) {
+	args := m.Called(adminID)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockAuthService) ConfirmTOTP(adminID int, code string) error {
+	args := m.Called(adminID, code)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) LogoutSession(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockAuthService) GetActiveSessions(userID int, userType string) ([]models.SessionResponse, error) {
+	args := m.Called(userID, userType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SessionResponse), args.Error(1)
+}
+
+func (m *MockAuthService) RevokeOtherSessions(userID int, userType, currentToken string) error {
+	args := m.Called(userID, userType, currentToken)
+	return args.Error(0)
+}
+
 func (m *MockAuthService) ValidateToken(tokenString string) (*utils.JWTClaims, error) {
 	args := m.Called(tokenString)
 	return args.Get(0).(*utils.JWTClaims), args.Error(1)
@@ -183,13 +398,37 @@ func (m *MockAuthService) ValidateResetToken(token string) error {
 	return args.Error(0)
 }
 
-func (m *MockAuthService) ChangePassword(userID int, userType, currentPassword, newPassword, confirmPassword string) error {
-	args := m.Called(userID, userType, currentPassword, newPassword, confirmPassword)
+func (m *MockAuthService) IssueUserResetLink(userID int, actor *models.Actor) (*models.PasswordResetLinkResponse, error) {
+	args := m.Called(userID, actor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PasswordResetLinkResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ChangePassword(userID int, userType, currentPassword, newPassword, confirmPassword, currentToken string) error {
+	args := m.Called(userID, userType, currentPassword, newPassword, confirmPassword, currentToken)
 	return args.Error(0)
 }
 
-func (m *MockAuthService) LoginWithSession(email, password string, rememberMe bool, deviceInfo, ipAddress, userAgent string) (*models.LoginResponse, error) {
-	args := m.Called(email, password, rememberMe, deviceInfo, ipAddress, userAgent)
+func (m *MockAuthService) VerifyPassword(userID int, userType, password string) (*models.ReauthResponse, error) {
+	args := m.Called(userID, userType, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReauthResponse), args.Error(1)
+}
+
+func (m *MockAuthService) ValidateReauthToken(tokenString string) (*utils.ReauthClaims, error) {
+	args := m.Called(tokenString)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*utils.ReauthClaims), args.Error(1)
+}
+
+func (m *MockAuthService) LoginWithSession(email, password string, rememberMe bool, deviceInfo, ipAddress, userAgent, sessionToken string) (*models.LoginResponse, error) {
+	args := m.Called(email, password, rememberMe, deviceInfo, ipAddress, userAgent, sessionToken)
 	return args.Get(0).(*models.LoginResponse), args.Error(1)
 }
 
@@ -265,6 +504,14 @@ func (m *MockAuthService) UpdateGamenetEmail(gamenetID int, newEmail string) (*m
 	return args.Get(0).(*models.GamenetResponse), args.Error(1)
 }
 
+func (m *MockAuthService) ChangeAdminEmail(adminID int, currentPassword, newEmail string) (*models.AdminResponse, error) {
+	args := m.Called(adminID, currentPassword, newEmail)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AdminResponse), args.Error(1)
+}
+
 func (m *MockAuthService) SendEmailVerification(userID int, userType, newEmail string) (string, error) {
 	args := m.Called(userID, userType, newEmail)
 	return args.String(0), args.Error(1)
@@ -301,11 +548,27 @@ type MockSessionRepository struct {
 	mock.Mock
 }
 
-func (m *MockSessionRepository) CreateSession(session *models.UserSession) error {
-	args := m.Called(session)
+func (m *MockSessionRepository) CreateSession(userID int, userType, sessionToken string, deviceInfo, ipAddress, userAgent *string, expiresAt time.Time) (*models.UserSession, error) {
+	args := m.Called(userID, userType, sessionToken, deviceInfo, ipAddress, userAgent, expiresAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSession), args.Error(1)
+}
+
+func (m *MockSessionRepository) RenewSession(sessionID int, sessionToken string, ipAddress, userAgent *string, expiresAt time.Time) error {
+	args := m.Called(sessionID, sessionToken, ipAddress, userAgent, expiresAt)
 	return args.Error(0)
 }
 
+func (m *MockSessionRepository) GetSessionByID(sessionID int) (*models.UserSession, error) {
+	args := m.Called(sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSession), args.Error(1)
+}
+
 func (m *MockSessionRepository) GetSessionByToken(token string) (*models.UserSession, error) {
 	args := m.Called(token)
 	if args.Get(0) == nil {
@@ -332,9 +595,9 @@ func (m *MockSessionRepository) DeactivateAllOtherUserSessions(userID int, userT
 	return args.Error(0)
 }
 
-func (m *MockSessionRepository) DeactivateAllUserSessions(userID int, userType string) error {
+func (m *MockSessionRepository) DeactivateAllUserSessions(userID int, userType string) (int, error) {
 	args := m.Called(userID, userType)
-	return args.Error(0)
+	return args.Int(0), args.Error(1)
 }
 
 func (m *MockSessionRepository) UpdateSessionActivity(sessionID int) error {
@@ -362,6 +625,34 @@ func (m *MockNotificationService) SendNotification(ctx context.Context, notifica
 	return args.Error(0)
 }
 
+func (m *MockNotificationService) EnqueueNotification(ctx context.Context, notification *models.CreateNotificationRequest) error {
+	args := m.Called(ctx, notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) RateLimitStats() map[models.NotificationType]utils.TokenBucketStats {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(map[models.NotificationType]utils.TokenBucketStats)
+}
+
+func (m *MockNotificationService) TestEmailConnection(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) TestSMSConnection(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) NotifyAdmins(ctx context.Context, subject, body string) error {
+	args := m.Called(ctx, subject, body)
+	return args.Error(0)
+}
+
 func (m *MockNotificationService) SendEmail(ctx context.Context, email *models.SendEmailRequest) error {
 	args := m.Called(ctx, email)
 	return args.Error(0)
@@ -449,11 +740,19 @@ func (m *MockSubscriptionPlanRepository) GetAll(limit, offset int, isActive *boo
 	return args.Get(0).([]*models.SubscriptionPlan), args.Error(1)
 }
 
-func (m *MockSubscriptionPlanRepository) Update(id int, plan *models.SubscriptionPlan) error {
-	args := m.Called(id, plan)
+func (m *MockSubscriptionPlanRepository) Update(id int, plan *models.SubscriptionPlan, history *models.PlanHistory) error {
+	args := m.Called(id, plan, history)
 	return args.Error(0)
 }
 
+func (m *MockSubscriptionPlanRepository) GetHistory(planID int) ([]*models.PlanHistory, error) {
+	args := m.Called(planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.PlanHistory), args.Error(1)
+}
+
 func (m *MockSubscriptionPlanRepository) Delete(id int) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -469,6 +768,47 @@ func (m *MockSubscriptionPlanRepository) HasActiveSubscriptions(planID int) (boo
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockSubscriptionPlanRepository) CountActiveSubscriptions(planID int) (int, error) {
+	args := m.Called(planID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSubscriptionPlanRepository) ExistsByName(name string) (bool, error) {
+	args := m.Called(name)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockUserSubscriptionRepository is a mock implementation of UserSubscriptionRepositoryInterface
+type MockUserSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserSubscriptionRepository) Create(subscription *models.UserSubscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *MockUserSubscriptionRepository) GetByID(id int) (*models.UserSubscription, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSubscription), args.Error(1)
+}
+
+func (m *MockUserSubscriptionRepository) GetActiveByGamenetID(gamenetID int) (*models.UserSubscription, error) {
+	args := m.Called(gamenetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSubscription), args.Error(1)
+}
+
+func (m *MockUserSubscriptionRepository) Cancel(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 // CreateMockSubscriptionPlan creates a mock subscription plan for testing
 func CreateMockSubscriptionPlan(id int, name, planType string, price float64) *models.SubscriptionPlan {
 	now := time.Now()
@@ -497,8 +837,8 @@ func CreateMockPlanResponse(id int, name, planType string, price float64) *model
 		AnnualDiscountPercentage: nil,
 		TrialDurationDays:        nil,
 		IsActive:                 true,
-		CreatedAt:                now,
-		UpdatedAt:                now,
+		CreatedAt:                models.NewTimestamp(now),
+		UpdatedAt:                models.NewTimestamp(now),
 	}
 }
 
@@ -512,3 +852,205 @@ func SetupMockSubscriptionPlanRepository(t *testing.T) *MockSubscriptionPlanRepo
 func AssertSubscriptionPlanRepositoryExpectations(t *testing.T, mockRepo *MockSubscriptionPlanRepository) {
 	mockRepo.AssertExpectations(t)
 }
+
+// MockKavenegarClient is a mock implementation of services.KavenegarClient
+type MockKavenegarClient struct {
+	mock.Mock
+}
+
+func (m *MockKavenegarClient) SendMessage(sender string, receptor []string, message string, params *kavenegar.MessageSendParam) ([]kavenegar.Message, error) {
+	args := m.Called(sender, receptor, message, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]kavenegar.Message), args.Error(1)
+}
+
+func (m *MockKavenegarClient) VerifyLookup(receptor, template, token string, params *kavenegar.VerifyLookupParam) (kavenegar.Message, error) {
+	args := m.Called(receptor, template, token, params)
+	return args.Get(0).(kavenegar.Message), args.Error(1)
+}
+
+func (m *MockKavenegarClient) AccountInfo() (kavenegar.AccountInfo, error) {
+	args := m.Called()
+	return args.Get(0).(kavenegar.AccountInfo), args.Error(1)
+}
+
+func (m *MockKavenegarClient) MessageStatus(messageIDs []string) ([]kavenegar.MessageStatus, error) {
+	args := m.Called(messageIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]kavenegar.MessageStatus), args.Error(1)
+}
+
+// MockLoginAuditRepository is a mock implementation of LoginAuditRepositoryInterface
+type MockLoginAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAuditRepository) RecordSuccessfulLogin(userID int, userType string, ipAddress, userAgent, deviceInfo *string) error {
+	args := m.Called(userID, userType, ipAddress, userAgent, deviceInfo)
+	return args.Error(0)
+}
+
+func (m *MockLoginAuditRepository) RecordFailedLogin(email string, ipAddress, userAgent, deviceInfo *string, reason string) error {
+	args := m.Called(email, ipAddress, userAgent, deviceInfo, reason)
+	return args.Error(0)
+}
+
+func (m *MockLoginAuditRepository) ListLoginEvents(filter *models.LoginEventFilter) (*models.LoginEventListResponse, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginEventListResponse), args.Error(1)
+}
+
+func (m *MockLoginAuditRepository) ExportLoginEvents(filter *models.LoginEventFilter, limit int) ([]models.LoginEvent, error) {
+	args := m.Called(filter, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LoginEvent), args.Error(1)
+}
+
+func (m *MockLoginAuditRepository) ListLoginAttempts(filter *models.LoginAttemptFilter) (*models.LoginAttemptListResponse, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LoginAttemptListResponse), args.Error(1)
+}
+
+func (m *MockLoginAuditRepository) CountLoginAttemptsBy(filter *models.LoginAttemptFilter, groupBy string, limit int) ([]models.LoginAttemptCount, error) {
+	args := m.Called(filter, groupBy, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LoginAttemptCount), args.Error(1)
+}
+
+// MockTemplateRepository is a mock implementation of repositories.NotificationTemplateRepository
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) Create(template *models.NotificationTemplate) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) GetByID(id int) (*models.NotificationTemplate, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetByNameAndType(name string, templateType models.NotificationType) (*models.NotificationTemplate, error) {
+	args := m.Called(name, templateType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetAll() ([]*models.NotificationTemplate, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.NotificationTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetByType(templateType models.NotificationType) ([]*models.NotificationTemplate, error) {
+	args := m.Called(templateType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.NotificationTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) Update(template *models.NotificationTemplate) error {
+	args := m.Called(template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// MockEmailVerificationRepository is a mock implementation of EmailVerificationRepositoryInterface
+type MockEmailVerificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationRepository) StoreCode(userID int, userType, email, code string, expiresAt time.Time) error {
+	args := m.Called(userID, userType, email, code, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationRepository) VerifyCode(userID int, userType, email, code string) (bool, error) {
+	args := m.Called(userID, userType, email, code)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) ReserveCode(userID int, userType, email, code string, expiresAt time.Time, cooldown time.Duration) (time.Duration, error) {
+	args := m.Called(userID, userType, email, code, expiresAt, cooldown)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
+func (m *MockEmailVerificationRepository) CleanupExpiredCodes() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// MockWalletRepository is a mock implementation of WalletRepositoryInterface
+type MockWalletRepository struct {
+	mock.Mock
+}
+
+func (m *MockWalletRepository) GetBalance(userID int) (*models.WalletBalance, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WalletBalance), args.Error(1)
+}
+
+func (m *MockWalletRepository) ApplyTransaction(userID int, txType models.WalletTransactionType, amount float64, reason string, allowDebt bool) (*models.WalletTransaction, error) {
+	args := m.Called(userID, txType, amount, reason, allowDebt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WalletTransaction), args.Error(1)
+}
+
+func (m *MockWalletRepository) ListTransactions(userID int, filter *models.WalletTransactionFilter) (*models.WalletTransactionListResponse, error) {
+	args := m.Called(userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WalletTransactionListResponse), args.Error(1)
+}
+
+// MockSafeSeeders is a mock implementation of seeders.SafeSeeders
+type MockSafeSeeders struct {
+	mock.Mock
+}
+
+func (m *MockSafeSeeders) List() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockSafeSeeders) Run(name string) (*models.SeedResult, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SeedResult), args.Error(1)
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gatehide/gatehide-api/internal/models"
+	"github.com/gatehide/gatehide-api/internal/repositories"
+	"github.com/gatehide/gatehide-api/internal/services"
+)
+
+var (
+	seededRoleNameRegex       = regexp.MustCompile(`r\.name\s*(?:=\s*'([^']+)'|IN\s*\(([^)]*)\))`)
+	seededPermissionNameRegex = regexp.MustCompile(`p\.name\s*(?:=\s*'([^']+)'|IN\s*\(([^)]*)\))`)
+	seededQuotedRegex         = regexp.MustCompile(`'([^']+)'`)
+)
+
+// SeededRolePermissions returns the permissions each role is granted by the UP sections
+// of the migrations, keyed by role name, so tests check access against the same grants
+// a migrated database has
+func SeededRolePermissions(t *testing.T) map[string]map[string]bool {
+	t.Helper()
+
+	_, file, _, _ := runtime.Caller(0)
+	dir := filepath.Join(filepath.Dir(file), "..", "..", "database", "migrations")
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("Failed to find migrations in %s: %v", dir, err)
+	}
+	sort.Strings(paths)
+
+	grants := make(map[string]map[string]bool)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read migration %s: %v", path, err)
+		}
+		up := string(content)
+		if i := strings.Index(up, "-- DOWN"); i >= 0 {
+			up = up[:i]
+		}
+
+		for _, statement := range strings.Split(up, ";") {
+			if !strings.Contains(statement, "INTO role_permissions") {
+				continue
+			}
+			for _, role := range seededNames(seededRoleNameRegex, statement) {
+				if grants[role] == nil {
+					grants[role] = make(map[string]bool)
+				}
+				for _, permission := range seededNames(seededPermissionNameRegex, statement) {
+					grants[role][permission] = true
+				}
+			}
+		}
+	}
+
+	return grants
+}
+
+// seededNames returns the names matched by an "x.name = '...'" or "x.name IN (...)" condition
+func seededNames(condition *regexp.Regexp, statement string) []string {
+	match := condition.FindStringSubmatch(statement)
+	if match == nil {
+		return nil
+	}
+	if match[1] != "" {
+		return []string{match[1]}
+	}
+
+	var names []string
+	for _, quoted := range seededQuotedRegex.FindAllStringSubmatch(match[2], -1) {
+		names = append(names, quoted[1])
+	}
+	return names
+}
+
+// SeededPermissionRepository is an in-memory PermissionRepositoryInterface backed by the
+// migration grants. Every account has the default role of its type.
+type SeededPermissionRepository struct {
+	grants map[string]map[string]bool
+}
+
+// NewSeededPermissionService returns a PermissionService that answers permission checks
+// with the roles and grants the migrations seed
+func NewSeededPermissionService(t *testing.T) *services.PermissionService {
+	return services.NewPermissionService(&SeededPermissionRepository{grants: SeededRolePermissions(t)}, nil)
+}
+
+// seededRoleName maps an account type to the role it is seeded with
+func seededRoleName(userType string) string {
+	if userType == "admin" {
+		return "administrator"
+	}
+	return userType
+}
+
+func (r *SeededPermissionRepository) permissions(role string) []models.Permission {
+	names := make([]string, 0, len(r.grants[role]))
+	for name := range r.grants[role] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	permissions := make([]models.Permission, 0, len(names))
+	for _, name := range names {
+		resource, action, _ := strings.Cut(name, ":")
+		permissions = append(permissions, models.Permission{Name: name, Resource: resource, Action: action})
+	}
+	return permissions
+}
+
+func (r *SeededPermissionRepository) GetPermissionsByRole(roleType string) ([]models.Permission, error) {
+	return r.permissions(roleType), nil
+}
+
+func (r *SeededPermissionRepository) HasPermission(roleType, resource, action string) (bool, error) {
+	return r.grants[roleType][resource+":"+action], nil
+}
+
+func (r *SeededPermissionRepository) HasUserPermission(userID int, userType, resource, action string) (bool, error) {
+	return r.grants[seededRoleName(userType)][resource+":"+action], nil
+}
+
+func (r *SeededPermissionRepository) GetRoleWithPermissions(roleType string) (*models.RoleWithPermissions, error) {
+	if _, ok := r.grants[roleType]; !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return &models.RoleWithPermissions{Role: models.Role{Name: roleType}, Permissions: r.permissions(roleType)}, nil
+}
+
+func (r *SeededPermissionRepository) GetRoleByName(roleName string) (*models.Role, error) {
+	if _, ok := r.grants[roleName]; !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return &models.Role{Name: roleName}, nil
+}
+
+func (r *SeededPermissionRepository) GetAllRoles() ([]models.Role, error) {
+	var roles []models.Role
+	for name := range r.grants {
+		roles = append(roles, models.Role{Name: name})
+	}
+	return roles, nil
+}
+
+func (r *SeededPermissionRepository) GetAllPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	for role := range r.grants {
+		permissions = append(permissions, r.permissions(role)...)
+	}
+	return permissions, nil
+}
+
+func (r *SeededPermissionRepository) AssignRoleToUser(userID int, userType string, roleName string) error {
+	return nil
+}
+
+func (r *SeededPermissionRepository) GetUserRoles(userID int, userType string) ([]models.Role, error) {
+	return []models.Role{{Name: seededRoleName(userType)}}, nil
+}
+
+func (r *SeededPermissionRepository) GetUserPermissions(userID int, userType string) ([]models.Permission, error) {
+	return r.permissions(seededRoleName(userType)), nil
+}
+
+func (r *SeededPermissionRepository) RemoveRoleFromUser(userID int, userType string, roleName string) error {
+	return nil
+}
+
+func (r *SeededPermissionRepository) HasUserRole(userID int, userType string, roleName string) (bool, error) {
+	return seededRoleName(userType) == roleName, nil
+}
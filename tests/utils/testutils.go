@@ -19,15 +19,21 @@ func TestConfig() *config.Config {
 		Server: config.ServerConfig{
 			Port:    "8081",
 			GinMode: "test",
+			// Integration tests read the emailed verification codes from responses
+			ExposeVerificationCodes: true,
 		},
 		App: config.AppConfig{
-			Name:    "GateHide API Test",
-			Version: "1.0.0-test",
+			Name:            "GateHide API Test",
+			Version:         "1.0.0-test",
+			FrontendBaseURL: "http://localhost:3000",
 		},
 		Security: config.SecurityConfig{
-			APISecret:     "test-api-secret",
-			JWTSecret:     "test-jwt-secret-key-for-testing-only",
-			JWTExpiration: 1, // 1 hour for tests
+			APISecret:                 "test-api-secret",
+			JWTSecret:                 "test-jwt-secret-key-for-testing-only",
+			JWTExpirationHours:        1,      // 1 hour for tests
+			RememberMeExpirationHours: 24 * 7, // 7 days for remember me
+			EnablePasswordLogin:       true,
+			PasswordPolicy:            config.PasswordPolicyConfig{MinLength: 6},
 		},
 		Database: config.DatabaseConfig{
 			Host:     getEnv("TEST_DB_HOST", "localhost"),
@@ -84,9 +90,13 @@ func CleanupTestDB(t *testing.T, db *sql.DB) {
 		"DELETE FROM permissions",
 		"DELETE FROM roles",
 		"DELETE FROM user_sessions",
+		"DELETE FROM wallet_transactions",
 		"DELETE FROM users",
 		"DELETE FROM admins",
 		"DELETE FROM gamenets",
+		"DELETE FROM notifications",
+		"DELETE FROM sms_logs",
+		"DELETE FROM seeders",
 		"DELETE FROM migrations",
 	}
 
@@ -115,9 +125,13 @@ func CleanupTestDBForce(t *testing.T, db *sql.DB) {
 		"DELETE FROM permissions",
 		"DELETE FROM roles",
 		"DELETE FROM user_sessions",
+		"DELETE FROM wallet_transactions",
 		"DELETE FROM users",
 		"DELETE FROM admins",
 		"DELETE FROM gamenets",
+		"DELETE FROM notifications",
+		"DELETE FROM sms_logs",
+		"DELETE FROM seeders",
 		"DELETE FROM migrations",
 		"ALTER TABLE user_roles AUTO_INCREMENT = 1",
 		"ALTER TABLE role_permissions AUTO_INCREMENT = 1",
@@ -127,6 +141,9 @@ func CleanupTestDBForce(t *testing.T, db *sql.DB) {
 		"ALTER TABLE users AUTO_INCREMENT = 1",
 		"ALTER TABLE admins AUTO_INCREMENT = 1",
 		"ALTER TABLE gamenets AUTO_INCREMENT = 1",
+		"ALTER TABLE notifications AUTO_INCREMENT = 1",
+		"ALTER TABLE sms_logs AUTO_INCREMENT = 1",
+		"ALTER TABLE seeders AUTO_INCREMENT = 1",
 		"ALTER TABLE migrations AUTO_INCREMENT = 1",
 	}
 
@@ -170,7 +187,7 @@ func CreateTestUser(t *testing.T, db *sql.DB, email, password, name string) *mod
 		Name:     name,
 		Mobile:   mobile,
 		Email:    email,
-		Password: hashedPassword,
+		Password: models.PasswordHash(hashedPassword),
 	}
 }
 
@@ -204,7 +221,7 @@ func CreateTestAdmin(t *testing.T, db *sql.DB, email, password, name string) *mo
 		Name:     name,
 		Mobile:   mobile,
 		Email:    email,
-		Password: hashedPassword,
+		Password: models.PasswordHash(hashedPassword),
 	}
 }
 
@@ -216,6 +233,7 @@ func runTestMigrations(db *sql.DB) error {
 			id INT AUTO_INCREMENT PRIMARY KEY,
 			version VARCHAR(255) NOT NULL UNIQUE,
 			description TEXT,
+			checksum CHAR(64) NULL,
 			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 	`
@@ -236,6 +254,8 @@ func runTestMigrations(db *sql.DB) error {
 			balance DECIMAL(10, 2) DEFAULT 0.00 NOT NULL,
 			debt DECIMAL(10, 2) DEFAULT 0.00 NOT NULL,
 			last_login_at TIMESTAMP NULL,
+			suspended_at TIMESTAMP NULL,
+			deleted_at TIMESTAMP NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
 			
@@ -324,6 +344,127 @@ func runTestMigrations(db *sql.DB) error {
 		return fmt.Errorf("failed to create user_sessions table: %w", err)
 	}
 
+	// Create revoked_tokens table
+	revokedTokensTable := `
+		CREATE TABLE IF NOT EXISTS revoked_tokens (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			jti VARCHAR(64) NOT NULL UNIQUE,
+			expires_at TIMESTAMP NOT NULL,
+			revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+			INDEX idx_expires_at (expires_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`
+
+	if _, err := db.Exec(revokedTokensTable); err != nil {
+		return fmt.Errorf("failed to create revoked_tokens table: %w", err)
+	}
+
+	// Create login_lockouts table
+	loginLockoutsTable := `
+		CREATE TABLE IF NOT EXISTS login_lockouts (
+			identifier VARCHAR(255) NOT NULL PRIMARY KEY,
+			failed_count INT NOT NULL DEFAULT 0,
+			window_ends_at DATETIME NOT NULL,
+			locked_until DATETIME NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+			INDEX idx_window_ends_at (window_ends_at),
+			INDEX idx_locked_until (locked_until)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`
+
+	if _, err := db.Exec(loginLockoutsTable); err != nil {
+		return fmt.Errorf("failed to create login_lockouts table: %w", err)
+	}
+
+	// Create notifications table
+	notificationsTable := `
+		CREATE TABLE IF NOT EXISTS notifications (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			type ENUM('email', 'sms', 'database') NOT NULL,
+			status ENUM('pending', 'sent', 'failed', 'cancelled', 'permanently_failed', 'skipped') NOT NULL DEFAULT 'pending',
+			priority ENUM('low', 'normal', 'high', 'urgent') NOT NULL DEFAULT 'normal',
+			recipient VARCHAR(255) NOT NULL,
+			related_user_id INT NULL,
+			related_user_type VARCHAR(20) NULL,
+			subject VARCHAR(500),
+			content TEXT,
+			html_content MEDIUMTEXT NULL,
+			template_id INT,
+			template_data JSON,
+			metadata JSON,
+			scheduled_at TIMESTAMP NULL,
+			sent_at TIMESTAMP NULL,
+			error_msg TEXT,
+			retry_count INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+			
+			INDEX idx_notifications_recipient (recipient),
+			INDEX idx_notifications_created_at_id (created_at, id),
+			INDEX idx_notifications_related_user (related_user_type, related_user_id, created_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`
+
+	if _, err := db.Exec(notificationsTable); err != nil {
+		return fmt.Errorf("failed to create notifications table: %w", err)
+	}
+
+	// Create SMS logs table
+	smsLogsTable := `
+		CREATE TABLE IF NOT EXISTS sms_logs (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			recipient VARCHAR(20) NOT NULL,
+			message_type VARCHAR(50) NOT NULL,
+			status ENUM('sent', 'failed') NOT NULL,
+			provider_message_id BIGINT NULL,
+			error_msg TEXT NULL,
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			
+			INDEX idx_sms_logs_created_at (created_at),
+			INDEX idx_sms_logs_type_created_at (message_type, created_at)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`
+
+	if _, err := db.Exec(smsLogsTable); err != nil {
+		return fmt.Errorf("failed to create sms_logs table: %w", err)
+	}
+
+	// Create wallet transactions table
+	walletTransactionsTable := `
+		CREATE TABLE IF NOT EXISTS wallet_transactions (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			user_id INT NOT NULL,
+			type ENUM('credit', 'debit') NOT NULL,
+			amount DECIMAL(10, 2) NOT NULL,
+			balance_after DECIMAL(10, 2) NOT NULL,
+			debt_after DECIMAL(10, 2) NOT NULL,
+			reason VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			
+			INDEX idx_wallet_transactions_user_created (user_id, created_at),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`
+
+	if _, err := db.Exec(walletTransactionsTable); err != nil {
+		return fmt.Errorf("failed to create wallet_transactions table: %w", err)
+	}
+
+	// Create seeders tracking table
+	seedersTable := `
+		CREATE TABLE IF NOT EXISTS seeders (
+			id INT AUTO_INCREMENT PRIMARY KEY,
+			name VARCHAR(100) NOT NULL UNIQUE,
+			ran_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+	`
+
+	if _, err := db.Exec(seedersTable); err != nil {
+		return fmt.Errorf("failed to create seeders table: %w", err)
+	}
+
 	// Create permissions tables for RBAC
 	permissionsTable := `
 		CREATE TABLE IF NOT EXISTS permissions (
@@ -450,9 +591,9 @@ func runTestMigrations(db *sql.DB) error {
 	// Assign permissions to roles - use individual transactions to avoid deadlocks
 	rolePermissions := []string{
 		// Administrator permissions
-		"INSERT IGNORE INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'administrator' AND p.name IN ('dashboard:view', 'gamenets:create', 'gamenets:read', 'gamenets:update', 'gamenets:delete', 'users:create', 'users:read', 'users:update', 'users:delete', 'subscription_plans:create', 'subscription_plans:read', 'subscription_plans:update', 'subscription_plans:delete', 'analytics:view', 'payments:view', 'transactions:view', 'invoices:view', 'settings:manage', 'support:access')",
+		"INSERT IGNORE INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'administrator' AND p.name IN ('dashboard:view', 'gamenets:create', 'gamenets:read', 'gamenets:update', 'gamenets:delete', 'users:create', 'users:read', 'users:update', 'users:delete', 'subscription_plans:create', 'subscription_plans:read', 'subscription_plans:update', 'subscription_plans:delete', 'analytics:view', 'payments:view', 'transactions:view', 'invoices:view', 'settings:manage', 'support:access', 'wallet:view')",
 		// Gamenet permissions
-		"INSERT IGNORE INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'gamenet' AND p.name IN ('dashboard:view', 'users:create', 'users:read', 'users:update', 'users:delete', 'analytics:view', 'transactions:view', 'payments:view', 'support:access', 'settings:manage')",
+		"INSERT IGNORE INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'gamenet' AND p.name IN ('dashboard:view', 'users:create', 'users:read', 'users:update', 'users:delete', 'analytics:view', 'transactions:view', 'payments:view', 'support:access', 'settings:manage', 'wallet:view')",
 		// User permissions
 		"INSERT IGNORE INTO role_permissions (role_id, permission_id) SELECT r.id, p.id FROM roles r, permissions p WHERE r.name = 'user' AND p.name IN ('reservation:manage', 'support:access', 'settings:manage', 'wallet:view')",
 	}
@@ -491,3 +632,25 @@ func SkipIfNoDB(t *testing.T) {
 		t.Skip("Skipping database tests")
 	}
 }
+
+// SkipIfNoPostgres skips the test unless PostgreSQL tests are enabled with TEST_POSTGRES=true
+func SkipIfNoPostgres(t *testing.T) {
+	if os.Getenv("TEST_POSTGRES") != "true" {
+		t.Skip("Skipping PostgreSQL tests (set TEST_POSTGRES=true to run them)")
+	}
+}
+
+// PostgresTestConfig returns a test configuration for a PostgreSQL database
+func PostgresTestConfig() *config.Config {
+	cfg := TestConfig()
+	cfg.Database = config.DatabaseConfig{
+		Host:     getEnv("TEST_PG_HOST", "localhost"),
+		Port:     getEnv("TEST_PG_PORT", "5432"),
+		User:     getEnv("TEST_PG_USER", "postgres"),
+		Password: getEnv("TEST_PG_PASSWORD", ""),
+		DBName:   getEnv("TEST_PG_NAME", "gatehide_test"),
+		SSLMode:  "false",
+		Driver:   "postgres",
+	}
+	return cfg
+}